@@ -25,8 +25,11 @@ import (
 func initAnnotsCmdMap() commandMap {
 	m := newCommandMap()
 	for k, v := range map[string]command{
-		"list":   {processListAnnotationsCommand, nil, "", ""},
-		"remove": {processRemoveAnnotationsCommand, nil, "", ""},
+		"export":  {processExportAnnotationsCommand, nil, "", ""},
+		"flatten": {processFlattenAnnotationsCommand, nil, "", ""},
+		"import":  {processImportAnnotationsCommand, nil, "", ""},
+		"list":    {processListAnnotationsCommand, nil, "", ""},
+		"remove":  {processRemoveAnnotationsCommand, nil, "", ""},
 	} {
 		m.register(k, v)
 	}
@@ -99,8 +102,10 @@ func initFontsCmdMap() commandMap {
 	m := newCommandMap()
 	for k, v := range map[string]command{
 		"cheatsheet": {processCreateCheatSheetFontsCommand, nil, "", ""},
+		"embed":      {processEmbedFontsCommand, nil, "", ""},
 		"install":    {processInstallFontsCommand, nil, "", ""},
 		"list":       {processListFontsCommand, nil, "", ""},
+		"replace":    {processReplaceFontCommand, nil, "", ""},
 	} {
 		m.register(k, v)
 	}
@@ -115,9 +120,11 @@ func initFormCmdMap() commandMap {
 		"lock":      {processLockFormCommand, nil, "", ""},
 		"unlock":    {processUnlockFormCommand, nil, "", ""},
 		"reset":     {processResetFormCommand, nil, "", ""},
+		"flatten":   {processFlattenFormCommand, nil, "", ""},
 		"export":    {processExportFormCommand, nil, "", ""},
 		"fill":      {processFillFormCommand, nil, "", ""},
 		"multifill": {processMultiFillFormCommand, nil, "", ""},
+		"xfa":       {processXFACommand, nil, "", ""},
 	} {
 		m.register(k, v)
 	}
@@ -243,6 +250,54 @@ func initPageLayoutCmdMap() commandMap {
 	return m
 }
 
+func initPageLabelsCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":   {processListPageLabelsCommand, nil, "", ""},
+		"add":    {processAddPageLabelsCommand, nil, "", ""},
+		"remove": {processRemovePageLabelsCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initLayersCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":    {processListLayersCommand, nil, "", ""},
+		"add":     {processAddLayerCommand, nil, "", ""},
+		"remove":  {processRemoveLayersCommand, nil, "", ""},
+		"show":    {processShowLayersCommand, nil, "", ""},
+		"hide":    {processHideLayersCommand, nil, "", ""},
+		"flatten": {processFlattenLayersCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initStructureCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"export": {processExportStructureCommand, nil, "", ""},
+		"import": {processImportStructureCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initPDFACmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"convert": {processConvertToPDFACommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
 func initSignaturesCmdMap() commandMap {
 	m := newCommandMap()
 	for k, v := range map[string]command{
@@ -277,12 +332,16 @@ func initCommandMap() {
 	formCmdMap := initFormCmdMap()
 	imagesCmdMap := initImagesCmdMap()
 	keywordsCmdMap := initKeywordsCmdMap()
+	layersCmdMap := initLayersCmdMap()
+	pageLabelsCmdMap := initPageLabelsCmdMap()
 	pagesCmdMap := initPagesCmdMap()
+	pdfaCmdMap := initPDFACmdMap()
 	permissionsCmdMap := initPermissionsCmdMap()
 	portfolioCmdMap := initPortfolioCmdMap()
 	propertiesCmdMap := initPropertiesCmdMap()
 	signaturesCmdMap := initSignaturesCmdMap()
 	stampCmdMap := initStampCmdMap()
+	structureCmdMap := initStructureCmdMap()
 	watermarkCmdMap := initWatermarkCmdMap()
 	pageModeCmdMap := initPageModeCmdMap()
 	pageLayoutCmdMap := initPageLayoutCmdMap()
@@ -316,14 +375,17 @@ func initCommandMap() {
 		"import":        {processImportImagesCommand, nil, usageImportImages, usageLongImportImages},
 		"info":          {processInfoCommand, nil, usageInfo, usageLongInfo},
 		"keywords":      {nil, keywordsCmdMap, usageKeywords, usageLongKeywords},
+		"layers":        {nil, layersCmdMap, usageLayers, usageLongLayers},
 		"merge":         {processMergeCommand, nil, usageMerge, usageLongMerge},
 		"ndown":         {processNDownCommand, nil, usageNDown, usageLongNDown},
 		"nup":           {processNUpCommand, nil, usageNUp, usageLongNUp},
 		"optimize":      {processOptimizeCommand, nil, usageOptimize, usageLongOptimize},
+		"pagelabels":    {nil, pageLabelsCmdMap, usagePageLabels, usageLongPageLabels},
 		"pagelayout":    {nil, pageLayoutCmdMap, usagePageLayout, usageLongPageLayout},
 		"pagemode":      {nil, pageModeCmdMap, usagePageMode, usageLongPageMode},
 		"pages":         {nil, pagesCmdMap, usagePages, usageLongPages},
 		"paper":         {printPaperSizes, nil, usagePaper, usageLongPaper},
+		"pdfa":          {nil, pdfaCmdMap, usagePDFA, usageLongPDFA},
 		"permissions":   {nil, permissionsCmdMap, usagePerm, usageLongPerm},
 		"portfolio":     {nil, portfolioCmdMap, usagePortfolio, usageLongPortfolio},
 		"poster":        {processPosterCommand, nil, usagePoster, usageLongPoster},
@@ -334,6 +396,7 @@ func initCommandMap() {
 		"signatures":    {nil, signaturesCmdMap, usageSignatures, usageLongSignatures},
 		"split":         {processSplitCommand, nil, usageSplit, usageLongSplit},
 		"stamp":         {nil, stampCmdMap, usageStamp, usageLongStamp},
+		"structure":     {nil, structureCmdMap, usageStructure, usageLongStructure},
 		"trim":          {processTrimCommand, nil, usageTrim, usageLongTrim},
 		"validate":      {processValidateCommand, nil, usageValidate, usageLongValidate},
 		"watermark":     {nil, watermarkCmdMap, usageWatermark, usageLongWatermark},
@@ -380,7 +443,7 @@ func initFlags() {
 	flag.BoolVar(&links, "links", false, linksUsage)
 	flag.BoolVar(&links, "l", false, linksUsage)
 
-	modeUsage := "validate: strict|relaxed; extract: image|font|content|page|meta; encrypt: rc4|aes; stamp:text|image/pdf"
+	modeUsage := "validate: strict|relaxed|pdfa-1b|pdfa-2b|pdfa-2u|pdfa-3b|pdfa-3u|ua; pdfa convert: pdfa-2b|pdfa-3b; extract: image|font|content|page|meta; encrypt: rc4|aes; stamp:text|image/pdf"
 	flag.StringVar(&mode, "mode", "", modeUsage)
 	flag.StringVar(&mode, "m", "", modeUsage)
 
@@ -420,6 +483,15 @@ func initFlags() {
 	flag.StringVar(&upw, "upw", "", "user password")
 	flag.StringVar(&opw, "opw", "", "owner password")
 
+	fdfUsage := "form: use FDF"
+	flag.BoolVar(&fdf, "fdf", false, fdfUsage)
+
+	xfdfUsage := "annotations, form: use XFDF"
+	flag.BoolVar(&xfdf, "xfdf", false, xfdfUsage)
+
+	sheetUsage := "form multifill: XLSX sheet name or number"
+	flag.StringVar(&sheet, "sheet", "", sheetUsage)
+
 	flag.BoolVar(&verbose, "verbose", false, "")
 	flag.BoolVar(&verbose, "v", false, "")
 	flag.BoolVar(&veryVerbose, "vv", false, "")
@@ -25,7 +25,7 @@ import (
 )
 
 var (
-	fileStats, mode, selectedPages           string
+	fileStats, mode, selectedPages, sheet    string
 	upw, opw, key, perm, unit, conf          string
 	verbose, veryVerbose                     bool
 	links, quiet, offline                    bool
@@ -34,6 +34,7 @@ var (
 	full                                     bool // eg. signature validation output
 	fonts                                    bool // Info
 	json                                     bool // List Viewer Preferences, Info
+	fdf, xfdf                                bool // Annotations, Form
 	bookmarks, dividerPage, optimize, sorted bool // Merge
 	bookmarksSet, offlineSet, optimizeSet    bool
 	needStackTrace                           = true
@@ -70,10 +70,36 @@ func ensureJSONExtension(filename string) {
 	}
 }
 
+func hasXFDFExtension(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".xfdf")
+}
+
+func ensureXFDFExtension(filename string) {
+	if !hasXFDFExtension(filename) {
+		fmt.Fprintf(os.Stderr, "%s needs extension \".xfdf\".\n", filename)
+		os.Exit(1)
+	}
+}
+
+func hasFDFExtension(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".fdf")
+}
+
+func ensureFDFExtension(filename string) {
+	if !hasFDFExtension(filename) {
+		fmt.Fprintf(os.Stderr, "%s needs extension \".fdf\".\n", filename)
+		os.Exit(1)
+	}
+}
+
 func hasCSVExtension(filename string) bool {
 	return strings.HasSuffix(strings.ToLower(filename), ".csv")
 }
 
+func hasXLSXExtension(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".xlsx")
+}
+
 func ensureCSVExtension(filename string) {
 	if !hasCSVExtension(filename) {
 		fmt.Fprintf(os.Stderr, "%s needs extension \".csv\".\n", filename)
@@ -354,8 +380,12 @@ func processValidateCommand(conf *model.Configuration) {
 		conf.ValidationMode = model.ValidationRelaxed
 	case "":
 	default:
-		fmt.Fprintf(os.Stderr, "%s\n\n", usageValidate)
-		os.Exit(1)
+		c := model.ConformanceFor(mode)
+		if c == nil {
+			fmt.Fprintf(os.Stderr, "%s\n\n", usageValidate)
+			os.Exit(1)
+		}
+		conf.Conformance = *c
 	}
 
 	if links {
@@ -370,6 +400,38 @@ func processValidateCommand(conf *model.Configuration) {
 	process(cli.ValidateCommand(inFiles, conf))
 }
 
+func processConvertToPDFACommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "%s\n\n", usagePDFAConvert)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := inFile
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	switch mode {
+	case "":
+		conf.Conformance = model.PDFA2B
+	default:
+		c := model.ConformanceFor(mode)
+		if c == nil || (*c != model.PDFA2B && *c != model.PDFA3B) {
+			fmt.Fprintf(os.Stderr, "%s\n\n", usagePDFAConvert)
+			os.Exit(1)
+		}
+		conf.Conformance = *c
+	}
+
+	process(cli.ConvertToPDFACommand(inFile, outFile, conf))
+}
+
 func processOptimizeCommand(conf *model.Configuration) {
 	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageOptimize)
@@ -1225,7 +1287,7 @@ func processRemoveWatermarksCommand(conf *model.Configuration) {
 
 func ensureImageExtension(filename string) {
 	if !model.ImageFileName(filename) {
-		fmt.Fprintf(os.Stderr, "%s needs an image extension (.jpg, .jpeg, .png, .tif, .tiff, .webp)\n", filename)
+		fmt.Fprintf(os.Stderr, "%s needs an image extension (.jpg, .jpeg, .jp2, .j2k, .jpx, .png, .tif, .tiff, .webp, .bmp, .gif)\n", filename)
 		os.Exit(1)
 	}
 }
@@ -1662,17 +1724,17 @@ func processListFontsCommand(conf *model.Configuration) {
 func processInstallFontsCommand(conf *model.Configuration) {
 	fileNames := []string{}
 	if len(flag.Args()) == 0 {
-		fmt.Fprintf(os.Stderr, "%s\n\n", "expecting a list of TrueType filenames (.ttf, .ttc) for installation.")
+		fmt.Fprintf(os.Stderr, "%s\n\n", "expecting a list of TrueType, OpenType or web font filenames (.ttf, .ttc, .otf, .woff, .woff2) for installation.")
 		os.Exit(1)
 	}
 	for _, arg := range flag.Args() {
-		if !types.MemberOf(filepath.Ext(arg), []string{".ttf", ".ttc"}) {
+		if !types.MemberOf(filepath.Ext(arg), []string{".ttf", ".ttc", ".otf", ".woff", ".woff2"}) {
 			continue
 		}
 		fileNames = append(fileNames, arg)
 	}
 	if len(fileNames) == 0 {
-		fmt.Fprintln(os.Stderr, "Please supply a *.ttf or *.tcc fontname!")
+		fmt.Fprintln(os.Stderr, "Please supply a *.ttf, *.ttc, *.otf, *.woff or *.woff2 fontname!")
 		os.Exit(1)
 	}
 	process(cli.InstallFontsCommand(fileNames, conf))
@@ -1686,6 +1748,60 @@ func processCreateCheatSheetFontsCommand(conf *model.Configuration) {
 	process(cli.CreateCheatSheetsFontsCommand(fileNames, conf))
 }
 
+func processEmbedFontsCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFontsEmbed)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+	outFile := inFile
+
+	fontMap := map[string]string{}
+
+	for i, arg := range flag.Args()[1:] {
+		fontName, userFontName, ok := strings.Cut(arg, ":")
+		if !ok {
+			if i > 0 {
+				fmt.Fprintf(os.Stderr, "fontMapping = 'fontName:userFontName'\n")
+				fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFontsEmbed)
+				os.Exit(1)
+			}
+			outFile = arg
+			ensurePDFExtension(outFile)
+			continue
+		}
+		fontMap[strings.TrimSpace(fontName)] = strings.TrimSpace(userFontName)
+	}
+
+	process(cli.EmbedFontsCommand(inFile, outFile, fontMap, conf))
+}
+
+func processReplaceFontCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 3 || len(flag.Args()) > 4 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFontsReplace)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+	outFile := inFile
+
+	args := flag.Args()[1:]
+	if len(args) == 3 {
+		outFile = args[0]
+		ensurePDFExtension(outFile)
+		args = args[1:]
+	}
+
+	process(cli.ReplaceFontCommand(inFile, outFile, args[0], args[1], conf))
+}
+
 func processListKeywordsCommand(conf *model.Configuration) {
 	if len(flag.Args()) != 1 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "usage: %s\n", usageKeywordsList)
@@ -1745,6 +1861,156 @@ func processRemoveKeywordsCommand(conf *model.Configuration) {
 	process(cli.RemoveKeywordsCommand(inFile, "", keywords, conf))
 }
 
+func processListLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageLayersList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.ListLayersCommand(inFile, conf))
+}
+
+func processAddLayerCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersAdd)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.AddLayerCommand(inFile, "", flag.Arg(1), true, conf))
+}
+
+func processRemoveLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersRemove)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	names := flag.Args()[1:]
+
+	process(cli.RemoveLayersCommand(inFile, "", names, conf))
+}
+
+func processShowLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersShow)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	names := flag.Args()[1:]
+
+	process(cli.ShowLayersCommand(inFile, "", names, conf))
+}
+
+func processHideLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersHide)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	names := flag.Args()[1:]
+
+	process(cli.HideLayersCommand(inFile, "", names, conf))
+}
+
+func processFlattenLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersFlatten)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	names := flag.Args()[1:]
+
+	process(cli.FlattenLayersCommand(inFile, "", names, conf))
+}
+
+func processListPageLabelsCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usagePageLabelsList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.ListPageLabelsCommand(inFile, conf))
+}
+
+func processAddPageLabelsCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usagePageLabelsAdd)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	pl, err := pdfcpu.ParsePageLabelDetails(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.AddPageLabelsCommand(inFile, "", []pdfcpu.PageLabel{*pl}, conf))
+}
+
+func processRemovePageLabelsCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usagePageLabelsRemove)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	var pageNrs []int
+	for _, s := range flag.Args()[1:] {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 {
+			fmt.Fprintf(os.Stderr, "invalid page number: %s\n", s)
+			os.Exit(1)
+		}
+		pageNrs = append(pageNrs, i)
+	}
+
+	process(cli.RemovePageLabelsCommand(inFile, "", pageNrs, conf))
+}
+
 func processListPropertiesCommand(conf *model.Configuration) {
 	if len(flag.Args()) != 1 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "usage: %s\n", usagePropertiesList)
@@ -2063,6 +2329,91 @@ func processRemoveAnnotationsCommand(conf *model.Configuration) {
 	process(cli.RemoveAnnotationsCommand(inFile, outFile, selectedPages, idsAndTypes, objNrs, conf))
 }
 
+func processFlattenAnnotationsCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageAnnotsFlatten)
+		os.Exit(1)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	inFile, outFile := "", ""
+
+	var annotTypes []string
+
+	for i, arg := range flag.Args() {
+		if i == 0 {
+			inFile = arg
+			if conf.CheckFileNameExt {
+				ensurePDFExtension(inFile)
+			}
+			continue
+		}
+		if i == 1 {
+			if hasPDFExtension(arg) {
+				outFile = arg
+				continue
+			}
+		}
+		annotTypes = append(annotTypes, arg)
+	}
+
+	process(cli.FlattenAnnotationsCommand(inFile, outFile, selectedPages, annotTypes, conf))
+}
+
+func processExportAnnotationsCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageAnnotsExport)
+		os.Exit(1)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFileXFDF := "out.xfdf"
+	if len(flag.Args()) == 2 {
+		outFileXFDF = flag.Arg(1)
+	}
+	ensureXFDFExtension(outFileXFDF)
+
+	process(cli.ExportAnnotationsCommand(inFile, outFileXFDF, selectedPages, conf))
+}
+
+func processImportAnnotationsCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || len(flag.Args()) > 3 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageAnnotsImport)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	inFileXFDF := flag.Arg(1)
+	ensureXFDFExtension(inFileXFDF)
+
+	outFile := inFile
+	if len(flag.Args()) == 3 {
+		outFile = flag.Arg(2)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.ImportAnnotationsCommand(inFile, inFileXFDF, outFile, conf))
+}
+
 func processListImagesCommand(conf *model.Configuration) {
 	if len(flag.Args()) < 1 {
 		fmt.Fprintf(os.Stderr, "usage: %s\n", usageImagesList)
@@ -2316,6 +2667,38 @@ func processLockFormCommand(conf *model.Configuration) {
 	process(cli.LockFormCommand(inFile, outFile, fieldIDs, conf))
 }
 
+func processFlattenFormCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormFlatten)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	var fieldIDs []string
+	outFile := inFile
+
+	if len(flag.Args()) > 1 {
+		s := flag.Arg(1)
+		if hasPDFExtension(s) {
+			outFile = s
+		} else {
+			fieldIDs = append(fieldIDs, s)
+		}
+	}
+
+	if len(flag.Args()) > 2 {
+		for i := 2; i < len(flag.Args()); i++ {
+			fieldIDs = append(fieldIDs, flag.Arg(i))
+		}
+	}
+
+	process(cli.FlattenFormCommand(inFile, outFile, fieldIDs, conf))
+}
+
 func processUnlockFormCommand(conf *model.Configuration) {
 	if len(flag.Args()) == 0 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormUnlock)
@@ -2391,6 +2774,26 @@ func processExportFormCommand(conf *model.Configuration) {
 		ensurePDFExtension(inFile)
 	}
 
+	if xfdf || (len(flag.Args()) == 2 && hasXFDFExtension(flag.Arg(1))) {
+		outFileXFDF := "out.xfdf"
+		if len(flag.Args()) == 2 {
+			outFileXFDF = flag.Arg(1)
+		}
+		ensureXFDFExtension(outFileXFDF)
+		process(cli.ExportFormXFDFCommand(inFile, outFileXFDF, conf))
+		return
+	}
+
+	if fdf || (len(flag.Args()) == 2 && hasFDFExtension(flag.Arg(1))) {
+		outFileFDF := "out.fdf"
+		if len(flag.Args()) == 2 {
+			outFileFDF = flag.Arg(1)
+		}
+		ensureFDFExtension(outFileFDF)
+		process(cli.ExportFormFDFCommand(inFile, outFileFDF, conf))
+		return
+	}
+
 	// TODO inFile.json
 	outFileJSON := "out.json"
 	if len(flag.Args()) == 2 {
@@ -2413,15 +2816,29 @@ func processFillFormCommand(conf *model.Configuration) {
 		ensurePDFExtension(inFile)
 	}
 
-	inFileJSON := flag.Arg(1)
-	ensureJSONExtension(inFileJSON)
-
 	outFile := inFile
 	if len(flag.Args()) == 3 {
 		outFile = flag.Arg(2)
 		ensurePDFExtension(outFile)
 	}
 
+	if xfdf || hasXFDFExtension(flag.Arg(1)) {
+		inFileXFDF := flag.Arg(1)
+		ensureXFDFExtension(inFileXFDF)
+		process(cli.FillFormXFDFCommand(inFile, inFileXFDF, outFile, conf))
+		return
+	}
+
+	if fdf || hasFDFExtension(flag.Arg(1)) {
+		inFileFDF := flag.Arg(1)
+		ensureFDFExtension(inFileFDF)
+		process(cli.FillFormFDFCommand(inFile, inFileFDF, outFile, conf))
+		return
+	}
+
+	inFileJSON := flag.Arg(1)
+	ensureJSONExtension(inFileJSON)
+
 	process(cli.FillFormCommand(inFile, inFileJSON, outFile, conf))
 }
 
@@ -2446,8 +2863,13 @@ func processMultiFillFormCommand(conf *model.Configuration) {
 	}
 
 	inFileData := flag.Arg(1)
-	if !hasJSONExtension(inFileData) && !hasCSVExtension(inFileData) {
-		fmt.Fprintf(os.Stderr, "%s needs extension \".json\" or \".csv\".\n", inFileData)
+	if !hasJSONExtension(inFileData) && !hasCSVExtension(inFileData) && !hasXLSXExtension(inFileData) {
+		fmt.Fprintf(os.Stderr, "%s needs extension \".json\", \".csv\" or \".xlsx\".\n", inFileData)
+		os.Exit(1)
+	}
+
+	if sheet != "" && !hasXLSXExtension(inFileData) {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormMultiFill)
 		os.Exit(1)
 	}
 
@@ -2459,9 +2881,101 @@ func processMultiFillFormCommand(conf *model.Configuration) {
 		ensurePDFExtension(outFile)
 	}
 
+	if hasXLSXExtension(inFileData) {
+		process(cli.MultiFillFormXLSXCommand(inFile, inFileData, sheet, outDir, outFile, mode == "merge", conf))
+		return
+	}
+
 	process(cli.MultiFillFormCommand(inFile, inFileData, outDir, outFile, mode == "merge", conf))
 }
 
+func hasXMLExtension(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".xml")
+}
+
+func processXFACommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormXFA)
+		os.Exit(1)
+	}
+
+	args := flag.Args()[1:]
+
+	switch modeCompletion(flag.Arg(0), []string{"list", "extract", "remove", "fill"}) {
+
+	case "list":
+		inFiles := []string{}
+		for _, arg := range args {
+			if strings.Contains(arg, "*") {
+				matches, err := filepath.Glob(arg)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s", err)
+					os.Exit(1)
+				}
+				inFiles = append(inFiles, matches...)
+				continue
+			}
+			if conf.CheckFileNameExt {
+				ensurePDFExtension(arg)
+			}
+			inFiles = append(inFiles, arg)
+		}
+		process(cli.ListXFACommand(inFiles, conf))
+
+	case "extract":
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormXFAExtract)
+			os.Exit(1)
+		}
+		inFile := args[0]
+		if conf.CheckFileNameExt {
+			ensurePDFExtension(inFile)
+		}
+		process(cli.ExtractXFACommand(inFile, args[1], conf))
+
+	case "remove":
+		if len(args) > 2 {
+			fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormXFARemove)
+			os.Exit(1)
+		}
+		inFile := args[0]
+		if conf.CheckFileNameExt {
+			ensurePDFExtension(inFile)
+		}
+		outFile := ""
+		if len(args) == 2 {
+			outFile = args[1]
+			ensurePDFExtension(outFile)
+		}
+		process(cli.RemoveXFACommand(inFile, outFile, conf))
+
+	case "fill":
+		if len(args) < 2 || len(args) > 3 {
+			fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormXFAFill)
+			os.Exit(1)
+		}
+		inFile := args[0]
+		if conf.CheckFileNameExt {
+			ensurePDFExtension(inFile)
+		}
+		inFileData := args[1]
+		if !hasXMLExtension(inFileData) && !hasJSONExtension(inFileData) {
+			fmt.Fprintf(os.Stderr, "%s needs extension \".xml\" or \".json\".\n", inFileData)
+			os.Exit(1)
+		}
+		outFile := inFile
+		if len(args) == 3 {
+			outFile = args[2]
+			ensurePDFExtension(outFile)
+		}
+		process(cli.FillXFACommand(inFile, inFileData, outFile, conf))
+
+	default:
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormXFA)
+		os.Exit(1)
+	}
+}
+
 func processResizeCommand(conf *model.Configuration) {
 	if len(flag.Args()) < 2 || len(flag.Args()) > 3 {
 		fmt.Fprintf(os.Stderr, "%s\n", usageResize)
@@ -2716,6 +3230,49 @@ func processRemoveBookmarksCommand(conf *model.Configuration) {
 	process(cli.RemoveBookmarksCommand(inFile, outFile, conf))
 }
 
+func processExportStructureCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageStructureExport)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFileJSON := "out.json"
+	if len(flag.Args()) == 2 {
+		outFileJSON = flag.Arg(1)
+		ensureJSONExtension(outFileJSON)
+	}
+
+	process(cli.ExportStructureCommand(inFile, outFileJSON, conf))
+}
+
+func processImportStructureCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || len(flag.Args()) > 3 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageStructureImport)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	inFileJSON := flag.Arg(1)
+	ensureJSONExtension(inFileJSON)
+
+	outFile := ""
+	if len(flag.Args()) == 3 {
+		outFile = flag.Arg(2)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.ImportStructureCommand(inFile, inFileJSON, outFile, conf))
+}
+
 func processListPageLayoutCommand(conf *model.Configuration) {
 	if len(flag.Args()) != 1 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "usage: %s\n", usagePageLayoutList)
@@ -41,21 +41,24 @@ The commands are:
    decrypt       remove password protection
    encrypt       set password protection		
    extract       extract images, fonts, content, pages or metadata
-   fonts         install, list supported fonts, create cheat sheets
+   fonts         install, list supported fonts, create cheat sheets, embed, replace
    form          list, remove fields, lock, unlock, reset, export, fill form via JSON or CSV
    grid          rearrange pages or images for enhanced browsing experience
    images        list, extract, update images
    import        import/convert images to PDF
    info          print file info
    keywords      list, add, remove keywords
+   layers        list, add, remove, show, hide, flatten layers (optional content groups)
    merge         concatenate PDFs
    ndown         cut selected pages into n pages symmetrically
    nup           rearrange pages or images for reduced number of pages
    optimize      optimize PDF by getting rid of redundant page resources
+   pagelabels    list, add, remove page labels
    pagelayout    list, set, reset page layout for opened document
    pagemode      list, set, reset page mode for opened document
    pages         insert, remove selected pages
    paper         print list of supported paper sizes
+   pdfa          convert to PDF/A
    permissions   list, set user access permissions
    portfolio     list, add, remove, extract portfolio entries with optional description
    poster        cut selected pages into poster by paper size or dimensions
@@ -66,6 +69,7 @@ The commands are:
    signatures    validate signatures
    split         split up a PDF by span or bookmark
    stamp         add, remove, update Unicode text, image or PDF stamps for selected pages
+   structure     export, import the logical structure of tagged PDFs
    trim          create trimmed version of selected pages
    validate      validate PDF against PDF 32000-1:2008 (PDF 1.7) + basic PDF 2.0 validation
    version       print version
@@ -92,7 +96,7 @@ common flags: -v(erbose)  ... turn on logging
                                                   cm ... centimetres
                                                   mm ... millimetres`
 
-	usageValidate = "usage: pdfcpu validate [-m(ode) strict|relaxed] [-l(inks) -opt(imize)] -- inFile..." + generalFlags
+	usageValidate = "usage: pdfcpu validate [-m(ode) strict|relaxed|pdfa-1b|pdfa-2b|pdfa-2u|pdfa-3b|pdfa-3u|ua] [-l(inks) -opt(imize)] -- inFile..." + generalFlags
 
 	usageLongValidate = `Check inFile for specification compliance.
 
@@ -104,6 +108,14 @@ common flags: -v(erbose)  ... turn on logging
 The validation modes are:
     strict ... validates against PDF 32000-1:2008 (PDF 1.7) and rudimentary against PDF 32000:2 (PDF 2.0)
    relaxed ... (default) like strict but doesn't complain about common seen spec violations.
+   pdfa-1b ... like relaxed and additionally checks conformance with ISO 19005-1 (PDF/A-1b)
+   pdfa-2b ... like relaxed and additionally checks conformance with ISO 19005-2 (PDF/A-2b)
+   pdfa-2u ... like pdfa-2b and additionally checks for Unicode mappings (PDF/A-2u)
+   pdfa-3b ... like relaxed and additionally checks conformance with ISO 19005-3 (PDF/A-3b)
+   pdfa-3u ... like pdfa-3b and additionally checks for Unicode mappings (PDF/A-3u)
+        ua ... like relaxed and additionally checks accessibility (ISO 14289-1, PDF/UA-1)
+
+A PDF/A or PDF/UA check reports all violations found grouped by topic.
 
 Validation turns off optimization unless in verbose mode.
 You can enforce optimization using -opt=true.`
@@ -202,7 +214,9 @@ Skip optimization before writing: -opt(imize)=false`
 
 	n serves as an alternative for !, since ! needs to be escaped with single quotes on the cmd line.
 
-        e.g. -3,5,7- or 4-7,!6 or 1-,!5 or odd,n1`
+	In place of a page number # you may also use a page label enclosed in curly braces.
+
+        e.g. -3,5,7- or 4-7,!6 or 1-,!5 or odd,n1 or {i}-{iv},{A-1}-`
 
 	usageExtract     = "usage: pdfcpu extract -m(ode) i(mage)|f(ont)|c(ontent)|p(age)|m(eta) [-p(ages) selectedPages] -- inFile outDir" + generalFlags
 	usageLongExtract = `Export inFile's images, fonts, content or pages into outDir.
@@ -353,7 +367,7 @@ content ... extract raw page content
    
    2) image based
       -mode image imageFileName
-         supported extensions: .jpg, .jpeg, .png, .tif, .tiff, .webp
+         supported extensions: .jpg, .jpeg, .jp2, .j2k, .jpx, .png, .tif, .tiff, .webp, .bmp, .gif
          eg. pdfcpu stamp add -mode image -- "logo.png" "" in.pdf out.pdf
          
    3) PDF based
@@ -383,7 +397,7 @@ content ... extract raw page content
    
    2) image based
       -mode image imageFileName
-         supported extensions: .jpg, .jpeg, .png, .tif, .tiff, .webp 
+         supported extensions: .jpg, .jpeg, .jp2, .j2k, .jpx, .png, .tif, .tiff, .webp, .bmp, .gif
          eg. pdfcpu watermark add -mode image -- "logo.png" "" in.pdf out.pdf
          
    3) PDF based
@@ -407,7 +421,7 @@ content ... extract raw page content
 
 <description> is a comma separated configuration string containing these optional entries:
    
-   (defaults: "font:Helvetica, points:24, rtl:off, pos:c, off:0,0 scale:0.5 rel, rot:0, d:1, op:1, m:0 and for all colors: 0.5 0.5 0.5")
+   (defaults: "font:Helvetica, points:24, rtl:off, kerning:off, pos:c, off:0,0 scale:0.5 rel, rot:0, d:1, op:1, m:0 and for all colors: 0.5 0.5 0.5")
 
    fontname:         Please refer to "pdfcpu fonts list"
 
@@ -423,6 +437,8 @@ content ... extract raw page content
 
    rtl:              render right to left (on/off, true/false, t/f)
 
+   kerning:          apply kerning and standard ligatures (on/off, true/false, t/f)
+
    position:         one of the anchors:
 
                            tl|top-left     tc|top-center      tr|top-right
@@ -473,6 +489,8 @@ content ... extract raw page content
 
    url:              Add link annotation for stamps only (omit https://)
 
+   layer:            Place watermark on the layer with this name, created if necessary
+
 A color value: 3 color intensities, where 0.0 < i < 1.0, eg 1.0, 
                or the hex RGB value: #RRGGBB, eg #FF0000 = red
 
@@ -502,7 +520,7 @@ e.g. "pos:bl, off: 20 5"   "rot:45"                 "op:0.5, scale:0.5 abs, rot:
      string ... display string for text based watermarks
        file ... image or PDF file
 description ... fontname, points, position, offset, scalefactor, aligntext, rotation, 
-                diagonal, opacity, rendermode, strokecolor, fillcolor, bgcolor, margins, border
+                diagonal, opacity, rendermode, strokecolor, fillcolor, bgcolor, margins, border, layer
      inFile ... input PDF file
     outFile ... output PDF file
 
@@ -523,7 +541,7 @@ description ... fontname, points, position, offset, scalefactor, aligntext, rota
      string ... display string for text based watermarks
        file ... image or PDF file
 description ... fontname, points, position, offset, scalefactor, aligntext, rotation,
-                diagonal, opacity, rendermode, strokecolor, fillcolor, bgcolor, margins, border
+                diagonal, opacity, rendermode, strokecolor, fillcolor, bgcolor, margins, border, layer
      inFile ... input PDF file
     outFile ... output PDF file
 
@@ -945,6 +963,28 @@ Examples: pdfcpu grid out.pdf 1 10 in.pdf
 	usagePaper     = "usage: pdfcpu paper"
 	usageLongPaper = "Print a list of supported paper sizes."
 
+	usagePDFAConvert = "pdfcpu pdfa convert [-m(ode) pdfa-2b|pdfa-3b] -- inFile [outFile]"
+	usagePDFA        = "usage: " + usagePDFAConvert + generalFlags
+
+	usageLongPDFA = `Convert inFile to PDF/A and write the result to outFile.
+
+      mode ... conformance level: pdfa-2b (default), pdfa-3b
+    inFile ... input PDF file
+   outFile ... output PDF file
+
+The conversion fixes all issues that can be fixed automatically:
+
+   - add an sRGB output intent
+   - generate XMP metadata including the PDF/A identification
+   - synchronize the document info dict and XMP metadata
+   - remove encryption, JavaScript and forbidden actions like Launch
+   - generate missing annotation appearances and set the annotation print flags
+
+If the result is still not conforming eg. because of fonts that are not embedded,
+no output gets written and all remaining violations are reported.
+
+Use "pdfcpu validate -mode pdfa-2b" for checking PDF/A conformance.`
+
 	usageSelectedPages     = "usage: pdfcpu selectedpages"
 	usageLongSelectedPages = "Print definition of the -pages flag."
 
@@ -959,13 +999,41 @@ Examples: pdfcpu grid out.pdf 1 10 in.pdf
 	usageFontsList       = "pdfcpu fonts list"
 	usageFontsInstall    = "pdfcpu fonts install fontFiles..."
 	usageFontsCheatSheet = "pdfcpu fonts cheatsheet fontFiles..."
+	usageFontsEmbed      = "pdfcpu fonts embed inFile [outFile] [fontName:userFontName...]"
+	usageFontsReplace    = "pdfcpu fonts replace inFile [outFile] fontName newFontName"
 
 	usageFonts = "usage: " + usageFontsList +
 		"\n       " + usageFontsInstall +
-		"\n       " + usageFontsCheatSheet
+		"\n       " + usageFontsCheatSheet +
+		"\n       " + usageFontsEmbed +
+		"\n       " + usageFontsReplace + generalFlags
 	usageLongFonts = `Print a list of supported fonts (includes the 14 PDF core fonts).
-Install given True Type fonts(.ttf) or True Type collections(.ttc) for usage in stamps/watermarks.
-Create single page PDF cheat sheets in current dir.`
+Install given True Type fonts(.ttf), True Type collections(.ttc), OpenType fonts(.otf)
+or web fonts(.woff, .woff2) for usage in stamps/watermarks.
+Create single page PDF cheat sheets in current dir.
+Embed non embedded fonts using installed user fonts.
+Replace a font by a core font or user font.
+
+         inFile ... input PDF file
+        outFile ... output PDF file
+       fontName ... font name as used in inFile (see "pdfcpu info -fonts")
+   userFontName ... installed user font matching fontName
+    newFontName ... core font or installed user font
+
+    Fonts are embedded as subsets retaining the original glyph widths.
+    Non embedded fonts are matched against user fonts by their PostScript name
+    unless a mapping is given.
+    Only simple fonts and Identity encoded CID fonts with a ToUnicode CMap are supported.
+
+    Eg. embed all fonts having an installed user font:
+           pdfcpu fonts embed in.pdf out.pdf
+
+        embed Arial using Liberation Sans:
+           pdfcpu fonts embed in.pdf out.pdf Arial:LiberationSans
+
+        replace Helvetica by Roboto:
+           pdfcpu fonts replace in.pdf out.pdf Helvetica Roboto-Regular
+`
 
 	usageKeywordsList   = "pdfcpu keywords list    inFile"
 	usageKeywordsAdd    = "pdfcpu keywords add     inFile keyword..."
@@ -987,6 +1055,83 @@ Create single page PDF cheat sheets in current dir.`
            pdfcpu keywords remove test.pdf
     `
 
+	usageLayersList    = "pdfcpu layers list    inFile"
+	usageLayersAdd     = "pdfcpu layers add     inFile name"
+	usageLayersRemove  = "pdfcpu layers remove  inFile name..."
+	usageLayersShow    = "pdfcpu layers show    inFile name..."
+	usageLayersHide    = "pdfcpu layers hide    inFile name..."
+	usageLayersFlatten = "pdfcpu layers flatten inFile [name...]"
+
+	usageLayers = "usage: " + usageLayersList +
+		"\n       " + usageLayersAdd +
+		"\n       " + usageLayersRemove +
+		"\n       " + usageLayersShow +
+		"\n       " + usageLayersHide +
+		"\n       " + usageLayersFlatten + generalFlags
+
+	usageLongLayers = `Manage layers (optional content groups).
+
+    inFile ... input PDF file
+      name ... layer name
+
+   list    ... list layers along with their default visibility and usage
+   add     ... add an empty visible layer eg. for placing watermarks using "layer:name"
+   remove  ... remove layers including their content
+   show    ... make layers visible by default
+   hide    ... hide layers by default
+   flatten ... turn layers (all layers if no name is given) into regular content,
+               content of hidden layers gets removed.
+
+    Eg. hide a layer:
+           pdfcpu layers hide test.pdf Notes
+
+        flatten all layers:
+           pdfcpu layers flatten test.pdf
+    `
+
+	usagePageLabelsList   = "pdfcpu pagelabels list    inFile"
+	usagePageLabelsAdd    = "pdfcpu pagelabels add     inFile description"
+	usagePageLabelsRemove = "pdfcpu pagelabels remove  inFile [page...]"
+
+	usagePageLabels = "usage: " + usagePageLabelsList +
+		"\n       " + usagePageLabelsAdd +
+		"\n       " + usagePageLabelsRemove + generalFlags
+
+	usageLongPageLabels = `Manage page labels.
+
+         inFile ... input PDF file
+    description ... page label range definition
+           page ... first page of a page label range
+
+   list   ... list page label ranges
+   add    ... add a page label range extending to the next range,
+              replaces any range starting on the same page
+   remove ... remove page label ranges (all ranges if no page is given)
+
+   A description is a comma separated list of parameter:value pairs:
+
+     page:   first page of this range, required
+     style:  D ... decimal arabic numerals (default)
+             R ... uppercase roman numerals
+             r ... lowercase roman numerals
+             A ... uppercase letters (A to Z, AA to ZZ ...)
+             a ... lowercase letters (a to z, aa to zz ...)
+             none  no numbering, use prefix only
+     prefix: label prefix
+     start:  numeric value for the first page of this range (default: 1)
+
+   Page labels may be used for page selection, please refer to "pdfcpu selectedpages".
+
+    Eg. front matter numbered i, ii, iii...:
+           pdfcpu pagelabels add test.pdf "page:1, style:r"
+
+        section prefixed numbering starting on page 5 with A-1:
+           pdfcpu pagelabels add test.pdf "page:5, prefix:A-"
+
+        remove all page labels:
+           pdfcpu pagelabels remove test.pdf
+    `
+
 	usagePropertiesList   = "pdfcpu properties list    inFile"
 	usagePropertiesAdd    = "pdfcpu properties add     inFile nameValuePair..."
 	usagePropertiesRemove = "pdfcpu properties remove  inFile [name...]"
@@ -1105,19 +1250,27 @@ Examples:
      
 ` + usageBoxDescription
 
-	usageAnnotsList   = "pdfcpu annotations list   [-p(ages) selectedPages] -- inFile"
-	usageAnnotsRemove = "pdfcpu annotations remove [-p(ages) selectedPages] -- inFile [outFile] [objNr|annotId|annotType]..."
+	usageAnnotsList    = "pdfcpu annotations list    [-p(ages) selectedPages] -- inFile"
+	usageAnnotsRemove  = "pdfcpu annotations remove  [-p(ages) selectedPages] -- inFile [outFile] [objNr|annotId|annotType]..."
+	usageAnnotsFlatten = "pdfcpu annotations flatten [-p(ages) selectedPages] -- inFile [outFile] [annotType]..."
+	usageAnnotsExport  = "pdfcpu annotations export  [-p(ages) selectedPages] [-xfdf] -- inFile [outFileXFDF]"
+	usageAnnotsImport  = "pdfcpu annotations import  [-xfdf] inFile inFileXFDF [outFile]"
 
 	usageAnnots = "usage: " + usageAnnotsList +
-		"\n       " + usageAnnotsRemove + generalFlags
+		"\n       " + usageAnnotsRemove +
+		"\n       " + usageAnnotsFlatten +
+		"\n       " + usageAnnotsExport +
+		"\n       " + usageAnnotsImport + generalFlags
 
 	usageLongAnnots = `Manage annotations.
    
       pages ... Please refer to "pdfcpu selectedpages"
      inFile ... input PDF file
+ inFileXFDF ... input XFDF file
+outFileXFDF ... output XFDF file (defaults to out.xfdf)
       objNr ... obj# from "pdfcpu annotations list"
     annotId ... id from "pdfcpu annotations list"
-  annotType ... Text, Link, FreeText, Line, Square, Circle, Polygon, PolyLine, HighLight, Underline, Squiggly, StrikeOut, Stamp,
+  annotType ... Text, Link, FreeText, Line, Square, Circle, Polygon, PolyLine, Highlight, Underline, Squiggly, StrikeOut, Stamp,
                 Caret, Ink, Popup, FileAttachment, Sound, Movie, Widget, Screen, PrinterMark, TrapNet, Watermark, 3D, Redact
    
    Examples:
@@ -1145,6 +1298,24 @@ Examples:
 
       Remove annotations by type, id and obj# and write to out.pdf:
          pdfcpu annot remove in.pdf out.pdf Link 30 Text someId
+
+      Flatten all markup annotations into page content and write to out.pdf:
+         pdfcpu annot flatten in.pdf out.pdf
+
+      Flatten all Highlight and Ink annotations on page 3:
+         pdfcpu annot flatten -pages 3 in.pdf Highlight Ink
+
+      Missing appearances are generated. Annotations flagged Hidden or NoView are removed without being rendered.
+      Annotations lacking the Print flag and annotations without appearance of unsupported type (eg. Stamp) are kept.
+      By default Link, Popup and Widget annotations are left untouched. Please use "pdfcpu form flatten" for form fields.
+
+      Export all markup annotations including replies and popups to comments.xfdf:
+         pdfcpu annot export -xfdf in.pdf comments.xfdf
+
+      Import annotations from comments.xfdf and write to out.pdf:
+         pdfcpu annot import -xfdf in.pdf comments.xfdf out.pdf
+
+      Annotations already present (same name on the same page) are skipped on import.
       `
 
 	usageImagesList    = "pdfcpu images list    [-p(ages) selectedPages] -- inFile..."
@@ -1224,32 +1395,49 @@ For more info on json syntax & samples please refer to :
    pdfcpu/pkg/testdata/json/*
    pdfcpu/pkg/samples/create/*`
 
-	usageFormListFields   = "pdfcpu form list   inFile..."
-	usageFormRemoveFields = "pdfcpu form remove inFile [outFile] <fieldID|fieldName>..."
-	usageFormLock         = "pdfcpu form lock   inFile [outFile] [fieldID|fieldName]..."
-	usageFormUnlock       = "pdfcpu form unlock inFile [outFile] [fieldID|fieldName]..."
-	usageFormReset        = "pdfcpu form reset  inFile [outFile] [fieldID|fieldName]..."
-	usageFormExport       = "pdfcpu form export inFile [outFileJSON]"
-	usageFormFill         = "pdfcpu form fill inFile inFileJSON [outFile]"
-	usageFormMultiFill    = "pdfcpu form multifill [-m(ode) single|merge] -- inFile inFileData outDir [outName]"
+	usageFormListFields   = "pdfcpu form list    inFile..."
+	usageFormRemoveFields = "pdfcpu form remove  inFile [outFile] <fieldID|fieldName>..."
+	usageFormLock         = "pdfcpu form lock    inFile [outFile] [fieldID|fieldName]..."
+	usageFormUnlock       = "pdfcpu form unlock  inFile [outFile] [fieldID|fieldName]..."
+	usageFormReset        = "pdfcpu form reset   inFile [outFile] [fieldID|fieldName]..."
+	usageFormFlatten      = "pdfcpu form flatten inFile [outFile] [fieldID|fieldName]..."
+	usageFormExport       = "pdfcpu form export  [-fdf|-xfdf] inFile [outFileJSON|outFileFDF|outFileXFDF]"
+	usageFormFill         = "pdfcpu form fill [-fdf|-xfdf] inFile inFileJSON|inFileFDF|inFileXFDF [outFile]"
+	usageFormMultiFill    = "pdfcpu form multifill [-m(ode) single|merge] [-sheet sheet] -- inFile inFileData outDir [outName]"
+	usageFormXFAList      = "pdfcpu form xfa list    inFile..."
+	usageFormXFAExtract   = "pdfcpu form xfa extract inFile outDir"
+	usageFormXFARemove    = "pdfcpu form xfa remove  inFile [outFile]"
+	usageFormXFAFill      = "pdfcpu form xfa fill    inFile inFileData [outFile]"
+
+	usageFormXFA = usageFormXFAList +
+		"\n       " + usageFormXFAExtract +
+		"\n       " + usageFormXFARemove +
+		"\n       " + usageFormXFAFill
 
 	usageForm = "usage: " + usageFormListFields +
 		"\n       " + usageFormRemoveFields +
 		"\n       " + usageFormLock +
 		"\n       " + usageFormUnlock +
 		"\n       " + usageFormReset +
+		"\n       " + usageFormFlatten +
 		"\n       " + usageFormExport +
 		"\n\n       " + usageFormFill +
-		"\n       " + usageFormMultiFill + generalFlags
+		"\n       " + usageFormMultiFill +
+		"\n\n       " + usageFormXFA + generalFlags
 
 	usageLongForm = `Manage PDF forms.
 
            inFile ... input PDF file
-       inFileData ... input CSV or JSON file
+       inFileData ... input CSV, XLSX or JSON file (xfa fill: XML or JSON file)
        inFileJSON ... input JSON file
+        inFileFDF ... input FDF file
+       inFileXFDF ... input XFDF file
           outFile ... output PDF file
       outFileJSON ... output JSON file
+       outFileFDF ... output FDF file
+      outFileXFDF ... output XFDF file
              mode ... output mode (defaults to single)
+            sheet ... XLSX sheet name or number (defaults to the first sheet)
            outDir ... output directory
           outName ... base output name
           fieldID ... as indicated by "pdfcpu form list"
@@ -1287,17 +1475,30 @@ Supported usecases:
          "pdfcpu form reset in.pdf" resets the whole form of in.pdf.
          You may supply a mixed list of field ids and field names.
        
-   6) Export all form fields as preparation for form filling:
+   6) Turn some or all fields into static page content:
+         "pdfcpu form flatten in.pdf signature" merges the appearance of the field "signature" into its page and removes the field.
+         "pdfcpu form flatten in.pdf" flattens the whole form of in.pdf.
+         Fields flagged Hidden or NoView or lacking the Print flag get removed without being rendered.
+         You may supply a mixed list of field ids and field names.
+
+   7) Export all form fields as preparation for form filling:
          "pdfcpu form export in.pdf" exports field data into a JSON structure written to in.json.
    
-   7) Fill a form with data:
+   8) Fill a form with data:
          a) Export your form into in.json and edit the field values.
          b) Optionally trim down each field to id or name and value(s).
          c) "pdfcpu form fill in.pdf in.json out.pdf" fills in.pdf with form data from in.json and writes the result to out.pdf.
+      or
+         a) "pdfcpu form export -xfdf in.pdf in.xfdf" exports the field values as XFDF for use with Acrobat.
+         b) "pdfcpu form fill -xfdf in.pdf in.xfdf out.pdf" fills in.pdf with the field values of in.xfdf.
+      or
+         a) "pdfcpu form export -fdf in.pdf in.fdf" exports the field values as FDF.
+         b) "pdfcpu form fill in.pdf in.fdf out.pdf" fills in.pdf with the field values of in.fdf.
+            Choice fields take /Opt export values, checkboxes take their on state name, eg. /Yes.
 
    or
 
-   8) Generate a sequence of filled instances of a form:
+   9) Generate a sequence of filled instances of a form:
          a) Export your form to in.json and edit the field values.
             Extend the JSON Array containing the form by using copy & paste and edit the corresponding form data.
          b) Optionally trim down each field to id or name and value(s).
@@ -1307,10 +1508,17 @@ Supported usecases:
          b) Create a CSV file holding form instance data where each CSV line corresponds to one form data tuple.
             The first line identifies fields via id or name from in.json.
          c) "pdfcpu form multifill in.pdf in.csv outDir" creates a separate PDF for each filled form instance in outDir.
+      or
+         a) Export your form to in.json.
+         b) Create an Excel sheet where each row corresponds to one form data tuple.
+            The first row identifies fields via id or name from in.json.
+            Date cells get formatted according to the date format of the corresponding date field.
+         c) "pdfcpu form multifill -sheet data in.pdf in.xlsx outDir" creates a separate PDF for each row of the sheet "data".
+            Omit -sheet to use the first sheet.
 
    or
 
-   9) Generate a sequence of filled instances of a form and merge output:
+  10) Generate a sequence of filled instances of a form and merge output:
          a) Export your form to in.json and edit the field values.
             Extend the JSON Array containing the form by using copy & paste and edit the corresponding form data.
          b) Optionally trim down each field to id or name and value(s).
@@ -1321,6 +1529,15 @@ Supported usecases:
             The first line identifies fields via id or name in in.json.
          c) "pdfcpu form multifill -m merge in.pdf in.csv outDir" creates a single output PDF in outDir.
 
+  11) Deal with XFA forms:
+         "pdfcpu form xfa list in.pdf" reports whether in.pdf is a static or dynamic XFA form and lists its XDP packets.
+         A hybrid XFA form also comes with AcroForm fields.
+         "pdfcpu form xfa extract in.pdf outDir" writes the packets (template, datasets, config..) and in.xdp to outDir.
+         "pdfcpu form xfa fill in.pdf data.xml out.pdf" replaces the XFA datasets by data.xml (xfa:datasets, xfa:data or the data root element).
+         "pdfcpu form xfa fill in.pdf data.json out.pdf" does the same for JSON where objects turn into subforms and arrays into repeated elements.
+            The AcroForm fields of hybrid forms are filled with matching data values.
+         "pdfcpu form xfa remove in.pdf out.pdf" drops the XFA form so viewers consistently use the AcroForm fields.
+
 
    (For syntax and details please refer to pdfcpu/pkg/api/test/form_test.go)`
 
@@ -1531,6 +1748,31 @@ description ... scalefactor, dimensions, formsize, enforce, border, bgcolor
       outFileJSON ... output PDF file
 `
 
+	usageStructureExport = "pdfcpu structure export inFile [outFileJSON]"
+	usageStructureImport = "pdfcpu structure import inFile inFileJSON [outFile]"
+
+	usageStructure = "usage: " + usageStructureExport +
+		"\n       " + usageStructureImport + generalFlags
+
+	usageLongStructure = `Manage the logical structure (structure tree) of tagged PDFs.
+
+           inFile ... input PDF file
+       inFileJSON ... input JSON file
+          outFile ... output PDF file
+      outFileJSON ... output JSON file
+
+Export writes all structure elements including roles, attributes, Alt, ActualText, Lang,
+page numbers, marked content ids and the role map.
+
+Import applies an edited export back to inFile.
+Structure elements are matched by id. The following changes are applied:
+
+   - role changes
+   - title, Alt, ActualText and Lang (empty values remove the entry)
+   - role map edits (if present)
+
+Attributes, page numbers, marked content ids and object references are read only.`
+
 	usagePageLayoutList  = "pdfcpu pagelayout list  inFile"
 	usagePageLayoutSet   = "pdfcpu pagelayout set   inFile value"
 	usagePageLayoutReset = "pdfcpu pagelayout reset inFile"
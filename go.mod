@@ -5,6 +5,7 @@ go 1.24.0
 toolchain go1.24.2
 
 require (
+	github.com/andybalholm/brotli v1.1.1
 	github.com/hhrutter/lzw v1.0.0
 	github.com/hhrutter/pkcs7 v0.2.0
 	github.com/hhrutter/tiff v1.0.2
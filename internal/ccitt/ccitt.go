@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ccitt implements an encoder and a decoder for bilevel images coded with CCITT Group 3 or Group 4 facsimile compression
+// as used by the PDF filter CCITTFaxDecode.
+//
+// Supported are pure one-dimensional coding (Group 3, 1-D), mixed one- and two-dimensional coding (Group 3, 2-D)
+// and pure two-dimensional coding (Group 4).
+// See ITU-T T.4 and T.6.
+package ccitt
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+var errCorrupt = errors.New("pdfcpu: ccitt: corrupt data")
+
+// Params are the parameters of a CCITTFaxDecode filter.
+// Image data is organized in rows of packed bits, each row starting on a byte boundary.
+type Params struct {
+	// K < 0: pure two-dimensional coding (Group 4)
+	// K = 0: pure one-dimensional coding (Group 3, 1-D)
+	// K > 0: mixed one- and two-dimensional coding (Group 3, 2-D), at most K-1 rows are coded two-dimensionally after a one-dimensionally coded row.
+	K                int
+	Columns, Rows    int
+	EndOfLine        bool // Each row is preceded by an EOL code.
+	EncodedByteAlign bool // Each coded row starts on a byte boundary.
+	EndOfBlock       bool // The data is terminated by an end-of-block pattern.
+	BlackIs1         bool // 1 bits are black pixels.
+}
+
+func (p Params) check(n int) error {
+	if p.Columns <= 0 || p.Rows < 0 || p.Rows > 0 && (p.Columns+7)/8 > n/p.Rows {
+		return errors.Errorf("pdfcpu: ccitt: invalid image size %d x %d", p.Columns, p.Rows)
+	}
+	return nil
+}
+
+// twoDimensional returns true if row y is coded two-dimensionally.
+func (p Params) twoDimensional(y int) bool {
+	return p.K < 0 || p.K > 0 && y%p.K != 0
+}
+
+// changes returns the positions of the changing elements of a row of w pixels.
+// A changing element is a pixel whose color differs from the color of the previous pixel, starting with an imaginary white pixel.
+// Therefore changes at even indices start black runs and changes at odd indices start white runs.
+func changes(row []byte, w int, blackIs1 bool, ch []int) []int {
+	ch = ch[:0]
+	white := byte(1)
+	if blackIs1 {
+		white = 0
+	}
+	c := white
+	for x := 0; x < w; x++ {
+		if v := row[x>>3] >> (7 - uint(x&7)) & 1; v != c {
+			ch = append(ch, x)
+			c = v
+		}
+	}
+	return ch
+}
+
+// at returns the changing element ch[i] or w if there is none.
+func at(ch []int, i, w int) int {
+	if i < len(ch) {
+		return ch[i]
+	}
+	return w
+}
+
+// next returns the index of the first changing element following position a0.
+func next(ch []int, a0 int) int {
+	return sort.SearchInts(ch, a0+1)
+}
+
+// referenceChanges returns the changing elements b1 and b2 of the reference row for the coding position a0 and its color,
+// see T.4 4.2.1.3.1
+func referenceChanges(ref []int, a0, color, w int) (int, int) {
+	i := next(ref, a0)
+	if i%2 != color {
+		// b1 is of opposite color to a0.
+		i++
+	}
+	return at(ref, i, w), at(ref, i+1, w)
+}
+
+type bitWriter struct {
+	buf []byte
+	n   int // number of bits written
+}
+
+func (w *bitWriter) write(c code) {
+	for i := c.n - 1; i >= 0; i-- {
+		if w.n&7 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		if c.bits>>uint(i)&1 == 1 {
+			w.buf[w.n>>3] |= 0x80 >> uint(w.n&7)
+		}
+		w.n++
+	}
+}
+
+func (w *bitWriter) writeString(s string) {
+	w.write(parseCode(s))
+}
+
+// pad writes 0 bits until the next m bits end on a byte boundary.
+func (w *bitWriter) pad(m int) {
+	if r := (w.n + m) & 7; r != 0 {
+		w.write(code{n: 8 - r})
+	}
+}
+
+type bitReader struct {
+	data []byte
+	pos  int // bit position
+}
+
+// peek returns the next n <= 24 bits, bits beyond the end of the data read as 0.
+func (r *bitReader) peek(n int) uint32 {
+	var v uint32
+	i := r.pos >> 3
+	for k := 0; k < 4; k++ {
+		v <<= 8
+		if i+k < len(r.data) {
+			v |= uint32(r.data[i+k])
+		}
+	}
+	return v << uint(r.pos&7) >> uint(32-n)
+}
+
+func (r *bitReader) skip(n int) {
+	r.pos += n
+}
+
+func (r *bitReader) align() {
+	r.pos = (r.pos + 7) &^ 7
+}
+
+func (r *bitReader) eof() bool {
+	return r.pos >= 8*len(r.data)
+}
+
+// decode reads the next code using the lookup table t.
+func (r *bitReader) decode(t []tableEntry) (int, error) {
+	e := t[r.peek(lookupBits)]
+	if e.n == 0 || r.pos+int(e.n) > 8*len(r.data) {
+		return 0, errCorrupt
+	}
+	r.pos += int(e.n)
+	return int(e.v), nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccitt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	xccitt "golang.org/x/image/ccitt"
+)
+
+func checkPrefixFree(t *testing.T, name string, codes []string) {
+	t.Helper()
+	for i, c1 := range codes {
+		for j, c2 := range codes {
+			if i != j && strings.HasPrefix(c2, c1) {
+				t.Errorf("%s: code %s is a prefix of %s", name, c1, c2)
+			}
+		}
+	}
+}
+
+func TestCodes(t *testing.T) {
+	white := append(append(whiteTermCodes[:], whiteMakeupCodes[:]...), extMakeupCodes[:]...)
+	black := append(append(blackTermCodes[:], blackMakeupCodes[:]...), extMakeupCodes[:]...)
+	checkPrefixFree(t, "white", append(white, eol))
+	checkPrefixFree(t, "black", append(black, eol))
+	checkPrefixFree(t, "mode", append(modeCodes[:], eol))
+}
+
+// testImage returns h rows of w pixels made of random runs including runs longer than a single make-up code
+// and rows similar to their predecessors.
+func testImage(w, h int, blackIs1 bool) []byte {
+	r := rand.New(rand.NewSource(int64(w*h + 1)))
+	stride := (w + 7) / 8
+	buf := make([]byte, stride*h)
+	for y := 0; y < h; y++ {
+		row := buf[y*stride : (y+1)*stride]
+		if y > 0 && r.Intn(3) > 0 {
+			copy(row, buf[(y-1)*stride:])
+			for i := r.Intn(4); i > 0; i-- {
+				x := r.Intn(w)
+				row[x>>3] ^= 0x80 >> uint(x&7)
+			}
+			continue
+		}
+		black := r.Intn(2) == 0
+		for x := 0; x < w; {
+			n := 1 + r.Intn(40)
+			if r.Intn(10) == 0 {
+				n = r.Intn(3000)
+			}
+			for ; n > 0 && x < w; n, x = n-1, x+1 {
+				if black == blackIs1 {
+					row[x>>3] |= 0x80 >> uint(x&7)
+				}
+			}
+			black = !black
+		}
+	}
+	return buf
+}
+
+func equalPixels(a, b []byte, w, h int) bool {
+	stride := (w + 7) / 8
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i, m := y*stride+x>>3, byte(0x80>>uint(x&7))
+			if a[i]&m != b[i]&m {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {7, 3}, {8, 10}, {100, 50}, {1728, 40}, {6000, 12}} {
+		for _, k := range []int{-1, 0, 1, 2, 4} {
+			for _, p := range []Params{
+				{},
+				{EndOfLine: true, EndOfBlock: true},
+				{EncodedByteAlign: true},
+				{EncodedByteAlign: true, EndOfLine: true, BlackIs1: true},
+			} {
+				p.K, p.Columns, p.Rows = k, size[0], size[1]
+				t.Run(fmt.Sprintf("%+v", p), func(t *testing.T) {
+					want := testImage(p.Columns, p.Rows, p.BlackIs1)
+
+					enc, err := Encode(want, p)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					got, err := Decode(enc, p)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !equalPixels(got, want, p.Columns, p.Rows) {
+						t.Fatal("decoded image differs")
+					}
+
+					// Cross check Group 4 and Group 3 1-D with EOL codes using x/image/ccitt.
+					sf := xccitt.Group4
+					if p.K == 0 && p.EndOfLine && p.EndOfBlock {
+						sf = xccitt.Group3
+					} else if p.K >= 0 {
+						return
+					}
+					opts := &xccitt.Options{Align: p.EncodedByteAlign, Invert: p.BlackIs1}
+					got, err = io.ReadAll(xccitt.NewReader(bytes.NewReader(enc), xccitt.MSB, sf, p.Columns, p.Rows, opts))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !equalPixels(got, want, p.Columns, p.Rows) {
+						t.Fatal("image decoded by x/image/ccitt differs")
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	// A page of text like content compresses well with Group 4.
+	w, h := 1728, 200
+	want := testImage(w, h, false)
+	enc, err := Encode(want, Params{K: -1, Columns: w, Rows: h})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enc) >= len(want)/2 {
+		t.Errorf("poor compression: %d -> %d bytes", len(want), len(enc))
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	w, h := 100, 20
+	enc, err := Encode(testImage(w, h, false), Params{K: 2, Columns: w, Rows: h})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range enc {
+		b := append([]byte(nil), enc...)
+		b[i] ^= 0x5A
+		// Must not panic.
+		_, _ = Decode(b, Params{K: 2, Columns: w, Rows: h})
+		_, _ = Decode(enc[:i], Params{K: 2, Columns: w, Rows: h})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccitt
+
+// Terminating codes for run lengths 0-63, see T.4 Table 2
+var (
+	whiteTermCodes = [64]string{
+		"00110101", "000111", "0111", "1000", "1011", "1100", "1110", "1111",
+		"10011", "10100", "00111", "01000", "001000", "000011", "110100", "110101",
+		"101010", "101011", "0100111", "0001100", "0001000", "0010111", "0000011", "0000100",
+		"0101000", "0101011", "0010011", "0100100", "0011000", "00000010", "00000011", "00011010",
+		"00011011", "00010010", "00010011", "00010100", "00010101", "00010110", "00010111", "00101000",
+		"00101001", "00101010", "00101011", "00101100", "00101101", "00000100", "00000101", "00001010",
+		"00001011", "01010010", "01010011", "01010100", "01010101", "00100100", "00100101", "01011000",
+		"01011001", "01011010", "01011011", "01001010", "01001011", "00110010", "00110011", "00110100",
+	}
+
+	blackTermCodes = [64]string{
+		"0000110111", "010", "11", "10", "011", "0011", "0010", "00011",
+		"000101", "000100", "0000100", "0000101", "0000111", "00000100", "00000111", "000011000",
+		"0000010111", "0000011000", "0000001000", "00001100111", "00001101000", "00001101100", "00000110111", "00000101000",
+		"00000010111", "00000011000", "000011001010", "000011001011", "000011001100", "000011001101", "000001101000", "000001101001",
+		"000001101010", "000001101011", "000011010010", "000011010011", "000011010100", "000011010101", "000011010110", "000011010111",
+		"000001101100", "000001101101", "000011011010", "000011011011", "000001010100", "000001010101", "000001010110", "000001010111",
+		"000001100100", "000001100101", "000001010010", "000001010011", "000000100100", "000000110111", "000000111000", "000000100111",
+		"000000101000", "000001011000", "000001011001", "000000101011", "000000101100", "000001011010", "000001100110", "000001100111",
+	}
+)
+
+// Make-up codes for run lengths 64-1728 in steps of 64, see T.4 Table 3
+var (
+	whiteMakeupCodes = [27]string{
+		"11011", "10010", "010111", "0110111", "00110110", "00110111", "01100100", "01100101",
+		"01101000", "01100111", "011001100", "011001101", "011010010", "011010011", "011010100", "011010101",
+		"011010110", "011010111", "011011000", "011011001", "011011010", "011011011", "010011000", "010011001",
+		"010011010", "011000", "010011011",
+	}
+
+	blackMakeupCodes = [27]string{
+		"0000001111", "000011001000", "000011001001", "000001011011", "000000110011", "000000110100", "000000110101", "0000001101100",
+		"0000001101101", "0000001001010", "0000001001011", "0000001001100", "0000001001101", "0000001110010", "0000001110011", "0000001110100",
+		"0000001110101", "0000001110110", "0000001110111", "0000001010010", "0000001010011", "0000001010100", "0000001010101", "0000001011010",
+		"0000001011011", "0000001100100", "0000001100101",
+	}
+)
+
+// Extended make-up codes shared by both colors for run lengths 1792-2560 in steps of 64, see T.4 Table 3
+var extMakeupCodes = [13]string{
+	"00000001000", "00000001100", "00000001101", "000000010010", "000000010011", "000000010100", "000000010101",
+	"000000010110", "000000010111", "000000011100", "000000011101", "000000011110", "000000011111",
+}
+
+// maxRun is the longest run length with a single make-up code.
+const maxRun = 2560
+
+const eol = "000000000001"
+
+// Two-dimensional coding modes.
+const (
+	modePass = iota
+	modeHorizontal
+	modeV0
+	modeVR1
+	modeVR2
+	modeVR3
+	modeVL1
+	modeVL2
+	modeVL3
+)
+
+// Mode codes, see T.4 Table 4
+var modeCodes = [...]string{
+	modePass:       "0001",
+	modeHorizontal: "001",
+	modeV0:         "1",
+	modeVR1:        "011",
+	modeVR2:        "000011",
+	modeVR3:        "0000011",
+	modeVL1:        "010",
+	modeVL2:        "000010",
+	modeVL3:        "0000010",
+}
+
+// verticalModes maps the offset a1-b1 + 3 to its vertical mode.
+var verticalModes = [7]int{modeVL3, modeVL2, modeVL1, modeV0, modeVR1, modeVR2, modeVR3}
+
+// code is a variable length code of n bits.
+type code struct {
+	bits uint32
+	n    int
+}
+
+func parseCode(s string) code {
+	c := code{n: len(s)}
+	for i := 0; i < len(s); i++ {
+		c.bits = c.bits<<1 | uint32(s[i]-'0')
+	}
+	return c
+}
+
+// runCode returns the code for a run length of either a terminating code (n < 64) or a make-up code (n multiple of 64).
+func runCode(n int, black bool) code {
+	switch {
+	case n < 64 && black:
+		return parseCode(blackTermCodes[n])
+	case n < 64:
+		return parseCode(whiteTermCodes[n])
+	case n > 1728:
+		return parseCode(extMakeupCodes[n/64-28])
+	case black:
+		return parseCode(blackMakeupCodes[n/64-1])
+	}
+	return parseCode(whiteMakeupCodes[n/64-1])
+}
+
+// lookupBits is the number of bits a decoding table is indexed by, the longest code has 13 bits.
+const lookupBits = 13
+
+// tableEntry is the value and length of the code found at a table index, n == 0 marks an invalid code.
+type tableEntry struct {
+	v int16
+	n uint8
+}
+
+// newDecodeTable returns a lookup table for the codes mapped to their values.
+func newDecodeTable(codes map[string]int) []tableEntry {
+	t := make([]tableEntry, 1<<lookupBits)
+	for s, v := range codes {
+		c := parseCode(s)
+		shift := uint(lookupBits - c.n)
+		for i := uint32(0); i < 1<<shift; i++ {
+			t[c.bits<<shift|i] = tableEntry{v: int16(v), n: uint8(c.n)}
+		}
+	}
+	return t
+}
+
+func runLengthCodes(term [64]string, makeup [27]string) map[string]int {
+	m := map[string]int{}
+	for i, s := range term {
+		m[s] = i
+	}
+	for i, s := range makeup {
+		m[s] = (i + 1) * 64
+	}
+	for i, s := range extMakeupCodes {
+		m[s] = (i + 28) * 64
+	}
+	return m
+}
+
+var (
+	whiteTable = newDecodeTable(runLengthCodes(whiteTermCodes, whiteMakeupCodes))
+	blackTable = newDecodeTable(runLengthCodes(blackTermCodes, blackMakeupCodes))
+	modeTable  = newDecodeTable(func() map[string]int {
+		m := map[string]int{}
+		for mode, s := range modeCodes {
+			m[s] = mode
+		}
+		return m
+	}())
+)
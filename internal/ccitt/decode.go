@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccitt
+
+// Decode returns p.Rows rows of p.Columns pixels decoded from data coded according to p.
+// EOL codes are optional regardless of p.EndOfLine. Rows missing at the end of data are white.
+func Decode(data []byte, p Params) ([]byte, error) {
+	w, stride := p.Columns, (p.Columns+7)/8
+	if err := p.check(stride * p.Rows); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, stride*p.Rows)
+	if !p.BlackIs1 {
+		for i := range buf {
+			buf[i] = 0xFF
+		}
+	}
+
+	br := &bitReader{data: data}
+
+	var ref, cur []int
+	for y := 0; y < p.Rows; y++ {
+		if p.EncodedByteAlign && (p.K < 0 || !p.EndOfLine) {
+			br.align()
+		}
+
+		if p.K >= 0 {
+			// Skip fill bits and an optional EOL code.
+			for !br.eof() && br.peek(len(eol)) == 0 {
+				br.skip(1)
+			}
+			if br.peek(len(eol)) == 1 {
+				br.skip(len(eol))
+			}
+		}
+
+		if br.eof() {
+			break
+		}
+
+		twoD := p.twoDimensional(y)
+		if p.K > 0 {
+			twoD = br.peek(1) == 0
+			br.skip(1)
+		}
+
+		var err error
+		if twoD {
+			cur, err = decode2D(br, cur[:0], ref, w)
+		} else {
+			cur, err = decode1D(br, cur[:0], w)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		setRow(buf[y*stride:], cur, w, p.BlackIs1)
+		ref, cur = cur, ref
+	}
+
+	return buf, nil
+}
+
+// setRow sets the black runs of a row described by its changing elements ch.
+func setRow(row []byte, ch []int, w int, blackIs1 bool) {
+	for i := 0; i < len(ch); i += 2 {
+		for x := ch[i]; x < at(ch, i+1, w); x++ {
+			if blackIs1 {
+				row[x>>3] |= 0x80 >> uint(x&7)
+			} else {
+				row[x>>3] &^= 0x80 >> uint(x&7)
+			}
+		}
+	}
+}
+
+// addChange appends the changing element x to ch.
+// Two changes at the same position cancel each other out.
+func addChange(ch []int, x, w int) ([]int, error) {
+	if n := len(ch); n > 0 {
+		if x < ch[n-1] {
+			return nil, errCorrupt
+		}
+		if x == ch[n-1] {
+			return ch[:n-1], nil
+		}
+	}
+	if x < w {
+		ch = append(ch, x)
+	}
+	return ch, nil
+}
+
+// readRun reads the make-up codes and the terminating code of a run.
+func readRun(br *bitReader, black bool) (int, error) {
+	t := whiteTable
+	if black {
+		t = blackTable
+	}
+	n := 0
+	for {
+		v, err := br.decode(t)
+		if err != nil {
+			return 0, err
+		}
+		n += v
+		if v < 64 {
+			return n, nil
+		}
+	}
+}
+
+func decode1D(br *bitReader, cur []int, w int) ([]int, error) {
+	a0 := 0
+	for i := 0; a0 < w; i++ {
+		n, err := readRun(br, i%2 == 1)
+		if err != nil {
+			return nil, err
+		}
+		if a0 += n; a0 > w {
+			return nil, errCorrupt
+		}
+		if cur, err = addChange(cur, a0, w); err != nil {
+			return nil, err
+		}
+	}
+	return cur, nil
+}
+
+func decode2D(br *bitReader, cur, ref []int, w int) ([]int, error) {
+	a0, color := -1, 0
+	for a0 < w {
+		mode, err := br.decode(modeTable)
+		if err != nil {
+			return nil, err
+		}
+
+		b1, b2 := referenceChanges(ref, a0, color, w)
+
+		switch mode {
+
+		case modePass:
+			a0 = b2
+
+		case modeHorizontal:
+			a1 := max(a0, 0)
+			for i := 0; i < 2; i++ {
+				n, err := readRun(br, color^i == 1)
+				if err != nil {
+					return nil, err
+				}
+				if a1 += n; a1 > w {
+					return nil, errCorrupt
+				}
+				if cur, err = addChange(cur, a1, w); err != nil {
+					return nil, err
+				}
+			}
+			a0 = a1
+
+		default:
+			a1 := b1
+			for i, m := range verticalModes {
+				if m == mode {
+					a1 += i - 3
+				}
+			}
+			if a1 < max(a0, 0) || a1 > w {
+				return nil, errCorrupt
+			}
+			if cur, err = addChange(cur, a1, w); err != nil {
+				return nil, err
+			}
+			a0 = a1
+			color ^= 1
+		}
+	}
+	return cur, nil
+}
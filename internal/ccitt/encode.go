@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccitt
+
+// Encode returns the image data of p.Rows rows of p.Columns pixels coded according to p.
+func Encode(data []byte, p Params) ([]byte, error) {
+	if err := p.check(len(data)); err != nil {
+		return nil, err
+	}
+
+	w, stride := p.Columns, (p.Columns+7)/8
+	bw := &bitWriter{}
+
+	var ref, cur []int
+	for y := 0; y < p.Rows; y++ {
+		cur = changes(data[y*stride:], w, p.BlackIs1, cur)
+		twoD := p.twoDimensional(y)
+
+		if p.K >= 0 && p.EndOfLine {
+			if p.EncodedByteAlign {
+				// The EOL code ends on a byte boundary.
+				bw.pad(len(eol))
+			}
+			bw.writeString(eol)
+		} else if p.EncodedByteAlign {
+			bw.pad(0)
+		}
+
+		if p.K > 0 {
+			// Tag bit announcing the coding of the row.
+			if twoD {
+				bw.writeString("0")
+			} else {
+				bw.writeString("1")
+			}
+		}
+
+		if twoD {
+			encode2D(bw, cur, ref, w)
+		} else {
+			encode1D(bw, cur, w)
+		}
+
+		ref, cur = cur, ref
+	}
+
+	if p.EndOfBlock {
+		// EOFB for Group 4 (T.6 2.4.1.1), RTC for Group 3 (T.4 4.1.4 and 4.2.2)
+		n := 6
+		if p.K < 0 {
+			n = 2
+		}
+		for i := 0; i < n; i++ {
+			bw.writeString(eol)
+			if p.K > 0 {
+				bw.writeString("1")
+			}
+		}
+	}
+
+	return bw.buf, nil
+}
+
+// writeRun writes the codes for a run of n pixels.
+func writeRun(bw *bitWriter, n int, black bool) {
+	for n > maxRun {
+		bw.write(runCode(maxRun, black))
+		n -= maxRun
+	}
+	if n >= 64 {
+		bw.write(runCode(n&^63, black))
+		n &= 63
+	}
+	bw.write(runCode(n, black))
+}
+
+// encode1D codes a row as alternating white and black runs starting with white, see T.4 4.1
+func encode1D(bw *bitWriter, cur []int, w int) {
+	a0 := 0
+	for i := 0; ; i++ {
+		a1 := at(cur, i, w)
+		writeRun(bw, a1-a0, i%2 == 1)
+		if a1 == w {
+			return
+		}
+		a0 = a1
+	}
+}
+
+// encode2D codes a row relative to the reference row ref, see T.4 4.2.1.3
+func encode2D(bw *bitWriter, cur, ref []int, w int) {
+	a0, color := -1, 0
+	for a0 < w {
+		i := next(cur, a0)
+		a1 := at(cur, i, w)
+		b1, b2 := referenceChanges(ref, a0, color, w)
+
+		if b2 < a1 {
+			bw.writeString(modeCodes[modePass])
+			a0 = b2
+			continue
+		}
+
+		if d := a1 - b1; d >= -3 && d <= 3 {
+			bw.writeString(modeCodes[verticalModes[d+3]])
+			a0 = a1
+			color ^= 1
+			continue
+		}
+
+		a2 := at(cur, i+1, w)
+		bw.writeString(modeCodes[modeHorizontal])
+		writeRun(bw, a1-max(a0, 0), color == 1)
+		writeRun(bw, a2-a1, color == 0)
+		a0 = a2
+	}
+}
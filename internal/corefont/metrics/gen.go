@@ -47,6 +47,13 @@ func main() {
 		finish(w, "standard.go")
 	}
 
+	// Generate kerning.go.
+	{
+		w := &bytes.Buffer{}
+		w.WriteString(kerningHeader)
+		writeCoreFontKerning(w)
+		finish(w, "kerning.go")
+	}
 }
 
 func writeWinAnsiGlyphMap(w *bytes.Buffer) {
@@ -181,6 +188,62 @@ func writeFontMetrics(w *bytes.Buffer, dir, fileName string) {
 	w.WriteString("\n},\n")
 }
 
+func writeCoreFontKerning(w *bytes.Buffer) {
+	s := `// CoreFontKerning represents the kerning pairs of the Adobe standard type 1 core fonts
+	// as horizontal adjustments in glyph space units for pairs of glyph names.
+	var CoreFontKerning = map[string]map[[2]string]int{
+	`
+	w.WriteString(s)
+	dir := "../Core14_AFMs"
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".afm") {
+			continue
+		}
+		writeFontKerning(w, dir, f.Name())
+	}
+	w.WriteString("}")
+}
+
+func writeFontKerning(w *bytes.Buffer, dir, fileName string) {
+	f, err := os.Open(filepath.Join(dir, fileName))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	var pairs []string
+	for s.Scan() {
+		ss := strings.Fields(s.Text())
+		if len(ss) == 0 || ss[0] != "KPX" {
+			continue
+		}
+		if len(ss) != 4 {
+			panic("corrupt .afm file!")
+		}
+		i, err := strconv.Atoi(ss[3])
+		if err != nil {
+			log.Fatal(err)
+		}
+		pairs = append(pairs, fmt.Sprintf("{\"%s\", \"%s\"}: %d", ss[1], ss[2], i))
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if len(pairs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\"%s\": {%s},\n", fileName[:len(fileName)-4], strings.Join(pairs, ", "))
+}
+
+const kerningHeader = `// generated by "go run gen.go". DO NOT EDIT.
+
+package metrics
+`
+
 const header = `// generated by "go run gen.go". DO NOT EDIT.
 
 package metrics
@@ -34,18 +34,19 @@ package metrics
 // Helvetica-BoldOblique,
 // Courier-BoldOblique
 
-// CoreFontCharWidth returns the character width for fontName and c in glyph space units.
-func CoreFontCharWidth(fontName string, c int) int {
-	var m map[int]string
+func glyphMap(fontName string) map[int]string {
 	switch fontName {
 	case "Symbol":
-		m = SymbolGlyphMap
+		return SymbolGlyphMap
 	case "ZapfDingbats":
-		m = ZapfDingbatsGlyphMap
-	default:
-		m = WinAnsiGlyphMap
+		return ZapfDingbatsGlyphMap
 	}
-	glyphName := m[c]
+	return WinAnsiGlyphMap
+}
+
+// CoreFontCharWidth returns the character width for fontName and c in glyph space units.
+func CoreFontCharWidth(fontName string, c int) int {
+	glyphName := glyphMap(fontName)[c]
 	fm := CoreFontMetrics[fontName]
 	w, ok := fm.W[glyphName]
 	if !ok {
@@ -53,3 +54,13 @@ func CoreFontCharWidth(fontName string, c int) int {
 	}
 	return w
 }
+
+// CoreFontCharKerning returns the kerning amount for fontName and the character pair c1, c2 in glyph space units.
+func CoreFontCharKerning(fontName string, c1, c2 int) int {
+	kp, ok := CoreFontKerning[fontName]
+	if !ok {
+		return 0
+	}
+	m := glyphMap(fontName)
+	return kp[[2]string{m[c1], m[c2]}]
+}
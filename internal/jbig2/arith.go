@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+// qeEntry is a probability estimation state of the arithmetic decoder, see Table E.1
+type qeEntry struct {
+	qe         uint32
+	nmps, nlps uint8
+	switchMPS  bool
+}
+
+var qeTable = [47]qeEntry{
+	{0x5601, 1, 1, true}, {0x3401, 2, 6, false}, {0x1801, 3, 9, false}, {0x0AC1, 4, 12, false},
+	{0x0521, 5, 29, false}, {0x0221, 38, 33, false}, {0x5601, 7, 6, true}, {0x5401, 8, 14, false},
+	{0x4801, 9, 14, false}, {0x3801, 10, 14, false}, {0x3001, 11, 17, false}, {0x2401, 12, 18, false},
+	{0x1C01, 13, 20, false}, {0x1601, 29, 21, false}, {0x5601, 15, 14, true}, {0x5401, 16, 14, false},
+	{0x5101, 17, 15, false}, {0x4801, 18, 16, false}, {0x3801, 19, 17, false}, {0x3401, 20, 18, false},
+	{0x3001, 21, 19, false}, {0x2801, 22, 19, false}, {0x2401, 23, 20, false}, {0x2201, 24, 21, false},
+	{0x1C01, 25, 22, false}, {0x1801, 26, 23, false}, {0x1601, 27, 24, false}, {0x1401, 28, 25, false},
+	{0x1201, 29, 26, false}, {0x1101, 30, 27, false}, {0x0AC1, 31, 28, false}, {0x09C1, 32, 29, false},
+	{0x08A1, 33, 30, false}, {0x0521, 34, 31, false}, {0x0441, 35, 32, false}, {0x02A1, 36, 33, false},
+	{0x0221, 37, 34, false}, {0x0141, 38, 35, false}, {0x0111, 39, 36, false}, {0x0085, 40, 37, false},
+	{0x0049, 41, 38, false}, {0x0025, 42, 39, false}, {0x0015, 43, 40, false}, {0x0009, 44, 41, false},
+	{0x0005, 45, 42, false}, {0x0001, 45, 43, false}, {0x5601, 46, 46, false},
+}
+
+// context holds the state index and the more probable symbol of an adaptive context.
+type context struct {
+	index uint8
+	mps   uint8
+}
+
+// arithDecoder is the MQ arithmetic decoder, see Annex E.3
+type arithDecoder struct {
+	data []byte
+	bp   int
+	a, c uint32
+	ct   int
+}
+
+func newArithDecoder(data []byte) *arithDecoder {
+	d := &arithDecoder{data: data}
+	d.c = d.byteAt(0) << 16
+	d.byteIn()
+	d.c <<= 7
+	d.ct -= 7
+	d.a = 0x8000
+	return d
+}
+
+// byteAt returns the byte at i. Bytes beyond the end of data are 0xFF.
+func (d *arithDecoder) byteAt(i int) uint32 {
+	if i < len(d.data) {
+		return uint32(d.data[i])
+	}
+	return 0xFF
+}
+
+func (d *arithDecoder) byteIn() {
+	if d.byteAt(d.bp) == 0xFF {
+		if d.byteAt(d.bp+1) > 0x8F {
+			d.c += 0xFF00
+			d.ct = 8
+		} else {
+			d.bp++
+			d.c += d.byteAt(d.bp) << 9
+			d.ct = 7
+		}
+		return
+	}
+	d.bp++
+	d.c += d.byteAt(d.bp) << 8
+	d.ct = 8
+}
+
+// decode returns the next decision using the context cx.
+func (d *arithDecoder) decode(cx *context) int {
+	q := qeTable[cx.index]
+	d.a -= q.qe
+	var bit int
+	if d.c>>16 < q.qe {
+		// LPS exchange
+		if d.a < q.qe {
+			bit = int(cx.mps)
+			cx.index = q.nmps
+		} else {
+			bit = int(1 - cx.mps)
+			if q.switchMPS {
+				cx.mps = 1 - cx.mps
+			}
+			cx.index = q.nlps
+		}
+		d.a = q.qe
+	} else {
+		d.c -= q.qe << 16
+		if d.a&0x8000 != 0 {
+			return int(cx.mps)
+		}
+		// MPS exchange
+		if d.a < q.qe {
+			bit = int(1 - cx.mps)
+			if q.switchMPS {
+				cx.mps = 1 - cx.mps
+			}
+			cx.index = q.nlps
+		} else {
+			bit = int(cx.mps)
+			cx.index = q.nmps
+		}
+	}
+	for d.a&0x8000 == 0 {
+		if d.ct == 0 {
+			d.byteIn()
+		}
+		d.a <<= 1
+		d.c <<= 1
+		d.ct--
+	}
+	return bit
+}
+
+// intContexts are the contexts of an integer arithmetic decoding procedure like IADH or IADW.
+type intContexts [512]context
+
+// decodeInt decodes a signed integer, see A.2
+// ok is false for the out-of-band value OOB.
+func (d *arithDecoder) decodeInt(cx *intContexts) (v int, ok bool) {
+	prev := 1
+	bit := func() int {
+		b := d.decode(&cx[prev])
+		if prev < 256 {
+			prev = prev<<1 | b
+		} else {
+			prev = (prev<<1|b)&511 | 256
+		}
+		return b
+	}
+
+	s := bit()
+
+	n, offset := 2, 0
+	switch {
+	case bit() == 0:
+	case bit() == 0:
+		n, offset = 4, 4
+	case bit() == 0:
+		n, offset = 6, 20
+	case bit() == 0:
+		n, offset = 8, 84
+	case bit() == 0:
+		n, offset = 12, 340
+	default:
+		n, offset = 32, 4436
+	}
+
+	for i := 0; i < n; i++ {
+		v = v<<1 | bit()
+	}
+	v += offset
+
+	if s == 1 {
+		if v == 0 {
+			return 0, false
+		}
+		v = -v
+	}
+
+	return v, true
+}
+
+// decodeID decodes a symbol ID using the IAID decoding procedure with codeLen bits, see A.3
+func (d *arithDecoder) decodeID(cx []context, codeLen int) int {
+	prev := 1
+	for i := 0; i < codeLen; i++ {
+		prev = prev<<1 | d.decode(&cx[prev])
+	}
+	return prev - 1<<uint(codeLen)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+// Combination operators, see 7.4.1.5
+const (
+	opOr = iota
+	opAnd
+	opXor
+	opXnor
+	opReplace
+)
+
+// bitmap is a bilevel image using one byte per pixel.
+type bitmap struct {
+	w, h int
+	pix  []byte
+}
+
+func newBitmap(w, h int) (*bitmap, error) {
+	if w < 0 || h < 0 || w > 0 && h > maxPixels/w {
+		return nil, errCorrupt
+	}
+	return &bitmap{w: w, h: h, pix: make([]byte, w*h)}, nil
+}
+
+// get returns the pixel at x,y. Pixels outside of b are 0.
+func (b *bitmap) get(x, y int) int {
+	if x < 0 || y < 0 || x >= b.w || y >= b.h {
+		return 0
+	}
+	return int(b.pix[y*b.w+x])
+}
+
+func (b *bitmap) fill(v byte) {
+	for i := range b.pix {
+		b.pix[i] = v
+	}
+}
+
+// grow extends b to h rows.
+func (b *bitmap) grow(h int, v byte) error {
+	if h <= b.h {
+		return nil
+	}
+	if b.w > 0 && h > maxPixels/b.w {
+		return errCorrupt
+	}
+	pix := make([]byte, b.w*h)
+	copy(pix, b.pix)
+	for i := len(b.pix); i < len(pix); i++ {
+		pix[i] = v
+	}
+	b.pix, b.h = pix, h
+	return nil
+}
+
+// sub returns a copy of the area of b with origin x,y and size w,h.
+func (b *bitmap) sub(x, y, w, h int) (*bitmap, error) {
+	s, err := newBitmap(w, h)
+	if err != nil {
+		return nil, err
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			s.pix[j*w+i] = byte(b.get(x+i, y+j))
+		}
+	}
+	return s, nil
+}
+
+// compose combines src into b at x,y using the combination operator op.
+func (b *bitmap) compose(src *bitmap, x, y, op int) {
+	for j := 0; j < src.h; j++ {
+		yy := y + j
+		if yy < 0 || yy >= b.h {
+			continue
+		}
+		for i := 0; i < src.w; i++ {
+			xx := x + i
+			if xx < 0 || xx >= b.w {
+				continue
+			}
+			s, d := src.pix[j*src.w+i], &b.pix[yy*b.w+xx]
+			switch op {
+			case opOr:
+				*d |= s
+			case opAnd:
+				*d &= s
+			case opXor:
+				*d ^= s
+			case opXnor:
+				*d = 1 ^ *d ^ s
+			default:
+				*d = s
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/image/ccitt"
+)
+
+// Context sizes of the generic region templates 0-3 and the generic refinement region templates 0-1.
+var (
+	genericContexts    = [4]int{1 << 16, 1 << 13, 1 << 10, 1 << 10}
+	refinementContexts = [2]int{1 << 13, 1 << 10}
+)
+
+// Contexts used for decoding SLTP, see 6.2.5.7
+var sltpContexts = [4]int{0x9B25, 0x0795, 0x00E5, 0x0195}
+
+// genericParams are the parameters of the generic region decoding procedure, see Table 2
+type genericParams struct {
+	w, h     int
+	template int
+	tpgdon   bool
+	skip     *bitmap
+	at       [4][2]int
+}
+
+// decodeGeneric runs the generic region decoding procedure, see 6.2
+// Arithmetic decoding uses the contexts cx which may be shared between several bitmaps.
+func decodeGeneric(d *arithDecoder, p *genericParams, cx []context) (*bitmap, error) {
+	b, err := newBitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+
+	ltp := 0
+	for y := 0; y < p.h; y++ {
+		if p.tpgdon {
+			ltp ^= d.decode(&cx[sltpContexts[p.template]])
+			if ltp == 1 {
+				if y > 0 {
+					copy(b.pix[y*b.w:(y+1)*b.w], b.pix[(y-1)*b.w:y*b.w])
+				}
+				continue
+			}
+		}
+		for x := 0; x < p.w; x++ {
+			if p.skip != nil && p.skip.get(x, y) == 1 {
+				continue
+			}
+			b.pix[y*b.w+x] = byte(d.decode(&cx[genericContext(b, x, y, p)]))
+		}
+	}
+
+	return b, nil
+}
+
+// genericContext returns the context of pixel x,y formed by the template pixels, see 6.2.5.3
+func genericContext(b *bitmap, x, y int, p *genericParams) int {
+	at := &p.at
+	switch p.template {
+	case 0:
+		return b.get(x-1, y) | b.get(x-2, y)<<1 | b.get(x-3, y)<<2 | b.get(x-4, y)<<3 |
+			b.get(x+at[0][0], y+at[0][1])<<4 |
+			b.get(x+2, y-1)<<5 | b.get(x+1, y-1)<<6 | b.get(x, y-1)<<7 | b.get(x-1, y-1)<<8 | b.get(x-2, y-1)<<9 |
+			b.get(x+at[1][0], y+at[1][1])<<10 | b.get(x+at[2][0], y+at[2][1])<<11 |
+			b.get(x+1, y-2)<<12 | b.get(x, y-2)<<13 | b.get(x-1, y-2)<<14 |
+			b.get(x+at[3][0], y+at[3][1])<<15
+	case 1:
+		return b.get(x-1, y) | b.get(x-2, y)<<1 | b.get(x-3, y)<<2 |
+			b.get(x+at[0][0], y+at[0][1])<<3 |
+			b.get(x+2, y-1)<<4 | b.get(x+1, y-1)<<5 | b.get(x, y-1)<<6 | b.get(x-1, y-1)<<7 | b.get(x-2, y-1)<<8 |
+			b.get(x+2, y-2)<<9 | b.get(x+1, y-2)<<10 | b.get(x, y-2)<<11 | b.get(x-1, y-2)<<12
+	case 2:
+		return b.get(x-1, y) | b.get(x-2, y)<<1 |
+			b.get(x+at[0][0], y+at[0][1])<<2 |
+			b.get(x+1, y-1)<<3 | b.get(x, y-1)<<4 | b.get(x-1, y-1)<<5 | b.get(x-2, y-1)<<6 |
+			b.get(x+1, y-2)<<7 | b.get(x, y-2)<<8 | b.get(x-1, y-2)<<9
+	}
+	return b.get(x-1, y) | b.get(x-2, y)<<1 | b.get(x-3, y)<<2 | b.get(x-4, y)<<3 |
+		b.get(x+at[0][0], y+at[0][1])<<4 |
+		b.get(x+1, y-1)<<5 | b.get(x, y-1)<<6 | b.get(x-1, y-1)<<7 | b.get(x-2, y-1)<<8 | b.get(x-3, y-1)<<9
+}
+
+// decodeMMR decodes a w x h bitmap coded with MMR (CCITT Group 4), see 6.2.6
+func decodeMMR(data []byte, w, h int) (*bitmap, error) {
+	b, err := newBitmap(w, h)
+	if err != nil || w == 0 || h == 0 {
+		return b, err
+	}
+
+	stride := (w + 7) / 8
+	bb := make([]byte, stride*h)
+	r := ccitt.NewReader(bytes.NewReader(data), ccitt.MSB, ccitt.Group4, w, h, &ccitt.Options{Invert: true})
+	if _, err := io.ReadFull(r, bb); err != nil {
+		return nil, errCorrupt
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			b.pix[y*w+x] = bb[y*stride+x>>3] >> (7 - uint(x&7)) & 1
+		}
+	}
+
+	return b, nil
+}
+
+// refinementParams are the parameters of the generic refinement region decoding procedure, see Table 6
+type refinementParams struct {
+	w, h     int
+	template int
+	ref      *bitmap
+	dx, dy   int
+	tpgron   bool
+	at       [2][2]int
+}
+
+// decodeRefinement runs the generic refinement region decoding procedure, see 6.3
+func decodeRefinement(d *arithDecoder, p *refinementParams, cx []context) (*bitmap, error) {
+	b, err := newBitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+
+	// The context for SLTP has only the reference pixel corresponding to the current pixel set, see 6.3.5.6
+	sltp := 0x100
+	if p.template == 1 {
+		sltp = 0x080
+	}
+
+	ltp := 0
+	for y := 0; y < p.h; y++ {
+		if p.tpgron {
+			ltp ^= d.decode(&cx[sltp])
+		}
+		for x := 0; x < p.w; x++ {
+			if ltp == 1 {
+				if v, ok := typicalPrediction(p.ref, x-p.dx, y-p.dy); ok {
+					b.pix[y*b.w+x] = byte(v)
+					continue
+				}
+			}
+			b.pix[y*b.w+x] = byte(d.decode(&cx[refinementContext(b, x, y, p)]))
+		}
+	}
+
+	return b, nil
+}
+
+// typicalPrediction returns the value of the 3x3 neighbourhood of the reference pixel x,y if all its pixels share this value.
+func typicalPrediction(ref *bitmap, x, y int) (int, bool) {
+	v := ref.get(x, y)
+	for j := -1; j <= 1; j++ {
+		for i := -1; i <= 1; i++ {
+			if ref.get(x+i, y+j) != v {
+				return 0, false
+			}
+		}
+	}
+	return v, true
+}
+
+// refinementContext returns the context of pixel x,y formed by the template pixels
+// of the bitmap being decoded and the reference bitmap, see 6.3.5.3
+func refinementContext(b *bitmap, x, y int, p *refinementParams) int {
+	r, rx, ry := p.ref, x-p.dx, y-p.dy
+	if p.template == 0 {
+		return b.get(x-1, y) | b.get(x+1, y-1)<<1 | b.get(x, y-1)<<2 |
+			b.get(x+p.at[0][0], y+p.at[0][1])<<3 |
+			r.get(rx+1, ry+1)<<4 | r.get(rx, ry+1)<<5 | r.get(rx-1, ry+1)<<6 |
+			r.get(rx+1, ry)<<7 | r.get(rx, ry)<<8 | r.get(rx-1, ry)<<9 |
+			r.get(rx+1, ry-1)<<10 | r.get(rx, ry-1)<<11 |
+			r.get(rx+p.at[1][0], ry+p.at[1][1])<<12
+	}
+	return b.get(x-1, y) | b.get(x+1, y-1)<<1 | b.get(x, y-1)<<2 | b.get(x-1, y-1)<<3 |
+		r.get(rx+1, ry+1)<<4 | r.get(rx, ry+1)<<5 |
+		r.get(rx+1, ry)<<6 | r.get(rx, ry)<<7 | r.get(rx-1, ry)<<8 |
+		r.get(rx, ry-1)<<9
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+// decodePatterns runs the pattern dictionary decoding procedure, see 6.7
+func decodePatterns(data []byte, mmr bool, template, pw, ph, grayMax int) ([]*bitmap, error) {
+	if pw == 0 || ph == 0 || grayMax < 0 || grayMax >= maxPixels/pw {
+		return nil, errCorrupt
+	}
+
+	w := (grayMax + 1) * pw
+
+	var (
+		b   *bitmap
+		err error
+	)
+	if mmr {
+		b, err = decodeMMR(data, w, ph)
+	} else {
+		p := &genericParams{w: w, h: ph, template: template, at: [4][2]int{{-pw, 0}, {-3, -1}, {2, -2}, {-2, -2}}}
+		b, err = decodeGeneric(newArithDecoder(data), p, make([]context, genericContexts[template]))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pp := make([]*bitmap, grayMax+1)
+	for i := range pp {
+		if pp[i], err = b.sub(i*pw, 0, pw, ph); err != nil {
+			return nil, err
+		}
+	}
+
+	return pp, nil
+}
+
+// halftoneParams are the parameters of the halftone region decoding procedure, see Table 20
+type halftoneParams struct {
+	w, h       int
+	mmr        bool
+	template   int
+	enableSkip bool
+	combOp     int
+	defPixel   byte
+	gw, gh     int
+	gx, gy     int
+	rx, ry     int
+	patterns   []*bitmap
+}
+
+// gridPosition returns the location of the pattern at grid position m,n, see 6.6.5.2
+func (p *halftoneParams) gridPosition(m, n int) (int, int) {
+	return (p.gx + m*p.ry + n*p.rx) >> 8, (p.gy + m*p.rx - n*p.ry) >> 8
+}
+
+// decodeHalftone runs the halftone region decoding procedure, see 6.6
+func decodeHalftone(data []byte, p *halftoneParams) (*bitmap, error) {
+	if len(p.patterns) == 0 {
+		return nil, errCorrupt
+	}
+
+	b, err := newBitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+	if p.defPixel == 1 {
+		b.fill(1)
+	}
+
+	pw, ph := p.patterns[0].w, p.patterns[0].h
+
+	var skip *bitmap
+	if p.enableSkip {
+		if skip, err = newBitmap(p.gw, p.gh); err != nil {
+			return nil, err
+		}
+		for m := 0; m < p.gh; m++ {
+			for n := 0; n < p.gw; n++ {
+				x, y := p.gridPosition(m, n)
+				if x+pw <= 0 || x >= p.w || y+ph <= 0 || y >= p.h {
+					skip.pix[m*p.gw+n] = 1
+				}
+			}
+		}
+	}
+
+	bpp := ceilLog2(len(p.patterns))
+	vals, err := decodeGrayScale(data, p, bpp, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	for m := 0; m < p.gh; m++ {
+		for n := 0; n < p.gw; n++ {
+			x, y := p.gridPosition(m, n)
+			i := min(vals[m*p.gw+n], len(p.patterns)-1)
+			b.compose(p.patterns[i], x, y, p.combOp)
+		}
+	}
+
+	return b, nil
+}
+
+// decodeGrayScale runs the gray-scale image decoding procedure for bpp bitplanes, see Annex C.5
+func decodeGrayScale(data []byte, p *halftoneParams, bpp int, skip *bitmap) ([]int, error) {
+	if p.mmr {
+		// The end of an MMR coded bitplane is only known after decoding it.
+		return nil, errUnsupported
+	}
+
+	at := [4][2]int{{3, -1}, {-3, -1}, {2, -2}, {-2, -2}}
+	if p.template > 1 {
+		at[0][0] = 2
+	}
+
+	gp := &genericParams{w: p.gw, h: p.gh, template: p.template, skip: skip, at: at}
+	d := newArithDecoder(data)
+	cx := make([]context, genericContexts[p.template])
+
+	vals := make([]int, p.gw*p.gh)
+	var prev *bitmap
+	for j := bpp - 1; j >= 0; j-- {
+		plane, err := decodeGeneric(d, gp, cx)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil {
+			// Gray code decoding.
+			for i := range plane.pix {
+				plane.pix[i] ^= prev.pix[i]
+			}
+		}
+		for i, v := range plane.pix {
+			vals[i] |= int(v) << uint(j)
+		}
+		prev = plane
+	}
+
+	return vals, nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+// bitReader reads MSB first bit sequences of Huffman coded data.
+type bitReader struct {
+	data []byte
+	pos  int // byte position
+	bit  int // bit position within the current byte
+}
+
+func (r *bitReader) readBit() (int, error) {
+	if r.pos >= len(r.data) {
+		return 0, errCorrupt
+	}
+	b := int(r.data[r.pos]>>(7-uint(r.bit))) & 1
+	if r.bit++; r.bit == 8 {
+		r.bit = 0
+		r.pos++
+	}
+	return b, nil
+}
+
+func (r *bitReader) readBits(n int) (int, error) {
+	v := 0
+	for i := 0; i < n; i++ {
+		b, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | b
+	}
+	return v, nil
+}
+
+// align skips the remaining bits of the current byte.
+func (r *bitReader) align() {
+	if r.bit > 0 {
+		r.bit = 0
+		r.pos++
+	}
+}
+
+// Kinds of Huffman table lines.
+const (
+	lineNormal = iota
+	lineLower  // lower range line
+	lineUpper  // upper range line
+	lineOOB    // out-of-band line
+)
+
+// huffLine is a line of a Huffman table, see B.2
+type huffLine struct {
+	prefLen, rangeLen, rangeLow int
+	kind                        int
+}
+
+// huffTable is a Huffman table with assigned prefix codes.
+type huffTable struct {
+	lines []huffLine
+	codes []int
+}
+
+// newHuffTable assigns the prefix codes of lines, see B.3
+func newHuffTable(lines []huffLine) *huffTable {
+	lenMax := 0
+	for _, l := range lines {
+		lenMax = max(lenMax, l.prefLen)
+	}
+
+	lenCount := make([]int, lenMax+1)
+	for _, l := range lines {
+		lenCount[l.prefLen]++
+	}
+	lenCount[0] = 0
+
+	t := &huffTable{lines: lines, codes: make([]int, len(lines))}
+	firstCode := 0
+	for curLen := 1; curLen <= lenMax; curLen++ {
+		firstCode = (firstCode + lenCount[curLen-1]) << 1
+		curCode := firstCode
+		for i, l := range lines {
+			if l.prefLen == curLen {
+				t.codes[i] = curCode
+				curCode++
+			}
+		}
+	}
+
+	return t
+}
+
+// decode decodes a value using t, see B.4
+// ok is false for the out-of-band value OOB.
+func (t *huffTable) decode(r *bitReader) (v int, ok bool, err error) {
+	code := 0
+	for n := 1; n <= 32; n++ {
+		b, err := r.readBit()
+		if err != nil {
+			return 0, false, err
+		}
+		code = code<<1 | b
+		for i, l := range t.lines {
+			if l.prefLen != n || t.codes[i] != code {
+				continue
+			}
+			switch l.kind {
+			case lineOOB:
+				return 0, false, nil
+			case lineLower:
+				v, err := r.readBits(32)
+				return l.rangeLow - v, true, err
+			}
+			v, err := r.readBits(l.rangeLen)
+			return l.rangeLow + v, true, err
+		}
+	}
+	return 0, false, errCorrupt
+}
+
+// parseHuffTable parses the custom Huffman table of a tables segment, see B.2
+func parseHuffTable(data []byte) (*huffTable, error) {
+	if len(data) < 9 {
+		return nil, errCorrupt
+	}
+	flags := data[0]
+	oob := flags&1 == 1
+	ps := int(flags>>1&7) + 1
+	rs := int(flags>>4&7) + 1
+	low := int(int32(be32(data[1:])))
+	high := int(int32(be32(data[5:])))
+
+	r := &bitReader{data: data[9:]}
+	var lines []huffLine
+	for cur := low; cur < high; {
+		prefLen, err := r.readBits(ps)
+		if err != nil {
+			return nil, err
+		}
+		rangeLen, err := r.readBits(rs)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, huffLine{prefLen, rangeLen, cur, lineNormal})
+		cur += 1 << uint(rangeLen)
+	}
+
+	prefLen, err := r.readBits(ps)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, huffLine{prefLen, 32, low - 1, lineLower})
+
+	if prefLen, err = r.readBits(ps); err != nil {
+		return nil, err
+	}
+	lines = append(lines, huffLine{prefLen, 32, high, lineUpper})
+
+	if oob {
+		if prefLen, err = r.readBits(ps); err != nil {
+			return nil, err
+		}
+		lines = append(lines, huffLine{prefLen, 0, 0, lineOOB})
+	}
+
+	return newHuffTable(lines), nil
+}
+
+// Standard Huffman tables B.1 - B.15, see Annex B.5
+var standardTables = [15][]huffLine{
+	// B.1
+	{{1, 4, 0, 0}, {2, 8, 16, 0}, {3, 16, 272, 0}, {3, 32, 65808, lineUpper}},
+	// B.2
+	{{1, 0, 0, 0}, {2, 0, 1, 0}, {3, 0, 2, 0}, {4, 3, 3, 0}, {5, 6, 11, 0},
+		{6, 32, 75, lineUpper}, {6, 0, 0, lineOOB}},
+	// B.3
+	{{8, 8, -256, 0}, {1, 0, 0, 0}, {2, 0, 1, 0}, {3, 0, 2, 0}, {4, 3, 3, 0}, {5, 6, 11, 0},
+		{8, 32, -257, lineLower}, {7, 32, 75, lineUpper}, {6, 0, 0, lineOOB}},
+	// B.4
+	{{1, 0, 1, 0}, {2, 0, 2, 0}, {3, 0, 3, 0}, {4, 3, 4, 0}, {5, 6, 12, 0}, {5, 32, 76, lineUpper}},
+	// B.5
+	{{7, 8, -255, 0}, {1, 0, 1, 0}, {2, 0, 2, 0}, {3, 0, 3, 0}, {4, 3, 4, 0}, {5, 6, 12, 0},
+		{7, 32, -256, lineLower}, {6, 32, 76, lineUpper}},
+	// B.6
+	{{5, 10, -2048, 0}, {4, 9, -1024, 0}, {4, 8, -512, 0}, {4, 7, -256, 0}, {5, 6, -128, 0},
+		{5, 5, -64, 0}, {4, 5, -32, 0}, {2, 7, 0, 0}, {3, 7, 128, 0}, {3, 8, 256, 0},
+		{4, 9, 512, 0}, {4, 10, 1024, 0}, {6, 32, -2049, lineLower}, {6, 32, 2048, lineUpper}},
+	// B.7
+	{{4, 9, -1024, 0}, {3, 8, -512, 0}, {4, 7, -256, 0}, {5, 6, -128, 0}, {5, 5, -64, 0},
+		{4, 5, -32, 0}, {4, 5, 0, 0}, {5, 5, 32, 0}, {5, 6, 64, 0}, {4, 7, 128, 0},
+		{3, 8, 256, 0}, {3, 9, 512, 0}, {3, 10, 1024, 0}, {5, 32, -1025, lineLower}, {5, 32, 2048, lineUpper}},
+	// B.8
+	{{8, 3, -15, 0}, {9, 1, -7, 0}, {8, 1, -5, 0}, {9, 0, -3, 0}, {7, 0, -2, 0}, {4, 0, -1, 0},
+		{2, 1, 0, 0}, {5, 0, 2, 0}, {6, 0, 3, 0}, {3, 4, 4, 0}, {6, 1, 20, 0}, {4, 4, 22, 0},
+		{4, 5, 38, 0}, {5, 6, 70, 0}, {5, 7, 134, 0}, {6, 7, 262, 0}, {7, 8, 390, 0}, {6, 10, 646, 0},
+		{9, 32, -16, lineLower}, {9, 32, 1670, lineUpper}, {2, 0, 0, lineOOB}},
+	// B.9
+	{{8, 4, -31, 0}, {9, 2, -15, 0}, {8, 2, -11, 0}, {9, 1, -7, 0}, {7, 1, -5, 0}, {4, 1, -3, 0},
+		{3, 1, -1, 0}, {3, 1, 1, 0}, {5, 1, 3, 0}, {6, 1, 5, 0}, {3, 5, 7, 0}, {6, 2, 39, 0},
+		{4, 5, 43, 0}, {4, 6, 75, 0}, {5, 7, 139, 0}, {5, 8, 267, 0}, {6, 8, 523, 0}, {7, 9, 779, 0},
+		{6, 11, 1291, 0}, {9, 32, -32, lineLower}, {9, 32, 3339, lineUpper}, {2, 0, 0, lineOOB}},
+	// B.10
+	{{7, 4, -21, 0}, {8, 0, -5, 0}, {7, 0, -4, 0}, {5, 0, -3, 0}, {2, 2, -2, 0}, {5, 0, 2, 0},
+		{6, 0, 3, 0}, {7, 0, 4, 0}, {8, 0, 5, 0}, {2, 6, 6, 0}, {5, 5, 70, 0}, {6, 5, 102, 0},
+		{6, 6, 134, 0}, {6, 7, 198, 0}, {6, 8, 326, 0}, {6, 9, 582, 0}, {6, 10, 1094, 0}, {7, 11, 2118, 0},
+		{8, 32, -22, lineLower}, {8, 32, 4166, lineUpper}, {2, 0, 0, lineOOB}},
+	// B.11
+	{{1, 0, 1, 0}, {2, 1, 2, 0}, {4, 0, 4, 0}, {4, 1, 5, 0}, {5, 1, 7, 0}, {5, 2, 9, 0},
+		{6, 2, 13, 0}, {7, 2, 17, 0}, {7, 3, 21, 0}, {7, 4, 29, 0}, {7, 5, 45, 0}, {7, 6, 77, 0},
+		{7, 32, 141, lineUpper}},
+	// B.12
+	{{1, 0, 1, 0}, {2, 0, 2, 0}, {3, 1, 3, 0}, {5, 0, 5, 0}, {5, 1, 6, 0}, {6, 1, 8, 0},
+		{7, 0, 10, 0}, {7, 1, 11, 0}, {7, 2, 13, 0}, {7, 3, 17, 0}, {7, 4, 25, 0}, {8, 5, 41, 0},
+		{8, 32, 73, lineUpper}},
+	// B.13
+	{{1, 0, 1, 0}, {3, 0, 2, 0}, {4, 0, 3, 0}, {5, 0, 4, 0}, {4, 1, 5, 0}, {3, 3, 7, 0},
+		{6, 1, 15, 0}, {6, 2, 17, 0}, {6, 3, 21, 0}, {6, 4, 29, 0}, {6, 5, 45, 0}, {7, 6, 77, 0},
+		{7, 32, 141, lineUpper}},
+	// B.14
+	{{3, 0, -2, 0}, {3, 0, -1, 0}, {1, 0, 0, 0}, {3, 0, 1, 0}, {3, 0, 2, 0}},
+	// B.15
+	{{7, 4, -24, 0}, {6, 2, -8, 0}, {5, 1, -4, 0}, {4, 0, -2, 0}, {3, 0, -1, 0}, {1, 0, 0, 0},
+		{3, 0, 1, 0}, {4, 0, 2, 0}, {5, 1, 3, 0}, {6, 2, 5, 0}, {7, 4, 9, 0},
+		{7, 32, -25, lineLower}, {7, 32, 25, lineUpper}},
+}
+
+var standardHuffTables [15]*huffTable
+
+func init() {
+	for i, lines := range standardTables {
+		standardHuffTables[i] = newHuffTable(lines)
+	}
+}
+
+// standardTable returns the standard Huffman table B.n
+func standardTable(n int) *huffTable {
+	return standardHuffTables[n-1]
+}
+
+// tableSelector hands out the custom tables of referred-to tables segments in order of use.
+type tableSelector struct {
+	custom []*huffTable
+}
+
+// next returns the standard table B.n for sel < len(n) or the next custom table.
+func (s *tableSelector) next(sel int, n ...int) (*huffTable, error) {
+	if sel < len(n) {
+		return standardTable(n[sel]), nil
+	}
+	if len(s.custom) == 0 {
+		return nil, errCorrupt
+	}
+	t := s.custom[0]
+	s.custom = s.custom[1:]
+	return t, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jbig2 implements a decoder for JBIG2 images as embedded by the PDF filter JBIG2Decode.
+//
+// Supported are generic regions (arithmetic and MMR coded), generic refinement regions,
+// symbol dictionaries, text regions, pattern dictionaries, halftone regions and custom Huffman tables.
+// See ITU-T T.88 | ISO/IEC 14492 and 7.4.7 in the PDF spec.
+package jbig2
+
+import (
+	"image"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	errCorrupt     = errors.New("pdfcpu: jbig2: corrupt data")
+	errUnsupported = errors.New("pdfcpu: jbig2: unsupported feature")
+)
+
+// maxPixels limits the size of any bitmap in order to guard against corrupt dimensions.
+const maxPixels = 1 << 28
+
+// Image is a decoded JBIG2 page.
+type Image struct {
+	Width, Height int
+	Pix           []byte // One byte per pixel, 1 for black and 0 for white.
+}
+
+// Bytes returns the rows of img packed into 1 bit samples padded to full bytes,
+// where 0 represents black as expected by a PDF image using JBIG2Decode.
+func (img *Image) Bytes() []byte {
+	stride := (img.Width + 7) / 8
+	bb := make([]byte, stride*img.Height)
+	for i := range bb {
+		bb[i] = 0xFF
+	}
+	for y := 0; y < img.Height; y++ {
+		row := img.Pix[y*img.Width : (y+1)*img.Width]
+		for x, v := range row {
+			if v == 1 {
+				bb[y*stride+x>>3] &^= 0x80 >> uint(x&7)
+			}
+		}
+	}
+	return bb
+}
+
+// Image returns img as a grayscale image.
+func (img *Image) Image() image.Image {
+	g := image.NewGray(image.Rect(0, 0, img.Width, img.Height))
+	for i, v := range img.Pix {
+		if v == 0 {
+			g.Pix[i] = 0xFF
+		}
+	}
+	return g
+}
+
+// Decode decodes the first page of the JBIG2 embedded stream data.
+// globals contains the optional global segments referenced by the PDF stream parameter JBIG2Globals.
+func Decode(data, globals []byte) (*Image, error) {
+	d := &decoder{segments: map[int]*segment{}}
+
+	if len(globals) > 0 {
+		ss, err := parseSegments(globals)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.process(ss); err != nil {
+			return nil, err
+		}
+	}
+
+	ss, err := parseSegments(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.process(ss); err != nil {
+		return nil, err
+	}
+
+	if d.page == nil {
+		return nil, errors.New("pdfcpu: jbig2: missing page information")
+	}
+
+	return &Image{Width: d.page.w, Height: d.page.h, Pix: d.page.pix}, nil
+}
@@ -0,0 +1,582 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// arithEncoder is the MQ arithmetic encoder, see Annex E.2
+type arithEncoder struct {
+	a, c uint32
+	ct   int
+	out  []byte // out[0] is the byte preceding the coded data.
+}
+
+func newArithEncoder() *arithEncoder {
+	return &arithEncoder{a: 0x8000, ct: 12, out: []byte{0}}
+}
+
+func (e *arithEncoder) byteOut() {
+	b := &e.out[len(e.out)-1]
+	if *b != 0xFF && e.c >= 0x8000000 {
+		*b++
+		e.c &= 0x7FFFFFF
+	}
+	if *b == 0xFF {
+		e.out = append(e.out, byte(e.c>>20))
+		e.c &= 0xFFFFF
+		e.ct = 7
+		return
+	}
+	e.out = append(e.out, byte(e.c>>19))
+	e.c &= 0x7FFFF
+	e.ct = 8
+}
+
+func (e *arithEncoder) renorm() {
+	for {
+		e.a <<= 1
+		e.c <<= 1
+		e.ct--
+		if e.ct == 0 {
+			e.byteOut()
+		}
+		if e.a&0x8000 != 0 {
+			return
+		}
+	}
+}
+
+func (e *arithEncoder) encode(cx *context, d int) {
+	q := qeTable[cx.index]
+	e.a -= q.qe
+	if d == int(cx.mps) {
+		if e.a&0x8000 != 0 {
+			e.c += q.qe
+			return
+		}
+		if e.a < q.qe {
+			e.a = q.qe
+		} else {
+			e.c += q.qe
+		}
+		cx.index = q.nmps
+	} else {
+		if e.a < q.qe {
+			e.c += q.qe
+		} else {
+			e.a = q.qe
+		}
+		if q.switchMPS {
+			cx.mps = 1 - cx.mps
+		}
+		cx.index = q.nlps
+	}
+	e.renorm()
+}
+
+func (e *arithEncoder) flush() []byte {
+	tempc := e.c + e.a
+	e.c |= 0xFFFF
+	if e.c >= tempc {
+		e.c -= 0x8000
+	}
+	e.c <<= uint(e.ct)
+	e.byteOut()
+	e.c <<= uint(e.ct)
+	e.byteOut()
+	out := e.out[1:]
+	if out[len(out)-1] != 0xFF {
+		out = append(out, 0xFF)
+	}
+	return append(out, 0xAC)
+}
+
+// encodeInt is the inverse of arithDecoder.decodeInt.
+func (e *arithEncoder) encodeInt(cx *intContexts, v int, oob bool) {
+	prev := 1
+	bit := func(b int) {
+		e.encode(&cx[prev], b)
+		if prev < 256 {
+			prev = prev<<1 | b
+		} else {
+			prev = (prev<<1|b)&511 | 256
+		}
+	}
+
+	if oob {
+		for _, b := range []int{1, 0, 0, 0} {
+			bit(b)
+		}
+		return
+	}
+
+	s := 0
+	if v < 0 {
+		s, v = 1, -v
+	}
+	bit(s)
+
+	var prefix []int
+	n, offset := 2, 0
+	switch {
+	case v < 4:
+		prefix = []int{0}
+	case v < 20:
+		prefix, n, offset = []int{1, 0}, 4, 4
+	case v < 84:
+		prefix, n, offset = []int{1, 1, 0}, 6, 20
+	case v < 340:
+		prefix, n, offset = []int{1, 1, 1, 0}, 8, 84
+	case v < 4436:
+		prefix, n, offset = []int{1, 1, 1, 1, 0}, 12, 340
+	default:
+		prefix, n, offset = []int{1, 1, 1, 1, 1}, 32, 4436
+	}
+	for _, b := range prefix {
+		bit(b)
+	}
+	for i := n - 1; i >= 0; i-- {
+		bit((v - offset) >> uint(i) & 1)
+	}
+}
+
+func (e *arithEncoder) encodeID(cx []context, codeLen, id int) {
+	prev := 1
+	for i := codeLen - 1; i >= 0; i-- {
+		b := id >> uint(i) & 1
+		e.encode(&cx[prev], b)
+		prev = prev<<1 | b
+	}
+}
+
+func rowEquals(b *bitmap, y0, y1 int) bool {
+	for x := 0; x < b.w; x++ {
+		if b.get(x, y0) != b.get(x, y1) {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeGeneric(e *arithEncoder, b *bitmap, p *genericParams, cx []context) {
+	ltp := 0
+	for y := 0; y < b.h; y++ {
+		if p.tpgdon {
+			typical := 0
+			if rowEquals(b, y, y-1) {
+				typical = 1
+			}
+			e.encode(&cx[sltpContexts[p.template]], ltp^typical)
+			if ltp = typical; ltp == 1 {
+				continue
+			}
+		}
+		for x := 0; x < b.w; x++ {
+			e.encode(&cx[genericContext(b, x, y, p)], b.get(x, y))
+		}
+	}
+}
+
+func encodeRefinement(e *arithEncoder, b *bitmap, p *refinementParams, cx []context) {
+	for y := 0; y < b.h; y++ {
+		for x := 0; x < b.w; x++ {
+			e.encode(&cx[refinementContext(b, x, y, p)], b.get(x, y))
+		}
+	}
+}
+
+// bitWriter writes MSB first bit sequences of Huffman coded data.
+type bitWriter struct {
+	out []byte
+	n   int
+}
+
+func (w *bitWriter) writeBits(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.out = append(w.out, 0)
+		}
+		w.out[len(w.out)-1] |= byte(v>>uint(i)&1) << (7 - uint(w.n%8))
+		w.n++
+	}
+}
+
+func (w *bitWriter) align() {
+	w.n = (w.n + 7) / 8 * 8
+}
+
+func (w *bitWriter) writeBytes(bb []byte) {
+	w.align()
+	w.out = append(w.out, bb...)
+	w.n += 8 * len(bb)
+}
+
+// encodeHuff is the inverse of huffTable.decode.
+func encodeHuff(t *testing.T, w *bitWriter, ht *huffTable, v int, oob bool) {
+	t.Helper()
+	for i, l := range ht.lines {
+		switch {
+		case oob && l.kind == lineOOB:
+			w.writeBits(ht.codes[i], l.prefLen)
+			return
+		case oob || l.kind == lineOOB:
+			continue
+		case l.kind == lineLower && v < l.rangeLow+1:
+			w.writeBits(ht.codes[i], l.prefLen)
+			w.writeBits(l.rangeLow-v, 32)
+			return
+		case l.kind == lineUpper && v >= l.rangeLow:
+			w.writeBits(ht.codes[i], l.prefLen)
+			w.writeBits(v-l.rangeLow, 32)
+			return
+		case l.kind == lineNormal && v >= l.rangeLow && v < l.rangeLow+1<<uint(l.rangeLen):
+			w.writeBits(ht.codes[i], l.prefLen)
+			w.writeBits(v-l.rangeLow, l.rangeLen)
+			return
+		}
+	}
+	t.Fatalf("value %d not encodable", v)
+}
+
+func encodeSegment(nr, typ int, refs []int, data []byte) []byte {
+	bb := []byte{0, 0, 0, byte(nr), byte(typ), byte(len(refs) << 5)}
+	for _, r := range refs {
+		bb = append(bb, byte(r))
+	}
+	bb = append(bb, 1)
+	bb = binary.BigEndian.AppendUint32(bb, uint32(len(data)))
+	return append(bb, data...)
+}
+
+func encodePageInfo(w, h int, defPixel byte) []byte {
+	bb := binary.BigEndian.AppendUint32(nil, uint32(w))
+	bb = binary.BigEndian.AppendUint32(bb, uint32(h))
+	bb = append(bb, make([]byte, 8)...)
+	return append(bb, defPixel<<2, 0, 0)
+}
+
+func encodeRegionInfo(w, h, x, y, op int) []byte {
+	bb := binary.BigEndian.AppendUint32(nil, uint32(w))
+	bb = binary.BigEndian.AppendUint32(bb, uint32(h))
+	bb = binary.BigEndian.AppendUint32(bb, uint32(x))
+	bb = binary.BigEndian.AppendUint32(bb, uint32(y))
+	return append(bb, byte(op))
+}
+
+// testBitmap returns a w x h bitmap with some structure and repeated rows.
+func testBitmap(w, h, seed int) *bitmap {
+	b, _ := newBitmap(w, h)
+	for y := 0; y < h; y++ {
+		yy := y
+		if y%5 == 3 {
+			yy = y - 1
+		}
+		for x := 0; x < w; x++ {
+			if (x*x+yy*seed+x*yy)%7 < 3 {
+				b.pix[y*w+x] = 1
+			}
+		}
+	}
+	return b
+}
+
+func checkPage(t *testing.T, img *Image, want *bitmap) {
+	t.Helper()
+	if img.Width != want.w || img.Height != want.h {
+		t.Fatalf("size: got %dx%d want %dx%d", img.Width, img.Height, want.w, want.h)
+	}
+	if !bytes.Equal(img.Pix, want.pix) {
+		t.Fatalf("page differs")
+	}
+}
+
+func TestGenericRegion(t *testing.T) {
+	src := testBitmap(37, 23, 3)
+	for template := 0; template < 4; template++ {
+		for _, tpgdon := range []bool{false, true} {
+			at := [4][2]int{{3, -1}, {-3, -1}, {2, -2}, {-2, -2}}
+			if template > 1 {
+				at[0] = [2]int{2, -1}
+			}
+			p := &genericParams{w: src.w, h: src.h, template: template, tpgdon: tpgdon, at: at}
+			e := newArithEncoder()
+			encodeGeneric(e, src, p, make([]context, genericContexts[template]))
+
+			flags := byte(template << 1)
+			if tpgdon {
+				flags |= 8
+			}
+			data := append(encodeRegionInfo(src.w, src.h, 0, 0, opOr), flags)
+			n := 1
+			if template == 0 {
+				n = 4
+			}
+			for i := 0; i < n; i++ {
+				data = append(data, byte(at[i][0]), byte(at[i][1]))
+			}
+			data = append(data, e.flush()...)
+
+			bb := append(encodeSegment(0, segPageInfo, nil, encodePageInfo(src.w, src.h, 0)), encodeSegment(1, segImmediateGeneric, nil, data)...)
+			img, err := Decode(bb, nil)
+			if err != nil {
+				t.Fatalf("template %d tpgdon %t: %v", template, tpgdon, err)
+			}
+			checkPage(t, img, src)
+		}
+	}
+}
+
+func TestRefinementRegion(t *testing.T) {
+	page := testBitmap(30, 20, 5)
+	target := testBitmap(10, 8, 5)
+	target.pix[12] ^= 1
+	for template := 0; template < 2; template++ {
+		// The page before refinement.
+		gp := &genericParams{w: page.w, h: page.h, template: 2, at: [4][2]int{{2, -1}}}
+		e := newArithEncoder()
+		encodeGeneric(e, page, gp, make([]context, genericContexts[2]))
+		gen := append(encodeRegionInfo(page.w, page.h, 0, 0, opOr), 2<<1, 2, 0xFF)
+		gen = append(gen, e.flush()...)
+
+		ref, _ := page.sub(4, 6, target.w, target.h)
+		rp := &refinementParams{w: target.w, h: target.h, template: template, ref: ref, at: [2][2]int{{-1, -1}, {-1, -1}}}
+		e = newArithEncoder()
+		encodeRefinement(e, target, rp, make([]context, refinementContexts[template]))
+		refine := append(encodeRegionInfo(target.w, target.h, 4, 6, opReplace), byte(template))
+		if template == 0 {
+			refine = append(refine, 0xFF, 0xFF, 0xFF, 0xFF)
+		}
+		refine = append(refine, e.flush()...)
+
+		bb := encodeSegment(0, segPageInfo, nil, encodePageInfo(page.w, page.h, 0))
+		bb = append(bb, encodeSegment(1, segImmediateGeneric, nil, gen)...)
+		bb = append(bb, encodeSegment(2, segImmediateRefinement, nil, refine)...)
+		img, err := Decode(bb, nil)
+		if err != nil {
+			t.Fatalf("template %d: %v", template, err)
+		}
+
+		want, _ := page.sub(0, 0, page.w, page.h)
+		want.compose(target, 4, 6, opReplace)
+		checkPage(t, img, want)
+	}
+}
+
+// symbols returns the test symbols C (6x4), A (5x7), B (3x7) and a refinement of A (6x7).
+func symbols() (c, a, b, a1 *bitmap) {
+	c = testBitmap(6, 4, 1)
+	a = testBitmap(5, 7, 2)
+	b = testBitmap(3, 7, 4)
+	a1, _ = a.sub(0, 0, 6, 7)
+	for y := 0; y < 7; y += 2 {
+		a1.pix[y*6+5] = 1
+	}
+	return c, a, b, a1
+}
+
+func TestSymbolTextRegion(t *testing.T) {
+	c, a, b, a1 := symbols()
+
+	// Symbol dictionary with height classes 4 (C) and 7 (A, B) in globals.
+	e := newArithEncoder()
+	cx := &symbolContexts{gb: make([]context, genericContexts[0])}
+	at := [4][2]int{{3, -1}, {-3, -1}, {2, -2}, {-2, -2}}
+	e.encodeInt(&cx.dh, 4, false)
+	e.encodeInt(&cx.dw, 6, false)
+	encodeGeneric(e, c, &genericParams{w: 6, h: 4, at: at}, cx.gb)
+	e.encodeInt(&cx.dw, 0, true)
+	e.encodeInt(&cx.dh, 3, false)
+	e.encodeInt(&cx.dw, 5, false)
+	encodeGeneric(e, a, &genericParams{w: 5, h: 7, at: at}, cx.gb)
+	e.encodeInt(&cx.dw, -2, false)
+	encodeGeneric(e, b, &genericParams{w: 3, h: 7, at: at}, cx.gb)
+	e.encodeInt(&cx.dw, 0, true)
+	e.encodeInt(&cx.ex, 0, false)
+	e.encodeInt(&cx.ex, 3, false)
+
+	sd := []byte{0, 0}
+	for _, p := range at {
+		sd = append(sd, byte(p[0]), byte(p[1]))
+	}
+	sd = binary.BigEndian.AppendUint32(sd, 3)
+	sd = binary.BigEndian.AppendUint32(sd, 3)
+	sd = append(sd, e.flush()...)
+	globals := encodeSegment(0, segSymbolDict, nil, sd)
+
+	// Text region using the symbol IDs C=0, A=1, B=2.
+	e = newArithEncoder()
+	tc := newTextContexts(2)
+	e.encodeInt(&tc.dt, 0, false)
+
+	e.encodeInt(&tc.dt, 2, false)
+	e.encodeInt(&tc.fs, 1, false)
+	e.encodeID(tc.id, 2, 1)
+	e.encodeInt(&tc.ri, 0, false)
+	e.encodeInt(&tc.ds, 3, false)
+	e.encodeID(tc.id, 2, 2)
+	e.encodeInt(&tc.ri, 0, false)
+	e.encodeInt(&tc.ds, 3, false)
+	e.encodeID(tc.id, 2, 0)
+	e.encodeInt(&tc.ri, 0, false)
+	e.encodeInt(&tc.ds, 0, true)
+
+	e.encodeInt(&tc.dt, 9, false)
+	e.encodeInt(&tc.fs, 2, false)
+	e.encodeID(tc.id, 2, 1)
+	e.encodeInt(&tc.ri, 1, false)
+	e.encodeInt(&tc.rdw, 1, false)
+	e.encodeInt(&tc.rdh, 0, false)
+	e.encodeInt(&tc.rdx, 0, false)
+	e.encodeInt(&tc.rdy, 0, false)
+	rp := &refinementParams{w: 6, h: 7, ref: a, at: [2][2]int{{-1, -1}, {-1, -1}}}
+	encodeRefinement(e, a1, rp, tc.gr)
+	e.encodeInt(&tc.ds, 0, true)
+
+	// SBREFINE, REFCORNER TOPLEFT
+	tr := append(encodeRegionInfo(40, 20, 0, 0, opOr), 0, 0x12, 0xFF, 0xFF, 0xFF, 0xFF)
+	tr = binary.BigEndian.AppendUint32(tr, 4)
+	tr = append(tr, e.flush()...)
+
+	data := encodeSegment(1, segPageInfo, nil, encodePageInfo(40, 20, 0))
+	data = append(data, encodeSegment(2, segImmediateText, []int{0}, tr)...)
+
+	img, err := Decode(data, globals)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := newBitmap(40, 20)
+	want.compose(a, 1, 2, opOr)
+	want.compose(b, 8, 2, opOr)
+	want.compose(c, 13, 2, opOr)
+	want.compose(a1, 3, 11, opOr)
+	checkPage(t, img, want)
+
+	// Corrupt or truncated data must not panic.
+	for i := 0; i < len(data); i++ {
+		Decode(data[:i], globals)
+		bb := append([]byte(nil), data...)
+		bb[i] ^= 0x5A
+		Decode(bb, globals)
+	}
+}
+
+func TestSymbolTextRegionHuffman(t *testing.T) {
+	_, a, b, _ := symbols()
+
+	// Symbol dictionary with a height class 7 (B, A) stored as uncompressed collective bitmap.
+	w := &bitWriter{}
+	encodeHuff(t, w, standardTable(4), 7, false)
+	encodeHuff(t, w, standardTable(2), 3, false)
+	encodeHuff(t, w, standardTable(2), 2, false)
+	encodeHuff(t, w, standardTable(2), 0, true)
+	encodeHuff(t, w, standardTable(1), 0, false)
+	w.align()
+	for y := 0; y < 7; y++ {
+		row := 0
+		for x := 0; x < 3; x++ {
+			row = row<<1 | b.get(x, y)
+		}
+		for x := 0; x < 5; x++ {
+			row = row<<1 | a.get(x, y)
+		}
+		w.writeBytes([]byte{byte(row)})
+	}
+	encodeHuff(t, w, standardTable(1), 0, false)
+	encodeHuff(t, w, standardTable(1), 2, false)
+
+	sd := binary.BigEndian.AppendUint32([]byte{0, 1}, 2)
+	sd = binary.BigEndian.AppendUint32(sd, 2)
+	sd = append(sd, w.out...)
+
+	// Text region with the symbol codes B=0 and A=1.
+	w = &bitWriter{}
+	for i := 0; i < 35; i++ {
+		n := 0
+		if i == 1 {
+			n = 1
+		}
+		w.writeBits(n, 4)
+	}
+	w.writeBits(0, 1)
+	w.writeBits(0, 1)
+	w.align()
+	encodeHuff(t, w, standardTable(11), 1, false)
+	encodeHuff(t, w, standardTable(11), 3, false)
+	encodeHuff(t, w, standardTable(6), 1, false)
+	w.writeBits(1, 1)
+	encodeHuff(t, w, standardTable(8), 2, false)
+	w.writeBits(0, 1)
+	encodeHuff(t, w, standardTable(8), 0, true)
+
+	// SBHUFF, REFCORNER TOPLEFT
+	tr := append(encodeRegionInfo(20, 12, 0, 0, opOr), 0, 0x11, 0, 0)
+	tr = binary.BigEndian.AppendUint32(tr, 2)
+	tr = append(tr, w.out...)
+
+	data := encodeSegment(0, segPageInfo, nil, encodePageInfo(20, 12, 0))
+	data = append(data, encodeSegment(1, segSymbolDict, nil, sd)...)
+	data = append(data, encodeSegment(2, segImmediateText, []int{1}, tr)...)
+
+	img, err := Decode(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := newBitmap(20, 12)
+	want.compose(a, 1, 2, opOr)
+	want.compose(b, 7, 2, opOr)
+	checkPage(t, img, want)
+}
+
+func TestMMRAndStripes(t *testing.T) {
+	// Page of unknown height with default pixel 1, all white MMR coded region (V0 per row) and an end of stripe.
+	data := encodeSegment(0, segPageInfo, nil, encodePageInfo(8, 0xFFFFFFFF, 1))
+	data = append(data, encodeSegment(1, segImmediateGeneric, nil, append(encodeRegionInfo(8, 8, 0, 0, opReplace), 1, 0xFF))...)
+	data = append(data, encodeSegment(2, segEndOfStripe, nil, []byte{0, 0, 0, 9})...)
+
+	img, err := Decode(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := newBitmap(8, 10)
+	want.fill(1)
+	want.compose(&bitmap{w: 8, h: 8, pix: make([]byte, 64)}, 0, 0, opReplace)
+	checkPage(t, img, want)
+
+	if got := img.Bytes(); len(got) != 10 || got[0] != 0xFF || got[9] != 0 {
+		t.Fatalf("Bytes: %v", got)
+	}
+}
+
+func TestStandardTables(t *testing.T) {
+	// The prefix codes of all standard tables are complete.
+	for i, lines := range standardTables {
+		var sum float64
+		for _, l := range lines {
+			sum += 1 / float64(uint64(1)<<uint(l.prefLen))
+		}
+		if sum != 1 {
+			t.Errorf("table B.%d: Kraft sum %f", i+1, sum)
+		}
+	}
+}
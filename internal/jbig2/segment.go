@@ -0,0 +1,693 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Segment types, see 7.3
+const (
+	segSymbolDict                  = 0
+	segIntermediateText            = 4
+	segImmediateText               = 6
+	segImmediateLosslessText       = 7
+	segPatternDict                 = 16
+	segIntermediateHalftone        = 20
+	segImmediateHalftone           = 22
+	segImmediateLosslessHalftone   = 23
+	segIntermediateGeneric         = 36
+	segImmediateGeneric            = 38
+	segImmediateLosslessGeneric    = 39
+	segIntermediateRefinement      = 40
+	segImmediateRefinement         = 42
+	segImmediateLosslessRefinement = 43
+	segPageInfo                    = 48
+	segEndOfStripe                 = 50
+	segEndOfFile                   = 51
+	segTables                      = 53
+)
+
+// regionInfoSize is the size of the region segment information field, see 7.4.1
+const regionInfoSize = 17
+
+func be32(bb []byte) uint32 {
+	return binary.BigEndian.Uint32(bb)
+}
+
+// segment is a JBIG2 segment along with the results of decoding it.
+type segment struct {
+	number, typ, page int
+	refs              []int
+	data              []byte
+	unknownLength     bool
+
+	symbols  []*bitmap  // exported symbols of a symbol dictionary
+	gb, gr   []context  // retained contexts of a symbol dictionary
+	patterns []*bitmap  // patterns of a pattern dictionary
+	table    *huffTable // custom Huffman table
+	region   *bitmap    // result of an intermediate region
+}
+
+// regionInfo is the region segment information field, see 7.4.1
+type regionInfo struct {
+	w, h, x, y int
+	op         int
+}
+
+func parseRegionInfo(data []byte) (regionInfo, error) {
+	if len(data) < regionInfoSize {
+		return regionInfo{}, errCorrupt
+	}
+	return regionInfo{
+		w:  int(be32(data)),
+		h:  int(be32(data[4:])),
+		x:  int(int32(be32(data[8:]))),
+		y:  int(int32(be32(data[12:]))),
+		op: int(data[16] & 7),
+	}, nil
+}
+
+// parseSegments parses the segments of bb using the sequential organization as embedded in PDF, see 7.2 and D.1
+func parseSegments(bb []byte) ([]*segment, error) {
+	var ss []*segment
+
+	for p := 0; p < len(bb); {
+		if len(bb)-p < 11 {
+			return nil, errCorrupt
+		}
+
+		s := &segment{number: int(be32(bb[p:]))}
+		flags := bb[p+4]
+		s.typ = int(flags & 0x3F)
+		p += 5
+
+		// Referred-to segment count and retention flags
+		n := int(bb[p] >> 5)
+		if n == 7 {
+			if len(bb)-p < 4 {
+				return nil, errCorrupt
+			}
+			n = int(be32(bb[p:]) & 0x1FFFFFFF)
+			p += 4 + (n+8)/8
+		} else {
+			p++
+		}
+
+		refSize := 1
+		if s.number > 65536 {
+			refSize = 4
+		} else if s.number > 256 {
+			refSize = 2
+		}
+
+		pageSize := 1
+		if flags&0x40 != 0 {
+			pageSize = 4
+		}
+
+		if n < 0 || p+n*refSize+pageSize+4 > len(bb) {
+			return nil, errCorrupt
+		}
+
+		for i := 0; i < n; i++ {
+			var r int
+			switch refSize {
+			case 1:
+				r = int(bb[p])
+			case 2:
+				r = int(binary.BigEndian.Uint16(bb[p:]))
+			default:
+				r = int(be32(bb[p:]))
+			}
+			s.refs = append(s.refs, r)
+			p += refSize
+		}
+
+		if pageSize == 1 {
+			s.page = int(bb[p])
+		} else {
+			s.page = int(be32(bb[p:]))
+		}
+		p += pageSize
+
+		l := be32(bb[p:])
+		p += 4
+
+		if l == 0xFFFFFFFF {
+			if s.typ != segImmediateGeneric {
+				return nil, errCorrupt
+			}
+			n, err := genericRegionLength(bb[p:])
+			if err != nil {
+				return nil, err
+			}
+			l = uint32(n)
+			s.unknownLength = true
+		}
+
+		if uint64(p)+uint64(l) > uint64(len(bb)) {
+			return nil, errCorrupt
+		}
+		s.data = bb[p : p+int(l)]
+		p += int(l)
+
+		ss = append(ss, s)
+
+		if s.typ == segEndOfFile {
+			break
+		}
+	}
+
+	return ss, nil
+}
+
+// genericRegionLength returns the length of the data of an immediate generic region segment with unknown length, see 7.2.7
+// The data is terminated by an end marker followed by the 4 byte row count.
+func genericRegionLength(data []byte) (int, error) {
+	if len(data) < regionInfoSize+1 {
+		return 0, errCorrupt
+	}
+
+	marker := []byte{0xFF, 0xAC}
+	if data[regionInfoSize]&1 == 1 {
+		// MMR
+		marker = []byte{0x00, 0x00}
+	}
+
+	i := bytes.Index(data[regionInfoSize+1:], marker)
+	if i < 0 || regionInfoSize+1+i+6 > len(data) {
+		return 0, errCorrupt
+	}
+
+	return regionInfoSize + 1 + i + 6, nil
+}
+
+// decoder holds the state of decoding the segments of a JBIG2 page.
+type decoder struct {
+	segments      map[int]*segment
+	page          *bitmap
+	pageNr        int
+	defPixel      byte
+	unknownHeight bool
+}
+
+// referred returns the referred-to segments of s having one of the types typ.
+func (d *decoder) referred(s *segment, typ ...int) []*segment {
+	var ss []*segment
+	for _, nr := range s.refs {
+		r, ok := d.segments[nr]
+		if !ok {
+			continue
+		}
+		for _, t := range typ {
+			if r.typ == t {
+				ss = append(ss, r)
+				break
+			}
+		}
+	}
+	return ss
+}
+
+// tables returns the custom Huffman tables referred to by s.
+func (d *decoder) tables(s *segment) *tableSelector {
+	ts := &tableSelector{}
+	for _, r := range d.referred(s, segTables) {
+		ts.custom = append(ts.custom, r.table)
+	}
+	return ts
+}
+
+func (d *decoder) process(ss []*segment) error {
+	for _, s := range ss {
+
+		if s.page != 0 && d.page != nil && s.page != d.pageNr {
+			// Only the first page is decoded.
+			continue
+		}
+
+		d.segments[s.number] = s
+
+		var err error
+
+		switch s.typ {
+
+		case segPageInfo:
+			if d.page == nil {
+				err = d.pageInfo(s)
+			}
+
+		case segEndOfStripe:
+			err = d.endOfStripe(s)
+
+		case segTables:
+			s.table, err = parseHuffTable(s.data)
+
+		case segSymbolDict:
+			err = d.symbolDict(s)
+
+		case segPatternDict:
+			err = d.patternDict(s)
+
+		case segIntermediateText, segImmediateText, segImmediateLosslessText:
+			err = d.region(s, d.textRegion)
+
+		case segIntermediateHalftone, segImmediateHalftone, segImmediateLosslessHalftone:
+			err = d.region(s, d.halftoneRegion)
+
+		case segIntermediateGeneric, segImmediateGeneric, segImmediateLosslessGeneric:
+			err = d.region(s, d.genericRegion)
+
+		case segIntermediateRefinement, segImmediateRefinement, segImmediateLosslessRefinement:
+			err = d.region(s, d.refinementRegion)
+
+		case segEndOfFile:
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pageInfo processes a page information segment, see 7.4.8
+func (d *decoder) pageInfo(s *segment) error {
+	if len(s.data) < 19 {
+		return errCorrupt
+	}
+
+	w, h := int(be32(s.data)), be32(s.data[4:])
+	if h == 0xFFFFFFFF {
+		d.unknownHeight = true
+		h = 0
+	}
+
+	page, err := newBitmap(w, int(h))
+	if err != nil {
+		return err
+	}
+
+	d.defPixel = s.data[16] >> 2 & 1
+	if d.defPixel == 1 {
+		page.fill(1)
+	}
+
+	d.page, d.pageNr = page, s.page
+
+	return nil
+}
+
+// endOfStripe processes an end of stripe segment, see 7.4.10
+func (d *decoder) endOfStripe(s *segment) error {
+	if len(s.data) < 4 || d.page == nil {
+		return errCorrupt
+	}
+	if d.unknownHeight {
+		return d.page.grow(int(be32(s.data))+1, d.defPixel)
+	}
+	return nil
+}
+
+// region decodes a region segment s using dec.
+// The result of an immediate region is combined into the page, the result of an intermediate region is kept for further refinement.
+func (d *decoder) region(s *segment, dec func(*segment, regionInfo) (*bitmap, error)) error {
+	ri, err := parseRegionInfo(s.data)
+	if err != nil {
+		return err
+	}
+
+	b, err := dec(s, ri)
+	if err != nil {
+		return err
+	}
+
+	switch s.typ {
+	case segIntermediateText, segIntermediateHalftone, segIntermediateGeneric, segIntermediateRefinement:
+		s.region = b
+		return nil
+	}
+
+	if d.page == nil {
+		return errCorrupt
+	}
+	if d.unknownHeight {
+		if err := d.page.grow(ri.y+b.h, d.defPixel); err != nil {
+			return err
+		}
+	}
+	d.page.compose(b, ri.x, ri.y, ri.op)
+
+	return nil
+}
+
+// genericRegion decodes a generic region segment, see 7.4.6
+func (d *decoder) genericRegion(s *segment, ri regionInfo) (*bitmap, error) {
+	data := s.data[regionInfoSize:]
+	if len(data) < 1 {
+		return nil, errCorrupt
+	}
+
+	flags := data[0]
+	data = data[1:]
+	if flags&0x10 != 0 {
+		// Extended template
+		return nil, errUnsupported
+	}
+
+	if s.unknownLength {
+		// The region height is given by the row count following the data.
+		ri.h = int(be32(data[len(data)-4:]))
+		data = data[:len(data)-4]
+	}
+
+	if flags&1 == 1 {
+		return decodeMMR(data, ri.w, ri.h)
+	}
+
+	p := &genericParams{w: ri.w, h: ri.h, template: int(flags >> 1 & 3), tpgdon: flags&8 != 0}
+
+	n := 1
+	if p.template == 0 {
+		n = 4
+	}
+	if len(data) < 2*n {
+		return nil, errCorrupt
+	}
+	for i := 0; i < n; i++ {
+		p.at[i] = [2]int{int(int8(data[2*i])), int(int8(data[2*i+1]))}
+	}
+	data = data[2*n:]
+
+	return decodeGeneric(newArithDecoder(data), p, make([]context, genericContexts[p.template]))
+}
+
+// refinementRegion decodes a generic refinement region segment, see 7.4.7
+func (d *decoder) refinementRegion(s *segment, ri regionInfo) (*bitmap, error) {
+	data := s.data[regionInfoSize:]
+	if len(data) < 1 {
+		return nil, errCorrupt
+	}
+
+	flags := data[0]
+	data = data[1:]
+
+	p := &refinementParams{w: ri.w, h: ri.h, template: int(flags & 1), tpgron: flags&2 != 0}
+	if p.template == 0 {
+		if len(data) < 4 {
+			return nil, errCorrupt
+		}
+		p.at = [2][2]int{{int(int8(data[0])), int(int8(data[1]))}, {int(int8(data[2])), int(int8(data[3]))}}
+		data = data[4:]
+	}
+
+	// The reference is either an intermediate region or the page area covered by this region.
+	for _, r := range d.referred(s, segIntermediateText, segIntermediateHalftone, segIntermediateGeneric, segIntermediateRefinement) {
+		p.ref = r.region
+	}
+	if p.ref == nil {
+		if d.page == nil {
+			return nil, errCorrupt
+		}
+		ref, err := d.page.sub(ri.x, ri.y, ri.w, ri.h)
+		if err != nil {
+			return nil, err
+		}
+		p.ref = ref
+	}
+
+	return decodeRefinement(newArithDecoder(data), p, make([]context, refinementContexts[p.template]))
+}
+
+// symbolDict decodes a symbol dictionary segment, see 7.4.2
+func (d *decoder) symbolDict(s *segment) error {
+	data := s.data
+	if len(data) < 2 {
+		return errCorrupt
+	}
+
+	flags := binary.BigEndian.Uint16(data)
+	data = data[2:]
+
+	p := &symbolParams{
+		huff:      flags&1 == 1,
+		refAgg:    flags>>1&1 == 1,
+		template:  int(flags >> 10 & 3),
+		rtemplate: int(flags >> 12 & 1),
+	}
+
+	if !p.huff {
+		n := 1
+		if p.template == 0 {
+			n = 4
+		}
+		if len(data) < 2*n {
+			return errCorrupt
+		}
+		for i := 0; i < n; i++ {
+			p.at[i] = [2]int{int(int8(data[2*i])), int(int8(data[2*i+1]))}
+		}
+		data = data[2*n:]
+	}
+
+	if p.refAgg && p.rtemplate == 0 {
+		if len(data) < 4 {
+			return errCorrupt
+		}
+		p.rat = [2][2]int{{int(int8(data[0])), int(int8(data[1]))}, {int(int8(data[2])), int(int8(data[3]))}}
+		data = data[4:]
+	}
+
+	if len(data) < 8 {
+		return errCorrupt
+	}
+	p.numNew = int(be32(data[4:]))
+	data = data[8:]
+
+	var prev *segment
+	for _, r := range d.referred(s, segSymbolDict) {
+		p.in = append(p.in, r.symbols...)
+		prev = r
+	}
+	if p.numNew > maxPixels || len(p.in)+p.numNew > maxPixels {
+		return errCorrupt
+	}
+
+	if p.huff {
+		ts := d.tables(s)
+		var err error
+		if p.dh, err = ts.next(int(flags>>2&3), 4, 5); err != nil {
+			return err
+		}
+		if p.dw, err = ts.next(int(flags>>4&3), 2, 3); err != nil {
+			return err
+		}
+		if p.bmSize, err = ts.next(int(flags>>6&1), 1); err != nil {
+			return err
+		}
+		if p.aggInst, err = ts.next(int(flags>>7&1), 1); err != nil {
+			return err
+		}
+	}
+
+	cx := &symbolContexts{
+		gb:   make([]context, genericContexts[0]),
+		text: newTextContexts(ceilLog2(len(p.in) + p.numNew)),
+	}
+
+	if flags>>8&1 == 1 && prev != nil && prev.gb != nil {
+		// Bitmap coding context used.
+		copy(cx.gb, prev.gb)
+		copy(cx.text.gr, prev.gr)
+	}
+
+	sd := &symbolDecoder{symbolParams: p, cx: cx}
+	if p.huff {
+		sd.br = &bitReader{data: data}
+	} else {
+		sd.ad = newArithDecoder(data)
+	}
+
+	syms, err := decodeSymbols(sd)
+	if err != nil {
+		return err
+	}
+	s.symbols = syms
+
+	if flags>>9&1 == 1 {
+		// Bitmap coding context retained.
+		s.gb, s.gr = cx.gb, cx.text.gr
+	}
+
+	return nil
+}
+
+// textRegion decodes a text region segment, see 7.4.3
+func (d *decoder) textRegion(s *segment, ri regionInfo) (*bitmap, error) {
+	data := s.data[regionInfoSize:]
+	if len(data) < 2 {
+		return nil, errCorrupt
+	}
+
+	flags := binary.BigEndian.Uint16(data)
+	data = data[2:]
+
+	p := &textParams{
+		huff:       flags&1 == 1,
+		refine:     flags>>1&1 == 1,
+		w:          ri.w,
+		h:          ri.h,
+		logStrips:  int(flags >> 2 & 3),
+		refCorner:  int(flags >> 4 & 3),
+		transposed: flags>>6&1 == 1,
+		combOp:     int(flags >> 7 & 3),
+		defPixel:   byte(flags >> 9 & 1),
+		dsOffset:   int(flags >> 10 & 0x1F),
+		rtemplate:  int(flags >> 15 & 1),
+	}
+	if p.dsOffset > 15 {
+		// SBDSOFFSET is a signed 5 bit value.
+		p.dsOffset -= 32
+	}
+
+	var hflags uint16
+	if p.huff {
+		if len(data) < 2 {
+			return nil, errCorrupt
+		}
+		hflags = binary.BigEndian.Uint16(data)
+		data = data[2:]
+	}
+
+	if p.refine && p.rtemplate == 0 {
+		if len(data) < 4 {
+			return nil, errCorrupt
+		}
+		p.rat = [2][2]int{{int(int8(data[0])), int(int8(data[1]))}, {int(int8(data[2])), int(int8(data[3]))}}
+		data = data[4:]
+	}
+
+	if len(data) < 4 {
+		return nil, errCorrupt
+	}
+	p.numInstances = int(be32(data))
+	data = data[4:]
+
+	for _, r := range d.referred(s, segSymbolDict) {
+		p.syms = append(p.syms, r.symbols...)
+	}
+
+	td := &textDecoder{textParams: p}
+
+	if !p.huff {
+		p.symCodeLen = ceilLog2(len(p.syms))
+		td.ad = newArithDecoder(data)
+		td.cx = newTextContexts(p.symCodeLen)
+		return decodeText(td)
+	}
+
+	ts := d.tables(s)
+	for _, t := range []struct {
+		table *(*huffTable)
+		sel   int
+		std   []int
+	}{
+		{&p.fs, int(hflags & 3), []int{6, 7}},
+		{&p.ds, int(hflags >> 2 & 3), []int{8, 9, 10}},
+		{&p.dt, int(hflags >> 4 & 3), []int{11, 12, 13}},
+		{&p.rdw, int(hflags >> 6 & 3), []int{14, 15}},
+		{&p.rdh, int(hflags >> 8 & 3), []int{14, 15}},
+		{&p.rdx, int(hflags >> 10 & 3), []int{14, 15}},
+		{&p.rdy, int(hflags >> 12 & 3), []int{14, 15}},
+		{&p.rsize, int(hflags >> 14 & 1), []int{1}},
+	} {
+		ht, err := ts.next(t.sel, t.std...)
+		if err != nil {
+			return nil, err
+		}
+		*t.table = ht
+	}
+
+	td.br = &bitReader{data: data}
+	td.cx = newTextContexts(0)
+
+	symCodes, err := symbolCodes(td.br, len(p.syms))
+	if err != nil {
+		return nil, err
+	}
+	p.symCodes = symCodes
+
+	return decodeText(td)
+}
+
+// patternDict decodes a pattern dictionary segment, see 7.4.4
+func (d *decoder) patternDict(s *segment) error {
+	if len(s.data) < 7 {
+		return errCorrupt
+	}
+
+	flags := s.data[0]
+	pw, ph := int(s.data[1]), int(s.data[2])
+	grayMax := int(be32(s.data[3:]))
+
+	pp, err := decodePatterns(s.data[7:], flags&1 == 1, int(flags>>1&3), pw, ph, grayMax)
+	if err != nil {
+		return err
+	}
+	s.patterns = pp
+
+	return nil
+}
+
+// halftoneRegion decodes a halftone region segment, see 7.4.5
+func (d *decoder) halftoneRegion(s *segment, ri regionInfo) (*bitmap, error) {
+	data := s.data[regionInfoSize:]
+	if len(data) < 21 {
+		return nil, errCorrupt
+	}
+
+	flags := data[0]
+	p := &halftoneParams{
+		w:          ri.w,
+		h:          ri.h,
+		mmr:        flags&1 == 1,
+		template:   int(flags >> 1 & 3),
+		enableSkip: flags>>3&1 == 1,
+		combOp:     int(flags >> 4 & 7),
+		defPixel:   flags >> 7 & 1,
+		gw:         int(be32(data[1:])),
+		gh:         int(be32(data[5:])),
+		gx:         int(int32(be32(data[9:]))),
+		gy:         int(int32(be32(data[13:]))),
+		rx:         int(binary.BigEndian.Uint16(data[17:])),
+		ry:         int(binary.BigEndian.Uint16(data[19:])),
+	}
+
+	for _, r := range d.referred(s, segPatternDict) {
+		p.patterns = r.patterns
+	}
+
+	if p.gw > 0 && p.gh > maxPixels/p.gw {
+		return nil, errCorrupt
+	}
+
+	return decodeHalftone(data[21:], p)
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+// symbolParams are the parameters of the symbol dictionary decoding procedure, see Table 13
+type symbolParams struct {
+	huff, refAgg bool
+	in           []*bitmap
+	numNew       int
+
+	dh, dw, bmSize, aggInst *huffTable
+
+	template  int
+	at        [4][2]int
+	rtemplate int
+	rat       [2][2]int
+}
+
+// symbolContexts are the arithmetic decoding contexts of the symbol dictionary decoding procedure.
+type symbolContexts struct {
+	dh, dw, ex, ai intContexts
+	gb             []context // generic region
+	text           *textContexts
+}
+
+// ceilLog2 returns the number of bits needed to code n distinct values.
+func ceilLog2(n int) int {
+	i := 0
+	for 1<<uint(i) < n {
+		i++
+	}
+	return i
+}
+
+// symbolDecoder decodes the values of a symbol dictionary either using the arithmetic decoder ad or the Huffman coded bits of br.
+type symbolDecoder struct {
+	*symbolParams
+	ad *arithDecoder
+	br *bitReader
+	cx *symbolContexts
+}
+
+func (d *symbolDecoder) int(cx *intContexts, t *huffTable) (int, bool, error) {
+	if d.huff {
+		return t.decode(d.br)
+	}
+	v, ok := d.ad.decodeInt(cx)
+	return v, ok, nil
+}
+
+func (d *symbolDecoder) value(cx *intContexts, t *huffTable) (int, error) {
+	v, ok, err := d.int(cx, t)
+	if err == nil && !ok {
+		err = errCorrupt
+	}
+	return v, err
+}
+
+// refAggSymbol decodes a symbol bitmap using refinement/aggregate coding, see 6.5.8.2
+func (d *symbolDecoder) refAggSymbol(syms []*bitmap, w, h int) (*bitmap, error) {
+	n, err := d.value(&d.cx.ai, d.aggInst)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return nil, errCorrupt
+	}
+
+	symCodeLen := ceilLog2(len(d.in) + d.numNew)
+
+	td := &textDecoder{
+		textParams: &textParams{
+			huff:         d.huff,
+			refine:       true,
+			w:            w,
+			h:            h,
+			numInstances: n,
+			syms:         syms,
+			symCodeLen:   symCodeLen,
+			combOp:       opOr,
+			refCorner:    cornerTopLeft,
+			fs:           standardTable(6),
+			ds:           standardTable(8),
+			dt:           standardTable(11),
+			rdw:          standardTable(15),
+			rdh:          standardTable(15),
+			rdx:          standardTable(15),
+			rdy:          standardTable(15),
+			rsize:        standardTable(1),
+			rtemplate:    d.rtemplate,
+			rat:          d.rat,
+		},
+		ad: d.ad,
+		br: d.br,
+		cx: d.cx.text,
+	}
+
+	if n > 1 {
+		// Aggregation of several symbol instances, see 6.5.8.2.1
+		return decodeText(td)
+	}
+
+	// Refinement of a single symbol, see 6.5.8.2.2
+	id, err := td.symbolID()
+	if err != nil {
+		return nil, err
+	}
+	if id < 0 || id >= len(syms) {
+		return nil, errCorrupt
+	}
+
+	rdx, err := td.value(&td.cx.rdx, td.rdx)
+	if err != nil {
+		return nil, err
+	}
+	rdy, err := td.value(&td.cx.rdy, td.rdy)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &refinementParams{w: w, h: h, template: d.rtemplate, ref: syms[id], dx: rdx, dy: rdy, at: d.rat}
+
+	if !d.huff {
+		return decodeRefinement(d.ad, p, td.cx.gr)
+	}
+
+	size, err := td.value(nil, td.rsize)
+	if err != nil {
+		return nil, err
+	}
+	d.br.align()
+	if size < 0 || d.br.pos+size > len(d.br.data) {
+		return nil, errCorrupt
+	}
+	b, err := decodeRefinement(newArithDecoder(d.br.data[d.br.pos:d.br.pos+size]), p, td.cx.gr)
+	d.br.pos += size
+	return b, err
+}
+
+// collectiveBitmap decodes the symbols of a height class stored as one bitmap, see 6.5.9
+func (d *symbolDecoder) collectiveBitmap(widths []int, h int) ([]*bitmap, error) {
+	size, err := d.value(nil, d.bmSize)
+	if err != nil {
+		return nil, err
+	}
+	d.br.align()
+
+	w := 0
+	for _, sw := range widths {
+		w += sw
+	}
+
+	var b *bitmap
+	if size == 0 {
+		// Uncompressed bitmap using byte aligned rows.
+		stride := (w + 7) / 8
+		if d.br.pos+stride*h > len(d.br.data) {
+			return nil, errCorrupt
+		}
+		if b, err = newBitmap(w, h); err != nil {
+			return nil, err
+		}
+		for y := 0; y < h; y++ {
+			row := d.br.data[d.br.pos+y*stride:]
+			for x := 0; x < w; x++ {
+				b.pix[y*w+x] = row[x>>3] >> (7 - uint(x&7)) & 1
+			}
+		}
+		d.br.pos += stride * h
+	} else {
+		if size < 0 || d.br.pos+size > len(d.br.data) {
+			return nil, errCorrupt
+		}
+		if b, err = decodeMMR(d.br.data[d.br.pos:d.br.pos+size], w, h); err != nil {
+			return nil, err
+		}
+		d.br.pos += size
+	}
+
+	var ss []*bitmap
+	x := 0
+	for _, sw := range widths {
+		s, err := b.sub(x, 0, sw, h)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+		x += sw
+	}
+
+	return ss, nil
+}
+
+// decodeSymbols runs the symbol dictionary decoding procedure and returns the exported symbols, see 6.5
+func decodeSymbols(d *symbolDecoder) ([]*bitmap, error) {
+	syms := append([]*bitmap(nil), d.in...)
+
+	var hcHeight int
+	for len(syms)-len(d.in) < d.numNew {
+
+		dh, err := d.value(&d.cx.dh, d.dh)
+		if err != nil {
+			return nil, err
+		}
+		hcHeight += dh
+		if hcHeight < 0 {
+			return nil, errCorrupt
+		}
+
+		var symWidth int
+		var widths []int
+
+		for {
+			dw, ok, err := d.int(&d.cx.dw, d.dw)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			if len(syms)-len(d.in)+len(widths) >= d.numNew {
+				return nil, errCorrupt
+			}
+			symWidth += dw
+			if symWidth < 0 {
+				return nil, errCorrupt
+			}
+
+			if d.huff && !d.refAgg {
+				widths = append(widths, symWidth)
+				continue
+			}
+
+			var b *bitmap
+			if d.refAgg {
+				b, err = d.refAggSymbol(syms, symWidth, hcHeight)
+			} else {
+				p := &genericParams{w: symWidth, h: hcHeight, template: d.template, at: d.at}
+				b, err = decodeGeneric(d.ad, p, d.cx.gb)
+			}
+			if err != nil {
+				return nil, err
+			}
+			syms = append(syms, b)
+		}
+
+		if len(widths) > 0 {
+			ss, err := d.collectiveBitmap(widths, hcHeight)
+			if err != nil {
+				return nil, err
+			}
+			syms = append(syms, ss...)
+		}
+	}
+
+	// Exported symbols, see 6.5.10
+	var ex []*bitmap
+	export := false
+	for i, n := 0, 0; i < len(syms); n++ {
+		if n > 2*len(syms)+2 {
+			return nil, errCorrupt
+		}
+		run, err := d.value(&d.cx.ex, standardTable(1))
+		if err != nil {
+			return nil, err
+		}
+		if run < 0 || i+run > len(syms) {
+			return nil, errCorrupt
+		}
+		if export {
+			ex = append(ex, syms[i:i+run]...)
+		}
+		i += run
+		export = !export
+	}
+
+	return ex, nil
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jbig2
+
+// Reference corners of symbol instances, see 7.4.3.1.1
+const (
+	cornerBottomLeft = iota
+	cornerTopLeft
+	cornerBottomRight
+	cornerTopRight
+)
+
+// textParams are the parameters of the text region decoding procedure, see Table 9
+type textParams struct {
+	huff         bool
+	refine       bool
+	w, h         int
+	numInstances int
+	logStrips    int
+	syms         []*bitmap
+	symCodeLen   int
+	symCodes     *huffTable // Huffman coded symbol IDs, nil for fixed length codes of symCodeLen bits.
+	defPixel     byte
+	combOp       int
+	transposed   bool
+	refCorner    int
+	dsOffset     int
+
+	fs, ds, dt, rdw, rdh, rdx, rdy, rsize *huffTable
+
+	rtemplate int
+	rat       [2][2]int
+}
+
+// textContexts are the arithmetic decoding contexts of the text region decoding procedure.
+type textContexts struct {
+	dt, fs, ds, it, ri, rdw, rdh, rdx, rdy intContexts
+	id                                     []context // IAID
+	gr                                     []context // generic refinement region
+}
+
+func newTextContexts(symCodeLen int) *textContexts {
+	return &textContexts{
+		id: make([]context, 1<<uint(symCodeLen+1)),
+		gr: make([]context, refinementContexts[0]),
+	}
+}
+
+// textDecoder decodes the values of a text region either using the arithmetic decoder ad or the Huffman coded bits of br.
+type textDecoder struct {
+	*textParams
+	ad *arithDecoder
+	br *bitReader
+	cx *textContexts
+}
+
+// int decodes an integer using either the contexts cx or the Huffman table t.
+func (d *textDecoder) int(cx *intContexts, t *huffTable) (int, bool, error) {
+	if d.huff {
+		return t.decode(d.br)
+	}
+	v, ok := d.ad.decodeInt(cx)
+	return v, ok, nil
+}
+
+// value decodes an integer which must not be OOB.
+func (d *textDecoder) value(cx *intContexts, t *huffTable) (int, error) {
+	v, ok, err := d.int(cx, t)
+	if err == nil && !ok {
+		err = errCorrupt
+	}
+	return v, err
+}
+
+func (d *textDecoder) symbolID() (int, error) {
+	if !d.huff {
+		return d.ad.decodeID(d.cx.id, d.symCodeLen), nil
+	}
+	if d.symCodes == nil {
+		return d.br.readBits(d.symCodeLen)
+	}
+	return d.value(nil, d.symCodes)
+}
+
+// refinedSymbol decodes the refinement of the symbol bitmap ibo, see 6.4.11
+func (d *textDecoder) refinedSymbol(ibo *bitmap) (*bitmap, error) {
+	var rd [4]int
+	for i, v := range []struct {
+		cx *intContexts
+		t  *huffTable
+	}{{&d.cx.rdw, d.rdw}, {&d.cx.rdh, d.rdh}, {&d.cx.rdx, d.rdx}, {&d.cx.rdy, d.rdy}} {
+		var err error
+		if rd[i], err = d.value(v.cx, v.t); err != nil {
+			return nil, err
+		}
+	}
+	rdw, rdh, rdx, rdy := rd[0], rd[1], rd[2], rd[3]
+
+	p := &refinementParams{
+		w:        ibo.w + rdw,
+		h:        ibo.h + rdh,
+		template: d.rtemplate,
+		ref:      ibo,
+		dx:       rdw>>1 + rdx,
+		dy:       rdh>>1 + rdy,
+		at:       d.rat,
+	}
+
+	if !d.huff {
+		return decodeRefinement(d.ad, p, d.cx.gr)
+	}
+
+	size, err := d.value(nil, d.rsize)
+	if err != nil {
+		return nil, err
+	}
+	d.br.align()
+	if size < 0 || d.br.pos+size > len(d.br.data) {
+		return nil, errCorrupt
+	}
+	b, err := decodeRefinement(newArithDecoder(d.br.data[d.br.pos:d.br.pos+size]), p, d.cx.gr)
+	d.br.pos += size
+	return b, err
+}
+
+// decodeText runs the text region decoding procedure, see 6.4
+func decodeText(d *textDecoder) (*bitmap, error) {
+	b, err := newBitmap(d.w, d.h)
+	if err != nil {
+		return nil, err
+	}
+	if d.defPixel == 1 {
+		b.fill(1)
+	}
+
+	strips := 1 << uint(d.logStrips)
+
+	stripT, err := d.value(&d.cx.dt, d.dt)
+	if err != nil {
+		return nil, err
+	}
+	stripT *= -strips
+	firstS := 0
+
+	for n := 0; n < d.numInstances; {
+
+		dt, err := d.value(&d.cx.dt, d.dt)
+		if err != nil {
+			return nil, err
+		}
+		stripT += dt * strips
+
+		dfs, err := d.value(&d.cx.fs, d.fs)
+		if err != nil {
+			return nil, err
+		}
+		firstS += dfs
+		curS := firstS
+
+		for {
+			curT := 0
+			if strips > 1 {
+				if d.huff {
+					curT, err = d.br.readBits(d.logStrips)
+				} else {
+					curT, err = d.value(&d.cx.it, nil)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			t := stripT + curT
+
+			id, err := d.symbolID()
+			if err != nil {
+				return nil, err
+			}
+			if id < 0 || id >= len(d.syms) {
+				return nil, errCorrupt
+			}
+
+			ri := 0
+			if d.refine {
+				if d.huff {
+					ri, err = d.br.readBit()
+				} else {
+					ri, err = d.value(&d.cx.ri, nil)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			ib := d.syms[id]
+			if ri == 1 {
+				if ib, err = d.refinedSymbol(ib); err != nil {
+					return nil, err
+				}
+			}
+
+			if !d.transposed && (d.refCorner == cornerTopRight || d.refCorner == cornerBottomRight) {
+				curS += ib.w - 1
+			} else if d.transposed && (d.refCorner == cornerBottomLeft || d.refCorner == cornerBottomRight) {
+				curS += ib.h - 1
+			}
+
+			x, y := curS, t
+			if d.transposed {
+				x, y = t, curS
+			}
+			if d.refCorner == cornerTopRight || d.refCorner == cornerBottomRight {
+				x -= ib.w - 1
+			}
+			if d.refCorner == cornerBottomLeft || d.refCorner == cornerBottomRight {
+				y -= ib.h - 1
+			}
+			b.compose(ib, x, y, d.combOp)
+
+			if !d.transposed && (d.refCorner == cornerTopLeft || d.refCorner == cornerBottomLeft) {
+				curS += ib.w - 1
+			} else if d.transposed && (d.refCorner == cornerTopLeft || d.refCorner == cornerTopRight) {
+				curS += ib.h - 1
+			}
+
+			n++
+
+			ids, ok, err := d.int(&d.cx.ds, d.ds)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			curS += ids + d.dsOffset
+		}
+	}
+
+	return b, nil
+}
+
+// symbolCodes decodes the Huffman table of the symbol ID codes of a text region, see 7.4.3.1.7
+func symbolCodes(r *bitReader, numSyms int) (*huffTable, error) {
+	lines := make([]huffLine, 35)
+	for i := range lines {
+		n, err := r.readBits(4)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = huffLine{prefLen: n, rangeLow: i}
+	}
+	runCodes := newHuffTable(lines)
+
+	lines = make([]huffLine, numSyms)
+	for i := 0; i < numSyms; {
+		c, _, err := runCodes.decode(r)
+		if err != nil {
+			return nil, err
+		}
+
+		n, v := 1, c
+		switch c {
+		case 32:
+			if i == 0 {
+				return nil, errCorrupt
+			}
+			v = lines[i-1].prefLen
+			n, err = r.readBits(2)
+			n += 3
+		case 33:
+			v = 0
+			n, err = r.readBits(3)
+			n += 3
+		case 34:
+			v = 0
+			n, err = r.readBits(7)
+			n += 11
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i+n > numSyms {
+			return nil, errCorrupt
+		}
+
+		for ; n > 0; n-- {
+			lines[i] = huffLine{prefLen: v, rangeLow: i}
+			i++
+		}
+	}
+	r.align()
+
+	return newHuffTable(lines), nil
+}
@@ -0,0 +1,532 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+import (
+	"encoding/binary"
+)
+
+// Codestream markers, see Annex A.
+const (
+	markerSOC = 0xFF4F
+	markerSIZ = 0xFF51
+	markerCOD = 0xFF52
+	markerCOC = 0xFF53
+	markerQCD = 0xFF5C
+	markerQCC = 0xFF5D
+	markerRGN = 0xFF5E
+	markerPOC = 0xFF5F
+	markerPPM = 0xFF60
+	markerPPT = 0xFF61
+	markerSOT = 0xFF90
+	markerSOP = 0xFF91
+	markerEPH = 0xFF92
+	markerSOD = 0xFF93
+	markerEOC = 0xFFD9
+)
+
+// Progression orders.
+const (
+	progLRCP = iota
+	progRLCP
+	progRPCL
+	progPCRL
+	progCPRL
+)
+
+// Code-block styles.
+const (
+	cbLazy    = 0x01
+	cbReset   = 0x02
+	cbTermAll = 0x04
+	cbVCausal = 0x08
+	cbSegSym  = 0x20
+	cbHTJ2K   = 0x40
+)
+
+// Quantization styles.
+const (
+	quantNone = iota
+	quantDerived
+	quantExpounded
+)
+
+type compSiz struct {
+	prec   int
+	signed bool
+	dx, dy int
+}
+
+// compStyle holds the coding style parameters of a tile-component (SPcod, SPcoc).
+type compStyle struct {
+	levels     int
+	xcb, ycb   int // Code-block size exponents.
+	cbStyle    int
+	reversible bool
+	ppx, ppy   []int // Precinct size exponents per resolution.
+}
+
+// codingStyle holds the coding style parameters of a tile (Scod, SGcod, SPcod).
+type codingStyle struct {
+	sop, eph bool
+	prog     int
+	layers   int
+	mct      bool
+	compStyle
+}
+
+type quantization struct {
+	style   int
+	guard   int
+	eps, mu []int
+}
+
+type progression struct {
+	rs, cs, lye, re, ce, prog int
+}
+
+// header holds the coding parameters of the main header or a tile header.
+type header struct {
+	cod *codingStyle
+	coc map[int]*compStyle
+	qcd *quantization
+	qcc map[int]*quantization
+	rgn map[int]int
+	poc []progression
+}
+
+type tile struct {
+	header
+	x0, y0, x1, y1 int // Tile area on the reference grid.
+	data           []byte
+}
+
+type codestream struct {
+	x1, y1, x0, y0   int // Image area on the reference grid.
+	tw, th, tx0, ty0 int // Tiling.
+	comps            []compSiz
+	main             header
+	tiles            []*tile
+	out              []Component
+}
+
+type segReader struct {
+	bb  []byte
+	err error
+}
+
+func (r *segReader) u8() int {
+	if len(r.bb) < 1 {
+		r.err = errCorrupt
+		return 0
+	}
+	v := r.bb[0]
+	r.bb = r.bb[1:]
+	return int(v)
+}
+
+func (r *segReader) u16() int {
+	if len(r.bb) < 2 {
+		r.err = errCorrupt
+		r.bb = nil
+		return 0
+	}
+	v := binary.BigEndian.Uint16(r.bb)
+	r.bb = r.bb[2:]
+	return int(v)
+}
+
+func (r *segReader) u32() int {
+	if len(r.bb) < 4 {
+		r.err = errCorrupt
+		r.bb = nil
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.bb)
+	r.bb = r.bb[4:]
+	return int(v)
+}
+
+// comp reads a component index which is 16 bit for images with more than 256 components.
+func (c *codestream) comp(r *segReader) int {
+	if len(c.comps) > 256 {
+		return r.u16()
+	}
+	return r.u8()
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func (c *codestream) parseSIZ(bb []byte) error {
+	r := &segReader{bb: bb}
+	r.u16() // Rsiz
+	c.x1, c.y1, c.x0, c.y0 = r.u32(), r.u32(), r.u32(), r.u32()
+	c.tw, c.th, c.tx0, c.ty0 = r.u32(), r.u32(), r.u32(), r.u32()
+	n := r.u16()
+	if r.err != nil || n == 0 || n > 16384 {
+		return errCorrupt
+	}
+	c.comps = make([]compSiz, n)
+	for i := range c.comps {
+		ssiz := r.u8()
+		c.comps[i] = compSiz{prec: ssiz&0x7F + 1, signed: ssiz&0x80 > 0, dx: r.u8(), dy: r.u8()}
+		if c.comps[i].dx == 0 || c.comps[i].dy == 0 {
+			return errCorrupt
+		}
+		if c.comps[i].prec > 16 {
+			return errUnsupported
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if c.x1 <= c.x0 || c.y1 <= c.y0 || c.tw == 0 || c.th == 0 || c.tx0 > c.x0 || c.ty0 > c.y0 ||
+		c.tx0+c.tw <= c.x0 || c.ty0+c.th <= c.y0 {
+		return errCorrupt
+	}
+	if (c.x1-c.x0)*(c.y1-c.y0) > 1<<28 {
+		return errUnsupported
+	}
+	return nil
+}
+
+func parseCompStyle(r *segReader, precincts bool) (compStyle, error) {
+	s := compStyle{levels: r.u8(), xcb: r.u8() + 2, ycb: r.u8() + 2, cbStyle: r.u8(), reversible: r.u8() == 1}
+	if r.err != nil || s.levels > 32 || s.xcb > 10 || s.ycb > 10 || s.xcb+s.ycb > 12 {
+		return s, errCorrupt
+	}
+	if s.cbStyle&cbHTJ2K > 0 {
+		return s, errUnsupported
+	}
+	s.ppx, s.ppy = make([]int, s.levels+1), make([]int, s.levels+1)
+	for i := range s.ppx {
+		s.ppx[i], s.ppy[i] = 15, 15
+		if precincts {
+			b := r.u8()
+			s.ppx[i], s.ppy[i] = b&0x0F, b>>4
+			if i > 0 && (s.ppx[i] == 0 || s.ppy[i] == 0) {
+				return s, errCorrupt
+			}
+		}
+	}
+	if r.err != nil {
+		return s, r.err
+	}
+	return s, nil
+}
+
+func parseCOD(bb []byte) (*codingStyle, error) {
+	r := &segReader{bb: bb}
+	scod := r.u8()
+	s := &codingStyle{sop: scod&0x02 > 0, eph: scod&0x04 > 0, prog: r.u8(), layers: r.u16(), mct: r.u8() == 1}
+	if r.err != nil || s.prog > progCPRL || s.layers == 0 {
+		return nil, errCorrupt
+	}
+	var err error
+	s.compStyle, err = parseCompStyle(r, scod&0x01 > 0)
+	return s, err
+}
+
+func (c *codestream) parseCOC(bb []byte) (int, *compStyle, error) {
+	r := &segReader{bb: bb}
+	i := c.comp(r)
+	scoc := r.u8()
+	if r.err != nil || i >= len(c.comps) {
+		return 0, nil, errCorrupt
+	}
+	s, err := parseCompStyle(r, scoc&0x01 > 0)
+	return i, &s, err
+}
+
+func parseQuantization(r *segReader) (*quantization, error) {
+	sqcd := r.u8()
+	q := &quantization{style: sqcd & 0x1F, guard: sqcd >> 5}
+	switch q.style {
+	case quantNone:
+		for len(r.bb) > 0 {
+			q.eps = append(q.eps, r.u8()>>3)
+			q.mu = append(q.mu, 0)
+		}
+	case quantDerived, quantExpounded:
+		for len(r.bb) > 1 {
+			v := r.u16()
+			q.eps = append(q.eps, v>>11)
+			q.mu = append(q.mu, v&0x7FF)
+		}
+	default:
+		return nil, errCorrupt
+	}
+	if r.err != nil || len(q.eps) == 0 {
+		return nil, errCorrupt
+	}
+	return q, nil
+}
+
+func (c *codestream) parseQCC(bb []byte) (int, *quantization, error) {
+	r := &segReader{bb: bb}
+	i := c.comp(r)
+	if r.err != nil || i >= len(c.comps) {
+		return 0, nil, errCorrupt
+	}
+	q, err := parseQuantization(r)
+	return i, q, err
+}
+
+func (c *codestream) parseRGN(bb []byte) (int, int, error) {
+	r := &segReader{bb: bb}
+	i := c.comp(r)
+	r.u8() // Srgn
+	shift := r.u8()
+	if r.err != nil || i >= len(c.comps) || shift > 37 {
+		return 0, 0, errCorrupt
+	}
+	return i, shift, nil
+}
+
+func (c *codestream) parsePOC(bb []byte) ([]progression, error) {
+	r := &segReader{bb: bb}
+	var pp []progression
+	for len(r.bb) > 0 {
+		p := progression{rs: r.u8(), cs: c.comp(r), lye: r.u16(), re: r.u8(), ce: c.comp(r), prog: r.u8()}
+		if p.ce == 0 {
+			p.ce = 256
+		}
+		if r.err != nil || p.prog > progCPRL {
+			return nil, errCorrupt
+		}
+		pp = append(pp, p)
+	}
+	return pp, nil
+}
+
+// parseMarker parses a marker segment of the main header or a tile-part header into h.
+func (c *codestream) parseMarker(h *header, m int, bb []byte) error {
+	switch m {
+	case markerCOD:
+		s, err := parseCOD(bb)
+		if err != nil {
+			return err
+		}
+		h.cod = s
+	case markerCOC:
+		i, s, err := c.parseCOC(bb)
+		if err != nil {
+			return err
+		}
+		if h.coc == nil {
+			h.coc = map[int]*compStyle{}
+		}
+		h.coc[i] = s
+	case markerQCD:
+		q, err := parseQuantization(&segReader{bb: bb})
+		if err != nil {
+			return err
+		}
+		h.qcd = q
+	case markerQCC:
+		i, q, err := c.parseQCC(bb)
+		if err != nil {
+			return err
+		}
+		if h.qcc == nil {
+			h.qcc = map[int]*quantization{}
+		}
+		h.qcc[i] = q
+	case markerRGN:
+		i, shift, err := c.parseRGN(bb)
+		if err != nil {
+			return err
+		}
+		if h.rgn == nil {
+			h.rgn = map[int]int{}
+		}
+		h.rgn[i] = shift
+	case markerPOC:
+		pp, err := c.parsePOC(bb)
+		if err != nil {
+			return err
+		}
+		h.poc = append(h.poc, pp...)
+	case markerPPM, markerPPT:
+		return errUnsupported
+	}
+	// Ignore TLM, PLM, PLT, CRG, COM and unknown markers.
+	return nil
+}
+
+// parseCodestream parses the main header and, unless headerOnly, all tile-parts of bb.
+func parseCodestream(bb []byte, headerOnly bool) (*codestream, error) {
+	if len(bb) < 4 || binary.BigEndian.Uint16(bb) != markerSOC || binary.BigEndian.Uint16(bb[2:]) != markerSIZ {
+		return nil, errCorrupt
+	}
+
+	c := &codestream{}
+	pos := 2
+	for {
+		if pos+4 > len(bb) {
+			return nil, errCorrupt
+		}
+		m, l := int(binary.BigEndian.Uint16(bb[pos:])), int(binary.BigEndian.Uint16(bb[pos+2:]))
+		if m == markerSOT {
+			break
+		}
+		if l < 2 || pos+2+l > len(bb) {
+			return nil, errCorrupt
+		}
+		seg := bb[pos+4 : pos+2+l]
+		pos += 2 + l
+		if m == markerSIZ {
+			if err := c.parseSIZ(seg); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := c.parseMarker(&c.main, m, seg); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.main.cod == nil || c.main.qcd == nil {
+		return nil, errCorrupt
+	}
+
+	if headerOnly {
+		return c, nil
+	}
+
+	nx, ny := ceilDiv(c.x1-c.tx0, c.tw), ceilDiv(c.y1-c.ty0, c.th)
+	c.tiles = make([]*tile, nx*ny)
+
+	if err := c.parseTileParts(bb[pos:]); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *codestream) parseTileParts(bb []byte) error {
+	pos := 0
+	for pos+12 <= len(bb) {
+		if int(binary.BigEndian.Uint16(bb[pos:])) != markerSOT {
+			// EOC or garbage.
+			break
+		}
+		r := &segReader{bb: bb[pos+4 : pos+12]}
+		i, psot, tpsot := r.u16(), r.u32(), r.u8()
+		if i >= len(c.tiles) {
+			return errCorrupt
+		}
+		end := pos + psot
+		if psot == 0 || end > len(bb) {
+			end = len(bb)
+		}
+
+		t := c.tiles[i]
+		if t == nil {
+			t = &tile{}
+			c.tiles[i] = t
+		}
+
+		pos += 12
+		for {
+			if pos+2 > end {
+				return nil
+			}
+			m := int(binary.BigEndian.Uint16(bb[pos:]))
+			if m == markerSOD {
+				pos += 2
+				break
+			}
+			if pos+4 > end {
+				return errCorrupt
+			}
+			l := int(binary.BigEndian.Uint16(bb[pos+2:]))
+			if l < 2 || pos+2+l > end {
+				return errCorrupt
+			}
+			// Only POC may appear in tile-parts other than the first one.
+			if tpsot == 0 || m == markerPOC {
+				if err := c.parseMarker(&t.header, m, bb[pos+4:pos+2+l]); err != nil {
+					return err
+				}
+			}
+			pos += 2 + l
+		}
+
+		data := bb[pos:end]
+		if end == len(bb) && len(data) >= 2 && int(binary.BigEndian.Uint16(data[len(data)-2:])) == markerEOC {
+			data = data[:len(data)-2]
+		}
+		t.data = append(t.data, data...)
+		pos = end
+	}
+	return nil
+}
+
+// codingStyle returns the coding style of tile t.
+func (c *codestream) codingStyle(t *tile) *codingStyle {
+	if t.cod != nil {
+		return t.cod
+	}
+	return c.main.cod
+}
+
+// compStyle returns the coding style of component i in tile t.
+func (c *codestream) compStyle(t *tile, i int) *compStyle {
+	if s, ok := t.coc[i]; ok {
+		return s
+	}
+	if t.cod != nil {
+		return &t.cod.compStyle
+	}
+	if s, ok := c.main.coc[i]; ok {
+		return s
+	}
+	return &c.main.cod.compStyle
+}
+
+// quantization returns the quantization of component i in tile t.
+func (c *codestream) quantization(t *tile, i int) *quantization {
+	if q, ok := t.qcc[i]; ok {
+		return q
+	}
+	if t.qcd != nil {
+		return t.qcd
+	}
+	if q, ok := c.main.qcc[i]; ok {
+		return q
+	}
+	return c.main.qcd
+}
+
+// roiShift returns the region of interest shift of component i in tile t.
+func (c *codestream) roiShift(t *tile, i int) int {
+	if s, ok := t.rgn[i]; ok {
+		return s
+	}
+	return c.main.rgn[i]
+}
+
+// progressions returns the progression order changes of tile t.
+func (c *codestream) progressions(t *tile) []progression {
+	if len(t.poc) > 0 {
+		return t.poc
+	}
+	return c.main.poc
+}
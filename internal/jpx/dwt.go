@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+import "math"
+
+// Lifting parameters of the irreversible 9/7 filter, see Table F.4
+const (
+	alpha = -1.586134342059924
+	beta  = -0.052980118572961
+	gamma = 0.882911075530934
+	delta = 0.443506852043971
+	kappa = 1.230174104914001
+)
+
+// extension is the number of samples added on each side for periodic symmetric extension.
+const extension = 4
+
+// inverseDWT reconstructs the samples of tile-component tc from its sub-bands, see F.3
+func inverseDWT(tc *tileComp) []float32 {
+	r0 := tc.res[0]
+	a := r0.bands[0].coeffs
+	if a == nil {
+		a = make([]float32, (r0.x1-r0.x0)*(r0.y1-r0.y0))
+	}
+	ll := &band{x0: r0.x0, y0: r0.y0, x1: r0.x1, y1: r0.y1, coeffs: a}
+
+	var ext []float32
+	for _, res := range tc.res[1:] {
+		w, h := res.x1-res.x0, res.y1-res.y0
+		a = make([]float32, w*h)
+		if w == 0 || h == 0 {
+			ll = &band{x0: res.x0, y0: res.y0, x1: res.x1, y1: res.y1, coeffs: a}
+			continue
+		}
+		interleave(a, res, ll)
+
+		if n := max(w, h) + 2*extension; len(ext) < n {
+			ext = make([]float32, n)
+		}
+
+		// Horizontal synthesis.
+		for y := 0; y < h; y++ {
+			synthesize(a[y*w:(y+1)*w], res.x0, tc.style.reversible, ext)
+		}
+
+		// Vertical synthesis.
+		col := make([]float32, h)
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				col[y] = a[y*w+x]
+			}
+			synthesize(col, res.y0, tc.style.reversible, ext)
+			for y := 0; y < h; y++ {
+				a[y*w+x] = col[y]
+			}
+		}
+
+		ll = &band{x0: res.x0, y0: res.y0, x1: res.x1, y1: res.y1, coeffs: a}
+	}
+
+	return a
+}
+
+// interleave fills a with the coefficients of the sub-bands ll, HL, LH and HH of res, see F.3.3
+func interleave(a []float32, res *resolution, ll *band) {
+	bands := [4]*band{ll, res.bands[0], res.bands[1], res.bands[2]}
+	w := res.x1 - res.x0
+	for y := res.y0; y < res.y1; y++ {
+		for x := res.x0; x < res.x1; x++ {
+			b := bands[(y&1)<<1|x&1]
+			if b.coeffs == nil {
+				continue
+			}
+			// Low-pass samples are located at even, high-pass samples at odd positions.
+			bx, by := x>>1, y>>1
+			a[(y-res.y0)*w+x-res.x0] = b.coeffs[(by-b.y0)*b.width()+bx-b.x0]
+		}
+	}
+}
+
+// synthesize applies the 1D sub-band reconstruction to the interleaved signal x starting at position i0, see F.3.6
+func synthesize(x []float32, i0 int, reversible bool, ext []float32) {
+	n := len(x)
+	if n == 1 {
+		if i0&1 == 1 {
+			if reversible {
+				x[0] = float32(math.Trunc(float64(x[0]) / 2))
+			} else {
+				x[0] /= 2
+			}
+		}
+		return
+	}
+
+	// Periodic symmetric extension, see F.3.7
+	e := ext[:n+2*extension]
+	copy(e[extension:], x)
+	period := 2 * (n - 1)
+	for k := 1; k <= extension; k++ {
+		i := k % period
+		if i >= n {
+			i = period - i
+		}
+		e[extension-k] = x[i]
+		j := (n - 1 + k) % period
+		if j >= n {
+			j = period - j
+		}
+		e[extension+n-1+k] = x[j]
+	}
+
+	// e[k] is even on the reference grid if k+off is even.
+	off := (i0 - extension) & 1
+
+	if reversible {
+		for k := 1 + (1+off)&1; k < len(e)-1; k += 2 {
+			e[k] -= float32(math.Floor(float64(e[k-1]+e[k+1]+2) / 4))
+		}
+		for k := 1 + off&1; k < len(e)-1; k += 2 {
+			e[k] += float32(math.Floor(float64(e[k-1]+e[k+1]) / 2))
+		}
+	} else {
+		even, odd := off, 1-off
+		for k := even; k < len(e); k += 2 {
+			e[k] *= kappa
+		}
+		for k := odd; k < len(e); k += 2 {
+			e[k] *= 1 / kappa
+		}
+		for _, s := range []struct {
+			start int
+			c     float32
+		}{{even, delta}, {odd, gamma}, {even, beta}, {odd, alpha}} {
+			k := s.start
+			if k == 0 {
+				k = 2
+			}
+			for ; k < len(e)-1; k += 2 {
+				e[k] -= s.c * (e[k-1] + e[k+1])
+			}
+		}
+	}
+
+	copy(x, e[extension:extension+n])
+}
@@ -0,0 +1,418 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+var jp2Signature = []byte("\x00\x00\x00\x0cjP  \r\n\x87\n")
+
+// Enumerated color spaces of a JP2 colr box.
+const (
+	enumCMYK    = 12
+	enumSRGB    = 16
+	enumGray    = 17
+	enumSYCC    = 18
+	enumESRGB   = 20
+	enumROMMRGB = 21
+	enumESYCC   = 24
+)
+
+// Channel types of a JP2 cdef box.
+const (
+	channelColor         = 0
+	channelOpacity       = 1
+	channelPremultiplied = 2
+)
+
+type box struct {
+	typ  string
+	data []byte
+}
+
+// boxes splits bb into a sequence of JP2 boxes.
+func boxes(bb []byte) ([]box, error) {
+	var bx []box
+	for len(bb) > 0 {
+		if len(bb) < 8 {
+			return nil, errCorrupt
+		}
+		l, typ, hl := uint64(binary.BigEndian.Uint32(bb)), string(bb[4:8]), uint64(8)
+		switch l {
+		case 0:
+			l = uint64(len(bb))
+		case 1:
+			if len(bb) < 16 {
+				return nil, errCorrupt
+			}
+			l, hl = binary.BigEndian.Uint64(bb[8:]), 16
+		}
+		if l < hl || l > uint64(len(bb)) {
+			if typ != "jp2c" {
+				return nil, errCorrupt
+			}
+			// Be lenient about truncated codestreams.
+			l = uint64(len(bb))
+		}
+		bx = append(bx, box{typ: typ, data: bb[hl:l]})
+		bb = bb[l:]
+	}
+	return bx, nil
+}
+
+type palette struct {
+	entries [][]uint16 // entries[i][column]
+	prec    []int
+}
+
+type componentMapping struct {
+	comp, typ, col int
+}
+
+type channelDef struct {
+	typ, asoc int
+}
+
+// file represents a JP2 file or a raw codestream.
+type file struct {
+	codestream []byte
+	enumCS     int
+	icc        []byte
+	pal        *palette
+	cmap       []componentMapping
+	cdef       map[int]channelDef
+}
+
+func parseFile(bb []byte) (*file, error) {
+	if bytes.HasPrefix(bb, []byte{0xFF, 0x4F}) {
+		return &file{codestream: bb}, nil
+	}
+	if !bytes.HasPrefix(bb, jp2Signature) {
+		return nil, errCorrupt
+	}
+
+	bx, err := boxes(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &file{}
+	for _, b := range bx {
+		switch b.typ {
+		case "jp2h":
+			if err := f.parseHeader(b.data); err != nil {
+				return nil, err
+			}
+		case "jp2c":
+			if f.codestream == nil {
+				f.codestream = b.data
+			}
+		}
+	}
+
+	if f.codestream == nil {
+		return nil, errCorrupt
+	}
+
+	return f, nil
+}
+
+func (f *file) parseHeader(bb []byte) error {
+	bx, err := boxes(bb)
+	if err != nil {
+		return err
+	}
+	colr := false
+	for _, b := range bx {
+		switch b.typ {
+		case "colr":
+			// Use the first colour specification only.
+			if !colr {
+				f.parseColr(b.data)
+				colr = true
+			}
+		case "pclr":
+			if f.pal, err = parsePclr(b.data); err != nil {
+				return err
+			}
+		case "cmap":
+			for bb := b.data; len(bb) >= 4; bb = bb[4:] {
+				f.cmap = append(f.cmap, componentMapping{comp: int(binary.BigEndian.Uint16(bb)), typ: int(bb[2]), col: int(bb[3])})
+			}
+		case "cdef":
+			if len(b.data) < 2 {
+				return errCorrupt
+			}
+			n := int(binary.BigEndian.Uint16(b.data))
+			if len(b.data) < 2+6*n {
+				return errCorrupt
+			}
+			f.cdef = map[int]channelDef{}
+			for i := 0; i < n; i++ {
+				bb := b.data[2+6*i:]
+				f.cdef[int(binary.BigEndian.Uint16(bb))] = channelDef{typ: int(binary.BigEndian.Uint16(bb[2:])), asoc: int(binary.BigEndian.Uint16(bb[4:]))}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *file) parseColr(bb []byte) {
+	if len(bb) < 3 {
+		return
+	}
+	switch bb[0] {
+	case 1:
+		if len(bb) >= 7 {
+			f.enumCS = int(binary.BigEndian.Uint32(bb[3:]))
+		}
+	case 2, 3:
+		f.icc = bb[3:]
+	}
+}
+
+func parsePclr(bb []byte) (*palette, error) {
+	if len(bb) < 3 {
+		return nil, errCorrupt
+	}
+	ne, npc := int(binary.BigEndian.Uint16(bb)), int(bb[2])
+	if len(bb) < 3+npc {
+		return nil, errCorrupt
+	}
+	pal := &palette{entries: make([][]uint16, ne), prec: make([]int, npc)}
+	signed := make([]bool, npc)
+	for i := 0; i < npc; i++ {
+		pal.prec[i] = int(bb[3+i]&0x7F) + 1
+		signed[i] = bb[3+i]&0x80 > 0
+		if pal.prec[i] > 16 {
+			return nil, errUnsupported
+		}
+	}
+	bb = bb[3+npc:]
+	for i := range pal.entries {
+		pal.entries[i] = make([]uint16, npc)
+		for j := 0; j < npc; j++ {
+			n := (pal.prec[j] + 7) / 8
+			if len(bb) < n {
+				return nil, errCorrupt
+			}
+			var v uint32
+			for _, b := range bb[:n] {
+				v = v<<8 | uint32(b)
+			}
+			bb = bb[n:]
+			if signed[j] {
+				v ^= 1 << (pal.prec[j] - 1)
+			}
+			pal.entries[i][j] = uint16(v)
+		}
+	}
+	return pal, nil
+}
+
+// channels maps the decoded components cc to the image channels.
+func (f *file) channels(cc []Component) ([]Component, error) {
+	if f.pal == nil {
+		return cc, nil
+	}
+	if len(f.cmap) == 0 {
+		return nil, errCorrupt
+	}
+	ch := make([]Component, len(f.cmap))
+	for i, m := range f.cmap {
+		if m.comp >= len(cc) {
+			return nil, errCorrupt
+		}
+		c := cc[m.comp]
+		if m.typ == 0 {
+			ch[i] = c
+			continue
+		}
+		if m.col >= len(f.pal.prec) {
+			return nil, errCorrupt
+		}
+		ch[i] = Component{Precision: f.pal.prec[m.col]}
+		if c.Samples == nil {
+			continue
+		}
+		ch[i].Samples = make([]uint16, len(c.Samples))
+		for j, v := range c.Samples {
+			k := min(int(v), len(f.pal.entries)-1)
+			ch[i].Samples[j] = f.pal.entries[k][m.col]
+		}
+	}
+	return ch, nil
+}
+
+func (f *file) colorSpace(n int) ColorSpace {
+	switch f.enumCS {
+	case enumGray:
+		return ColorSpaceGray
+	case enumSRGB, enumSYCC, enumESRGB, enumROMMRGB, enumESYCC:
+		return ColorSpaceRGB
+	case enumCMYK:
+		return ColorSpaceCMYK
+	}
+	if len(f.icc) >= 20 {
+		switch string(f.icc[16:20]) {
+		case "GRAY":
+			return ColorSpaceGray
+		case "RGB ":
+			return ColorSpaceRGB
+		case "CMYK":
+			return ColorSpaceCMYK
+		}
+	}
+	switch n {
+	case 1, 2:
+		return ColorSpaceGray
+	case 3:
+		return ColorSpaceRGB
+	case 4:
+		return ColorSpaceCMYK
+	}
+	return ColorSpaceUnknown
+}
+
+func colorants(cs ColorSpace) int {
+	switch cs {
+	case ColorSpaceGray:
+		return 1
+	case ColorSpaceRGB:
+		return 3
+	case ColorSpaceCMYK:
+		return 4
+	}
+	return 0
+}
+
+// split separates color channels ordered by their association from an optional opacity channel.
+func (f *file) split(ch []Component) (cs ColorSpace, colors []Component, alpha *Component, premultiplied bool) {
+	cs = f.colorSpace(len(ch))
+
+	if f.cdef == nil {
+		n := colorants(cs)
+		if n == 0 || n > len(ch) {
+			n = len(ch)
+		}
+		if n == 1 && len(ch) == 2 {
+			alpha = &ch[1]
+		}
+		return cs, ch[:n], alpha, false
+	}
+
+	colors = make([]Component, 0, len(ch))
+	asoc := make([]int, 0, len(ch))
+	for i := range ch {
+		d, ok := f.cdef[i]
+		if !ok {
+			continue
+		}
+		switch d.typ {
+		case channelColor:
+			j := len(colors)
+			for j > 0 && asoc[j-1] > d.asoc {
+				j--
+			}
+			colors = append(colors[:j], append([]Component{ch[i]}, colors[j:]...)...)
+			asoc = append(asoc[:j], append([]int{d.asoc}, asoc[j:]...)...)
+		case channelOpacity, channelPremultiplied:
+			if alpha == nil && (d.asoc == 0 || d.asoc == math.MaxUint16) {
+				alpha = &ch[i]
+				premultiplied = d.typ == channelPremultiplied
+			}
+		}
+	}
+	if len(colors) == 0 {
+		colors = ch[:1]
+	}
+	if f.enumCS == 0 && f.icc == nil {
+		cs = f.colorSpace(len(colors))
+	}
+	return cs, colors, alpha, premultiplied
+}
+
+func (f *file) config(c *codestream) Config {
+	ch := make([]Component, len(c.comps))
+	for i, comp := range c.comps {
+		ch[i].Precision = comp.prec
+	}
+	if cc, err := f.channels(ch); err == nil {
+		ch = cc
+	}
+	cs, colors, alpha, _ := f.split(ch)
+	return Config{
+		Width:            c.x1 - c.x0,
+		Height:           c.y1 - c.y0,
+		Components:       len(colors),
+		BitsPerComponent: colors[0].Precision,
+		ColorSpace:       cs,
+		Alpha:            alpha != nil,
+	}
+}
+
+func (f *file) image(c *codestream) (*Image, error) {
+	ch, err := f.channels(c.out)
+	if err != nil {
+		return nil, err
+	}
+	cs, colors, alpha, premultiplied := f.split(ch)
+
+	if (f.enumCS == enumSYCC || f.enumCS == enumESYCC) && len(colors) >= 3 {
+		colors = yccToRGB(colors)
+	}
+
+	return &Image{
+		Width:         c.x1 - c.x0,
+		Height:        c.y1 - c.y0,
+		ColorSpace:    cs,
+		ICCProfile:    f.icc,
+		Components:    colors,
+		Alpha:         alpha,
+		Premultiplied: premultiplied,
+	}, nil
+}
+
+func yccToRGB(cc []Component) []Component {
+	p := cc[0].Precision
+	off, max := float64(int(1)<<(p-1)), float64(int(1)<<p-1)
+	rgb := []Component{{Precision: p}, {Precision: p}, {Precision: p}}
+	for i := range rgb {
+		rgb[i].Samples = make([]uint16, len(cc[0].Samples))
+	}
+	for i := range cc[0].Samples {
+		y, cb, cr := float64(cc[0].Samples[i]), float64(cc[1].Samples[i])-off, float64(cc[2].Samples[i])-off
+		rgb[0].Samples[i] = clampSample(y+1.402*cr, max)
+		rgb[1].Samples[i] = clampSample(y-0.344136*cb-0.714136*cr, max)
+		rgb[2].Samples[i] = clampSample(y+1.772*cb, max)
+	}
+	return append(rgb, cc[3:]...)
+}
+
+func clampSample(v, max float64) uint16 {
+	v = math.Round(v)
+	if v < 0 {
+		return 0
+	}
+	if v > max {
+		return uint16(max)
+	}
+	return uint16(v)
+}
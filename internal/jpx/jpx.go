@@ -0,0 +1,243 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jpx implements a decoder for JPEG 2000 images as used by the PDF filter JPXDecode.
+//
+// Supported are JP2 files and raw J2K codestreams using the reversible 5/3 and the irreversible 9/7 wavelet transformation,
+// any number of components and all progression orders.
+// See ITU-T T.800 | ISO/IEC 15444-1.
+package jpx
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ColorSpace is the color space of a decoded image.
+type ColorSpace int
+
+// Color spaces signaled by JP2 files or derived from the number of components.
+const (
+	ColorSpaceUnknown ColorSpace = iota
+	ColorSpaceGray
+	ColorSpaceRGB
+	ColorSpaceCMYK
+)
+
+func (cs ColorSpace) String() string {
+	switch cs {
+	case ColorSpaceGray:
+		return "DeviceGray"
+	case ColorSpaceRGB:
+		return "DeviceRGB"
+	case ColorSpaceCMYK:
+		return "DeviceCMYK"
+	}
+	return "Unknown"
+}
+
+var (
+	errCorrupt     = errors.New("pdfcpu: jpx: corrupt image")
+	errUnsupported = errors.New("pdfcpu: jpx: unsupported feature")
+)
+
+// Component is a decoded image channel sampled on the full image grid.
+type Component struct {
+	Precision int      // Bits per sample.
+	Samples   []uint16 // Unsigned samples, signed values are offset by 2^(Precision-1).
+}
+
+// Bytes returns the samples of c scaled to 8 bits.
+func (c Component) Bytes() []byte {
+	bb := make([]byte, len(c.Samples))
+	for i, v := range c.Samples {
+		bb[i] = scale8(v, c.Precision)
+	}
+	return bb
+}
+
+func scale8(v uint16, prec int) byte {
+	switch {
+	case prec == 8:
+		return byte(v)
+	case prec > 8:
+		return byte(v >> (prec - 8))
+	}
+	return byte(uint32(v) * 255 / (1<<prec - 1))
+}
+
+// Image is a decoded JPEG 2000 image.
+type Image struct {
+	Width, Height int
+	ColorSpace    ColorSpace
+	ICCProfile    []byte
+	Components    []Component // Color channels.
+	Alpha         *Component  // Opacity channel, optional.
+	Premultiplied bool        // Color channels are premultiplied by Alpha.
+}
+
+// Bytes returns the interleaved color samples of img scaled to 8 bits.
+func (img *Image) Bytes() []byte {
+	n := len(img.Components)
+	bb := make([]byte, img.Width*img.Height*n)
+	for j, c := range img.Components {
+		for i, v := range c.Samples {
+			bb[i*n+j] = scale8(v, c.Precision)
+		}
+	}
+	return bb
+}
+
+// Image returns img as an image.Image.
+func (img *Image) Image() image.Image {
+	r := image.Rect(0, 0, img.Width, img.Height)
+	cc := make([][]byte, len(img.Components))
+	for i, c := range img.Components {
+		cc[i] = c.Bytes()
+	}
+	var aa []byte
+	if img.Alpha != nil {
+		aa = img.Alpha.Bytes()
+	}
+
+	if img.ColorSpace == ColorSpaceCMYK && len(cc) == 4 {
+		im := image.NewCMYK(r)
+		for i := range cc[0] {
+			copy(im.Pix[4*i:], []byte{cc[0][i], cc[1][i], cc[2][i], cc[3][i]})
+		}
+		return im
+	}
+
+	if len(cc) < 3 && aa == nil {
+		im := image.NewGray(r)
+		copy(im.Pix, cc[0])
+		return im
+	}
+
+	var im draw
+	if aa != nil && !img.Premultiplied {
+		im = image.NewNRGBA(r)
+	} else {
+		im = image.NewRGBA(r)
+	}
+	for i := range cc[0] {
+		red, green, blue, a := cc[0][i], cc[0][i], cc[0][i], byte(255)
+		if len(cc) >= 3 {
+			green, blue = cc[1][i], cc[2][i]
+		}
+		if aa != nil {
+			a = aa[i]
+		}
+		x, y := i%img.Width, i/img.Width
+		switch im := im.(type) {
+		case *image.NRGBA:
+			im.SetNRGBA(x, y, color.NRGBA{red, green, blue, a})
+		case *image.RGBA:
+			im.SetRGBA(x, y, color.RGBA{red, green, blue, a})
+		}
+	}
+	return im
+}
+
+type draw interface {
+	image.Image
+	Set(x, y int, c color.Color)
+}
+
+// Config describes a JPEG 2000 image without decoding it.
+type Config struct {
+	Width, Height    int
+	Components       int // Number of color channels.
+	BitsPerComponent int
+	ColorSpace       ColorSpace
+	Alpha            bool
+}
+
+// Decode reads a JP2 file or J2K codestream from r and returns the decoded image.
+func Decode(r io.Reader) (*Image, error) {
+	bb, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseFile(bb)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := parseCodestream(f.codestream, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.decode(); err != nil {
+		return nil, err
+	}
+	return f.image(cs)
+}
+
+// DecodeConfig returns the dimensions and color model of a JPEG 2000 image read from r.
+func DecodeConfig(r io.Reader) (Config, error) {
+	bb, err := io.ReadAll(r)
+	if err != nil {
+		return Config{}, err
+	}
+	f, err := parseFile(bb)
+	if err != nil {
+		return Config{}, err
+	}
+	cs, err := parseCodestream(f.codestream, true)
+	if err != nil {
+		return Config{}, err
+	}
+	return f.config(cs), nil
+}
+
+func decodeImage(r io.Reader) (image.Image, error) {
+	img, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return img.Image(), nil
+}
+
+func decodeImageConfig(r io.Reader) (image.Config, error) {
+	c, err := DecodeConfig(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	cm := color.RGBAModel
+	switch {
+	case c.ColorSpace == ColorSpaceCMYK:
+		cm = color.CMYKModel
+	case c.Alpha:
+		cm = color.NRGBAModel
+	case c.Components < 3:
+		cm = color.GrayModel
+	}
+	return image.Config{ColorModel: cm, Width: c.Width, Height: c.Height}, nil
+}
+
+func init() {
+	image.RegisterFormat("jpx", string(jp2Signature), decodeImage, decodeImageConfig)
+	image.RegisterFormat("jpx", "\xff\x4f\xff\x51", decodeImage, decodeImageConfig)
+}
+
+// IsJPX returns true if bb starts with a JP2 signature or a J2K codestream.
+func IsJPX(bb []byte) bool {
+	return bytes.HasPrefix(bb, jp2Signature) || bytes.HasPrefix(bb, []byte{0xFF, 0x4F, 0xFF, 0x51})
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var resDir = filepath.Join("..", "..", "pkg", "testdata", "resources")
+
+func TestDecode(t *testing.T) {
+	bb, err := os.ReadFile(filepath.Join(resDir, "mountain.jpx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := DecodeConfig(bytes.NewReader(bb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Width != 1667 || c.Height != 2646 || c.Components != 3 || c.BitsPerComponent != 8 || c.ColorSpace != ColorSpaceRGB || !c.Alpha {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+
+	img, err := Decode(bytes.NewReader(bb))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(resDir, "mountain.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := img.Image()
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("bounds: got %v want %v", got.Bounds(), want.Bounds())
+	}
+
+	// mountain.jpx has been encoded lossy.
+	var sum float64
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r1, g1, b1, _ := want.At(x, y).RGBA()
+			r2, g2, b2, _ := got.At(x, y).RGBA()
+			sum += math.Abs(float64(r1>>8)-float64(r2>>8)) + math.Abs(float64(g1>>8)-float64(g2>>8)) + math.Abs(float64(b1>>8)-float64(b2>>8))
+		}
+	}
+	if mad := sum / float64(3*b.Dx()*b.Dy()); mad > 2 {
+		t.Fatalf("mean absolute difference too large: %.2f", mad)
+	}
+
+	// Decoding via the image package.
+	if _, format, err := image.DecodeConfig(bytes.NewReader(bb)); err != nil || format != "jpx" {
+		t.Fatalf("image.DecodeConfig: format=%s err=%v", format, err)
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	for _, bb := range [][]byte{
+		nil,
+		[]byte("no jpx"),
+		jp2Signature,
+		{0xFF, 0x4F, 0xFF, 0x51, 0x00},
+	} {
+		if _, err := Decode(bytes.NewReader(bb)); err == nil {
+			t.Fatalf("expected error for %v", bb)
+		}
+	}
+}
+
+// analyze53 applies the forward reversible 5/3 transformation to the signal x starting at i0.
+func analyze53(x []float32, i0 int) []float32 {
+	n := len(x)
+	y := make([]float32, n)
+	at := func(v []float32, i int) float32 {
+		p := 2 * (n - 1)
+		k := ((i-i0)%p + p) % p
+		if k >= n {
+			k = p - k
+		}
+		return v[k]
+	}
+	for i := i0; i < i0+n; i++ {
+		if i&1 == 1 {
+			y[i-i0] = x[i-i0] - float32(math.Floor(float64(at(x, i-1)+at(x, i+1))/2))
+		}
+	}
+	for i := i0; i < i0+n; i++ {
+		if i&1 == 0 {
+			y[i-i0] = x[i-i0] + float32(math.Floor(float64(at(y, i-1)+at(y, i+1)+2)/4))
+		}
+	}
+	return y
+}
+
+// analyze97 applies the forward irreversible 9/7 transformation to the signal x starting at i0.
+func analyze97(x []float32, i0 int) []float32 {
+	n := len(x)
+	y := append([]float32(nil), x...)
+	at := func(i int) float32 {
+		p := 2 * (n - 1)
+		k := ((i-i0)%p + p) % p
+		if k >= n {
+			k = p - k
+		}
+		return y[k]
+	}
+	for _, s := range []struct {
+		parity int
+		c      float32
+	}{{1, alpha}, {0, beta}, {1, gamma}, {0, delta}} {
+		z := append([]float32(nil), y...)
+		for i := i0; i < i0+n; i++ {
+			if i&1 == s.parity {
+				z[i-i0] = y[i-i0] + s.c*(at(i-1)+at(i+1))
+			}
+		}
+		y = z
+	}
+	for i := i0; i < i0+n; i++ {
+		if i&1 == 0 {
+			y[i-i0] /= kappa
+		} else {
+			y[i-i0] *= kappa
+		}
+	}
+	return y
+}
+
+func TestSynthesize(t *testing.T) {
+	ext := make([]float32, 64)
+	for _, n := range []int{2, 3, 4, 7, 16, 17} {
+		for i0 := 0; i0 < 3; i0++ {
+			x := make([]float32, n)
+			for i := range x {
+				x[i] = float32((i*37)%23 - 11)
+			}
+
+			y := analyze53(x, i0)
+			synthesize(y, i0, true, ext)
+			for i := range x {
+				if y[i] != x[i] {
+					t.Fatalf("5/3 n=%d i0=%d: got %v want %v", n, i0, y, x)
+				}
+			}
+
+			y = analyze97(x, i0)
+			synthesize(y, i0, false, ext)
+			for i := range x {
+				if math.Abs(float64(y[i]-x[i])) > 1e-3 {
+					t.Fatalf("9/7 n=%d i0=%d: got %v want %v", n, i0, y, x)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,456 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+import (
+	"math/bits"
+)
+
+// bitReader reads packet header bits honoring bit stuffing, see B.10.1
+type bitReader struct {
+	data []byte
+	pos  int
+	buf  uint
+	ct   int
+}
+
+func (r *bitReader) readBit() (int, error) {
+	if r.ct == 0 {
+		if r.pos >= len(r.data) {
+			return 0, errCorrupt
+		}
+		r.ct = 8
+		if r.buf == 0xFF {
+			r.ct = 7
+		}
+		r.buf = uint(r.data[r.pos])
+		r.pos++
+	}
+	r.ct--
+	return int(r.buf>>r.ct) & 1, nil
+}
+
+func (r *bitReader) readBits(n int) (int, error) {
+	v := 0
+	for ; n > 0; n-- {
+		b, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | b
+	}
+	return v, nil
+}
+
+// align skips the remaining bits of the packet header.
+func (r *bitReader) align() {
+	if r.buf == 0xFF && r.pos < len(r.data) {
+		// Skip the byte holding the stuffed bit.
+		r.pos++
+	}
+	r.buf, r.ct = 0, 0
+}
+
+// tagTree decodes tag trees, see B.10.2
+type tagTree struct {
+	nodes []tagNode
+}
+
+type tagNode struct {
+	parent     int
+	value, low int
+}
+
+func newTagTree(w, h int) *tagTree {
+	type level struct{ w, h, off int }
+	var ll []level
+	n := 0
+	for {
+		ll = append(ll, level{w, h, n})
+		n += w * h
+		if w*h <= 1 {
+			break
+		}
+		w, h = (w+1)/2, (h+1)/2
+	}
+
+	t := &tagTree{nodes: make([]tagNode, n)}
+	for i, l := range ll {
+		for y := 0; y < l.h; y++ {
+			for x := 0; x < l.w; x++ {
+				nd := &t.nodes[l.off+y*l.w+x]
+				nd.value, nd.parent = 1<<30, -1
+				if i+1 < len(ll) {
+					p := ll[i+1]
+					nd.parent = p.off + y/2*p.w + x/2
+				}
+			}
+		}
+	}
+	return t
+}
+
+// decode returns true if the value of leaf i is less than threshold.
+func (t *tagTree) decode(r *bitReader, i, threshold int) (bool, error) {
+	var stack [32]int
+	sp := 0
+	for t.nodes[i].parent >= 0 {
+		stack[sp] = i
+		sp++
+		i = t.nodes[i].parent
+	}
+
+	low := 0
+	for {
+		nd := &t.nodes[i]
+		if low > nd.low {
+			nd.low = low
+		} else {
+			low = nd.low
+		}
+		for low < threshold && low < nd.value {
+			b, err := r.readBit()
+			if err != nil {
+				return false, err
+			}
+			if b == 1 {
+				nd.value = low
+			} else {
+				low++
+			}
+		}
+		nd.low = low
+		if sp == 0 {
+			return nd.value < threshold, nil
+		}
+		sp--
+		i = stack[sp]
+	}
+}
+
+type packetDecoder struct {
+	data     []byte
+	pos      int
+	sop, eph bool
+}
+
+// numPasses decodes the number of coding passes, see Table B.4
+func numPasses(r *bitReader) (int, error) {
+	for _, p := range []struct{ bits, base, esc int }{{1, 1, 1}, {1, 2, 1}, {2, 3, 3}, {5, 6, 31}, {7, 37, -1}} {
+		v, err := r.readBits(p.bits)
+		if err != nil {
+			return 0, err
+		}
+		if p.bits == 1 {
+			// Single bit codewords.
+			if v == 0 {
+				return p.base, nil
+			}
+			continue
+		}
+		if v != p.esc {
+			return p.base + v, nil
+		}
+	}
+	return 0, errCorrupt
+}
+
+// addSegment appends a codeword segment to cb, see D.4.1
+func (cb *codeBlock) addSegment(cbStyle int) {
+	maxPasses := 109
+	switch {
+	case cbStyle&cbTermAll > 0:
+		maxPasses = 1
+	case cbStyle&cbLazy > 0:
+		maxPasses = 10
+		if n := len(cb.segs); n > 0 {
+			maxPasses = 1
+			if prev := cb.segs[n-1].maxPasses; prev == 1 || prev == 10 {
+				maxPasses = 2
+			}
+		}
+	}
+	cb.segs = append(cb.segs, segment{maxPasses: maxPasses})
+}
+
+// decodePacket reads the packet for layer l of precinct prc, see B.10
+func (d *packetDecoder) decodePacket(prc *precinct, l, cbStyle int) error {
+	if d.sop && d.pos+6 <= len(d.data) && d.data[d.pos] == 0xFF && d.data[d.pos+1] == markerSOP&0xFF {
+		d.pos += 6
+	}
+
+	r := &bitReader{data: d.data, pos: d.pos}
+	present, err := r.readBit()
+	if err != nil {
+		return err
+	}
+
+	type contribution struct {
+		cb     *codeBlock
+		seg, n int
+	}
+	var cc []contribution
+
+	if present == 1 {
+		for _, pb := range prc.bands {
+			for i, cb := range pb.blocks {
+				if !cb.included {
+					ok, err := pb.incl.decode(r, i, l+1)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						continue
+					}
+					n := 1
+					for {
+						ok, err := pb.zbp.decode(r, i, n)
+						if err != nil {
+							return err
+						}
+						if ok {
+							break
+						}
+						if n++; n > 64 {
+							return errCorrupt
+						}
+					}
+					cb.zeroBitPlanes = n - 1
+					cb.included = true
+				} else {
+					b, err := r.readBit()
+					if err != nil {
+						return err
+					}
+					if b == 0 {
+						continue
+					}
+				}
+
+				passes, err := numPasses(r)
+				if err != nil {
+					return err
+				}
+				for {
+					b, err := r.readBit()
+					if err != nil {
+						return err
+					}
+					if b == 0 {
+						break
+					}
+					cb.lblock++
+				}
+
+				if n := len(cb.segs); n == 0 || cb.segs[n-1].passes == cb.segs[n-1].maxPasses {
+					cb.addSegment(cbStyle)
+				}
+				for passes > 0 {
+					i := len(cb.segs) - 1
+					s := &cb.segs[i]
+					k := min(s.maxPasses-s.passes, passes)
+					n, err := r.readBits(cb.lblock + bits.Len(uint(k)) - 1)
+					if err != nil {
+						return err
+					}
+					s.passes += k
+					cc = append(cc, contribution{cb: cb, seg: i, n: n})
+					if passes -= k; passes > 0 {
+						cb.addSegment(cbStyle)
+					}
+				}
+			}
+		}
+	}
+
+	r.align()
+	d.pos = r.pos
+
+	if d.eph && d.pos+2 <= len(d.data) && d.data[d.pos] == 0xFF && d.data[d.pos+1] == markerEPH&0xFF {
+		d.pos += 2
+	}
+
+	for _, c := range cc {
+		end := d.pos + c.n
+		if end > len(d.data) {
+			end = len(d.data)
+		}
+		s := &c.cb.segs[c.seg]
+		s.data = append(s.data, d.data[d.pos:end]...)
+		d.pos = end
+	}
+
+	return nil
+}
+
+type packet struct {
+	l, r, c, p int
+}
+
+// packets returns the sequence of packets of a tile according to its progression order and progression order changes, see B.12
+func (c *codestream) packets(t *tile, tcs []*tileComp, layers int) []packet {
+	maxRes := 0
+	for _, tc := range tcs {
+		maxRes = max(maxRes, len(tc.res))
+	}
+
+	pp := c.progressions(t)
+	if len(pp) == 0 {
+		pp = []progression{{rs: 0, cs: 0, lye: layers, re: maxRes, ce: len(tcs), prog: c.codingStyle(t).prog}}
+	}
+
+	var pkts []packet
+
+	emit := func(l, r, ci, p int) {
+		prc := tcs[ci].res[r].precincts[p]
+		if l < prc.nextLayer {
+			return
+		}
+		prc.nextLayer = l + 1
+		pkts = append(pkts, packet{l: l, r: r, c: ci, p: p})
+	}
+
+	for _, po := range pp {
+		lye, re, ce := min(po.lye, layers), min(po.re, maxRes), min(po.ce, len(tcs))
+		if po.rs >= re || po.cs >= ce {
+			continue
+		}
+
+		switch po.prog {
+
+		case progLRCP:
+			for l := 0; l < lye; l++ {
+				for r := po.rs; r < re; r++ {
+					for ci := po.cs; ci < ce; ci++ {
+						if r < len(tcs[ci].res) {
+							for p := range tcs[ci].res[r].precincts {
+								emit(l, r, ci, p)
+							}
+						}
+					}
+				}
+			}
+
+		case progRLCP:
+			for r := po.rs; r < re; r++ {
+				for l := 0; l < lye; l++ {
+					for ci := po.cs; ci < ce; ci++ {
+						if r < len(tcs[ci].res) {
+							for p := range tcs[ci].res[r].precincts {
+								emit(l, r, ci, p)
+							}
+						}
+					}
+				}
+			}
+
+		case progRPCL:
+			dx, dy := precinctSteps(tcs[po.cs:ce], po.rs, re)
+			for r := po.rs; r < re; r++ {
+				forEachPosition(t, dx, dy, func(x, y int) {
+					for ci := po.cs; ci < ce; ci++ {
+						if p, ok := precinctAt(t, tcs[ci], r, x, y); ok {
+							for l := 0; l < lye; l++ {
+								emit(l, r, ci, p)
+							}
+						}
+					}
+				})
+			}
+
+		case progPCRL:
+			dx, dy := precinctSteps(tcs[po.cs:ce], po.rs, re)
+			forEachPosition(t, dx, dy, func(x, y int) {
+				for ci := po.cs; ci < ce; ci++ {
+					for r := po.rs; r < re; r++ {
+						if p, ok := precinctAt(t, tcs[ci], r, x, y); ok {
+							for l := 0; l < lye; l++ {
+								emit(l, r, ci, p)
+							}
+						}
+					}
+				}
+			})
+
+		case progCPRL:
+			for ci := po.cs; ci < ce; ci++ {
+				dx, dy := precinctSteps(tcs[ci:ci+1], po.rs, re)
+				forEachPosition(t, dx, dy, func(x, y int) {
+					for r := po.rs; r < re; r++ {
+						if p, ok := precinctAt(t, tcs[ci], r, x, y); ok {
+							for l := 0; l < lye; l++ {
+								emit(l, r, ci, p)
+							}
+						}
+					}
+				})
+			}
+		}
+	}
+
+	return pkts
+}
+
+// precinctSteps returns the smallest precinct dimensions on the reference grid.
+func precinctSteps(tcs []*tileComp, rs, re int) (int, int) {
+	dx, dy := 1<<30, 1<<30
+	for _, tc := range tcs {
+		for r := rs; r < min(re, len(tc.res)); r++ {
+			l := len(tc.res) - 1 - r
+			res := tc.res[r]
+			dx = min(dx, tc.dx<<(res.ppx+l))
+			dy = min(dy, tc.dy<<(res.ppy+l))
+		}
+	}
+	return dx, dy
+}
+
+func forEachPosition(t *tile, dx, dy int, f func(x, y int)) {
+	if dx <= 0 || dy <= 0 || dx >= 1<<30 || dy >= 1<<30 {
+		return
+	}
+	for y := t.y0; y < t.y1; y += dy - y%dy {
+		for x := t.x0; x < t.x1; x += dx - x%dx {
+			f(x, y)
+		}
+	}
+}
+
+// precinctAt returns the precinct of resolution r of tc starting at reference grid position x,y.
+func precinctAt(t *tile, tc *tileComp, r, x, y int) (int, bool) {
+	if r >= len(tc.res) {
+		return 0, false
+	}
+	res := tc.res[r]
+	if res.pw == 0 || res.ph == 0 {
+		return 0, false
+	}
+	l := len(tc.res) - 1 - r
+	rpx, rpy := res.ppx+l, res.ppy+l
+	if !(y%(tc.dy<<rpy) == 0 || (y == t.y0 && (res.y0<<l)%(1<<rpy) != 0)) {
+		return 0, false
+	}
+	if !(x%(tc.dx<<rpx) == 0 || (x == t.x0 && (res.x0<<l)%(1<<rpx) != 0)) {
+		return 0, false
+	}
+	i := floorDivPow2(ceilDiv(x, tc.dx<<l), res.ppx) - floorDivPow2(res.x0, res.ppx)
+	j := floorDivPow2(ceilDiv(y, tc.dy<<l), res.ppy) - floorDivPow2(res.y0, res.ppy)
+	if i < 0 || i >= res.pw || j < 0 || j >= res.ph {
+		return 0, false
+	}
+	return j*res.pw + i, true
+}
@@ -0,0 +1,530 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+// qeEntry is a probability estimation state of the MQ decoder, see Table C.2
+type qeEntry struct {
+	qe         uint32
+	nmps, nlps uint8
+	switchMPS  bool
+}
+
+var qeTable = [47]qeEntry{
+	{0x5601, 1, 1, true}, {0x3401, 2, 6, false}, {0x1801, 3, 9, false}, {0x0AC1, 4, 12, false},
+	{0x0521, 5, 29, false}, {0x0221, 38, 33, false}, {0x5601, 7, 6, true}, {0x5401, 8, 14, false},
+	{0x4801, 9, 14, false}, {0x3801, 10, 14, false}, {0x3001, 11, 17, false}, {0x2401, 12, 18, false},
+	{0x1C01, 13, 20, false}, {0x1601, 29, 21, false}, {0x5601, 15, 14, true}, {0x5401, 16, 14, false},
+	{0x5101, 17, 15, false}, {0x4801, 18, 16, false}, {0x3801, 19, 17, false}, {0x3401, 20, 18, false},
+	{0x3001, 21, 19, false}, {0x2801, 22, 19, false}, {0x2401, 23, 20, false}, {0x2201, 24, 21, false},
+	{0x1C01, 25, 22, false}, {0x1801, 26, 23, false}, {0x1601, 27, 24, false}, {0x1401, 28, 25, false},
+	{0x1201, 29, 26, false}, {0x1101, 30, 27, false}, {0x0AC1, 31, 28, false}, {0x09C1, 32, 29, false},
+	{0x08A1, 33, 30, false}, {0x0521, 34, 31, false}, {0x0441, 35, 32, false}, {0x02A1, 36, 33, false},
+	{0x0221, 37, 34, false}, {0x0141, 38, 35, false}, {0x0111, 39, 36, false}, {0x0085, 40, 37, false},
+	{0x0049, 41, 38, false}, {0x0025, 42, 39, false}, {0x0015, 43, 40, false}, {0x0009, 44, 41, false},
+	{0x0005, 45, 42, false}, {0x0001, 45, 43, false}, {0x5601, 46, 46, false},
+}
+
+// mqContext holds the state index and the more probable symbol of a context.
+type mqContext struct {
+	index uint8
+	mps   uint8
+}
+
+// mqDecoder is the arithmetic decoder, see Annex C.3
+type mqDecoder struct {
+	data []byte
+	bp   int
+	a, c uint32
+	ct   int
+}
+
+func (d *mqDecoder) byteAt(i int) uint32 {
+	if i < len(d.data) {
+		return uint32(d.data[i])
+	}
+	return 0xFF
+}
+
+func (d *mqDecoder) init(data []byte) {
+	d.data, d.bp = data, 0
+	d.c = d.byteAt(0) << 16
+	d.byteIn()
+	d.c <<= 7
+	d.ct -= 7
+	d.a = 0x8000
+}
+
+func (d *mqDecoder) byteIn() {
+	if d.byteAt(d.bp) == 0xFF {
+		if d.byteAt(d.bp+1) > 0x8F {
+			d.c += 0xFF00
+			d.ct = 8
+		} else {
+			d.bp++
+			d.c += d.byteAt(d.bp) << 9
+			d.ct = 7
+		}
+		return
+	}
+	d.bp++
+	d.c += d.byteAt(d.bp) << 8
+	d.ct = 8
+}
+
+func (d *mqDecoder) decode(cx *mqContext) int {
+	q := qeTable[cx.index]
+	d.a -= q.qe
+	var bit int
+	if d.c>>16 < q.qe {
+		// LPS exchange
+		if d.a < q.qe {
+			bit = int(cx.mps)
+			cx.index = q.nmps
+		} else {
+			bit = int(1 - cx.mps)
+			if q.switchMPS {
+				cx.mps = 1 - cx.mps
+			}
+			cx.index = q.nlps
+		}
+		d.a = q.qe
+	} else {
+		d.c -= q.qe << 16
+		if d.a&0x8000 != 0 {
+			return int(cx.mps)
+		}
+		// MPS exchange
+		if d.a < q.qe {
+			bit = int(1 - cx.mps)
+			if q.switchMPS {
+				cx.mps = 1 - cx.mps
+			}
+			cx.index = q.nlps
+		} else {
+			bit = int(cx.mps)
+			cx.index = q.nmps
+		}
+	}
+	for d.a&0x8000 == 0 {
+		if d.ct == 0 {
+			d.byteIn()
+		}
+		d.a <<= 1
+		d.c <<= 1
+		d.ct--
+	}
+	return bit
+}
+
+// rawDecoder reads the bits of raw coding passes (arithmetic coding bypass), see D.6
+type rawDecoder struct {
+	data []byte
+	pos  int
+	c    uint32
+	ct   int
+}
+
+func (d *rawDecoder) init(data []byte) {
+	d.data, d.pos, d.c, d.ct = data, 0, 0, 0
+}
+
+func (d *rawDecoder) decode() int {
+	if d.ct == 0 {
+		b := uint32(0xFF)
+		if d.pos < len(d.data) {
+			b = uint32(d.data[d.pos])
+			d.pos++
+		}
+		d.ct = 8
+		if d.c == 0xFF {
+			d.ct = 7
+		}
+		d.c = b
+	}
+	d.ct--
+	return int(d.c>>d.ct) & 1
+}
+
+// Context labels, see Table D.7
+const (
+	ctxSC      = 9
+	ctxMR      = 14
+	ctxRL      = 17
+	ctxUniform = 18
+	numCtx     = 19
+)
+
+// Coefficient state flags.
+const (
+	fSigN uint16 = 1 << iota
+	fSigS
+	fSigW
+	fSigE
+	fSigNW
+	fSigNE
+	fSigSW
+	fSigSE
+	fNegN
+	fNegS
+	fNegW
+	fNegE
+	fSig     // Coefficient is significant.
+	fVisited // Coefficient has been coded in the current bit plane.
+	fRefined // Coefficient has been refined.
+	fNeg     // Coefficient is negative.
+
+	fNeighbours = fSigN | fSigS | fSigW | fSigE | fSigNW | fSigNE | fSigSW | fSigSE
+	fSouth      = fSigS | fSigSW | fSigSE | fNegS
+)
+
+// zcContexts maps the significance of the 8 neighbours to the zero coding context per sub-band orientation, see Table D.1
+var zcContexts [4][256]uint8
+
+func init() {
+	for o := 0; o < 4; o++ {
+		for f := 0; f < 256; f++ {
+			m := uint16(f)
+			bit := func(b uint16) int {
+				if m&b > 0 {
+					return 1
+				}
+				return 0
+			}
+			h, v := bit(fSigW)+bit(fSigE), bit(fSigN)+bit(fSigS)
+			d := bit(fSigNW) + bit(fSigNE) + bit(fSigSW) + bit(fSigSE)
+			if o == bandHL {
+				h, v = v, h
+			}
+			var cx int
+			if o == bandHH {
+				hv := h + v
+				switch {
+				case d >= 3:
+					cx = 8
+				case d == 2:
+					cx = 6
+					if hv >= 1 {
+						cx = 7
+					}
+				case d == 1:
+					cx = 3 + min(hv, 2)
+				default:
+					cx = min(hv, 2)
+				}
+			} else {
+				switch {
+				case h == 2:
+					cx = 8
+				case h == 1:
+					cx = 5
+					if v >= 1 {
+						cx = 7
+					} else if d >= 1 {
+						cx = 6
+					}
+				case v == 2:
+					cx = 4
+				case v == 1:
+					cx = 3
+				default:
+					cx = min(d, 2)
+				}
+			}
+			zcContexts[o][f] = uint8(cx)
+		}
+	}
+}
+
+// signContext returns the sign coding context and the XOR bit, see Table D.3
+func signContext(f uint16) (int, int) {
+	contrib := func(sig, neg uint16) int {
+		switch {
+		case f&sig == 0:
+			return 0
+		case f&neg > 0:
+			return -1
+		}
+		return 1
+	}
+	h := max(-1, min(1, contrib(fSigW, fNegW)+contrib(fSigE, fNegE)))
+	v := max(-1, min(1, contrib(fSigN, fNegN)+contrib(fSigS, fNegS)))
+	xor := 0
+	if h < 0 || (h == 0 && v < 0) {
+		h, v, xor = -h, -v, 1
+	}
+	if h == 0 {
+		return ctxSC + v, xor
+	}
+	return ctxSC + 3 + v, xor
+}
+
+// t1Decoder decodes code-blocks, see Annex D.
+type t1Decoder struct {
+	w, h   int
+	flags  []uint16 // Padded by one coefficient on each side.
+	mag    []uint32
+	plane  []int8 // Lowest decoded bit plane.
+	mq     mqDecoder
+	raw    rawDecoder
+	ctx    [numCtx]mqContext
+	orient int
+	style  int
+}
+
+func (t *t1Decoder) reset(w, h int) {
+	t.w, t.h = w, h
+	n := (w + 2) * (h + 2)
+	if cap(t.flags) < n {
+		t.flags = make([]uint16, n)
+	}
+	t.flags = t.flags[:n]
+	clear(t.flags)
+	if cap(t.mag) < w*h {
+		t.mag, t.plane = make([]uint32, w*h), make([]int8, w*h)
+	}
+	t.mag, t.plane = t.mag[:w*h], t.plane[:w*h]
+	clear(t.mag)
+	t.resetContexts()
+}
+
+func (t *t1Decoder) resetContexts() {
+	t.ctx = [numCtx]mqContext{}
+	t.ctx[0].index = 4
+	t.ctx[ctxRL].index = 3
+	t.ctx[ctxUniform].index = 46
+}
+
+// neighbours returns the flags of coefficient x,y taking into account vertically causal context formation.
+func (t *t1Decoder) neighbours(i, y int) uint16 {
+	f := t.flags[i]
+	if t.style&cbVCausal > 0 && y%4 == 3 {
+		f &^= fSouth
+	}
+	return f
+}
+
+// setSignificant marks coefficient x,y significant at bit plane p.
+func (t *t1Decoder) setSignificant(x, y, p int, neg bool) {
+	s := t.w + 2
+	i := (y+1)*s + x + 1
+	t.flags[i] |= fSig
+	j := y*t.w + x
+	t.mag[j], t.plane[j] = 1<<p, int8(p)
+
+	var nn, ns, nw, ne uint16
+	if neg {
+		t.flags[i] |= fNeg
+		nn, ns, nw, ne = fNegS, fNegN, fNegE, fNegW
+	}
+	t.flags[i-s] |= fSigS | nn
+	t.flags[i+s] |= fSigN | ns
+	t.flags[i-1] |= fSigE | nw
+	t.flags[i+1] |= fSigW | ne
+	t.flags[i-s-1] |= fSigSE
+	t.flags[i-s+1] |= fSigSW
+	t.flags[i+s-1] |= fSigNE
+	t.flags[i+s+1] |= fSigNW
+}
+
+func (t *t1Decoder) decodeSign(i, y int, raw bool) bool {
+	if raw {
+		return t.raw.decode() == 1
+	}
+	cx, xor := signContext(t.neighbours(i, y))
+	return t.mq.decode(&t.ctx[cx])^xor == 1
+}
+
+// significancePass decodes a significance propagation pass, see D.3.1
+func (t *t1Decoder) significancePass(p int, raw bool) {
+	s := t.w + 2
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			for y := y0; y < min(y0+4, t.h); y++ {
+				i := (y+1)*s + x + 1
+				f := t.neighbours(i, y)
+				if f&fSig > 0 || f&fNeighbours == 0 {
+					continue
+				}
+				var b int
+				if raw {
+					b = t.raw.decode()
+				} else {
+					b = t.mq.decode(&t.ctx[zcContexts[t.orient][f&fNeighbours]])
+				}
+				if b == 1 {
+					t.setSignificant(x, y, p, t.decodeSign(i, y, raw))
+				}
+				t.flags[i] |= fVisited
+			}
+		}
+	}
+}
+
+// refinementPass decodes a magnitude refinement pass, see D.3.3
+func (t *t1Decoder) refinementPass(p int, raw bool) {
+	s := t.w + 2
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			for y := y0; y < min(y0+4, t.h); y++ {
+				i := (y+1)*s + x + 1
+				f := t.neighbours(i, y)
+				if f&fSig == 0 || f&fVisited > 0 {
+					continue
+				}
+				var b int
+				if raw {
+					b = t.raw.decode()
+				} else {
+					cx := ctxMR + 2
+					if f&fRefined == 0 {
+						cx = ctxMR
+						if f&fNeighbours > 0 {
+							cx = ctxMR + 1
+						}
+					}
+					b = t.mq.decode(&t.ctx[cx])
+				}
+				j := y*t.w + x
+				t.mag[j] |= uint32(b) << p
+				t.plane[j] = int8(p)
+				t.flags[i] |= fRefined
+			}
+		}
+	}
+}
+
+// cleanupPass decodes a cleanup pass, see D.3.4
+func (t *t1Decoder) cleanupPass(p int) {
+	s := t.w + 2
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			y := y0
+			if y0+4 <= t.h {
+				runLength := true
+				for k := 0; k < 4; k++ {
+					if t.neighbours((y0+k+1)*s+x+1, y0+k)&(fSig|fVisited|fNeighbours) > 0 {
+						runLength = false
+						break
+					}
+				}
+				if runLength {
+					if t.mq.decode(&t.ctx[ctxRL]) == 0 {
+						continue
+					}
+					y += t.mq.decode(&t.ctx[ctxUniform])<<1 | t.mq.decode(&t.ctx[ctxUniform])
+					t.setSignificant(x, y, p, t.decodeSign((y+1)*s+x+1, y, false))
+					y++
+				}
+			}
+			for ; y < min(y0+4, t.h); y++ {
+				i := (y+1)*s + x + 1
+				f := t.neighbours(i, y)
+				if f&(fSig|fVisited) > 0 {
+					continue
+				}
+				if t.mq.decode(&t.ctx[zcContexts[t.orient][f&fNeighbours]]) == 1 {
+					t.setSignificant(x, y, p, t.decodeSign(i, y, false))
+				}
+			}
+		}
+	}
+
+	for i := range t.flags {
+		t.flags[i] &^= fVisited
+	}
+
+	if t.style&cbSegSym > 0 {
+		for k := 0; k < 4; k++ {
+			t.mq.decode(&t.ctx[ctxUniform])
+		}
+	}
+}
+
+// decode decodes the coding passes of code-block cb and stores the dequantized coefficients in sub-band b.
+func (t *t1Decoder) decode(cb *codeBlock, b *band, style, roi int, reversible bool) {
+	w, h := cb.x1-cb.x0, cb.y1-cb.y0
+	if w <= 0 || h <= 0 || len(cb.segs) == 0 {
+		return
+	}
+	numbps := b.mb + roi - cb.zeroBitPlanes
+	if numbps <= 0 || numbps > 31 {
+		return
+	}
+
+	t.reset(w, h)
+	t.orient, t.style = b.orient, style
+
+	k := 0
+	for _, seg := range cb.segs {
+		if seg.passes == 0 {
+			continue
+		}
+		raw := style&cbLazy > 0 && k >= 10 && k%3 != 0
+		if raw {
+			t.raw.init(seg.data)
+		} else {
+			t.mq.init(seg.data)
+		}
+		for n := 0; n < seg.passes; n, k = n+1, k+1 {
+			p := numbps - 1 - (k+2)/3
+			if p < 0 {
+				break
+			}
+			switch k % 3 {
+			case 0:
+				t.cleanupPass(p)
+			case 1:
+				t.significancePass(p, raw)
+			case 2:
+				t.refinementPass(p, raw)
+			}
+			if style&cbReset > 0 {
+				t.resetContexts()
+			}
+		}
+	}
+
+	t.store(cb, b, roi, reversible)
+}
+
+// store dequantizes the decoded magnitudes into the coefficients of sub-band b, see E.1.1
+func (t *t1Decoder) store(cb *codeBlock, b *band, roi int, reversible bool) {
+	s := t.w + 2
+	bw := b.width()
+	for y := 0; y < t.h; y++ {
+		for x := 0; x < t.w; x++ {
+			j := y*t.w + x
+			m, p := t.mag[j], int(t.plane[j])
+			if m == 0 {
+				continue
+			}
+			if roi > 0 && m >= 1<<roi {
+				m >>= roi
+				p = max(p-roi, 0)
+			}
+			v := float32(m)
+			if !reversible && p > 0 {
+				v += float32(uint32(1) << (p - 1))
+			}
+			v *= b.delta
+			if t.flags[(y+1)*s+x+1]&fNeg > 0 {
+				v = -v
+			}
+			b.coeffs[(cb.y0-b.y0+y)*bw+cb.x0-b.x0+x] = v
+		}
+	}
+}
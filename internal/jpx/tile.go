@@ -0,0 +1,372 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jpx
+
+import (
+	"math"
+)
+
+// Sub-band orientations.
+const (
+	bandLL = iota
+	bandHL
+	bandLH
+	bandHH
+)
+
+type segment struct {
+	data      []byte
+	passes    int
+	maxPasses int
+}
+
+type codeBlock struct {
+	x0, y0, x1, y1 int
+	included       bool
+	lblock         int
+	zeroBitPlanes  int
+	segs           []segment
+}
+
+type band struct {
+	orient         int
+	x0, y0, x1, y1 int
+	mb             int     // Number of magnitude bit planes.
+	delta          float32 // Quantization step size.
+	coeffs         []float32
+}
+
+func (b *band) width() int {
+	return b.x1 - b.x0
+}
+
+// precinctBand holds the code-blocks of a precinct within one sub-band.
+type precinctBand struct {
+	band      *band
+	cw, ch    int
+	blocks    []*codeBlock
+	incl, zbp *tagTree
+}
+
+type precinct struct {
+	bands     []*precinctBand
+	nextLayer int
+}
+
+type resolution struct {
+	x0, y0, x1, y1 int
+	ppx, ppy       int
+	pw, ph         int
+	bands          []*band
+	precincts      []*precinct
+}
+
+type tileComp struct {
+	x0, y0, x1, y1 int
+	dx, dy         int
+	style          *compStyle
+	roi            int
+	res            []*resolution
+}
+
+func floorDivPow2(a, n int) int {
+	return a >> n
+}
+
+func ceilDivPow2(a, n int) int {
+	return (a + 1<<n - 1) >> n
+}
+
+// newTileComp sets up the resolutions, sub-bands, precincts and code-blocks of component i in tile t.
+func (c *codestream) newTileComp(t *tile, i int) (*tileComp, error) {
+	comp := c.comps[i]
+	s := c.compStyle(t, i)
+	q := c.quantization(t, i)
+
+	tc := &tileComp{
+		x0: ceilDiv(t.x0, comp.dx), y0: ceilDiv(t.y0, comp.dy),
+		x1: ceilDiv(t.x1, comp.dx), y1: ceilDiv(t.y1, comp.dy),
+		dx: comp.dx, dy: comp.dy,
+		style: s,
+		roi:   c.roiShift(t, i),
+	}
+
+	nl := s.levels
+	for r := 0; r <= nl; r++ {
+		l := nl - r
+		res := &resolution{
+			x0: ceilDivPow2(tc.x0, l), y0: ceilDivPow2(tc.y0, l),
+			x1: ceilDivPow2(tc.x1, l), y1: ceilDivPow2(tc.y1, l),
+			ppx: s.ppx[r], ppy: s.ppy[r],
+		}
+
+		// Precinct partition, see B.6
+		prcx0, prcy0 := floorDivPow2(res.x0, res.ppx)<<res.ppx, floorDivPow2(res.y0, res.ppy)<<res.ppy
+		if res.x1 > res.x0 && res.y1 > res.y0 {
+			res.pw = ceilDivPow2(res.x1, res.ppx) - floorDivPow2(res.x0, res.ppx)
+			res.ph = ceilDivPow2(res.y1, res.ppy) - floorDivPow2(res.y0, res.ppy)
+		}
+
+		orients := []int{bandHL, bandLH, bandHH}
+		if r == 0 {
+			orients = []int{bandLL}
+		}
+		for _, o := range orients {
+			b, err := newBand(tc, q, comp.prec, r, o)
+			if err != nil {
+				return nil, err
+			}
+			res.bands = append(res.bands, b)
+		}
+
+		// Code-block partition, see B.7
+		cbgx0, cbgy0, pw, ph := prcx0, prcy0, res.ppx, res.ppy
+		if r > 0 {
+			cbgx0, cbgy0, pw, ph = ceilDivPow2(prcx0, 1), ceilDivPow2(prcy0, 1), pw-1, ph-1
+		}
+		xcb, ycb := min(s.xcb, pw), min(s.ycb, ph)
+
+		res.precincts = make([]*precinct, res.pw*res.ph)
+		for p := range res.precincts {
+			px0, py0 := cbgx0+(p%res.pw)<<pw, cbgy0+(p/res.pw)<<ph
+			prc := &precinct{}
+			for _, b := range res.bands {
+				x0, y0 := max(px0, b.x0), max(py0, b.y0)
+				x1, y1 := min(px0+1<<pw, b.x1), min(py0+1<<ph, b.y1)
+				pb := &precinctBand{band: b}
+				if x1 > x0 && y1 > y0 {
+					cbx0, cby0 := floorDivPow2(x0, xcb)<<xcb, floorDivPow2(y0, ycb)<<ycb
+					pb.cw = ceilDivPow2(x1, xcb) - floorDivPow2(x0, xcb)
+					pb.ch = ceilDivPow2(y1, ycb) - floorDivPow2(y0, ycb)
+					pb.blocks = make([]*codeBlock, pb.cw*pb.ch)
+					for k := range pb.blocks {
+						bx0, by0 := cbx0+(k%pb.cw)<<xcb, cby0+(k/pb.cw)<<ycb
+						pb.blocks[k] = &codeBlock{
+							x0: max(bx0, x0), y0: max(by0, y0),
+							x1: min(bx0+1<<xcb, x1), y1: min(by0+1<<ycb, y1),
+							lblock: 3,
+						}
+					}
+					pb.incl, pb.zbp = newTagTree(pb.cw, pb.ch), newTagTree(pb.cw, pb.ch)
+				}
+				prc.bands = append(prc.bands, pb)
+			}
+			res.precincts[p] = prc
+		}
+
+		tc.res = append(tc.res, res)
+	}
+
+	return tc, nil
+}
+
+// newBand returns the sub-band with orientation o of resolution r including its quantization parameters, see E.1.1
+func newBand(tc *tileComp, q *quantization, prec, r, o int) (*band, error) {
+	nl := tc.style.levels
+	b := &band{orient: o}
+
+	nb, xob, yob := nl, 0, 0
+	if r > 0 {
+		nb = nl - r + 1
+		xob, yob = o&1, o>>1
+	}
+	b.x0, b.y0 = ceilDivPow2(tc.x0-xob<<nb>>1, nb), ceilDivPow2(tc.y0-yob<<nb>>1, nb)
+	b.x1, b.y1 = ceilDivPow2(tc.x1-xob<<nb>>1, nb), ceilDivPow2(tc.y1-yob<<nb>>1, nb)
+
+	i := 0
+	if r > 0 {
+		i = 3*(r-1) + o
+	}
+
+	var eps, mu int
+	switch q.style {
+	case quantDerived:
+		eps, mu = q.eps[0]-nl+nb, q.mu[0]
+	default:
+		if i >= len(q.eps) {
+			return nil, errCorrupt
+		}
+		eps, mu = q.eps[i], q.mu[i]
+	}
+
+	gain := [4]int{0, 1, 1, 2}[o]
+	b.mb = q.guard + eps - 1
+	b.delta = 1
+	if !tc.style.reversible {
+		b.delta = float32(math.Ldexp(1+float64(mu)/2048, prec+gain-eps))
+	}
+
+	if w, h := b.x1-b.x0, b.y1-b.y0; w > 0 && h > 0 {
+		b.coeffs = make([]float32, w*h)
+	}
+
+	return b, nil
+}
+
+// decodeTile decodes tile i into c.out.
+func (c *codestream) decodeTile(i int) error {
+	t := c.tiles[i]
+	if t == nil {
+		t = &tile{}
+		c.tiles[i] = t
+	}
+
+	nx := ceilDiv(c.x1-c.tx0, c.tw)
+	p, q := i%nx, i/nx
+	t.x0, t.y0 = max(c.tx0+p*c.tw, c.x0), max(c.ty0+q*c.th, c.y0)
+	t.x1, t.y1 = min(c.tx0+(p+1)*c.tw, c.x1), min(c.ty0+(q+1)*c.th, c.y1)
+
+	tcs := make([]*tileComp, len(c.comps))
+	for j := range c.comps {
+		tc, err := c.newTileComp(t, j)
+		if err != nil {
+			return err
+		}
+		tcs[j] = tc
+	}
+
+	cs := c.codingStyle(t)
+
+	// Tier-2: Collect the code-block contributions of all packets.
+	d := &packetDecoder{data: t.data, sop: cs.sop, eph: cs.eph}
+	for _, pk := range c.packets(t, tcs, cs.layers) {
+		tc := tcs[pk.c]
+		res := tc.res[pk.r]
+		if err := d.decodePacket(res.precincts[pk.p], pk.l, tc.style.cbStyle); err != nil {
+			// Be lenient about truncated or corrupt tile data.
+			break
+		}
+	}
+
+	// Tier-1: Decode the code-blocks.
+	var t1 t1Decoder
+	for _, tc := range tcs {
+		for _, res := range tc.res {
+			for _, prc := range res.precincts {
+				for _, pb := range prc.bands {
+					for _, cb := range pb.blocks {
+						t1.decode(cb, pb.band, tc.style.cbStyle, tc.roi, tc.style.reversible)
+					}
+				}
+			}
+		}
+	}
+
+	// Inverse wavelet transformation.
+	data := make([][]float32, len(tcs))
+	for j, tc := range tcs {
+		data[j] = inverseDWT(tc)
+	}
+
+	if cs.mct && len(tcs) >= 3 {
+		n := len(data[0])
+		if len(data[1]) != n || len(data[2]) != n {
+			return errCorrupt
+		}
+		if tcs[0].style.reversible {
+			inverseRCT(data[0], data[1], data[2])
+		} else {
+			inverseICT(data[0], data[1], data[2])
+		}
+	}
+
+	for j, tc := range tcs {
+		c.store(j, tc, data[j])
+	}
+
+	return nil
+}
+
+// store applies the DC level shift to the samples of tile-component tc and copies them into component j.
+func (c *codestream) store(j int, tc *tileComp, data []float32) {
+	comp := c.comps[j]
+	out := c.out[j].Samples
+	w := ceilDiv(c.x1, comp.dx) - ceilDiv(c.x0, comp.dx)
+	x0, y0 := tc.x0-ceilDiv(c.x0, comp.dx), tc.y0-ceilDiv(c.y0, comp.dy)
+	tw := tc.x1 - tc.x0
+	off, max := float32(int(1)<<(comp.prec-1)), float32(int(1)<<comp.prec-1)
+	for y := 0; y < tc.y1-tc.y0; y++ {
+		for x := 0; x < tw; x++ {
+			v := data[y*tw+x] + off
+			if !tc.style.reversible {
+				v = float32(math.Round(float64(v)))
+			}
+			switch {
+			case v < 0:
+				v = 0
+			case v > max:
+				v = max
+			}
+			out[(y0+y)*w+x0+x] = uint16(v)
+		}
+	}
+}
+
+func (c *codestream) decode() error {
+	c.out = make([]Component, len(c.comps))
+	for j, comp := range c.comps {
+		w := ceilDiv(c.x1, comp.dx) - ceilDiv(c.x0, comp.dx)
+		h := ceilDiv(c.y1, comp.dy) - ceilDiv(c.y0, comp.dy)
+		c.out[j] = Component{Precision: comp.prec, Samples: make([]uint16, w*h)}
+	}
+
+	for i := range c.tiles {
+		if err := c.decodeTile(i); err != nil {
+			return err
+		}
+	}
+
+	// Upsample subsampled components to the image grid.
+	w, h := c.x1-c.x0, c.y1-c.y0
+	for j, comp := range c.comps {
+		if comp.dx == 1 && comp.dy == 1 {
+			continue
+		}
+		cw, cx0, cy0 := ceilDiv(c.x1, comp.dx)-ceilDiv(c.x0, comp.dx), ceilDiv(c.x0, comp.dx), ceilDiv(c.y0, comp.dy)
+		ch := len(c.out[j].Samples) / cw
+		ss := make([]uint16, w*h)
+		for y := 0; y < h; y++ {
+			cy := min(max((c.y0+y)/comp.dy-cy0, 0), ch-1)
+			for x := 0; x < w; x++ {
+				cx := min(max((c.x0+x)/comp.dx-cx0, 0), cw-1)
+				ss[y*w+x] = c.out[j].Samples[cy*cw+cx]
+			}
+		}
+		c.out[j].Samples = ss
+	}
+
+	return nil
+}
+
+// inverseRCT applies the reversible component transformation, see G.2
+func inverseRCT(c0, c1, c2 []float32) {
+	for i := range c0 {
+		y, u, v := c0[i], c1[i], c2[i]
+		g := y - float32(math.Floor(float64(u+v)/4))
+		c0[i], c1[i], c2[i] = v+g, g, u+g
+	}
+}
+
+// inverseICT applies the irreversible component transformation, see G.3
+func inverseICT(c0, c1, c2 []float32) {
+	for i := range c0 {
+		y, cb, cr := c0[i], c1[i], c2[i]
+		c0[i] = y + 1.402*cr
+		c1[i] = y - 0.34413*cb - 0.71414*cr
+		c2[i] = y + 1.772*cb
+	}
+}
@@ -41,7 +41,7 @@ func Annotations(rs io.ReadSeeker, selectedPages []string, conf *model.Configura
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func AddAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages []string, ann m
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -101,7 +101,7 @@ func AddAnnotationsAsIncrement(rws io.ReadWriteSeeker, selectedPages []string, a
 		return errors.New("Incremental writing not supported for PDF version < V1.4 (Hint: Use pdfcpu optimize then try again)")
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -293,7 +293,7 @@ func RemoveAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages, idsAndTypes
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -330,7 +330,7 @@ func RemoveAnnotationsAsIncrement(rws io.ReadWriteSeeker, selectedPages, idsAndT
 		return errors.New("pdfcpu: Incremental writing unsupported for PDF version < V1.4 (Hint: Use pdfcpu optimize then try again)")
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -400,3 +400,236 @@ func RemoveAnnotationsFile(inFile, outFile string, selectedPages, idsAndTypes []
 
 	return RemoveAnnotations(f1, f2, selectedPages, idsAndTypes, objNrs, conf)
 }
+
+// FlattenAnnotations renders annotations of selected pages into page content, removes them
+// from a PDF context read from rs and writes the result to w.
+// annotTypes restricts flattening to certain annotation types, by default all markup annotations are flattened.
+func FlattenAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages, annotTypes []string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FlattenAnnotations: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENANNOTATIONS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := SelectPages(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.FlattenAnnotations(ctx, pages, annotTypes)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("pdfcpu: FlattenAnnotations: No annotation flattened")
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// FlattenAnnotationsFile renders annotations of selected pages into page content, removes them
+// from a PDF context read from inFile and writes the result to outFile.
+func FlattenAnnotationsFile(inFile, outFile string, selectedPages, annotTypes []string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return FlattenAnnotations(f1, f2, selectedPages, annotTypes, conf)
+}
+
+// ExportAnnotationsXFDF exports the markup annotations of selected pages of rs originating from source
+// and writes an XFDF representation to w.
+func ExportAnnotationsXFDF(rs io.ReadSeeker, w io.Writer, source string, selectedPages []string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExportAnnotationsXFDF: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: ExportAnnotationsXFDF: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTANNOTATIONS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := SelectPages(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	x, ok, err := pdfcpu.ExportAnnotationsXFDF(ctx, pages, source)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("pdfcpu: ExportAnnotationsXFDF: No annotations available")
+	}
+
+	return x.Write(w)
+}
+
+// ExportAnnotationsXFDFFile exports the markup annotations of selected pages of inFile to outFileXFDF.
+func ExportAnnotationsXFDFFile(inFile, outFileXFDF string, selectedPages []string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(outFileXFDF); err != nil {
+		f1.Close()
+		return err
+	}
+	logWritingTo(outFileXFDF)
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+	}()
+
+	return ExportAnnotationsXFDF(f1, f2, inFile, selectedPages, conf)
+}
+
+// ImportAnnotationsXFDF adds the annotations of the XFDF document rd to rs and writes the result to w.
+func ImportAnnotationsXFDF(rs io.ReadSeeker, rd io.Reader, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ImportAnnotationsXFDF: missing rs")
+	}
+
+	if rd == nil {
+		return errors.New("pdfcpu: ImportAnnotationsXFDF: missing rd")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.IMPORTANNOTATIONS
+
+	x, err := model.ParseXFDF(rd)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.ImportAnnotationsXFDF(ctx, x, false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("pdfcpu: ImportAnnotationsXFDF: No annotations imported")
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// ImportAnnotationsXFDFFile adds the annotations of inFileXFDF to inFile and writes the result to outFile.
+func ImportAnnotationsXFDFFile(inFile, inFileXFDF, outFile string, conf *model.Configuration) (err error) {
+	var f0, f1, f2 *os.File
+
+	if f0, err = os.Open(inFileXFDF); err != nil {
+		return err
+	}
+
+	if f1, err = os.Open(inFile); err != nil {
+		f0.Close()
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		f0.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			f0.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if err = f0.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return ImportAnnotationsXFDF(f1, f0, f2, conf)
+}
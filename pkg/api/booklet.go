@@ -87,7 +87,7 @@ func Booklet(rs io.ReadSeeker, w io.Writer, imgFiles, selectedPages []string, nu
 			return err
 		}
 
-		pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+		pages, err := SelectPages(ctx, selectedPages, true, true)
 		if err != nil {
 			return err
 		}
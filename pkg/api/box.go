@@ -57,7 +57,7 @@ func Boxes(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration)
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +81,7 @@ func AddBoxes(rs io.ReadSeeker, w io.Writer, selectedPages []string, pb *model.P
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -154,7 +154,7 @@ func RemoveBoxes(rs io.ReadSeeker, w io.Writer, selectedPages []string, pb *mode
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -228,7 +228,7 @@ func Crop(rs io.ReadSeeker, w io.Writer, selectedPages []string, b *model.Box, c
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
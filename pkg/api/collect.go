@@ -41,7 +41,7 @@ func Collect(rs io.ReadSeeker, w io.Writer, selectedPages []string, conf *model.
 		return err
 	}
 
-	pages, err := PagesForPageCollection(ctx.PageCount, selectedPages)
+	pages, err := collectPages(ctx, selectedPages)
 	if err != nil {
 		return err
 	}
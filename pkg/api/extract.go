@@ -50,7 +50,7 @@ func ExtractImagesRaw(rs io.ReadSeeker, selectedPages []string, conf *model.Conf
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +86,7 @@ func ExtractImages(rs io.ReadSeeker, selectedPages []string, digestImage func(mo
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -169,7 +169,7 @@ func ExtractFonts(rs io.ReadSeeker, outDir, fileName string, selectedPages []str
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -259,7 +259,7 @@ func ExtractPages(rs io.ReadSeeker, outDir, fileName string, selectedPages []str
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -317,7 +317,7 @@ func ExtractContent(rs io.ReadSeeker, outDir, fileName string, selectedPages []s
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -19,7 +19,8 @@ package api
 import (
 	"bytes"
 	"fmt"
-
+	"io"
+	"os"
 	"path/filepath"
 	"sort"
 	"unicode/utf8"
@@ -60,7 +61,7 @@ func ListFonts() ([]string, error) {
 	return append(sscf, ssuf...), nil
 }
 
-// InstallFonts installs true type fonts for embedding.
+// InstallFonts installs TrueType, OpenType or web fonts (WOFF, WOFF2) for embedding.
 func InstallFonts(fileNames []string) error {
 	if log.CLIEnabled() {
 		log.CLI.Printf("installing to %s...", font.UserFontDir)
@@ -68,7 +69,7 @@ func InstallFonts(fileNames []string) error {
 
 	for _, fn := range fileNames {
 		switch filepath.Ext(fn) {
-		case ".ttf":
+		case ".ttf", ".otf", ".woff", ".woff2":
 			//log.CLI.Println(filepath.Base(fn))
 			if err := font.InstallTrueTypeFont(font.UserFontDir, fn); err != nil {
 				if log.CLIEnabled() {
@@ -88,6 +89,114 @@ func InstallFonts(fileNames []string) error {
 	return font.LoadUserFonts()
 }
 
+func updateFonts(rs io.ReadSeeker, w io.Writer, conf *model.Configuration, f func(*model.Context) error) error {
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := f(ctx); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+func updateFontsFile(inFile, outFile string, f func(rs io.ReadSeeker, w io.Writer) error) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return f(f1, f2)
+}
+
+// EmbedFonts reads a PDF stream from rs, embeds all non embedded fonts matching an installed user font and writes the result to w.
+// fontMap maps font names used in rs to user font names, otherwise fonts are matched by their PostScript name.
+func EmbedFonts(rs io.ReadSeeker, w io.Writer, fontMap map[string]string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: EmbedFonts: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EMBEDFONTS
+
+	return updateFonts(rs, w, conf, func(ctx *model.Context) error {
+		return pdfcpu.EmbedFonts(ctx, fontMap)
+	})
+}
+
+// EmbedFontsFile embeds all non embedded fonts of inFile matching an installed user font and writes the result to outFile.
+// If outFile is not provided then inFile gets overwritten.
+func EmbedFontsFile(inFile, outFile string, fontMap map[string]string, conf *model.Configuration) error {
+	return updateFontsFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return EmbedFonts(rs, w, fontMap, conf)
+	})
+}
+
+// ReplaceFont reads a PDF stream from rs, replaces the font fontName by the core font or user font newFontName
+// and writes the result to w.
+func ReplaceFont(rs io.ReadSeeker, w io.Writer, fontName, newFontName string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ReplaceFont: missing rs")
+	}
+
+	if fontName == "" || newFontName == "" {
+		return errors.New("pdfcpu: ReplaceFont: missing font name")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REPLACEFONT
+
+	return updateFonts(rs, w, conf, func(ctx *model.Context) error {
+		return pdfcpu.ReplaceFont(ctx, fontName, newFontName)
+	})
+}
+
+// ReplaceFontFile replaces the font fontName of inFile by the core font or user font newFontName and writes the result to outFile.
+// If outFile is not provided then inFile gets overwritten.
+func ReplaceFontFile(inFile, outFile, fontName, newFontName string, conf *model.Configuration) error {
+	return updateFontsFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return ReplaceFont(rs, w, fontName, newFontName, conf)
+	})
+}
+
 func rowLabel(xRefTable *model.XRefTable, i int, td model.TextDescriptor, baseFontName, baseFontKey string, buf *bytes.Buffer, mb *types.Rectangle, left bool) {
 	x := 39.
 	if !left {
@@ -331,6 +331,75 @@ func ResetFormFieldsFile(inFile, outFile string, fieldIDsOrNames []string, conf
 	return ResetFormFields(f1, f2, fieldIDsOrNames, conf)
 }
 
+// FlattenForm merges the appearance of form fields in rs into page content, removes these fields and writes the result to w.
+// An empty fieldIDsOrNames flattens the whole form.
+// Widgets flagged Hidden or NoView or lacking the Print flag are removed without being rendered.
+func FlattenForm(rs io.ReadSeeker, w io.Writer, fieldIDsOrNames []string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FlattenForm: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENFORMFIELDS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := form.FlattenForm(ctx, fieldIDsOrNames)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoFormFieldsAffected
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// FlattenFormFile merges the appearance of form fields of inFile into page content, removes these fields and writes the result to outFile.
+func FlattenFormFile(inFile, outFile string, fieldIDsOrNames []string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+	}
+	logWritingTo(outFile)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return FlattenForm(f1, f2, fieldIDsOrNames, conf)
+}
+
 // ExportForm extracts form data originating from source from rs.
 func ExportForm(rs io.ReadSeeker, source string, conf *model.Configuration) (*form.FormGroup, error) {
 	if rs == nil {
@@ -544,8 +613,10 @@ func FillForm(rs io.ReadSeeker, rd io.Reader, w io.Writer, conf *model.Configura
 		return ErrNoFormData
 	}
 
-	f := formGroup.Forms[0]
+	return fillForm(ctx, formGroup.Forms[0], w, conf)
+}
 
+func fillForm(ctx *model.Context, f form.Form, w io.Writer, conf *model.Configuration) error {
 	if err := validateOptionValues(f); err != nil {
 		return err
 	}
@@ -623,6 +694,308 @@ func FillFormFile(inFilePDF, inFileJSON, outFilePDF string, conf *model.Configur
 	return FillForm(rs, f0, f2, conf)
 }
 
+// ExportFormXFDF extracts form data originating from source from rs and writes an XFDF representation to w.
+func ExportFormXFDF(rs io.ReadSeeker, w io.Writer, source string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExportFormXFDF: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: ExportFormXFDF: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTFORMFIELDS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := form.ExportFormXFDF(ctx.XRefTable, source, w)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoFormFieldsAffected
+	}
+
+	return nil
+}
+
+// ExportFormXFDFFile extracts form data from inFilePDF and writes the result to outFileXFDF.
+func ExportFormXFDFFile(inFilePDF, outFileXFDF string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(outFileXFDF); err != nil {
+		f1.Close()
+		return err
+	}
+	logWritingTo(outFileXFDF)
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+	}()
+
+	return ExportFormXFDF(f1, f2, inFilePDF, conf)
+}
+
+// FillFormXFDF populates the form rs with XFDF data from rd and writes the result to w.
+func FillFormXFDF(rs io.ReadSeeker, rd io.Reader, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FillFormXFDF: missing rs")
+	}
+
+	if rd == nil {
+		return errors.New("pdfcpu: FillFormXFDF: missing rd")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FILLFORMFIELDS
+
+	x, err := model.ParseXFDF(rd)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	// TODO not necessarily so
+	ctx.RemoveSignature()
+
+	f, ok, err := form.FormForXFDF(ctx.XRefTable, x)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoFormData
+	}
+
+	return fillForm(ctx, *f, w, conf)
+}
+
+// FillFormXFDFFile populates the form inFilePDF with data from inFileXFDF and writes the result to outFilePDF.
+func FillFormXFDFFile(inFilePDF, inFileXFDF, outFilePDF string, conf *model.Configuration) (err error) {
+	var f0, f1, f2 *os.File
+
+	if f0, err = os.Open(inFileXFDF); err != nil {
+		return err
+	}
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		f0.Close()
+		return err
+	}
+
+	tmpFile := inFilePDF + ".tmp"
+	if outFilePDF != "" && inFilePDF != outFilePDF {
+		tmpFile = outFilePDF
+	}
+	logWritingTo(outFilePDF)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		f0.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			f0.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if err = f0.Close(); err != nil {
+			return
+		}
+		if outFilePDF == "" || inFilePDF == outFilePDF {
+			err = os.Rename(tmpFile, inFilePDF)
+		}
+	}()
+
+	return FillFormXFDF(f1, f0, f2, conf)
+}
+
+// ExportFormFDF extracts form data originating from source from rs and writes an FDF representation to w.
+func ExportFormFDF(rs io.ReadSeeker, w io.Writer, source string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExportFormFDF: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: ExportFormFDF: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTFORMFIELDS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := form.ExportFormFDF(ctx.XRefTable, source, w)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoFormFieldsAffected
+	}
+
+	return nil
+}
+
+// ExportFormFDFFile extracts form data from inFilePDF and writes the result to outFileFDF.
+func ExportFormFDFFile(inFilePDF, outFileFDF string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(outFileFDF); err != nil {
+		f1.Close()
+		return err
+	}
+	logWritingTo(outFileFDF)
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+	}()
+
+	return ExportFormFDF(f1, f2, inFilePDF, conf)
+}
+
+// FillFormFDF populates the form rs with FDF data from rd and writes the result to w.
+func FillFormFDF(rs io.ReadSeeker, rd io.Reader, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FillFormFDF: missing rs")
+	}
+
+	if rd == nil {
+		return errors.New("pdfcpu: FillFormFDF: missing rd")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FILLFORMFIELDS
+
+	fdf, err := form.ParseFDF(rd)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	// TODO not necessarily so
+	ctx.RemoveSignature()
+
+	f, ok, err := form.FormForFDF(ctx.XRefTable, fdf)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoFormData
+	}
+
+	return fillForm(ctx, *f, w, conf)
+}
+
+// FillFormFDFFile populates the form inFilePDF with data from inFileFDF and writes the result to outFilePDF.
+func FillFormFDFFile(inFilePDF, inFileFDF, outFilePDF string, conf *model.Configuration) (err error) {
+	var f0, f1, f2 *os.File
+
+	if f0, err = os.Open(inFileFDF); err != nil {
+		return err
+	}
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		f0.Close()
+		return err
+	}
+
+	tmpFile := inFilePDF + ".tmp"
+	if outFilePDF != "" && inFilePDF != outFilePDF {
+		tmpFile = outFilePDF
+	}
+	logWritingTo(outFilePDF)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		f0.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			f0.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if err = f0.Close(); err != nil {
+			return
+		}
+		if outFilePDF == "" || inFilePDF == outFilePDF {
+			err = os.Rename(tmpFile, inFilePDF)
+		}
+	}()
+
+	return FillFormFDF(f1, f0, f2, conf)
+}
+
 func parseFormGroup(rd io.Reader) (*form.FormGroup, error) {
 	formGroup := &form.FormGroup{}
 
@@ -759,10 +1132,49 @@ func multiFillFormCSV(inFilePDF string, rd io.Reader, outDir, fileName string, m
 		return err
 	}
 
-	fieldNames := csvLines[0]
+	return multiFillFormRecords(inFilePDF, csvLines[0], csvLines[1:], outDir, fileName, merge, conf)
+}
+
+func multiFillFormXLSX(inFilePDF string, rd io.Reader, sheet, outDir, fileName string, merge bool, conf *model.Configuration) error {
+	bb, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+
+	rows, err := form.ReadXLSX(bytes.NewReader(bb), int64(len(bb)), sheet)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(inFilePDF)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, err := ReadValidateAndOptimize(f, conf)
+	if err != nil {
+		return err
+	}
+
+	dateFormats, err := form.DateFormats(ctx.XRefTable)
+	if err != nil {
+		return err
+	}
+
+	fieldNames, records, err := form.XLSXRecords(rows, dateFormats)
+	if err != nil {
+		return err
+	}
+
+	return multiFillFormRecords(inFilePDF, fieldNames, records, outDir, fileName, merge, conf)
+}
+
+// multiFillFormRecords fills one instance of inFilePDF's form for each record.
+func multiFillFormRecords(inFilePDF string, fieldNames []string, records [][]string, outDir, fileName string, merge bool, conf *model.Configuration) error {
 	var outFiles []string
 
-	for i, formRecord := range csvLines[1:] {
+	for i, formRecord := range records {
 
 		f, err := os.Open(inFilePDF)
 		if err != nil {
@@ -824,19 +1236,38 @@ func MultiFillForm(inFilePDF string, rd io.Reader, outDir, fileName string, form
 
 	fileName = strings.TrimSuffix(filepath.Base(fileName), ".pdf")
 
-	if format == form.JSON {
+	switch format {
+	case form.JSON:
 		return multiFillFormJSON(inFilePDF, rd, outDir, fileName, merge, conf)
+	case form.XLSX:
+		return multiFillFormXLSX(inFilePDF, rd, "", outDir, fileName, merge, conf)
 	}
 
 	return multiFillFormCSV(inFilePDF, rd, outDir, fileName, merge, conf)
 }
 
+// MultiFillFormXLSX populates multiples instances of inFilePDF's form with data from sheet of the XLSX workbook rd and writes the result to outDir.
+// sheet is a sheet name or a 1-based sheet number, the first sheet is used if sheet is empty.
+func MultiFillFormXLSX(inFilePDF string, rd io.Reader, sheet, outDir, fileName string, merge bool, conf *model.Configuration) error {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.MULTIFILLFORMFIELDS
+
+	fileName = strings.TrimSuffix(filepath.Base(fileName), ".pdf")
+
+	return multiFillFormXLSX(inFilePDF, rd, sheet, outDir, fileName, merge, conf)
+}
+
 // MultiFillFormFile populates multiples instances of inFilePDFs form with data from inFileData and writes the result to outDir.
 func MultiFillFormFile(inFilePDF, inFileData, outDir, outFilePDF string, merge bool, conf *model.Configuration) (err error) {
 	format := form.JSON
 	if strings.HasSuffix(strings.ToLower(inFileData), ".csv") {
 		format = form.CSV
 	}
+	if strings.HasSuffix(strings.ToLower(inFileData), ".xlsx") {
+		return MultiFillFormXLSXFile(inFilePDF, inFileData, "", outDir, outFilePDF, merge, conf)
+	}
 
 	var f *os.File
 
@@ -864,3 +1295,28 @@ func MultiFillFormFile(inFilePDF, inFileData, outDir, outFilePDF string, merge b
 
 	return MultiFillForm(inFilePDF, f, outDir, outFileBase, format, merge, conf)
 }
+
+// MultiFillFormXLSXFile populates multiples instances of inFilePDFs form with data from sheet of inFileXLSX and writes the result to outDir.
+// sheet is a sheet name or a 1-based sheet number, the first sheet is used if sheet is empty.
+func MultiFillFormXLSXFile(inFilePDF, inFileXLSX, sheet, outDir, outFilePDF string, merge bool, conf *model.Configuration) (err error) {
+	var f *os.File
+
+	if f, err = os.Open(inFileXLSX); err != nil {
+		return err
+	}
+
+	defer func() {
+		cerr := f.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	outFileBase := filepath.Base(outFilePDF)
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("filling multiple forms via %s based on XLSX data from %s into %s/%s ...\n", inFilePDF, inFileXLSX, outDir, outFileBase)
+	}
+
+	return MultiFillFormXLSX(inFilePDF, f, sheet, outDir, outFileBase, merge, conf)
+}
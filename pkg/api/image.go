@@ -44,7 +44,7 @@ func Images(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration)
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +48,7 @@ func PDFInfo(rs io.ReadSeeker, fileName string, selectedPages []string, fonts bo
 		}
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, false, true)
+	pages, err := SelectPages(ctx, selectedPages, false, true)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +47,7 @@ func InsertPages(rs io.ReadSeeker, w io.Writer, selectedPages []string, before b
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -121,7 +121,7 @@ func RemovePages(rs io.ReadSeeker, w io.Writer, selectedPages []string, conf *mo
 		return err
 	}
 
-	pages, err := RemainingPagesForPageRemoval(ctx.PageCount, selectedPages, true)
+	pages, err := remainingPages(ctx, selectedPages, true)
 	if err != nil {
 		return err
 	}
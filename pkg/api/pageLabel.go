@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// PageLabels returns the page label ranges of rs.
+func PageLabels(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.PageLabel, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: PageLabels: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTPAGELABELS
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.PageLabels(ctx)
+}
+
+func writePageLabels(rs io.ReadSeeker, w io.Writer, conf *model.Configuration, f func(*model.Context) error) error {
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := f(ctx); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+func writePageLabelsFile(inFile, outFile string, f func(rs io.ReadSeeker, w io.Writer) error) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return f(f1, f2)
+}
+
+// SetPageLabels replaces the page labels of rs by labels and writes the result to w.
+// An empty labels removes all page labels.
+func SetPageLabels(rs io.ReadSeeker, w io.Writer, labels []pdfcpu.PageLabel, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: SetPageLabels: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.ADDPAGELABELS
+
+	return writePageLabels(rs, w, conf, func(ctx *model.Context) error {
+		return pdfcpu.SetPageLabels(ctx, labels)
+	})
+}
+
+// SetPageLabelsFile replaces the page labels of inFile by labels and writes the result to outFile.
+func SetPageLabelsFile(inFile, outFile string, labels []pdfcpu.PageLabel, conf *model.Configuration) error {
+	return writePageLabelsFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return SetPageLabels(rs, w, labels, conf)
+	})
+}
+
+// AddPageLabels adds page label ranges to rs and writes the result to w.
+// Existing ranges starting on the same page get replaced.
+func AddPageLabels(rs io.ReadSeeker, w io.Writer, labels []pdfcpu.PageLabel, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: AddPageLabels: missing rs")
+	}
+
+	if len(labels) == 0 {
+		return errors.New("pdfcpu: AddPageLabels: missing labels")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.ADDPAGELABELS
+
+	return writePageLabels(rs, w, conf, func(ctx *model.Context) error {
+		return pdfcpu.AddPageLabels(ctx, labels)
+	})
+}
+
+// AddPageLabelsFile adds page label ranges to inFile and writes the result to outFile.
+func AddPageLabelsFile(inFile, outFile string, labels []pdfcpu.PageLabel, conf *model.Configuration) error {
+	return writePageLabelsFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return AddPageLabels(rs, w, labels, conf)
+	})
+}
+
+// RemovePageLabels removes the page label ranges starting at pageNrs from rs and writes the result to w.
+// An empty pageNrs removes all page labels.
+func RemovePageLabels(rs io.ReadSeeker, w io.Writer, pageNrs []int, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RemovePageLabels: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.REMOVEPAGELABELS
+
+	return writePageLabels(rs, w, conf, func(ctx *model.Context) error {
+		ok, err := pdfcpu.RemovePageLabels(ctx, pageNrs)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("no page labels removed")
+		}
+		return nil
+	})
+}
+
+// RemovePageLabelsFile removes the page label ranges starting at pageNrs from inFile and writes the result to outFile.
+func RemovePageLabelsFile(inFile, outFile string, pageNrs []int, conf *model.Configuration) error {
+	return writePageLabelsFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return RemovePageLabels(rs, w, pageNrs, conf)
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
+	"github.com/pkg/errors"
+)
+
+// ConvertToPDFA reads a PDF stream from rs, converts it to conf.Conformance (PDF/A-2b or PDF/A-3b, defaults to PDF/A-2b)
+// and writes the result to w.
+// The result is validated against the requested conformance level.
+// If there are violations that could not be fixed automatically, eg. fonts that are not embedded,
+// nothing gets written and the returned report lists all remaining violations.
+func ConvertToPDFA(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) (*model.ConformanceReport, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ConvertToPDFA: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.CONVERTPDFA
+
+	if conf.Conformance == model.ConformanceNone {
+		// Don't leak the default into subsequent usage of conf.
+		defer func() { conf.Conformance = model.ConformanceNone }()
+		conf.Conformance = model.PDFA2B
+	}
+
+	if conf.Conformance != model.PDFA2B && conf.Conformance != model.PDFA3B {
+		return nil, errors.Errorf("pdfcpu: ConvertToPDFA: unsupported conformance level %s, use PDF/A-2b or PDF/A-3b", conf.Conformance)
+	}
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pdfcpu.ConvertToPDFA(ctx); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := WriteContext(ctx, &buf); err != nil {
+		return nil, err
+	}
+
+	// Verify the result.
+	ctx, err = ReadAndValidate(bytes.NewReader(buf.Bytes()), conf)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := validate.PDFA(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.Conforming() {
+		if log.CLIEnabled() {
+			for _, s := range r.ListViolations() {
+				log.CLI.Println(s)
+			}
+		}
+		return r, errors.Errorf("pdfcpu: ConvertToPDFA: not %s conforming: %d violation(s)", r.Conformance, len(r.Violations))
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return r, err
+}
+
+// ConvertToPDFAFile converts inFile to conf.Conformance (PDF/A-2b or PDF/A-3b, defaults to PDF/A-2b) and writes the result to outFile.
+// If outFile is not provided then inFile gets overwritten
+// which leads to the same result as when inFile equals outFile.
+func ConvertToPDFAFile(inFile, outFile string, conf *model.Configuration) (r *model.ConformanceReport, err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return nil, err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return ConvertToPDFA(f1, f2, conf)
+}
@@ -24,12 +24,15 @@ import (
 	"strings"
 
 	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
 )
 
 var (
 	selectedPagesRegExp *regexp.Regexp
+	pageLabelRegExp     = regexp.MustCompile(`\{[^{},]+\}`)
 )
 
 func setupRegExpForPageSelection() *regexp.Regexp {
@@ -63,8 +66,11 @@ func ParsePageSelection(s string) ([]string, error) {
 	// The pageSelection is evaluated strictly from left to right!
 	// e.g. "!3,1-5" extracts pages 1-5 whereas "1-5,!3" extracts pages 1,2,4,5
 	//
+	// A page may also be referred to by its page label enclosed in curly braces
+	// e.g. "{i}-{iv}" or "{A-1}-" or "!{ix}"
+	//
 
-	if !selectedPagesRegExp.MatchString(s) {
+	if !selectedPagesRegExp.MatchString(pageLabelRegExp.ReplaceAllString(s, "1")) {
 		return nil, errors.Errorf("-pages \"%s\" => syntax error\n", s)
 	}
 
@@ -73,6 +79,44 @@ func ParsePageSelection(s string) ([]string, error) {
 	return strings.Split(s, ","), nil
 }
 
+// ResolvePageLabels replaces all page labels referred to in pageSelection by their page numbers in ctx.
+func ResolvePageLabels(ctx *model.Context, pageSelection []string) ([]string, error) {
+	var labels []string
+
+	ss := make([]string, len(pageSelection))
+
+	for i, s := range pageSelection {
+		if !pageLabelRegExp.MatchString(s) {
+			ss[i] = s
+			continue
+		}
+
+		if labels == nil {
+			var err error
+			if labels, err = pdfcpu.PageLabelStrings(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		ss[i] = pageLabelRegExp.ReplaceAllStringFunc(s, func(s string) string {
+			label := s[1 : len(s)-1]
+			for j, l := range labels {
+				if l == label {
+					return strconv.Itoa(j + 1)
+				}
+			}
+			err = errors.Errorf("pdfcpu: unknown page label: %s", label)
+			return s
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ss, nil
+}
+
 func handlePrefix(v string, negated bool, pageCount int, selectedPages types.IntSet) error {
 	// -l
 	if v == "l" {
@@ -391,6 +435,24 @@ func PagesForPageSelection(pageCount int, pageSelection []string, ensureAllforNo
 	return m, nil
 }
 
+// SelectPages resolves the page labels referred to in pageSelection
+// and returns the set of selected page numbers of ctx, see PagesForPageSelection.
+func SelectPages(ctx *model.Context, pageSelection []string, ensureAllforNone bool, log bool) (types.IntSet, error) {
+	pageSelection, err := ResolvePageLabels(ctx, pageSelection)
+	if err != nil {
+		return nil, err
+	}
+	return PagesForPageSelection(ctx.PageCount, pageSelection, ensureAllforNone, log)
+}
+
+func remainingPages(ctx *model.Context, pageSelection []string, log bool) (types.IntSet, error) {
+	pageSelection, err := ResolvePageLabels(ctx, pageSelection)
+	if err != nil {
+		return nil, err
+	}
+	return RemainingPagesForPageRemoval(ctx.PageCount, pageSelection, log)
+}
+
 func RemainingPagesForPageRemoval(pageCount int, pageSelection []string, log bool) (types.IntSet, error) {
 	pagesToRemove, err := selectedPages(pageCount, pageSelection, log)
 	if err != nil {
@@ -688,6 +750,14 @@ func PagesForPageCollection(pageCount int, pageSelection []string) ([]int, error
 	return collectedPages, nil
 }
 
+func collectPages(ctx *model.Context, pageSelection []string) ([]int, error) {
+	pageSelection, err := ResolvePageLabels(ctx, pageSelection)
+	if err != nil {
+		return nil, err
+	}
+	return PagesForPageCollection(ctx.PageCount, pageSelection)
+}
+
 // PagesForPageRange returns a slice of page numbers for a page range.
 func PagesForPageRange(from, thru int) []int {
 	s := make([]int, thru-from+1)
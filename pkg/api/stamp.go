@@ -189,7 +189,7 @@ func AddWatermarks(rs io.ReadSeeker, w io.Writer, selectedPages []string, wm *mo
 	}
 
 	var pages types.IntSet
-	pages, err = PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err = SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -258,7 +258,7 @@ func RemoveWatermarks(rs io.ReadSeeker, w io.Writer, selectedPages []string, con
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
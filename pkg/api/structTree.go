@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// ErrNoStructTree is returned for documents without a structure tree, ie. untagged PDFs.
+var ErrNoStructTree = errors.New("pdfcpu: no structure tree available")
+
+// StructureTree returns rs's logical structure.
+func StructureTree(rs io.ReadSeeker, conf *model.Configuration) (*pdfcpu.StructureTree, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: StructureTree: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTSTRUCTURE
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := pdfcpu.ReadStructureTree(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, ErrNoStructTree
+	}
+
+	return st, nil
+}
+
+// ExportStructureJSON extracts the logical structure from rs (originating from source) and writes the result to w.
+func ExportStructureJSON(rs io.ReadSeeker, w io.Writer, source string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExportStructureJSON: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: ExportStructureJSON: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTSTRUCTURE
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.ExportStructureTreeJSON(ctx, source, w)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoStructTree
+	}
+
+	return nil
+}
+
+// ExportStructureFile extracts the logical structure from inFilePDF and writes the result to outFileJSON.
+func ExportStructureFile(inFilePDF, outFileJSON string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(outFileJSON); err != nil {
+		f1.Close()
+		return err
+	}
+	logWritingTo(outFileJSON)
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+	}()
+
+	return ExportStructureJSON(f1, f2, inFilePDF, conf)
+}
+
+// ImportStructure applies the logical structure read from rd to rs and writes the result to w.
+// Structure elements are matched by id. Roles, titles, Alt, ActualText, Lang and the role map get updated.
+func ImportStructure(rs io.ReadSeeker, rd io.Reader, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ImportStructure: missing rs")
+	}
+
+	if rd == nil {
+		return errors.New("pdfcpu: ImportStructure: missing rd")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		// Don't leak the relaxed validation into the caller's configuration.
+		c := *conf
+		c.ValidationMode = model.ValidationRelaxed
+		conf = &c
+	}
+	conf.Cmd = model.IMPORTSTRUCTURE
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.ImportStructureTree(ctx, rd)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoStructTree
+	}
+
+	return WriteContext(ctx, w)
+}
+
+// ImportStructureFile applies the logical structure read from inFileJSON to inFilePDF and writes the result to outFilePDF.
+func ImportStructureFile(inFilePDF, inFileJSON, outFilePDF string, conf *model.Configuration) (err error) {
+	var f0, f1, f2 *os.File
+
+	if f0, err = os.Open(inFilePDF); err != nil {
+		return err
+	}
+
+	if f1, err = os.Open(inFileJSON); err != nil {
+		f0.Close()
+		return err
+	}
+
+	tmpFile := inFilePDF + ".tmp"
+	if outFilePDF != "" && inFilePDF != outFilePDF {
+		tmpFile = outFilePDF
+		logWritingTo(outFilePDF)
+	} else {
+		logWritingTo(inFilePDF)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		f0.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			f0.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if err = f0.Close(); err != nil {
+			return
+		}
+		if outFilePDF == "" || inFilePDF == outFilePDF {
+			err = os.Rename(tmpFile, inFilePDF)
+		}
+	}()
+
+	return ImportStructure(f0, f1, f2, conf)
+}
@@ -34,7 +34,7 @@ var textAnn model.AnnotationRenderer = model.NewTextAnnotation(
 	"Text Annotation",                   // contents
 	"ID1",                               // id
 	"",                                  // modDate
-	0,                                   // f
+	model.AnnPrint,                      // f
 	&color.Gray,                         // col
 	"Title1",                            // title
 	nil,                                 // popupIndRef
@@ -91,8 +91,8 @@ line 3`,
 	// 	</body>
 	//  </xhmtl>`, // rich text (ignored by Mac Preview and rendered mediocre by Adobe Reader)
 	types.AlignCenter, // horizontal alignment
-	"Helvetica",       // font name (TODO)
-	12,                // font size in points (TODO)
+	"Helvetica",       // font name
+	12,                // font size in points
 	&color.Green,      // font color
 	"",                // DS (default style string)
 	nil,               // Intent
@@ -126,7 +126,7 @@ var squareAnn model.AnnotationRenderer = model.NewSquareAnnotation(
 	"Square Annotation",                  // contents
 	"ID3",                                // id
 	"",                                   // modDate
-	0,                                    // f
+	model.AnnPrint,                       // f
 	&color.Gray,                          // col
 	"Title1",                             // title
 	nil,                                  // popupIndRef
@@ -504,6 +504,266 @@ func TestAddRemoveAllAnnotationsAsIncrements(t *testing.T) {
 	}
 }
 
+func TestFlattenAnnotations(t *testing.T) {
+	msg := "TestFlattenAnnotations"
+
+	fn := "text_annotations.pdf"
+	copyFile(t, filepath.Join(inDir, fn), filepath.Join(outDir, fn))
+	inFile := filepath.Join(outDir, fn)
+
+	// We start with 1 highlight and 7 text annotations.
+	if i := annotationCount(t, inFile); i != 8 {
+		t.Fatalf("%s count: got %d want 8\n", msg, i)
+	}
+
+	// Flatten annotations by annotation type.
+	if err := api.FlattenAnnotationsFile(inFile, "", nil, []string{"Highlight"}, nil); err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	if i := annotationCount(t, inFile); i != 7 {
+		t.Fatalf("%s count: got %d want 7\n", msg, i)
+	}
+
+	// Flatten all markup annotations.
+	if err := api.FlattenAnnotationsFile(inFile, "", nil, nil, nil); err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	if i := annotationCount(t, inFile); i > 0 {
+		t.Fatalf("%s count: got %d want 0\n", msg, i)
+	}
+
+	if err := api.ValidateFile(inFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestFlattenAnnotationsKeepsLinks(t *testing.T) {
+	msg := "TestFlattenAnnotationsKeepsLinks"
+
+	fn := "test.pdf"
+	copyFile(t, filepath.Join(inDir, fn), filepath.Join(outDir, fn))
+	inFile := filepath.Join(outDir, fn)
+
+	add2Annotations(t, msg, inFile, false)
+
+	// Flatten the text annotation, the link annotation stays.
+	if err := api.FlattenAnnotationsFile(inFile, "", []string{"1"}, nil, nil); err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	if i := annotationCount(t, inFile); i != 1 {
+		t.Fatalf("%s count: got %d want 1\n", msg, i)
+	}
+
+	// The text annotation comes without appearance and gets rendered using a generated one.
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+	_, _, inhPAttrs, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+	d, err := ctx.DereferenceDict(inhPAttrs.Resources["XObject"])
+	if err != nil || d["Fx0"] == nil {
+		t.Fatalf("%s: missing rendered appearance: %v\n", msg, err)
+	}
+
+	// Widgets are flattened via form flattening.
+	if err := api.FlattenAnnotationsFile(inFile, "", nil, []string{"Widget"}, nil); err == nil {
+		t.Fatalf("%s: expected error for Widget\n", msg)
+	}
+}
+
+func TestFlattenAnnotationsKeepsUnrenderable(t *testing.T) {
+	msg := "TestFlattenAnnotationsKeepsUnrenderable"
+	inFile := filepath.Join(inDir, "test.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+
+	// A Stamp without appearance and a Square not meant for printing.
+	var annots types.Array
+	for _, d := range []types.Dict{
+		{"Type": types.Name("Annot"), "Subtype": types.Name("Stamp"), "Rect": types.NewNumberArray(0, 0, 100, 50), "F": types.Integer(model.AnnPrint)},
+		{"Type": types.Name("Annot"), "Subtype": types.Name("Square"), "Rect": types.NewNumberArray(0, 100, 100, 150)},
+	} {
+		ir, err := ctx.IndRefForNewObject(d)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		annots = append(annots, *ir)
+	}
+	pageDict["Annots"] = annots
+
+	ok, err := pdfcpu.FlattenAnnotations(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+	if ok {
+		t.Fatalf("%s: unexpected flattening\n", msg)
+	}
+	if got := len(pageDict.ArrayEntry("Annots")); got != 2 {
+		t.Fatalf("%s count: got %d want 2\n", msg, got)
+	}
+}
+
+func TestFlattenAnnotationsReplyChain(t *testing.T) {
+	msg := "TestFlattenAnnotationsReplyChain"
+	inFile := filepath.Join(inDir, "test.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+
+	square := func(irt *types.IndirectRef, f model.AnnotationFlags) types.Dict {
+		d := types.Dict{"Type": types.Name("Annot"), "Subtype": types.Name("Square"), "Rect": types.NewNumberArray(0, 0, 100, 50), "F": types.Integer(f)}
+		if irt != nil {
+			d["IRT"] = *irt
+			d["RT"] = types.Name("R")
+		}
+		return d
+	}
+
+	add := func(d types.Dict) *types.IndirectRef {
+		ir, err := ctx.IndRefForNewObject(d)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		pageDict["Annots"] = append(pageDict.ArrayEntry("Annots"), *ir)
+		return ir
+	}
+
+	// Non printable annotations are kept, printable ones get flattened.
+	// a <- b <- c
+	a := add(square(nil, 0))
+	b := add(square(a, model.AnnPrint))
+	c := add(square(b, 0))
+
+	// d <- e
+	d := add(square(nil, model.AnnPrint))
+	e := add(square(d, 0))
+
+	// The structure tree refers to b and c.
+	objRef := func(ir *types.IndirectRef) types.Dict {
+		return types.Dict{"Type": types.Name("OBJR"), "Obj": *ir}
+	}
+	elem := types.Dict{"Type": types.Name("StructElem"), "S": types.Name("Annot"), "K": types.Array{objRef(b), objRef(c)}}
+	elemIndRef, err := ctx.IndRefForNewObject(elem)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	rootDict["StructTreeRoot"] = types.Dict{"Type": types.Name("StructTreeRoot"), "K": *elemIndRef}
+
+	if ok, err := pdfcpu.FlattenAnnotations(ctx, nil, nil); err != nil || !ok {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	if got := len(pageDict.ArrayEntry("Annots")); got != 3 {
+		t.Fatalf("%s count: got %d want 3\n", msg, got)
+	}
+
+	// c replies to a now.
+	cd, err := ctx.DereferenceDict(*c)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ir := cd.IndirectRefEntry("IRT"); ir == nil || ir.ObjectNumber != a.ObjectNumber {
+		t.Fatalf("%s: c: got IRT %v want %v\n", msg, ir, a)
+	}
+
+	// e is a standalone annotation now.
+	ed, err := ctx.DereferenceDict(*e)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ed["IRT"] != nil || ed["RT"] != nil {
+		t.Fatalf("%s: e: unexpected reply to flattened annotation: %v\n", msg, ed)
+	}
+
+	// The structure tree only refers to c.
+	kids := elem.ArrayEntry("K")
+	if len(kids) != 1 {
+		t.Fatalf("%s: struct elem kids: got %d want 1\n", msg, len(kids))
+	}
+	if ir := kids[0].(types.Dict).IndirectRefEntry("Obj"); ir == nil || ir.ObjectNumber != c.ObjectNumber {
+		t.Fatalf("%s: struct elem kid: got %v want %v\n", msg, ir, c)
+	}
+}
+
+func TestFlattenAnnotationsSharedResources(t *testing.T) {
+	msg := "TestFlattenAnnotationsSharedResources"
+	inFile := filepath.Join(inDir, "bookletTestA6.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	// Let pages 1 and 2 share a resource dict including an XObject dict.
+	res := types.Dict(map[string]types.Object{"XObject": types.Dict{}})
+	ir, err := ctx.IndRefForNewObject(res)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for i := 1; i <= 2; i++ {
+		d, _, _, err := ctx.PageDict(i, false)
+		if err != nil {
+			t.Fatalf("%s pageDict: %v\n", msg, err)
+		}
+		d["Resources"] = *ir
+	}
+
+	if _, _, err := pdfcpu.AddAnnotationToPage(ctx, 1, squareAnn, false); err != nil {
+		t.Fatalf("%s add: %v\n", msg, err)
+	}
+
+	if ok, err := pdfcpu.FlattenAnnotations(ctx, types.IntSet{1: true}, nil); err != nil || !ok {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	// Page 2 stays untouched.
+	_, _, inhPAttrs, err := ctx.PageDict(2, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+	d, err := ctx.DereferenceDict(inhPAttrs.Resources["XObject"])
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(d) > 0 {
+		t.Fatalf("%s: flattened appearance leaked into page 2 resources: %v\n", msg, d)
+	}
+
+	// Page 1 refers to the appearance.
+	_, _, inhPAttrs, err = ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+	if d, err = ctx.DereferenceDict(inhPAttrs.Resources["XObject"]); err != nil || d["Fx0"] == nil {
+		t.Fatalf("%s: missing flattened appearance on page 1: %v\n", msg, err)
+	}
+}
+
 func TestAddAnnotationsLowLevel(t *testing.T) {
 	msg := "TestAddAnnotationsLowLevel"
 
@@ -1094,3 +1354,389 @@ func TestCaretAnnotation(t *testing.T) {
 		t.Fatalf("%s add: %v\n", msg, err)
 	}
 }
+
+func TestAnnotationAppearances(t *testing.T) {
+	msg := "TestAnnotationAppearances"
+
+	inFile := filepath.Join(inDir, "testWithText.pdf")
+	outFile := filepath.Join(outDir, "AnnotationAppearances.pdf")
+
+	leOpenArrow := model.LEOpenArrow
+	leClosedArrow := model.LEClosedArrow
+	leCircle := model.LECircle
+	ca := .5
+
+	lineAnn := model.NewLineAnnotation(
+		*types.NewRectangle(30, 30, 300, 300), // rect
+		0,                                     // apObjNr
+		"Diagonal",                            // contents
+		"IDLine",                              // id
+		"",                                    // modDate
+		0,                                     // f
+		&color.Red,                            // col
+		"Title1",                              // title
+		nil,                                   // popupIndRef
+		nil,                                   // ca
+		"",                                    // rc
+		"",                                    // subject
+		types.NewPoint(50, 50),                // P1
+		types.NewPoint(250, 250),              // P2
+		&leOpenArrow,                          // start lineEndingStyle
+		&leClosedArrow,                        // end lineEndingStyle
+		20,                                    // leader line length
+		0,                                     // leader line offset
+		5,                                     // leader line extension length
+		nil,                                   // intent
+		nil,                                   // measure
+		true,                                  // caption
+		false,                                 // caption position top
+		0,                                     // caption offset X
+		0,                                     // caption offset Y
+		&color.Blue,                           // fillCol
+		1,                                     // borderWidth
+		model.BSSolid)                         // borderStyle
+	lineAnn.GenerateAP = true
+
+	squareAnn := model.NewSquareAnnotation(
+		*types.NewRectangle(300, 30, 400, 130), // rect
+		0,                                      // apObjNr
+		"Square",                               // contents
+		"IDSquare",                             // id
+		"",                                     // modDate
+		0,                                      // f
+		&color.Blue,                            // col
+		"Title1",                               // title
+		nil,                                    // popupIndRef
+		&ca,                                    // ca
+		"",                                     // rc
+		"",                                     // subject
+		&color.Yellow,                          // fillCol
+		5,                                      // MLeft
+		5,                                      // MTop
+		5,                                      // MRight
+		5,                                      // MBot
+		3,                                      // borderWidth
+		model.BSDashed,                         // borderStyle
+		false,                                  // cloudyBorder
+		0)                                      // cloudyBorderIntensity
+	squareAnn.GenerateAP = true
+
+	circleAnn := model.NewCircleAnnotation(
+		*types.NewRectangle(420, 30, 520, 130), // rect
+		0,                                      // apObjNr
+		"Circle",                               // contents
+		"IDCircle",                             // id
+		"",                                     // modDate
+		0,                                      // f
+		&color.Green,                           // col
+		"Title1",                               // title
+		nil,                                    // popupIndRef
+		nil,                                    // ca
+		"",                                     // rc
+		"",                                     // subject
+		&color.LightGray,                       // fillCol
+		0,                                      // MLeft
+		0,                                      // MTop
+		0,                                      // MRight
+		0,                                      // MBot
+		2,                                      // borderWidth
+		model.BSSolid,                          // borderStyle
+		false,                                  // cloudyBorder
+		0)                                      // cloudyBorderIntensity
+	circleAnn.GenerateAP = true
+
+	polyLineAnn := model.NewPolyLineAnnotation(
+		*types.NewRectangle(30, 320, 150, 440), // rect
+		0,                                      // apObjNr
+		"PolyLine",                             // contents
+		"IDPolyLine",                           // id
+		"",                                     // modDate
+		0,                                      // f
+		&color.Gray,                            // col
+		"Title1",                               // title
+		nil,                                    // popupIndRef
+		nil,                                    // ca
+		"",                                     // rc
+		"",                                     // subject
+		types.NewNumberArray(40, 330, 140, 430, 140, 330), // vertices
+		nil,            // path
+		nil,            // intent
+		nil,            // measure
+		&color.Green,   // fillCol
+		1,              // borderWidth
+		model.BSDashed, // borderStyle
+		&leCircle,      // start lineEndingStyle
+		&leOpenArrow,   // end lineEndingStyle
+	)
+	polyLineAnn.GenerateAP = true
+
+	inkAnn := model.NewInkAnnotation(
+		*types.NewRectangle(200, 320, 300, 440), // rect
+		0,                                       // apObjNr
+		"Ink",                                   // contents
+		"IDInk",                                 // id
+		"",                                      // modDate
+		0,                                       // f
+		&color.Red,                              // col
+		"Title1",                                // title
+		nil,                                     // popupIndRef
+		nil,                                     // ca
+		"",                                      // rc
+		"",                                      // subject
+		[]model.InkPath{{210, 330, 250, 430, 290, 330}}, // InkList
+		2,             // borderWidth
+		model.BSSolid, // borderStyle
+	)
+	inkAnn.GenerateAP = true
+
+	r := types.NewRectangle(205, 624.16, 400, 645.88)
+	highlightAnn := model.NewHighlightAnnotation(
+		*r,            // rect
+		0,             // apObjNr
+		"Highlight",   // contents
+		"IDHighlight", // id
+		"",            // modDate
+		0,             // f
+		&color.Yellow, // col
+		0,             // borderRadX
+		0,             // borderRadY
+		0,             // borderWidth
+		"Title1",      // title
+		nil,           // popupIndRef
+		nil,           // ca
+		"",            // rc
+		"",            // subject
+		types.QuadPoints{*types.NewQuadLiteralForRect(r)}, // quad points
+	)
+	highlightAnn.GenerateAP = true
+
+	caretAnn := model.NewCaretAnnotation(
+		*types.NewRectangle(320, 320, 360, 360), // rect
+		0,                                       // apObjNr
+		"Caret",                                 // contents
+		"IDCaret",                               // id
+		"",                                      // modDate
+		0,                                       // f,
+		&color.Blue,                             // col
+		0,                                       // borderRadX
+		0,                                       // borderRadY
+		0,                                       // borderWidth
+		"Title1",                                // title
+		nil,                                     // popupIndRef
+		nil,                                     // ca
+		"",                                      // rc
+		"",                                      // subject
+		nil,                                     // RD
+		false)                                   // paragraph symbol
+	caretAnn.GenerateAP = true
+
+	freeTextAnn := model.NewFreeTextAnnotation(
+		*types.NewRectangle(380, 320, 560, 440), // rect
+		0,                                       // apObjNr
+		"FreeText renders its own appearance, wrapped into the annotation rectangle.", // contents
+		"IDFreeText",      // id
+		"",                // modDate
+		0,                 // f
+		&color.LightGray,  // col
+		"Title1",          // title
+		nil,               // popupIndRef
+		nil,               // ca
+		"",                // rc
+		"",                // subject
+		"",                // text
+		types.AlignCenter, // horizontal alignment
+		"Times-Roman",     // font name
+		14,                // font size in points
+		&color.Blue,       // font color
+		"",                // DS (default style string)
+		nil,               // Intent
+		nil,               // callOutLine
+		nil,               // callOutLineEndingStyle
+		5, 5, 5, 5,        // margin
+		1,             // borderWidth
+		model.BSSolid, // borderStyle
+		false,         // cloudyBorder
+		0)             // cloudyBorderIntensity
+	freeTextAnn.GenerateAP = true
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	anns := []model.AnnotationRenderer{lineAnn, squareAnn, circleAnn, polyLineAnn, inkAnn, highlightAnn, caretAnn, freeTextAnn}
+	for _, ann := range anns {
+		_, d, err := pdfcpu.AddAnnotationToPage(ctx, 1, ann, false)
+		if err != nil {
+			t.Fatalf("%s add %s: %v\n", msg, ann.ID(), err)
+		}
+		if _, ok := d.Find("AP"); !ok {
+			t.Fatalf("%s %s: missing appearance dict\n", msg, ann.ID())
+		}
+	}
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s write: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s validate: %v\n", msg, err)
+	}
+
+	// Generated appearances may be flattened into the page content.
+	if err := api.FlattenAnnotationsFile(outFile, outFile, nil, nil, nil); err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	// FreeText appearances are restricted to core fonts.
+	freeTextAnn.FontName = "Roboto-Regular"
+	if _, _, err := pdfcpu.AddAnnotationToPage(ctx, 1, freeTextAnn, false); err == nil {
+		t.Fatalf("%s: expected error for non core font\n", msg)
+	}
+}
+
+const xfdfAnnots = `<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+	<annots>
+		<text page="0" rect="320,700,340,720" name="reply" title="Bob" inreplyto="parent" replyType="reply" icon="Comment">
+			<contents>I agree.</contents>
+		</text>
+		<highlight page="0" rect="70,700,260,720" name="parent" title="Alice" color="#FFFF00" flags="print" date="D:20260101120000Z"
+			coords="70,720,260,720,70,700,260,700">
+			<contents>Please check.</contents>
+			<popup page="0" rect="300,600,500,700" open="yes"/>
+		</highlight>
+		<ink page="0" rect="100,400,200,500" name="ink" title="Alice" color="#0000FF" width="2">
+			<inklist>
+				<gesture>110,410;150,490;190,410</gesture>
+				<gesture>120,450;180,450</gesture>
+			</inklist>
+		</ink>
+	</annots>
+</xfdf>
+`
+
+func TestAnnotationsXFDF(t *testing.T) {
+	msg := "TestAnnotationsXFDF"
+
+	inFile := filepath.Join(inDir, "testWithText.pdf")
+	inFileXFDF := filepath.Join(outDir, "annotations.xfdf")
+	outFile := filepath.Join(outDir, "AnnotationsXFDF.pdf")
+
+	if err := os.WriteFile(inFileXFDF, []byte(xfdfAnnots), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ImportAnnotationsXFDFFile(inFile, inFileXFDF, outFile, nil); err != nil {
+		t.Fatalf("%s import: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Annotations already present are skipped.
+	if err := api.ImportAnnotationsXFDFFile(outFile, inFileXFDF, outFile, nil); err == nil {
+		t.Fatalf("%s: want error on repeated import\n", msg)
+	}
+
+	// Export and check replies, popups, ink lists and quad points survived.
+	outFileXFDF := filepath.Join(outDir, "AnnotationsXFDF.xfdf")
+	if err := api.ExportAnnotationsXFDFFile(outFile, outFileXFDF, nil, nil); err != nil {
+		t.Fatalf("%s export: %v\n", msg, err)
+	}
+
+	f, err := os.Open(outFileXFDF)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	x, err := model.ParseXFDF(f)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	m := map[string]*model.XFDFAnnot{}
+	for _, a := range x.Annots.Annots {
+		m[a.Name] = a
+	}
+
+	a := m["reply"]
+	if a == nil || a.InReplyTo != "parent" || a.Contents != "I agree." {
+		t.Fatalf("%s: reply lost: %+v\n", msg, a)
+	}
+
+	a = m["parent"]
+	if a == nil || a.Type() != "highlight" || a.Popup == nil || a.Coords == "" || a.Date != "D:20260101120000Z" {
+		t.Fatalf("%s: highlight lost: %+v\n", msg, a)
+	}
+
+	a = m["ink"]
+	if a == nil || a.InkList == nil || len(a.InkList.Gestures) != 2 || a.Color != "#0000FF" {
+		t.Fatalf("%s: ink lost: %+v\n", msg, a)
+	}
+}
+
+const xfdfReply = `<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+	<annots>
+		<highlight page="0" rect="70,700,260,720" name="parent" title="Alice" color="#FFFF00" coords="70,720,260,720,70,700,260,700"/>
+		<text page="0" rect="340,700,360,720" name="reply2" title="Carol" inreplyto="parent" replyType="reply" icon="Comment">
+			<contents>Me too.</contents>
+		</text>
+	</annots>
+</xfdf>
+`
+
+func TestAnnotationsXFDFReplyToExisting(t *testing.T) {
+	msg := "TestAnnotationsXFDFReplyToExisting"
+
+	inFile := filepath.Join(inDir, "testWithText.pdf")
+	inFileXFDF := filepath.Join(outDir, "annotationsReplyToExisting.xfdf")
+	outFile := filepath.Join(outDir, "AnnotationsXFDFReplyToExisting.pdf")
+
+	if err := os.WriteFile(inFileXFDF, []byte(xfdfAnnots), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ImportAnnotationsXFDFFile(inFile, inFileXFDF, outFile, nil); err != nil {
+		t.Fatalf("%s import: %v\n", msg, err)
+	}
+
+	// Reply to the already present annotation "parent".
+	if err := os.WriteFile(inFileXFDF, []byte(xfdfReply), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ImportAnnotationsXFDFFile(outFile, inFileXFDF, outFile, nil); err != nil {
+		t.Fatalf("%s import reply: %v\n", msg, err)
+	}
+
+	outFileXFDF := filepath.Join(outDir, "AnnotationsXFDFReplyToExisting.xfdf")
+	if err := api.ExportAnnotationsXFDFFile(outFile, outFileXFDF, nil, nil); err != nil {
+		t.Fatalf("%s export: %v\n", msg, err)
+	}
+
+	f, err := os.Open(outFileXFDF)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	x, err := model.ParseXFDF(f)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	m := map[string]int{}
+	for _, a := range x.Annots.Annots {
+		m[a.Name]++
+		if a.Name == "reply2" && a.InReplyTo != "parent" {
+			t.Fatalf("%s: reply2 want inreplyto parent, got %q\n", msg, a.InReplyTo)
+		}
+	}
+	if m["parent"] != 1 || m["reply2"] != 1 {
+		t.Fatalf("%s: unexpected annotations: %v\n", msg, m)
+	}
+}
@@ -37,7 +37,7 @@ var conf *model.Configuration
 
 func isTrueType(filename string) bool {
 	s := strings.ToLower(filename)
-	return strings.HasSuffix(s, ".ttf") || strings.HasSuffix(s, ".ttc")
+	return strings.HasSuffix(s, ".ttf") || strings.HasSuffix(s, ".ttc") || strings.HasSuffix(s, ".otf")
 }
 
 func userFonts(dir string) ([]string, error) {
@@ -99,6 +99,24 @@ func TestCreateContentPrimitivesViaJson(t *testing.T) {
 
 }
 
+func TestCreateTextFallbackViaJson(t *testing.T) {
+	msg := "TestCreateTextFallbackViaJson"
+	inFileJSON := filepath.Join(inDir, "json", "create", "textFallback.json")
+	outFile := filepath.Join(samplesDir, "create", "primitives", "textFallback.pdf")
+
+	// Greek, Cyrillic, Hebrew and Arabic characters missing in the core fonts are rendered using DejaVuSans.
+	createPDF(t, msg, "", inFileJSON, outFile, conf)
+}
+
+func TestCreateTextKerningViaJson(t *testing.T) {
+	msg := "TestCreateTextKerningViaJson"
+	inFileJSON := filepath.Join(inDir, "json", "create", "textKerning.json")
+	outFile := filepath.Join(samplesDir, "create", "primitives", "textKerning.pdf")
+
+	// Core fonts are kerned using their AFM kerning pairs, user fonts using their GPOS or kern table.
+	createPDF(t, msg, "", inFileJSON, outFile, conf)
+}
+
 func TestCreateFormPrimitivesViaJson(t *testing.T) {
 
 	inDirForm := filepath.Join(inDir, "json", "form")
@@ -115,6 +133,7 @@ func TestCreateFormPrimitivesViaJson(t *testing.T) {
 		{"TestTextfield", "textfield.json", "textfield.pdf"},
 		{"TestTextfieldGroup", "textfieldGroup.json", "textfieldGroup.pdf"},
 		{"TestTextfieldGroupSingle", "textfieldGroupSingle.json", "textfieldGroupSingle.pdf"},
+		{"TestTextfieldScripts", "textfieldScripts.json", "textfieldScripts.pdf"},
 
 		// Textarea
 		{"TestTextarea", "textarea.json", "textarea.pdf"},
@@ -354,3 +373,29 @@ func TestReadFormAndUpdateFormViaJson(t *testing.T) {
 	outFile = filepath.Join(outDir, "readFormAndUpdateFormCJK.pdf")
 	createPDF(t, "pass1", inFile, inFileJSON, outFile, conf)
 }
+
+func TestCreateTaggedPDFViaJson(t *testing.T) {
+	msg := "TestCreateTaggedPDFViaJson"
+	inFileJSON := filepath.Join(inDir, "json", "create", "tagged.json")
+	outFile := filepath.Join(outDir, "tagged.pdf")
+
+	createPDF(t, msg, "", inFileJSON, outFile, conf)
+
+	r := validateConformance(t, outFile, model.PDFUA1)
+	for _, topic := range []string{"StructureTree", "TaggedContent", "AlternateDescriptions", "Headings", "Tables", "Annotations", "Navigation"} {
+		if hasViolation(r, topic) {
+			t.Fatalf("%s %s: unexpected %s violation\n%s\n", msg, outFile, topic, r)
+		}
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if _, found := ctx.RootDict.Find("StructTreeRoot"); !found {
+		t.Fatalf("%s: missing StructTreeRoot\n", msg)
+	}
+	if lang := ctx.RootDict.StringEntry("Lang"); lang == nil || *lang != "en-US" {
+		t.Fatalf("%s: missing Lang\n", msg)
+	}
+}
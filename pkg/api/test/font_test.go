@@ -18,8 +18,9 @@ package test
 
 import (
 	"fmt"
-
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
@@ -29,6 +30,11 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/draw"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
 )
 
 func writeCoreFontDemoContent(xRefTable *model.XRefTable, p model.Page, fontName string) {
@@ -128,3 +134,230 @@ func TestUserFontDemoPDF(t *testing.T) {
 		}
 	}
 }
+
+func embeddedOpenTypeFont(t *testing.T, ctx *model.Context, fontName string) (string, []byte) {
+	t.Helper()
+
+	for _, entry := range ctx.Table {
+		d, ok := entry.Object.(types.Dict)
+		if !ok || d.Subtype() == nil || *d.Subtype() != "CIDFontType0" {
+			continue
+		}
+		if bf := d.NameEntry("BaseFont"); bf == nil || !strings.HasSuffix(*bf, "+"+fontName) {
+			continue
+		}
+		fd, err := ctx.DereferenceDict(d["FontDescriptor"])
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		sd, _, err := ctx.DereferenceStreamDict(fd["FontFile3"])
+		if err != nil || sd == nil {
+			t.Fatalf("missing FontFile3: %v\n", err)
+		}
+		if err := sd.Decode(); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		subType := ""
+		if sd.Subtype() != nil {
+			subType = *sd.Subtype()
+		}
+		return subType, sd.Content
+	}
+
+	t.Fatalf("missing CIDFontType0 for %s\n", fontName)
+	return "", nil
+}
+
+func TestOpenTypeCFFFont(t *testing.T) {
+	msg := "TestOpenTypeCFFFont"
+	fontName := "CFFTest"
+
+	bb, err := os.ReadFile(filepath.Join(inDir, "fonts", fontName+".otf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	f, err := sfnt.Parse(bb)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	font.UserFontMetricsLock.RLock()
+	ttf, ok := font.UserFontMetrics[fontName]
+	font.UserFontMetricsLock.RUnlock()
+	if !ok {
+		t.Fatalf("%s: %s not installed\n", msg, fontName)
+	}
+
+	// Compare metrics extracted at installation against the font program.
+	if !ttf.CFF {
+		t.Fatalf("%s: %s not flagged as CFF\n", msg, fontName)
+	}
+	if ttf.GlyphCount != f.NumGlyphs() {
+		t.Fatalf("%s: glyph count want:%d got:%d\n", msg, f.NumGlyphs(), ttf.GlyphCount)
+	}
+	var b sfnt.Buffer
+	ppem := fixed.I(ttf.UnitsPerEm)
+	for gid, w := range ttf.GlyphWidths {
+		adv, err := f.GlyphAdvance(&b, sfnt.GlyphIndex(gid), ppem, xfont.HintingNone)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if want := adv.Round() * 1000 / ttf.UnitsPerEm; w != want {
+			t.Fatalf("%s: glyph %d width want:%d got:%d\n", msg, gid, want, w)
+		}
+	}
+	for _, r := range "01Q" {
+		gid, err := f.GlyphIndex(&b, r)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if uint16(gid) != ttf.Chars[uint32(r)] {
+			t.Fatalf("%s: glyph index for %c want:%d got:%d\n", msg, r, gid, ttf.Chars[uint32(r)])
+		}
+	}
+
+	// Stamp using a subset of the available glyphs.
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join(outDir, "stampOpenTypeCFF.pdf")
+	desc := "font:" + fontName + ", scale:.5 rel, rot:0"
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, "0Q", desc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	subType, bb := embeddedOpenTypeFont(t, ctx, fontName)
+	if subType != "OpenType" {
+		t.Fatalf("%s: FontFile3 Subtype want:OpenType got:%s\n", msg, subType)
+	}
+
+	// Used glyphs survive subsetting, all others are empty.
+	f, err = sfnt.Parse(bb)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if f.NumGlyphs() != ttf.GlyphCount {
+		t.Fatalf("%s: subset glyph count want:%d got:%d\n", msg, ttf.GlyphCount, f.NumGlyphs())
+	}
+	for _, r := range "01Q" {
+		segs, err := f.LoadGlyph(&b, sfnt.GlyphIndex(ttf.Chars[uint32(r)]), ppem, nil)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if used := r != '1'; used != (len(segs) > 0) {
+			t.Fatalf("%s: glyph for %c used:%t outline segments:%d\n", msg, r, used, len(segs))
+		}
+	}
+}
+
+// fontsByName returns the embedding state for all top level fonts of inFile.
+func fontsByName(t *testing.T, msg, inFile string) map[string]bool {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	m := map[string]bool{}
+	for objNr, entry := range ctx.Table {
+		d, ok := entry.Object.(types.Dict)
+		if !ok || d.Type() == nil || *d.Type() != "Font" {
+			continue
+		}
+		if st := d.Subtype(); st == nil || *st == "Type3" || strings.HasPrefix(*st, "CIDFontType") {
+			continue
+		}
+		_, fontName, err := pdffont.Name(ctx.XRefTable, d, objNr)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if m[fontName], err = pdffont.Embedded(ctx.XRefTable, d, objNr); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+	}
+	return m
+}
+
+func TestEmbedFonts(t *testing.T) {
+	msg := "TestEmbedFonts"
+	inFile := filepath.Join(outDir, "embedFontsIn.pdf")
+	outFile := filepath.Join(outDir, "embedFonts.pdf")
+
+	// Stamp text using a core font which does not get embedded.
+	desc := "font:Helvetica, scale:.5 rel, rot:0"
+	if err := api.AddTextWatermarksFile(filepath.Join(inDir, "mountain.pdf"), inFile, nil, true, "Grüße €", desc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if embedded, ok := fontsByName(t, msg, inFile)["Helvetica"]; !ok || embedded {
+		t.Fatalf("%s: want non embedded Helvetica\n", msg)
+	}
+
+	for _, fontMap := range []map[string]string{
+		nil, // No installed user font matches Helvetica.
+		{"Helvetica": "NoSuchFont"},
+		{"Helvetica": "Roboto-Regular", "NoSuchFont": "Roboto-Regular"},
+	} {
+		if err := api.EmbedFontsFile(inFile, outFile, fontMap, nil); err == nil {
+			t.Fatalf("%s: %v: want error\n", msg, fontMap)
+		}
+	}
+
+	if err := api.EmbedFontsFile(inFile, outFile, map[string]string{"Helvetica": "Roboto-Regular"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	m := fontsByName(t, msg, outFile)
+	if _, ok := m["Helvetica"]; ok {
+		t.Fatalf("%s: Helvetica not replaced\n", msg)
+	}
+	if !m["Roboto-Regular"] {
+		t.Fatalf("%s: Roboto-Regular not embedded\n", msg)
+	}
+}
+
+func TestReplaceFont(t *testing.T) {
+	msg := "TestReplaceFont"
+	inFile := filepath.Join(outDir, "replaceFontIn.pdf")
+	outFile := filepath.Join(outDir, "replaceFont.pdf")
+
+	desc := "font:DejaVuSans, scale:.5 rel, rot:0"
+	if err := api.AddTextWatermarksFile(filepath.Join(inDir, "mountain.pdf"), inFile, nil, true, "Hello Ωμέγα", desc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	for _, tt := range []struct {
+		fontName, newFontName string
+		wantErr               bool
+	}{
+		{"DejaVuSans", "Roboto-Regular", false},
+		{"DejaVuSans", "Courier", true}, // CID fonts can't be replaced by core fonts.
+		{"NoSuchFont", "Roboto-Regular", true},
+	} {
+		err := api.ReplaceFontFile(inFile, outFile, tt.fontName, tt.newFontName, nil)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: %s => %s: want error\n", msg, tt.fontName, tt.newFontName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		m := fontsByName(t, msg, outFile)
+		if _, ok := m[tt.fontName]; ok || !m[tt.newFontName] {
+			t.Fatalf("%s: %s not replaced by embedded %s: %v\n", msg, tt.fontName, tt.newFontName, m)
+		}
+	}
+}
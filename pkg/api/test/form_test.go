@@ -17,13 +17,22 @@ limitations under the License.
 package test
 
 import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/primitives"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 /**************************************************************
@@ -160,6 +169,104 @@ func TestUnlockFormFields(t *testing.T) {
 	}
 }
 
+func TestFlattenForm(t *testing.T) {
+
+	msg := "TestFlattenForm"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+
+	ss, err := listFormFieldsFile(t, inFile, conf)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	want := len(ss) - 2
+
+	// Flatten selected fields.
+	outFile := filepath.Join(outDir, "englishFlattenedFields.pdf")
+	if err := api.FlattenFormFile(inFile, outFile, []string{"dob1", "firstName1"}, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ss, err = listFormFieldsFile(t, outFile, conf)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if got := len(ss); got != want {
+		t.Fatalf("%s: want %d, got %d lines\n", msg, want, got)
+	}
+
+	// Flatten the whole form.
+	outFile = filepath.Join(outDir, "englishFlattened.pdf")
+	if err := api.FlattenFormFile(inFile, outFile, nil, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if _, err := listFormFieldsFile(t, outFile, conf); err == nil {
+		t.Fatalf("%s: form still present\n", msg)
+	}
+
+	if err := api.ValidateFile(outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestFlattenFormIndirectAP(t *testing.T) {
+	msg := "TestFlattenFormIndirectAP"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Find a merged text field widget and move its appearance dict into an indirect object.
+	var (
+		id string
+		n  types.IndirectRef
+	)
+	for objNr, entry := range ctx.Table {
+		d, ok := entry.Object.(types.Dict)
+		if !ok || d.Subtype() == nil || *d.Subtype() != "Widget" || d.NameEntry("FT") == nil || *d.NameEntry("FT") != "Tx" {
+			continue
+		}
+		ap := d.DictEntry("AP")
+		if ap == nil || d.StringEntry("T") == nil || ap.IndirectRefEntry("N") == nil {
+			continue
+		}
+		ir, err := ctx.IndRefForNewObject(ap)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		d["AP"] = *ir
+		id, n = strconv.Itoa(objNr), *ap.IndirectRefEntry("N")
+		break
+	}
+	if id == "" {
+		t.Fatalf("%s: missing text field widget\n", msg)
+	}
+
+	if ok, err := form.FlattenForm(ctx, []string{id}); err != nil || !ok {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// The existing appearance gets rendered instead of a regenerated one.
+	for i := 1; i <= ctx.PageCount; i++ {
+		_, _, inhPAttrs, err := ctx.PageDict(i, false)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		d, err := ctx.DereferenceDict(inhPAttrs.Resources["XObject"])
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		for _, o := range d {
+			if ir, ok := o.(types.IndirectRef); ok && ir.ObjectNumber == n.ObjectNumber {
+				return
+			}
+		}
+	}
+	t.Fatalf("%s: appearance %s of field %s not flattened\n", msg, n, id)
+}
+
 func TestExportForm(t *testing.T) {
 
 	inDir := filepath.Join(samplesDir, "form", "demoSinglePage")
@@ -300,3 +407,742 @@ func TestMultiFillFormCSVMerged(t *testing.T) {
 		}
 	}
 }
+
+func TestFormXFDF(t *testing.T) {
+
+	msg := "TestFormXFDF"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+	inFileXFDF := filepath.Join(outDir, "englishFill.xfdf")
+	outFile := filepath.Join(outDir, "englishXFDF.pdf")
+	outFileXFDF := filepath.Join(outDir, "englishXFDF.xfdf")
+
+	xfdf := `<?xml version="1.0" encoding="UTF-8"?>
+<xfdf xmlns="http://ns.adobe.com/xfdf/" xml:space="preserve">
+	<fields>
+		<field name="firstName1"><value>Jane</value></field>
+		<field name="cb11"><value>Yes</value></field>
+		<field name="gender1"><value>female</value></field>
+		<field name="city11"><value>Vienna</value></field>
+	</fields>
+</xfdf>
+`
+	if err := os.WriteFile(inFileXFDF, []byte(xfdf), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.FillFormXFDFFile(inFile, inFileXFDF, outFile, conf); err != nil {
+		t.Fatalf("%s fill: %v\n", msg, err)
+	}
+
+	if err := api.ExportFormXFDFFile(outFile, outFileXFDF, conf); err != nil {
+		t.Fatalf("%s export: %v\n", msg, err)
+	}
+
+	f, err := os.Open(outFileXFDF)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	x, err := model.ParseXFDF(f)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	want := map[string]string{"firstName1": "Jane", "cb11": "Yes", "gender1": "female", "city11": "Vienna"}
+	for _, fi := range x.Fields.Fields {
+		v, ok := want[fi.Name]
+		if !ok {
+			continue
+		}
+		if len(fi.Values) != 1 || fi.Values[0] != v {
+			t.Fatalf("%s: %s want %s, got %v\n", msg, fi.Name, v, fi.Values)
+		}
+		delete(want, fi.Name)
+	}
+	if len(want) > 0 {
+		t.Fatalf("%s: missing fields: %v\n", msg, want)
+	}
+}
+
+// prepareFDFForm turns english.pdf into a form using a field hierarchy
+// (person.firstName1) and a combobox with export values (city12).
+func prepareFDFForm(t *testing.T, inFile, outFile string) {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acroForm, err := ctx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := ctx.DereferenceArray(acroForm["Fields"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, o := range fields {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t1, _ := d.StringOrHexLiteralEntry("T")
+		if t1 == nil {
+			continue
+		}
+		switch *t1 {
+		case "firstName1":
+			parent := types.Dict{"T": types.StringLiteral("person"), "Kids": types.Array{o}}
+			indRef, err := ctx.IndRefForNewObject(parent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d["Parent"] = *indRef
+			fields[i] = *indRef
+		case "city12":
+			d["Opt"] = types.Array{
+				types.Array{types.StringLiteral("LDN"), types.StringLiteral("London")},
+				types.Array{types.StringLiteral("SFO"), types.StringLiteral("San Francisco")},
+				types.Array{types.StringLiteral("SYD"), types.StringLiteral("Sidney")},
+			}
+		}
+	}
+	acroForm["Fields"] = fields
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const fdfData = `%FDF-1.2
+1 0 obj
+<< /FDF << /F (english.pdf) /Fields 2 0 R >> >>
+endobj
+2 0 obj
+[ << /T (person) /Kids [ << /T (firstName1) /V (Jane) >> ] >>
+  << /T (city12) /V (LDN) >>
+  << /T (cb11) /V /Yes >>
+  << /T (gender1) /V /male >>
+  << /T (note1) /V <FEFF00C400D600DC> >> ]
+endobj
+trailer
+<< /Root 1 0 R >>
+%%EOF
+`
+
+func fdfValues(t *testing.T, ff []*form.FDFField, prefix string, m map[string]types.Object) {
+	t.Helper()
+	for _, f := range ff {
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if f.Value != nil {
+			m[name] = f.Value
+		}
+		fdfValues(t, f.Kids, name, m)
+	}
+}
+
+func TestFormFDF(t *testing.T) {
+
+	msg := "TestFormFDF"
+	inFile := filepath.Join(outDir, "englishFDF.pdf")
+	inFileFDF := filepath.Join(outDir, "englishFill.fdf")
+	outFile := filepath.Join(outDir, "englishFDFFilled.pdf")
+	outFileFDF := filepath.Join(outDir, "englishFDFFilled.fdf")
+
+	prepareFDFForm(t, filepath.Join(samplesDir, "form", "demo", "english.pdf"), inFile)
+
+	if err := os.WriteFile(inFileFDF, []byte(fdfData), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.FillFormFDFFile(inFile, inFileFDF, outFile, conf); err != nil {
+		t.Fatalf("%s fill: %v\n", msg, err)
+	}
+
+	// The combobox holds the display value.
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	formGroup, _, err := form.ExportForm(ctx.XRefTable, outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for _, cb := range formGroup.Forms[0].ComboBoxes {
+		if cb.Name == "city12" && cb.Value != "London" {
+			t.Fatalf("%s: city12 want London, got %s\n", msg, cb.Value)
+		}
+	}
+
+	if err := api.ExportFormFDFFile(outFile, outFileFDF, conf); err != nil {
+		t.Fatalf("%s export: %v\n", msg, err)
+	}
+
+	f, err := os.Open(outFileFDF)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	fdf, err := form.ParseFDF(f)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	m := map[string]types.Object{}
+	fdfValues(t, fdf.Fields, "", m)
+
+	for k, want := range map[string]string{
+		"person.firstName1": "(Jane)",
+		"city12":            "(LDN)", // export value
+		"cb11":              "/Yes",  // on state name
+		"gender1":           "/male",
+		"note1":             "ÄÖÜ",
+	} {
+		o, found := m[k]
+		if !found {
+			t.Fatalf("%s: missing %s\n", msg, k)
+		}
+		got := o.PDFString()
+		if sl, ok := o.(types.StringLiteral); ok && k == "note1" {
+			got, _ = types.StringLiteralToString(sl)
+		}
+		if got != want {
+			t.Fatalf("%s: %s want %s, got %s\n", msg, k, want, got)
+		}
+	}
+}
+
+var xfaPackets = []form.XFAPacket{
+	{Name: "preamble", Content: []byte(`<xdp:xdp xmlns:xdp="http://ns.adobe.com/xdp/">`)},
+	{Name: "config", Content: []byte(`<config xmlns="http://www.xfa.org/schema/xci/3.0/"><present><pdf><version>1.7</version></pdf></present></config>`)},
+	{Name: "template", Content: []byte(`<template xmlns="http://www.xfa.org/schema/xfa-template/3.3/"><subform name="form1" layout="position">` +
+		`<field name="firstName1"/><field name="cb11"/><field name="city12"/></subform></template>`)},
+	{Name: "datasets", Content: []byte(`<xfa:datasets xmlns:xfa="http://www.xfa.org/schema/xfa-data/1.0/"><xfa:data><form1/></xfa:data></xfa:datasets>`)},
+	{Name: "postamble", Content: []byte(`</xdp:xdp>`)},
+}
+
+// prepareXFAForm turns english.pdf into a static hybrid XFA form made up of packets.
+func prepareXFAForm(t *testing.T, inFile, outFile string, packets []form.XFAPacket, array bool) {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acroForm, err := ctx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newStream := func(bb []byte) types.IndirectRef {
+		sd, err := ctx.NewStreamDictForBuf(bb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sd.Encode(); err != nil {
+			t.Fatal(err)
+		}
+		indRef, err := ctx.IndRefForNewObject(*sd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *indRef
+	}
+
+	if array {
+		arr := types.Array{}
+		for _, p := range packets {
+			arr = append(arr, types.StringLiteral(p.Name), newStream(p.Content))
+		}
+		acroForm["XFA"] = arr
+	} else {
+		var bb []byte
+		for _, p := range packets {
+			bb = append(bb, p.Content...)
+			bb = append(bb, '\n')
+		}
+		acroForm["XFA"] = newStream(bb)
+	}
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readXFA(t *testing.T, inFile string) (*model.Context, *form.XFA, error) {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xfa, err := form.ReadXFA(ctx.XRefTable)
+
+	return ctx, xfa, err
+}
+
+func TestXFA(t *testing.T) {
+
+	msg := "TestXFA"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+	xfaFile := filepath.Join(outDir, "englishXFA.pdf")
+
+	prepareXFAForm(t, inFile, xfaFile, xfaPackets, false)
+
+	ctx, xfa, err := readXFA(t, xfaFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// The XDP stream gets split into packets.
+	var names []string
+	for _, p := range xfa.Packets {
+		names = append(names, p.Name)
+	}
+	if got, want := strings.Join(names, ","), "preamble,config,template,datasets,postamble"; got != want {
+		t.Fatalf("%s: want packets %s, got %s\n", msg, want, got)
+	}
+
+	ss, err := form.ListXFA(ctx.XRefTable)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !strings.HasPrefix(ss[0], "XFA form: static, hybrid") {
+		t.Fatalf("%s: unexpected list output: %s\n", msg, ss[0])
+	}
+
+	if err := api.ExtractXFAFile(xfaFile, outDir, conf); err != nil {
+		t.Fatalf("%s extract: %v\n", msg, err)
+	}
+	bb, err := os.ReadFile(filepath.Join(outDir, "englishXFA.xdp"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !bytes.Equal(bb, xfa.XDP()) {
+		t.Fatalf("%s: XDP mismatch\n", msg)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "englishXFA_template.xml")); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	outFile := filepath.Join(outDir, "englishXFARemoved.pdf")
+	if err := api.RemoveXFAFile(xfaFile, outFile, conf); err != nil {
+		t.Fatalf("%s remove: %v\n", msg, err)
+	}
+	if _, _, err := readXFA(t, outFile); err != form.ErrNoXFA {
+		t.Fatalf("%s: XFA still present\n", msg)
+	}
+	if err := api.ValidateFile(outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestExtractXFAPacketNames(t *testing.T) {
+
+	msg := "TestExtractXFAPacketNames"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+	xfaFile := filepath.Join(outDir, "englishXFAEvil.pdf")
+
+	packets := append([]form.XFAPacket{}, xfaPackets...)
+	packets[1].Name = "../../../xfa_escaped"
+
+	prepareXFAForm(t, inFile, xfaFile, packets, true)
+
+	dir, err := os.MkdirTemp(outDir, "xfa")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	extractDir := filepath.Join(dir, "a", "b", "c")
+	if err := os.MkdirAll(extractDir, os.ModePerm); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ExtractXFAFile(xfaFile, extractDir, conf); err != nil {
+		t.Fatalf("%s extract: %v\n", msg, err)
+	}
+
+	// The packet must not escape extractDir but fall back to its index.
+	var names []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if filepath.Dir(path) != extractDir {
+			t.Fatalf("%s: %s escaped %s\n", msg, path, extractDir)
+		}
+		names = append(names, d.Name())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	want := "englishXFAEvil.xdp,englishXFAEvil_datasets.xml,englishXFAEvil_packet1.xml,englishXFAEvil_template.xml"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("%s: want %s, got %s\n", msg, want, got)
+	}
+}
+
+func TestFillXFA(t *testing.T) {
+
+	msg := "TestFillXFA"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+	xfaFile := filepath.Join(outDir, "englishXFAArray.pdf")
+
+	prepareXFAForm(t, inFile, xfaFile, xfaPackets, true)
+
+	for _, tt := range []struct {
+		fileName, data string
+	}{
+		{"xfaData.json", `{"form1": {"firstName1": "Jane", "cb11": true, "city12": "Sidney"}}`},
+		{"xfaData.xml", `<form1><firstName1>Jane</firstName1><cb11>1</cb11><city12>Sidney</city12></form1>`},
+	} {
+		inFileData := filepath.Join(outDir, tt.fileName)
+		if err := os.WriteFile(inFileData, []byte(tt.data), 0644); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+
+		outFile := filepath.Join(outDir, "englishXFAFilled.pdf")
+		if err := api.FillXFAFile(xfaFile, inFileData, outFile, conf); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+
+		ctx, xfa, err := readXFA(t, outFile)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+
+		datasets := string(xfa.Packet("datasets").Content)
+		if !strings.Contains(datasets, "<xfa:data><form1><firstName1>Jane</firstName1><cb11>1</cb11>") {
+			t.Fatalf("%s %s: unexpected datasets: %s\n", msg, tt.fileName, datasets)
+		}
+
+		// Hybrid form: AcroForm fields are in sync.
+		formGroup, _, err := form.ExportForm(ctx.XRefTable, outFile)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		f := formGroup.Forms[0]
+		for _, tf := range f.TextFields {
+			if tf.Name == "firstName1" && tf.Value != "Jane" {
+				t.Fatalf("%s %s: firstName1 want Jane, got %s\n", msg, tt.fileName, tf.Value)
+			}
+		}
+		for _, cb := range f.CheckBoxes {
+			if cb.Name == "cb11" && !cb.Value {
+				t.Fatalf("%s %s: cb11 not checked\n", msg, tt.fileName)
+			}
+		}
+		for _, cb := range f.ComboBoxes {
+			if cb.Name == "city12" && cb.Value != "Sidney" {
+				t.Fatalf("%s %s: city12 want Sidney, got %s\n", msg, tt.fileName, cb.Value)
+			}
+		}
+	}
+}
+
+func TestFillFormScripts(t *testing.T) {
+
+	msg := "TestFillFormScripts"
+	inFile := filepath.Join(outDir, "textfieldScripts.pdf")
+	inFileFDF := filepath.Join(outDir, "textfieldScripts.fdf")
+	outFile := filepath.Join(outDir, "textfieldScriptsFilled.pdf")
+
+	createPDF(t, msg, "", filepath.Join(inDir, "json", "form", "textfieldScripts.json"), inFile, conf)
+
+	fill := func(price, quantity string) error {
+		t.Helper()
+		fdf := &form.FDF{Fields: []*form.FDFField{
+			{Name: "price", Value: types.StringLiteral(price)},
+			{Name: "quantity", Value: types.StringLiteral(quantity)},
+		}}
+		f, err := os.Create(inFileFDF)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if err := fdf.Write(f); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		f.Close()
+		return api.FillFormFDFFile(inFile, inFileFDF, outFile, conf)
+	}
+
+	// Range validation
+	if err := fill("10", "300"); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Fatalf("%s: want range error, got %v\n", msg, err)
+	}
+
+	if err := fill("10.25", "3"); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Calculation order
+	co := ctx.Form.ArrayEntry("CO")
+	if len(co) != 1 {
+		t.Fatalf("%s: want 1 calculated field, got %d\n", msg, len(co))
+	}
+
+	d, err := ctx.DereferenceDict(co[0])
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	fs, err := primitives.ParseFieldScripts(ctx.XRefTable, d)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if fs == nil || fs.Calc == nil || fs.Calc.Op != "PRD" || fs.Format == nil || fs.Format.SepStyle != 2 {
+		t.Fatalf("%s: unexpected field scripts: %v\n", msg, fs)
+	}
+
+	v, err := ctx.DereferenceText(d["V"])
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if v != "30.75" {
+		t.Fatalf("%s: total want 30.75, got %s\n", msg, v)
+	}
+
+	s, err := fs.Format.Apply(v)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if s != "30,75 €" {
+		t.Fatalf("%s: formatted total want \"30,75 €\", got %q\n", msg, s)
+	}
+}
+
+func TestParseFieldScriptsUnsupportedFormat(t *testing.T) {
+	msg := "TestParseFieldScriptsUnsupportedFormat"
+
+	xRefTable := &model.XRefTable{}
+
+	for _, tt := range []struct {
+		js  string
+		ok  bool
+		dec int
+	}{
+		{`AFNumber_Format(2, 5, 0, 0, "", true);`, false, 0},
+		{`AFNumber_Format(2, -1, 0, 0, "", true);`, false, 0},
+		{`AFNumber_Format(2, 0, 4, 0, "", true);`, false, 0},
+		{`AFPercent_Format(1, 7);`, false, 0},
+		{`AFNumber_Format(20, 0, 0, 0, "", true);`, true, 10},
+		{`AFNumber_Format(-2, 0, 0, 0, "", true);`, true, 0},
+	} {
+		d := types.Dict{"AA": types.Dict{"F": types.Dict{"S": types.Name("JavaScript"), "JS": types.StringLiteral(tt.js)}}}
+
+		fs, err := primitives.ParseFieldScripts(xRefTable, d)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.js, err)
+		}
+
+		if !tt.ok {
+			// Unsupported formats are ignored.
+			if fs != nil {
+				t.Fatalf("%s %s: want nil, got %v\n", msg, tt.js, fs.Format)
+			}
+			continue
+		}
+
+		if fs == nil || fs.Format == nil || fs.Format.Decimals != tt.dec {
+			t.Fatalf("%s %s: unexpected field scripts: %v\n", msg, tt.js, fs)
+		}
+		if _, err := fs.Format.Apply("1234.5"); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.js, err)
+		}
+	}
+}
+
+// writeXLSX writes a minimal workbook holding a notes sheet followed by a data sheet.
+// parts replaces the bodies of the corresponding zip parts.
+func writeXLSX(t *testing.T, fileName string, parts map[string]string) {
+	t.Helper()
+
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="notes" sheetId="1" r:id="rId1"/><sheet name="data" sheetId="2" r:id="rId2"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/></Relationships>`},
+		{"xl/sharedStrings.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>firstName1</t></si><si><t>dob1</t></si><si><t>cb11</t></si>
+<si><t>note1</t></si><si><t>city12</t></si><si><r><t>Ja</t></r><r><t>ne</t></r></si><si><t>London</t></si><si><t>Joe</t></si></sst>`},
+		{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="1"><numFmt numFmtId="164" formatCode="d/m/yyyy;@"/></numFmts>
+<cellXfs count="3"><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/></cellXfs></styleSheet>`},
+		{"xl/worksheets/sheet1.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Not a form</t></is></c></row></sheetData></worksheet>`},
+		{"xl/worksheets/sheet2.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>4</v></c></row>
+<row r="2"><c r="A2" t="s"><v>5</v></c><c r="B2" s="1"><v>36531</v></c><c r="C2" t="b"><v>1</v></c><c r="D2" t="inlineStr"><is><t>Person #1</t></is></c><c r="E2" t="s"><v>6</v></c></row>
+<row r="4"><c r="A4" t="s"><v>7</v></c><c r="B4" s="2"><v>37102.5</v></c><c r="C4" t="b"><v>0</v></c><c r="D4"><v>42</v></c></row>
+</sheetData></worksheet>`},
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body, ok := parts[file.name]; ok {
+			file.body = body
+		}
+		if _, err := w.Write([]byte(file.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiFillFormXLSX(t *testing.T) {
+
+	msg := "TestMultiFillFormXLSX"
+	inFile := filepath.Join(samplesDir, "form", "demoSinglePage", "english.pdf")
+	inFileXLSX := filepath.Join(outDir, "english.xlsx")
+
+	writeXLSX(t, inFileXLSX, nil)
+
+	bb, err := os.ReadFile(inFileXLSX)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	rows, err := form.ReadXLSX(bytes.NewReader(bb), int64(len(bb)), "2")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Empty rows are skipped, missing cells are empty.
+	fieldNames, records, err := form.XLSXRecords(rows, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if got, want := strings.Join(fieldNames, ","), "firstName1,dob1,cb11,note1,city12"; got != want {
+		t.Fatalf("%s: want field names %s, got %s\n", msg, want, got)
+	}
+	if got, want := strings.Join(records[1], ","), "Joe,2001-07-30,false,42,"; got != want {
+		t.Fatalf("%s: want record %s, got %s\n", msg, want, got)
+	}
+
+	if err := api.MultiFillFormXLSXFile(inFile, inFileXLSX, "data", outDir, "englishXLSX", false, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ctx, err := api.ReadContextFile(filepath.Join(outDir, "englishXLSX_01.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	formGroup, _, err := form.ExportForm(ctx.XRefTable, "")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	f := formGroup.Forms[0]
+
+	// Date cells take the format of the date field.
+	for _, df := range f.DateFields {
+		if df.Name == "dob1" && df.Value != "06.01.2000" {
+			t.Fatalf("%s: dob1 want 06.01.2000, got %s\n", msg, df.Value)
+		}
+	}
+	for _, tf := range f.TextFields {
+		if tf.Name == "firstName1" && tf.Value != "Jane" {
+			t.Fatalf("%s: firstName1 want Jane, got %s\n", msg, tf.Value)
+		}
+	}
+	for _, cb := range f.CheckBoxes {
+		if cb.Name == "cb11" && !cb.Value {
+			t.Fatalf("%s: cb11 not checked\n", msg)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "englishXLSX_02.pdf")); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.MultiFillFormXLSXFile(inFile, inFileXLSX, "missing", outDir, "englishXLSX", false, conf); err == nil {
+		t.Fatalf("%s: want error for unknown sheet\n", msg)
+	}
+}
+
+func TestReadXLSXLimits(t *testing.T) {
+	msg := "TestReadXLSXLimits"
+
+	sheet := `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>%s</sheetData></worksheet>`
+
+	for _, tt := range []struct {
+		name, sheetData string
+	}{
+		{"column", `<row r="1"><c r="ZZZZZZZZ1" t="inlineStr"><is><t>x</t></is></c></row>`},
+		{"columnXFE", `<row r="1"><c r="XFE1" t="inlineStr"><is><t>x</t></is></c></row>`},
+		{"partSize", strings.Repeat(" ", 64<<20+1)},
+	} {
+		fileName := filepath.Join(outDir, "limits.xlsx")
+		writeXLSX(t, fileName, map[string]string{"xl/worksheets/sheet2.xml": fmt.Sprintf(sheet, tt.sheetData)})
+
+		bb, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.name, err)
+		}
+
+		if _, err := form.ReadXLSX(bytes.NewReader(bb), int64(len(bb)), "2"); !errors.Is(err, form.ErrInvalidXLSX) {
+			t.Fatalf("%s %s: want ErrInvalidXLSX, got %v\n", msg, tt.name, err)
+		}
+	}
+
+	// XFD is the last valid column.
+	fileName := filepath.Join(outDir, "limits.xlsx")
+	writeXLSX(t, fileName, map[string]string{"xl/worksheets/sheet2.xml": fmt.Sprintf(sheet,
+		`<row r="1"><c r="A1" t="inlineStr"><is><t>a</t></is></c><c r="XFD1" t="inlineStr"><is><t>b</t></is></c></row>`)})
+
+	bb, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	rows, err := form.ReadXLSX(bytes.NewReader(bb), int64(len(bb)), "2")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(rows) != 1 || len(rows[0]) != 16384 || rows[0][16383].Value != "b" {
+		t.Fatalf("%s: unexpected rows\n", msg)
+	}
+}
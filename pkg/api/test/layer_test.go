@@ -0,0 +1,374 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func layersFile(t *testing.T, fileName string) map[string]pdfcpu.Layer {
+	t.Helper()
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", fileName, err)
+	}
+	defer f.Close()
+
+	ll, err := api.Layers(f, nil)
+	if err != nil {
+		t.Fatalf("%s layers: %v\n", fileName, err)
+	}
+
+	m := map[string]pdfcpu.Layer{}
+	for _, l := range ll {
+		m[l.Name] = l
+	}
+
+	return m
+}
+
+// layerContent returns the number of form XObjects painted on page 1 of fileName by layer name.
+func layerContent(t *testing.T, fileName string) map[string]int {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fileName, err)
+	}
+
+	d, _, inhPAttrs, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fileName, err)
+	}
+
+	bb, err := ctx.PageContent(d, 1)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fileName, err)
+	}
+
+	xObjects, err := ctx.DereferenceDict(inhPAttrs.Resources["XObject"])
+	if err != nil {
+		t.Fatalf("%s: %v\n", fileName, err)
+	}
+
+	m := map[string]int{}
+	for id, o := range xObjects {
+		if !bytes.Contains(bb, []byte("/"+id+" Do")) {
+			continue
+		}
+		sd, _, err := ctx.DereferenceStreamDict(o)
+		if err != nil {
+			t.Fatalf("%s: %v\n", fileName, err)
+		}
+		ocg, err := ctx.DereferenceDict(sd.Dict["OC"])
+		if err != nil {
+			t.Fatalf("%s: %v\n", fileName, err)
+		}
+		if ocg == nil {
+			continue
+		}
+		name, err := model.Text(ocg["Name"])
+		if err != nil {
+			t.Fatalf("%s: %v\n", fileName, err)
+		}
+		m[name]++
+	}
+
+	return m
+}
+
+// addForeignArtifact paints a form on layer onto page 1 of fileName using the pagination artifact markup of watermarks.
+// The layer gets marked as pagination artifact like layers of other producers would be.
+func addForeignArtifact(t *testing.T, fileName, layer string) {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fileName, err)
+	}
+
+	newStream := func(d types.Dict, bb []byte) types.IndirectRef {
+		sd, err := ctx.NewStreamDictForBuf(bb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range d {
+			sd.Insert(k, v)
+		}
+		if err := sd.Encode(); err != nil {
+			t.Fatal(err)
+		}
+		ir, err := ctx.IndRefForNewObject(*sd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *ir
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ocp, err := ctx.DereferenceDict(rootDict["OCProperties"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ocgs, err := ctx.DereferenceArray(ocp["OCGs"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ocgIndRef *types.IndirectRef
+	for _, o := range ocgs {
+		ir, _ := o.(types.IndirectRef)
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name, _ := model.Text(d["Name"]); name == layer {
+			d["Usage"] = types.Dict{"PageElement": types.Dict{"Subtype": types.Name("HF")}}
+			ocgIndRef = &ir
+		}
+	}
+	if ocgIndRef == nil {
+		t.Fatalf("%s: missing layer %s\n", fileName, layer)
+	}
+
+	form := newStream(types.Dict{
+		"Type":    types.Name("XObject"),
+		"Subtype": types.Name("Form"),
+		"BBox":    types.NewNumberArray(0, 0, 100, 20),
+		"OC":      *ocgIndRef,
+	}, []byte("0 0 100 20 re f"))
+
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := ctx.DereferenceDict(pageDict["Resources"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	xObjects, err := ctx.DereferenceDict(res["XObject"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xObjects == nil {
+		xObjects = types.Dict{}
+		res["XObject"] = xObjects
+	}
+	xObjects["Fm99"] = form
+
+	content := newStream(types.Dict{}, []byte(" /Artifact <</Subtype /Watermark /Type /Pagination >>BDC q /Fm99 Do Q EMC "))
+
+	o, err := ctx.Dereference(pageDict["Contents"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr, ok := o.(types.Array)
+	if !ok {
+		arr = types.Array{pageDict["Contents"]}
+	}
+	pageDict["Contents"] = append(arr, content)
+
+	if err := api.WriteContextFile(ctx, fileName); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	msg := "TestLayers"
+
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenLayers.pdf")
+
+	// Place a watermark and a stamp on separate layers.
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, false, "Draft", "layer:Draft, rot:45", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.AddTextWatermarksFile(outFile, "", nil, true, "Confidential", "layer:Notes", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.AddLayerFile(outFile, "", "Empty", true, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if c := layerContent(t, outFile); c["Draft"] != 1 || c["Notes"] != 1 || c["Empty"] != 0 {
+		t.Fatalf("%s: unexpected layer content: %v\n", msg, c)
+	}
+
+	m := layersFile(t, outFile)
+	for _, name := range []string{"Draft", "Notes", "Empty"} {
+		l, ok := m[name]
+		if !ok {
+			t.Fatalf("%s: missing layer %s\n", msg, name)
+		}
+		if !l.Visible {
+			t.Fatalf("%s: layer %s should be visible\n", msg, name)
+		}
+	}
+
+	if err := api.AddLayerFile(outFile, "", "Empty", true, nil); err == nil {
+		t.Fatalf("%s: adding duplicate layer should fail\n", msg)
+	}
+
+	if err := api.HideLayersFile(outFile, "", []string{"Notes"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if m = layersFile(t, outFile); m["Notes"].Visible {
+		t.Fatalf("%s: layer Notes should be hidden\n", msg)
+	}
+
+	if err := api.ShowLayersFile(outFile, "", []string{"Notes"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if m = layersFile(t, outFile); !m["Notes"].Visible {
+		t.Fatalf("%s: layer Notes should be visible\n", msg)
+	}
+
+	if err := api.HideLayersFile(outFile, "", []string{"Unknown"}, nil); err == nil {
+		t.Fatalf("%s: hiding unknown layer should fail\n", msg)
+	}
+
+	// Remove the stamp layer including its content.
+	outFile2 := filepath.Join(outDir, "WaldenLayersRemoved.pdf")
+	if err := api.RemoveLayersFile(outFile, outFile2, []string{"Notes"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile2, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if m = layersFile(t, outFile2); len(m) != 2 {
+		t.Fatalf("%s: want 2 layers, got %d\n", msg, len(m))
+	}
+	if c := layerContent(t, outFile2); c["Draft"] != 1 || c["Notes"] != 0 {
+		t.Fatalf("%s: unexpected layer content: %v\n", msg, c)
+	}
+
+	// Remove the watermark, the remaining layers survive.
+	if err := api.RemoveWatermarksFile(outFile2, "", nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if c := layerContent(t, outFile2); c["Draft"] != 0 {
+		t.Fatalf("%s: watermark not removed: %v\n", msg, c)
+	}
+	if m = layersFile(t, outFile2); len(m) != 2 {
+		t.Fatalf("%s: want 2 layers, got %d\n", msg, len(m))
+	}
+
+	// Flatten all layers.
+	outFile3 := filepath.Join(outDir, "WaldenLayersFlattened.pdf")
+	if err := api.HideLayersFile(outFile, outFile3, []string{"Draft"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.FlattenLayersFile(outFile3, "", nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile3, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if m = layersFile(t, outFile3); len(m) != 0 {
+		t.Fatalf("%s: want no layers, got %d\n", msg, len(m))
+	}
+	if c := layerContent(t, outFile3); len(c) != 0 {
+		t.Fatalf("%s: unexpected layer content: %v\n", msg, c)
+	}
+}
+
+func TestWatermarkOnExistingLayer(t *testing.T) {
+	msg := "TestWatermarkOnExistingLayer"
+
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenUserLayer.pdf")
+
+	if err := api.AddLayerFile(inFile, outFile, "Notes", true, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Placing a watermark on a user layer must not turn it into a watermark layer.
+	if err := api.AddTextWatermarksFile(outFile, "", nil, true, "Confidential", "layer:Notes", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if l := layersFile(t, outFile)["Notes"]; len(l.Usage) > 0 {
+		t.Fatalf("%s: layer Notes modified: %v\n", msg, l.Usage)
+	}
+
+	// A layer created for watermarks gets marked as such.
+	if err := api.AddTextWatermarksFile(outFile, "", nil, true, "Draft", "layer:Draft", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if l := layersFile(t, outFile)["Draft"]; l.Usage["PageElement"] != "Subtype=FG" {
+		t.Fatalf("%s: layer Draft not marked: %v\n", msg, l.Usage)
+	}
+}
+
+func TestRemoveWatermarkFromExistingLayer(t *testing.T) {
+	msg := "TestRemoveWatermarkFromExistingLayer"
+
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenUserLayerRemoveWM.pdf")
+
+	// A user layer and a foreign layer holding pagination artifacts.
+	if err := api.AddLayerFile(inFile, outFile, "Notes", true, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.AddLayerFile(outFile, "", "Header", true, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	addForeignArtifact(t, outFile, "Header")
+
+	if err := api.AddTextWatermarksFile(outFile, "", nil, true, "Confidential", "layer:Notes", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if c := layerContent(t, outFile); c["Notes"] != 1 || c["Header"] != 1 {
+		t.Fatalf("%s: unexpected layer content: %v\n", msg, c)
+	}
+
+	// Only the watermark created by pdfcpu gets removed.
+	if err := api.RemoveWatermarksFile(outFile, "", nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if c := layerContent(t, outFile); c["Notes"] != 0 || c["Header"] != 1 {
+		t.Fatalf("%s: unexpected layer content: %v\n", msg, c)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	m := layersFile(t, outFile)
+	for _, name := range []string{"Notes", "Header"} {
+		if _, ok := m[name]; !ok {
+			t.Fatalf("%s: missing layer %s\n", msg, name)
+		}
+	}
+
+	// Foreign artifacts are no watermarks.
+	if err := api.RemoveWatermarksFile(outFile, "", nil, nil); err == nil {
+		t.Fatalf("%s: removing foreign artifacts should fail\n", msg)
+	}
+	if c := layerContent(t, outFile); c["Header"] != 1 {
+		t.Fatalf("%s: foreign content removed: %v\n", msg, c)
+	}
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package test
 
 import (
+	"bytes"
 	"path/filepath"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 func TestOptimize(t *testing.T) {
@@ -41,3 +44,105 @@ func TestOptimize(t *testing.T) {
 		t.Fatalf("%s: %v\n", msg, err)
 	}
 }
+
+// jbig2BlankPage is a JBIG2 embedded stream consisting of a single page information segment for a blank 8 x 2 page.
+var jbig2BlankPage = []byte{
+	0, 0, 0, 0, 48, 0, 1, 0, 0, 0, 19, // segment header
+	0, 0, 0, 8, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // page information
+}
+
+func jbig2ImageObject() types.StreamDict {
+	streamLength := int64(len(jbig2BlankPage))
+	sd := types.NewStreamDict(
+		types.Dict(map[string]types.Object{
+			"Type":             types.Name("XObject"),
+			"Subtype":          types.Name("Image"),
+			"Width":            types.Integer(8),
+			"Height":           types.Integer(2),
+			"BitsPerComponent": types.Integer(1),
+			"ColorSpace":       types.Name("DeviceGray"),
+			"Filter":           types.Name(filter.JBIG2),
+			"Length":           types.Integer(len(jbig2BlankPage)),
+		}),
+		0, &streamLength, nil,
+		[]types.PDFFilter{{Name: filter.JBIG2}},
+	)
+	sd.Raw = jbig2BlankPage
+	return sd
+}
+
+func TestOptimizeJBIG2Images(t *testing.T) {
+	msg := "TestOptimizeJBIG2Images"
+	inFile := filepath.Join(inDir, "empty.pdf")
+	outFile := filepath.Join(outDir, "jbig2Images.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Add two identical JBIG2 images to page 1.
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	xObjDict := types.Dict{}
+	for _, id := range []string{"Im1", "Im2"} {
+		indRef, err := ctx.IndRefForNewObject(jbig2ImageObject())
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		xObjDict[id] = *indRef
+	}
+	pageDict["Resources"] = types.Dict{"XObject": xObjDict}
+	sd, err := ctx.NewStreamDictForBuf([]byte("q 80 0 0 20 0 0 cm /Im1 Do Q q 80 0 0 20 0 40 cm /Im2 Do Q"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := sd.Encode(); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	indRef, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	pageDict["Contents"] = *indRef
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Optimizing must neither re-encode nor drop the JBIG2 image data.
+	if err := api.OptimizeFile(outFile, "", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if ctx, err = api.ReadContextFile(outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if pageDict, _, _, err = ctx.PageDict(1, false); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	resDict, err := ctx.DereferenceDict(pageDict["Resources"])
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if xObjDict, err = ctx.DereferenceDict(resDict["XObject"]); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ir1, ir2 := xObjDict.IndirectRefEntry("Im1"), xObjDict.IndirectRefEntry("Im2")
+	if ir1 == nil || ir2 == nil {
+		t.Fatalf("%s: missing images: %s\n", msg, xObjDict)
+	}
+	if ir1.ObjectNumber != ir2.ObjectNumber {
+		t.Errorf("%s: duplicate images not merged: %s %s\n", msg, ir1, ir2)
+	}
+
+	if sd, _, err = ctx.DereferenceStreamDict(*ir1); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !bytes.Equal(sd.Raw, jbig2BlankPage) {
+		t.Errorf("%s: JBIG2 image data changed: % X\n", msg, sd.Raw)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func pageLabelStringsFile(t *testing.T, fileName string) []string {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fileName)
+	if err != nil {
+		t.Fatalf("%s ReadContextFile: %v\n", fileName, err)
+	}
+
+	ss, err := pdfcpu.PageLabelStrings(ctx)
+	if err != nil {
+		t.Fatalf("%s PageLabelStrings: %v\n", fileName, err)
+	}
+
+	return ss
+}
+
+func TestPageLabels(t *testing.T) {
+	msg := "TestPageLabels"
+
+	inFile := filepath.Join(inDir, "zineTest.pdf")
+	outFile := filepath.Join(outDir, "pageLabels.pdf")
+
+	labels := []pdfcpu.PageLabel{
+		{PageFrom: 1, Style: "r"},
+		{PageFrom: 3, Style: "D", Prefix: "A-"},
+		{PageFrom: 5, Style: "R", Start: 4},
+		{PageFrom: 6, Style: "a", Start: 27},
+		{PageFrom: 8, Prefix: "Cover"},
+	}
+
+	if err := api.SetPageLabelsFile(inFile, outFile, labels, nil); err != nil {
+		t.Fatalf("%s set: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	f, err := os.Open(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	pls, err := api.PageLabels(f, nil)
+	if err != nil {
+		t.Fatalf("%s PageLabels: %v\n", msg, err)
+	}
+	if len(pls) != len(labels) {
+		t.Fatalf("%s: want %d ranges, got %d\n", msg, len(labels), len(pls))
+	}
+
+	want := []string{"i", "ii", "A-1", "A-2", "IV", "aa", "bb", "Cover"}
+	if got := pageLabelStringsFile(t, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+
+	// Replace the Roman range and remove the prefix only range.
+	if err := api.AddPageLabelsFile(outFile, "", []pdfcpu.PageLabel{{PageFrom: 5, Style: "A"}}, nil); err != nil {
+		t.Fatalf("%s add: %v\n", msg, err)
+	}
+	if err := api.RemovePageLabelsFile(outFile, "", []int{8}, nil); err != nil {
+		t.Fatalf("%s remove: %v\n", msg, err)
+	}
+
+	want = []string{"i", "ii", "A-1", "A-2", "A", "aa", "bb", "cc"}
+	if got := pageLabelStringsFile(t, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+
+	if err := api.RemovePageLabelsFile(outFile, "", nil, nil); err != nil {
+		t.Fatalf("%s remove all: %v\n", msg, err)
+	}
+
+	want = []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	if got := pageLabelStringsFile(t, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+}
+
+func TestPageLabelsPreserved(t *testing.T) {
+	msg := "TestPageLabelsPreserved"
+
+	inFile := filepath.Join(outDir, "pageLabelsIn.pdf")
+
+	labels := []pdfcpu.PageLabel{
+		{PageFrom: 1, Style: "r"},
+		{PageFrom: 3, Style: "D", Prefix: "A-"},
+	}
+
+	if err := api.SetPageLabelsFile(filepath.Join(inDir, "zineTest.pdf"), inFile, labels, nil); err != nil {
+		t.Fatalf("%s set: %v\n", msg, err)
+	}
+
+	// Select pages by page label.
+	outFile := filepath.Join(outDir, "pageLabelsTrim.pdf")
+	if err := api.TrimFile(inFile, outFile, []string{"{ii}-{A-2}", "{A-5}"}, nil); err != nil {
+		t.Fatalf("%s trim: %v\n", msg, err)
+	}
+	want := []string{"ii", "A-1", "A-2", "A-5"}
+	if got := pageLabelStringsFile(t, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s trim:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+
+	outFile = filepath.Join(outDir, "pageLabelsCollect.pdf")
+	if err := api.CollectFile(inFile, outFile, []string{"{A-1}", "{i}", "{i}"}, nil); err != nil {
+		t.Fatalf("%s collect: %v\n", msg, err)
+	}
+	want = []string{"A-1", "i", "i"}
+	if got := pageLabelStringsFile(t, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s collect:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+
+	if err := api.SplitFile(inFile, outDir, 4, nil); err != nil {
+		t.Fatalf("%s split: %v\n", msg, err)
+	}
+	want = []string{"A-3", "A-4", "A-5", "A-6"}
+	if got := pageLabelStringsFile(t, filepath.Join(outDir, "pageLabelsIn_5-8.pdf")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s split:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+
+	// Pages of a merged file without page labels get labelled by page number.
+	outFile = filepath.Join(outDir, "pageLabelsMerge.pdf")
+	inFiles := []string{inFile, filepath.Join(inDir, "Walden.pdf")}
+	if err := api.MergeCreateFile(inFiles, outFile, false, nil); err != nil {
+		t.Fatalf("%s merge: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	want = []string{"i", "ii", "A-1", "A-2", "A-3", "A-4", "A-5", "A-6", "9", "10"}
+	if got := pageLabelStringsFile(t, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s merge:\nwant: %v\ngot:  %v\n", msg, want, got)
+	}
+
+	if _, err := api.ParsePageSelection("{i}-{iv},!{A-1},{App-1}-"); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.TrimFile(inFile, outFile, []string{"{x}"}, nil); err == nil {
+		t.Fatalf("%s: want error for unknown page label\n", msg)
+	}
+}
@@ -0,0 +1,453 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func validateConformance(t *testing.T, inFile string, c model.Conformance) *model.ConformanceReport {
+	t.Helper()
+	msg := "validateConformance"
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	defer f.Close()
+
+	conf := model.NewDefaultConfiguration()
+	conf.Conformance = c
+
+	r, err := api.ValidateConformance(f, conf)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	return r
+}
+
+func hasViolation(r *model.ConformanceReport, topic string) bool {
+	for _, v := range r.Violations {
+		if v.Topic == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidatePDFA(t *testing.T) {
+	msg := "TestValidatePDFA"
+	inFile := filepath.Join(inDir, "test.pdf")
+
+	for _, c := range []model.Conformance{model.PDFA1B, model.PDFA2B, model.PDFA2U, model.PDFA3B, model.PDFA3U} {
+		r := validateConformance(t, inFile, c)
+		if r.Conforming() {
+			t.Fatalf("%s %s: unexpected %s conformance\n", msg, inFile, c)
+		}
+		if !hasViolation(r, "Metadata") {
+			t.Fatalf("%s %s: %s: missing Metadata violation\n%s\n", msg, inFile, c, r)
+		}
+	}
+
+	conf := model.NewDefaultConfiguration()
+	conf.Conformance = model.PDFA2B
+	if err := api.ValidateFile(inFile, conf); err == nil {
+		t.Fatalf("%s %s: expected PDF/A-2b validation to fail\n", msg, inFile)
+	}
+}
+
+func TestValidatePDFAConforming(t *testing.T) {
+	msg := "TestValidatePDFAConforming"
+	inFile := filepath.Join(inDir, "annotTest.pdf")
+	outFile := filepath.Join(outDir, "annotTestPDFA2b.pdf")
+
+	conf := model.NewDefaultConfiguration()
+	conf.Conformance = model.PDFA2B
+
+	if _, err := api.ConvertToPDFAFile(inFile, outFile, conf); err != nil {
+		t.Fatalf("%s convert: %v\n", msg, err)
+	}
+
+	if r := validateConformance(t, outFile, model.PDFA2B); len(r.Violations) > 0 {
+		t.Fatalf("%s %s: want 0 violations, got %d:\n%s\n", msg, outFile, len(r.Violations), r)
+	}
+
+	conf = model.NewDefaultConfiguration()
+	conf.Conformance = model.PDFA2B
+	if err := api.ValidateFile(outFile, conf); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+}
+
+func TestValidatePDFAAnnotationAppearance(t *testing.T) {
+	msg := "TestValidatePDFAAnnotationAppearance"
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "annotationAppearanceRD.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	_, d, err := pdfcpu.AddAnnotationToPage(ctx, 1, squareAnn, false)
+	if err != nil {
+		t.Fatalf("%s add: %v\n", msg, err)
+	}
+
+	ir, err := ctx.CreateAnnotationAppearance(d, false)
+	if err != nil {
+		t.Fatalf("%s appearance: %v\n", msg, err)
+	}
+
+	// A down appearance is not allowed for any PDF/A part.
+	d["AP"] = types.Dict(map[string]types.Object{"N": *ir, "D": *ir})
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s write: %v\n", msg, err)
+	}
+
+	for _, c := range []model.Conformance{model.PDFA1B, model.PDFA2B} {
+		r := validateConformance(t, outFile, c)
+		var found bool
+		for _, v := range r.Violations {
+			if v.Topic == "Annotations" && strings.Contains(v.Msg, "shall only contain N") {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("%s %s: missing appearance dict violation\n%s\n", msg, c, r)
+		}
+	}
+}
+
+// writeFileAttachmentAnnotation writes test.pdf with a file attachment annotation on page 1 to outFile.
+func writeFileAttachmentAnnotation(t *testing.T, msg, outFile string) {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(filepath.Join(inDir, "test.pdf"))
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	d := types.Dict(map[string]types.Object{
+		"Type":     types.Name("Annot"),
+		"Subtype":  types.Name("FileAttachment"),
+		"Rect":     types.NewRectangle(10, 10, 30, 30).Array(),
+		"F":        types.Integer(model.AnnPrint),
+		"Contents": types.StringLiteral("attachment"),
+		"FS": types.Dict(map[string]types.Object{
+			"Type": types.Name("Filespec"),
+			"F":    types.StringLiteral("attachment.txt"),
+			"UF":   types.StringLiteral("attachment.txt"),
+		}),
+	})
+
+	ir, err := ctx.CreateAnnotationAppearance(d, false)
+	if err != nil {
+		t.Fatalf("%s appearance: %v\n", msg, err)
+	}
+	d["AP"] = types.Dict(map[string]types.Object{"N": *ir})
+
+	ir, err = ctx.IndRefForNewObject(d)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+	annots, err := ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	pageDict["Annots"] = append(annots, *ir)
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s write: %v\n", msg, err)
+	}
+}
+
+func TestValidatePDFAFileAttachmentAnnotation(t *testing.T) {
+	msg := "TestValidatePDFAFileAttachmentAnnotation"
+	outFile := filepath.Join(outDir, "fileAttachmentAnnotation.pdf")
+
+	writeFileAttachmentAnnotation(t, msg, outFile)
+
+	forbidden := func(r *model.ConformanceReport) bool {
+		for _, v := range r.Violations {
+			if v.Topic == "Annotations" && strings.Contains(v.Msg, "FileAttachment annotation") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// File attachment annotations are allowed as of PDF/A-2.
+	if r := validateConformance(t, outFile, model.PDFA2B); forbidden(r) {
+		t.Fatalf("%s: unexpected file attachment annotation violation\n%s\n", msg, r)
+	}
+
+	if r := validateConformance(t, outFile, model.PDFA1B); !forbidden(r) {
+		t.Fatalf("%s: missing file attachment annotation violation\n%s\n", msg, r)
+	}
+}
+
+func TestValidatePDFAViolationPageNr(t *testing.T) {
+	msg := "TestValidatePDFAViolationPageNr"
+	inFile := filepath.Join(inDir, "bookletTestA6.pdf")
+	outFile := filepath.Join(outDir, "violationPageNr.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	// A square annotation without print flag on page 2.
+	if _, _, err := pdfcpu.AddAnnotationToPage(ctx, 2, squareAnn, false); err != nil {
+		t.Fatalf("%s add: %v\n", msg, err)
+	}
+
+	// A link on page 1 pointing to page 2.
+	pageLinkAnn := model.NewLinkAnnotation(
+		*types.NewRectangle(10, 10, 30, 30), // rect
+		0,                                   // apObjNr
+		"",                                  // contents
+		"IDLink",                            // id
+		"",                                  // modDate
+		model.AnnPrint,                      // f
+		nil,                                 // borderCol
+		&model.Destination{Typ: model.DestFit, PageNr: 2}, // dest
+		"",            // uri
+		nil,           // quad
+		false,         // border
+		0,             // borderWidth
+		model.BSSolid) // borderStyle
+	if _, _, err := pdfcpu.AddAnnotationToPage(ctx, 1, pageLinkAnn, false); err != nil {
+		t.Fatalf("%s add: %v\n", msg, err)
+	}
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s write: %v\n", msg, err)
+	}
+
+	var found bool
+	for _, v := range validateConformance(t, outFile, model.PDFA2B).Violations {
+		if !strings.HasPrefix(v.Msg, "Square annotation") {
+			continue
+		}
+		if v.PageNr != 2 {
+			t.Fatalf("%s: %s reported for page %d\n", msg, v.Msg, v.PageNr)
+		}
+		found = true
+	}
+	if !found {
+		t.Fatalf("%s: missing Square annotation violation\n", msg)
+	}
+}
+
+func TestValidatePDFAGraphicsState(t *testing.T) {
+	msg := "TestValidatePDFAGraphicsState"
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "extGStateTR.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	pageDict, _, inhPAttrs, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+
+	res := inhPAttrs.Resources.Clone().(types.Dict)
+	res["ExtGState"] = types.Dict(map[string]types.Object{
+		"GSTR": types.Dict(map[string]types.Object{
+			"Type": types.Name("ExtGState"),
+			"TR":   types.Name("Identity"),
+		}),
+	})
+	pageDict["Resources"] = res
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s write: %v\n", msg, err)
+	}
+
+	r := validateConformance(t, outFile, model.PDFA2B)
+	for _, v := range r.Violations {
+		if strings.Contains(v.Msg, "TR is forbidden") {
+			if v.Topic != "GraphicsState" {
+				t.Fatalf("%s: unexpected topic %s for %s\n", msg, v.Topic, v.Msg)
+			}
+			return
+		}
+	}
+	t.Fatalf("%s: missing TR violation\n%s\n", msg, r)
+}
+
+func TestValidatePDFAInlineImageFilter(t *testing.T) {
+	msg := "TestValidatePDFAInlineImageFilter"
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "inlineImageFilter.pdf")
+
+	for _, tt := range []struct {
+		content string
+		lzw     bool
+	}{
+		{"q BI /W 1 /H 1 /BPC 8 /CS /G /F /LZW ID 00> EI Q", true},
+		{"q BI /W 1 /H 1 /BPC 8 /CS /G /F [/AHx /LZWDecode] ID 00> EI Q", true},
+		// Names containing LZW which are not the filter value.
+		{"q BI /W 1 /H 1 /BPC 8 /F /AHx /CS /LZWGray ID 00> EI Q", false},
+		{"q BI /W 1 /H 1 /BPC 8 /F [/AHx] /CS /LZW ID 00> EI Q", false},
+	} {
+		ctx, err := api.ReadContextFile(inFile)
+		if err != nil {
+			t.Fatalf("%s readContext: %v\n", msg, err)
+		}
+
+		pageDict, _, _, err := ctx.PageDict(1, false)
+		if err != nil {
+			t.Fatalf("%s pageDict: %v\n", msg, err)
+		}
+
+		ir, err := ctx.StreamDictIndRef([]byte(tt.content))
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		o, err := ctx.Dereference(pageDict["Contents"])
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		arr, ok := o.(types.Array)
+		if !ok {
+			arr = types.Array{pageDict["Contents"]}
+		}
+		pageDict["Contents"] = append(arr, *ir)
+
+		if err := api.WriteContextFile(ctx, outFile); err != nil {
+			t.Fatalf("%s write: %v\n", msg, err)
+		}
+
+		r := validateConformance(t, outFile, model.PDFA2B)
+		lzw := false
+		for _, v := range r.Violations {
+			if strings.Contains(v.Msg, "inline image: LZWDecode") {
+				lzw = true
+			}
+		}
+		if lzw != tt.lzw {
+			t.Fatalf("%s %s: want LZW violation: %t\n%s\n", msg, tt.content, tt.lzw, r)
+		}
+	}
+}
+
+func TestValidatePDFAAllPDFs(t *testing.T) {
+	for _, fn := range AllPDFs(t, inDir) {
+		// The check itself is expected to succeed for any valid PDF.
+		validateConformance(t, filepath.Join(inDir, fn), model.PDFA2U)
+	}
+}
+
+func TestConvertToPDFA(t *testing.T) {
+	msg := "TestConvertToPDFA"
+
+	for _, c := range []model.Conformance{model.PDFA2B, model.PDFA3B} {
+		inFile := filepath.Join(inDir, "annotTest.pdf")
+		outFile := filepath.Join(outDir, "annotTestPDFA.pdf")
+
+		conf := model.NewDefaultConfiguration()
+		conf.Conformance = c
+
+		if _, err := api.ConvertToPDFAFile(inFile, outFile, conf); err != nil {
+			t.Fatalf("%s %s: %s: %v\n", msg, inFile, c, err)
+		}
+
+		r := validateConformance(t, outFile, c)
+		if !r.Conforming() {
+			t.Fatalf("%s %s: %s: not conforming:\n%s\n", msg, outFile, c, r)
+		}
+	}
+}
+
+func TestConvertToPDFAKeepsFileAttachmentAnnotation(t *testing.T) {
+	msg := "TestConvertToPDFAKeepsFileAttachmentAnnotation"
+	inFile := filepath.Join(outDir, "fileAttachmentAnnotationIn.pdf")
+	outFile := filepath.Join(outDir, "fileAttachmentAnnotationPDFA.pdf")
+
+	writeFileAttachmentAnnotation(t, msg, inFile)
+
+	conf := model.NewDefaultConfiguration()
+	conf.Conformance = model.PDFA2B
+
+	if _, err := api.ConvertToPDFAFile(inFile, outFile, conf); err != nil {
+		t.Fatalf("%s convert: %v\n", msg, err)
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	pageDict, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s pageDict: %v\n", msg, err)
+	}
+
+	annots, err := ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	for _, o := range annots {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if st := d.Subtype(); st != nil && *st == "FileAttachment" {
+			return
+		}
+	}
+
+	t.Fatalf("%s: file attachment annotation removed\n", msg)
+}
+
+func TestConvertToPDFAFontsNotEmbedded(t *testing.T) {
+	msg := "TestConvertToPDFAFontsNotEmbedded"
+	inFile := filepath.Join(inDir, "empty.pdf")
+	outFile := filepath.Join(outDir, "emptyPDFA.pdf")
+
+	r, err := api.ConvertToPDFAFile(inFile, outFile, nil)
+	if err == nil {
+		t.Fatalf("%s %s: expected conversion to fail\n", msg, inFile)
+	}
+	if r == nil || !hasViolation(r, "Fonts") {
+		t.Fatalf("%s %s: missing Fonts violation\n", msg, inFile)
+	}
+	if _, err := os.Stat(outFile); err == nil {
+		t.Fatalf("%s %s: unexpected output file\n", msg, outFile)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func TestValidatePDFUA(t *testing.T) {
+	msg := "TestValidatePDFUA"
+	inFile := filepath.Join(inDir, "test.pdf")
+
+	r := validateConformance(t, inFile, model.PDFUA1)
+	if r.Conforming() {
+		t.Fatalf("%s %s: unexpected PDF/UA-1 conformance\n", msg, inFile)
+	}
+
+	for _, topic := range []string{"Catalog", "Metadata", "StructureTree"} {
+		if !hasViolation(r, topic) {
+			t.Fatalf("%s %s: missing %s violation\n%s\n", msg, inFile, topic, r)
+		}
+	}
+
+	conf := model.NewDefaultConfiguration()
+	conf.Conformance = model.PDFUA1
+	if err := api.ValidateFile(inFile, conf); err == nil {
+		t.Fatalf("%s %s: expected PDF/UA-1 validation to fail\n", msg, inFile)
+	}
+}
+
+func TestValidatePDFUAAllPDFs(t *testing.T) {
+	for _, fn := range AllPDFs(t, inDir) {
+		// The check itself is expected to succeed for any valid PDF.
+		validateConformance(t, filepath.Join(inDir, fn), model.PDFUA1)
+	}
+}
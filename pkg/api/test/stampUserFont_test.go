@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
 func TestStampUserFont(t *testing.T) {
@@ -45,3 +46,45 @@ func TestStampUserFont(t *testing.T) {
 		}
 	}
 }
+
+func TestStampShapedUserFont(t *testing.T) {
+	msg := "TestStampShapedUserFont"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outDir := filepath.Join("..", "..", "samples", "stamp", "text", "utf8")
+
+	// Arabic script gets joined and Hebrew marks positioned using the font's OpenType layout features.
+	for _, sample := range []sample{
+		{"DejaVuSans", "Arabic", sampleArabic, true},
+		{"DejaVuSans", "Hebrew", sampleHebrew, true},
+		{"DejaVuSans", "Persian", samplePersian, true},
+	} {
+		outFile := filepath.Join(outDir, sample.lang+"Shaped.pdf")
+		desc := "font:" + sample.fontName + ", rtl:on, align:r, scale:1.0 rel, rot:0, fillc:#000000, bgcol:#ab6f30, margin:10, border:10 round, opacity:.7"
+		err := api.AddTextWatermarksFile(inFile, outFile, nil, true, sample.text, desc, nil)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, outFile, err)
+		}
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+	}
+}
+
+func TestStampFallbackFonts(t *testing.T) {
+	msg := "TestStampFallbackFonts"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join("..", "..", "samples", "stamp", "text", "utf8", "fallback.pdf")
+
+	// Characters missing in Helvetica are rendered using the first configured fallback font supporting them.
+	conf := model.NewDefaultConfiguration()
+	conf.FallbackFonts = []string{"DejaVuSans"}
+
+	text := "Grüße, Ελλάδα, Россия, שלום\nHelvetica € 100"
+	desc := "font:Helvetica, points:24, align:j, scale:1 abs, rot:0, fillc:#000000, bgcol:#ab6f30, margin:10"
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, text, desc, conf); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
@@ -199,6 +199,16 @@ func TestAddWatermarks(t *testing.T) {
 				"\"al:j, fillc:#3277d3, bgcol:#000000, rot:0\"",
 			"al:j, fillc:#3277d3, bgcol:#000000, rot:0"},
 
+		{"TestWatermarkText",
+			"Walden.pdf",
+			"TextKerning.pdf",
+			[]string{"1-"},
+			"text",
+			"AVAST! Yield To WAVY Typography\n" +
+				"using kerned and justified text lines\n" +
+				"\"kerning:on, al:j, fillc:#3277d3, bgcol:#beded9, rot:0\"",
+			"kerning:on, al:j, fillc:#3277d3, bgcol:#beded9, rot:0"},
+
 		{"TestWatermarkText",
 			"Walden.pdf",
 			"TextScaleRel25.pdf",
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func structureTreeFile(t *testing.T, fileName string) *pdfcpu.StructureTree {
+	t.Helper()
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", fileName, err)
+	}
+	defer f.Close()
+
+	st, err := api.StructureTree(f, nil)
+	if err != nil {
+		t.Fatalf("%s StructureTree: %v\n", fileName, err)
+	}
+
+	return st
+}
+
+func firstStructNode(sn []*pdfcpu.StructNode, f func(*pdfcpu.StructNode) bool) *pdfcpu.StructNode {
+	for _, n := range sn {
+		if f(n) {
+			return n
+		}
+		if n1 := firstStructNode(n.Kids, f); n1 != nil {
+			return n1
+		}
+	}
+	return nil
+}
+
+func TestStructureTree(t *testing.T) {
+	msg := "TestStructureTree"
+
+	st := structureTreeFile(t, filepath.Join(inDir, "go.pdf"))
+
+	sn := firstStructNode(st.Kids, func(sn *pdfcpu.StructNode) bool { return sn.PageNr > 0 && len(sn.MCIDs) > 0 })
+	if sn == nil {
+		t.Fatalf("%s: missing structure element with marked content\n", msg)
+	}
+
+	f, err := os.Open(filepath.Join(inDir, "test.pdf"))
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	if _, err := api.StructureTree(f, nil); err != api.ErrNoStructTree {
+		t.Fatalf("%s: want ErrNoStructTree, got: %v\n", msg, err)
+	}
+}
+
+func TestExportImportStructure(t *testing.T) {
+	msg := "TestExportImportStructure"
+
+	inFile := filepath.Join(inDir, "go.pdf")
+	outFileJSON := filepath.Join(outDir, "goStructure.json")
+	outFile := filepath.Join(outDir, "goStructure.pdf")
+
+	if err := api.ExportStructureFile(inFile, outFileJSON, nil); err != nil {
+		t.Fatalf("%s export: %v\n", msg, err)
+	}
+
+	bb, err := os.ReadFile(outFileJSON)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	st := pdfcpu.StructureTree{}
+	if err := json.Unmarshal(bb, &st); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Edit the structure: change a role, insert Alt text and add a role map entry.
+	sn := firstStructNode(st.Kids, func(sn *pdfcpu.StructNode) bool { return sn.ID > 0 && len(sn.MCIDs) > 0 })
+	if sn == nil {
+		t.Fatalf("%s: missing structure element with marked content\n", msg)
+	}
+	id := sn.ID
+	sn.Role = "Caption"
+	sn.Alt = "Gopher ∞"
+	if st.RoleMap == nil {
+		st.RoleMap = map[string]string{}
+	}
+	st.RoleMap["Chapter"] = "Sect"
+
+	if bb, err = json.Marshal(st); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := os.WriteFile(outFileJSON, bb, 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Importing must leave the caller's configuration untouched.
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationStrict
+	if err := api.ImportStructureFile(inFile, outFileJSON, outFile, conf); err != nil {
+		t.Fatalf("%s import: %v\n", msg, err)
+	}
+	if conf.ValidationMode != model.ValidationStrict || conf.Cmd == model.IMPORTSTRUCTURE {
+		t.Fatalf("%s: configuration modified\n", msg)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	st1 := structureTreeFile(t, outFile)
+
+	sn = firstStructNode(st1.Kids, func(sn *pdfcpu.StructNode) bool { return sn.ID == id })
+	if sn == nil {
+		t.Fatalf("%s: missing structure element %d\n", msg, id)
+	}
+	if sn.Role != "Caption" {
+		t.Fatalf("%s: role want: Caption, got: %s\n", msg, sn.Role)
+	}
+	if sn.Alt != "Gopher ∞" {
+		t.Fatalf("%s: alt want: Gopher ∞, got: %s\n", msg, sn.Alt)
+	}
+	if st1.RoleMap["Chapter"] != "Sect" {
+		t.Fatalf("%s: role map want: Chapter -> Sect, got: %v\n", msg, st1.RoleMap)
+	}
+}
+
+func TestImportStructureRejectsForeignObjects(t *testing.T) {
+	msg := "TestImportStructureRejectsForeignObjects"
+
+	ctx, err := api.ReadContextFile(filepath.Join(inDir, "go.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// An action dict has an S entry too but is no structure element.
+	ir, err := ctx.IndRefForNewObject(types.Dict{"S": types.Name("URI"), "URI": types.StringLiteral("https://pdfcpu.io")})
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	objNr := ir.ObjectNumber.Value()
+	js := fmt.Sprintf(`{"kids": [{"id": %d, "role": "P"}]}`, objNr)
+
+	if _, err := pdfcpu.ImportStructureTree(ctx, strings.NewReader(js)); err == nil {
+		t.Fatalf("%s: want error for object %d\n", msg, objNr)
+	}
+
+	d, err := ctx.DereferenceDict(*ir)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if s := d.NameEntry("S"); s == nil || *s != "URI" {
+		t.Fatalf("%s: action modified: %s\n", msg, d)
+	}
+}
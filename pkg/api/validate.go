@@ -25,6 +25,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
 	"github.com/pkg/errors"
 )
 
@@ -57,6 +58,10 @@ func Validate(rs io.ReadSeeker, conf *model.Configuration) error {
 		err = errors.Wrap(err, fmt.Sprintf("validation error (obj#:%d)%s", ctx.CurObj, s))
 	}
 
+	if err == nil && conf.Conformance != model.ConformanceNone {
+		err = checkConformance(ctx)
+	}
+
 	if err == nil {
 		if conf.Optimize {
 			if log.CLIEnabled() {
@@ -83,6 +88,48 @@ func Validate(rs io.ReadSeeker, conf *model.Configuration) error {
 	return err
 }
 
+func checkConformance(ctx *model.Context) error {
+	r, err := validate.CheckConformance(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.Conforming() {
+		return nil
+	}
+
+	if log.CLIEnabled() {
+		for _, s := range r.ListViolations() {
+			log.CLI.Println(s)
+		}
+	}
+
+	return errors.Errorf("pdfcpu: not %s conforming: %d violation(s)", r.Conformance, len(r.Violations))
+}
+
+// ValidateConformance validates a PDF stream read from rs and checks conformance against conf.Conformance.
+func ValidateConformance(rs io.ReadSeeker, conf *model.Configuration) (*model.ConformanceReport, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ValidateConformance: missing rs")
+	}
+
+	if conf == nil || conf.Conformance == model.ConformanceNone {
+		return nil, errors.New("pdfcpu: ValidateConformance: missing conformance level")
+	}
+	conf.Cmd = model.VALIDATE
+
+	ctx, err := ReadContext(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateContext(ctx); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("validation error (obj#:%d)", ctx.CurObj))
+	}
+
+	return validate.CheckConformance(ctx)
+}
+
 // ValidateFile validates inFile.
 func ValidateFile(inFile string, conf *model.Configuration) error {
 	if conf == nil {
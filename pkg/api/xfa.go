@@ -0,0 +1,271 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// xfaPacketFileName returns name if it is safe to use as part of a file name.
+// Packet names originate from the PDF, so anything resembling a path falls back to the packet index.
+func xfaPacketFileName(name string, i int) string {
+	if name == "" || strings.Contains(name, "..") || strings.ContainsAny(name, `/\:`) {
+		return fmt.Sprintf("packet%d", i)
+	}
+	return name
+}
+
+// ExtractXFA writes the XFA packets of rs originating from fileName into outDir.
+// Besides one XML file per packet the complete XML Data Package (XDP) is written.
+func ExtractXFA(rs io.ReadSeeker, outDir, fileName string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExtractXFA: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTXFA
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	xfa, err := form.ReadXFA(ctx.XRefTable)
+	if err != nil {
+		return err
+	}
+
+	fileName = strings.TrimSuffix(filepath.Base(fileName), ".pdf")
+
+	write := func(outFile string, bb []byte) error {
+		logWritingTo(outFile)
+		return os.WriteFile(outFile, bb, os.ModePerm)
+	}
+
+	for i, p := range xfa.Packets {
+		if p.Name == "preamble" || p.Name == "postamble" {
+			continue
+		}
+		outFile := filepath.Join(outDir, fmt.Sprintf("%s_%s.xml", fileName, xfaPacketFileName(p.Name, i)))
+		if err := write(outFile, p.Content); err != nil {
+			return err
+		}
+	}
+
+	return write(filepath.Join(outDir, fileName+".xdp"), xfa.XDP())
+}
+
+// ExtractXFAFile writes the XFA packets of inFile into outDir.
+func ExtractXFAFile(inFile, outDir string, conf *model.Configuration) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return ExtractXFA(f, outDir, inFile, conf)
+}
+
+// RemoveXFA removes the XFA form of rs so viewers use the AcroForm fields and writes the result to w.
+func RemoveXFA(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RemoveXFA: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: RemoveXFA: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEXFA
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := form.RemoveXFA(ctx.XRefTable)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return form.ErrNoXFA
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// RemoveXFAFile removes the XFA form of inFile and writes the result to outFile.
+func RemoveXFAFile(inFile, outFile string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+	}
+	logWritingTo(outFile)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return RemoveXFA(f1, f2, conf)
+}
+
+// FillXFA replaces the XFA datasets of rs with XML or JSON data read from rd and writes the result to w.
+// For hybrid forms the matching AcroForm fields get filled too.
+func FillXFA(rs io.ReadSeeker, rd io.Reader, w io.Writer, format form.DataFormat, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FillXFA: missing rs")
+	}
+
+	if rd == nil {
+		return errors.New("pdfcpu: FillXFA: missing rd")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FILLXFA
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	// TODO not necessarily so
+	ctx.RemoveSignature()
+
+	f, ok, err := form.FillXFA(ctx.XRefTable, rd, format)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if err := validateOptionValues(*f); err != nil {
+			return err
+		}
+
+		ok, pp, err := form.FillForm(ctx, form.FillDetails(f, nil), f.Pages, form.JSON)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			if log.CLIEnabled() {
+				log.CLI.Println("filling AcroForm fields...")
+			}
+			if err := fillPostProc(ctx, pp); err != nil {
+				return err
+			}
+		}
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// FillXFAFile replaces the XFA datasets of inFilePDF with data from inFileData (.xml or .json)
+// and writes the result to outFilePDF.
+func FillXFAFile(inFilePDF, inFileData, outFilePDF string, conf *model.Configuration) (err error) {
+	format := form.XML
+	if strings.HasSuffix(strings.ToLower(inFileData), ".json") {
+		format = form.JSON
+	}
+
+	var f0, f1, f2 *os.File
+
+	if f0, err = os.Open(inFileData); err != nil {
+		return err
+	}
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		f0.Close()
+		return err
+	}
+
+	tmpFile := inFilePDF + ".tmp"
+	if outFilePDF != "" && inFilePDF != outFilePDF {
+		tmpFile = outFilePDF
+	}
+	logWritingTo(outFilePDF)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		f0.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			f0.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if err = f0.Close(); err != nil {
+			return
+		}
+		if outFilePDF == "" || inFilePDF == outFilePDF {
+			err = os.Rename(tmpFile, inFilePDF)
+		}
+	}()
+
+	return FillXFA(f1, f0, f2, format, conf)
+}
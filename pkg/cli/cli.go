@@ -32,6 +32,12 @@ func Optimize(cmd *Command) ([]string, error) {
 	return nil, api.OptimizeFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
 }
 
+// ConvertToPDFA converts inFile to PDF/A and writes the result to outFile.
+func ConvertToPDFA(cmd *Command) ([]string, error) {
+	_, err := api.ConvertToPDFAFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
+	return nil, err
+}
+
 // Encrypt inFile and write result to outFile.
 func Encrypt(cmd *Command) ([]string, error) {
 	return nil, api.EncryptFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
@@ -203,6 +209,16 @@ func InstallFonts(cmd *Command) ([]string, error) {
 	return nil, api.InstallFonts(cmd.InFiles)
 }
 
+// EmbedFonts embeds non embedded fonts of inFile using installed user fonts and writes the result to outFile.
+func EmbedFonts(cmd *Command) ([]string, error) {
+	return nil, api.EmbedFontsFile(*cmd.InFile, *cmd.OutFile, cmd.StringMap, cmd.Conf)
+}
+
+// ReplaceFont replaces a font of inFile by a core font or user font and writes the result to outFile.
+func ReplaceFont(cmd *Command) ([]string, error) {
+	return nil, api.ReplaceFontFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals[0], cmd.StringVals[1], cmd.Conf)
+}
+
 // ListKeywords returns a list of keywords for inFile.
 func ListKeywords(cmd *Command) ([]string, error) {
 	return ListKeywordsFile(*cmd.InFile, cmd.Conf)
@@ -270,6 +286,41 @@ func RemoveAnnotations(cmd *Command) ([]string, error) {
 	return nil, api.RemoveAnnotationsFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.StringVals, cmd.IntVals, cmd.Conf, incr)
 }
 
+// FlattenAnnotations renders annotations into inFile's page content, removes them and writes the result to outFile.
+func FlattenAnnotations(cmd *Command) ([]string, error) {
+	return nil, api.FlattenAnnotationsFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.StringVals, cmd.Conf)
+}
+
+// ListXFA returns the XFA details of inFiles.
+func ListXFA(cmd *Command) ([]string, error) {
+	return ListXFAFile(cmd.InFiles, cmd.Conf)
+}
+
+// ExtractXFA extracts inFile's XFA packets into outDir.
+func ExtractXFA(cmd *Command) ([]string, error) {
+	return nil, api.ExtractXFAFile(*cmd.InFile, *cmd.OutDir, cmd.Conf)
+}
+
+// RemoveXFA removes inFile's XFA form and writes the result to outFile.
+func RemoveXFA(cmd *Command) ([]string, error) {
+	return nil, api.RemoveXFAFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
+}
+
+// FillXFA fills inFile's XFA datasets using XML or JSON data and writes the result to outFile.
+func FillXFA(cmd *Command) ([]string, error) {
+	return nil, api.FillXFAFile(*cmd.InFile, *cmd.InFileJSON, *cmd.OutFile, cmd.Conf)
+}
+
+// ExportAnnotations exports inFile's markup annotations to an XFDF file.
+func ExportAnnotations(cmd *Command) ([]string, error) {
+	return nil, api.ExportAnnotationsXFDFFile(*cmd.InFile, *cmd.OutFileXFDF, cmd.PageSelection, cmd.Conf)
+}
+
+// ImportAnnotations adds the annotations of an XFDF file to inFile and writes the result to outFile.
+func ImportAnnotations(cmd *Command) ([]string, error) {
+	return nil, api.ImportAnnotationsXFDFFile(*cmd.InFile, *cmd.InFileXFDF, *cmd.OutFile, cmd.Conf)
+}
+
 // ListImages returns inFiles embedded images.
 func ListImages(cmd *Command) ([]string, error) {
 	return ListImagesFile(cmd.InFiles, cmd.PageSelection, cmd.Conf)
@@ -331,18 +382,39 @@ func ResetFormFields(cmd *Command) ([]string, error) {
 	return nil, api.ResetFormFieldsFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
 }
 
+// FlattenFormFields merges some or all form fields of inFile into page content.
+func FlattenFormFields(cmd *Command) ([]string, error) {
+	return nil, api.FlattenFormFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
 // ExportFormFields returns a representation of inFile's form as outFileJSON.
 func ExportFormFields(cmd *Command) ([]string, error) {
+	if cmd.OutFileXFDF != nil {
+		return nil, api.ExportFormXFDFFile(*cmd.InFile, *cmd.OutFileXFDF, cmd.Conf)
+	}
+	if cmd.OutFileFDF != nil {
+		return nil, api.ExportFormFDFFile(*cmd.InFile, *cmd.OutFileFDF, cmd.Conf)
+	}
 	return nil, api.ExportFormFile(*cmd.InFile, *cmd.OutFileJSON, cmd.Conf)
 }
 
 // FillFormFields fills out inFile's form using data represented by inFileJSON.
 func FillFormFields(cmd *Command) ([]string, error) {
+	if cmd.InFileXFDF != nil {
+		return nil, api.FillFormXFDFFile(*cmd.InFile, *cmd.InFileXFDF, *cmd.OutFile, cmd.Conf)
+	}
+	if cmd.InFileFDF != nil {
+		return nil, api.FillFormFDFFile(*cmd.InFile, *cmd.InFileFDF, *cmd.OutFile, cmd.Conf)
+	}
 	return nil, api.FillFormFile(*cmd.InFile, *cmd.InFileJSON, *cmd.OutFile, cmd.Conf)
 }
 
-// MultiFillFormFields fills out multiple instances of inFile's form using JSON or CSV data.
+// MultiFillFormFields fills out multiple instances of inFile's form using JSON, CSV or XLSX data.
 func MultiFillFormFields(cmd *Command) ([]string, error) {
+	if cmd.StringVal != "" {
+		// XLSX sheet
+		return nil, api.MultiFillFormXLSXFile(*cmd.InFile, *cmd.InFileJSON, cmd.StringVal, *cmd.OutDir, *cmd.OutFile, cmd.BoolVal1, cmd.Conf)
+	}
 	return nil, api.MultiFillFormFile(*cmd.InFile, *cmd.InFileJSON, *cmd.OutDir, *cmd.OutFile, cmd.BoolVal1, cmd.Conf)
 }
 
@@ -386,6 +458,61 @@ func RemoveBookmarks(cmd *Command) ([]string, error) {
 	return nil, api.RemoveBookmarksFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
 }
 
+// ExportStructure returns a representation of inFile's logical structure as outFileJSON.
+func ExportStructure(cmd *Command) ([]string, error) {
+	return nil, api.ExportStructureFile(*cmd.InFile, *cmd.OutFileJSON, cmd.Conf)
+}
+
+// ImportStructure applies the logical structure found in inFileJSON to inFile and writes the result to outFile.
+func ImportStructure(cmd *Command) ([]string, error) {
+	return nil, api.ImportStructureFile(*cmd.InFile, *cmd.InFileJSON, *cmd.OutFile, cmd.Conf)
+}
+
+// ListLayers returns inFile's layers.
+func ListLayers(cmd *Command) ([]string, error) {
+	return ListLayersFile(*cmd.InFile, cmd.Conf)
+}
+
+// AddLayer adds an empty layer to inFile and writes the result to outFile.
+func AddLayer(cmd *Command) ([]string, error) {
+	return nil, api.AddLayerFile(*cmd.InFile, *cmd.OutFile, cmd.StringVal, cmd.BoolVal1, cmd.Conf)
+}
+
+// RemoveLayers removes layers including their content from inFile and writes the result to outFile.
+func RemoveLayers(cmd *Command) ([]string, error) {
+	return nil, api.RemoveLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
+// ShowLayers makes layers of inFile visible by default and writes the result to outFile.
+func ShowLayers(cmd *Command) ([]string, error) {
+	return nil, api.ShowLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
+// HideLayers hides layers of inFile by default and writes the result to outFile.
+func HideLayers(cmd *Command) ([]string, error) {
+	return nil, api.HideLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
+// FlattenLayers turns layers of inFile into regular content and writes the result to outFile.
+func FlattenLayers(cmd *Command) ([]string, error) {
+	return nil, api.FlattenLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
+// ListPageLabels returns inFile's page label ranges.
+func ListPageLabels(cmd *Command) ([]string, error) {
+	return ListPageLabelsFile(*cmd.InFile, cmd.Conf)
+}
+
+// AddPageLabels adds page label ranges to inFile and writes the result to outFile.
+func AddPageLabels(cmd *Command) ([]string, error) {
+	return nil, api.AddPageLabelsFile(*cmd.InFile, *cmd.OutFile, cmd.PageLabels, cmd.Conf)
+}
+
+// RemovePageLabels removes page label ranges from inFile and writes the result to outFile.
+func RemovePageLabels(cmd *Command) ([]string, error) {
+	return nil, api.RemovePageLabelsFile(*cmd.InFile, *cmd.OutFile, cmd.IntVals, cmd.Conf)
+}
+
 // ListPageLayout returns inFile's page layout.
 func ListPageLayout(cmd *Command) ([]string, error) {
 	return api.ListPageLayoutFile(*cmd.InFile, cmd.Conf)
@@ -28,10 +28,14 @@ type Command struct {
 	Mode              model.CommandMode
 	InFile            *string
 	InFileJSON        *string
+	InFileXFDF        *string
+	InFileFDF         *string
 	InFiles           []string
 	InDir             *string
 	OutFile           *string
 	OutFileJSON       *string
+	OutFileXFDF       *string
+	OutFileFDF        *string
 	OutDir            *string
 	PageSelection     []string
 	PWOld             *string
@@ -56,6 +60,7 @@ type Command struct {
 	Watermark         *model.Watermark
 	ViewerPreferences *model.ViewerPreferences
 	PageConf          *pdfcpu.PageConfiguration
+	PageLabels        []pdfcpu.PageLabel
 	Conf              *model.Configuration
 }
 
@@ -96,6 +101,8 @@ var cmdMap = map[model.CommandMode]func(cmd *Command) ([]string, error){
 	model.LISTINFO:                ListInfo,
 	model.CHEATSHEETSFONTS:        CreateCheatSheetsFonts,
 	model.INSTALLFONTS:            InstallFonts,
+	model.EMBEDFONTS:              EmbedFonts,
+	model.REPLACEFONT:             ReplaceFont,
 	model.LISTFONTS:               ListFonts,
 	model.LISTKEYWORDS:            processKeywords,
 	model.ADDKEYWORDS:             processKeywords,
@@ -110,6 +117,9 @@ var cmdMap = map[model.CommandMode]func(cmd *Command) ([]string, error){
 	model.CROP:                    processPageBoundaries,
 	model.LISTANNOTATIONS:         processPageAnnotations,
 	model.REMOVEANNOTATIONS:       processPageAnnotations,
+	model.FLATTENANNOTATIONS:      processPageAnnotations,
+	model.EXPORTANNOTATIONS:       processPageAnnotations,
+	model.IMPORTANNOTATIONS:       processPageAnnotations,
 	model.LISTIMAGES:              processImages,
 	model.UPDATEIMAGES:            processImages,
 	model.DUMP:                    Dump,
@@ -118,10 +128,15 @@ var cmdMap = map[model.CommandMode]func(cmd *Command) ([]string, error){
 	model.REMOVEFORMFIELDS:        processForm,
 	model.LOCKFORMFIELDS:          processForm,
 	model.UNLOCKFORMFIELDS:        processForm,
+	model.FLATTENFORMFIELDS:       processForm,
 	model.RESETFORMFIELDS:         processForm,
 	model.EXPORTFORMFIELDS:        processForm,
 	model.FILLFORMFIELDS:          processForm,
 	model.MULTIFILLFORMFIELDS:     processForm,
+	model.LISTXFA:                 processForm,
+	model.EXTRACTXFA:              processForm,
+	model.REMOVEXFA:               processForm,
+	model.FILLXFA:                 processForm,
 	model.RESIZE:                  Resize,
 	model.POSTER:                  Poster,
 	model.NDOWN:                   NDown,
@@ -144,6 +159,18 @@ var cmdMap = map[model.CommandMode]func(cmd *Command) ([]string, error){
 	model.INSPECTCERTIFICATES:     processCertificates,
 	model.IMPORTCERTIFICATES:      processCertificates,
 	model.VALIDATESIGNATURES:      processSignatures,
+	model.CONVERTPDFA:             processPDFA,
+	model.EXPORTSTRUCTURE:         processStructure,
+	model.IMPORTSTRUCTURE:         processStructure,
+	model.LISTLAYERS:              processLayers,
+	model.ADDLAYER:                processLayers,
+	model.REMOVELAYERS:            processLayers,
+	model.SHOWLAYERS:              processLayers,
+	model.HIDELAYERS:              processLayers,
+	model.FLATTENLAYERS:           processLayers,
+	model.LISTPAGELABELS:          processPageLabels,
+	model.ADDPAGELABELS:           processPageLabels,
+	model.REMOVEPAGELABELS:        processPageLabels,
 }
 
 // ValidateCommand creates a new command to validate a file.
@@ -158,6 +185,19 @@ func ValidateCommand(inFiles []string, conf *model.Configuration) *Command {
 		Conf:    conf}
 }
 
+// ConvertToPDFACommand creates a new command to convert a file to PDF/A.
+func ConvertToPDFACommand(inFile, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.CONVERTPDFA
+	return &Command{
+		Mode:    model.CONVERTPDFA,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		Conf:    conf}
+}
+
 // OptimizeCommand creates a new command to optimize a file.
 func OptimizeCommand(inFile, outFile string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -648,6 +688,34 @@ func CreateCheatSheetsFontsCommand(fontFiles []string, conf *model.Configuration
 		Conf:    conf}
 }
 
+// EmbedFontsCommand creates a new command to embed non embedded fonts using installed user fonts.
+func EmbedFontsCommand(inFile, outFile string, fontMap map[string]string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EMBEDFONTS
+	return &Command{
+		Mode:      model.EMBEDFONTS,
+		InFile:    &inFile,
+		OutFile:   &outFile,
+		StringMap: fontMap,
+		Conf:      conf}
+}
+
+// ReplaceFontCommand creates a new command to replace a font by a core font or user font.
+func ReplaceFontCommand(inFile, outFile, fontName, newFontName string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REPLACEFONT
+	return &Command{
+		Mode:       model.REPLACEFONT,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: []string{fontName, newFontName},
+		Conf:       conf}
+}
+
 // ListKeywordsCommand create a new command to list keywords.
 func ListKeywordsCommand(inFile string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -830,6 +898,49 @@ func RemoveAnnotationsCommand(inFile, outFile string, pageSelection []string, id
 		Conf:          conf}
 }
 
+// FlattenAnnotationsCommand creates a new command to flatten annotations for selected pages.
+func FlattenAnnotationsCommand(inFile, outFile string, pageSelection []string, annotTypes []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENANNOTATIONS
+	return &Command{
+		Mode:          model.FLATTENANNOTATIONS,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		StringVals:    annotTypes,
+		Conf:          conf}
+}
+
+// ExportAnnotationsCommand creates a new command to export annotations of selected pages to XFDF.
+func ExportAnnotationsCommand(inFile, outFileXFDF string, pageSelection []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTANNOTATIONS
+	return &Command{
+		Mode:          model.EXPORTANNOTATIONS,
+		InFile:        &inFile,
+		OutFileXFDF:   &outFileXFDF,
+		PageSelection: pageSelection,
+		Conf:          conf}
+}
+
+// ImportAnnotationsCommand creates a new command to import annotations from XFDF.
+func ImportAnnotationsCommand(inFile, inFileXFDF, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.IMPORTANNOTATIONS
+	return &Command{
+		Mode:       model.IMPORTANNOTATIONS,
+		InFile:     &inFile,
+		InFileXFDF: &inFileXFDF,
+		OutFile:    &outFile,
+		Conf:       conf}
+}
+
 // ListImagesCommand creates a new command to list annotations for selected pages.
 func ListImagesCommand(inFiles []string, pageSelection []string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -940,6 +1051,20 @@ func UnlockFormCommand(inFile, outFile string, fieldIDs []string, conf *model.Co
 		Conf:       conf}
 }
 
+// FlattenFormCommand creates a new command to flatten PDF form fields.
+func FlattenFormCommand(inFile, outFile string, fieldIDs []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENFORMFIELDS
+	return &Command{
+		Mode:       model.FLATTENFORMFIELDS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: fieldIDs,
+		Conf:       conf}
+}
+
 // ResetFormCommand creates a new command to lock PDF form fields.
 func ResetFormCommand(inFile, outFile string, fieldIDs []string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -967,6 +1092,32 @@ func ExportFormCommand(inFilePDF, outFileJSON string, conf *model.Configuration)
 		Conf:        conf}
 }
 
+// ExportFormXFDFCommand creates a new command to export a PDF form to XFDF.
+func ExportFormXFDFCommand(inFilePDF, outFileXFDF string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTFORMFIELDS
+	return &Command{
+		Mode:        model.EXPORTFORMFIELDS,
+		InFile:      &inFilePDF,
+		OutFileXFDF: &outFileXFDF,
+		Conf:        conf}
+}
+
+// ExportFormFDFCommand creates a new command to export a PDF form to FDF.
+func ExportFormFDFCommand(inFilePDF, outFileFDF string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTFORMFIELDS
+	return &Command{
+		Mode:       model.EXPORTFORMFIELDS,
+		InFile:     &inFilePDF,
+		OutFileFDF: &outFileFDF,
+		Conf:       conf}
+}
+
 // FillFormCommand creates a new command to fill a PDF form with data.
 func FillFormCommand(inFilePDF, inFileJSON, outFilePDF string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -981,7 +1132,35 @@ func FillFormCommand(inFilePDF, inFileJSON, outFilePDF string, conf *model.Confi
 		Conf:       conf}
 }
 
-// MultiFillFormCommand creates a new command to fill multiple PDF forms with JSON or CSV data.
+// FillFormXFDFCommand creates a new command to fill a PDF form with XFDF data.
+func FillFormXFDFCommand(inFilePDF, inFileXFDF, outFilePDF string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FILLFORMFIELDS
+	return &Command{
+		Mode:       model.FILLFORMFIELDS,
+		InFile:     &inFilePDF,
+		InFileXFDF: &inFileXFDF,
+		OutFile:    &outFilePDF,
+		Conf:       conf}
+}
+
+// FillFormFDFCommand creates a new command to fill a PDF form with FDF data.
+func FillFormFDFCommand(inFilePDF, inFileFDF, outFilePDF string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FILLFORMFIELDS
+	return &Command{
+		Mode:      model.FILLFORMFIELDS,
+		InFile:    &inFilePDF,
+		InFileFDF: &inFileFDF,
+		OutFile:   &outFilePDF,
+		Conf:      conf}
+}
+
+// MultiFillFormCommand creates a new command to fill multiple PDF forms with JSON, CSV or XLSX data.
 func MultiFillFormCommand(inFilePDF, inFileData, outDir, outFilePDF string, merge bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
@@ -997,6 +1176,65 @@ func MultiFillFormCommand(inFilePDF, inFileData, outDir, outFilePDF string, merg
 		Conf:       conf}
 }
 
+// MultiFillFormXLSXCommand creates a new command to fill multiple PDF forms with data from a sheet of an XLSX workbook.
+func MultiFillFormXLSXCommand(inFilePDF, inFileXLSX, sheet, outDir, outFilePDF string, merge bool, conf *model.Configuration) *Command {
+	cmd := MultiFillFormCommand(inFilePDF, inFileXLSX, outDir, outFilePDF, merge, conf)
+	cmd.StringVal = sheet
+	return cmd
+}
+
+// ListXFACommand creates a new command to list the XFA details of PDF forms.
+func ListXFACommand(inFiles []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTXFA
+	return &Command{
+		Mode:    model.LISTXFA,
+		InFiles: inFiles,
+		Conf:    conf}
+}
+
+// ExtractXFACommand creates a new command to extract the XFA packets of a PDF form.
+func ExtractXFACommand(inFile, outDir string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTXFA
+	return &Command{
+		Mode:   model.EXTRACTXFA,
+		InFile: &inFile,
+		OutDir: &outDir,
+		Conf:   conf}
+}
+
+// RemoveXFACommand creates a new command to remove the XFA part of a PDF form.
+func RemoveXFACommand(inFile, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEXFA
+	return &Command{
+		Mode:    model.REMOVEXFA,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		Conf:    conf}
+}
+
+// FillXFACommand creates a new command to fill the XFA datasets of a PDF form with XML or JSON data.
+func FillXFACommand(inFile, inFileData, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FILLXFA
+	return &Command{
+		Mode:       model.FILLXFA,
+		InFile:     &inFile,
+		InFileJSON: &inFileData, // TODO Fix name clash.
+		OutFile:    &outFile,
+		Conf:       conf}
+}
+
 // ResizeCommand creates a new command to scale selected pages.
 func ResizeCommand(inFile, outFile string, pageSelection []string, resize *model.Resize, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -1114,6 +1352,156 @@ func RemoveBookmarksCommand(inFile, outFile string, conf *model.Configuration) *
 		Conf:    conf}
 }
 
+// ExportStructureCommand creates a new command to export the logical structure of inFile.
+func ExportStructureCommand(inFile, outFileJSON string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTSTRUCTURE
+	return &Command{
+		Mode:        model.EXPORTSTRUCTURE,
+		InFile:      &inFile,
+		OutFileJSON: &outFileJSON,
+		Conf:        conf}
+}
+
+// ImportStructureCommand creates a new command to apply a logical structure to inFile.
+func ImportStructureCommand(inFile, inFileJSON, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.IMPORTSTRUCTURE
+	return &Command{
+		Mode:       model.IMPORTSTRUCTURE,
+		InFile:     &inFile,
+		InFileJSON: &inFileJSON,
+		OutFile:    &outFile,
+		Conf:       conf}
+}
+
+// ListLayersCommand creates a new command to list the layers of inFile.
+func ListLayersCommand(inFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTLAYERS
+	return &Command{
+		Mode:   model.LISTLAYERS,
+		InFile: &inFile,
+		Conf:   conf}
+}
+
+// AddLayerCommand creates a new command to add a layer to inFile.
+func AddLayerCommand(inFile, outFile string, name string, visible bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDLAYER
+	return &Command{
+		Mode:      model.ADDLAYER,
+		InFile:    &inFile,
+		OutFile:   &outFile,
+		StringVal: name,
+		BoolVal1:  visible,
+		Conf:      conf}
+}
+
+// RemoveLayersCommand creates a new command to remove layers including their content.
+func RemoveLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVELAYERS
+	return &Command{
+		Mode:       model.REMOVELAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// ShowLayersCommand creates a new command to make layers visible by default.
+func ShowLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SHOWLAYERS
+	return &Command{
+		Mode:       model.SHOWLAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// HideLayersCommand creates a new command to hide layers by default.
+func HideLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.HIDELAYERS
+	return &Command{
+		Mode:       model.HIDELAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// FlattenLayersCommand creates a new command to turn layers into regular content.
+func FlattenLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENLAYERS
+	return &Command{
+		Mode:       model.FLATTENLAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// ListPageLabelsCommand creates a new command to list the page labels of inFile.
+func ListPageLabelsCommand(inFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTPAGELABELS
+	return &Command{
+		Mode:   model.LISTPAGELABELS,
+		InFile: &inFile,
+		Conf:   conf}
+}
+
+// AddPageLabelsCommand creates a new command to add page label ranges to inFile.
+func AddPageLabelsCommand(inFile, outFile string, labels []pdfcpu.PageLabel, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDPAGELABELS
+	return &Command{
+		Mode:       model.ADDPAGELABELS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		PageLabels: labels,
+		Conf:       conf}
+}
+
+// RemovePageLabelsCommand creates a new command to remove page label ranges from inFile.
+func RemovePageLabelsCommand(inFile, outFile string, pageNrs []int, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEPAGELABELS
+	return &Command{
+		Mode:    model.REMOVEPAGELABELS,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		IntVals: pageNrs,
+		Conf:    conf}
+}
+
 // ListPageLayoutCommand creates a new command to list the document page layout.
 func ListPageLayoutCommand(inFile string, conf *model.Configuration) *Command {
 	if conf == nil {
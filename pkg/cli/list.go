@@ -133,7 +133,7 @@ func listBoxes(rs io.ReadSeeker, selectedPages []string, pb *model.PageBoundarie
 		return nil, err
 	}
 
-	pages, err := api.PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := api.SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +206,54 @@ func ListFormFieldsFile(inFiles []string, conf *model.Configuration) ([]string,
 	return ss, nil
 }
 
+func listXFA(rs io.ReadSeeker, conf *model.Configuration) ([]string, error) {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTXFA
+
+	ctx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return form.ListXFA(ctx.XRefTable)
+}
+
+// ListXFAFile returns the XFA details of inFiles.
+func ListXFAFile(inFiles []string, conf *model.Configuration) ([]string, error) {
+	log.SetCLILogger(nil)
+
+	ss := []string{}
+
+	for _, fn := range inFiles {
+
+		f, err := os.Open(fn)
+		if err != nil {
+			if len(inFiles) > 1 {
+				ss = append(ss, fmt.Sprintf("\ncan't open %s: %v", fn, err))
+				continue
+			}
+			return nil, err
+		}
+		defer f.Close()
+
+		output, err := listXFA(f, conf)
+		if err != nil {
+			if len(inFiles) > 1 {
+				ss = append(ss, fmt.Sprintf("\n%s:\n%v", fn, err))
+				continue
+			}
+			return nil, err
+		}
+
+		ss = append(ss, "\n"+fn+":\n")
+		ss = append(ss, output...)
+	}
+
+	return ss, nil
+}
+
 func listImages(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration) ([]string, error) {
 	if rs == nil {
 		return nil, errors.New("pdfcpu: listImages: Please provide rs")
@@ -221,7 +269,7 @@ func listImages(rs io.ReadSeeker, selectedPages []string, conf *model.Configurat
 		return nil, err
 	}
 
-	pages, err := api.PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := api.SelectPages(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -277,12 +325,7 @@ func ListInfoFile(inFile string, selectedPages []string, fonts bool, conf *model
 		return nil, err
 	}
 
-	pages, err := api.PagesForPageSelection(info.PageCount, selectedPages, false, false)
-	if err != nil {
-		return nil, err
-	}
-
-	ss, err := pdfcpu.ListInfo(info, pages, fonts)
+	ss, err := pdfcpu.ListInfo(info, info.SelectedPages, fonts)
 	if err != nil {
 		return nil, err
 	}
@@ -373,12 +416,7 @@ func listInfoFilesJSON(inFiles []string, selectedPages []string, fonts bool, con
 			return nil, err
 		}
 
-		pages, err := api.PagesForPageSelection(info.PageCount, selectedPages, false, false)
-		if err != nil {
-			return nil, err
-		}
-
-		info.Boundaries, info.Dimensions = jsonInfo(info, pages)
+		info.Boundaries, info.Dimensions = jsonInfo(info, info.SelectedPages)
 
 		infos = append(infos, info)
 	}
@@ -547,6 +585,68 @@ func ListBookmarksFile(inFile string, conf *model.Configuration) ([]string, erro
 	return listBookmarks(f, conf)
 }
 
+func listLayers(rs io.ReadSeeker, conf *model.Configuration) ([]string, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: listLayers: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTLAYERS
+
+	ctx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.LayerList(ctx)
+}
+
+// ListLayersFile returns the layers of inFile.
+func ListLayersFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return listLayers(f, conf)
+}
+
+func listPageLabels(rs io.ReadSeeker, conf *model.Configuration) ([]string, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: listPageLabels: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTPAGELABELS
+
+	ctx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.PageLabelList(ctx)
+}
+
+// ListPageLabelsFile returns the page label ranges of inFile.
+func ListPageLabelsFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return listPageLabels(f, conf)
+}
+
 func listPEM(fName string) (int, error) {
 	bb, err := os.ReadFile(fName)
 	if err != nil {
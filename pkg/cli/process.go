@@ -76,6 +76,60 @@ func processBookmarks(cmd *Command) (out []string, err error) {
 	return nil, nil
 }
 
+func processStructure(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.EXPORTSTRUCTURE:
+		return ExportStructure(cmd)
+
+	case model.IMPORTSTRUCTURE:
+		return ImportStructure(cmd)
+	}
+
+	return nil, nil
+}
+
+func processLayers(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.LISTLAYERS:
+		return ListLayers(cmd)
+
+	case model.ADDLAYER:
+		return AddLayer(cmd)
+
+	case model.REMOVELAYERS:
+		return RemoveLayers(cmd)
+
+	case model.SHOWLAYERS:
+		return ShowLayers(cmd)
+
+	case model.HIDELAYERS:
+		return HideLayers(cmd)
+
+	case model.FLATTENLAYERS:
+		return FlattenLayers(cmd)
+	}
+
+	return nil, nil
+}
+
+func processPageLabels(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.LISTPAGELABELS:
+		return ListPageLabels(cmd)
+
+	case model.ADDPAGELABELS:
+		return AddPageLabels(cmd)
+
+	case model.REMOVEPAGELABELS:
+		return RemovePageLabels(cmd)
+	}
+
+	return nil, nil
+}
+
 func processEncryption(cmd *Command) (out []string, err error) {
 	switch cmd.Mode {
 
@@ -113,6 +167,9 @@ func processForm(cmd *Command) (out []string, err error) {
 	case model.RESETFORMFIELDS:
 		return ResetFormFields(cmd)
 
+	case model.FLATTENFORMFIELDS:
+		return FlattenFormFields(cmd)
+
 	case model.EXPORTFORMFIELDS:
 		return ExportFormFields(cmd)
 
@@ -121,6 +178,18 @@ func processForm(cmd *Command) (out []string, err error) {
 
 	case model.MULTIFILLFORMFIELDS:
 		return MultiFillFormFields(cmd)
+
+	case model.LISTXFA:
+		return ListXFA(cmd)
+
+	case model.EXTRACTXFA:
+		return ExtractXFA(cmd)
+
+	case model.REMOVEXFA:
+		return RemoveXFA(cmd)
+
+	case model.FILLXFA:
+		return FillXFA(cmd)
 	}
 
 	return nil, nil
@@ -164,6 +233,15 @@ func processPageAnnotations(cmd *Command) (out []string, err error) {
 
 	case model.REMOVEANNOTATIONS:
 		out, err = RemoveAnnotations(cmd)
+
+	case model.FLATTENANNOTATIONS:
+		out, err = FlattenAnnotations(cmd)
+
+	case model.EXPORTANNOTATIONS:
+		out, err = ExportAnnotations(cmd)
+
+	case model.IMPORTANNOTATIONS:
+		out, err = ImportAnnotations(cmd)
 	}
 
 	return out, err
@@ -296,6 +374,16 @@ func processCertificates(cmd *Command) (out []string, err error) {
 	return nil, nil
 }
 
+func processPDFA(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.CONVERTPDFA:
+		return ConvertToPDFA(cmd)
+	}
+
+	return nil, nil
+}
+
 func processSignatures(cmd *Command) (out []string, err error) {
 	switch cmd.Mode {
 
@@ -57,3 +57,27 @@ func TestListAndRemoveAnnotations(t *testing.T) {
 	}
 
 }
+
+func TestFlattenAnnotations(t *testing.T) {
+	msg := "TestFlattenAnnotations"
+
+	fn := "text_annotations.pdf"
+	copyFile(t, filepath.Join(inDir, fn), filepath.Join(outDir, fn))
+	inFile := filepath.Join(outDir, fn)
+
+	// Flatten all Highlight annotations.
+	cmd := cli.FlattenAnnotationsCommand(inFile, "", nil, []string{"Highlight"}, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Flatten all markup annotations of page 1.
+	cmd = cli.FlattenAnnotationsCommand(inFile, "", []string{"1"}, nil, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := validateFile(t, inFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
@@ -120,6 +120,31 @@ func TestUnlockFormFields(t *testing.T) {
 	}
 }
 
+func TestFlattenFormFields(t *testing.T) {
+
+	for _, tt := range []struct {
+		msg     string
+		inFile  string
+		outFile string
+	}{
+		{"TestFlattenFormEN", "english.pdf", "english-flattened.pdf"},              // Core font (Helvetica)
+		{"TestFlattenFormUK", "ukrainian.pdf", "ukrainian-flattened.pdf"},          // User font (Roboto-Regular)
+		{"TestFlattenFormCJK", "chineseSimple.pdf", "chineseSimple-flattened.pdf"}, // User font CJK (UnifontMedium)
+		{"TestFlattenPersonForm", "person.pdf", "person-flattened.pdf"},            // Person Form
+	} {
+		inFile := filepath.Join(samplesDir, "form", "demoSinglePage", tt.inFile)
+		outFile := filepath.Join(outDir, tt.outFile)
+
+		cmd := cli.FlattenFormCommand(inFile, outFile, nil, conf)
+		if _, err := cli.Process(cmd); err != nil {
+			t.Fatalf("%s %s: %v\n", tt.msg, inFile, err)
+		}
+		if err := validateFile(t, outFile, conf); err != nil {
+			t.Fatalf("%s: %v\n", tt.msg, err)
+		}
+	}
+}
+
 func TestExportForm(t *testing.T) {
 
 	inDir := filepath.Join(samplesDir, "form", "demoSinglePage")
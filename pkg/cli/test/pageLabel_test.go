@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func TestPageLabelsCommand(t *testing.T) {
+	msg := "TestPageLabelsCommand"
+	inFile := filepath.Join(inDir, "bookletTestA6.pdf")
+	outFile := filepath.Join(outDir, "bookletTestA6PageLabels.pdf")
+
+	if err := copyFile(t, inFile, outFile); err != nil {
+		t.Fatalf("%s copyFile: %v\n", msg, err)
+	}
+
+	for _, s := range []string{"page:1, style:r", "page:5, prefix:A-, start:3", "page:9, style:A"} {
+		pl, err := pdfcpu.ParsePageLabelDetails(s)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, s, err)
+		}
+		cmd := cli.AddPageLabelsCommand(outFile, "", []pdfcpu.PageLabel{*pl}, conf)
+		if _, err := cli.Process(cmd); err != nil {
+			t.Fatalf("%s add: %v\n", msg, err)
+		}
+	}
+
+	cmd := cli.ListPageLabelsCommand(outFile, conf)
+	ss, err := cli.Process(cmd)
+	if err != nil {
+		t.Fatalf("%s list: %v\n", msg, err)
+	}
+	if len(ss) != 5 {
+		t.Fatalf("%s list: want 3 ranges plus header, got: %v\n", msg, ss)
+	}
+
+	// Page labels may be used for page selection.
+	pages := []string{"{i}-{iii}"}
+	for _, cmd := range []*cli.Command{
+		cli.InfoCommand([]string{outFile}, pages, false, false, conf),
+		cli.InfoCommand([]string{outFile}, pages, false, true, conf),
+		cli.ListBoxesCommand(outFile, pages, nil, conf),
+		cli.ListImagesCommand([]string{outFile}, pages, conf),
+	} {
+		if _, err := cli.Process(cmd); err != nil {
+			t.Fatalf("%s %d: %v\n", msg, cmd.Mode, err)
+		}
+	}
+
+	cmd = cli.RemovePageLabelsCommand(outFile, "", []int{9}, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s remove: %v\n", msg, err)
+	}
+
+	if err := validateFile(t, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
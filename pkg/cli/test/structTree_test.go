@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+)
+
+func TestStructureCommand(t *testing.T) {
+	msg := "TestStructureCommand"
+	inFile := filepath.Join(inDir, "CenterOfWhy.pdf")
+	outFileJSON := filepath.Join(outDir, "CenterOfWhyStructure.json")
+	outFile := filepath.Join(outDir, "CenterOfWhyStructure.pdf")
+
+	cmd := cli.ExportStructureCommand(inFile, outFileJSON, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s export %s: %v\n", msg, inFile, err)
+	}
+
+	// Apply the unmodified export.
+	cmd = cli.ImportStructureCommand(inFile, outFileJSON, outFile, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s import %s: %v\n", msg, inFile, err)
+	}
+
+	if err := validateFile(t, outFile, conf); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+}
@@ -20,72 +20,109 @@ import (
 	"bytes"
 	"io"
 
+	"github.com/pdfcpu/pdfcpu/internal/ccitt"
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pkg/errors"
-	"golang.org/x/image/ccitt"
+	xccitt "golang.org/x/image/ccitt"
 )
 
 type ccittDecode struct {
 	baseFilter
 }
 
-// Encode implements encoding for a CCITTDecode filter.
-func (f ccittDecode) Encode(r io.Reader) (io.Reader, error) {
-	// TODO
-	return nil, nil
-}
+// params returns the decode parameters of f, a missing "Rows" is derived from n bytes of image data.
+func (f ccittDecode) params(n int) (ccitt.Params, error) {
+	// <0 : Pure two-dimensional encoding (Group 4)
+	// =0 : Pure one-dimensional encoding (Group 3, 1-D)
+	// >0 : Mixed one- and two-dimensional encoding (Group 3, 2-D)
+	p := ccitt.Params{K: f.parms["K"], Columns: 1728, EndOfBlock: true}
 
-// Decode implements decoding for a CCITTDecode filter.
-func (f ccittDecode) Decode(r io.Reader) (io.Reader, error) {
-	return f.DecodeLength(r, -1)
+	if col, ok := f.parms["Columns"]; ok {
+		p.Columns = col
+	}
+	if p.Columns <= 0 {
+		return p, errors.Errorf("pdfcpu: ccitt: invalid DecodeParam \"Columns\": %d", p.Columns)
+	}
+
+	rows, ok := f.parms["Rows"]
+	if !ok {
+		if n < 0 {
+			return p, errors.New("pdfcpu: ccitt: missing DecodeParam \"Rows\"")
+		}
+		rows = n / ((p.Columns + 7) / 8)
+	}
+	p.Rows = rows
+
+	flag := func(name string, v *bool) {
+		if i, ok := f.parms[name]; ok {
+			*v = i == 1
+		}
+	}
+	flag("EndOfLine", &p.EndOfLine)
+	flag("EncodedByteAlign", &p.EncodedByteAlign)
+	flag("EndOfBlock", &p.EndOfBlock)
+	flag("BlackIs1", &p.BlackIs1)
+
+	return p, nil
 }
 
-func (f ccittDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+// Encode implements encoding for a CCITTDecode filter.
+func (f ccittDecode) Encode(r io.Reader) (io.Reader, error) {
 	if log.TraceEnabled() {
-		log.Trace.Println("DecodeCCITT begin")
+		log.Trace.Println("EncodeCCITT begin")
 	}
 
-	var ok bool
-
-	// <0 : Pure two-dimensional encoding (Group 4)
-	// =0 : Pure one-dimensional encoding (Group 3, 1-D)
-	// >0 : Mixed one- and two-dimensional encoding (Group 3, 2-D)
-	k := 0
-	k, ok = f.parms["K"]
-	if ok && k > 0 {
-		return nil, errors.New("pdfcpu: filter CCITTFax k > 0 currently unsupported")
+	bb, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
 
-	cols := 1728
-	col, ok := f.parms["Columns"]
-	if ok {
-		cols = col
+	p, err := f.params(len(bb))
+	if err != nil {
+		return nil, err
 	}
 
-	rows, ok := f.parms["Rows"]
-	if !ok {
-		return nil, errors.New("pdfcpu: ccitt: missing DecodeParam \"Rows\"")
+	bb, err = ccitt.Encode(bb, p)
+	if err != nil {
+		return nil, err
 	}
 
-	blackIs1 := false
-	v, ok := f.parms["BlackIs1"]
-	if ok && v == 1 {
-		blackIs1 = true
+	if log.TraceEnabled() {
+		log.Trace.Printf("EncodeCCITT end: %d bytes\n", len(bb))
 	}
 
-	encodedByteAlign := false
-	v, ok = f.parms["EncodedByteAlign"]
-	if ok && v == 1 {
-		encodedByteAlign = true
+	return bytes.NewBuffer(bb), nil
+}
+
+// Decode implements decoding for a CCITTDecode filter.
+func (f ccittDecode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+func (f ccittDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	if log.TraceEnabled() {
+		log.Trace.Println("DecodeCCITT begin")
 	}
 
-	opts := &ccitt.Options{Invert: blackIs1, Align: encodedByteAlign}
+	p, err := f.params(-1)
+	if err != nil {
+		return nil, err
+	}
 
-	mode := ccitt.Group3
-	if k < 0 {
-		mode = ccitt.Group4
+	if p.K >= 0 {
+		// Group 3 data usually comes without EOL codes which x/image/ccitt requires.
+		bb, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if bb, err = ccitt.Decode(bb, p); err != nil {
+			return nil, err
+		}
+		return bytes.NewBuffer(bb), nil
 	}
-	rd := ccitt.NewReader(r, ccitt.MSB, mode, cols, rows, opts)
+
+	opts := &xccitt.Options{Invert: p.BlackIs1, Align: p.EncodedByteAlign}
+	rd := xccitt.NewReader(r, xccitt.MSB, xccitt.Group4, p.Columns, p.Rows, opts)
 
 	var b bytes.Buffer
 	written, err := io.Copy(&b, rd)
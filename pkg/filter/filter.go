@@ -21,7 +21,6 @@ import (
 	"bytes"
 	"io"
 
-	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pkg/errors"
 )
 
@@ -76,16 +75,11 @@ func NewFilter(filterName string, parms map[string]int) (filter Filter, err erro
 	case DCT:
 		filter = dctDecode{baseFilter{parms}}
 
-	case JBIG2:
-		// Unsupported
-		fallthrough
-
 	case JPX:
-		// Unsupported
-		if log.InfoEnabled() {
-			log.Info.Printf("Filter not supported: <%s>", filterName)
-		}
-		err = ErrUnsupportedFilter
+		filter = jpxDecode{baseFilter{parms}}
+
+	case JBIG2:
+		filter = jbig2Decode{baseFilter: baseFilter{parms}}
 
 	default:
 		err = errors.Errorf("Invalid filter: <%s>", filterName)
@@ -17,7 +17,9 @@ limitations under the License.
 package filter_test
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -38,8 +40,8 @@ func TestFilterSupport(t *testing.T) {
 		{filter.Flate, nil},
 		{filter.CCITTFax, nil},
 		{filter.DCT, nil},
-		{filter.JBIG2, filter.ErrUnsupportedFilter},
-		{filter.JPX, filter.ErrUnsupportedFilter},
+		{filter.JBIG2, nil},
+		{filter.JPX, nil},
 		{"INVALID_FILTER", errors.New("Invalid filter: <INVALID_FILTER>")},
 	}
 	for _, tt := range filtersTests {
@@ -327,3 +329,65 @@ func TestASCII85DecodeWithCRLF(t *testing.T) {
 		})
 	}
 }
+
+func TestCCITTEncodeDecode(t *testing.T) {
+	// 4 rows of 16 pixels, 0 bits are black.
+	want := []byte{0xFF, 0xFF, 0x0F, 0xF0, 0x00, 0xFF, 0xAA, 0x55}
+
+	for _, k := range []int{-1, 0, 1, 2} {
+		t.Run(fmt.Sprintf("K=%d", k), func(t *testing.T) {
+			f, err := filter.NewFilter(filter.CCITTFax, map[string]int{"K": k, "Columns": 16, "Rows": 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			enc, err := f.Encode(bytes.NewReader(want))
+			if err != nil {
+				t.Fatalf("Encoding failed: %v", err)
+			}
+
+			dec, err := f.Decode(enc)
+			if err != nil {
+				t.Fatalf("Decoding failed: %v", err)
+			}
+
+			got, err := io.ReadAll(dec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Mismatch: got % X, want % X", got, want)
+			}
+		})
+	}
+}
+
+// jbig2BlankPage is a JBIG2 embedded stream consisting of a single page information segment for a blank 8 x 2 page.
+var jbig2BlankPage = []byte{
+	0, 0, 0, 0, 48, 0, 1, 0, 0, 0, 19, // segment header
+	0, 0, 0, 8, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // page information
+}
+
+func TestJBIG2EncodeDecode(t *testing.T) {
+	f, err := filter.NewFilter(filter.JBIG2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := f.Decode(bytes.NewReader(jbig2BlankPage))
+	if err != nil {
+		t.Fatalf("Decoding failed: %v", err)
+	}
+
+	got, err := io.ReadAll(dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0xFF, 0xFF}; !bytes.Equal(got, want) {
+		t.Errorf("Mismatch: got % X, want % X", got, want)
+	}
+
+	if _, err := f.Encode(bytes.NewReader(got)); err == nil {
+		t.Error("Encoding: want error")
+	}
+}
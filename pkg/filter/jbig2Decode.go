@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/internal/jbig2"
+	"github.com/pkg/errors"
+)
+
+type jbig2Decode struct {
+	baseFilter
+	globals []byte
+}
+
+// NewJBIG2Filter returns a JBIG2Decode filter using the global segments of the stream referenced by JBIG2Globals.
+func NewJBIG2Filter(globals []byte) Filter {
+	return jbig2Decode{globals: globals}
+}
+
+// Encode implements encoding for a JBIG2Decode filter.
+// JBIG2 encoding is not supported, JBIG2 image streams are always written using their original raw bytes.
+func (f jbig2Decode) Encode(r io.Reader) (io.Reader, error) {
+	return nil, errors.New("pdfcpu: filter JBIG2Decode: encoding unsupported")
+}
+
+// Decode implements decoding for a JBIG2Decode filter.
+func (f jbig2Decode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+// DecodeLength decodes the whole JBIG2 page into 1 bit samples where 0 represents black.
+func (f jbig2Decode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := jbig2.Decode(bb, f.globals)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(img.Bytes()), nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/internal/jpx"
+)
+
+type jpxDecode struct {
+	baseFilter
+}
+
+// Encode implements encoding for a JPXDecode filter.
+func (f jpxDecode) Encode(r io.Reader) (io.Reader, error) {
+
+	return nil, nil
+}
+
+// Decode implements decoding for a JPXDecode filter.
+func (f jpxDecode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+// DecodeLength decodes the whole JPEG 2000 image into interleaved 8 bit color samples.
+// An opacity channel is not part of the result.
+func (f jpxDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	img, err := jpx.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(img.Bytes()), nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import "unicode"
+
+// Arabic joining behaviour, see Unicode ArabicShaping.txt.
+type joiningType uint8
+
+const (
+	joinNone        joiningType = iota // U: non joining
+	joinRight                          // R: joins with the preceding character only
+	joinDual                           // D: joins on both sides
+	joinCausing                        // C: tatweel, zero width joiner
+	joinTransparent                    // T: marks
+)
+
+// Right joining characters of the Arabic and Arabic Supplement blocks.
+var arabicRightJoining = []struct{ from, thru rune }{
+	{0x0622, 0x0625}, {0x0627, 0x0627}, {0x0629, 0x0629}, {0x062F, 0x0632}, {0x0648, 0x0648},
+	{0x0671, 0x0673}, {0x0675, 0x0677}, {0x0688, 0x0699}, {0x06C0, 0x06C0}, {0x06C3, 0x06CB},
+	{0x06CD, 0x06CD}, {0x06CF, 0x06CF}, {0x06D2, 0x06D3}, {0x06D5, 0x06D5}, {0x06EE, 0x06EF},
+	{0x0759, 0x075B}, {0x076B, 0x076C}, {0x0771, 0x0771}, {0x0773, 0x0774}, {0x0778, 0x0779},
+	{0x08AA, 0x08AC}, {0x08AE, 0x08AE}, {0x08B1, 0x08B2}, {0x08B9, 0x08B9},
+}
+
+// Non joining letters within the Arabic blocks.
+var arabicNonJoining = []rune{0x0621, 0x0674, 0x06D4, 0x06DD, 0x08AD}
+
+func arabicJoiningType(r rune) joiningType {
+	switch {
+	case r == 0x0640 || r == 0x200D:
+		return joinCausing
+	case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) && r != 0x200C:
+		return joinTransparent
+	}
+	if !(r >= 0x0620 && r <= 0x06FF || r >= 0x0750 && r <= 0x077F || r >= 0x08A0 && r <= 0x08BF) {
+		return joinNone
+	}
+	for _, jr := range arabicRightJoining {
+		if r >= jr.from && r <= jr.thru {
+			return joinRight
+		}
+	}
+	for _, nj := range arabicNonJoining {
+		if r == nj {
+			return joinNone
+		}
+	}
+	if unicode.IsLetter(r) {
+		return joinDual
+	}
+	return joinNone
+}
+
+// Arabic positional feature masks.
+const (
+	maskIsol = 1 << (iota + 1)
+	maskFina
+	maskMedi
+	maskInit
+)
+
+var (
+	arabicGSUBStages = []stage{
+		{"ccmp", "locl"}, {"isol"}, {"fina"}, {"medi"}, {"init"}, {"rlig"}, {"calt"}, {"liga", "clig", "rclt"}, {"mset"},
+	}
+	arabicGPOSStages = defaultGPOSStages
+)
+
+// joinArabic sets the positional feature masks of the glyph buffer (still in logical order).
+func (s *shaper) joinArabic() {
+	prev := -1
+	var prevType joiningType
+	for i := range s.buf {
+		t := arabicJoiningType(s.buf[i].runes[0])
+		if t == joinTransparent {
+			continue
+		}
+		if t != joinNone {
+			s.buf[i].mask |= maskIsol
+		}
+		if prev >= 0 && (prevType == joinDual || prevType == joinCausing) && t != joinNone {
+			g := &s.buf[prev]
+			if g.mask&maskFina > 0 {
+				g.mask = g.mask&^maskFina | maskMedi
+			} else if g.mask&maskIsol > 0 {
+				g.mask = g.mask&^maskIsol | maskInit
+			}
+			s.buf[i].mask = s.buf[i].mask&^maskIsol | maskFina
+		}
+		prev, prevType = i, t
+	}
+}
+
+func (s *shaper) shapeArabic() {
+	s.features["isol"] = maskIsol
+	s.features["fina"] = maskFina
+	s.features["medi"] = maskMedi
+	s.features["init"] = maskInit
+	s.enableFeatures(arabicGSUBStages, arabicGPOSStages)
+
+	s.joinArabic()
+	s.removeMissingGlyphs()
+
+	scripts := []string{"arab"}
+	s.applyStages(s.l.gsub, s.l.gsub.langSys(scripts, ""), arabicGSUBStages, false)
+	s.initAdvances()
+	s.applyStages(s.l.gpos, s.l.gpos.langSys(scripts, ""), arabicGPOSStages, true)
+	s.zeroMarkWidths()
+}
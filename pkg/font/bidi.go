@@ -0,0 +1,643 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import "golang.org/x/text/unicode/bidi"
+
+// Unicode Bidirectional Algorithm for a single paragraph.
+// See https://www.unicode.org/reports/tr9/
+
+const maxBidiDepth = 125
+
+var bidiBracketPairs = map[rune]rune{
+	'(': ')', '[': ']', '{': '}',
+	0x0F3A: 0x0F3B, 0x0F3C: 0x0F3D, 0x169B: 0x169C,
+	0x2045: 0x2046, 0x207D: 0x207E, 0x208D: 0x208E,
+	0x2308: 0x2309, 0x230A: 0x230B, 0x2329: 0x232A, 0x2768: 0x2769,
+	0x276A: 0x276B, 0x276C: 0x276D, 0x276E: 0x276F, 0x2770: 0x2771,
+	0x2772: 0x2773, 0x2774: 0x2775, 0x27C5: 0x27C6, 0x27E6: 0x27E7,
+	0x27E8: 0x27E9, 0x27EA: 0x27EB, 0x27EC: 0x27ED, 0x27EE: 0x27EF,
+	0x2983: 0x2984, 0x2985: 0x2986, 0x2987: 0x2988, 0x2989: 0x298A,
+	0x298B: 0x298C, 0x298D: 0x2990, 0x298F: 0x298E, 0x2991: 0x2992,
+	0x2993: 0x2994, 0x2995: 0x2996, 0x2997: 0x2998, 0x29D8: 0x29D9,
+	0x29DA: 0x29DB, 0x29FC: 0x29FD, 0x2E22: 0x2E23, 0x2E24: 0x2E25,
+	0x2E26: 0x2E27, 0x2E28: 0x2E29, 0x3008: 0x3009, 0x300A: 0x300B,
+	0x300C: 0x300D, 0x300E: 0x300F, 0x3010: 0x3011, 0x3014: 0x3015,
+	0x3016: 0x3017, 0x3018: 0x3019, 0x301A: 0x301B, 0xFE59: 0xFE5A,
+	0xFE5B: 0xFE5C, 0xFE5D: 0xFE5E, 0xFF08: 0xFF09, 0xFF3B: 0xFF3D,
+	0xFF5B: 0xFF5D, 0xFF5F: 0xFF60, 0xFF62: 0xFF63,
+}
+
+var bidiMirrors = map[rune]rune{
+	'<': '>', '>': '<', 0x00AB: 0x00BB, 0x00BB: 0x00AB,
+	0x2039: 0x203A, 0x203A: 0x2039, 0x2264: 0x2265, 0x2265: 0x2264,
+	0x2266: 0x2267, 0x2267: 0x2266, 0x226A: 0x226B, 0x226B: 0x226A,
+	0x2282: 0x2283, 0x2283: 0x2282, 0x2286: 0x2287, 0x2287: 0x2286,
+	0x2208: 0x220B, 0x220B: 0x2208,
+}
+
+func init() {
+	for o, c := range bidiBracketPairs {
+		bidiMirrors[o] = c
+		bidiMirrors[c] = o
+	}
+}
+
+// canonicalBracket maps U+2329, U+232A to their canonical equivalents U+3008, U+3009.
+func canonicalBracket(r rune) rune {
+	switch r {
+	case 0x2329:
+		return 0x3008
+	case 0x232A:
+		return 0x3009
+	}
+	return r
+}
+
+func bidiClass(r rune) bidi.Class {
+	p, _ := bidi.LookupRune(r)
+	return p.Class()
+}
+
+func isIsolateInitiator(c bidi.Class) bool {
+	return c == bidi.LRI || c == bidi.RLI || c == bidi.FSI
+}
+
+func isRemovedByX9(c bidi.Class) bool {
+	switch c {
+	case bidi.RLE, bidi.LRE, bidi.RLO, bidi.LRO, bidi.PDF, bidi.BN:
+		return true
+	}
+	return false
+}
+
+func isNI(c bidi.Class) bool {
+	switch c {
+	case bidi.B, bidi.S, bidi.WS, bidi.ON, bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI:
+		return true
+	}
+	return false
+}
+
+// bidiDirection returns L or R for a level.
+func bidiDirection(level uint8) bidi.Class {
+	if level&1 == 1 {
+		return bidi.R
+	}
+	return bidi.L
+}
+
+type bidiParagraph struct {
+	runes     []rune
+	initTypes []bidi.Class
+	types     []bidi.Class
+	levels    []uint8
+	level     uint8
+	matchPDI  []int // index of matching PDI for isolate initiators or -1
+	matchInit []int // index of matching isolate initiator for PDIs or -1
+}
+
+// firstStrongLevel returns the level implied by the first strong character in [from, to) (P2, P3).
+func (p *bidiParagraph) firstStrongLevel(from, to int, def uint8) uint8 {
+	for i := from; i < to; i++ {
+		switch c := p.initTypes[i]; {
+		case c == bidi.L:
+			return 0
+		case c == bidi.R || c == bidi.AL:
+			return 1
+		case isIsolateInitiator(c):
+			if p.matchPDI[i] < 0 {
+				return def
+			}
+			i = p.matchPDI[i]
+		}
+	}
+	return def
+}
+
+func (p *bidiParagraph) matchIsolates() {
+	n := len(p.runes)
+	p.matchPDI = make([]int, n)
+	p.matchInit = make([]int, n)
+	for i := range p.matchPDI {
+		p.matchPDI[i], p.matchInit[i] = -1, -1
+	}
+	var stack []int
+	for i, c := range p.initTypes {
+		switch {
+		case isIsolateInitiator(c):
+			stack = append(stack, i)
+		case c == bidi.PDI && len(stack) > 0:
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			p.matchPDI[j], p.matchInit[i] = i, j
+		}
+	}
+}
+
+type bidiStatus struct {
+	level    uint8
+	override bidi.Class // ON, L or R
+	isolate  bool
+}
+
+// explicitLevels applies rules X1 - X8.
+func (p *bidiParagraph) explicitLevels() {
+	stack := []bidiStatus{{level: p.level, override: bidi.ON}}
+	overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+
+	for i, c := range p.initTypes {
+		top := stack[len(stack)-1]
+		switch c {
+
+		case bidi.RLE, bidi.LRE, bidi.RLO, bidi.LRO:
+			p.levels[i] = top.level
+			l := (top.level + 2) &^ 1 // next even
+			if c == bidi.RLE || c == bidi.RLO {
+				l = (top.level + 1) | 1 // next odd
+			}
+			if l <= maxBidiDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				o := bidi.ON
+				if c == bidi.RLO {
+					o = bidi.R
+				} else if c == bidi.LRO {
+					o = bidi.L
+				}
+				stack = append(stack, bidiStatus{level: l, override: o})
+				continue
+			}
+			if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+
+		case bidi.RLI, bidi.LRI, bidi.FSI:
+			p.levels[i] = top.level
+			if top.override != bidi.ON {
+				p.types[i] = top.override
+			}
+			rtl := c == bidi.RLI
+			if c == bidi.FSI {
+				to := p.matchPDI[i]
+				if to < 0 {
+					to = len(p.runes)
+				}
+				rtl = p.firstStrongLevel(i+1, to, 0) == 1
+			}
+			l := (top.level + 2) &^ 1
+			if rtl {
+				l = (top.level + 1) | 1
+			}
+			if l <= maxBidiDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				validIsolates++
+				stack = append(stack, bidiStatus{level: l, override: bidi.ON, isolate: true})
+				continue
+			}
+			overflowIsolates++
+
+		case bidi.PDI:
+			if overflowIsolates > 0 {
+				overflowIsolates--
+			} else if validIsolates > 0 {
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			top = stack[len(stack)-1]
+			p.levels[i] = top.level
+			if top.override != bidi.ON {
+				p.types[i] = top.override
+			}
+
+		case bidi.PDF:
+			p.levels[i] = top.level
+			if overflowIsolates > 0 {
+				break
+			}
+			if overflowEmbeddings > 0 {
+				overflowEmbeddings--
+				break
+			}
+			if !top.isolate && len(stack) >= 2 {
+				stack = stack[:len(stack)-1]
+			}
+
+		case bidi.B:
+			p.levels[i] = p.level
+
+		default:
+			p.levels[i] = top.level
+			if top.override != bidi.ON && c != bidi.BN {
+				p.types[i] = top.override
+			}
+		}
+	}
+}
+
+// isolatingRunSequences computes the isolating run sequences (BD13, X10).
+func (p *bidiParagraph) isolatingRunSequences() [][]int {
+	var runs [][]int
+	var run []int
+	for i, c := range p.initTypes {
+		if isRemovedByX9(c) {
+			continue
+		}
+		if len(run) > 0 && p.levels[run[len(run)-1]] != p.levels[i] {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, i)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+
+	runForChar := map[int]int{}
+	for k, r := range runs {
+		runForChar[r[0]] = k
+	}
+
+	var seqs [][]int
+	for _, r := range runs {
+		if first := r[0]; p.initTypes[first] == bidi.PDI && p.matchInit[first] >= 0 {
+			continue
+		}
+		seq := append([]int(nil), r...)
+		for {
+			last := seq[len(seq)-1]
+			if !isIsolateInitiator(p.initTypes[last]) || p.matchPDI[last] < 0 {
+				break
+			}
+			k, ok := runForChar[p.matchPDI[last]]
+			if !ok {
+				break
+			}
+			seq = append(seq, runs[k]...)
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+func (p *bidiParagraph) levelBefore(i int) uint8 {
+	for j := i - 1; j >= 0; j-- {
+		if !isRemovedByX9(p.initTypes[j]) {
+			return p.levels[j]
+		}
+	}
+	return p.level
+}
+
+func (p *bidiParagraph) levelAfter(i int) uint8 {
+	for j := i + 1; j < len(p.runes); j++ {
+		if !isRemovedByX9(p.initTypes[j]) {
+			return p.levels[j]
+		}
+	}
+	return p.level
+}
+
+func maxLevel(a, b uint8) uint8 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// resolveSequence applies rules W1 - W7, N0 - N2 and I1 - I2 to an isolating run sequence.
+func (p *bidiParagraph) resolveSequence(seq []int) {
+	level := p.levels[seq[0]]
+	first, last := seq[0], seq[len(seq)-1]
+	sos := bidiDirection(maxLevel(level, p.levelBefore(first)))
+	eosLevel := p.levelAfter(last)
+	if isIsolateInitiator(p.initTypes[last]) {
+		eosLevel = p.level
+	}
+	eos := bidiDirection(maxLevel(level, eosLevel))
+
+	t := make([]bidi.Class, len(seq))
+	for k, i := range seq {
+		t[k] = p.types[i]
+	}
+
+	// W1
+	prev := sos
+	for k := range t {
+		if t[k] == bidi.NSM {
+			t[k] = prev
+			if isIsolateInitiator(prev) || prev == bidi.PDI {
+				t[k] = bidi.ON
+			}
+		}
+		prev = t[k]
+	}
+
+	// W2, W3
+	strong := sos
+	for k := range t {
+		switch t[k] {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = t[k]
+		case bidi.EN:
+			if strong == bidi.AL {
+				t[k] = bidi.AN
+			}
+		}
+	}
+	for k := range t {
+		if t[k] == bidi.AL {
+			t[k] = bidi.R
+		}
+	}
+
+	// W4
+	for k := 1; k < len(t)-1; k++ {
+		if t[k] == bidi.ES && t[k-1] == bidi.EN && t[k+1] == bidi.EN {
+			t[k] = bidi.EN
+		} else if t[k] == bidi.CS && t[k-1] == t[k+1] && (t[k-1] == bidi.EN || t[k-1] == bidi.AN) {
+			t[k] = t[k-1]
+		}
+	}
+
+	// W5
+	for k := 0; k < len(t); k++ {
+		if t[k] != bidi.ET {
+			continue
+		}
+		e := k
+		for e < len(t) && t[e] == bidi.ET {
+			e++
+		}
+		if (k > 0 && t[k-1] == bidi.EN) || (e < len(t) && t[e] == bidi.EN) {
+			for j := k; j < e; j++ {
+				t[j] = bidi.EN
+			}
+		}
+		k = e - 1
+	}
+
+	// W6
+	for k := range t {
+		switch t[k] {
+		case bidi.ES, bidi.ET, bidi.CS:
+			t[k] = bidi.ON
+		}
+	}
+
+	// W7
+	strong = sos
+	for k := range t {
+		switch t[k] {
+		case bidi.L, bidi.R:
+			strong = t[k]
+		case bidi.EN:
+			if strong == bidi.L {
+				t[k] = bidi.L
+			}
+		}
+	}
+
+	p.resolveBrackets(seq, t, sos, bidiDirection(level))
+
+	// N1, N2
+	for k := 0; k < len(t); k++ {
+		if !isNI(t[k]) {
+			continue
+		}
+		e := k
+		for e < len(t) && isNI(t[e]) {
+			e++
+		}
+		before, after := sos, eos
+		if k > 0 {
+			before = strongForNeutrals(t[k-1])
+		}
+		if e < len(t) {
+			after = strongForNeutrals(t[e])
+		}
+		d := bidiDirection(level)
+		if before == after {
+			d = before
+		}
+		for j := k; j < e; j++ {
+			t[j] = d
+		}
+		k = e - 1
+	}
+
+	// I1, I2
+	for k, i := range seq {
+		l := p.levels[i]
+		if l&1 == 0 {
+			switch t[k] {
+			case bidi.R:
+				l++
+			case bidi.AN, bidi.EN:
+				l += 2
+			}
+		} else if t[k] == bidi.L || t[k] == bidi.EN || t[k] == bidi.AN {
+			l++
+		}
+		p.levels[i] = l
+		p.types[i] = t[k]
+	}
+}
+
+func strongForNeutrals(c bidi.Class) bidi.Class {
+	if c == bidi.EN || c == bidi.AN {
+		return bidi.R
+	}
+	return c
+}
+
+// resolveBrackets applies rule N0 to paired brackets of an isolating run sequence.
+func (p *bidiParagraph) resolveBrackets(seq []int, t []bidi.Class, sos, e bidi.Class) {
+	type opener struct {
+		pos   int
+		close rune
+	}
+	var stack []opener
+	var pairs [][2]int
+
+	for k, i := range seq {
+		if t[k] != bidi.ON {
+			continue
+		}
+		r := p.runes[i]
+		if c, ok := bidiBracketPairs[r]; ok {
+			if len(stack) == 63 {
+				break
+			}
+			stack = append(stack, opener{k, canonicalBracket(c)})
+			continue
+		}
+		r = canonicalBracket(r)
+		for j := len(stack) - 1; j >= 0; j-- {
+			if stack[j].close == r {
+				pairs = append(pairs, [2]int{stack[j].pos, k})
+				stack = stack[:j]
+				break
+			}
+		}
+	}
+
+	// Process pairs in order of their opening brackets.
+	for a := 1; a < len(pairs); a++ {
+		for b := a; b > 0 && pairs[b][0] < pairs[b-1][0]; b-- {
+			pairs[b], pairs[b-1] = pairs[b-1], pairs[b]
+		}
+	}
+
+	for _, pair := range pairs {
+		o, c := pair[0], pair[1]
+		var foundE, foundOpposite bool
+		for k := o + 1; k < c; k++ {
+			d := strongForNeutrals(t[k])
+			if d == e {
+				foundE = true
+				break
+			}
+			if d == bidi.L || d == bidi.R {
+				foundOpposite = true
+			}
+		}
+		var d bidi.Class
+		switch {
+		case foundE:
+			d = e
+		case foundOpposite:
+			before := sos
+			for k := o - 1; k >= 0; k-- {
+				if s := strongForNeutrals(t[k]); s == bidi.L || s == bidi.R {
+					before = s
+					break
+				}
+			}
+			d = e
+			if before != e {
+				d = before
+			}
+		default:
+			continue
+		}
+		t[o], t[c] = d, d
+		for _, k := range []int{o, c} {
+			for j := k + 1; j < len(t) && p.initTypes[seq[j]] == bidi.NSM; j++ {
+				t[j] = d
+			}
+		}
+	}
+}
+
+// resetWhitespaceLevels applies rule L1 for a single line paragraph.
+func (p *bidiParagraph) resetWhitespaceLevels() {
+	trailing := true
+	for i := len(p.runes) - 1; i >= 0; i-- {
+		switch c := p.initTypes[i]; {
+		case c == bidi.S || c == bidi.B:
+			p.levels[i] = p.level
+			trailing = true
+		case c == bidi.WS || isIsolateInitiator(c) || c == bidi.PDI || isRemovedByX9(c):
+			if trailing {
+				p.levels[i] = p.level
+			}
+		default:
+			trailing = false
+		}
+	}
+	// Characters removed by X9 take the level of the preceding character.
+	for i, c := range p.initTypes {
+		if isRemovedByX9(c) && !(c == bidi.S || c == bidi.B) {
+			if i == 0 {
+				p.levels[i] = p.level
+			} else {
+				p.levels[i] = p.levels[i-1]
+			}
+		}
+	}
+}
+
+// bidiLevels returns the resolved embedding levels for a single line of text.
+// rtl forces a right-to-left paragraph, otherwise the direction is taken from the first strong character.
+func bidiLevels(rr []rune, rtl bool) []uint8 {
+	n := len(rr)
+	p := &bidiParagraph{
+		runes:     rr,
+		initTypes: make([]bidi.Class, n),
+		types:     make([]bidi.Class, n),
+		levels:    make([]uint8, n),
+	}
+	for i, r := range rr {
+		p.initTypes[i] = bidiClass(r)
+	}
+	copy(p.types, p.initTypes)
+	p.matchIsolates()
+
+	if rtl {
+		p.level = 1
+	} else {
+		p.level = p.firstStrongLevel(0, n, 0)
+	}
+
+	p.explicitLevels()
+	for _, seq := range p.isolatingRunSequences() {
+		p.resolveSequence(seq)
+	}
+	p.resetWhitespaceLevels()
+	return p.levels
+}
+
+// visualOrder returns the logical indices in visual order (rule L2).
+func visualOrder(levels []uint8) []int {
+	order := make([]int, len(levels))
+	var hi, lo uint8 = 0, 0xFF
+	for i, l := range levels {
+		order[i] = i
+		if l > hi {
+			hi = l
+		}
+		if l&1 == 1 && l < lo {
+			lo = l
+		}
+	}
+	for l := hi; l >= lo && l > 0; l-- {
+		for i := 0; i < len(levels); i++ {
+			if levels[order[i]] < l {
+				continue
+			}
+			j := i
+			for j < len(levels) && levels[order[j]] >= l {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = j
+		}
+	}
+	return order
+}
+
+// mirror returns the mirrored glyph character for r (rule L4).
+func mirror(r rune) rune {
+	if m, ok := bidiMirrors[r]; ok {
+		return m
+	}
+	return r
+}
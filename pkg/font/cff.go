@@ -0,0 +1,639 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// Compact Font Format (CFF) support for OpenType fonts with sfnt version "OTTO".
+// See Adobe Technical Note #5176 and #5177.
+
+// CFF DICT operators of interest. Two byte operators are represented as 1200 + second byte.
+const (
+	cffOpCharset     = 15
+	cffOpEncoding    = 16
+	cffOpCharStrings = 17
+	cffOpPrivate     = 18
+	cffOpSubrs       = 19
+	cffOpROS         = 1230
+	cffOpCIDCount    = 1234
+	cffOpFDArray     = 1236
+	cffOpFDSelect    = 1237
+)
+
+// cffEndChar is the Type 2 charstring used for glyphs not part of a subset.
+var cffEndChar = []byte{14}
+
+var errCorruptCFF = errors.New("pdfcpu: corrupt CFF table")
+
+// parseCFFIndex parses the CFF INDEX at off and returns its items and the offset following it.
+func parseCFFIndex(bb []byte, off int) ([][]byte, int, error) {
+	if off+2 > len(bb) {
+		return nil, 0, errCorruptCFF
+	}
+	count := int(binary.BigEndian.Uint16(bb[off:]))
+	if count == 0 {
+		return nil, off + 2, nil
+	}
+	if off+3 > len(bb) {
+		return nil, 0, errCorruptCFF
+	}
+	offSize := int(bb[off+2])
+	if offSize < 1 || offSize > 4 {
+		return nil, 0, errCorruptCFF
+	}
+	offBase := off + 3
+	dataBase := offBase + (count+1)*offSize - 1
+	if dataBase >= len(bb) {
+		return nil, 0, errCorruptCFF
+	}
+
+	offset := func(i int) int {
+		v := 0
+		for _, b := range bb[offBase+i*offSize : offBase+(i+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return dataBase + v
+	}
+
+	items := make([][]byte, count)
+	from := offset(0)
+	for i := 0; i < count; i++ {
+		thru := offset(i + 1)
+		if thru < from || thru > len(bb) {
+			return nil, 0, errCorruptCFF
+		}
+		items[i] = bb[from:thru]
+		from = thru
+	}
+
+	return items, from, nil
+}
+
+// cffIndexBytes encodes items as CFF INDEX.
+func cffIndexBytes(items [][]byte) []byte {
+	if len(items) == 0 {
+		return []byte{0, 0}
+	}
+
+	size := 1
+	for _, item := range items {
+		size += len(item)
+	}
+
+	offSize := 1
+	for max := 0xFF; size > max && offSize < 4; max = max<<8 | 0xFF {
+		offSize++
+	}
+
+	var buf bytes.Buffer
+	buf.Write(uint16ToBigEndianBytes(uint16(len(items))))
+	buf.WriteByte(byte(offSize))
+
+	writeOffset := func(v int) {
+		for i := offSize - 1; i >= 0; i-- {
+			buf.WriteByte(byte(v >> (8 * i)))
+		}
+	}
+
+	off := 1
+	writeOffset(off)
+	for _, item := range items {
+		off += len(item)
+		writeOffset(off)
+	}
+	for _, item := range items {
+		buf.Write(item)
+	}
+
+	return buf.Bytes()
+}
+
+type cffDictEntry struct {
+	op       int
+	operands [][]byte // raw encoded operands
+}
+
+// cffDict represents a CFF DICT preserving the order of its entries.
+type cffDict []cffDictEntry
+
+func parseCFFDict(bb []byte) (cffDict, error) {
+	var (
+		d        cffDict
+		operands [][]byte
+	)
+
+	for i := 0; i < len(bb); {
+		b0 := bb[i]
+		n := 0
+		switch {
+		case b0 <= 21:
+			op := int(b0)
+			i++
+			if b0 == 12 {
+				if i >= len(bb) {
+					return nil, errCorruptCFF
+				}
+				op = 1200 + int(bb[i])
+				i++
+			}
+			d = append(d, cffDictEntry{op: op, operands: operands})
+			operands = nil
+			continue
+		case b0 == 28:
+			n = 3
+		case b0 == 29:
+			n = 5
+		case b0 == 30:
+			// real number: nibbles terminated by 0xf
+			for n = 1; i+n < len(bb); n++ {
+				if bb[i+n]&0x0F == 0x0F || bb[i+n]>>4 == 0x0F {
+					n++
+					break
+				}
+			}
+		case b0 >= 32 && b0 <= 246:
+			n = 1
+		case b0 >= 247 && b0 <= 254:
+			n = 2
+		default:
+			return nil, errCorruptCFF
+		}
+		if i+n > len(bb) {
+			return nil, errCorruptCFF
+		}
+		operands = append(operands, bb[i:i+n])
+		i += n
+	}
+
+	return d, nil
+}
+
+func cffOperandInt(bb []byte) int {
+	b0 := int(bb[0])
+	switch {
+	case b0 == 28:
+		return int(int16(binary.BigEndian.Uint16(bb[1:])))
+	case b0 == 29:
+		return int(int32(binary.BigEndian.Uint32(bb[1:])))
+	case b0 >= 32 && b0 <= 246:
+		return b0 - 139
+	case b0 >= 247 && b0 <= 250:
+		return (b0-247)*256 + int(bb[1]) + 108
+	case b0 >= 251 && b0 <= 254:
+		return -(b0-251)*256 - int(bb[1]) - 108
+	}
+	return 0
+}
+
+// ints returns the integer operands for op.
+func (d cffDict) ints(op int) ([]int, bool) {
+	for _, e := range d {
+		if e.op == op {
+			ii := make([]int, len(e.operands))
+			for i, o := range e.operands {
+				ii[i] = cffOperandInt(o)
+			}
+			return ii, true
+		}
+	}
+	return nil, false
+}
+
+// setInts replaces the operands of op by fixed size integers.
+func (d cffDict) setInts(op int, ii ...int) {
+	for i, e := range d {
+		if e.op != op {
+			continue
+		}
+		operands := make([][]byte, len(ii))
+		for j, v := range ii {
+			operands[j] = append([]byte{29}, uint32ToBigEndianBytes(uint32(int32(v)))...)
+		}
+		d[i].operands = operands
+		return
+	}
+}
+
+func (d cffDict) bytes() []byte {
+	var buf bytes.Buffer
+	for _, e := range d {
+		for _, o := range e.operands {
+			buf.Write(o)
+		}
+		if e.op >= 1200 {
+			buf.WriteByte(12)
+			buf.WriteByte(byte(e.op - 1200))
+			continue
+		}
+		buf.WriteByte(byte(e.op))
+	}
+	return buf.Bytes()
+}
+
+// cffCharsetLength returns the byte length of the charset at off.
+func cffCharsetLength(bb []byte, off, numGlyphs int) (int, error) {
+	if off >= len(bb) {
+		return 0, errCorruptCFF
+	}
+	switch bb[off] {
+	case 0:
+		return 1 + 2*(numGlyphs-1), nil
+	case 1, 2:
+		nLeftSize := int(bb[off])
+		i := off + 1
+		for covered := 1; covered < numGlyphs; {
+			if i+2+nLeftSize > len(bb) {
+				return 0, errCorruptCFF
+			}
+			nLeft := int(bb[i+2])
+			if nLeftSize == 2 {
+				nLeft = int(binary.BigEndian.Uint16(bb[i+2:]))
+			}
+			covered += nLeft + 1
+			i += 2 + nLeftSize
+		}
+		return i - off, nil
+	}
+	return 0, errCorruptCFF
+}
+
+// cffEncodingLength returns the byte length of the custom encoding at off.
+func cffEncodingLength(bb []byte, off int) (int, error) {
+	if off+2 > len(bb) {
+		return 0, errCorruptCFF
+	}
+	format := bb[off]
+	l := 2 + int(bb[off+1])
+	if format&0x7F == 1 {
+		l = 2 + 2*int(bb[off+1])
+	} else if format&0x7F != 0 {
+		return 0, errCorruptCFF
+	}
+	if format&0x80 > 0 {
+		if off+l >= len(bb) {
+			return 0, errCorruptCFF
+		}
+		l += 1 + 3*int(bb[off+l])
+	}
+	return l, nil
+}
+
+// cffFDSelectLength returns the byte length of the FDSelect structure at off.
+func cffFDSelectLength(bb []byte, off, numGlyphs int) (int, error) {
+	if off >= len(bb) {
+		return 0, errCorruptCFF
+	}
+	switch bb[off] {
+	case 0:
+		return 1 + numGlyphs, nil
+	case 3:
+		if off+3 > len(bb) {
+			return 0, errCorruptCFF
+		}
+		nRanges := int(binary.BigEndian.Uint16(bb[off+1:]))
+		return 3 + 3*nRanges + 2, nil
+	}
+	return 0, errCorruptCFF
+}
+
+// cffPrivate represents a Private DICT and its local subroutines.
+type cffPrivate struct {
+	dict  cffDict
+	subrs []byte // encoded local subrs INDEX
+}
+
+func parseCFFPrivate(bb []byte, d cffDict) (*cffPrivate, error) {
+	ii, ok := d.ints(cffOpPrivate)
+	if !ok {
+		return nil, nil
+	}
+	if len(ii) != 2 || ii[0] < 0 || ii[1] < 0 || ii[1]+ii[0] > len(bb) {
+		return nil, errCorruptCFF
+	}
+	size, off := ii[0], ii[1]
+
+	pd, err := parseCFFDict(bb[off : off+size])
+	if err != nil {
+		return nil, err
+	}
+
+	p := &cffPrivate{dict: pd}
+
+	if ii, ok := pd.ints(cffOpSubrs); ok && len(ii) == 1 {
+		from := off + ii[0]
+		_, thru, err := parseCFFIndex(bb, from)
+		if err != nil {
+			return nil, err
+		}
+		p.subrs = bb[from:thru]
+	}
+
+	return p, nil
+}
+
+// bytes encodes p with its local subrs following the Private DICT.
+func (p *cffPrivate) bytes() []byte {
+	p.dict.setInts(cffOpSubrs, 0)
+	bb := p.dict.bytes()
+	if p.subrs == nil {
+		return bb
+	}
+	p.dict.setInts(cffOpSubrs, len(bb))
+	return append(p.dict.bytes(), p.subrs...)
+}
+
+// cff represents the parsed "CFF " table of an OpenType font.
+type cff struct {
+	header      []byte
+	names       [][]byte
+	top         cffDict
+	strings     [][]byte
+	globalSubrs [][]byte
+	charset     []byte
+	encoding    []byte
+	charStrings [][]byte
+	private     *cffPrivate
+	fdSelect    []byte
+	fdArray     []cffDict
+	fdPrivates  []*cffPrivate
+}
+
+func (c *cff) cidKeyed() bool {
+	_, ok := c.top.ints(cffOpROS)
+	return ok
+}
+
+func parseCFF(bb []byte) (*cff, error) {
+	if len(bb) < 4 || bb[0] != 1 {
+		return nil, errCorruptCFF
+	}
+	hdrSize := int(bb[2])
+	if hdrSize < 4 || hdrSize > len(bb) {
+		return nil, errCorruptCFF
+	}
+
+	c := &cff{header: bb[:hdrSize]}
+
+	var (
+		tops [][]byte
+		off  int
+		err  error
+	)
+
+	if c.names, off, err = parseCFFIndex(bb, hdrSize); err != nil {
+		return nil, err
+	}
+	if tops, off, err = parseCFFIndex(bb, off); err != nil {
+		return nil, err
+	}
+	if len(c.names) != 1 || len(tops) != 1 {
+		return nil, errors.New("pdfcpu: CFF font sets unsupported")
+	}
+	if c.strings, off, err = parseCFFIndex(bb, off); err != nil {
+		return nil, err
+	}
+	if c.globalSubrs, _, err = parseCFFIndex(bb, off); err != nil {
+		return nil, err
+	}
+
+	if c.top, err = parseCFFDict(tops[0]); err != nil {
+		return nil, err
+	}
+
+	ii, ok := c.top.ints(cffOpCharStrings)
+	if !ok || len(ii) != 1 {
+		return nil, errCorruptCFF
+	}
+	if c.charStrings, _, err = parseCFFIndex(bb, ii[0]); err != nil {
+		return nil, err
+	}
+	numGlyphs := len(c.charStrings)
+
+	if ii, ok := c.top.ints(cffOpCharset); ok && len(ii) == 1 && ii[0] > 2 {
+		l, err := cffCharsetLength(bb, ii[0], numGlyphs)
+		if err != nil {
+			return nil, err
+		}
+		if ii[0]+l > len(bb) {
+			return nil, errCorruptCFF
+		}
+		c.charset = bb[ii[0] : ii[0]+l]
+	}
+
+	if ii, ok := c.top.ints(cffOpEncoding); ok && len(ii) == 1 && ii[0] > 1 {
+		l, err := cffEncodingLength(bb, ii[0])
+		if err != nil {
+			return nil, err
+		}
+		if ii[0]+l > len(bb) {
+			return nil, errCorruptCFF
+		}
+		c.encoding = bb[ii[0] : ii[0]+l]
+	}
+
+	if c.private, err = parseCFFPrivate(bb, c.top); err != nil {
+		return nil, err
+	}
+
+	if !c.cidKeyed() {
+		return c, nil
+	}
+
+	if ii, ok := c.top.ints(cffOpFDSelect); ok && len(ii) == 1 {
+		l, err := cffFDSelectLength(bb, ii[0], numGlyphs)
+		if err != nil {
+			return nil, err
+		}
+		if ii[0]+l > len(bb) {
+			return nil, errCorruptCFF
+		}
+		c.fdSelect = bb[ii[0] : ii[0]+l]
+	}
+
+	ii, ok = c.top.ints(cffOpFDArray)
+	if !ok || len(ii) != 1 {
+		return nil, errCorruptCFF
+	}
+	fds, _, err := parseCFFIndex(bb, ii[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, fd := range fds {
+		d, err := parseCFFDict(fd)
+		if err != nil {
+			return nil, err
+		}
+		p, err := parseCFFPrivate(bb, d)
+		if err != nil {
+			return nil, err
+		}
+		c.fdArray = append(c.fdArray, d)
+		c.fdPrivates = append(c.fdPrivates, p)
+	}
+
+	return c, nil
+}
+
+// bytes serializes c.
+// All offsets are encoded as 5 byte integers which fixes the size of the DICTs involved.
+func (c *cff) bytes() []byte {
+	layout := func() ([]byte, []byte) {
+		var head bytes.Buffer
+		head.Write(c.header)
+		head.Write(cffIndexBytes(c.names))
+		head.Write(cffIndexBytes([][]byte{c.top.bytes()}))
+		head.Write(cffIndexBytes(c.strings))
+		head.Write(cffIndexBytes(c.globalSubrs))
+
+		var body bytes.Buffer
+		off := func() int { return head.Len() + body.Len() }
+
+		if c.charset != nil {
+			c.top.setInts(cffOpCharset, off())
+			body.Write(c.charset)
+		}
+
+		if c.encoding != nil {
+			c.top.setInts(cffOpEncoding, off())
+			body.Write(c.encoding)
+		}
+
+		if c.fdSelect != nil {
+			c.top.setInts(cffOpFDSelect, off())
+			body.Write(c.fdSelect)
+		}
+
+		c.top.setInts(cffOpCharStrings, off())
+		body.Write(cffIndexBytes(c.charStrings))
+
+		if c.private != nil {
+			bb := c.private.bytes()
+			c.top.setInts(cffOpPrivate, len(bb)-len(c.private.subrs), off())
+			body.Write(bb)
+		}
+
+		if c.fdArray != nil {
+			// Font DICTs are fixed size, so their Private offsets may be calculated upfront.
+			fds := make([][]byte, len(c.fdArray))
+			for i, d := range c.fdArray {
+				fds[i] = d.bytes()
+			}
+			privOff := off() + len(cffIndexBytes(fds))
+			privates := []byte{}
+			for i, p := range c.fdPrivates {
+				if p == nil {
+					continue
+				}
+				bb := p.bytes()
+				c.fdArray[i].setInts(cffOpPrivate, len(bb)-len(p.subrs), privOff+len(privates))
+				fds[i] = c.fdArray[i].bytes()
+				privates = append(privates, bb...)
+			}
+			c.top.setInts(cffOpFDArray, off())
+			body.Write(cffIndexBytes(fds))
+			body.Write(privates)
+		}
+
+		return head.Bytes(), body.Bytes()
+	}
+
+	// The Top DICT is written ahead of the data it points to:
+	// the first pass fixes operand sizes, the second resolves offsets and the third one serializes.
+	layout()
+	layout()
+	head, body := layout()
+
+	return append(head, body...)
+}
+
+// subset replaces the charstrings of all glyphs not contained in usedGIDs by an empty glyph.
+func (c *cff) subset(usedGIDs map[uint16]bool) {
+	for gid := range c.charStrings {
+		if gid == 0 || usedGIDs[uint16(gid)] {
+			continue
+		}
+		c.charStrings[gid] = cffEndChar
+	}
+}
+
+// identityCharset maps the glyphs of a CID-keyed font to CIDs equal to their glyph index.
+// CIDFontType0 fonts select glyphs by CID via the charset whereas pdfcpu writes glyph indices as CIDs.
+func (c *cff) identityCharset() {
+	n := len(c.charStrings)
+
+	// Format 0 for .notdef only, otherwise format 2 with a single range starting at CID 1.
+	c.charset = []byte{0}
+	if n > 1 {
+		c.charset = []byte{2, 0, 1, byte((n - 2) >> 8), byte(n - 2)}
+	}
+
+	if _, ok := c.top.ints(cffOpCharset); !ok {
+		c.top = append(c.top, cffDictEntry{op: cffOpCharset})
+	}
+
+	// The default CIDCount is 8720.
+	ii, ok := c.top.ints(cffOpCIDCount)
+	if !ok && n > 8720 {
+		c.top = append(c.top, cffDictEntry{op: cffOpCIDCount})
+	}
+	if (!ok && n > 8720) || (ok && len(ii) == 1 && ii[0] < n) {
+		c.top.setInts(cffOpCIDCount, n)
+	}
+}
+
+func (t table) parseCompactFontFormatTable(fd *ttf) error {
+	// table "CFF "
+	c, err := parseCFF(t.data[:t.size])
+	if err != nil {
+		return err
+	}
+	if len(c.charStrings) != fd.GlyphCount {
+		return errors.Errorf("pdfcpu: CFF glyph count mismatch: %d != %d", len(c.charStrings), fd.GlyphCount)
+	}
+	fd.CFF = true
+	return nil
+}
+
+func cffSubset(fontName string, tables map[string]*table, usedGIDs map[uint16]bool) error {
+	t, ok := tables["CFF "]
+	if !ok {
+		return errors.Errorf("pdfcpu: missing \"CFF \" table for font: %s", fontName)
+	}
+
+	c, err := parseCFF(t.data[:t.size])
+	if err != nil {
+		return err
+	}
+
+	c.subset(usedGIDs)
+
+	if c.cidKeyed() {
+		c.identityCharset()
+	}
+
+	bb := c.bytes()
+	t.size = uint32(len(bb))
+	t.data = pad(bb)
+	t.padded = uint32(len(t.data))
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"testing"
+)
+
+// cidKeyedCFF returns a CID-keyed CFF with 3 glyphs mapped to CIDs 0, 5 and 9.
+func cidKeyedCFF() *cff {
+	top := cffDict{{op: cffOpROS}, {op: cffOpCharset}, {op: cffOpFDSelect}, {op: cffOpCharStrings}, {op: cffOpFDArray}}
+	top.setInts(cffOpROS, 391, 392, 0)
+
+	return &cff{
+		header:      []byte{1, 0, 4, 4},
+		names:       [][]byte{[]byte("Test")},
+		top:         top,
+		strings:     [][]byte{[]byte("Adobe"), []byte("Identity")},
+		charset:     []byte{0, 0, 5, 0, 9},
+		charStrings: [][]byte{cffEndChar, cffEndChar, cffEndChar},
+		fdSelect:    []byte{0, 0, 0, 0},
+		fdArray:     []cffDict{{}},
+		fdPrivates:  []*cffPrivate{nil},
+	}
+}
+
+func TestCFFIdentityCharset(t *testing.T) {
+	c, err := parseCFF(cidKeyedCFF().bytes())
+	if err != nil {
+		t.Fatalf("parse: %v\n", err)
+	}
+	if !c.cidKeyed() {
+		t.Fatal("want CID-keyed font")
+	}
+
+	c.identityCharset()
+
+	c, err = parseCFF(c.bytes())
+	if err != nil {
+		t.Fatalf("parse: %v\n", err)
+	}
+
+	// Glyphs 1 and 2 map to CIDs 1 and 2.
+	if want := []byte{2, 0, 1, 0, 1}; !bytes.Equal(c.charset, want) {
+		t.Fatalf("charset: got % X want % X\n", c.charset, want)
+	}
+	if len(c.charStrings) != 3 || len(c.fdArray) != 1 {
+		t.Fatalf("corrupt font: %d glyphs, %d font dicts\n", len(c.charStrings), len(c.fdArray))
+	}
+}
+
+func TestCFFHeaderSize(t *testing.T) {
+	for _, hdrSize := range []byte{0, 3, 255} {
+		bb := cidKeyedCFF().bytes()
+		bb[2] = hdrSize
+		if _, err := parseCFF(bb); err != errCorruptCFF {
+			t.Fatalf("header size %d: want errCorruptCFF, got %v\n", hdrSize, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"strings"
+	"unicode"
+)
+
+var unicodeToCP1252 = map[rune]byte{
+	0x20AC: 128, // € Euro Sign Note: Width in metrics file is not correct!
+	0x201A: 130, // ‚ Single Low-9 Quotation Mark
+	0x0192: 131, // ƒ Latin Small Letter F with Hook
+	0x201E: 132, // „ Double Low-9 Quotation Mark
+	0x2026: 133, // … Horizontal Ellipsis
+	0x2020: 134, // † Dagger
+	0x2021: 135, // ‡ Double Dagger
+	0x02C6: 136, // ˆ Modifier Letter Circumflex Accent
+	0x2030: 137, // ‰ Per Mille Sign
+	0x0160: 138, // Š Latin Capital Letter S with Caron
+	0x2039: 139, // ‹ Single Left-Pointing Angle Quotation Mark
+	0x0152: 140, // Œ Latin Capital Ligature Oe
+	0x017D: 142, // Ž Latin Capital Letter Z with Caron
+	0x2018: 145, // ‘ Left Single Quotation Mark
+	0x2019: 146, // ’ Right Single Quotation Mark
+	0x201C: 147, // “ Left Double Quotation Mark
+	0x201D: 148, // ” Right Double Quotation Mark
+	0x2022: 149, // • Bullet
+	0x2013: 150, // – En Dash
+	0x2014: 151, // — Em Dash
+	0x02DC: 152, // ˜ Small Tilde
+	0x2122: 153, // ™ Trade Mark Sign Emoji
+	0x0161: 154, // š Latin Small Letter S with Caron
+	0x203A: 155, // › Single Right-Pointing Angle Quotation Mark
+	0x0153: 156, // œ Latin Small Ligature Oe
+	0x017E: 158, // ž Latin Small Letter Z with Caron
+	0x0178: 159, // Ÿ Latin Capital Letter Y with Diaeresis
+}
+
+// CoreFontCharCode returns the WinAnsi char code used by core fonts for r.
+func CoreFontCharCode(r rune) (byte, bool) {
+	if r <= 0xFF {
+		return byte(r), true
+	}
+	b, ok := unicodeToCP1252[r]
+	return b, ok
+}
+
+var cp1252ToUnicode map[byte]rune
+
+func init() {
+	cp1252ToUnicode = make(map[byte]rune, len(unicodeToCP1252))
+	for r, b := range unicodeToCP1252 {
+		cp1252ToUnicode[b] = r
+	}
+}
+
+// WinAnsiRune returns the character for the WinAnsi char code b used by core fonts.
+func WinAnsiRune(b byte) (rune, bool) {
+	if b < 0x80 || b >= 0xA0 {
+		return rune(b), true
+	}
+	r, ok := cp1252ToUnicode[b]
+	return r, ok
+}
+
+// coreFontText encodes s for core fonts replacing unsupported characters by blanks.
+func coreFontText(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		b, ok := CoreFontCharCode(r)
+		if !ok {
+			b = 0x20
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
+
+// FontRun is a piece of text rendered using a single font.
+type FontRun struct {
+	FontName string
+	Text     string
+	RTL      bool // Text is a right-to-left run.
+}
+
+type fontCoverage func(r rune) bool
+
+func fontCoverageFor(fontName string) fontCoverage {
+	if IsCoreFont(fontName) {
+		return func(r rune) bool {
+			_, ok := CoreFontCharCode(r)
+			return ok
+		}
+	}
+	UserFontMetricsLock.RLock()
+	fd, ok := UserFontMetrics[fontName]
+	UserFontMetricsLock.RUnlock()
+	return func(r rune) bool {
+		if !ok {
+			return false
+		}
+		_, ok := fd.Chars[uint32(r)]
+		return ok
+	}
+}
+
+// runeFonts returns for each rune of rr the index of the font used for rendering it.
+func runeFonts(rr []rune, fontNames []string) []int {
+	cc := make([]fontCoverage, len(fontNames))
+	for i, fn := range fontNames {
+		cc[i] = fontCoverageFor(fn)
+	}
+
+	ff := make([]int, len(rr))
+	for i, r := range rr {
+		if i > 0 && cc[ff[i-1]](r) {
+			// Keep blanks and marks with their preceding character.
+			if sc, _ := runeScript(r); sc == scriptInherited || unicode.IsSpace(r) {
+				ff[i] = ff[i-1]
+				continue
+			}
+		}
+		for j, c := range cc {
+			if c(r) {
+				ff[i] = j
+				break
+			}
+		}
+	}
+	return ff
+}
+
+// FontRuns splits text into runs rendered using fontName or, for characters missing in fontName,
+// the first font of fallbacks supporting them.
+// For bidirectional text the runs are returned in visual order, see VisualOrder for the meaning of rtl.
+func FontRuns(text, fontName string, rtl bool, fallbacks ...string) []FontRun {
+	if len(fallbacks) == 0 || text == "" {
+		return []FontRun{{FontName: fontName, Text: text, RTL: rtl}}
+	}
+
+	EnsureUserFontsLoaded()
+	fontNames := append([]string{fontName}, fallbacks...)
+	rr := []rune(text)
+	ff := runeFonts(rr, fontNames)
+
+	single := true
+	for _, f := range ff {
+		if f != 0 {
+			single = false
+			break
+		}
+	}
+	if single {
+		return []FontRun{{FontName: fontName, Text: text, RTL: rtl}}
+	}
+
+	levels := make([]uint8, len(rr))
+	if rtl || NeedsShaping(text) {
+		levels = bidiLevels(rr, rtl)
+	}
+
+	var runs []textRun
+	for i := range rr {
+		if i == 0 || ff[i] != ff[i-1] || levels[i] != levels[i-1] {
+			if i > 0 {
+				runs[len(runs)-1].to = i
+			}
+			runs = append(runs, textRun{from: i, level: levels[i]})
+		}
+	}
+	runs[len(runs)-1].to = len(rr)
+
+	fr := make([]FontRun, len(runs))
+	for i, run := range visualRuns(runs) {
+		fr[i] = FontRun{
+			FontName: fontNames[ff[run.from]],
+			Text:     string(rr[run.from:run.to]),
+			RTL:      run.level&1 == 1,
+		}
+	}
+	return fr
+}
+
+// UsedFallbacks returns the fonts of fallbacks needed for rendering characters of text missing in fontName.
+func UsedFallbacks(text, fontName string, fallbacks ...string) []string {
+	if len(fallbacks) == 0 {
+		return nil
+	}
+	EnsureUserFontsLoaded()
+	fontNames := append([]string{fontName}, fallbacks...)
+	used := make([]bool, len(fontNames))
+	for _, f := range runeFonts([]rune(text), fontNames) {
+		used[f] = true
+	}
+	var ss []string
+	for i, fn := range fallbacks {
+		if used[i+1] {
+			ss = append(ss, fn)
+		}
+	}
+	return ss
+}
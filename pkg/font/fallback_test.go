@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFontRuns(t *testing.T) {
+	installShapingFont(t)
+
+	for _, tt := range []struct {
+		s    string
+		rtl  bool
+		want []FontRun
+	}{
+		{"Grüße €", false, []FontRun{{"Helvetica", "Grüße €", false}}},
+		{"Hello Ωμέγα!", false, []FontRun{{"Helvetica", "Hello ", false}, {shapingFont, "Ωμέγα", false}, {"Helvetica", "!", false}}},
+		{"Name: Иван Петров", false, []FontRun{{"Helvetica", "Name: ", false}, {shapingFont, "Иван Петров", false}}},
+		{"abc שלום def", false, []FontRun{{"Helvetica", "abc ", false}, {shapingFont, "שלום", true}, {shapingFont, " ", false}, {"Helvetica", "def", false}}},
+		{"שלום abc", true, []FontRun{{"Helvetica", "abc", false}, {shapingFont, "שלום ", true}}},
+	} {
+		got := FontRuns(tt.s, "Helvetica", tt.rtl, "NoSuchFont", shapingFont)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FontRuns(%q, %t):\nwant %v\ngot  %v\n", tt.s, tt.rtl, tt.want, got)
+		}
+	}
+
+	if got := UsedFallbacks("Hello Ωμέγα", "Helvetica", "NoSuchFont", shapingFont); !reflect.DeepEqual(got, []string{shapingFont}) {
+		t.Errorf("UsedFallbacks: want [%s] got %v\n", shapingFont, got)
+	}
+	if got := UsedFallbacks("Grüße", "Helvetica", shapingFont); got != nil {
+		t.Errorf("UsedFallbacks: want none, got %v\n", got)
+	}
+}
+
+func TestTextWidthFallback(t *testing.T) {
+	installShapingFont(t)
+
+	want := TextWidth("Hello ", "Helvetica", 12) + TextWidth("Ωμέγα", shapingFont, 12)
+	if got := TextWidth("Hello Ωμέγα", "Helvetica", 12, shapingFont); got != want {
+		t.Errorf("TextWidth: want %f got %f\n", want, got)
+	}
+
+	// Text supported by the selected font is not affected by fallback fonts.
+	if got, want := TextWidth("Hello", "Helvetica", 12, shapingFont), TextWidth("Hello", "Helvetica", 12); got != want {
+		t.Errorf("TextWidth: want %f got %f\n", want, got)
+	}
+}
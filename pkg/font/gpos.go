@@ -0,0 +1,457 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import "math/bits"
+
+// Glyph positioning table (GPOS).
+// See https://learn.microsoft.com/en-us/typography/opentype/spec/gpos
+
+const gposExtension = 9
+
+// Attachment types.
+const (
+	attachMark    = 1
+	attachCursive = 2
+)
+
+// valueRecord holds a glyph position adjustment in font units, device tables are ignored.
+type valueRecord struct {
+	xPla, yPla, xAdv, yAdv int
+}
+
+func valueRecordSize(format uint16) int {
+	return 2 * bits.OnesCount16(format)
+}
+
+func parseValueRecord(b otlData, off int, format uint16) valueRecord {
+	var v valueRecord
+	for bit := uint16(1); bit <= 0x80; bit <<= 1 {
+		if format&bit == 0 {
+			continue
+		}
+		switch bit {
+		case 0x01:
+			v.xPla = int(b.i16(off))
+		case 0x02:
+			v.yPla = int(b.i16(off))
+		case 0x04:
+			v.xAdv = int(b.i16(off))
+		case 0x08:
+			v.yAdv = int(b.i16(off))
+		}
+		off += 2
+	}
+	return v
+}
+
+type anchor struct {
+	x, y int
+}
+
+func parseAnchor(b otlData) *anchor {
+	if b == nil {
+		return nil
+	}
+	return &anchor{int(b.i16(2)), int(b.i16(4))}
+}
+
+type singlePos struct {
+	cov    *coverage
+	values []valueRecord // a single value for format 1
+}
+
+type pairValue struct {
+	second uint16
+	v1, v2 valueRecord
+}
+
+type pairPos struct {
+	cov                  *coverage
+	format2              bool
+	skipSecond           bool // the second glyph has a value record
+	pairSets             [][]pairValue
+	classDef1, classDef2 *classDef
+	class2Count          int
+	classValues          []pairValue // class1 * class2Count + class2
+}
+
+type cursivePos struct {
+	cov         *coverage
+	entry, exit []*anchor
+}
+
+type markRecord struct {
+	class  int
+	anchor *anchor
+}
+
+// markAttachPos represents mark-to-base, mark-to-ligature and mark-to-mark attachment.
+// For ligatures anchors are indexed by ligature, component and mark class,
+// otherwise the component index is always 0.
+type markAttachPos struct {
+	kind             int
+	markCov, baseCov *coverage
+	marks            []markRecord
+	anchors          [][][]*anchor
+}
+
+func parseMarkArray(b otlData) []markRecord {
+	n := int(b.u16(0))
+	mm := make([]markRecord, n)
+	for i := 0; i < n; i++ {
+		off := 2 + 4*i
+		mm[i] = markRecord{int(b.u16(off)), parseAnchor(b.at(int(b.u16(off + 2))))}
+	}
+	return mm
+}
+
+func parseAnchorMatrix(b otlData, classCount int) [][]*anchor {
+	n := int(b.u16(0))
+	m := make([][]*anchor, n)
+	for i := 0; i < n; i++ {
+		m[i] = make([]*anchor, classCount)
+		for j := 0; j < classCount; j++ {
+			m[i][j] = parseAnchor(b.at(int(b.u16(2 + 2*(i*classCount+j)))))
+		}
+	}
+	return m
+}
+
+func parseMarkAttachPos(kind int, b otlData) *markAttachPos {
+	classCount := int(b.u16(6))
+	p := &markAttachPos{
+		kind:    kind,
+		markCov: parseCoverage(b.at(int(b.u16(2)))),
+		baseCov: parseCoverage(b.at(int(b.u16(4)))),
+		marks:   parseMarkArray(b.at(int(b.u16(8)))),
+	}
+	bb := b.at(int(b.u16(10)))
+	if kind != 5 {
+		for _, aa := range parseAnchorMatrix(bb, classCount) {
+			p.anchors = append(p.anchors, [][]*anchor{aa})
+		}
+		return p
+	}
+	n := int(bb.u16(0))
+	for i := 0; i < n; i++ {
+		p.anchors = append(p.anchors, parseAnchorMatrix(bb.at(int(bb.u16(2+2*i))), classCount))
+	}
+	return p
+}
+
+func parseGPOSSubtable(kind int, b otlData) any {
+	switch kind {
+	case 1:
+		f := b.u16(4)
+		p := &singlePos{cov: parseCoverage(b.at(int(b.u16(2))))}
+		switch b.u16(0) {
+		case 1:
+			p.values = []valueRecord{parseValueRecord(b, 6, f)}
+		case 2:
+			n := int(b.u16(6))
+			for i := 0; i < n; i++ {
+				p.values = append(p.values, parseValueRecord(b, 8+i*valueRecordSize(f), f))
+			}
+		default:
+			return nil
+		}
+		return p
+	case 2:
+		f1, f2 := b.u16(4), b.u16(6)
+		s1, s2 := valueRecordSize(f1), valueRecordSize(f2)
+		p := &pairPos{cov: parseCoverage(b.at(int(b.u16(2))))}
+		switch b.u16(0) {
+		case 1:
+			n := int(b.u16(8))
+			p.pairSets = make([][]pairValue, n)
+			for i := 0; i < n; i++ {
+				ps := b.at(int(b.u16(10 + 2*i)))
+				c := int(ps.u16(0))
+				for j := 0; j < c; j++ {
+					off := 2 + j*(2+s1+s2)
+					p.pairSets[i] = append(p.pairSets[i], pairValue{
+						second: ps.u16(off),
+						v1:     parseValueRecord(ps, off+2, f1),
+						v2:     parseValueRecord(ps, off+2+s1, f2),
+					})
+				}
+			}
+		case 2:
+			p.format2 = true
+			p.classDef1 = parseClassDef(b.at(int(b.u16(8))))
+			p.classDef2 = parseClassDef(b.at(int(b.u16(10))))
+			c1, c2 := int(b.u16(12)), int(b.u16(14))
+			p.class2Count = c2
+			p.classValues = make([]pairValue, c1*c2)
+			for i := 0; i < c1*c2; i++ {
+				off := 16 + i*(s1+s2)
+				p.classValues[i] = pairValue{v1: parseValueRecord(b, off, f1), v2: parseValueRecord(b, off+s1, f2)}
+			}
+		default:
+			return nil
+		}
+		p.skipSecond = f2 != 0
+		return p
+	case 3:
+		p := &cursivePos{cov: parseCoverage(b.at(int(b.u16(2))))}
+		n := int(b.u16(4))
+		for i := 0; i < n; i++ {
+			off := 6 + 4*i
+			p.entry = append(p.entry, parseAnchor(b.at(int(b.u16(off)))))
+			p.exit = append(p.exit, parseAnchor(b.at(int(b.u16(off+2)))))
+		}
+		return p
+	case 4, 5, 6:
+		return parseMarkAttachPos(kind, b)
+	case 7:
+		return parseContextSubtable(b)
+	case 8:
+		return parseChainedContextSubtable(b)
+	}
+	return nil
+}
+
+func parseGPOS(b otlData) *layoutTable {
+	return parseLayoutTable(b, gposExtension, parseGPOSSubtable)
+}
+
+func (s *shaper) adjust(i int, v valueRecord) {
+	g := &s.buf[i]
+	g.xOff += v.xPla
+	g.yOff += v.yPla
+	g.xAdv += v.xAdv
+}
+
+func (s *shaper) applySinglePos(p *singlePos, i int) (int, bool) {
+	ci := p.cov.index(s.buf[i].gid)
+	if ci < 0 {
+		return 0, false
+	}
+	if len(p.values) == 1 {
+		ci = 0
+	}
+	if ci >= len(p.values) {
+		return 0, false
+	}
+	s.adjust(i, p.values[ci])
+	return i + 1, true
+}
+
+func (s *shaper) applyPairPos(p *pairPos, i int) (int, bool) {
+	ci := p.cov.index(s.buf[i].gid)
+	if ci < 0 {
+		return 0, false
+	}
+	j := s.next(i)
+	if j < 0 {
+		return 0, false
+	}
+	gid2 := s.buf[j].gid
+
+	var pv *pairValue
+	if !p.format2 {
+		if ci >= len(p.pairSets) {
+			return 0, false
+		}
+		for k := range p.pairSets[ci] {
+			if p.pairSets[ci][k].second == gid2 {
+				pv = &p.pairSets[ci][k]
+				break
+			}
+		}
+	} else {
+		k := int(p.classDef1.class(s.buf[i].gid))*p.class2Count + int(p.classDef2.class(gid2))
+		if k < len(p.classValues) {
+			pv = &p.classValues[k]
+		}
+	}
+	if pv == nil {
+		return 0, false
+	}
+
+	s.adjust(i, pv.v1)
+	s.adjust(j, pv.v2)
+	if p.skipSecond {
+		return j + 1, true
+	}
+	return j, true
+}
+
+func (s *shaper) applyCursivePos(p *cursivePos, i int) (int, bool) {
+	ci := p.cov.index(s.buf[i].gid)
+	if ci < 0 || ci >= len(p.exit) || p.exit[ci] == nil {
+		return 0, false
+	}
+	j := s.next(i)
+	if j < 0 {
+		return 0, false
+	}
+	cj := p.cov.index(s.buf[j].gid)
+	if cj < 0 || cj >= len(p.entry) || p.entry[cj] == nil {
+		return 0, false
+	}
+	exit, entry := p.exit[ci], p.entry[cj]
+	gi, gj := &s.buf[i], &s.buf[j]
+
+	if !s.rtl {
+		gi.xAdv = exit.x + gi.xOff
+		d := entry.x + gj.xOff
+		gj.xAdv -= d
+		gj.xOff -= d
+	} else {
+		d := exit.x + gi.xOff
+		gi.xAdv -= d
+		gi.xOff -= d
+		gj.xAdv = entry.x + gj.xOff
+	}
+
+	child, parent, y := i, j, entry.y-exit.y
+	if s.flag&lookupRightToLeft == 0 {
+		child, parent, y = j, i, exit.y-entry.y
+	}
+	c := &s.buf[child]
+	c.attachKind = attachCursive
+	c.attach = parent - child
+	c.yOff = y
+	return j, true
+}
+
+// markBase returns the index of the glyph mark i attaches to.
+func (s *shaper) markBase(i int, kind int) int {
+	if kind == 6 {
+		j := s.prev(i)
+		if j < 0 || s.buf[j].class != glyphClassMark {
+			return -1
+		}
+		return j
+	}
+	for j := i - 1; j >= 0; j-- {
+		if s.buf[j].class != glyphClassMark {
+			return j
+		}
+	}
+	return -1
+}
+
+func (s *shaper) applyMarkAttachPos(p *markAttachPos, i int) (int, bool) {
+	mi := p.markCov.index(s.buf[i].gid)
+	if mi < 0 || mi >= len(p.marks) {
+		return 0, false
+	}
+	j := s.markBase(i, p.kind)
+	if j < 0 {
+		return 0, false
+	}
+	bi := p.baseCov.index(s.buf[j].gid)
+	if bi < 0 || bi >= len(p.anchors) {
+		return 0, false
+	}
+	comps := p.anchors[bi]
+	if len(comps) == 0 {
+		return 0, false
+	}
+	comp := len(comps) - 1
+	if p.kind == 5 {
+		if g := s.buf[i]; g.ligID > 0 && g.ligID == s.buf[j].ligID && g.ligComp > 0 && g.ligComp <= len(comps) {
+			comp = g.ligComp - 1
+		}
+	}
+	m := p.marks[mi]
+	if m.class >= len(comps[comp]) || comps[comp][m.class] == nil || m.anchor == nil {
+		return 0, false
+	}
+	a := comps[comp][m.class]
+	g := &s.buf[i]
+	g.xOff = a.x - m.anchor.x
+	g.yOff = a.y - m.anchor.y
+	g.attachKind = attachMark
+	g.attach = j - i
+	return i + 1, true
+}
+
+func (s *shaper) applyPosSubtable(st any, i int) (int, bool) {
+	switch st := st.(type) {
+	case *singlePos:
+		return s.applySinglePos(st, i)
+	case *pairPos:
+		return s.applyPairPos(st, i)
+	case *cursivePos:
+		return s.applyCursivePos(st, i)
+	case *markAttachPos:
+		return s.applyMarkAttachPos(st, i)
+	case *contextSubtable:
+		return s.applyContext(st, i)
+	}
+	return 0, false
+}
+
+// position applies a GPOS lookup to all glyphs enabled by mask.
+func (s *shaper) position(lu *lookup, mask uint32) {
+	s.setLookup(lu)
+	for i := 0; i < len(s.buf); {
+		if s.buf[i].mask&mask == 0 || s.ignored(&s.buf[i]) {
+			i++
+			continue
+		}
+		next, ok := s.applyLookupAt(lu, i)
+		if !ok || next <= i {
+			next = i + 1
+		}
+		i = next
+	}
+}
+
+// propagateAttachments resolves offsets of attached glyphs relative to their own pen position.
+func (s *shaper) propagateAttachments() {
+	done := make([]bool, len(s.buf))
+	for i := range s.buf {
+		s.propagateAttachment(i, done, 0)
+	}
+}
+
+func (s *shaper) propagateAttachment(i int, done []bool, depth int) {
+	if done[i] || depth > 32 {
+		return
+	}
+	done[i] = true
+	g := &s.buf[i]
+	if g.attachKind == 0 {
+		return
+	}
+	j := i + g.attach
+	if j < 0 || j >= len(s.buf) || j == i {
+		return
+	}
+	s.propagateAttachment(j, done, depth+1)
+	p := s.buf[j]
+
+	g.yOff += p.yOff
+	if g.attachKind == attachCursive {
+		return
+	}
+	g.xOff += p.xOff
+	if !s.rtl {
+		for k := j; k < i; k++ {
+			g.xOff -= s.buf[k].xAdv
+		}
+		return
+	}
+	for k := j + 1; k <= i; k++ {
+		g.xOff += s.buf[k].xAdv
+	}
+}
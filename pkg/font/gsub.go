@@ -0,0 +1,286 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+// Glyph substitution table (GSUB).
+// See https://learn.microsoft.com/en-us/typography/opentype/spec/gsub
+
+const gsubExtension = 7
+
+type singleSubst struct {
+	cov         *coverage
+	delta       int
+	substitutes []uint16
+}
+
+// multipleSubst also covers alternate substitution where the first alternate is used.
+type multipleSubst struct {
+	cov       *coverage
+	sequences [][]uint16
+}
+
+type ligature struct {
+	glyph      uint16
+	components []uint16 // excluding the first component
+}
+
+type ligatureSubst struct {
+	cov     *coverage
+	ligSets [][]ligature
+}
+
+type reverseChainSubst struct {
+	cov                  *coverage
+	backtrack, lookahead []*coverage
+	substitutes          []uint16
+}
+
+func parseGSUBSubtable(kind int, b otlData) any {
+	switch kind {
+	case 1:
+		s := &singleSubst{cov: parseCoverage(b.at(int(b.u16(2))))}
+		switch b.u16(0) {
+		case 1:
+			s.delta = int(b.i16(4))
+		case 2:
+			s.substitutes = b.u16s(6, int(b.u16(4)))
+		default:
+			return nil
+		}
+		return s
+	case 2, 3:
+		s := &multipleSubst{cov: parseCoverage(b.at(int(b.u16(2))))}
+		n := int(b.u16(4))
+		s.sequences = make([][]uint16, n)
+		for i := 0; i < n; i++ {
+			seq := b.at(int(b.u16(6 + 2*i)))
+			gg := seq.u16s(2, int(seq.u16(0)))
+			if kind == 3 && len(gg) > 1 {
+				gg = gg[:1]
+			}
+			s.sequences[i] = gg
+		}
+		return s
+	case 4:
+		s := &ligatureSubst{cov: parseCoverage(b.at(int(b.u16(2))))}
+		n := int(b.u16(4))
+		s.ligSets = make([][]ligature, n)
+		for i := 0; i < n; i++ {
+			ls := b.at(int(b.u16(6 + 2*i)))
+			c := int(ls.u16(0))
+			for j := 0; j < c; j++ {
+				l := ls.at(int(ls.u16(2 + 2*j)))
+				if l == nil {
+					continue
+				}
+				s.ligSets[i] = append(s.ligSets[i], ligature{glyph: l.u16(0), components: l.u16s(4, int(l.u16(2))-1)})
+			}
+		}
+		return s
+	case 5:
+		return parseContextSubtable(b)
+	case 6:
+		return parseChainedContextSubtable(b)
+	case 8:
+		s := &reverseChainSubst{cov: parseCoverage(b.at(int(b.u16(2))))}
+		o := 4
+		c := int(b.u16(o))
+		s.backtrack = parseCoverages(b, o+2, c)
+		o += 2 + 2*c
+		c = int(b.u16(o))
+		s.lookahead = parseCoverages(b, o+2, c)
+		o += 2 + 2*c
+		s.substitutes = b.u16s(o+2, int(b.u16(o)))
+		return s
+	}
+	return nil
+}
+
+func parseGSUB(b otlData) *layoutTable {
+	return parseLayoutTable(b, gsubExtension, parseGSUBSubtable)
+}
+
+func (s *shaper) applySingleSubst(st *singleSubst, i int) (int, bool) {
+	ci := st.cov.index(s.buf[i].gid)
+	if ci < 0 {
+		return 0, false
+	}
+	if st.substitutes == nil {
+		s.setGlyph(i, uint16(int(s.buf[i].gid)+st.delta))
+		return i + 1, true
+	}
+	if ci >= len(st.substitutes) {
+		return 0, false
+	}
+	s.setGlyph(i, st.substitutes[ci])
+	return i + 1, true
+}
+
+func (s *shaper) applyMultipleSubst(st *multipleSubst, i int) (int, bool) {
+	ci := st.cov.index(s.buf[i].gid)
+	if ci < 0 || ci >= len(st.sequences) {
+		return 0, false
+	}
+	seq := st.sequences[ci]
+	if len(seq) == 1 {
+		s.setGlyph(i, seq[0])
+		return i + 1, true
+	}
+	g := s.buf[i]
+	gg := make([]glyphInfo, len(seq))
+	for j, gid := range seq {
+		gg[j] = g
+		if j > 0 {
+			gg[j].runes = nil
+		}
+		gg[j].gid = gid
+		gg[j].class = s.l.glyphClass(gid, g.class)
+	}
+	s.buf = append(s.buf[:i], append(gg, s.buf[i+1:]...)...)
+	return i + len(seq), true
+}
+
+func (s *shaper) applyLigatureSubst(st *ligatureSubst, i int) (int, bool) {
+	ci := st.cov.index(s.buf[i].gid)
+	if ci < 0 || ci >= len(st.ligSets) {
+		return 0, false
+	}
+	for _, lig := range st.ligSets[ci] {
+		pos := s.matchInput(i, len(lig.components)+1, func(k int, gid uint16) bool {
+			return gid == lig.components[k-1]
+		})
+		if pos == nil {
+			continue
+		}
+		s.ligate(pos, lig.glyph)
+		return i + 1, true
+	}
+	return 0, false
+}
+
+// ligate replaces the glyphs at pos with a ligature glyph.
+// Skipped marks in between remember the ligature component they belong to.
+func (s *shaper) ligate(pos []int, gid uint16) {
+	first := pos[0]
+	allMarks := true
+	for _, p := range pos {
+		if s.buf[p].class != glyphClassMark {
+			allMarks = false
+		}
+	}
+	ligID := 0
+	if !allMarks {
+		s.ligID++
+		ligID = s.ligID
+	}
+
+	var runes []rune
+	comp, k := 1, 1
+	last := pos[len(pos)-1]
+	for j := first; j <= last; j++ {
+		if j == pos[0] {
+			runes = append(runes, s.buf[j].runes...)
+			continue
+		}
+		if k < len(pos) && j == pos[k] {
+			runes = append(runes, s.buf[j].runes...)
+			comp++
+			k++
+			continue
+		}
+		if ligID > 0 && s.buf[j].class == glyphClassMark {
+			s.buf[j].ligID, s.buf[j].ligComp = ligID, comp
+		}
+	}
+	// Marks following the ligature attach to its last component.
+	for j := last + 1; ligID > 0 && j < len(s.buf) && s.buf[j].class == glyphClassMark; j++ {
+		if s.buf[j].ligID == 0 {
+			s.buf[j].ligID, s.buf[j].ligComp = ligID, comp
+		}
+	}
+
+	s.buf[first].runes = runes
+	s.buf[first].ligID, s.buf[first].ligComp = ligID, 0
+	s.buf[first].compCount = len(pos)
+	s.setGlyph(first, gid)
+	if allMarks {
+		s.buf[first].class = glyphClassMark
+	}
+
+	for k := len(pos) - 1; k > 0; k-- {
+		p := pos[k]
+		s.buf = append(s.buf[:p], s.buf[p+1:]...)
+	}
+}
+
+func (s *shaper) applyReverseChainSubst(st *reverseChainSubst, i int) bool {
+	ci := st.cov.index(s.buf[i].gid)
+	if ci < 0 || ci >= len(st.substitutes) {
+		return false
+	}
+	if !s.matchBacktrack(i, len(st.backtrack), func(k int, gid uint16) bool { return st.backtrack[k].index(gid) >= 0 }) {
+		return false
+	}
+	if !s.matchLookahead(i, len(st.lookahead), func(k int, gid uint16) bool { return st.lookahead[k].index(gid) >= 0 }) {
+		return false
+	}
+	s.setGlyph(i, st.substitutes[ci])
+	return true
+}
+
+func (s *shaper) applySubstSubtable(st any, i int) (int, bool) {
+	switch st := st.(type) {
+	case *singleSubst:
+		return s.applySingleSubst(st, i)
+	case *multipleSubst:
+		return s.applyMultipleSubst(st, i)
+	case *ligatureSubst:
+		return s.applyLigatureSubst(st, i)
+	case *contextSubtable:
+		return s.applyContext(st, i)
+	}
+	return 0, false
+}
+
+// substitute applies a GSUB lookup to all glyphs enabled by mask.
+func (s *shaper) substitute(lu *lookup, mask uint32) {
+	s.setLookup(lu)
+	if lu.kind == 8 {
+		for i := len(s.buf) - 1; i >= 0; i-- {
+			if s.buf[i].mask&mask == 0 || s.ignored(&s.buf[i]) {
+				continue
+			}
+			for _, st := range lu.subtables {
+				if st, ok := st.(*reverseChainSubst); ok && s.applyReverseChainSubst(st, i) {
+					break
+				}
+			}
+		}
+		return
+	}
+	for i := 0; i < len(s.buf); {
+		if s.buf[i].mask&mask == 0 || s.ignored(&s.buf[i]) {
+			i++
+			continue
+		}
+		next, ok := s.applyLookupAt(lu, i)
+		if !ok {
+			next = i + 1
+		}
+		i = next
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+// Shaping of Indic scripts following the Unicode block layout shared by
+// Devanagari, Bengali, Gurmukhi, Gujarati, Oriya, Tamil, Telugu, Kannada and Malayalam.
+// See https://learn.microsoft.com/en-us/typography/script-development/devanagari
+
+// Indic character categories.
+const (
+	indicOther uint8 = iota
+	indicConsonant
+	indicVowel
+	indicMatra
+	indicPreBaseMatra
+	indicNukta
+	indicHalant
+	indicModifier
+	indicZWJ
+	indicZWNJ
+)
+
+const indicRa = 0x30 // RA offset within each Indic block
+
+type indicScript struct {
+	tags    []string // new and old OpenType script tags
+	preBase []rune   // pre-base matra offsets
+}
+
+var indicScripts = map[rune]indicScript{
+	0x0900: {[]string{"dev2", "deva"}, []rune{0x3F, 0x4E}},
+	0x0980: {[]string{"bng2", "beng"}, []rune{0x3F, 0x47, 0x48}},
+	0x0A00: {[]string{"gur2", "guru"}, []rune{0x3F}},
+	0x0A80: {[]string{"gjr2", "gujr"}, []rune{0x3F}},
+	0x0B00: {[]string{"ory2", "orya"}, []rune{0x47}},
+	0x0B80: {[]string{"tml2", "taml"}, []rune{0x46, 0x47, 0x48}},
+	0x0C00: {[]string{"tel2", "telu"}, nil},
+	0x0C80: {[]string{"knd2", "knda"}, nil},
+	0x0D00: {[]string{"mlm2", "mlym"}, []rune{0x46, 0x47, 0x48}},
+}
+
+// Two part vowel signs decomposed before shaping.
+var indicSplitMatras = map[rune][]rune{
+	0x09CB: {0x09C7, 0x09BE}, 0x09CC: {0x09C7, 0x09D7},
+	0x0B48: {0x0B47, 0x0B56}, 0x0B4B: {0x0B47, 0x0B3E}, 0x0B4C: {0x0B47, 0x0B57},
+	0x0BCA: {0x0BC6, 0x0BBE}, 0x0BCB: {0x0BC7, 0x0BBE}, 0x0BCC: {0x0BC6, 0x0BD7},
+	0x0D4A: {0x0D46, 0x0D3E}, 0x0D4B: {0x0D47, 0x0D3E}, 0x0D4C: {0x0D46, 0x0D57},
+}
+
+func indicCategory(r, block rune, preBase []rune) uint8 {
+	switch r {
+	case 0x200C:
+		return indicZWNJ
+	case 0x200D:
+		return indicZWJ
+	}
+	if r&^0x7F != block {
+		return indicOther
+	}
+	o := r - block
+	switch {
+	case o >= 0x01 && o <= 0x03:
+		return indicModifier
+	case o >= 0x04 && o <= 0x14, o == 0x60 || o == 0x61, block == 0x0900 && o >= 0x72 && o <= 0x77:
+		return indicVowel
+	case o >= 0x15 && o <= 0x39, o >= 0x58 && o <= 0x5F, block == 0x0900 && o >= 0x78:
+		return indicConsonant
+	case o == 0x3C:
+		return indicNukta
+	case o == 0x4D:
+		return indicHalant
+	case o == 0x3A || o == 0x3B || o >= 0x3E && o <= 0x4C || o == 0x4E || o == 0x4F || o >= 0x55 && o <= 0x57 || o == 0x62 || o == 0x63:
+		for _, pb := range preBase {
+			if o == pb {
+				return indicPreBaseMatra
+			}
+		}
+		return indicMatra
+	}
+	return indicOther
+}
+
+// Indic feature masks.
+const (
+	maskRphf = 1 << (iota + 1)
+	maskHalf
+	maskPostBase
+)
+
+var (
+	indicBasicStages = []stage{
+		{"locl", "ccmp"}, {"nukt"}, {"akhn"}, {"rphf"}, {"rkrf"}, {"pref"}, {"blwf"}, {"abvf"}, {"half"}, {"pstf"}, {"vatu"}, {"cjct"},
+	}
+	indicPresentationStages = []stage{{"init"}, {"pres", "abvs", "blws", "psts", "haln"}, {"calt", "clig"}}
+	indicGPOSStages         = []stage{{"kern", "dist", "abvm", "blwm"}}
+)
+
+// indicSyllables segments the glyph buffer into syllables and returns their boundaries.
+func (s *shaper) indicSyllables() [][2]int {
+	var syllables [][2]int
+	n := len(s.buf)
+	cat := func(i int) uint8 {
+		if i < n {
+			return s.buf[i].cat
+		}
+		return indicOther
+	}
+	for i := 0; i < n; {
+		start := i
+		switch cat(i) {
+		case indicConsonant:
+			for {
+				// C N? (H ZWJ|ZWNJ? C N?)*
+				i++
+				if cat(i) == indicNukta {
+					i++
+				}
+				j := i
+				if cat(j) != indicHalant {
+					break
+				}
+				j++
+				if c := cat(j); c == indicZWJ || c == indicZWNJ {
+					j++
+				}
+				if cat(j) != indicConsonant {
+					break
+				}
+				i = j
+			}
+			for cat(i) == indicMatra || cat(i) == indicPreBaseMatra || cat(i) == indicNukta {
+				i++
+			}
+			if c := cat(i); c == indicHalant {
+				i++
+				if c := cat(i); c == indicZWJ || c == indicZWNJ {
+					i++
+				}
+			}
+		case indicVowel:
+			i++
+			for c := cat(i); c == indicNukta || c == indicMatra || c == indicPreBaseMatra; c = cat(i) {
+				i++
+			}
+		default:
+			i++
+		}
+		for cat(i) == indicModifier {
+			i++
+		}
+		syllables = append(syllables, [2]int{start, i})
+	}
+	for k, sy := range syllables {
+		for i := sy[0]; i < sy[1]; i++ {
+			s.buf[i].syllable = k
+		}
+	}
+	return syllables
+}
+
+// reorderIndicSyllable determines base consonant and reph of a consonant syllable,
+// sets feature masks and moves pre-base matras in front of the syllable.
+func (s *shaper) reorderIndicSyllable(ls *langSys, start, end int) {
+	if s.buf[start].cat != indicConsonant {
+		return
+	}
+
+	// The base consonant is the last consonant not taking a below-base or post-base form.
+	base := start
+	for i := end - 1; i > start; i-- {
+		if s.buf[i].cat != indicConsonant {
+			continue
+		}
+		base = i
+		if s.buf[i-1].cat == indicHalant {
+			h, c := s.buf[i-1].gid, s.buf[i].gid
+			if s.wouldSubstitute(ls, "blwf", []uint16{h, c}) || s.wouldSubstitute(ls, "pstf", []uint16{h, c}) ||
+				s.wouldSubstitute(ls, "blwf", []uint16{c, h}) || s.wouldSubstitute(ls, "pstf", []uint16{c, h}) {
+				continue
+			}
+		}
+		break
+	}
+
+	// Reph: syllable initial RA + halant followed by another consonant.
+	reph := false
+	if base > start+1 && s.buf[start].runes[0]&0x7F == indicRa && s.buf[start+1].cat == indicHalant &&
+		s.wouldSubstitute(ls, "rphf", []uint16{s.buf[start].gid, s.buf[start+1].gid}) {
+		reph = true
+		for i := start; i < start+2; i++ {
+			s.buf[i].mask |= maskRphf
+			s.buf[i].reph = true
+		}
+		if base == start {
+			base = start + 2
+		}
+	}
+
+	from := start
+	if reph {
+		from = start + 2
+	}
+	for i := from; i < base; i++ {
+		s.buf[i].mask |= maskHalf
+	}
+	for i := base + 1; i < end; i++ {
+		s.buf[i].mask |= maskPostBase
+	}
+
+	// Move pre-base matras in front of the pre-base consonants.
+	for i := base + 1; i < end; i++ {
+		if s.buf[i].cat != indicPreBaseMatra {
+			continue
+		}
+		m := s.buf[i]
+		copy(s.buf[from+1:i+1], s.buf[from:i])
+		s.buf[from] = m
+		from++
+	}
+}
+
+// finalReorderIndic moves formed rephs to the end of their syllable, before any syllable modifiers.
+func (s *shaper) finalReorderIndic() {
+	for i := 0; i < len(s.buf); i++ {
+		g := s.buf[i]
+		if !g.reph {
+			continue
+		}
+		if i+1 < len(s.buf) && s.buf[i+1].reph && s.buf[i+1].syllable == g.syllable {
+			// Not formed: RA and halant are still separate glyphs.
+			s.buf[i].reph, s.buf[i+1].reph = false, false
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(s.buf) && s.buf[end].syllable == g.syllable {
+			end++
+		}
+		to := end
+		for to > i+1 && s.buf[to-1].cat == indicModifier {
+			to--
+		}
+		g.reph = false
+		copy(s.buf[i:to-1], s.buf[i+1:to])
+		s.buf[to-1] = g
+	}
+}
+
+func (s *shaper) shapeIndic(block rune) {
+	is, ok := indicScripts[block]
+	if !ok {
+		s.shapeDefault(nil)
+		return
+	}
+
+	// Decompose split matras, the first part keeps the original character.
+	var buf []glyphInfo
+	for _, g := range s.buf {
+		rr, ok := indicSplitMatras[g.runes[0]]
+		if !ok {
+			g.cat = indicCategory(g.runes[0], block, is.preBase)
+			buf = append(buf, g)
+			continue
+		}
+		for i, r := range rr {
+			g1 := g
+			g1.gid = s.l.chars[uint32(r)]
+			g1.cat = indicCategory(r, block, is.preBase)
+			if i > 0 {
+				g1.runes = nil
+			}
+			buf = append(buf, g1)
+		}
+	}
+	s.buf = buf
+
+	s.features["rphf"] = maskRphf
+	s.features["half"] = maskHalf
+	for _, f := range []string{"pref", "blwf", "abvf", "pstf", "vatu"} {
+		s.features[f] = maskPostBase | maskHalf
+	}
+	s.enableFeatures(indicBasicStages, indicPresentationStages, indicGPOSStages)
+
+	ls := s.l.gsub.langSys(is.tags, "")
+	s.removeMissingGlyphs()
+	for _, sy := range s.indicSyllables() {
+		s.reorderIndicSyllable(ls, sy[0], sy[1])
+	}
+
+	s.applyStages(s.l.gsub, ls, indicBasicStages, false)
+	s.finalReorderIndic()
+	s.applyStages(s.l.gsub, ls, indicPresentationStages, false)
+	s.initAdvances()
+	s.applyStages(s.l.gpos, s.l.gpos.langSys(is.tags, ""), indicGPOSStages, true)
+}
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package font provides support for TrueType fonts.
+// Package font provides support for TrueType and OpenType fonts.
 package font
 
 import (
@@ -62,6 +62,7 @@ type ttf struct {
 	Chars              map[uint32]uint16 // cmap: Unicode character to glyph index
 	ToUnicode          map[uint16]uint32 // map glyph index to unicode character
 	Planes             map[int]bool      // used Unicode planes
+	CFF                bool              // CFF: glyph outlines in Compact Font Format
 	FontFile           []byte
 }
 
@@ -80,7 +81,8 @@ FontBoundingBox = (%.2f, %.2f, %.2f, %.2f)
      FixedPitch = %t
            Bold = %t
 HorMetricsCount = %d
-     GlyphCount = %d`,
+     GlyphCount = %d
+            CFF = %t`,
 		fd.PostscriptName,
 		fd.Protected,
 		fd.UnitsPerEm,
@@ -95,6 +97,7 @@ HorMetricsCount = %d
 		fd.Bold,
 		fd.HorMetricsCount,
 		fd.GlyphCount,
+		fd.CFF,
 	)
 }
 
@@ -164,7 +167,7 @@ func (t table) uint32(off int) uint32 {
 }
 
 func (t table) fixed32(off int) float64 {
-	return float64(t.uint32(off)) / 65536.0
+	return float64(int32(t.uint32(off))) / 65536.0
 }
 
 func (t table) parseFontHeaderTable(fd *ttf) error {
@@ -541,11 +544,7 @@ func headerAndTables(fn string, r io.ReaderAt, baseOff int64) ([]byte, map[strin
 
 	st := string(header[:4])
 
-	if st == sfntVersionCFF {
-		return nil, nil, fmt.Errorf("pdfcpu: %s is based on OpenType CFF and unsupported at the moment :(", fn)
-	}
-
-	if st != sfntVersionTrueType && st != sfntVersionTrueTypeApple {
+	if st != sfntVersionTrueType && st != sfntVersionTrueTypeApple && st != sfntVersionCFF {
 		return nil, nil, fmt.Errorf("pdfcpu: unrecognized font format: %s", fn)
 	}
 
@@ -623,6 +622,8 @@ func parse(tags map[string]*table, tag string, fd *ttf) error {
 		err = t.parseHorizontalMetricsTable(fd)
 	case "cmap":
 		err = t.parseCharToGlyphMappingTable(fd)
+	case "CFF ":
+		err = t.parseCompactFontFormatTable(fd)
 	}
 
 	return err
@@ -651,7 +652,12 @@ func readGob(fileName string, fd *ttf) error {
 func installTrueTypeRep(fontDir, fontName string, header []byte, tables map[string]*table) error {
 	fd := ttf{}
 	//fmt.Println(fontName)
-	for _, v := range []string{"head", "OS/2", "post", "name", "hhea", "maxp", "hmtx", "cmap"} {
+	tags := []string{"head", "OS/2", "post", "name", "hhea", "maxp", "hmtx", "cmap"}
+	if string(header[:4]) == sfntVersionCFF {
+		// OpenType font with CFF based glyph outlines.
+		tags = append(tags, "CFF ")
+	}
+	for _, v := range tags {
 		if err := parse(tables, v, &fd); err != nil {
 			return err
 		}
@@ -735,23 +741,25 @@ func InstallTrueTypeCollection(fontDir, fn string) error {
 	return nil
 }
 
-// InstallTrueTypeFont saves an internal representation of TrueType font fontName to the pdfcpu config dir.
+// InstallTrueTypeFont saves an internal representation of TrueType or OpenType font fontName to the pdfcpu config dir.
+// WOFF and WOFF2 encoded fonts are decoded into sfnt.
 func InstallTrueTypeFont(fontDir, fontName string) error {
-	f, err := os.Open(fontName)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	header, tables, err := headerAndTables(fontName, f, 0)
+	bb, err := os.ReadFile(fontName)
 	if err != nil {
 		return err
 	}
-	return installTrueTypeRep(fontDir, fontName, header, tables)
+	return InstallFontFromBytes(fontDir, fontName, bb)
 }
 
-// InstallFontFromBytes saves an internal representation of TrueType font fontName to the pdfcpu config dir.
+// InstallFontFromBytes saves an internal representation of TrueType or OpenType font fontName to the pdfcpu config dir.
+// WOFF and WOFF2 encoded fonts are decoded into sfnt.
 func InstallFontFromBytes(fontDir, fontName string, bb []byte) error {
+	if isWebFont(bb) {
+		var err error
+		if bb, err = decodeWebFont(fontName, bb); err != nil {
+			return err
+		}
+	}
 	rd := bytes.NewReader(bb)
 	header, tables, err := headerAndTables(fontName, rd, 0)
 	if err != nil {
@@ -988,7 +996,7 @@ func createTTF(header []byte, tables map[string]*table) ([]byte, error) {
 		if _, err := buf.WriteString(tag); err != nil {
 			return nil, err
 		}
-		if tag == "loca" || tag == "glyf" {
+		if tag == "loca" || tag == "glyf" || tag == "CFF " {
 			t.chksum = calcTableChecksum(tag, t.data)
 		}
 		if _, err := buf.Write(uint32ToBigEndianBytes(t.chksum)); err != nil {
@@ -1032,6 +1040,13 @@ func Subset(fontName string, usedGIDs map[uint16]bool) ([]byte, error) {
 		return nil, err
 	}
 
+	if string(header[:4]) == sfntVersionCFF {
+		if err := cffSubset(fontName, tables, usedGIDs); err != nil {
+			return nil, err
+		}
+		return createTTF(header, tables)
+	}
+
 	if err := glyfAndLoca(fontName, tables, usedGIDs); err != nil {
 		return nil, err
 	}
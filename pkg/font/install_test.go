@@ -0,0 +1,37 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestPostScriptTableItalicAngle(t *testing.T) {
+	for _, want := range []float64{0, 12.5, -12.5, -0.25} {
+		data := make([]byte, 32)
+		binary.BigEndian.PutUint32(data[4:], uint32(int32(want*65536)))
+
+		fd := &ttf{}
+		if err := (table{data: data}).parsePostScriptTable(fd); err != nil {
+			t.Fatal(err)
+		}
+		if fd.ItalicAngle != want {
+			t.Fatalf("italic angle: got %.2f want %.2f\n", fd.ItalicAngle, want)
+		}
+	}
+}
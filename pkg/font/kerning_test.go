@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"testing"
+)
+
+func TestCoreFontKerning(t *testing.T) {
+	// KPX A V -70
+	if got := CoreFontKerning("Helvetica", 'A', 'V'); got != -70 {
+		t.Errorf("Helvetica AV: want -70 got %d\n", got)
+	}
+	if got := CoreFontKerning("Courier", 'A', 'V'); got != 0 {
+		t.Errorf("Courier AV: want 0 got %d\n", got)
+	}
+
+	want := TextWidth("AV", "Helvetica", 10) - 0.7
+	if got := KernedTextWidth("AV", "Helvetica", 10); got != want {
+		t.Errorf("KernedTextWidth: want %f got %f\n", want, got)
+	}
+	if got, want := KernedTextWidth("AVA", "Courier", 10), TextWidth("AVA", "Courier", 10); got != want {
+		t.Errorf("KernedTextWidth Courier: want %f got %f\n", want, got)
+	}
+}
+
+func TestShapeKerned(t *testing.T) {
+	fd := installShapingFont(t)
+
+	// Plain Latin text is not kerned by Shape.
+	gg := Shape(shapingFont, "AV", false)
+	if len(gg) != 2 || gg[0].Width != fd.GlyphWidths[fd.Chars['A']] {
+		t.Fatalf("Shape: unexpected glyphs %v\n", gg)
+	}
+
+	gg = ShapeKerned(shapingFont, "AV", false)
+	if len(gg) != 2 || gg[0].Width >= fd.GlyphWidths[fd.Chars['A']] {
+		t.Errorf("ShapeKerned: want kerned A, got %v\n", gg)
+	}
+
+	// Standard ligatures
+	gg = ShapeKerned(shapingFont, "fi", false)
+	if len(gg) != 1 || string(gg[0].Runes) != "fi" {
+		t.Errorf("ShapeKerned: want fi ligature, got %v\n", gg)
+	}
+
+	if KernedTextWidth("AVA", shapingFont, 10) >= TextWidth("AVA", shapingFont, 10) {
+		t.Errorf("KernedTextWidth: want kerned width < unkerned width\n")
+	}
+}
+
+func TestApplyKernTable(t *testing.T) {
+	l := &fontLayout{upem: 2048, kern: map[uint32]int{1<<16 | 2: -205}}
+
+	// Marks are skipped.
+	gg := []Glyph{{GID: 1, Width: 500}, {GID: 3}, {GID: 2, Width: 400}, {GID: 1, Width: 500}}
+	l.applyKernTable(gg)
+	if gg[0].Width != 400 || gg[2].Width != 400 {
+		t.Errorf("want kerned first glyph, got %v\n", gg)
+	}
+}
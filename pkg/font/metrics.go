@@ -34,7 +34,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-// TTFLight represents a TrueType font w/o font file.
+// TTFLight represents a TrueType or OpenType font w/o font file.
 type TTFLight struct {
 	PostscriptName     string            // name: NameID 6
 	Protected          bool              // OS/2: fsType
@@ -55,6 +55,7 @@ type TTFLight struct {
 	Chars              map[uint32]uint16 // cmap: Unicode character to glyph index
 	ToUnicode          map[uint16]uint32 // map glyph index to unicode character
 	Planes             map[int]bool      // used Unicode planes
+	CFF                bool              // CFF: glyph outlines in Compact Font Format
 }
 
 func (fd TTFLight) String() string {
@@ -73,7 +74,8 @@ FontBoundingBox = (%.2f, %.2f, %.2f, %.2f)
            Bold = %t
 HorMetricsCount = %d
 	 GlyphCount = %d
-len(GlyphWidths) = %d`,
+len(GlyphWidths) = %d
+            CFF = %t`,
 		fd.PostscriptName,
 		fd.Protected,
 		fd.UnitsPerEm,
@@ -89,6 +91,7 @@ len(GlyphWidths) = %d`,
 		fd.HorMetricsCount,
 		fd.GlyphCount,
 		len(fd.GlyphWidths),
+		fd.CFF,
 	)
 }
 
@@ -363,22 +366,85 @@ func glyphSpaceWidth(text, fontName string) int {
 		}
 		return w
 	}
+	if NeedsShaping(text) && IsUserFont(fontName) {
+		for _, g := range Shape(fontName, text, false) {
+			w += g.Width
+		}
+		return w
+	}
 	for _, r := range text {
 		w += CharWidth(fontName, r)
 	}
 	return w
 }
 
+// CoreFontKerning returns the kerning amount for the char codes c1 and c2 of core font fontName in glyph space units.
+func CoreFontKerning(fontName string, c1, c2 byte) int {
+	return metrics.CoreFontCharKerning(fontName, int(c1), int(c2))
+}
+
+// kernedGlyphSpaceWidth returns the width of text applying kerning and standard ligatures.
+func kernedGlyphSpaceWidth(text, fontName string) int {
+	if IsCoreFont(fontName) {
+		w := glyphSpaceWidth(text, fontName)
+		for i := 1; i < len(text); i++ {
+			w += CoreFontKerning(fontName, text[i-1], text[i])
+		}
+		return w
+	}
+	if !IsUserFont(fontName) {
+		return glyphSpaceWidth(text, fontName)
+	}
+	var w int
+	for _, g := range ShapeKerned(fontName, text, false) {
+		w += g.Width
+	}
+	return w
+}
+
+// fallbackGlyphSpaceWidth returns the width of UTF-8 encoded text rendered using fontName and fallbacks.
+func fallbackGlyphSpaceWidth(text, fontName string, fallbacks []string, kern bool) int {
+	width := glyphSpaceWidth
+	if kern {
+		width = kernedGlyphSpaceWidth
+	}
+	if len(fallbacks) == 0 {
+		return width(text, fontName)
+	}
+	var w int
+	for _, run := range FontRuns(text, fontName, false, fallbacks...) {
+		s := run.Text
+		if IsCoreFont(run.FontName) {
+			s = coreFontText(s)
+		}
+		w += width(s, run.FontName)
+	}
+	return w
+}
+
 // TextWidth represents the width in user space units for a given text string, font name and font size.
-func TextWidth(text, fontName string, fontSize int) float64 {
-	w := glyphSpaceWidth(text, fontName)
+// Characters missing in fontName are measured using the first font of fallbacks supporting them.
+func TextWidth(text, fontName string, fontSize int, fallbacks ...string) float64 {
+	w := fallbackGlyphSpaceWidth(text, fontName, fallbacks, false)
+	return UserSpaceUnits(float64(w), fontSize)
+}
+
+// KernedTextWidth works like TextWidth but takes kerning and standard ligatures into account.
+func KernedTextWidth(text, fontName string, fontSize int, fallbacks ...string) float64 {
+	w := fallbackGlyphSpaceWidth(text, fontName, fallbacks, true)
 	return UserSpaceUnits(float64(w), fontSize)
 }
 
 // Size returns the needed font size (aka. font scaling factor) in points
-// for rendering a given text string using a given font name with a given user space width.
-func Size(text, fontName string, width float64) int {
-	w := glyphSpaceWidth(text, fontName)
+// for rendering a given text string using a given font name and optional fallback fonts with a given user space width.
+func Size(text, fontName string, width float64, fallbacks ...string) int {
+	w := fallbackGlyphSpaceWidth(text, fontName, fallbacks, false)
+	return fontScalingFactor(float64(w), width)
+}
+
+// KernedSize works like Size but takes kerning and standard ligatures into account.
+func KernedSize(text, fontName string, width float64, fallbacks ...string) int {
+	w := fallbackGlyphSpaceWidth(text, fontName, fallbacks, true)
 	return fontScalingFactor(float64(w), width)
 }
 
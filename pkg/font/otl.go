@@ -0,0 +1,524 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// OpenType Layout common table formats shared by GSUB, GPOS and GDEF.
+// See https://learn.microsoft.com/en-us/typography/opentype/spec/chapter2
+
+// Lookup flags.
+const (
+	lookupRightToLeft         = 0x0001
+	lookupIgnoreBaseGlyphs    = 0x0002
+	lookupIgnoreLigatures     = 0x0004
+	lookupIgnoreMarks         = 0x0008
+	lookupUseMarkFilteringSet = 0x0010
+	lookupMarkAttachmentType  = 0xFF00
+)
+
+// GDEF glyph classes.
+const (
+	glyphClassBase      = 1
+	glyphClassLigature  = 2
+	glyphClassMark      = 3
+	glyphClassComponent = 4
+)
+
+// otlData is a bounds checked view on OpenType layout table data.
+// Corrupt offsets yield zero values instead of panics.
+type otlData []byte
+
+func (b otlData) u16(off int) uint16 {
+	if off < 0 || off+2 > len(b) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b[off:])
+}
+
+func (b otlData) i16(off int) int16 {
+	return int16(b.u16(off))
+}
+
+func (b otlData) u32(off int) uint32 {
+	if off < 0 || off+4 > len(b) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b[off:])
+}
+
+func (b otlData) tag(off int) string {
+	if off < 0 || off+4 > len(b) {
+		return ""
+	}
+	return string(b[off : off+4])
+}
+
+// at returns the data starting at off or nil for a null or invalid offset.
+func (b otlData) at(off int) otlData {
+	if off <= 0 || off >= len(b) {
+		return nil
+	}
+	return b[off:]
+}
+
+// u16s returns count uint16 values starting at off.
+func (b otlData) u16s(off, count int) []uint16 {
+	if count <= 0 || off < 0 || off+2*count > len(b) {
+		return nil
+	}
+	vv := make([]uint16, count)
+	for i := range vv {
+		vv[i] = b.u16(off + 2*i)
+	}
+	return vv
+}
+
+type glyphRange struct {
+	start, end uint16
+	value      int // start coverage index or class
+}
+
+// coverage maps glyph ids to coverage indices.
+type coverage struct {
+	glyphs []uint16
+	ranges []glyphRange
+}
+
+func parseCoverage(b otlData) *coverage {
+	if b == nil {
+		return nil
+	}
+	c := &coverage{}
+	switch b.u16(0) {
+	case 1:
+		c.glyphs = b.u16s(4, int(b.u16(2)))
+	case 2:
+		n := int(b.u16(2))
+		for i := 0; i < n; i++ {
+			off := 4 + 6*i
+			c.ranges = append(c.ranges, glyphRange{b.u16(off), b.u16(off + 2), int(b.u16(off + 4))})
+		}
+	}
+	return c
+}
+
+// index returns the coverage index of gid or -1.
+func (c *coverage) index(gid uint16) int {
+	if c == nil {
+		return -1
+	}
+	if c.ranges == nil {
+		i := sort.Search(len(c.glyphs), func(i int) bool { return c.glyphs[i] >= gid })
+		if i < len(c.glyphs) && c.glyphs[i] == gid {
+			return i
+		}
+		return -1
+	}
+	i := sort.Search(len(c.ranges), func(i int) bool { return c.ranges[i].end >= gid })
+	if i < len(c.ranges) && c.ranges[i].start <= gid {
+		return c.ranges[i].value + int(gid-c.ranges[i].start)
+	}
+	return -1
+}
+
+// classDef maps glyph ids to classes. Unlisted glyphs belong to class 0.
+type classDef struct {
+	start   uint16
+	classes []uint16
+	ranges  []glyphRange
+}
+
+func parseClassDef(b otlData) *classDef {
+	if b == nil {
+		return nil
+	}
+	cd := &classDef{}
+	switch b.u16(0) {
+	case 1:
+		cd.start = b.u16(2)
+		cd.classes = b.u16s(6, int(b.u16(4)))
+	case 2:
+		n := int(b.u16(2))
+		for i := 0; i < n; i++ {
+			off := 4 + 6*i
+			cd.ranges = append(cd.ranges, glyphRange{b.u16(off), b.u16(off + 2), int(b.u16(off + 4))})
+		}
+	}
+	return cd
+}
+
+func (cd *classDef) class(gid uint16) uint16 {
+	if cd == nil {
+		return 0
+	}
+	if cd.ranges == nil {
+		if gid >= cd.start && int(gid-cd.start) < len(cd.classes) {
+			return cd.classes[gid-cd.start]
+		}
+		return 0
+	}
+	i := sort.Search(len(cd.ranges), func(i int) bool { return cd.ranges[i].end >= gid })
+	if i < len(cd.ranges) && cd.ranges[i].start <= gid {
+		return uint16(cd.ranges[i].value)
+	}
+	return 0
+}
+
+type langSys struct {
+	required int // required feature index or -1
+	features []int
+}
+
+type feature struct {
+	tag     string
+	lookups []int
+}
+
+// lookup represents a GSUB or GPOS lookup table.
+type lookup struct {
+	kind      int
+	flag      uint16
+	markSet   int
+	subtables []any
+}
+
+// layoutTable represents the common structure of a GSUB or GPOS table.
+type layoutTable struct {
+	scripts  map[string]map[string]*langSys // script tag => language tag ("" for default) => language system
+	features []feature
+	lookups  []*lookup
+}
+
+func parseLangSys(b otlData) *langSys {
+	if b == nil {
+		return nil
+	}
+	ls := &langSys{required: -1}
+	if i := b.u16(2); i != 0xFFFF {
+		ls.required = int(i)
+	}
+	for _, i := range b.u16s(6, int(b.u16(4))) {
+		ls.features = append(ls.features, int(i))
+	}
+	return ls
+}
+
+func parseScriptList(b otlData) map[string]map[string]*langSys {
+	m := map[string]map[string]*langSys{}
+	n := int(b.u16(0))
+	for i := 0; i < n; i++ {
+		off := 2 + 6*i
+		s := b.at(int(b.u16(off + 4)))
+		if s == nil {
+			continue
+		}
+		langs := map[string]*langSys{}
+		if ls := parseLangSys(s.at(int(s.u16(0)))); ls != nil {
+			langs[""] = ls
+		}
+		c := int(s.u16(2))
+		for j := 0; j < c; j++ {
+			off1 := 4 + 6*j
+			if ls := parseLangSys(s.at(int(s.u16(off1 + 4)))); ls != nil {
+				langs[s.tag(off1)] = ls
+			}
+		}
+		m[b.tag(off)] = langs
+	}
+	return m
+}
+
+func parseFeatureList(b otlData) []feature {
+	n := int(b.u16(0))
+	ff := make([]feature, n)
+	for i := 0; i < n; i++ {
+		off := 2 + 6*i
+		ff[i].tag = b.tag(off)
+		f := b.at(int(b.u16(off + 4)))
+		for _, l := range f.u16s(4, int(f.u16(2))) {
+			ff[i].lookups = append(ff[i].lookups, int(l))
+		}
+	}
+	return ff
+}
+
+// parseLayoutTable parses the script, feature and lookup lists of a GSUB or GPOS table.
+// ext is the extension lookup type and parseSubtable decodes a single lookup subtable.
+func parseLayoutTable(b otlData, ext int, parseSubtable func(kind int, b otlData) any) *layoutTable {
+	if b == nil || b.u16(0) != 1 {
+		return nil
+	}
+	lt := &layoutTable{
+		scripts:  parseScriptList(b.at(int(b.u16(4)))),
+		features: parseFeatureList(b.at(int(b.u16(6)))),
+	}
+	ll := b.at(int(b.u16(8)))
+	n := int(ll.u16(0))
+	lt.lookups = make([]*lookup, n)
+	for i := 0; i < n; i++ {
+		l := ll.at(int(ll.u16(2 + 2*i)))
+		lu := &lookup{kind: int(l.u16(0)), flag: l.u16(2)}
+		c := int(l.u16(4))
+		if lu.flag&lookupUseMarkFilteringSet > 0 {
+			lu.markSet = int(l.u16(6 + 2*c))
+		}
+		for j := 0; j < c; j++ {
+			st := l.at(int(l.u16(6 + 2*j)))
+			kind := lu.kind
+			if kind == ext {
+				// Extension subtable: format, extensionLookupType, extensionOffset
+				kind = int(st.u16(2))
+				st = st.at(int(st.u32(4)))
+				lu.kind = kind
+			}
+			if st == nil {
+				continue
+			}
+			if s := parseSubtable(kind, st); s != nil {
+				lu.subtables = append(lu.subtables, s)
+			}
+		}
+		lt.lookups[i] = lu
+	}
+	return lt
+}
+
+// langSys returns the language system for script and lang.
+func (lt *layoutTable) langSys(scripts []string, lang string) *langSys {
+	if lt == nil {
+		return nil
+	}
+	tags := append(append([]string(nil), scripts...), "DFLT", "latn")
+	for _, s := range tags {
+		langs, ok := lt.scripts[s]
+		if !ok {
+			continue
+		}
+		if ls, ok := langs[lang]; ok {
+			return ls
+		}
+		if ls, ok := langs[""]; ok {
+			return ls
+		}
+	}
+	return nil
+}
+
+// featureLookups returns the lookup indices for feature tag within ls.
+func (lt *layoutTable) featureLookups(ls *langSys, tag string) []int {
+	if ls == nil {
+		return nil
+	}
+	var ll []int
+	for _, i := range ls.features {
+		if i < len(lt.features) && lt.features[i].tag == tag {
+			ll = append(ll, lt.features[i].lookups...)
+		}
+	}
+	if i := ls.required; i >= 0 && i < len(lt.features) && lt.features[i].tag == tag {
+		ll = append(ll, lt.features[i].lookups...)
+	}
+	return ll
+}
+
+// gdef represents the glyph definition table.
+type gdef struct {
+	glyphClasses      *classDef
+	markAttachClasses *classDef
+	markSets          []*coverage
+}
+
+func parseGDEF(b otlData) *gdef {
+	if b == nil {
+		return nil
+	}
+	g := &gdef{
+		glyphClasses:      parseClassDef(b.at(int(b.u16(4)))),
+		markAttachClasses: parseClassDef(b.at(int(b.u16(10)))),
+	}
+	if b.u16(2) >= 2 {
+		if ms := b.at(int(b.u16(12))); ms != nil {
+			n := int(ms.u16(2))
+			for i := 0; i < n; i++ {
+				g.markSets = append(g.markSets, parseCoverage(ms.at(int(ms.u32(4+4*i)))))
+			}
+		}
+	}
+	return g
+}
+
+// parseKern parses the horizontal format 0 subtables of a TrueType kern table (version 0)
+// into kerning values in font units keyed by left<<16|right glyph id.
+func parseKern(b otlData) map[uint32]int {
+	if b == nil || b.u16(0) != 0 {
+		return nil
+	}
+	var m map[uint32]int
+	n, off := int(b.u16(2)), 4
+	for i := 0; i < n && off < len(b); i++ {
+		st := b[off:]
+		length, cov := int(st.u16(2)), st.u16(4)
+		// Format 0, horizontal, kerning values (not minimum values), not cross stream.
+		if cov>>8 == 0 && cov&0x07 == 0x01 {
+			c := int(st.u16(6))
+			for j := 0; j < c; j++ {
+				o := 14 + 6*j
+				if o+6 > len(st) {
+					break
+				}
+				if m == nil {
+					m = map[uint32]int{}
+				}
+				m[uint32(st.u16(o))<<16|uint32(st.u16(o+2))] = int(st.i16(o + 4))
+			}
+		}
+		if length <= 0 {
+			break
+		}
+		off += length
+	}
+	return m
+}
+
+// sequenceLookup applies lookup to the glyph at position seqIndex of a matched input sequence.
+type sequenceLookup struct {
+	seqIndex, lookup int
+}
+
+func parseSequenceLookups(b otlData, off, count int) []sequenceLookup {
+	sl := make([]sequenceLookup, 0, count)
+	for i := 0; i < count; i++ {
+		sl = append(sl, sequenceLookup{int(b.u16(off + 4*i)), int(b.u16(off + 4*i + 2))})
+	}
+	return sl
+}
+
+// contextRule represents a (chained) sequence context rule.
+// Input, backtrack and lookahead hold glyph ids or classes, input excludes the first glyph.
+type contextRule struct {
+	backtrack, input, lookahead []uint16
+	lookups                     []sequenceLookup
+}
+
+// contextSubtable represents GSUB types 5, 6 and GPOS types 7, 8.
+type contextSubtable struct {
+	format                               int
+	cov                                  *coverage
+	backtrackDef, inputDef, lookaheadDef *classDef
+	ruleSets                             [][]contextRule
+	backtrackCov, inputCov, lookaheadCov []*coverage
+	lookups                              []sequenceLookup
+}
+
+func parseCoverages(b otlData, off, count int) []*coverage {
+	cc := make([]*coverage, count)
+	for i := range cc {
+		cc[i] = parseCoverage(b.at(int(b.u16(off + 2*i))))
+	}
+	return cc
+}
+
+func parseContextRuleSets(b otlData, off, count int, chained bool) [][]contextRule {
+	rss := make([][]contextRule, count)
+	for i := 0; i < count; i++ {
+		rs := b.at(int(b.u16(off + 2*i)))
+		if rs == nil {
+			continue
+		}
+		n := int(rs.u16(0))
+		for j := 0; j < n; j++ {
+			r := rs.at(int(rs.u16(2 + 2*j)))
+			if r == nil {
+				continue
+			}
+			var cr contextRule
+			if !chained {
+				c, lc := int(r.u16(0)), int(r.u16(2))
+				cr.input = r.u16s(4, c-1)
+				cr.lookups = parseSequenceLookups(r, 4+2*(c-1), lc)
+			} else {
+				o := 0
+				c := int(r.u16(o))
+				cr.backtrack = r.u16s(o+2, c)
+				o += 2 + 2*c
+				c = int(r.u16(o))
+				cr.input = r.u16s(o+2, c-1)
+				o += 2 + 2*(c-1)
+				c = int(r.u16(o))
+				cr.lookahead = r.u16s(o+2, c)
+				o += 2 + 2*c
+				cr.lookups = parseSequenceLookups(r, o+2, int(r.u16(o)))
+			}
+			rss[i] = append(rss[i], cr)
+		}
+	}
+	return rss
+}
+
+func parseContextSubtable(b otlData) any {
+	cs := &contextSubtable{format: int(b.u16(0))}
+	switch cs.format {
+	case 1:
+		cs.cov = parseCoverage(b.at(int(b.u16(2))))
+		cs.ruleSets = parseContextRuleSets(b, 6, int(b.u16(4)), false)
+	case 2:
+		cs.cov = parseCoverage(b.at(int(b.u16(2))))
+		cs.inputDef = parseClassDef(b.at(int(b.u16(4))))
+		cs.ruleSets = parseContextRuleSets(b, 8, int(b.u16(6)), false)
+	case 3:
+		c, lc := int(b.u16(2)), int(b.u16(4))
+		cs.inputCov = parseCoverages(b, 6, c)
+		cs.lookups = parseSequenceLookups(b, 6+2*c, lc)
+	default:
+		return nil
+	}
+	return cs
+}
+
+func parseChainedContextSubtable(b otlData) any {
+	cs := &contextSubtable{format: int(b.u16(0))}
+	switch cs.format {
+	case 1:
+		cs.cov = parseCoverage(b.at(int(b.u16(2))))
+		cs.ruleSets = parseContextRuleSets(b, 6, int(b.u16(4)), true)
+	case 2:
+		cs.cov = parseCoverage(b.at(int(b.u16(2))))
+		cs.backtrackDef = parseClassDef(b.at(int(b.u16(4))))
+		cs.inputDef = parseClassDef(b.at(int(b.u16(6))))
+		cs.lookaheadDef = parseClassDef(b.at(int(b.u16(8))))
+		cs.ruleSets = parseContextRuleSets(b, 12, int(b.u16(10)), true)
+	case 3:
+		o := 2
+		c := int(b.u16(o))
+		cs.backtrackCov = parseCoverages(b, o+2, c)
+		o += 2 + 2*c
+		c = int(b.u16(o))
+		cs.inputCov = parseCoverages(b, o+2, c)
+		o += 2 + 2*c
+		c = int(b.u16(o))
+		cs.lookaheadCov = parseCoverages(b, o+2, c)
+		o += 2 + 2*c
+		cs.lookups = parseSequenceLookups(b, o+2, int(b.u16(o)))
+	default:
+		return nil
+	}
+	return cs
+}
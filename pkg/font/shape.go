@@ -0,0 +1,787 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Glyph represents a shaped glyph in glyph space units (1/1000 text space units).
+type Glyph struct {
+	GID    uint16
+	Runes  []rune // The characters represented by this glyph, nil for additional glyphs of a decomposed character.
+	Width  int    // The advance width after positioning.
+	DX, DY int    // The offset from the current text position.
+}
+
+// fontLayout holds the font data needed for shaping.
+type fontLayout struct {
+	upem       int
+	advances   []int // hmtx: advance widths in font units
+	chars      map[uint32]uint16
+	gdef       *gdef
+	gsub, gpos *layoutTable
+	kern       map[uint32]int // legacy kern table: left<<16|right glyph id => kerning value in font units
+}
+
+var (
+	fontLayoutsLock sync.Mutex
+	fontLayouts     = map[string]*fontLayout{}
+)
+
+func parseFontLayout(bb []byte, fd TTFLight) *fontLayout {
+	if len(bb) < 12 {
+		return nil
+	}
+	tables, err := ttfTables(int(binary.BigEndian.Uint16(bb[4:])), bb)
+	if err != nil {
+		return nil
+	}
+	data := func(tag string) otlData {
+		if t, ok := tables[tag]; ok {
+			return otlData(t.data[:t.size])
+		}
+		return nil
+	}
+
+	l := &fontLayout{
+		upem:  fd.UnitsPerEm,
+		chars: fd.Chars,
+		gdef:  parseGDEF(data("GDEF")),
+		gsub:  parseGSUB(data("GSUB")),
+		gpos:  parseGPOS(data("GPOS")),
+		kern:  parseKern(data("kern")),
+	}
+	if l.upem == 0 {
+		l.upem = 1000
+	}
+
+	n := int(data("hhea").u16(34))
+	hmtx := data("hmtx")
+	l.advances = make([]int, fd.GlyphCount)
+	for i := range l.advances {
+		if i < n {
+			l.advances[i] = int(hmtx.u16(4 * i))
+		} else if n > 0 {
+			l.advances[i] = l.advances[n-1]
+		}
+	}
+	return l
+}
+
+// userFontLayout returns the cached shaping data for an installed user font.
+func userFontLayout(fontName string) *fontLayout {
+	fontLayoutsLock.Lock()
+	defer fontLayoutsLock.Unlock()
+
+	if l, ok := fontLayouts[fontName]; ok {
+		return l
+	}
+
+	UserFontMetricsLock.RLock()
+	fd, ok := UserFontMetrics[fontName]
+	UserFontMetricsLock.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	var l *fontLayout
+	if bb, err := Read(fontName); err == nil {
+		l = parseFontLayout(bb, fd)
+	}
+	fontLayouts[fontName] = l
+	return l
+}
+
+// glyphClass returns the GDEF glyph class of gid or def if there is no GDEF glyph class definition.
+func (l *fontLayout) glyphClass(gid uint16, def uint16) uint16 {
+	if l.gdef == nil || l.gdef.glyphClasses == nil {
+		return def
+	}
+	return l.gdef.glyphClasses.class(gid)
+}
+
+// glyphInfo represents a glyph during shaping.
+type glyphInfo struct {
+	gid       uint16
+	runes     []rune
+	mask      uint32
+	class     uint16 // GDEF glyph class
+	ligID     int    // ligature id for ligatures and marks attached to them
+	ligComp   int    // ligature component of a mark
+	compCount int    // number of ligature components
+	syllable  int
+	cat       uint8 // script specific character category
+	reph      bool
+
+	xAdv, xOff, yOff int // font units
+	attachKind       uint8
+	attach           int // offset to the glyph this glyph is attached to
+}
+
+// shaper applies OpenType layout features to a single run of text with uniform script and direction.
+type shaper struct {
+	l        *fontLayout
+	buf      []glyphInfo
+	rtl      bool
+	ligID    int
+	table    *layoutTable // current table: gsub or gpos
+	pos      bool         // positioning
+	flag     uint16       // current lookup flag
+	markSet  int          // current mark filtering set
+	depth    int          // nesting level of contextual lookups
+	features map[string]uint32
+}
+
+// ignored returns true if g is skipped by the current lookup flag.
+func (s *shaper) ignored(g *glyphInfo) bool {
+	switch g.class {
+	case glyphClassBase:
+		return s.flag&lookupIgnoreBaseGlyphs > 0
+	case glyphClassLigature:
+		return s.flag&lookupIgnoreLigatures > 0
+	case glyphClassMark:
+		if s.flag&lookupIgnoreMarks > 0 {
+			return true
+		}
+		if s.flag&lookupUseMarkFilteringSet > 0 {
+			if s.l.gdef == nil || s.markSet >= len(s.l.gdef.markSets) {
+				return true
+			}
+			return s.l.gdef.markSets[s.markSet].index(g.gid) < 0
+		}
+		if t := s.flag & lookupMarkAttachmentType >> 8; t != 0 {
+			return s.l.gdef == nil || s.l.gdef.markAttachClasses.class(g.gid) != t
+		}
+	}
+	return false
+}
+
+func (s *shaper) next(i int) int {
+	for i++; i < len(s.buf); i++ {
+		if !s.ignored(&s.buf[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *shaper) prev(i int) int {
+	for i--; i >= 0; i-- {
+		if !s.ignored(&s.buf[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *shaper) setLookup(lu *lookup) {
+	s.flag, s.markSet = lu.flag, lu.markSet
+}
+
+func (s *shaper) setGlyph(i int, gid uint16) {
+	s.buf[i].gid = gid
+	s.buf[i].class = s.l.glyphClass(gid, s.buf[i].class)
+}
+
+// matchInput matches n glyphs starting at i and returns their positions.
+func (s *shaper) matchInput(i, n int, f func(k int, gid uint16) bool) []int {
+	pos := []int{i}
+	for k, j := 1, i; k < n; k++ {
+		if j = s.next(j); j < 0 || !f(k, s.buf[j].gid) {
+			return nil
+		}
+		pos = append(pos, j)
+	}
+	return pos
+}
+
+func (s *shaper) matchBacktrack(i, n int, f func(k int, gid uint16) bool) bool {
+	for k, j := 0, i; k < n; k++ {
+		if j = s.prev(j); j < 0 || !f(k, s.buf[j].gid) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *shaper) matchLookahead(i, n int, f func(k int, gid uint16) bool) bool {
+	for k, j := 0, i; k < n; k++ {
+		if j = s.next(j); j < 0 || !f(k, s.buf[j].gid) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *shaper) matchRule(i int, r *contextRule, bt, in, la func(v uint16, gid uint16) bool) []int {
+	pos := s.matchInput(i, len(r.input)+1, func(k int, gid uint16) bool { return in(r.input[k-1], gid) })
+	if pos == nil {
+		return nil
+	}
+	if !s.matchBacktrack(i, len(r.backtrack), func(k int, gid uint16) bool { return bt(r.backtrack[k], gid) }) {
+		return nil
+	}
+	if !s.matchLookahead(pos[len(pos)-1], len(r.lookahead), func(k int, gid uint16) bool { return la(r.lookahead[k], gid) }) {
+		return nil
+	}
+	return pos
+}
+
+// matchContext returns the matched input positions and sequence lookups of cs at position i.
+func (s *shaper) matchContext(cs *contextSubtable, i int) ([]int, []sequenceLookup) {
+	gid := s.buf[i].gid
+
+	if cs.format == 3 {
+		if len(cs.inputCov) == 0 || cs.inputCov[0].index(gid) < 0 {
+			return nil, nil
+		}
+		cov := func(cc []*coverage) func(k int, gid uint16) bool {
+			return func(k int, gid uint16) bool { return cc[k].index(gid) >= 0 }
+		}
+		pos := s.matchInput(i, len(cs.inputCov), cov(cs.inputCov))
+		if pos == nil ||
+			!s.matchBacktrack(i, len(cs.backtrackCov), cov(cs.backtrackCov)) ||
+			!s.matchLookahead(pos[len(pos)-1], len(cs.lookaheadCov), cov(cs.lookaheadCov)) {
+			return nil, nil
+		}
+		return pos, cs.lookups
+	}
+
+	ci := cs.cov.index(gid)
+	if ci < 0 {
+		return nil, nil
+	}
+
+	glyph := func(v, gid uint16) bool { return v == gid }
+	bt, in, la := glyph, glyph, glyph
+	if cs.format == 2 {
+		ci = int(cs.inputDef.class(gid))
+		class := func(cd *classDef) func(v, gid uint16) bool {
+			return func(v, gid uint16) bool { return cd.class(gid) == v }
+		}
+		bt, in, la = class(cs.backtrackDef), class(cs.inputDef), class(cs.lookaheadDef)
+	}
+	if ci >= len(cs.ruleSets) {
+		return nil, nil
+	}
+	for k := range cs.ruleSets[ci] {
+		r := &cs.ruleSets[ci][k]
+		if pos := s.matchRule(i, r, bt, in, la); pos != nil {
+			return pos, r.lookups
+		}
+	}
+	return nil, nil
+}
+
+func (s *shaper) applyContext(cs *contextSubtable, i int) (int, bool) {
+	pos, lookups := s.matchContext(cs, i)
+	if pos == nil {
+		return 0, false
+	}
+	if s.depth > 8 {
+		return pos[len(pos)-1] + 1, true
+	}
+	flag, markSet := s.flag, s.markSet
+	s.depth++
+	for _, sl := range lookups {
+		if sl.seqIndex >= len(pos) || sl.lookup >= len(s.table.lookups) {
+			continue
+		}
+		p := pos[sl.seqIndex]
+		if p >= len(s.buf) {
+			continue
+		}
+		lu := s.table.lookups[sl.lookup]
+		s.setLookup(lu)
+		n := len(s.buf)
+		s.applyLookupAt(lu, p)
+		if d := len(s.buf) - n; d != 0 {
+			for k := range pos {
+				if pos[k] > p {
+					pos[k] += d
+				}
+			}
+		}
+	}
+	s.depth--
+	s.flag, s.markSet = flag, markSet
+	end := pos[len(pos)-1] + 1
+	if end > len(s.buf) {
+		end = len(s.buf)
+	}
+	if end <= i {
+		end = i + 1
+	}
+	return end, true
+}
+
+// applyLookupAt applies the first matching subtable of lu at position i.
+func (s *shaper) applyLookupAt(lu *lookup, i int) (int, bool) {
+	for _, st := range lu.subtables {
+		var next int
+		var ok bool
+		if s.pos {
+			next, ok = s.applyPosSubtable(st, i)
+		} else {
+			next, ok = s.applySubstSubtable(st, i)
+		}
+		if ok {
+			return next, true
+		}
+	}
+	return 0, false
+}
+
+// wouldSubstitute returns true if the lookups of feature substitute gids.
+func (s *shaper) wouldSubstitute(ls *langSys, feature string, gids []uint16) bool {
+	t := &shaper{l: s.l, table: s.l.gsub}
+	for _, gid := range gids {
+		t.buf = append(t.buf, glyphInfo{gid: gid, mask: 1, class: s.l.glyphClass(gid, glyphClassBase)})
+	}
+	for _, li := range s.l.gsub.featureLookups(ls, feature) {
+		if li >= len(s.l.gsub.lookups) {
+			continue
+		}
+		t.substitute(s.l.gsub.lookups[li], 1)
+		if len(t.buf) != len(gids) {
+			return true
+		}
+		for i, gid := range gids {
+			if t.buf[i].gid != gid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stage is a set of features whose lookups are applied together in lookup order.
+type stage []string
+
+// applyStages applies the lookups of all features in stages to the glyph buffer.
+func (s *shaper) applyStages(lt *layoutTable, ls *langSys, stages []stage, pos bool) {
+	if lt == nil || ls == nil {
+		return
+	}
+	s.table, s.pos = lt, pos
+	for _, st := range stages {
+		masks := map[int]uint32{}
+		var order []int
+		for _, f := range st {
+			m, ok := s.features[f]
+			if !ok {
+				continue
+			}
+			for _, li := range lt.featureLookups(ls, f) {
+				if li >= len(lt.lookups) {
+					continue
+				}
+				if _, ok := masks[li]; !ok {
+					order = append(order, li)
+				}
+				masks[li] |= m
+			}
+		}
+		sortInts(order)
+		for _, li := range order {
+			if pos {
+				s.position(lt.lookups[li], masks[li])
+			} else {
+				s.substitute(lt.lookups[li], masks[li])
+			}
+		}
+	}
+}
+
+func sortInts(ii []int) {
+	for a := 1; a < len(ii); a++ {
+		for b := a; b > 0 && ii[b] < ii[b-1]; b-- {
+			ii[b], ii[b-1] = ii[b-1], ii[b]
+		}
+	}
+}
+
+// Feature mask bits. Global features are enabled for all glyphs.
+const maskGlobal = 1
+
+// script identifies the shaping model of a text run.
+type script int
+
+const (
+	scriptCommon script = iota
+	scriptInherited
+	scriptOther
+	scriptArabic
+	scriptHebrew
+	scriptIndic // Indic scripts are further identified by their Unicode block.
+)
+
+func runeScript(r rune) (script, rune) {
+	switch {
+	case r >= 0x0600 && r <= 0x06FF, r >= 0x0750 && r <= 0x077F, r >= 0x08A0 && r <= 0x08FF,
+		r >= 0xFB50 && r <= 0xFDFF, r >= 0xFE70 && r <= 0xFEFF:
+		if unicode.Is(unicode.Mn, r) {
+			return scriptInherited, 0
+		}
+		if r >= 0x0660 && r <= 0x0669 || r == 0x060C || r == 0x061B || r == 0x061F || r == 0x0640 {
+			// Arabic digits and punctuation are shared with other scripts.
+			return scriptCommon, 0
+		}
+		return scriptArabic, 0
+	case r >= 0x0590 && r <= 0x05FF, r >= 0xFB1D && r <= 0xFB4F:
+		return scriptHebrew, 0
+	case r >= 0x0900 && r <= 0x0D7F:
+		if r == 0x0964 || r == 0x0965 {
+			// Danda and double danda
+			return scriptCommon, 0
+		}
+		return scriptIndic, r &^ 0x7F
+	case r == 0x200C || r == 0x200D:
+		return scriptInherited, 0
+	case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+		return scriptInherited, 0
+	case unicode.IsLetter(r):
+		return scriptOther, 0
+	}
+	return scriptCommon, 0
+}
+
+// NeedsShaping returns true if s contains right-to-left or complex script characters.
+func NeedsShaping(s string) bool {
+	for _, r := range s {
+		switch bidiClass(r) {
+		case bidi.R, bidi.AL, bidi.AN, bidi.RLE, bidi.RLO, bidi.RLI, bidi.LRE, bidi.LRO, bidi.LRI, bidi.FSI:
+			return true
+		}
+		if sc, _ := runeScript(r); sc >= scriptArabic {
+			return true
+		}
+	}
+	return false
+}
+
+// textRun is a sequence of characters with uniform embedding level and script.
+type textRun struct {
+	from, to int
+	level    uint8
+	script   script
+	block    rune
+}
+
+// itemize splits rr into runs of uniform bidi level and script in logical order.
+func itemize(rr []rune, levels []uint8) []textRun {
+	var runs []textRun
+	cur := textRun{script: scriptCommon}
+	for i, r := range rr {
+		sc, block := runeScript(r)
+		if i > 0 && levels[i] != cur.level {
+			cur.to = i
+			runs = append(runs, cur)
+			cur = textRun{from: i, script: scriptCommon}
+		}
+		cur.level = levels[i]
+		if sc == scriptCommon || sc == scriptInherited {
+			continue
+		}
+		if cur.script == scriptCommon {
+			cur.script, cur.block = sc, block
+			continue
+		}
+		if sc != cur.script || block != cur.block {
+			// Split before any preceding marks belong to the new script.
+			cur.to = i
+			runs = append(runs, cur)
+			cur = textRun{from: i, level: levels[i], script: sc, block: block}
+		}
+	}
+	cur.to = len(rr)
+	if cur.to > cur.from {
+		runs = append(runs, cur)
+	}
+	return runs
+}
+
+// visualRuns returns runs in visual order.
+func visualRuns(runs []textRun) []textRun {
+	levels := make([]uint8, len(runs))
+	for i, r := range runs {
+		levels[i] = r.level
+	}
+	vr := make([]textRun, len(runs))
+	for i, j := range visualOrder(levels) {
+		vr[i] = runs[j]
+	}
+	return vr
+}
+
+func reverseGlyphs(gg []Glyph) {
+	for i, j := 0, len(gg)-1; i < j; i, j = i+1, j-1 {
+		gg[i], gg[j] = gg[j], gg[i]
+	}
+}
+
+// plainGlyphs maps the runes of a run to glyphs in visual order without applying layout features.
+func plainGlyphs(fd TTFLight, rr []rune, rtl bool) []Glyph {
+	gg := make([]Glyph, 0, len(rr))
+	for _, r := range rr {
+		if rtl {
+			r = mirror(r)
+		}
+		gid, ok := fd.Chars[uint32(r)]
+		if !ok {
+			continue
+		}
+		var w int
+		if int(gid) < len(fd.GlyphWidths) {
+			w = fd.GlyphWidths[gid]
+		}
+		gg = append(gg, Glyph{GID: gid, Runes: []rune{r}, Width: w})
+	}
+	if rtl {
+		reverseGlyphs(gg)
+	}
+	return gg
+}
+
+var (
+	defaultGSUBStages = []stage{{"ccmp", "locl"}, {"rlig"}, {"calt", "clig", "liga", "rclt"}}
+	defaultGPOSStages = []stage{{"curs", "kern", "mark", "mkmk", "dist", "abvm", "blwm"}}
+)
+
+// enableFeatures enables the given features for all glyphs.
+func (s *shaper) enableFeatures(stages ...[]stage) {
+	for _, ss := range stages {
+		for _, st := range ss {
+			for _, f := range st {
+				if _, ok := s.features[f]; !ok {
+					s.features[f] = maskGlobal
+				}
+			}
+		}
+	}
+}
+
+// removeMissingGlyphs drops characters not covered by the font.
+func (s *shaper) removeMissingGlyphs() {
+	buf := s.buf[:0]
+	for _, g := range s.buf {
+		if g.gid != 0 {
+			buf = append(buf, g)
+		}
+	}
+	s.buf = buf
+}
+
+// zeroMarkWidths zeroes the advance widths of marks after positioning.
+func (s *shaper) zeroMarkWidths() {
+	for i := range s.buf {
+		if g := &s.buf[i]; g.class == glyphClassMark {
+			if !s.rtl {
+				g.xOff -= g.xAdv
+			}
+			g.xAdv = 0
+		}
+	}
+}
+
+func (s *shaper) shapeDefault(scripts []string) {
+	s.removeMissingGlyphs()
+	s.enableFeatures(defaultGSUBStages, defaultGPOSStages)
+	s.applyStages(s.l.gsub, s.l.gsub.langSys(scripts, ""), defaultGSUBStages, false)
+	s.initAdvances()
+	s.applyStages(s.l.gpos, s.l.gpos.langSys(scripts, ""), defaultGPOSStages, true)
+	s.zeroMarkWidths()
+}
+
+func (s *shaper) initAdvances() {
+	for i := range s.buf {
+		g := &s.buf[i]
+		g.xAdv, g.xOff, g.yOff = 0, 0, 0
+		if int(g.gid) < len(s.l.advances) {
+			g.xAdv = s.l.advances[g.gid]
+		}
+	}
+}
+
+var openTypeScripts = []struct {
+	tab *unicode.RangeTable
+	tag string
+}{
+	{unicode.Latin, "latn"},
+	{unicode.Greek, "grek"},
+	{unicode.Cyrillic, "cyrl"},
+	{unicode.Armenian, "armn"},
+	{unicode.Georgian, "geor"},
+}
+
+// scriptTags returns the OpenType script tag for the first letter of rr with a known script.
+func scriptTags(rr []rune) []string {
+	for _, r := range rr {
+		for _, sc := range openTypeScripts {
+			if unicode.Is(sc.tab, r) {
+				return []string{sc.tag}
+			}
+		}
+	}
+	return nil
+}
+
+// toGlyphSpace converts font units into glyph space units.
+func (l *fontLayout) toGlyphSpace(i int) int {
+	if i < 0 {
+		return -((-i*1000 + l.upem/2) / l.upem)
+	}
+	return (i*1000 + l.upem/2) / l.upem
+}
+
+// shape shapes a single run using the font's OpenType layout tables and returns glyphs in visual order.
+func (l *fontLayout) shape(fd TTFLight, run textRun, rr []rune) []Glyph {
+	rtl := run.level&1 == 1
+	s := &shaper{l: l, rtl: rtl, features: map[string]uint32{}}
+	for _, r := range rr {
+		if rtl {
+			r = mirror(r)
+		}
+		class := uint16(glyphClassBase)
+		if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) {
+			class = glyphClassMark
+		}
+		gid := l.chars[uint32(r)]
+		s.buf = append(s.buf, glyphInfo{gid: gid, runes: []rune{r}, mask: maskGlobal, class: l.glyphClass(gid, class)})
+	}
+
+	switch run.script {
+	case scriptArabic:
+		s.shapeArabic()
+	case scriptIndic:
+		s.shapeIndic(run.block)
+	case scriptHebrew:
+		s.shapeDefault([]string{"hebr"})
+	default:
+		s.shapeDefault(scriptTags(rr))
+	}
+	s.propagateAttachments()
+
+	gg := make([]Glyph, len(s.buf))
+	for i, g := range s.buf {
+		w := l.toGlyphSpace(g.xAdv)
+		if int(g.gid) < len(l.advances) && g.xAdv == l.advances[g.gid] && int(g.gid) < len(fd.GlyphWidths) {
+			w = fd.GlyphWidths[g.gid]
+		}
+		gg[i] = Glyph{GID: g.gid, Runes: g.runes, Width: w, DX: l.toGlyphSpace(g.xOff), DY: l.toGlyphSpace(g.yOff)}
+	}
+	if rtl {
+		reverseGlyphs(gg)
+	}
+	return gg
+}
+
+// hasGPOSKerning returns true if the font's GPOS table provides a kern feature.
+func (l *fontLayout) hasGPOSKerning() bool {
+	if l.gpos == nil {
+		return false
+	}
+	for _, f := range l.gpos.features {
+		if f.tag == "kern" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyKernTable applies the legacy kern table to glyphs in visual order.
+func (l *fontLayout) applyKernTable(gg []Glyph) {
+	prev := -1
+	for i, g := range gg {
+		if g.Width == 0 {
+			// Skip marks.
+			continue
+		}
+		if prev >= 0 {
+			if v, ok := l.kern[uint32(gg[prev].GID)<<16|uint32(g.GID)]; ok {
+				gg[prev].Width += l.toGlyphSpace(v)
+			}
+		}
+		prev = i
+	}
+}
+
+// Shape returns the glyphs of user font fontName for s in visual order.
+// Bidirectional text is reordered according to the Unicode Bidirectional Algorithm (UAX #9)
+// using a right-to-left paragraph direction for rtl or the direction of the first strong character otherwise.
+// Arabic, Hebrew and Indic text runs are shaped using the font's OpenType GSUB and GPOS features.
+// Characters not covered by the font are skipped.
+func Shape(fontName, s string, rtl bool) []Glyph {
+	return shape(fontName, s, rtl, false)
+}
+
+// ShapeKerned works like Shape but also applies standard ligatures and kerning to all text runs.
+// Fonts without a GPOS kern feature are kerned using their kern table.
+func ShapeKerned(fontName, s string, rtl bool) []Glyph {
+	return shape(fontName, s, rtl, true)
+}
+
+func shape(fontName, s string, rtl, kern bool) []Glyph {
+	EnsureUserFontsLoaded()
+	UserFontMetricsLock.RLock()
+	fd, ok := UserFontMetrics[fontName]
+	UserFontMetricsLock.RUnlock()
+	if !ok || s == "" {
+		return nil
+	}
+
+	rr := []rune(s)
+	var gg []Glyph
+	for _, run := range visualRuns(itemize(rr, bidiLevels(rr, rtl))) {
+		var l *fontLayout
+		if kern || run.script >= scriptArabic {
+			l = userFontLayout(fontName)
+		}
+		var gg1 []Glyph
+		if l != nil && (l.gsub != nil || l.gpos != nil) {
+			gg1 = l.shape(fd, run, rr[run.from:run.to])
+		} else {
+			gg1 = plainGlyphs(fd, rr[run.from:run.to], run.level&1 == 1)
+		}
+		if kern && l != nil && l.kern != nil && !l.hasGPOSKerning() {
+			l.applyKernTable(gg1)
+		}
+		gg = append(gg, gg1...)
+	}
+	return gg
+}
+
+// VisualOrder reorders s according to the Unicode Bidirectional Algorithm (UAX #9) including mirroring
+// using a right-to-left paragraph direction for rtl or the direction of the first strong character otherwise.
+func VisualOrder(s string, rtl bool) string {
+	rr := []rune(s)
+	levels := bidiLevels(rr, rtl)
+	vv := make([]rune, 0, len(rr))
+	for _, i := range visualOrder(levels) {
+		r := rr[i]
+		if levels[i]&1 == 1 {
+			r = mirror(r)
+		}
+		vv = append(vv, r)
+	}
+	return string(vv)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const shapingFont = "DejaVuSans"
+
+func installShapingFont(t *testing.T) TTFLight {
+	t.Helper()
+
+	if UserFontDir == "" {
+		dir, err := os.MkdirTemp("", "pdfcpu-fonts")
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		UserFontDir = dir
+		if err := InstallTrueTypeFont(dir, filepath.Join("..", "testdata", "fonts", shapingFont+".ttf")); err != nil {
+			t.Fatalf("install %s: %v\n", shapingFont, err)
+		}
+	}
+	if err := LoadUserFonts(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	return UserFontMetrics[shapingFont]
+}
+
+func TestVisualOrder(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		rtl  bool
+		want string
+	}{
+		{"abc", false, "abc"},
+		{"abc", true, "abc"},
+		{"אבג", false, "גבא"},
+		{"abc אבג def", false, "abc גבא def"},
+		{"אבג (abc)", false, "(abc) גבא"},
+		{"123 אבג", false, "גבא 123"},
+		{"abc 123", true, "abc 123"},
+		{"abc אבג", true, "גבא abc"},
+		{"سلام ١٢٣", false, "١٢٣ مالس"},
+	} {
+		if got := VisualOrder(tt.s, tt.rtl); got != tt.want {
+			t.Errorf("VisualOrder(%q, %t): want %q got %q\n", tt.s, tt.rtl, tt.want, got)
+		}
+	}
+}
+
+func TestNeedsShaping(t *testing.T) {
+	for s, want := range map[string]bool{
+		"Hello, world": false,
+		"Grüße":        false,
+		"سلام":         true,
+		"שלום":         true,
+		"नमस्ते":       true,
+	} {
+		if got := NeedsShaping(s); got != want {
+			t.Errorf("NeedsShaping(%q): want %t got %t\n", s, want, got)
+		}
+	}
+}
+
+func TestShapeArabic(t *testing.T) {
+	fd := installShapingFont(t)
+
+	// seen (initial), lam alef (final ligature), meem (isolated) in visual order.
+	gg := Shape(shapingFont, "سلام", false)
+	if len(gg) != 3 {
+		t.Fatalf("want 3 glyphs, got %d: %v\n", len(gg), gg)
+	}
+	if gg[0].GID != fd.Chars['م'] {
+		t.Errorf("meem: want isolated form %d, got %d\n", fd.Chars['م'], gg[0].GID)
+	}
+	if gg[1].GID == fd.Chars['ل'] || string(gg[1].Runes) != "لا" {
+		t.Errorf("lam alef: want ligature, got %d %q\n", gg[1].GID, string(gg[1].Runes))
+	}
+	if gg[2].GID == fd.Chars['س'] || string(gg[2].Runes) != "س" {
+		t.Errorf("seen: want initial form, got %d %q\n", gg[2].GID, string(gg[2].Runes))
+	}
+
+	// Embedded Latin keeps its logical order and plain glyphs.
+	gg = Shape(shapingFont, "abc سلام", false)
+	if len(gg) != 7 || gg[0].GID != fd.Chars['a'] || gg[0].Width != fd.GlyphWidths[fd.Chars['a']] {
+		t.Errorf("mixed text: unexpected glyphs %v\n", gg)
+	}
+}
+
+func TestShapeHebrewMarks(t *testing.T) {
+	installShapingFont(t)
+
+	gg := Shape(shapingFont, "שָׁ", false)
+	if len(gg) != 3 {
+		t.Fatalf("want 3 glyphs, got %d: %v\n", len(gg), gg)
+	}
+	// Marks precede their base in visual order and do not advance.
+	if string(gg[2].Runes) != "ש" {
+		t.Errorf("want base last, got %q\n", string(gg[2].Runes))
+	}
+	for _, g := range gg[:2] {
+		if g.Width != 0 {
+			t.Errorf("mark %q: want zero width, got %d\n", string(g.Runes), g.Width)
+		}
+	}
+}
+
+func TestShapeIndicReordering(t *testing.T) {
+	// A font without layout tables still gets its pre-base matras reordered.
+	l := &fontLayout{upem: 1000, advances: []int{0, 500, 300, 400, 200}, chars: map[uint32]uint16{0x0915: 1, 0x093F: 2, 0x0937: 3, 0x094D: 4}}
+
+	// KA I
+	gg := l.shape(TTFLight{}, textRun{to: 2, script: scriptIndic, block: 0x0900}, []rune{0x0915, 0x093F})
+	if len(gg) != 2 || gg[0].GID != 2 || gg[1].GID != 1 {
+		t.Errorf("KA I: want matra first, got %v\n", gg)
+	}
+
+	// KA HALANT SSA I
+	gg = l.shape(TTFLight{}, textRun{to: 4, script: scriptIndic, block: 0x0900}, []rune{0x0915, 0x094D, 0x0937, 0x093F})
+	want := []uint16{2, 1, 4, 3}
+	for i, g := range gg {
+		if i >= len(want) || g.GID != want[i] {
+			t.Fatalf("KA HALANT SSA I: want %v, got %v\n", want, gg)
+		}
+	}
+}
@@ -0,0 +1,754 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/andybalholm/brotli"
+	"github.com/pkg/errors"
+)
+
+// Web Open Font Format support.
+// See https://www.w3.org/TR/WOFF and https://www.w3.org/TR/WOFF2
+
+const (
+	woffSignature  = "wOFF"
+	woff2Signature = "wOF2"
+)
+
+// maxWebFontSize limits the size of decoded web fonts.
+const maxWebFontSize = 128 << 20
+
+var errCorruptWOFF = errors.New("pdfcpu: corrupt WOFF font")
+
+// woff2KnownTags are the table tags addressed by the 6 bit tag index of a WOFF2 table directory entry.
+var woff2KnownTags = []string{
+	"cmap", "head", "hhea", "hmtx", "maxp", "name", "OS/2", "post",
+	"cvt ", "fpgm", "glyf", "loca", "prep", "CFF ", "VORG", "EBDT",
+	"EBLC", "gasp", "hdmx", "kern", "LTSH", "PCLT", "VDMX", "vhea",
+	"vmtx", "BASE", "GDEF", "GPOS", "GSUB", "EBSC", "JSTF", "MATH",
+	"CBDT", "CBLC", "COLR", "CPAL", "SVG ", "sbix", "acnt", "avar",
+	"bdat", "bloc", "bsln", "cvar", "fdsc", "feat", "fmtx", "fvar",
+	"gvar", "hsty", "just", "lcar", "mort", "morx", "opbd", "prop",
+	"trak", "Zapf", "Silf", "Glat", "Gloc", "Feat", "Sill",
+}
+
+// isWebFont returns true for WOFF or WOFF2 encoded font data.
+func isWebFont(bb []byte) bool {
+	if len(bb) < 4 {
+		return false
+	}
+	s := string(bb[:4])
+	return s == woffSignature || s == woff2Signature
+}
+
+// decodeWebFont decodes WOFF or WOFF2 encoded font data into sfnt.
+func decodeWebFont(fontName string, bb []byte) ([]byte, error) {
+	var err error
+	if string(bb[:4]) == woffSignature {
+		bb, err = decodeWOFF(bb)
+	} else {
+		bb, err = decodeWOFF2(bb)
+	}
+	if err != nil {
+		return nil, errors.Errorf("%v: %s", err, fontName)
+	}
+	return bb, nil
+}
+
+// sfntHeader returns the offset table for an sfnt with flavor and numTables.
+func sfntHeader(flavor uint32, numTables int) []byte {
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= numTables {
+		entrySelector++
+	}
+	searchRange := (1 << entrySelector) * 16
+
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header, flavor)
+	binary.BigEndian.PutUint16(header[4:], uint16(numTables))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(numTables*16-searchRange))
+	return header
+}
+
+func newTable(tag string, bb []byte) *table {
+	// bb may be part of a larger buffer.
+	data := pad(append([]byte(nil), bb...))
+	return &table{chksum: calcTableChecksum(tag, data), size: uint32(len(bb)), padded: uint32(len(data)), data: data}
+}
+
+func decodeWOFF(bb []byte) ([]byte, error) {
+	if len(bb) < 44 {
+		return nil, errCorruptWOFF
+	}
+
+	flavor := binary.BigEndian.Uint32(bb[4:])
+	numTables := int(binary.BigEndian.Uint16(bb[12:]))
+	totalSfntSize := uint64(binary.BigEndian.Uint32(bb[16:]))
+	if len(bb) < 44+numTables*20 || totalSfntSize > maxWebFontSize {
+		return nil, errCorruptWOFF
+	}
+
+	tables := map[string]*table{}
+	var total uint64
+
+	for i := 0; i < numTables; i++ {
+		b := bb[44+i*20:]
+		tag := string(b[:4])
+		off := binary.BigEndian.Uint32(b[4:])
+		compLength := binary.BigEndian.Uint32(b[8:])
+		origLength := binary.BigEndian.Uint32(b[12:])
+		if uint64(off)+uint64(compLength) > uint64(len(bb)) || compLength > origLength {
+			return nil, errCorruptWOFF
+		}
+
+		// Decoded tables have to fit into the sfnt.
+		if total += uint64(origLength); total > totalSfntSize {
+			return nil, errCorruptWOFF
+		}
+
+		data := bb[off : off+compLength]
+		if compLength < origLength {
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			data = make([]byte, origLength)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, errCorruptWOFF
+			}
+		}
+
+		tables[tag] = newTable(tag, data)
+	}
+
+	return createTTF(sfntHeader(flavor, numTables), tables)
+}
+
+// readUIntBase128 reads a WOFF2 UIntBase128 value.
+func readUIntBase128(r *bytes.Reader) (uint32, error) {
+	var v uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, errCorruptWOFF
+		}
+		// No leading zeros.
+		if i == 0 && b == 0x80 {
+			return 0, errCorruptWOFF
+		}
+		// No overflow.
+		if v&0xFE000000 != 0 {
+			return 0, errCorruptWOFF
+		}
+		v = v<<7 | uint32(b&0x7F)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, errCorruptWOFF
+}
+
+// read255UInt16 reads a WOFF2 255UInt16 value.
+func read255UInt16(r *bytes.Reader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, errCorruptWOFF
+	}
+	switch b {
+	case 253:
+		var v uint16
+		if err := binary.Read(r, binary.BigEndian, &v); err != nil {
+			return 0, errCorruptWOFF
+		}
+		return int(v), nil
+	case 254, 255:
+		b1, err := r.ReadByte()
+		if err != nil {
+			return 0, errCorruptWOFF
+		}
+		if b == 255 {
+			return 253 + int(b1), nil
+		}
+		return 506 + int(b1), nil
+	}
+	return int(b), nil
+}
+
+type woff2Table struct {
+	tag             string
+	origLength      uint32
+	transformLength uint32
+	transformed     bool
+}
+
+func woff2TableDirectory(r *bytes.Reader, numTables int) ([]woff2Table, error) {
+	tt := make([]woff2Table, numTables)
+
+	for i := range tt {
+		flags, err := r.ReadByte()
+		if err != nil {
+			return nil, errCorruptWOFF
+		}
+
+		t := &tt[i]
+
+		if j := int(flags & 0x3F); j < len(woff2KnownTags) {
+			t.tag = woff2KnownTags[j]
+		} else if j == 0x3F {
+			tag := make([]byte, 4)
+			if _, err := io.ReadFull(r, tag); err != nil {
+				return nil, errCorruptWOFF
+			}
+			t.tag = string(tag)
+		} else {
+			return nil, errCorruptWOFF
+		}
+
+		if t.origLength, err = readUIntBase128(r); err != nil {
+			return nil, err
+		}
+		t.transformLength = t.origLength
+
+		// Transformation version 0 is the default transform for glyf and loca,
+		// for all other tables it is the null transform.
+		version := flags >> 6
+		if t.tag == "glyf" || t.tag == "loca" {
+			t.transformed = version == 0
+		} else {
+			t.transformed = version != 0
+		}
+
+		if t.transformed {
+			if t.transformLength, err = readUIntBase128(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tt, nil
+}
+
+func decodeWOFF2(bb []byte) ([]byte, error) {
+	if len(bb) < 48 {
+		return nil, errCorruptWOFF
+	}
+
+	flavor := binary.BigEndian.Uint32(bb[4:])
+	if string(bb[4:8]) == ttcTag {
+		return nil, errors.New("pdfcpu: WOFF2 font collections unsupported")
+	}
+	numTables := int(binary.BigEndian.Uint16(bb[12:]))
+	totalCompressedSize := binary.BigEndian.Uint32(bb[20:])
+
+	r := bytes.NewReader(bb[48:])
+	tt, err := woff2TableDirectory(r, numTables)
+	if err != nil {
+		return nil, err
+	}
+
+	off := len(bb) - r.Len()
+	if uint64(off)+uint64(totalCompressedSize) > uint64(len(bb)) {
+		return nil, errCorruptWOFF
+	}
+
+	// The decompressed stream consists of all tables in their transformed form.
+	var size uint64
+	for _, t := range tt {
+		size += uint64(t.transformLength)
+	}
+	if size > maxWebFontSize {
+		return nil, errCorruptWOFF
+	}
+
+	br := brotli.NewReader(bytes.NewReader(bb[off : off+int(totalCompressedSize)]))
+	data, err := io.ReadAll(io.LimitReader(br, int64(size)))
+	if err != nil {
+		return nil, errCorruptWOFF
+	}
+
+	m := map[string][]byte{}
+	var transformed []woff2Table
+	off = 0
+	for _, t := range tt {
+		if uint64(off)+uint64(t.transformLength) > uint64(len(data)) {
+			return nil, errCorruptWOFF
+		}
+		m[t.tag] = data[off : off+int(t.transformLength)]
+		off += int(t.transformLength)
+		if t.transformed {
+			transformed = append(transformed, t)
+		}
+	}
+
+	// glyf and loca need to be reconstructed ahead of hmtx.
+	sort.SliceStable(transformed, func(i, j int) bool {
+		return transformed[i].tag != "hmtx" && transformed[j].tag == "hmtx"
+	})
+
+	var xMins []int16
+
+	for _, t := range transformed {
+		switch t.tag {
+		case "glyf":
+			glyf, loca, xm, err := reconstructGlyf(m["glyf"])
+			if err != nil {
+				return nil, err
+			}
+			m["glyf"], m["loca"], xMins = glyf, loca, xm
+		case "loca":
+			// Reconstructed along with glyf.
+		case "hmtx":
+			hmtx, err := reconstructHmtx(m["hmtx"], m["hhea"], xMins)
+			if err != nil {
+				return nil, err
+			}
+			m["hmtx"] = hmtx
+		default:
+			return nil, errors.Errorf("pdfcpu: unsupported WOFF2 transform for table %s", t.tag)
+		}
+	}
+
+	tables := map[string]*table{}
+	for tag, bb := range m {
+		tables[tag] = newTable(tag, bb)
+	}
+
+	return createTTF(sfntHeader(flavor, len(tables)), tables)
+}
+
+// woff2GlyfStreams represents the substreams of a transformed glyf table.
+type woff2GlyfStreams struct {
+	nContour, nPoints, flag, glyph, composite, bbox, instruction *bytes.Reader
+	bboxBitmap, overlapBitmap                                    []byte
+}
+
+type point struct {
+	x, y    int
+	onCurve bool
+}
+
+func withSign(flag byte, v int) int {
+	if flag&1 > 0 {
+		return v
+	}
+	return -v
+}
+
+// decodeTriplets decodes the coordinates of n points using the triplet encoding.
+func (s *woff2GlyfStreams) decodeTriplets(n int) ([]point, error) {
+	pp := make([]point, n)
+	x, y := 0, 0
+
+	for i := range pp {
+		flag, err := s.flag.ReadByte()
+		if err != nil {
+			return nil, errCorruptWOFF
+		}
+		onCurve := flag>>7 == 0
+		flag &= 0x7F
+
+		l := 4
+		switch {
+		case flag < 84:
+			l = 1
+		case flag < 120:
+			l = 2
+		case flag < 124:
+			l = 3
+		}
+		b := make([]byte, l)
+		if _, err := io.ReadFull(s.glyph, b); err != nil {
+			return nil, errCorruptWOFF
+		}
+
+		var dx, dy int
+		switch {
+		case flag < 10:
+			dy = withSign(flag, int(flag&14)<<7+int(b[0]))
+		case flag < 20:
+			dx = withSign(flag, int((flag-10)&14)<<7+int(b[0]))
+		case flag < 84:
+			b0, b1 := int(flag-20), int(b[0])
+			dx = withSign(flag, 1+b0&0x30+b1>>4)
+			dy = withSign(flag>>1, 1+(b0&0x0C)<<2+b1&0x0F)
+		case flag < 120:
+			b0 := int(flag - 84)
+			dx = withSign(flag, 1+(b0/12)<<8+int(b[0]))
+			dy = withSign(flag>>1, 1+((b0%12)>>2)<<8+int(b[1]))
+		case flag < 124:
+			dx = withSign(flag, int(b[0])<<4+int(b[1])>>4)
+			dy = withSign(flag>>1, int(b[1]&0x0F)<<8+int(b[2]))
+		default:
+			dx = withSign(flag, int(b[0])<<8+int(b[1]))
+			dy = withSign(flag>>1, int(b[2])<<8+int(b[3]))
+		}
+
+		x += dx
+		y += dy
+		pp[i] = point{x: x, y: y, onCurve: onCurve}
+	}
+
+	return pp, nil
+}
+
+func (s *woff2GlyfStreams) instructions() ([]byte, error) {
+	l, err := read255UInt16(s.glyph)
+	if err != nil {
+		return nil, err
+	}
+	bb := make([]byte, l)
+	if _, err := io.ReadFull(s.instruction, bb); err != nil {
+		return nil, errCorruptWOFF
+	}
+	return bb, nil
+}
+
+func (s *woff2GlyfStreams) explicitBBox(gid int) ([]int16, error) {
+	if s.bboxBitmap[gid>>3]&(0x80>>(gid&7)) == 0 {
+		return nil, nil
+	}
+	bbox := make([]int16, 4)
+	if err := binary.Read(s.bbox, binary.BigEndian, bbox); err != nil {
+		return nil, errCorruptWOFF
+	}
+	return bbox, nil
+}
+
+func (s *woff2GlyfStreams) simpleGlyph(gid, nContours int) ([]byte, error) {
+	endPts := make([]int, nContours)
+	nPoints := 0
+	for i := range endPts {
+		n, err := read255UInt16(s.nPoints)
+		if err != nil {
+			return nil, err
+		}
+		nPoints += n
+		endPts[i] = nPoints - 1
+	}
+
+	// Each point comes with a flag.
+	if nPoints > s.flag.Len() {
+		return nil, errCorruptWOFF
+	}
+
+	pp, err := s.decodeTriplets(nPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := s.instructions()
+	if err != nil {
+		return nil, err
+	}
+
+	bbox, err := s.explicitBBox(gid)
+	if err != nil {
+		return nil, err
+	}
+	if bbox == nil {
+		bbox = make([]int16, 4)
+		for i, p := range pp {
+			x, y := int16(p.x), int16(p.y)
+			if i == 0 || x < bbox[0] {
+				bbox[0] = x
+			}
+			if i == 0 || y < bbox[1] {
+				bbox[1] = y
+			}
+			if i == 0 || x > bbox[2] {
+				bbox[2] = x
+			}
+			if i == 0 || y > bbox[3] {
+				bbox[3] = y
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, int16(nContours))
+	binary.Write(&buf, binary.BigEndian, bbox)
+	for _, i := range endPts {
+		binary.Write(&buf, binary.BigEndian, uint16(i))
+	}
+	binary.Write(&buf, binary.BigEndian, uint16(len(instructions)))
+	buf.Write(instructions)
+
+	overlap := s.overlapBitmap != nil && s.overlapBitmap[gid>>3]&(0x80>>(gid&7)) > 0
+
+	var xs, ys bytes.Buffer
+	x, y := 0, 0
+	for i, p := range pp {
+		var flag byte
+		if p.onCurve {
+			flag |= 0x01
+		}
+		if i == 0 && overlap {
+			flag |= 0x40
+		}
+
+		dx, dy := p.x-x, p.y-y
+		x, y = p.x, p.y
+
+		switch {
+		case dx == 0:
+			// x is same
+			flag |= 0x10
+		case dx > -256 && dx < 256:
+			flag |= 0x02
+			if dx > 0 {
+				flag |= 0x10
+			} else {
+				dx = -dx
+			}
+			xs.WriteByte(byte(dx))
+		default:
+			binary.Write(&xs, binary.BigEndian, int16(dx))
+		}
+
+		switch {
+		case dy == 0:
+			// y is same
+			flag |= 0x20
+		case dy > -256 && dy < 256:
+			flag |= 0x04
+			if dy > 0 {
+				flag |= 0x20
+			} else {
+				dy = -dy
+			}
+			ys.WriteByte(byte(dy))
+		default:
+			binary.Write(&ys, binary.BigEndian, int16(dy))
+		}
+
+		buf.WriteByte(flag)
+	}
+	buf.Write(xs.Bytes())
+	buf.Write(ys.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func (s *woff2GlyfStreams) compositeGlyph(gid int) ([]byte, error) {
+	bbox, err := s.explicitBBox(gid)
+	if err != nil {
+		return nil, err
+	}
+	if bbox == nil {
+		// Composite glyphs need an explicit bounding box.
+		return nil, errCorruptWOFF
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, int16(-1))
+	binary.Write(&buf, binary.BigEndian, bbox)
+
+	haveInstructions := false
+	for more := true; more; {
+		var flags uint16
+		if err := binary.Read(s.composite, binary.BigEndian, &flags); err != nil {
+			return nil, errCorruptWOFF
+		}
+		more = flags&0x0020 > 0
+		if flags&0x0100 > 0 {
+			haveInstructions = true
+		}
+
+		// glyphIndex and arguments
+		n := 2 + 2
+		if flags&0x0001 > 0 {
+			n += 2
+		}
+
+		// transformation
+		switch {
+		case flags&0x0008 > 0:
+			n += 2
+		case flags&0x0040 > 0:
+			n += 4
+		case flags&0x0080 > 0:
+			n += 8
+		}
+
+		b := make([]byte, n)
+		if _, err := io.ReadFull(s.composite, b); err != nil {
+			return nil, errCorruptWOFF
+		}
+		binary.Write(&buf, binary.BigEndian, flags)
+		buf.Write(b)
+	}
+
+	if haveInstructions {
+		instructions, err := s.instructions()
+		if err != nil {
+			return nil, err
+		}
+		binary.Write(&buf, binary.BigEndian, uint16(len(instructions)))
+		buf.Write(instructions)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// reconstructGlyf reconstructs glyf and loca from a transformed glyf table.
+// It also returns the xMin values of all glyphs needed for hmtx reconstruction.
+func reconstructGlyf(bb []byte) ([]byte, []byte, []int16, error) {
+	if len(bb) < 36 {
+		return nil, nil, nil, errCorruptWOFF
+	}
+
+	optionFlags := binary.BigEndian.Uint16(bb[2:])
+	numGlyphs := int(binary.BigEndian.Uint16(bb[4:]))
+	indexFormat := int(binary.BigEndian.Uint16(bb[6:]))
+
+	streams := make([]*bytes.Reader, 7)
+	off := 36
+	for i := range streams {
+		l := int(binary.BigEndian.Uint32(bb[8+i*4:]))
+		if l < 0 || off+l > len(bb) {
+			return nil, nil, nil, errCorruptWOFF
+		}
+		streams[i] = bytes.NewReader(bb[off : off+l])
+		off += l
+	}
+
+	s := woff2GlyfStreams{
+		nContour:    streams[0],
+		nPoints:     streams[1],
+		flag:        streams[2],
+		glyph:       streams[3],
+		composite:   streams[4],
+		bbox:        streams[5],
+		instruction: streams[6],
+	}
+
+	bitmapLen := ((numGlyphs + 31) >> 5) << 2
+	s.bboxBitmap = make([]byte, bitmapLen)
+	if _, err := io.ReadFull(s.bbox, s.bboxBitmap); err != nil {
+		return nil, nil, nil, errCorruptWOFF
+	}
+
+	if optionFlags&0x01 > 0 {
+		l := (numGlyphs + 7) >> 3
+		if off+l > len(bb) {
+			return nil, nil, nil, errCorruptWOFF
+		}
+		s.overlapBitmap = bb[off : off+l]
+	}
+
+	var glyf, loca bytes.Buffer
+	xMins := make([]int16, numGlyphs)
+
+	writeOffset := func() {
+		if indexFormat == 0 {
+			binary.Write(&loca, binary.BigEndian, uint16(glyf.Len()/2))
+			return
+		}
+		binary.Write(&loca, binary.BigEndian, uint32(glyf.Len()))
+	}
+
+	for gid := 0; gid < numGlyphs; gid++ {
+		writeOffset()
+
+		var nContours int16
+		if err := binary.Read(s.nContour, binary.BigEndian, &nContours); err != nil {
+			return nil, nil, nil, errCorruptWOFF
+		}
+
+		var (
+			g   []byte
+			err error
+		)
+
+		switch {
+		case nContours == 0:
+			// empty glyph
+			if s.bboxBitmap[gid>>3]&(0x80>>(gid&7)) > 0 {
+				return nil, nil, nil, errCorruptWOFF
+			}
+			continue
+		case nContours > 0:
+			g, err = s.simpleGlyph(gid, int(nContours))
+		case nContours == -1:
+			g, err = s.compositeGlyph(gid)
+		default:
+			err = errCorruptWOFF
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		xMins[gid] = int16(binary.BigEndian.Uint16(g[2:]))
+		glyf.Write(pad(g))
+	}
+	writeOffset()
+
+	return glyf.Bytes(), loca.Bytes(), xMins, nil
+}
+
+// reconstructHmtx reconstructs hmtx from a transformed hmtx table.
+func reconstructHmtx(bb, hhea []byte, xMins []int16) ([]byte, error) {
+	if len(bb) < 1 || len(hhea) < 36 || xMins == nil {
+		return nil, errCorruptWOFF
+	}
+
+	flags := bb[0]
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	numGlyphs := len(xMins)
+	if numHMetrics < 1 || numHMetrics > numGlyphs {
+		return nil, errCorruptWOFF
+	}
+
+	r := bytes.NewReader(bb[1:])
+
+	advanceWidths := make([]uint16, numHMetrics)
+	if err := binary.Read(r, binary.BigEndian, advanceWidths); err != nil {
+		return nil, errCorruptWOFF
+	}
+
+	lsbs := make([]int16, numGlyphs)
+	copy(lsbs, xMins)
+
+	if flags&0x01 == 0 {
+		if err := binary.Read(r, binary.BigEndian, lsbs[:numHMetrics]); err != nil {
+			return nil, errCorruptWOFF
+		}
+	}
+
+	if flags&0x02 == 0 {
+		if err := binary.Read(r, binary.BigEndian, lsbs[numHMetrics:]); err != nil {
+			return nil, errCorruptWOFF
+		}
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < numHMetrics; i++ {
+		binary.Write(&buf, binary.BigEndian, advanceWidths[i])
+		binary.Write(&buf, binary.BigEndian, lsbs[i])
+	}
+	binary.Write(&buf, binary.BigEndian, lsbs[numHMetrics:])
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+var webFontDir = filepath.Join("..", "testdata", "fonts", "web")
+
+func installAndLoad(t *testing.T, fileName string) TTFLight {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := InstallTrueTypeFont(dir, filepath.Join(webFontDir, fileName)); err != nil {
+		t.Fatalf("install %s: %v\n", fileName, err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("install %s: missing gob: %v\n", fileName, err)
+	}
+
+	fd := TTFLight{}
+	if err := load(filepath.Join(dir, files[0].Name()), &fd); err != nil {
+		t.Fatalf("load %s: %v\n", fileName, err)
+	}
+	return fd
+}
+
+func parseSfnt(t *testing.T, fileName string) *sfnt.Font {
+	t.Helper()
+
+	bb, err := os.ReadFile(filepath.Join(webFontDir, fileName))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if isWebFont(bb) {
+		if bb, err = decodeWebFont(fileName, bb); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	f, err := sfnt.Parse(bb)
+	if err != nil {
+		t.Fatalf("parse %s: %v\n", fileName, err)
+	}
+	return f
+}
+
+func TestInstallWebFonts(t *testing.T) {
+	want := installAndLoad(t, "FontAwesome.ttf")
+
+	for _, fn := range []string{"FontAwesome.woff", "FontAwesome.woff2"} {
+		got := installAndLoad(t, fn)
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("%s: metrics mismatch\nwant:%s\ngot:%s\n", fn, want, got)
+		}
+	}
+}
+
+func TestDecodeWebFontOutlines(t *testing.T) {
+	want := parseSfnt(t, "FontAwesome.ttf")
+	ppem := fixed.I(int(want.UnitsPerEm()))
+
+	var b1, b2 sfnt.Buffer
+
+	for _, fn := range []string{"FontAwesome.woff", "FontAwesome.woff2"} {
+		got := parseSfnt(t, fn)
+		if got.NumGlyphs() != want.NumGlyphs() {
+			t.Fatalf("%s: glyph count want:%d got:%d\n", fn, want.NumGlyphs(), got.NumGlyphs())
+		}
+		for i := 0; i < want.NumGlyphs(); i++ {
+			gid := sfnt.GlyphIndex(i)
+
+			adv1, err := want.GlyphAdvance(&b1, gid, ppem, xfont.HintingNone)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+			adv2, err := got.GlyphAdvance(&b2, gid, ppem, xfont.HintingNone)
+			if err != nil {
+				t.Fatalf("%s: %v\n", fn, err)
+			}
+			if adv1 != adv2 {
+				t.Fatalf("%s: glyph %d advance want:%d got:%d\n", fn, i, adv1, adv2)
+			}
+
+			segs1, err := want.LoadGlyph(&b1, gid, ppem, nil)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+			segs2, err := got.LoadGlyph(&b2, gid, ppem, nil)
+			if err != nil {
+				t.Fatalf("%s: glyph %d: %v\n", fn, i, err)
+			}
+			if !reflect.DeepEqual(segs1, segs2) {
+				t.Fatalf("%s: glyph %d outline mismatch\n", fn, i)
+			}
+		}
+	}
+}
+
+func TestDecodeWOFFExcessiveTableLength(t *testing.T) {
+	// A single table claiming to inflate to 4 GiB.
+	bb := make([]byte, 64)
+	copy(bb, woffSignature)
+	binary.BigEndian.PutUint32(bb[4:], 0x00010000)
+	binary.BigEndian.PutUint32(bb[8:], uint32(len(bb)))
+	binary.BigEndian.PutUint16(bb[12:], 1)
+	binary.BigEndian.PutUint32(bb[16:], 1024)
+	copy(bb[44:], "post")
+	binary.BigEndian.PutUint32(bb[48:], 64)
+	binary.BigEndian.PutUint32(bb[56:], 0xFFFFFFFF)
+
+	if _, err := decodeWebFont("test.woff", bb); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+func TestDecodeWOFF2DecompressionLimit(t *testing.T) {
+	// A 4 byte table followed by 16 MiB of compressed zeros.
+	var buf bytes.Buffer
+	w := brotli.NewWriterLevel(&buf, brotli.BestSpeed)
+	if _, err := w.Write(make([]byte, 16<<20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bb := make([]byte, 50)
+	copy(bb, woff2Signature)
+	binary.BigEndian.PutUint32(bb[4:], 0x00010000)
+	binary.BigEndian.PutUint16(bb[12:], 1)
+	binary.BigEndian.PutUint32(bb[16:], 1024)
+	binary.BigEndian.PutUint32(bb[20:], uint32(buf.Len()))
+	bb[48], bb[49] = 7, 4 // post, origLength 4
+	bb = append(bb, buf.Bytes()...)
+	binary.BigEndian.PutUint32(bb[8:], uint32(len(bb)))
+
+	sfnt, err := decodeWebFont("test.woff2", bb)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(sfnt) > 1024 {
+		t.Fatalf("decoded %d bytes\n", len(sfnt))
+	}
+}
@@ -1073,3 +1073,380 @@ func RemoveAnnotations(ctx *model.Context, selectedPages types.IntSet, idsAndTyp
 
 	return removed, nil
 }
+
+func prepForFlattenAnnotations(annotTypes []string) (map[model.AnnotationType]bool, error) {
+	m := map[model.AnnotationType]bool{}
+	for _, s := range annotTypes {
+		at, ok := model.AnnotTypes[s]
+		if !ok {
+			return nil, errors.Errorf("pdfcpu: unknown annotation type: %s", s)
+		}
+		if at == model.AnnWidget {
+			return nil, errors.New("pdfcpu: widget annotations are flattened via \"pdfcpu form flatten\"")
+		}
+		m[at] = true
+	}
+	return m, nil
+}
+
+func flattenAnnotation(d types.Dict, annTypes map[model.AnnotationType]bool) bool {
+	st := d.Subtype()
+	if st == nil {
+		return false
+	}
+
+	at, ok := model.AnnotTypes[*st]
+	if !ok || at == model.AnnWidget {
+		return false
+	}
+
+	if len(annTypes) > 0 {
+		return annTypes[at]
+	}
+
+	// By default flatten markup only.
+	return at != model.AnnLink && at != model.AnnPopup
+}
+
+// flattenable ensures a normal appearance for the printable annotation d and
+// returns true if d is either going to be rendered or meant to be removed without rendering.
+func flattenable(ctx *model.Context, d types.Dict) (bool, error) {
+	if !model.AnnotationVisible(d) {
+		return true, nil
+	}
+
+	// Keep annotations meant for screen display only.
+	if !model.AnnotationPrintable(d) {
+		return false, nil
+	}
+
+	if len(d.ArrayEntry("Rect")) != 4 {
+		return false, nil
+	}
+
+	if _, found := d.Find("AP"); !found {
+		if st := d.Subtype(); st == nil || !model.AnnotationAppearanceSupported(*st) {
+			return false, nil
+		}
+		ir, err := ctx.CreateAnnotationAppearance(d, true)
+		if err != nil {
+			return false, err
+		}
+		d["AP"] = types.Dict(map[string]types.Object{"N": *ir})
+	}
+
+	ir, err := ctx.NormalAppearance(d)
+	if err != nil || ir == nil {
+		return false, err
+	}
+
+	sd, _, err := ctx.DereferenceStreamDict(*ir)
+	if err != nil {
+		return false, err
+	}
+
+	return sd != nil, nil
+}
+
+func annotationsForFlattening(ctx *model.Context, annots types.Array, annTypes map[model.AnnotationType]bool, freed map[int]*types.IndirectRef) ([]types.Dict, types.IntSet, error) {
+	var dd []types.Dict
+	objNrs := types.IntSet{}
+
+	for _, o := range annots {
+
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if d == nil || !flattenAnnotation(d, annTypes) {
+			continue
+		}
+
+		ok, err = flattenable(ctx, d)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			// Keep what can't be rendered.
+			continue
+		}
+
+		dd = append(dd, d)
+		objNrs[ir.ObjectNumber.Value()] = true
+		freed[ir.ObjectNumber.Value()] = d.IndirectRefEntry("IRT")
+
+		// A popup is of no use once its parent is gone.
+		if ir := d.IndirectRefEntry("Popup"); ir != nil {
+			objNrs[ir.ObjectNumber.Value()] = true
+			freed[ir.ObjectNumber.Value()] = nil
+		}
+	}
+
+	return dd, objNrs, nil
+}
+
+func removeAnnotationsFromCacheByObjNr(ctx *model.Context, pageNr int, objNrs types.IntSet) {
+	pgAnnots, ok := ctx.PageAnnots[pageNr]
+	if !ok {
+		return
+	}
+	for annType, annots := range pgAnnots {
+		for objNr := range objNrs {
+			delete(annots.Map, objNr)
+		}
+		if len(annots.Map) == 0 {
+			delete(pgAnnots, annType)
+		}
+	}
+	if len(pgAnnots) == 0 {
+		delete(ctx.PageAnnots, pageNr)
+	}
+}
+
+func removeFlattenedAnnotations(ctx *model.Context, pageDict types.Dict, pageNr int, annots types.Array, objNrs types.IntSet) error {
+	// Free objects without deep removal since the flattened appearance streams are still in use.
+	if ir, ok := pageDict["Annots"].(types.IndirectRef); ok {
+		if err := ctx.FreeObject(ir.ObjectNumber.Value()); err != nil {
+			return err
+		}
+	}
+
+	var arr types.Array
+
+	for _, o := range annots {
+		if ir, ok := o.(types.IndirectRef); ok && objNrs[ir.ObjectNumber.Value()] {
+			if err := ctx.FreeObject(ir.ObjectNumber.Value()); err != nil {
+				return err
+			}
+			continue
+		}
+		arr = append(arr, o)
+	}
+
+	if len(arr) == 0 {
+		pageDict.Delete("Annots")
+	} else {
+		pageDict["Annots"] = arr
+	}
+
+	removeAnnotationsFromCacheByObjNr(ctx, pageNr, objNrs)
+
+	return nil
+}
+
+func flattenPageAnnotations(ctx *model.Context, pageNr int, annTypes map[model.AnnotationType]bool, freed map[int]*types.IndirectRef) (bool, error) {
+	pageDict, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil || pageDict == nil {
+		return false, err
+	}
+
+	o, found := pageDict.Find("Annots")
+	if !found {
+		return false, nil
+	}
+
+	annots, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return false, err
+	}
+
+	dd, objNrs, err := annotationsForFlattening(ctx, annots, annTypes, freed)
+	if err != nil || len(dd) == 0 {
+		return false, err
+	}
+
+	if _, err := ctx.FlattenAppearances(pageNr, dd); err != nil {
+		return false, err
+	}
+
+	return true, removeFlattenedAnnotations(ctx, pageDict, pageNr, annots, objNrs)
+}
+
+// repointReplies fixes the in-reply-to links of remaining annotations.
+// freed maps the obj# of each flattened annotation to its own /IRT.
+// A reply is attached to its closest remaining ancestor or becomes a standalone annotation.
+func repointReplies(ctx *model.Context, freed map[int]*types.IndirectRef) {
+	for _, entry := range ctx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		d, ok := entry.Object.(types.Dict)
+		if !ok {
+			continue
+		}
+		ir := d.IndirectRefEntry("IRT")
+		if ir == nil {
+			continue
+		}
+		target := ir
+		for i := 0; target != nil; i++ {
+			irt, ok := freed[target.ObjectNumber.Value()]
+			if !ok {
+				break
+			}
+			if i == len(freed) {
+				// Cyclic reply chain.
+				target = nil
+				break
+			}
+			target = irt
+		}
+		if target == ir {
+			continue
+		}
+		if target == nil {
+			d.Delete("IRT")
+			d.Delete("RT")
+			continue
+		}
+		d["IRT"] = *target
+	}
+}
+
+func pruneStructKid(ctx *model.Context, o types.Object, freed map[int]*types.IndirectRef, visited types.IntSet) (bool, error) {
+	if ir, ok := o.(types.IndirectRef); ok {
+		if visited[ir.ObjectNumber.Value()] {
+			return true, nil
+		}
+		visited[ir.ObjectNumber.Value()] = true
+	}
+
+	o, err := ctx.Dereference(o)
+	if err != nil {
+		return false, err
+	}
+
+	d, ok := o.(types.Dict)
+	if !ok {
+		// Marked content id
+		return true, nil
+	}
+
+	if t := d.Type(); t != nil {
+		switch *t {
+		case "OBJR":
+			ir := d.IndirectRefEntry("Obj")
+			if ir == nil {
+				return true, nil
+			}
+			_, gone := freed[ir.ObjectNumber.Value()]
+			return !gone, nil
+		case "MCR":
+			return true, nil
+		}
+	}
+
+	return true, pruneStructKids(ctx, d, freed, visited)
+}
+
+func pruneStructKids(ctx *model.Context, d types.Dict, freed map[int]*types.IndirectRef, visited types.IntSet) error {
+	o, found := d.Find("K")
+	if !found {
+		return nil
+	}
+
+	o1, err := ctx.Dereference(o)
+	if err != nil || o1 == nil {
+		return err
+	}
+
+	a, ok := o1.(types.Array)
+	if !ok {
+		keep, err := pruneStructKid(ctx, o, freed, visited)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			d.Delete("K")
+		}
+		return nil
+	}
+
+	var kids types.Array
+	for _, o := range a {
+		keep, err := pruneStructKid(ctx, o, freed, visited)
+		if err != nil {
+			return err
+		}
+		if keep {
+			kids = append(kids, o)
+		}
+	}
+
+	if len(kids) == len(a) {
+		return nil
+	}
+	if len(kids) == 0 {
+		d.Delete("K")
+		return nil
+	}
+	d["K"] = kids
+
+	return nil
+}
+
+// removeFlattenedAnnotationRefs removes references to flattened annotations
+// from remaining annotations and from the structure tree.
+func removeFlattenedAnnotationRefs(ctx *model.Context, freed map[int]*types.IndirectRef) error {
+	repointReplies(ctx, freed)
+
+	d, err := structRoot(ctx)
+	if err != nil || d == nil {
+		return err
+	}
+
+	return pruneStructKids(ctx, d, freed, types.IntSet{})
+}
+
+// FlattenAnnotations renders the appearance of annotations of selected pages into page content and removes them.
+// annotTypes restricts flattening to certain annotation types.
+// If annotTypes is empty all markup annotations (excluding Link and Popup) are flattened.
+// Missing appearances are generated.
+// Annotations flagged Hidden or NoView are removed without being rendered.
+// Annotations not meant for printing and annotations whose appearance can't be rendered are kept.
+// Replies to flattened annotations are attached to their closest remaining ancestor.
+func FlattenAnnotations(ctx *model.Context, selectedPages types.IntSet, annotTypes []string) (bool, error) {
+	annTypes, err := prepForFlattenAnnotations(annotTypes)
+	if err != nil {
+		return false, err
+	}
+
+	var flattened bool
+
+	// obj# of flattened annotation -> its in-reply-to annotation
+	freed := map[int]*types.IndirectRef{}
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+
+		if selectedPages != nil {
+			if _, found := selectedPages[pageNr]; !found {
+				continue
+			}
+		}
+
+		ok, err := flattenPageAnnotations(ctx, pageNr, annTypes, freed)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			flattened = true
+		}
+	}
+
+	if !flattened {
+		return false, nil
+	}
+
+	if err := removeFlattenedAnnotationRefs(ctx, freed); err != nil {
+		return false, err
+	}
+
+	ctx.EnsureVersionForWriting()
+
+	return true, nil
+}
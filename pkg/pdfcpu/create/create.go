@@ -145,6 +145,9 @@ func setAnnotationParentsAndFields(xRefTable *model.XRefTable, p *model.Page, pI
 			}
 			an.IndRef = indRef
 			p.AnnotTabs[k] = an
+		} else if an.Dict != nil {
+			// Already registered for tagging.
+			an.Dict["P"] = pIndRef
 		}
 		p.Fields = append(p.Fields, *an.IndRef)
 	}
@@ -157,6 +160,9 @@ func setAnnotationParentsAndFields(xRefTable *model.XRefTable, p *model.Page, pI
 			}
 			an.IndRef = indRef
 			p.Annots[k] = an
+		} else if an.Dict != nil {
+			// Already registered for tagging.
+			an.Dict["P"] = pIndRef
 		}
 		p.Fields = append(p.Fields, *an.IndRef)
 	}
@@ -341,6 +347,9 @@ func CreatePage(
 			return nil, nil, err
 		}
 		arr = append(arr, *ir)
+		if p.Tagged {
+			p.StructElems = append(p.StructElems, &model.StructElem{Type: "Link", Objs: []types.IndirectRef{*ir}})
+		}
 	}
 
 	pageDict["Annots"] = arr
@@ -503,24 +512,24 @@ func appendPage(
 	pagesDictIndRef types.IndirectRef,
 	pagesDict types.Dict,
 	p *model.Page,
-	fonts model.FontMap) error {
+	fonts model.FontMap) (*types.IndirectRef, error) {
 
 	ir, _, err := CreatePage(ctx.XRefTable, pagesDictIndRef, p, fonts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := ctx.SetValid(*ir); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := model.AppendPageTree(ir, 1, pagesDict); err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx.PageCount++
 
-	return nil
+	return ir, nil
 }
 
 func updatePage(ctx *model.Context, pageNr int, p *model.Page, fonts model.FontMap) error {
@@ -556,6 +565,8 @@ func UpdatePageTree(ctx *model.Context, pages []*model.Page, fontMap model.FontM
 
 	fields := types.Array{}
 
+	var tagged []taggedPage
+
 	for i, p := range pages {
 
 		if p == nil {
@@ -564,19 +575,30 @@ func UpdatePageTree(ctx *model.Context, pages []*model.Page, fontMap model.FontM
 
 		pageNr := i + 1
 
-		var err error
-
 		if pageNr > pageCount {
-			err = appendPage(ctx, *ir, d, p, fontMap)
+			pageIndRef, err := appendPage(ctx, *ir, d, p, fontMap)
+			if err != nil {
+				return nil, nil, err
+			}
+			if p.Tagged {
+				tagged = append(tagged, taggedPage{indRef: *pageIndRef, p: p})
+			}
 		} else {
-			err = updatePage(ctx, pageNr, p, fontMap)
+			if p.Tagged {
+				return nil, nil, errors.New("pdfcpu: tagging is supported for new pages only")
+			}
+			if err := updatePage(ctx, pageNr, p, fontMap); err != nil {
+				return nil, nil, err
+			}
 		}
 
-		if err != nil {
+		fields = append(fields, p.Fields...)
+	}
+
+	if len(tagged) > 0 {
+		if err := createStructTree(ctx, tagged); err != nil {
 			return nil, nil, err
 		}
-
-		fields = append(fields, p.Fields...)
 	}
 
 	return fields, fontMap, nil
@@ -619,6 +641,30 @@ func prepareFormFontResDict(ctx *model.Context, pdf *primitives.PDF, fonts model
 	return d, nil
 }
 
+// calculationOrder returns the fields carrying a calculation action.
+func calculationOrder(ctx *model.Context, fields types.Array) (types.Array, error) {
+	var co types.Array
+
+	for _, o := range fields {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return nil, err
+		}
+		if len(d) == 0 {
+			continue
+		}
+		aa, err := ctx.DereferenceDict(d["AA"])
+		if err != nil {
+			return nil, err
+		}
+		if _, found := aa.Find("C"); found {
+			co = append(co, o)
+		}
+	}
+
+	return co, nil
+}
+
 func createForm(
 	ctx *model.Context,
 	pdf *primitives.PDF,
@@ -627,6 +673,14 @@ func createForm(
 
 	d := types.Dict{"Fields": fields}
 
+	co, err := calculationOrder(ctx, fields)
+	if err != nil {
+		return err
+	}
+	if len(co) > 0 {
+		d["CO"] = co
+	}
+
 	if len(pdf.FormFonts) > 0 {
 		d1, err := prepareFormFontResDict(ctx, pdf, fonts)
 		if err != nil {
@@ -655,6 +709,18 @@ func updateForm(
 	}
 	d["Fields"] = append(arr, fields...)
 
+	co, err := calculationOrder(ctx, fields)
+	if err != nil {
+		return err
+	}
+	if len(co) > 0 {
+		arr, err := ctx.DereferenceArray(d["CO"])
+		if err != nil {
+			return err
+		}
+		d["CO"] = append(arr, co...)
+	}
+
 	if len(pdf.FormFonts) == 0 {
 		return nil
 	}
@@ -751,6 +817,14 @@ func FromJSON(ctx *model.Context, rd io.Reader) error {
 		return err
 	}
 
+	if pdf.Lang != "" {
+		s, err := types.Escape(pdf.Lang)
+		if err != nil {
+			return err
+		}
+		ctx.RootDict.InsertString("Lang", *s)
+	}
+
 	if len(fields) > 0 {
 		if err := handleForm(ctx, pdf, fields, fonts); err != nil {
 			return err
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+type taggedPage struct {
+	indRef types.IndirectRef
+	p      *model.Page
+}
+
+// structTreeBuilder creates the structure tree and the parent tree for tagged pages.
+type structTreeBuilder struct {
+	xRefTable *model.XRefTable
+	nextKey   int         // next parent tree key
+	nums      types.Array // parent tree number array
+}
+
+func (b *structTreeBuilder) newKey() int {
+	k := b.nextKey
+	b.nextKey++
+	return k
+}
+
+func (b *structTreeBuilder) createObjRefs(se *model.StructElem, seIndRef, pgIndRef types.IndirectRef, kids *types.Array, nums *types.Array) error {
+	for _, ir := range se.Objs {
+		d, err := b.xRefTable.DereferenceDict(ir)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		key := b.newKey()
+		d["StructParent"] = types.Integer(key)
+		*nums = append(*nums, types.Integer(key), seIndRef)
+
+		*kids = append(*kids, types.Dict(map[string]types.Object{
+			"Type": types.Name("OBJR"),
+			"Pg":   pgIndRef,
+			"Obj":  ir,
+		}))
+	}
+
+	return nil
+}
+
+func (b *structTreeBuilder) createStructElem(
+	se *model.StructElem,
+	parentIndRef, pgIndRef types.IndirectRef,
+	mcids map[int]types.IndirectRef,
+	nums *types.Array) (*types.IndirectRef, error) {
+
+	d := types.Dict(map[string]types.Object{
+		"Type": types.Name("StructElem"),
+		"S":    types.Name(se.Type),
+		"P":    parentIndRef,
+		"Pg":   pgIndRef,
+	})
+
+	if se.Alt != "" {
+		s, err := types.EscapedUTF16String(se.Alt)
+		if err != nil {
+			return nil, err
+		}
+		d.InsertString("Alt", *s)
+	}
+
+	ir, err := b.xRefTable.IndRefForNewObject(d)
+	if err != nil {
+		return nil, err
+	}
+
+	kids := types.Array{}
+
+	for _, mcid := range se.MCIDs {
+		mcids[mcid] = *ir
+		kids = append(kids, types.Integer(mcid))
+	}
+
+	if err := b.createObjRefs(se, *ir, pgIndRef, &kids, nums); err != nil {
+		return nil, err
+	}
+
+	for _, kid := range se.Kids {
+		kidIndRef, err := b.createStructElem(kid, *ir, pgIndRef, mcids, nums)
+		if err != nil {
+			return nil, err
+		}
+		kids = append(kids, *kidIndRef)
+	}
+
+	switch len(kids) {
+	case 0:
+	case 1:
+		d["K"] = kids[0]
+	default:
+		d["K"] = kids
+	}
+
+	return ir, nil
+}
+
+func (b *structTreeBuilder) createPageStructElems(tp taggedPage, docIndRef types.IndirectRef) (types.Array, error) {
+	pageKey := b.newKey()
+	mcids := map[int]types.IndirectRef{}
+	annotNums := types.Array{}
+	kids := types.Array{}
+
+	for _, se := range tp.p.StructElems {
+		ir, err := b.createStructElem(se, docIndRef, tp.indRef, mcids, &annotNums)
+		if err != nil {
+			return nil, err
+		}
+		kids = append(kids, *ir)
+	}
+
+	// The parent tree entry of a page is an array indexed by MCID.
+	maxMCID := -1
+	for mcid := range mcids {
+		if mcid > maxMCID {
+			maxMCID = mcid
+		}
+	}
+	parents := make(types.Array, maxMCID+1)
+	for i := range parents {
+		if ir, ok := mcids[i]; ok {
+			parents[i] = ir
+		}
+	}
+
+	b.nums = append(b.nums, types.Integer(pageKey), parents)
+	b.nums = append(b.nums, annotNums...)
+
+	pageDict, err := b.xRefTable.DereferenceDict(tp.indRef)
+	if err != nil {
+		return nil, err
+	}
+	pageDict["StructParents"] = types.Integer(pageKey)
+	if _, found := pageDict.Find("Annots"); found {
+		pageDict["Tabs"] = types.Name("S")
+	}
+
+	return kids, nil
+}
+
+// createStructTree adds a structure tree for tagged pages to the catalog and marks the document as tagged.
+func createStructTree(ctx *model.Context, pages []taggedPage) error {
+	xRefTable := ctx.XRefTable
+
+	rootDict := types.Dict(map[string]types.Object{"Type": types.Name("StructTreeRoot")})
+	rootIndRef, err := xRefTable.IndRefForNewObject(rootDict)
+	if err != nil {
+		return err
+	}
+
+	docDict := types.Dict(map[string]types.Object{
+		"Type": types.Name("StructElem"),
+		"S":    types.Name("Document"),
+		"P":    *rootIndRef,
+	})
+	docIndRef, err := xRefTable.IndRefForNewObject(docDict)
+	if err != nil {
+		return err
+	}
+
+	b := &structTreeBuilder{xRefTable: xRefTable, nums: types.Array{}}
+
+	kids := types.Array{}
+	for _, tp := range pages {
+		a, err := b.createPageStructElems(tp, *docIndRef)
+		if err != nil {
+			return err
+		}
+		kids = append(kids, a...)
+	}
+	docDict["K"] = kids
+
+	parentTreeIndRef, err := xRefTable.IndRefForNewObject(types.Dict(map[string]types.Object{"Nums": b.nums}))
+	if err != nil {
+		return err
+	}
+
+	rootDict["K"] = *docIndRef
+	rootDict["ParentTree"] = *parentTreeIndRef
+	rootDict["ParentTreeNextKey"] = types.Integer(b.nextKey)
+
+	ctx.RootDict["StructTreeRoot"] = *rootIndRef
+	ctx.RootDict["MarkInfo"] = types.Dict(map[string]types.Object{"Marked": types.Boolean(true)})
+
+	return nil
+}
@@ -51,6 +51,7 @@ func CreateXRefTableWithRootDict() (*model.XRefTable, error) {
 		ValidateLinks:     false,
 		URIs:              map[int]map[string]string{},
 		UsedGIDs:          map[string]map[uint16]bool{},
+		GlyphRunes:        map[string]map[uint16][]rune{},
 		FillFonts:         map[string]types.IndirectRef{},
 		Conf:              nil,
 	}
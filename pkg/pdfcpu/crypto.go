@@ -114,6 +114,28 @@ var (
 		model.RESETFORMFIELDS:         {0, 1},
 		model.EXPORTFORMFIELDS:        {0, 1},
 		model.FILLFORMFIELDS:          {0, 1},
+		model.CONVERTPDFA:             {0, 1},
+		model.EXPORTSTRUCTURE:         {0, 0},
+		model.IMPORTSTRUCTURE:         {0, 1},
+		model.LISTLAYERS:              {0, 0},
+		model.ADDLAYER:                {0, 1},
+		model.REMOVELAYERS:            {0, 1},
+		model.SHOWLAYERS:              {0, 1},
+		model.HIDELAYERS:              {0, 1},
+		model.FLATTENLAYERS:           {0, 1},
+		model.LISTPAGELABELS:          {0, 0},
+		model.ADDPAGELABELS:           {0, 1},
+		model.REMOVEPAGELABELS:        {0, 1},
+		model.FLATTENFORMFIELDS:       {0, 1},
+		model.FLATTENANNOTATIONS:      {0, 1},
+		model.EXPORTANNOTATIONS:       {0, 1},
+		model.IMPORTANNOTATIONS:       {0, 1},
+		model.LISTXFA:                 {0, 0},
+		model.EXTRACTXFA:              {1, 0},
+		model.REMOVEXFA:               {0, 1},
+		model.FILLXFA:                 {0, 1},
+		model.EMBEDFONTS:              {0, 1},
+		model.REPLACEFONT:             {0, 1},
 		model.LISTPAGELAYOUT:          {0, 1},
 		model.SETPAGELAYOUT:           {0, 1},
 		model.RESETPAGELAYOUT:         {0, 1},
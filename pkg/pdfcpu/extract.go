@@ -22,6 +22,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/pdfcpu/pdfcpu/internal/jpx"
 	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
@@ -241,6 +242,17 @@ func imageHeight(ctx *model.Context, sd *types.StreamDict, objNr int) (int, erro
 	return i.Value(), nil
 }
 
+// jpxConfig returns the image parameters embedded in the JPX data of sd.
+func jpxConfig(sd *types.StreamDict) (jpx.Config, error) {
+	if len(sd.FilterPipeline) > 1 {
+		if err := sd.Decode(); err != nil {
+			return jpx.Config{}, err
+		}
+		return jpx.DecodeConfig(bytes.NewReader(sd.Content))
+	}
+	return jpx.DecodeConfig(bytes.NewReader(sd.Raw))
+}
+
 func imageStub(
 	ctx *model.Context,
 	sd *types.StreamDict,
@@ -268,7 +280,7 @@ func imageStub(
 	if err != nil {
 		return nil, err
 	}
-	if lastFilter == filter.CCITTFax {
+	if lastFilter == filter.CCITTFax || lastFilter == filter.JBIG2 {
 		comp = 1
 	}
 
@@ -277,7 +289,20 @@ func imageStub(
 		bpc = *i
 	}
 	// if jpx, bpc is undefined
-	if imgMask {
+	if lastFilter == filter.JPX && (bpc == 0 || comp == 0) {
+		if c, err := jpxConfig(sd); err == nil {
+			if bpc == 0 {
+				bpc = c.BitsPerComponent
+			}
+			if comp == 0 {
+				comp = c.Components
+			}
+			if cs == "" {
+				cs = c.ColorSpace.String()
+			}
+		}
+	}
+	if imgMask || lastFilter == filter.JBIG2 {
 		bpc = 1
 	}
 
@@ -356,7 +381,7 @@ func prepareExtractImage(sd *types.StreamDict) (string, string, types.Dict, bool
 }
 func decodeImage(ctx *model.Context, sd *types.StreamDict, filters, lastFilter string, objNr int) error {
 	// CCITTDecoded images / (bit) masks don't have a ColorSpace attribute, but we render image files.
-	if lastFilter == filter.CCITTFax {
+	if lastFilter == filter.CCITTFax || lastFilter == filter.JBIG2 {
 		if _, err := ctx.DereferenceDictEntry(sd.Dict, "ColorSpace"); err != nil {
 			sd.InsertName("ColorSpace", model.DeviceGrayCS)
 		}
@@ -372,7 +397,7 @@ func decodeImage(ctx *model.Context, sd *types.StreamDict, filters, lastFilter s
 
 	switch lastFilter {
 
-	case filter.DCT, filter.JPX, filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength:
+	case filter.DCT, filter.JPX, filter.JBIG2, filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength:
 		if err := sd.Decode(); err != nil {
 			return err
 		}
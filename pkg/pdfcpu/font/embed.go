@@ -0,0 +1,610 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/internal/corefont/metrics"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	glyphRunesOnce sync.Once
+	glyphRunes     map[string]rune // glyph name => character
+)
+
+// glyphNameRune returns the character for a glyph name used in an encoding's Differences array.
+func glyphNameRune(name string) (rune, bool) {
+	glyphRunesOnce.Do(func() {
+		glyphRunes = map[string]rune{}
+		for c, n := range metrics.WinAnsiGlyphMap {
+			if r, ok := font.WinAnsiRune(byte(c)); ok {
+				glyphRunes[n] = r
+			}
+		}
+	})
+	if r, ok := glyphRunes[name]; ok {
+		return r, true
+	}
+	// uniXXXX and uXXXX[XX]
+	for _, p := range []string{"uni", "u"} {
+		if s, ok := strings.CutPrefix(name, p); ok && len(s) >= 4 && len(s) <= 6 {
+			if i, err := strconv.ParseUint(s, 16, 32); err == nil {
+				return rune(i), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// macRomanHigh holds the characters for the char codes 0x80-0xFF of MacRomanEncoding.
+const macRomanHigh = "ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø¿¡¬√ƒ≈∆«»…\u00A0ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄¤‹›ﬁﬂ‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ"
+
+var cMapToken = regexp.MustCompile(`<[0-9A-Fa-f\s]*>|\[|\]|[A-Za-z]+`)
+
+func cMapHex(s string) []byte {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, s[1:len(s)-1])
+	if len(s)%2 > 0 {
+		s += "0"
+	}
+	bb, _ := hex.DecodeString(s)
+	return bb
+}
+
+func cMapCode(s string) int {
+	c := 0
+	for _, b := range cMapHex(s) {
+		c = c<<8 | int(b)
+	}
+	return c
+}
+
+func cMapRunes(s string) []rune {
+	bb := cMapHex(s)
+	if len(bb) == 0 || len(bb)%2 > 0 {
+		return nil
+	}
+	u := make([]uint16, len(bb)/2)
+	for i := range u {
+		u[i] = binary.BigEndian.Uint16(bb[2*i:])
+	}
+	return utf16.Decode(u)
+}
+
+// parseToUnicode returns the characters for the char codes of a ToUnicode CMap.
+// Only the first character of multi character mappings like ligatures is used.
+func parseToUnicode(cMap string) map[int]rune {
+	m := map[int]rune{}
+	tt := cMapToken.FindAllString(cMap, -1)
+	for i := 0; i < len(tt); i++ {
+		switch tt[i] {
+		case "beginbfchar":
+			for i++; i+1 < len(tt) && tt[i] != "endbfchar"; i += 2 {
+				if rr := cMapRunes(tt[i+1]); len(rr) > 0 && tt[i][0] == '<' {
+					m[cMapCode(tt[i])] = rr[0]
+				}
+			}
+		case "beginbfrange":
+			for i++; i+2 < len(tt) && tt[i] != "endbfrange"; i += 3 {
+				lo, hi := cMapCode(tt[i]), cMapCode(tt[i+1])
+				if hi-lo > 0xFFFF {
+					continue
+				}
+				if tt[i+2] != "[" {
+					rr := cMapRunes(tt[i+2])
+					if len(rr) == 0 {
+						continue
+					}
+					for c := lo; c <= hi; c++ {
+						m[c] = rr[len(rr)-1] + rune(c-lo)
+					}
+					continue
+				}
+				c := lo
+				for i += 3; i < len(tt) && tt[i] != "]"; i++ {
+					if rr := cMapRunes(tt[i]); len(rr) > 0 && c <= hi {
+						m[c] = rr[0]
+					}
+					c++
+				}
+				i -= 2
+			}
+		}
+	}
+	return m
+}
+
+func toUnicodeRunes(xRefTable *model.XRefTable, d types.Dict) (map[int]rune, error) {
+	o, found := d.Find("ToUnicode")
+	if !found {
+		return nil, nil
+	}
+	sd, _, err := xRefTable.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil, err
+	}
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+	return parseToUnicode(string(sd.Content)), nil
+}
+
+// encodingRunes returns the characters for the char codes of a simple font using its encoding.
+func encodingRunes(xRefTable *model.XRefTable, d types.Dict) (map[int]rune, error) {
+	o, err := xRefTable.DereferenceDictEntry(d, "Encoding")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		baseEnc string
+		diffs   types.Array
+	)
+
+	switch o := o.(type) {
+	case types.Name:
+		baseEnc = o.Value()
+	case types.Dict:
+		if n := o.NameEntry("BaseEncoding"); n != nil {
+			baseEnc = *n
+		}
+		if diffs, err = xRefTable.DereferenceArray(o["Differences"]); err != nil {
+			return nil, err
+		}
+	}
+
+	m := map[int]rune{}
+	switch baseEnc {
+	case "WinAnsiEncoding":
+		for c := 0x20; c <= 0xFF; c++ {
+			if r, ok := font.WinAnsiRune(byte(c)); ok {
+				m[c] = r
+			}
+		}
+	default:
+		// Standard and MacRoman encoding share the printable ASCII characters except for quotes.
+		for c := 0x20; c < 0x7F; c++ {
+			m[c] = rune(c)
+		}
+		if baseEnc != "MacRomanEncoding" {
+			m[0x27], m[0x60] = 0x2019, 0x2018
+			break
+		}
+		for i, r := range []rune(macRomanHigh) {
+			m[0x80+i] = r
+		}
+	}
+
+	c := 0
+	for _, o := range diffs {
+		o, err := xRefTable.Dereference(o)
+		if err != nil {
+			return nil, err
+		}
+		switch o := o.(type) {
+		case types.Integer:
+			c = o.Value()
+		case types.Name:
+			if r, ok := glyphNameRune(o.Value()); ok {
+				m[c] = r
+			} else {
+				delete(m, c)
+			}
+			c++
+		}
+	}
+
+	return m, nil
+}
+
+// simpleFontEncoding returns an encoding mapping the char codes of a simple font to the glyph names for runes.
+func simpleFontEncoding(runes map[int]rune) types.Dict {
+	cc := []int{}
+	for c := range runes {
+		if c <= 0xFF {
+			cc = append(cc, c)
+		}
+	}
+	sort.Ints(cc)
+
+	diffs := types.Array{}
+	prev := -2
+	for _, c := range cc {
+		r := runes[c]
+		if w, ok := font.WinAnsiRune(byte(c)); ok && w == r && metrics.WinAnsiGlyphMap[c] != "" {
+			continue
+		}
+		name := fmt.Sprintf("uni%04X", r)
+		if b, ok := font.CoreFontCharCode(r); ok && metrics.WinAnsiGlyphMap[int(b)] != "" {
+			name = metrics.WinAnsiGlyphMap[int(b)]
+		}
+		if c != prev+1 {
+			diffs = append(diffs, types.Integer(c))
+		}
+		diffs = append(diffs, types.Name(name))
+		prev = c
+	}
+
+	d := types.Dict{"Type": types.Name("Encoding"), "BaseEncoding": types.Name("WinAnsiEncoding")}
+	if len(diffs) > 0 {
+		d["Differences"] = diffs
+	}
+	return d
+}
+
+func intArray(xRefTable *model.XRefTable, o types.Object) ([]int, error) {
+	a, err := xRefTable.DereferenceArray(o)
+	if err != nil || a == nil {
+		return nil, err
+	}
+	ii := make([]int, len(a))
+	for i, o := range a {
+		f, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return nil, err
+		}
+		ii[i] = int(f)
+	}
+	return ii, nil
+}
+
+func userFontMetrics(fontName string) (font.TTFLight, error) {
+	font.EnsureUserFontsLoaded()
+	font.UserFontMetricsLock.RLock()
+	ttf, ok := font.UserFontMetrics[fontName]
+	font.UserFontMetricsLock.RUnlock()
+	if !ok {
+		return ttf, errors.Errorf("pdfcpu: userfont %s not available", fontName)
+	}
+	return ttf, nil
+}
+
+func glyphWidth(ttf font.TTFLight, gid uint16) int {
+	if int(gid) < len(ttf.GlyphWidths) {
+		return ttf.GlyphWidths[gid]
+	}
+	return 0
+}
+
+// updateFontDescriptor points the font descriptor of a font dict to the embedded font program of fontName.
+func updateFontDescriptor(xRefTable *model.XRefTable, d types.Dict, ttf font.TTFLight, fontName, baseFont string, fontFile types.IndirectRef) error {
+	o, found := d.Find("FontDescriptor")
+	if !found {
+		fd := fontDescriptorDict(ttf, baseFont)
+		fd[fontFileEntry(ttf.CFF)] = fontFile
+		ir, err := xRefTable.IndRefForNewObject(fd)
+		if err != nil {
+			return err
+		}
+		d["FontDescriptor"] = *ir
+		return nil
+	}
+
+	fd, err := xRefTable.DereferenceDict(o)
+	if err != nil || fd == nil {
+		return errors.Errorf("pdfcpu: %s: corrupt font descriptor", fontName)
+	}
+	for _, k := range []string{"FontFile", "FontFile2", "FontFile3", "CharSet", "CIDSet"} {
+		fd.Delete(k)
+	}
+	fd[fontFileEntry(ttf.CFF)] = fontFile
+	fd["FontName"] = types.Name(baseFont)
+
+	// The embedded font program is a Unicode font.
+	flags := ttfFontDescriptorFlags(ttf)
+	if f := fd.IntEntry("Flags"); f != nil {
+		flags = uint32(*f)&^0x04 | 0x20
+	}
+	fd["Flags"] = types.Integer(flags)
+
+	return nil
+}
+
+func simpleFontWidths(xRefTable *model.XRefTable, d types.Dict, fontName string, runes map[int]rune, ttf font.TTFLight, keepWidths bool) (int, []int, error) {
+	first, last := -1, -1
+	if i := d.IntEntry("FirstChar"); i != nil {
+		first = *i
+	}
+	if i := d.IntEntry("LastChar"); i != nil {
+		last = *i
+	}
+	ww, err := intArray(xRefTable, d["Widths"])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if first < 0 || len(ww) == 0 {
+		first, last = 0xFF, 0
+		for c := range runes {
+			first, last = min(first, c), max(last, c)
+		}
+		ww = nil
+	}
+
+	if len(ww) > 0 && keepWidths {
+		return first, ww, nil
+	}
+
+	ww = make([]int, last-first+1)
+	for c := first; c <= last; c++ {
+		r, ok := runes[c]
+		if !ok {
+			continue
+		}
+		if keepWidths && font.IsCoreFont(fontName) {
+			// Retain the metrics used for laying out this text.
+			if b, ok := font.CoreFontCharCode(r); ok {
+				ww[c-first] = metrics.CoreFontCharWidth(fontName, int(b))
+			}
+			continue
+		}
+		if gid, ok := ttf.Chars[uint32(r)]; ok {
+			ww[c-first] = glyphWidth(ttf, gid)
+		}
+	}
+
+	return first, ww, nil
+}
+
+func embedSimpleFont(xRefTable *model.XRefTable, d types.Dict, fontName, userFontName string, ttf font.TTFLight, keepWidths bool) error {
+	runes, err := toUnicodeRunes(xRefTable, d)
+	if err != nil {
+		return err
+	}
+	var enc types.Object
+	if len(runes) > 0 {
+		// Glyphs of the embedded font are selected via glyph names.
+		enc = simpleFontEncoding(runes)
+	} else {
+		if fontName == "Symbol" || fontName == "ZapfDingbats" {
+			return errors.Errorf("pdfcpu: %s: symbolic fonts are not supported", fontName)
+		}
+		if runes, err = encodingRunes(xRefTable, d); err != nil {
+			return err
+		}
+	}
+
+	first, ww, err := simpleFontWidths(xRefTable, d, fontName, runes, ttf, keepWidths)
+	if err != nil {
+		return err
+	}
+
+	// Embed glyphs for all char codes with widths.
+	usedGIDs := map[uint16]bool{0: true}
+	for c, r := range runes {
+		if c < first || c >= first+len(ww) || ww[c-first] == 0 {
+			continue
+		}
+		if gid, ok := ttf.Chars[uint32(r)]; ok {
+			usedGIDs[gid] = true
+		}
+	}
+
+	bb, err := font.Subset(userFontName, usedGIDs)
+	if err != nil {
+		return err
+	}
+	fontFile, err := fontFileStreamIndRef(xRefTable, bb, ttf.CFF)
+	if err != nil {
+		return err
+	}
+
+	baseFont := subFontPrefix() + "+" + userFontName
+	if err := updateFontDescriptor(xRefTable, d, ttf, fontName, baseFont, *fontFile); err != nil {
+		return err
+	}
+
+	a := make(types.Array, len(ww))
+	for i, w := range ww {
+		a[i] = types.Integer(w)
+	}
+	wIndRef, err := xRefTable.IndRefForNewObject(a)
+	if err != nil {
+		return err
+	}
+
+	subType := "TrueType"
+	if ttf.CFF {
+		subType = "Type1"
+	}
+	d["Subtype"] = types.Name(subType)
+	d["BaseFont"] = types.Name(baseFont)
+	d["FirstChar"] = types.Integer(first)
+	d["LastChar"] = types.Integer(first + len(ww) - 1)
+	d["Widths"] = *wIndRef
+	if enc != nil {
+		d["Encoding"] = enc
+	}
+
+	return nil
+}
+
+func cidWidths(cids []int, ttf font.TTFLight, gids map[int]uint16) types.Array {
+	a := types.Array{}
+	for i := 0; i < len(cids); {
+		j := i + 1
+		for j < len(cids) && cids[j] == cids[j-1]+1 {
+			j++
+		}
+		ww := types.Array{}
+		for _, cid := range cids[i:j] {
+			ww = append(ww, types.Integer(glyphWidth(ttf, gids[cid])))
+		}
+		a = append(a, types.Integer(cids[i]), ww)
+		i = j
+	}
+	return a
+}
+
+func embedCIDFont(xRefTable *model.XRefTable, d types.Dict, fontName, userFontName string, ttf font.TTFLight, keepWidths bool) error {
+	if enc := d.NameEntry("Encoding"); enc == nil || (*enc != "Identity-H" && *enc != "Identity-V") {
+		return errors.Errorf("pdfcpu: %s: only Identity encoded CID fonts are supported", fontName)
+	}
+	if ttf.CFF {
+		return errors.Errorf("pdfcpu: %s: userfont %s with CFF outlines not supported for CID fonts", fontName, userFontName)
+	}
+
+	runes, err := toUnicodeRunes(xRefTable, d)
+	if err != nil {
+		return err
+	}
+	if len(runes) == 0 {
+		return errors.Errorf("pdfcpu: %s: missing ToUnicode CMap", fontName)
+	}
+
+	a, err := xRefTable.DereferenceArray(d["DescendantFonts"])
+	if err != nil || len(a) != 1 {
+		return ErrCorruptFontDict
+	}
+	df, err := xRefTable.DereferenceDict(a[0])
+	if err != nil || df == nil {
+		return ErrCorruptFontDict
+	}
+
+	// Map CIDs to the glyphs of userFontName.
+	usedGIDs := map[uint16]bool{0: true}
+	gids := map[int]uint16{}
+	cids := []int{}
+	maxCID := 0
+	for cid, r := range runes {
+		gid, ok := ttf.Chars[uint32(r)]
+		if !ok || cid > 0xFFFF {
+			continue
+		}
+		usedGIDs[gid] = true
+		gids[cid] = gid
+		cids = append(cids, cid)
+		maxCID = max(maxCID, cid)
+	}
+	sort.Ints(cids)
+
+	cidToGIDMap := make([]byte, 2*(maxCID+1))
+	for cid, gid := range gids {
+		binary.BigEndian.PutUint16(cidToGIDMap[2*cid:], gid)
+	}
+	sd, err := xRefTable.NewStreamDictForBuf(cidToGIDMap)
+	if err != nil {
+		return err
+	}
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+	cidToGIDMapIndRef, err := xRefTable.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	bb, err := font.Subset(userFontName, usedGIDs)
+	if err != nil {
+		return err
+	}
+	fontFile, err := fontFileStreamIndRef(xRefTable, bb, false)
+	if err != nil {
+		return err
+	}
+
+	baseFont := subFontPrefix() + "+" + userFontName
+	if err := updateFontDescriptor(xRefTable, df, ttf, fontName, baseFont, *fontFile); err != nil {
+		return err
+	}
+
+	df["Subtype"] = types.Name("CIDFontType2")
+	df["BaseFont"] = types.Name(baseFont)
+	df["CIDToGIDMap"] = *cidToGIDMapIndRef
+	d["BaseFont"] = types.Name(baseFont)
+
+	if !keepWidths {
+		wIndRef, err := xRefTable.IndRefForNewObject(cidWidths(cids, ttf, gids))
+		if err != nil {
+			return err
+		}
+		df["W"] = *wIndRef
+		df.Delete("DW")
+	}
+
+	return nil
+}
+
+// EmbedUserFont embeds a subset of userFontName into the font dict d replacing any embedded font program.
+// Glyphs are selected via the ToUnicode CMap of d or the encoding of simple fonts.
+// For keepWidths the glyph widths of d are retained, otherwise the widths of userFontName are used.
+func EmbedUserFont(xRefTable *model.XRefTable, d types.Dict, objNr int, userFontName string, keepWidths bool) error {
+	ttf, err := userFontMetrics(userFontName)
+	if err != nil {
+		return err
+	}
+
+	st := d.Subtype()
+	if st == nil {
+		return ErrCorruptFontDict
+	}
+
+	_, fontName, err := Name(xRefTable, d, objNr)
+	if err != nil {
+		return err
+	}
+
+	switch *st {
+	case "Type0":
+		return embedCIDFont(xRefTable, d, fontName, userFontName, ttf, keepWidths)
+	case "Type1", "MMType1", "TrueType":
+		return embedSimpleFont(xRefTable, d, fontName, userFontName, ttf, keepWidths)
+	default:
+		return errors.Errorf("pdfcpu: %s: unsupported font type %s", fontName, *st)
+	}
+}
+
+// ReplaceWithCoreFont turns the simple font dict d into a dict for coreFontName.
+func ReplaceWithCoreFont(xRefTable *model.XRefTable, d types.Dict, objNr int, coreFontName string) error {
+	st := d.Subtype()
+	if st == nil {
+		return ErrCorruptFontDict
+	}
+	_, fontName, err := Name(xRefTable, d, objNr)
+	if err != nil {
+		return err
+	}
+	if *st != "Type1" && *st != "MMType1" && *st != "TrueType" {
+		return errors.Errorf("pdfcpu: %s: can't replace composite or Type3 font with core font %s", fontName, coreFontName)
+	}
+	for _, k := range []string{"FontDescriptor", "Widths", "FirstChar", "LastChar"} {
+		d.Delete(k)
+	}
+	d["Subtype"] = types.Name("Type1")
+	d["BaseFont"] = types.Name(coreFontName)
+	if _, found := d.Find("Encoding"); !found && coreFontName != "Symbol" && coreFontName != "ZapfDingbats" {
+		d.InsertName("Encoding", "WinAnsiEncoding")
+	}
+	return nil
+}
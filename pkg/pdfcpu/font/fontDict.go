@@ -91,6 +91,9 @@ func fontDescriptorIndRefs(fd types.Dict, lang string, font *model.FontResource)
 	// }
 
 	font.FontFile = fd.IndirectRefEntry("FontFile2")
+	if font.FontFile == nil {
+		font.FontFile = fd.IndirectRefEntry("FontFile3")
+	}
 	// if font.FontFile == nil {
 	// 	return ErrCorruptFontDict
 	// }
@@ -149,7 +152,10 @@ func IndRefsForUserfontUpdate(xRefTable *model.XRefTable, d types.Dict, lang str
 }
 
 func flateEncodedStreamIndRef(xRefTable *model.XRefTable, data []byte) (*types.IndirectRef, error) {
-	sd, _ := xRefTable.NewStreamDictForBuf(data)
+	sd, err := xRefTable.NewStreamDictForBuf(data)
+	if err != nil {
+		return nil, err
+	}
 	sd.InsertInt("Length1", len(data))
 	if err := sd.Encode(); err != nil {
 		return nil, err
@@ -157,26 +163,52 @@ func flateEncodedStreamIndRef(xRefTable *model.XRefTable, data []byte) (*types.I
 	return xRefTable.IndRefForNewObject(*sd)
 }
 
-func ttfFontFile(xRefTable *model.XRefTable, fontName string) (*types.IndirectRef, error) {
+// fontFileEntry returns the font descriptor entry name for the embedded font program.
+func fontFileEntry(cff bool) string {
+	if cff {
+		return "FontFile3"
+	}
+	return "FontFile2"
+}
+
+func fontFileStreamIndRef(xRefTable *model.XRefTable, data []byte, cff bool) (*types.IndirectRef, error) {
+	if !cff {
+		return flateEncodedStreamIndRef(xRefTable, data)
+	}
+	// OpenType font program with CFF based glyph outlines.
+	sd, err := xRefTable.NewStreamDictForBuf(data)
+	if err != nil {
+		return nil, err
+	}
+	sd.InsertName("Subtype", "OpenType")
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+	return xRefTable.IndRefForNewObject(*sd)
+}
+
+func ttfFontFile(xRefTable *model.XRefTable, fontName string, cff bool) (*types.IndirectRef, error) {
 	bb, err := font.Read(fontName)
 	if err != nil {
 		return nil, err
 	}
-	return flateEncodedStreamIndRef(xRefTable, bb)
+	return fontFileStreamIndRef(xRefTable, bb, cff)
 }
 
-func ttfSubFontFile(xRefTable *model.XRefTable, fontName string, indRef *types.IndirectRef) (*types.IndirectRef, error) {
+func ttfSubFontFile(xRefTable *model.XRefTable, fontName string, cff bool, indRef *types.IndirectRef) (*types.IndirectRef, error) {
 	bb, err := font.Subset(fontName, xRefTable.UsedGIDs[fontName])
 	if err != nil {
 		return nil, err
 	}
 	if indRef == nil {
-		return flateEncodedStreamIndRef(xRefTable, bb)
+		return fontFileStreamIndRef(xRefTable, bb, cff)
 	}
 	entry, _ := xRefTable.FindTableEntryForIndRef(indRef)
 	sd, _ := entry.Object.(types.StreamDict)
 	sd.Content = bb
-	sd.InsertInt("Length1", len(bb))
+	if !cff {
+		sd.InsertInt("Length1", len(bb))
+	}
 	if err := sd.Encode(); err != nil {
 		return nil, err
 	}
@@ -301,12 +333,17 @@ func ttfFontDescriptorFlags(ttf font.TTFLight) uint32 {
 	return flags
 }
 
-// CIDFontFile returns a TrueType font file or subfont file for fontName.
+// CIDFontFile returns a TrueType or OpenType font file or subfont file for fontName.
 func CIDFontFile(xRefTable *model.XRefTable, fontName string, subFont bool) (*types.IndirectRef, error) {
+	font.EnsureUserFontsLoaded()
+	font.UserFontMetricsLock.RLock()
+	cff := font.UserFontMetrics[fontName].CFF
+	font.UserFontMetricsLock.RUnlock()
+
 	if subFont {
-		return ttfSubFontFile(xRefTable, fontName, nil)
+		return ttfSubFontFile(xRefTable, fontName, cff, nil)
 	}
-	return ttfFontFile(xRefTable, fontName)
+	return ttfFontFile(xRefTable, fontName, cff)
 }
 
 // CIDFontDescriptor returns a font descriptor describing the CIDFont’s default metrics other than its glyph widths.
@@ -335,7 +372,7 @@ func CIDFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 		if err != nil {
 			return nil, err
 		}
-		d["FontFile2"] = *fontFile
+		d[fontFileEntry(ttf.CFF)] = *fontFile
 	}
 
 	if embed {
@@ -362,12 +399,24 @@ func CIDFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 
 // FontDescriptor returns a TrueType font descriptor describing font’s default metrics other than its glyph widths.
 func NewFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, fontLang string) (*types.IndirectRef, error) {
-	fontFile, err := ttfFontFile(xRefTable, fontName)
+	fontFile, err := ttfFontFile(xRefTable, fontName, ttf.CFF)
 	if err != nil {
 		return nil, err
 	}
 
-	d := types.Dict(
+	d := fontDescriptorDict(ttf, fontName)
+
+	d[fontFileEntry(ttf.CFF)] = *fontFile
+
+	if fontLang != "" {
+		d["Lang"] = types.Name(fontLang)
+	}
+
+	return xRefTable.IndRefForNewObject(d)
+}
+
+func fontDescriptorDict(ttf font.TTFLight, fontName string) types.Dict {
+	return types.Dict(
 		map[string]types.Object{
 			"Ascent":      types.Integer(ttf.Ascent),
 			"CapHeight":   types.Integer(ttf.CapHeight),
@@ -375,19 +424,12 @@ func NewFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 			"Flags":       types.Integer(ttfFontDescriptorFlags(ttf)),
 			"FontBBox":    types.NewNumberArray(ttf.LLx, ttf.LLy, ttf.URx, ttf.URy),
 			"FontFamily":  types.StringLiteral(fontName),
-			"FontFile2":   *fontFile,
 			"FontName":    types.Name(fontName),
 			"ItalicAngle": types.Float(ttf.ItalicAngle),
 			"StemV":       types.Integer(70), // Irrelevant for embedded files.
 			"Type":        types.Name("FontDescriptor"),
 		},
 	)
-
-	if fontLang != "" {
-		d["Lang"] = types.Name(fontLang)
-	}
-
-	return xRefTable.IndRefForNewObject(d)
 }
 
 func wArr(ttf font.TTFLight, from, thru int) types.Array {
@@ -561,7 +603,7 @@ func Widths(xRefTable *model.XRefTable, ttf font.TTFLight, first, last int) (*ty
 	return xRefTable.IndRefForNewObject(a)
 }
 
-func bf(b *bytes.Buffer, ttf font.TTFLight, usedGIDs map[uint16]bool, subFont bool) {
+func bf(b *bytes.Buffer, ttf font.TTFLight, usedGIDs map[uint16]bool, glyphRunes map[uint16][]rune, subFont bool) {
 	var gids []int
 	if subFont {
 		gids = make([]int, 0, len(usedGIDs))
@@ -570,6 +612,11 @@ func bf(b *bytes.Buffer, ttf font.TTFLight, usedGIDs map[uint16]bool, subFont bo
 		}
 	} else {
 		gids = ttf.Gids()
+		for gid := range glyphRunes {
+			if _, ok := ttf.ToUnicode[gid]; !ok {
+				gids = append(gids, int(gid))
+			}
+		}
 	}
 	sort.Ints(gids)
 
@@ -584,8 +631,12 @@ func bf(b *bytes.Buffer, ttf font.TTFLight, usedGIDs map[uint16]bool, subFont bo
 	for i := 0; i < l; i++ {
 		gid := gids[i]
 		fmt.Fprintf(b, "<%04X> <", gid)
-		u := ttf.ToUnicode[uint16(gid)]
-		s := utf16.Encode([]rune{rune(u)})
+		rr := []rune{rune(ttf.ToUnicode[uint16(gid)])}
+		if _, ok := ttf.ToUnicode[uint16(gid)]; !ok && len(glyphRunes[uint16(gid)]) > 0 {
+			// Shaped glyph
+			rr = glyphRunes[uint16(gid)]
+		}
+		s := utf16.Encode(rr)
 		for _, v := range s {
 			fmt.Fprintf(b, "%04X", v)
 		}
@@ -646,7 +697,7 @@ end`
 	if usedGIDs == nil {
 		usedGIDs = map[uint16]bool{}
 	}
-	bf(&b, ttf, usedGIDs, subFont)
+	bf(&b, ttf, usedGIDs, xRefTable.GlyphRunes[fontName], subFont)
 	b.WriteString(epi)
 
 	bb := b.Bytes()
@@ -672,11 +723,12 @@ var (
 	ErrCorruptFontDict = errors.New("pdfcpu: corrupt fontDict")
 )
 
-func usedGIDsFromCMap(cMap string) ([]uint16, error) {
+func usedGIDsFromCMap(cMap string) ([]uint16, map[uint16][]rune, error) {
 	gids := []uint16{}
+	glyphRunes := map[uint16][]rune{}
 	i := strings.Index(cMap, "endcodespacerange")
 	if i < 0 {
-		return nil, errCorruptCMap
+		return nil, nil, errCorruptCMap
 	}
 	scanner := bufio.NewScanner(strings.NewReader(cMap[i+len("endcodespacerange")+1:]))
 
@@ -690,7 +742,7 @@ func usedGIDsFromCMap(cMap string) ([]uint16, error) {
 		ss := strings.Split(s, " ")
 		i, err := strconv.Atoi(ss[0])
 		if err != nil {
-			return nil, errCorruptCMap
+			return nil, nil, errCorruptCMap
 		}
 
 		lastBlock = i < 100
@@ -700,20 +752,23 @@ func usedGIDsFromCMap(cMap string) ([]uint16, error) {
 			scanner.Scan()
 			s1 := scanner.Text()
 			if s1[0] != '<' {
-				return nil, errCorruptCMap
+				return nil, nil, errCorruptCMap
 			}
 			bb, err := hex.DecodeString(s1[1:5])
 			if err != nil {
-				return nil, errCorruptCMap
+				return nil, nil, errCorruptCMap
 			}
 			gid := binary.BigEndian.Uint16(bb)
 			gids = append(gids, gid)
+			if rr := cMapDstRunes(s1); rr != nil {
+				glyphRunes[gid] = rr
+			}
 		}
 
 		// scanLine: endbfchar
 		scanner.Scan()
 		if scanner.Text() != "endbfchar" {
-			return nil, errCorruptCMap
+			return nil, nil, errCorruptCMap
 		}
 
 		// scanLine: endcmap => done, or %d beginbfchar
@@ -723,15 +778,33 @@ func usedGIDsFromCMap(cMap string) ([]uint16, error) {
 			break
 		}
 		if lastBlock {
-			return nil, errCorruptCMap
+			return nil, nil, errCorruptCMap
 		}
 	}
 
-	return gids, nil
+	return gids, glyphRunes, nil
+}
+
+// cMapDstRunes returns the characters of a bfchar mapping line: <srcCode> <dstString>
+func cMapDstRunes(s string) []rune {
+	i := strings.LastIndex(s, "<")
+	if i <= 0 || !strings.HasSuffix(s, ">") {
+		return nil
+	}
+	bb, err := hex.DecodeString(s[i+1 : len(s)-1])
+	if err != nil || len(bb) == 0 || len(bb)%2 > 0 {
+		return nil
+	}
+	u := make([]uint16, len(bb)/2)
+	for j := range u {
+		u[j] = binary.BigEndian.Uint16(bb[2*j:])
+	}
+	return utf16.Decode(u)
 }
 
 // UpdateUserfont updates the fontdict for fontName via supplied font resource.
 func UpdateUserfont(xRefTable *model.XRefTable, fontName string, f model.FontResource) error {
+	font.EnsureUserFontsLoaded()
 	font.UserFontMetricsLock.RLock()
 	ttf, ok := font.UserFontMetrics[fontName]
 	font.UserFontMetricsLock.RUnlock()
@@ -748,7 +821,7 @@ func UpdateUserfont(xRefTable *model.XRefTable, fontName string, f model.FontRes
 		return err
 	}
 
-	if _, err := ttfSubFontFile(xRefTable, fontName, f.FontFile); err != nil {
+	if _, err := ttfSubFontFile(xRefTable, fontName, ttf.CFF, f.FontFile); err != nil {
 		return err
 	}
 
@@ -771,7 +844,7 @@ func usedGIDsFromCMapIndRef(xRefTable *model.XRefTable, fontName string, cmapInd
 	if err := sd.Decode(); err != nil {
 		return err
 	}
-	gids, err := usedGIDsFromCMap(string(sd.Content))
+	gids, glyphRunes, err := usedGIDsFromCMap(string(sd.Content))
 	if err != nil {
 		return err
 	}
@@ -783,6 +856,19 @@ func usedGIDsFromCMapIndRef(xRefTable *model.XRefTable, fontName string, cmapInd
 	for _, gid := range gids {
 		m[gid] = true
 	}
+	if xRefTable.GlyphRunes == nil {
+		xRefTable.GlyphRunes = map[string]map[uint16][]rune{}
+	}
+	gr, ok := xRefTable.GlyphRunes[fontName]
+	if !ok {
+		gr = map[uint16][]rune{}
+		xRefTable.GlyphRunes[fontName] = gr
+	}
+	for gid, rr := range glyphRunes {
+		if _, ok := gr[gid]; !ok {
+			gr[gid] = rr
+		}
+	}
 	return nil
 }
 
@@ -813,10 +899,17 @@ func CIDFontDict(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, baseFo
 		supplement = parms.supplement
 	}
 
+	// CFF based OpenType fonts are embedded as CIDFontType0 using CIDs as glyph indices.
+	// CID-keyed CFF fonts get an identity charset on subsetting mapping each glyph index to the same CID.
+	subType := "CIDFontType2"
+	if ttf.CFF {
+		subType = "CIDFontType0"
+	}
+
 	d := types.Dict(
 		map[string]types.Object{
 			"Type":     types.Name("Font"),
-			"Subtype":  types.Name("CIDFontType2"),
+			"Subtype":  types.Name(subType),
 			"BaseFont": types.Name(baseFontName),
 			"CIDSystemInfo": types.Dict(
 				map[string]types.Object{
@@ -856,7 +949,7 @@ func CIDFontDict(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, baseFo
 	// maps CIDs to the glyph indices for the appropriate glyph descriptions in that font program.
 	// if stream: the glyph index for a particular CID value c shall be a 2-byte value stored in bytes 2 × c and 2 × c + 1,
 	// where the first byte shall be the high-order byte.))
-	if ordering == "Identity" {
+	if ordering == "Identity" && !ttf.CFF {
 		d["CIDToGIDMap"] = types.Name("Identity")
 	}
 
@@ -874,6 +967,7 @@ func CIDFontDict(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, baseFo
 }
 
 func type0FontDict(xRefTable *model.XRefTable, fontName, lang, script string, indRef *types.IndirectRef) (*types.IndirectRef, error) {
+	font.EnsureUserFontsLoaded()
 	font.UserFontMetricsLock.RLock()
 	ttf, ok := font.UserFontMetrics[fontName]
 	font.UserFontMetricsLock.RUnlock()
@@ -942,6 +1036,7 @@ func type0FontDict(xRefTable *model.XRefTable, fontName, lang, script string, in
 }
 
 func trueTypeFontDict(xRefTable *model.XRefTable, fontName, fontLang string) (*types.IndirectRef, error) {
+	font.EnsureUserFontsLoaded()
 	font.UserFontMetricsLock.RLock()
 	ttf, ok := font.UserFontMetrics[fontName]
 	font.UserFontMetricsLock.RUnlock()
@@ -960,9 +1055,14 @@ func trueTypeFontDict(xRefTable *model.XRefTable, fontName, fontLang string) (*t
 		return nil, err
 	}
 
+	subType := "TrueType"
+	if ttf.CFF {
+		subType = "Type1"
+	}
+
 	d := types.NewDict()
 	d.InsertName("Type", "Font")
-	d.InsertName("Subtype", "TrueType")
+	d.InsertName("Subtype", subType)
 	d.InsertName("BaseFont", fontName)
 	d.InsertName("Name", fontName)
 	d.InsertName("Encoding", "WinAnsiEncoding")
@@ -0,0 +1,216 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+)
+
+type fontDictObj struct {
+	objNr    int
+	fontName string
+	d        types.Dict
+}
+
+// topLevelFontDicts returns all font dicts except for Type3 and descendant fonts sorted by object number.
+func topLevelFontDicts(ctx *model.Context) ([]fontDictObj, error) {
+	objNrs := make([]int, 0, len(ctx.Table))
+	for objNr := range ctx.Table {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	descendants := map[int]bool{}
+	ff := []fontDictObj{}
+
+	for _, objNr := range objNrs {
+		entry := ctx.Table[objNr]
+		if entry == nil || entry.Free || entry.Object == nil {
+			continue
+		}
+		d, ok := entry.Object.(types.Dict)
+		if !ok || d.Type() == nil || *d.Type() != "Font" {
+			continue
+		}
+		st := d.Subtype()
+		if st == nil || *st == "Type3" {
+			continue
+		}
+		if *st == "Type0" {
+			if a, err := ctx.DereferenceArray(d["DescendantFonts"]); err == nil {
+				for _, o := range a {
+					if ir, ok := o.(types.IndirectRef); ok {
+						descendants[ir.ObjectNumber.Value()] = true
+					}
+				}
+			}
+		}
+		_, fontName, err := pdffont.Name(ctx.XRefTable, d, objNr)
+		if err != nil {
+			return nil, err
+		}
+		ff = append(ff, fontDictObj{objNr: objNr, fontName: fontName, d: d})
+	}
+
+	i := 0
+	for _, f := range ff {
+		if !descendants[f.objNr] {
+			ff[i] = f
+			i++
+		}
+	}
+
+	return ff[:i], nil
+}
+
+func normalizedFontName(fontName string) string {
+	return strings.ToLower(strings.NewReplacer(",", "-", " ", "", "_", "-").Replace(fontName))
+}
+
+// matchingUserFont returns the installed user font for fontName using fontMap or its PostScript name.
+func matchingUserFont(fontName string, fontMap map[string]string) (string, bool) {
+	if fn, ok := fontMap[fontName]; ok {
+		return fn, font.IsUserFont(fn)
+	}
+	if font.IsUserFont(fontName) {
+		return fontName, true
+	}
+	s := normalizedFontName(fontName)
+	for _, fn := range font.UserFontNames() {
+		if normalizedFontName(fn) == s {
+			return fn, true
+		}
+	}
+	return "", false
+}
+
+// EmbedFonts embeds all non embedded fonts of ctx matching an installed user font.
+// fontMap maps font names as used in ctx to user font names.
+// Glyph widths are retained in order to leave the layout unchanged.
+// Fonts requested via fontMap which can't be embedded are an error, so is not embedding any font at all.
+func EmbedFonts(ctx *model.Context, fontMap map[string]string) error {
+	ff, err := topLevelFontDicts(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		found     = map[string]bool{}
+		candidate bool
+		c         int
+	)
+
+	for _, f := range ff {
+		found[f.fontName] = true
+
+		embedded, err := pdffont.Embedded(ctx.XRefTable, f.d, f.objNr)
+		if err != nil {
+			return err
+		}
+		if embedded {
+			continue
+		}
+		candidate = true
+
+		_, requested := fontMap[f.fontName]
+
+		userFontName, ok := matchingUserFont(f.fontName, fontMap)
+		if !ok {
+			if requested {
+				return errors.Errorf("pdfcpu: obj#%d: %s: user font %s not installed", f.objNr, f.fontName, fontMap[f.fontName])
+			}
+			if log.CLIEnabled() {
+				log.CLI.Printf("obj#%d: %s: no matching user font\n", f.objNr, f.fontName)
+			}
+			continue
+		}
+
+		if err := pdffont.EmbedUserFont(ctx.XRefTable, f.d, f.objNr, userFontName, true); err != nil {
+			return errors.Wrapf(err, "pdfcpu: obj#%d: %s", f.objNr, f.fontName)
+		}
+		c++
+
+		if log.CLIEnabled() {
+			log.CLI.Printf("obj#%d: %s: embedded %s\n", f.objNr, f.fontName, userFontName)
+		}
+	}
+
+	fontNames := make([]string, 0, len(fontMap))
+	for fontName := range fontMap {
+		fontNames = append(fontNames, fontName)
+	}
+	sort.Strings(fontNames)
+	for _, fontName := range fontNames {
+		if !found[fontName] {
+			return errors.Errorf("pdfcpu: font %s not found", fontName)
+		}
+	}
+
+	if candidate && c == 0 {
+		return errors.New("pdfcpu: no matching user font found")
+	}
+
+	return nil
+}
+
+// ReplaceFont replaces all fonts named fontName by the core font or user font newFontName.
+func ReplaceFont(ctx *model.Context, fontName, newFontName string) error {
+	if !font.SupportedFont(newFontName) {
+		return errors.Errorf("pdfcpu: font %s not available", newFontName)
+	}
+
+	ff, err := topLevelFontDicts(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, f := range ff {
+		if f.fontName != fontName {
+			continue
+		}
+		found = true
+
+		if font.IsCoreFont(newFontName) {
+			err = pdffont.ReplaceWithCoreFont(ctx.XRefTable, f.d, f.objNr, newFontName)
+		} else {
+			err = pdffont.EmbedUserFont(ctx.XRefTable, f.d, f.objNr, newFontName, false)
+		}
+		if err != nil {
+			return err
+		}
+
+		if log.CLIEnabled() {
+			log.CLI.Printf("obj#%d: %s: replaced by %s\n", f.objNr, fontName, newFontName)
+		}
+	}
+
+	if !found {
+		return errors.Errorf("pdfcpu: font %s not found", fontName)
+	}
+
+	return nil
+}
@@ -854,3 +854,147 @@ func ExportFormJSON(xRefTable *model.XRefTable, source string, w io.Writer) (boo
 
 	return ok, err
 }
+
+// optPairs returns the export and display values of choice field options given as pairs.
+func optPairs(xRefTable *model.XRefTable, d types.Dict) ([][2]string, error) {
+	o, found := d.Find("Opt")
+	if !found {
+		return nil, nil
+	}
+
+	arr, err := xRefTable.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs [][2]string
+
+	for _, o := range arr {
+		a, ok := o.(types.Array)
+		if !ok || len(a) != 2 {
+			continue
+		}
+		exp, err := xRefTable.DereferenceText(a[0])
+		if err != nil {
+			return nil, err
+		}
+		disp, err := xRefTable.DereferenceText(a[1])
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, [2]string{exp, strings.TrimSpace(disp)})
+	}
+
+	return pairs, nil
+}
+
+func exportValue(pairs [][2]string, s string) string {
+	for _, p := range pairs {
+		if p[1] == s {
+			return p[0]
+		}
+	}
+	return s
+}
+
+func displayValue(pairs [][2]string, s string) string {
+	for _, p := range pairs {
+		if p[0] == s {
+			return p[1]
+		}
+	}
+	return s
+}
+
+func exportValueLiteral(pairs [][2]string, o types.Object) (types.Object, error) {
+	s, err := model.Text(o)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return o, nil
+	}
+	s1, err := types.EscapedUTF16String(exportValue(pairs, s))
+	if err != nil {
+		return nil, err
+	}
+	return types.StringLiteral(*s1), nil
+}
+
+// fieldValue returns the value of the field d as used for data exchange.
+// Choice field values are mapped to their export values.
+func fieldValue(xRefTable *model.XRefTable, d types.Dict) (types.Object, error) {
+	o, found := d.Find("V")
+	if !found {
+		return nil, nil
+	}
+
+	o, err := xRefTable.Dereference(o)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := optPairs(xRefTable, d)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := o.(type) {
+
+	case types.Name:
+		return v, nil
+
+	case types.StringLiteral, types.HexLiteral:
+		return exportValueLiteral(pairs, v)
+
+	case types.Array:
+		arr := types.Array{}
+		for _, o := range v {
+			o, err := xRefTable.Dereference(o)
+			if err != nil {
+				return nil, err
+			}
+			if o, err = exportValueLiteral(pairs, o); err != nil {
+				return nil, err
+			}
+			arr = append(arr, o)
+		}
+		return arr, nil
+	}
+
+	// eg. signature dicts
+	return nil, nil
+}
+
+// valueStrings returns the strings represented by the field value o.
+func valueStrings(o types.Object) ([]string, error) {
+	switch v := o.(type) {
+
+	case types.Name:
+		s, err := types.DecodeName(v.Value())
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+
+	case types.StringLiteral, types.HexLiteral:
+		s, err := model.Text(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+
+	case types.Array:
+		var ss []string
+		for _, o := range v {
+			s, err := model.Text(o)
+			if err != nil {
+				return nil, err
+			}
+			ss = append(ss, s)
+		}
+		return ss, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,416 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidFDF is returned for input that is not a well formed FDF file.
+var ErrInvalidFDF = errors.New("pdfcpu: invalid FDF")
+
+var fdfObjRegExp = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// FDF represents the form data of a Forms Data Format file (ISO 32000-1, 12.7.8).
+type FDF struct {
+	F      string      // The PDF file this FDF file belongs to.
+	Fields []*FDFField // The root fields.
+}
+
+// FDFField represents a form field identified by its partial name.
+type FDFField struct {
+	Name  string       // T
+	Value types.Object // V: name, string or array of strings
+	Kids  []*FDFField
+}
+
+type fdfParser struct {
+	objs map[int]types.Object
+}
+
+func (p fdfParser) dereference(o types.Object) types.Object {
+	for i := 0; i < 10; i++ {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok {
+			return o
+		}
+		o = p.objs[indRef.ObjectNumber.Value()]
+	}
+	return nil
+}
+
+func (p fdfParser) dereferenceDict(o types.Object) types.Dict {
+	d, _ := p.dereference(o).(types.Dict)
+	return d
+}
+
+func (p fdfParser) dereferenceArray(o types.Object) types.Array {
+	arr, _ := p.dereference(o).(types.Array)
+	return arr
+}
+
+func (p fdfParser) value(o types.Object) types.Object {
+	o = p.dereference(o)
+	arr, ok := o.(types.Array)
+	if !ok {
+		return o
+	}
+	a := types.Array{}
+	for _, o := range arr {
+		a = append(a, p.dereference(o))
+	}
+	return a
+}
+
+func (p fdfParser) fields(arr types.Array) ([]*FDFField, error) {
+	var ff []*FDFField
+
+	for _, o := range arr {
+		d := p.dereferenceDict(o)
+		if d == nil {
+			return nil, errors.Wrap(ErrInvalidFDF, "corrupt field")
+		}
+
+		o, found := d.Find("T")
+		if !found {
+			continue
+		}
+		t, err := model.Text(p.dereference(o))
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidFDF, err.Error())
+		}
+
+		f := &FDFField{Name: t}
+
+		if o, found := d.Find("V"); found {
+			f.Value = p.value(o)
+		}
+
+		if o, found := d.Find("Kids"); found {
+			if f.Kids, err = p.fields(p.dereferenceArray(o)); err != nil {
+				return nil, err
+			}
+		}
+
+		ff = append(ff, f)
+	}
+
+	return ff, nil
+}
+
+// parseObjects parses the indirect objects of an FDF file skipping stream data.
+func (p fdfParser) parseObjects(s string) error {
+	pos := 0
+	for _, m := range fdfObjRegExp.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] < pos {
+			// Inside an object or stream already processed.
+			continue
+		}
+
+		objNr, err := strconv.Atoi(s[m[2]:m[3]])
+		if err != nil {
+			return errors.Wrap(ErrInvalidFDF, err.Error())
+		}
+
+		l := s[m[1]:]
+		o, err := model.ParseObject(&l)
+		if err != nil {
+			return errors.Wrapf(ErrInvalidFDF, "obj#%d: %v", objNr, err)
+		}
+		p.objs[objNr] = o
+
+		pos = len(s) - len(l)
+		if strings.HasPrefix(strings.TrimSpace(l), "stream") {
+			if i := strings.Index(l, "endstream"); i > 0 {
+				pos += i
+			}
+		}
+	}
+
+	return nil
+}
+
+// ParseFDF parses the form data of an FDF file from rd.
+func ParseFDF(rd io.Reader) (*FDF, error) {
+	bb, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(bytes.TrimLeft(bb, " \t\r\n"), []byte("%FDF-")) {
+		return nil, errors.Wrap(ErrInvalidFDF, "missing header")
+	}
+
+	s := string(bb)
+
+	p := fdfParser{objs: map[int]types.Object{}}
+	if err := p.parseObjects(s); err != nil {
+		return nil, err
+	}
+
+	i := strings.LastIndex(s, "trailer")
+	if i < 0 {
+		return nil, errors.Wrap(ErrInvalidFDF, "missing trailer")
+	}
+	l := s[i+len("trailer"):]
+	o, err := model.ParseObject(&l)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFDF, err.Error())
+	}
+
+	trailer, ok := o.(types.Dict)
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidFDF, "corrupt trailer")
+	}
+
+	root := p.dereferenceDict(trailer["Root"])
+	if root == nil {
+		return nil, errors.Wrap(ErrInvalidFDF, "missing catalog")
+	}
+
+	d := p.dereferenceDict(root["FDF"])
+	if d == nil {
+		return nil, errors.Wrap(ErrInvalidFDF, "missing FDF dict")
+	}
+
+	fdf := &FDF{}
+
+	if o, found := d.Find("F"); found {
+		switch f := p.dereference(o).(type) {
+		case types.StringLiteral, types.HexLiteral:
+			fdf.F, _ = model.Text(f)
+		case types.Dict:
+			// File specification dict
+			fdf.F, _ = model.Text(p.dereference(f["F"]))
+		}
+	}
+
+	if fdf.Fields, err = p.fields(p.dereferenceArray(d["Fields"])); err != nil {
+		return nil, err
+	}
+
+	return fdf, nil
+}
+
+// fdfString returns s as string literal using UTF-16BE encoding for non ASCII text only.
+func fdfString(s string) (types.StringLiteral, error) {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			s1, err := types.EscapedUTF16String(s)
+			if err != nil {
+				return "", err
+			}
+			return types.StringLiteral(*s1), nil
+		}
+	}
+	s1, err := types.Escape(s)
+	if err != nil {
+		return "", err
+	}
+	return types.StringLiteral(*s1), nil
+}
+
+// fdfValue returns the field value o with all text re encoded for FDF.
+func fdfValue(o types.Object) (types.Object, error) {
+	switch v := o.(type) {
+
+	case types.StringLiteral, types.HexLiteral:
+		s, err := model.Text(v)
+		if err != nil {
+			return nil, err
+		}
+		return fdfString(s)
+
+	case types.Array:
+		arr := types.Array{}
+		for _, o := range v {
+			o, err := fdfValue(o)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, o)
+		}
+		return arr, nil
+	}
+
+	return o, nil
+}
+
+func fdfFieldDicts(ff []*FDFField) (types.Array, error) {
+	arr := types.Array{}
+
+	for _, f := range ff {
+		t, err := fdfString(f.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		d := types.Dict{"T": t}
+
+		if f.Value != nil {
+			if d["V"], err = fdfValue(f.Value); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(f.Kids) > 0 {
+			if d["Kids"], err = fdfFieldDicts(f.Kids); err != nil {
+				return nil, err
+			}
+		}
+
+		arr = append(arr, d)
+	}
+
+	return arr, nil
+}
+
+// Write writes fdf as FDF file to w.
+func (fdf *FDF) Write(w io.Writer) error {
+	fields, err := fdfFieldDicts(fdf.Fields)
+	if err != nil {
+		return err
+	}
+
+	d := types.Dict{"Fields": fields}
+
+	if fdf.F != "" {
+		if d["F"], err = fdfString(fdf.F); err != nil {
+			return err
+		}
+	}
+
+	root := types.Dict{"FDF": d}
+
+	var b bytes.Buffer
+	b.WriteString("%FDF-1.2\n%\xe2\xe3\xcf\xd3\n")
+	fmt.Fprintf(&b, "1 0 obj\n%s\nendobj\n", root.PDFString())
+	b.WriteString("trailer\n<</Root 1 0 R>>\n%%EOF\n")
+
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// fdfFields returns the named fields of arr as FDF field hierarchy.
+func fdfFields(xRefTable *model.XRefTable, arr types.Array) ([]*FDFField, error) {
+	var ff []*FDFField
+
+	for _, o := range arr {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return nil, err
+		}
+		if len(d) == 0 {
+			continue
+		}
+
+		t, err := d.StringOrHexLiteralEntry("T")
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			// Widget annotation
+			continue
+		}
+
+		f := &FDFField{Name: *t}
+
+		if o, found := d.Find("Kids"); found {
+			kids, err := xRefTable.DereferenceArray(o)
+			if err != nil {
+				return nil, err
+			}
+			if f.Kids, err = fdfFields(xRefTable, kids); err != nil {
+				return nil, err
+			}
+		}
+
+		if f.Value, err = fieldValue(xRefTable, d); err != nil {
+			return nil, err
+		}
+
+		ff = append(ff, f)
+	}
+
+	return ff, nil
+}
+
+// ExportFormFDF extracts form data originating from source from xRefTable and writes an FDF representation to w.
+func ExportFormFDF(xRefTable *model.XRefTable, source string, w io.Writer) (bool, error) {
+	fields, err := Fields(xRefTable)
+	if err != nil {
+		return false, err
+	}
+
+	ff, err := fdfFields(xRefTable, fields)
+	if err != nil || len(ff) == 0 {
+		return false, err
+	}
+
+	fdf := &FDF{Fields: ff}
+	if source != "" {
+		fdf.F = filepath.Base(source)
+	}
+
+	return true, fdf.Write(w)
+}
+
+// fdfFieldValueMap maps fully qualified field names to FDF field values.
+func fdfFieldValueMap(ff []*FDFField, prefix string, m map[string][]string) error {
+	for _, f := range ff {
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if f.Value != nil {
+			vv, err := valueStrings(f.Value)
+			if err != nil {
+				return errors.Wrapf(ErrInvalidFDF, "field %s: %v", name, err)
+			}
+			m[name] = vv
+		}
+		if err := fdfFieldValueMap(f.Kids, name, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormForFDF returns the fields of xRefTable's form with values provided by fdf.
+// Fields not covered by fdf are not part of the result.
+func FormForFDF(xRefTable *model.XRefTable, fdf *FDF) (*Form, bool, error) {
+	m := map[string][]string{}
+	if err := fdfFieldValueMap(fdf.Fields, "", m); err != nil {
+		return nil, false, err
+	}
+	if len(m) == 0 {
+		return nil, false, nil
+	}
+
+	return formForFieldValues(xRefTable, m)
+}
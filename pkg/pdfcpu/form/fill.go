@@ -35,6 +35,8 @@ type DataFormat int
 const (
 	CSV DataFormat = iota
 	JSON
+	XML
+	XLSX
 )
 
 func cacheResIDs(ctx *model.Context, pdf *primitives.PDF) error {
@@ -966,6 +968,21 @@ func fillTextField(
 		return nil
 	}
 
+	vDisplay, err := textFieldDisplayValue(ctx.XRefTable, d, name, vNew)
+	if err != nil {
+		return err
+	}
+
+	if err := setTextFieldValue(ctx, d, vNew, vDisplay, ff, fonts); err != nil {
+		return err
+	}
+
+	*ok = true
+	return nil
+}
+
+// setTextFieldValue sets the value of the text field d and renders vDisplay into its appearance streams.
+func setTextFieldValue(ctx *model.Context, d types.Dict, vNew, vDisplay string, ff *int, fonts map[string]types.IndirectRef) error {
 	s, err := types.EscapedUTF16String(vNew)
 	if err != nil {
 		return err
@@ -994,22 +1011,15 @@ func fillTextField(
 				return err
 			}
 
-			if err := primitives.EnsureTextFieldAP(ctx, d, vNew, multiLine, comb, maxLen, da, fonts); err != nil {
+			if err := primitives.EnsureTextFieldAP(ctx, d, vDisplay, multiLine, comb, maxLen, da, fonts); err != nil {
 				return err
 			}
-
-			*ok = true
 		}
 
 		return nil
 	}
 
-	if err := primitives.EnsureTextFieldAP(ctx, d, vNew, multiLine, comb, maxLen, da, fonts); err != nil {
-		return err
-	}
-
-	*ok = true
-	return nil
+	return primitives.EnsureTextFieldAP(ctx, d, vDisplay, multiLine, comb, maxLen, da, fonts)
 }
 
 func fillTx(
@@ -1177,6 +1187,12 @@ func FillForm(
 		}
 	}
 
+	if ok {
+		if err := calculateFields(ctx, fields, fonts); err != nil {
+			return false, nil, err
+		}
+	}
+
 	for fName, indRef := range fonts {
 		if len(ctx.UsedGIDs[fName]) == 0 {
 			continue
@@ -1212,3 +1228,140 @@ func FillForm(
 
 	return ok, pages, nil
 }
+
+// optPairsByName returns the option pairs of all choice fields by fully qualified field name.
+func optPairsByName(xRefTable *model.XRefTable, fields types.Array, prefix string, m map[string][][2]string) error {
+	for _, o := range fields {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if len(d) == 0 {
+			continue
+		}
+
+		name := prefix
+		t, err := d.StringOrHexLiteralEntry("T")
+		if err != nil {
+			return err
+		}
+		if t != nil && *t != "" {
+			if name != "" {
+				name += "."
+			}
+			name += *t
+		}
+
+		pairs, err := optPairs(xRefTable, d)
+		if err != nil {
+			return err
+		}
+		if len(pairs) > 0 {
+			m[name] = pairs
+		}
+
+		if o, found := d.Find("Kids"); found {
+			kids, err := xRefTable.DereferenceArray(o)
+			if err != nil {
+				return err
+			}
+			if err := optPairsByName(xRefTable, kids, name, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// formForFieldValues returns the fields of xRefTable's form with values taken from m,
+// which maps fully qualified field names to values as exchanged via FDF or XFDF.
+// Fields not covered by m are not part of the result.
+func formForFieldValues(xRefTable *model.XRefTable, m map[string][]string) (*Form, bool, error) {
+	fields, err := Fields(xRefTable)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pairs := map[string][][2]string{}
+	if err := optPairsByName(xRefTable, fields, "", pairs); err != nil {
+		return nil, false, err
+	}
+
+	formGroup, ok, err := ExportForm(xRefTable, "")
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	f0 := formGroup.Forms[0]
+
+	f := &Form{}
+
+	for _, tf := range f0.TextFields {
+		if vv, found := m[tf.Name]; found && len(vv) > 0 {
+			tf.Value = vv[0]
+			f.TextFields = append(f.TextFields, tf)
+		}
+	}
+
+	for _, df := range f0.DateFields {
+		if vv, found := m[df.Name]; found && len(vv) > 0 {
+			df.Value = vv[0]
+			f.DateFields = append(f.DateFields, df)
+		}
+	}
+
+	for _, cb := range f0.CheckBoxes {
+		if vv, found := m[cb.Name]; found && len(vv) > 0 {
+			// Any on state name checks the box.
+			cb.Value = vv[0] != "" && vv[0] != "Off"
+			f.CheckBoxes = append(f.CheckBoxes, cb)
+		}
+	}
+
+	for _, rbg := range f0.RadioButtonGroups {
+		if vv, found := m[rbg.Name]; found && len(vv) > 0 {
+			rbg.Value = radioButtonGroupValue(rbg.Options, vv[0])
+			f.RadioButtonGroups = append(f.RadioButtonGroups, rbg)
+		}
+	}
+
+	for _, cb := range f0.ComboBoxes {
+		if vv, found := m[cb.Name]; found && len(vv) > 0 {
+			cb.Value = displayValue(pairs[cb.Name], vv[0])
+			f.ComboBoxes = append(f.ComboBoxes, cb)
+		}
+	}
+
+	for _, lb := range f0.ListBoxes {
+		if vv, found := m[lb.Name]; found {
+			lb.Values = nil
+			for _, v := range vv {
+				if v != "" {
+					lb.Values = append(lb.Values, displayValue(pairs[lb.Name], v))
+				}
+			}
+			f.ListBoxes = append(f.ListBoxes, lb)
+		}
+	}
+
+	ok = len(f.TextFields)+len(f.DateFields)+len(f.CheckBoxes)+len(f.RadioButtonGroups)+len(f.ComboBoxes)+len(f.ListBoxes) > 0
+
+	return f, ok, nil
+}
+
+// radioButtonGroupValue returns the option selected by the state name v.
+// For groups using /Opt the state names are the indices of the options.
+func radioButtonGroupValue(opts []string, v string) string {
+	if v == "Off" {
+		return ""
+	}
+	for _, o := range opts {
+		if o == v {
+			return v
+		}
+	}
+	if i, err := strconv.Atoi(v); err == nil && i >= 0 && i < len(opts) {
+		return opts[i]
+	}
+	return v
+}
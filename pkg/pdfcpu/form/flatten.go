@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/primitives"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+func hasNormalAppearance(xRefTable *model.XRefTable, d types.Dict) (bool, error) {
+	ap, err := xRefTable.DereferenceDict(d["AP"])
+	if err != nil || ap == nil {
+		return false, err
+	}
+	_, found := ap.Find("N")
+	return found, nil
+}
+
+// ensureWidgetAP generates a missing appearance stream for the widget wd of the field fd.
+func ensureWidgetAP(ctx *model.Context, wd, fd types.Dict, ft string, fonts map[string]types.IndirectRef) error {
+	ok, err := hasNormalAppearance(ctx.XRefTable, wd)
+	if err != nil || ok {
+		return err
+	}
+
+	da := fd.StringEntry("DA")
+	if da == nil {
+		da = wd.StringEntry("DA")
+	}
+
+	ff := fd.IntEntry("Ff")
+
+	switch ft {
+
+	case "Tx":
+		s, err := getV(ctx.XRefTable, fd)
+		if err != nil {
+			return err
+		}
+		if _, err := primitives.DateFormatForDate(s); s != "" && err == nil {
+			return primitives.EnsureDateFieldAP(ctx, wd, s, da, fonts)
+		}
+		multiLine := ff != nil && primitives.FieldFlags(*ff)&primitives.FieldMultiline > 0
+		comb := ff != nil && primitives.FieldFlags(*ff)&primitives.FieldComb > 0
+		maxLen := 0
+		if i := fd.IntEntry("MaxLen"); i != nil {
+			maxLen = *i
+		}
+		return primitives.EnsureTextFieldAP(ctx, wd, s, multiLine, comb, maxLen, da, fonts)
+
+	case "Ch":
+		if ff != nil && primitives.FieldFlags(*ff)&primitives.FieldCombo > 0 {
+			s, err := getV(ctx.XRefTable, fd)
+			if err != nil {
+				return err
+			}
+			return primitives.EnsureComboBoxAP(ctx, wd, s, da, fonts)
+		}
+		opts, err := parseOptions(ctx.XRefTable, fd, OPTIONAL)
+		if err != nil {
+			return err
+		}
+		return primitives.EnsureListBoxAP(ctx, wd, opts, fd.ArrayEntry("I"), da, fonts)
+	}
+
+	// Buttons without appearance streams have nothing to render.
+	return nil
+}
+
+func flattenPageWidgets(
+	ctx *model.Context,
+	pageNr int,
+	fieldIDsOrNames []string,
+	fields types.Array,
+	fonts map[string]types.IndirectRef,
+	flattened map[string]bool) error {
+
+	pageDict, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+
+	o, found := pageDict.Find("Annots")
+	if !found {
+		return nil
+	}
+
+	annots, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	var dd []types.Dict
+
+	for _, o := range annots {
+
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+
+		wd, err := ctx.DereferenceDict(ir)
+		if err != nil {
+			return err
+		}
+		if wd == nil || wd.Subtype() == nil || *wd.Subtype() != "Widget" {
+			continue
+		}
+
+		found, fi, err := isField(ctx.XRefTable, ir, fields)
+		if err != nil {
+			return err
+		}
+		if !found || !matchField(fi, fieldIDsOrNames) {
+			continue
+		}
+
+		fd := wd
+		if fi.indRef != nil {
+			if fd, err = ctx.DereferenceDict(*fi.indRef); err != nil {
+				return err
+			}
+		}
+
+		if fi.ft != nil {
+			if err := ensureWidgetAP(ctx, wd, fd, *fi.ft, fonts); err != nil {
+				return err
+			}
+		}
+
+		dd = append(dd, wd)
+
+		if fi.id != "" {
+			flattened[fi.id] = true
+		}
+	}
+
+	if len(dd) == 0 {
+		return nil
+	}
+
+	_, err = ctx.FlattenAppearances(pageNr, dd)
+
+	return err
+}
+
+func updateUserfonts(ctx *model.Context, fonts map[string]types.IndirectRef) error {
+	for fName, indRef := range fonts {
+		if len(ctx.UsedGIDs[fName]) == 0 {
+			continue
+		}
+		fDict, err := ctx.DereferenceDict(indRef)
+		if err != nil {
+			return err
+		}
+		fr := model.FontResource{}
+		if err := pdffont.IndRefsForUserfontUpdate(ctx.XRefTable, fDict, "", &fr); err != nil {
+			return pdffont.ErrCorruptFontDict
+		}
+		if err := pdffont.UpdateUserfont(ctx.XRefTable, fName, fr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlattenForm merges the appearance of all form fields contained in fieldIDsOrNames into page content and removes the fields.
+// An empty fieldIDsOrNames flattens the whole form.
+// Widgets which are not printable are removed without being rendered, see model.AnnotationPrintable.
+func FlattenForm(ctx *model.Context, fieldIDsOrNames []string) (bool, error) {
+	xRefTable := ctx.XRefTable
+
+	fields, err := Fields(xRefTable)
+	if err != nil {
+		return false, err
+	}
+
+	fonts := map[string]types.IndirectRef{}
+	flattened := map[string]bool{}
+
+	for i := 1; i <= xRefTable.PageCount; i++ {
+		if err := flattenPageWidgets(ctx, i, fieldIDsOrNames, fields, fonts, flattened); err != nil {
+			return false, err
+		}
+	}
+
+	if len(flattened) == 0 {
+		return false, nil
+	}
+
+	if err := updateUserfonts(ctx, fonts); err != nil {
+		return false, err
+	}
+
+	// Selectively flattened fields are removed by id.
+	var ids []string
+	if len(fieldIDsOrNames) > 0 {
+		for id := range flattened {
+			ids = append(ids, id)
+		}
+	}
+
+	ok, err := RemoveFormFields(ctx, ids)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, errors.New("pdfcpu: unable to remove flattened form fields")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/primitives"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// textFieldDisplayValue validates v against the range validation script of the text field d
+// and returns v formatted as specified by its format script.
+func textFieldDisplayValue(xRefTable *model.XRefTable, d types.Dict, id, v string) (string, error) {
+	fs, err := primitives.ParseFieldScripts(xRefTable, d)
+	if err != nil || fs == nil {
+		return v, err
+	}
+
+	if fs.Range != nil {
+		if err := fs.Range.Validate(v); err != nil {
+			return "", errors.Errorf("pdfcpu: field: %s %v", id, err)
+		}
+	}
+
+	if fs.Format != nil {
+		s, err := fs.Format.Apply(v)
+		if err != nil {
+			return "", errors.Errorf("pdfcpu: field: %s %v", id, err)
+		}
+		return s, nil
+	}
+
+	return v, nil
+}
+
+// fieldDictsByName maps fully qualified field names to field dicts.
+func fieldDictsByName(xRefTable *model.XRefTable, fields types.Array, prefix string, m map[string]types.Dict) error {
+	for _, o := range fields {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if len(d) == 0 {
+			continue
+		}
+
+		t, err := d.StringOrHexLiteralEntry("T")
+		if err != nil {
+			return err
+		}
+		if t == nil || *t == "" {
+			// Widget annotation
+			continue
+		}
+
+		name := *t
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		m[name] = d
+
+		if o, found := d.Find("Kids"); found {
+			kids, err := xRefTable.DereferenceArray(o)
+			if err != nil {
+				return err
+			}
+			if err := fieldDictsByName(xRefTable, kids, name, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// calculateFields updates all calculated fields in calculation order.
+func calculateFields(ctx *model.Context, fields types.Array, fonts map[string]types.IndirectRef) error {
+	xRefTable := ctx.XRefTable
+
+	co, err := xRefTable.DereferenceArray(xRefTable.Form["CO"])
+	if err != nil || len(co) == 0 {
+		return err
+	}
+
+	m := map[string]types.Dict{}
+	if err := fieldDictsByName(xRefTable, fields, "", m); err != nil {
+		return err
+	}
+
+	for _, o := range co {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if len(d) == 0 {
+			continue
+		}
+
+		fs, err := primitives.ParseFieldScripts(xRefTable, d)
+		if err != nil {
+			return err
+		}
+		if fs == nil || fs.Calc == nil {
+			continue
+		}
+
+		id := ""
+		if t, _ := d.StringOrHexLiteralEntry("T"); t != nil {
+			id = *t
+		}
+
+		var ff []float64
+		for _, name := range fs.Calc.Fields {
+			d1, found := m[name]
+			if !found {
+				continue
+			}
+			v, err := getV(xRefTable, d1)
+			if err != nil {
+				return err
+			}
+			f, err := primitives.ParseNumber(v)
+			if err != nil {
+				return errors.Errorf("pdfcpu: field: %s %v", name, err)
+			}
+			ff = append(ff, f)
+		}
+
+		vNew := primitives.FormatNumber(fs.Calc.Calculate(ff))
+
+		vOld, err := getV(xRefTable, d)
+		if err != nil {
+			return err
+		}
+		if vNew == vOld {
+			continue
+		}
+
+		vDisplay := vNew
+		if fs.Format != nil {
+			if vDisplay, err = fs.Format.Apply(vNew); err != nil {
+				return errors.Errorf("pdfcpu: field: %s %v", id, err)
+			}
+		}
+
+		if err := setTextFieldValue(ctx, d, vNew, vDisplay, d.IntEntry("Ff"), fonts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
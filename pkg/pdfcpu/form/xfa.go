@@ -0,0 +1,642 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNoXFA      = errors.New("pdfcpu: no XFA form available")
+	ErrInvalidXFA = errors.New("pdfcpu: invalid XFA")
+)
+
+const xfaDataNamespace = "http://www.xfa.org/schema/xfa-data/1.0/"
+
+var xfaIndexRegExp = regexp.MustCompile(`\[\d+\]`)
+
+// XFAPacket represents a packet of an XML Data Package (XDP) like template, datasets or config.
+type XFAPacket struct {
+	Name    string
+	Content []byte
+}
+
+// XFA represents the XML Forms Architecture part of a form.
+type XFA struct {
+	Packets []XFAPacket
+	Dynamic bool // The viewer renders the pages from the template (NeedsRendering).
+	Fields  int  // The number of AcroForm fields available as fallback.
+	array   bool // The XDP is split into packets by the XFA array.
+}
+
+// Hybrid returns true if the XFA form comes with AcroForm fields.
+func (xfa XFA) Hybrid() bool {
+	return xfa.Fields > 0
+}
+
+// Kind returns "dynamic" or "static".
+func (xfa XFA) Kind() string {
+	if xfa.Dynamic {
+		return "dynamic"
+	}
+	return "static"
+}
+
+// Packet returns the packet named name.
+func (xfa XFA) Packet(name string) *XFAPacket {
+	for i, p := range xfa.Packets {
+		if p.Name == name {
+			return &xfa.Packets[i]
+		}
+	}
+	return nil
+}
+
+// XDP returns the complete XML Data Package.
+func (xfa XFA) XDP() []byte {
+	var b bytes.Buffer
+	for _, p := range xfa.Packets {
+		b.Write(p.Content)
+	}
+	return b.Bytes()
+}
+
+func acroFormDict(xRefTable *model.XRefTable) (types.Dict, error) {
+	rootDict, err := xRefTable.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := rootDict.Find("AcroForm")
+	if !found {
+		return nil, nil
+	}
+
+	return xRefTable.DereferenceDict(o)
+}
+
+func xfaStreamContent(xRefTable *model.XRefTable, o types.Object) ([]byte, error) {
+	sd, _, err := xRefTable.DereferenceStreamDict(o)
+	if err != nil {
+		return nil, err
+	}
+	if sd == nil {
+		return nil, errors.Wrap(ErrInvalidXFA, "missing stream")
+	}
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+	return sd.Content, nil
+}
+
+// splitXDP splits an XML Data Package into its packets.
+// Anything preceding the first packet is the preamble, anything following the last packet is the postamble.
+func splitXDP(bb []byte) ([]XFAPacket, error) {
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+
+	var (
+		pp         []XFAPacket
+		depth      int
+		start, end int64
+		name       string
+	)
+
+	for {
+		off := dec.InputOffset()
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidXFA, err.Error())
+		}
+
+		switch t := t.(type) {
+
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				if len(pp) == 0 {
+					pp = append(pp, XFAPacket{Name: "preamble", Content: bb[:off]})
+				} else {
+					// Whitespace following the previous packet
+					pp[len(pp)-1].Content = bb[start:off]
+				}
+				start, name = off, t.Name.Local
+			}
+
+		case xml.EndElement:
+			if depth == 2 {
+				end = dec.InputOffset()
+				pp = append(pp, XFAPacket{Name: name, Content: bb[start:end]})
+			}
+			depth--
+		}
+	}
+
+	if len(pp) == 0 {
+		return nil, errors.Wrap(ErrInvalidXFA, "no packets")
+	}
+
+	pp = append(pp, XFAPacket{Name: "postamble", Content: bb[end:]})
+
+	return pp, nil
+}
+
+// ReadXFA returns the XFA part of xRefTable's form.
+func ReadXFA(xRefTable *model.XRefTable) (*XFA, error) {
+	d, err := acroFormDict(xRefTable)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrNoXFA
+	}
+
+	o, found := d.Find("XFA")
+	if !found {
+		return nil, ErrNoXFA
+	}
+
+	o, err = xRefTable.Dereference(o)
+	if err != nil {
+		return nil, err
+	}
+
+	xfa := &XFA{}
+
+	switch o := o.(type) {
+
+	case types.StreamDict:
+		bb, err := xfaStreamContent(xRefTable, o)
+		if err != nil {
+			return nil, err
+		}
+		if xfa.Packets, err = splitXDP(bb); err != nil {
+			return nil, err
+		}
+
+	case types.Array:
+		xfa.array = true
+		for i := 0; i+1 < len(o); i += 2 {
+			name, err := xRefTable.DereferenceText(o[i])
+			if err != nil {
+				return nil, err
+			}
+			bb, err := xfaStreamContent(xRefTable, o[i+1])
+			if err != nil {
+				return nil, err
+			}
+			xfa.Packets = append(xfa.Packets, XFAPacket{Name: name, Content: bb})
+		}
+
+	default:
+		return nil, errors.Wrap(ErrInvalidXFA, "corrupt XFA entry")
+	}
+
+	rootDict, err := xRefTable.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	if b := rootDict.BooleanEntry("NeedsRendering"); b != nil {
+		xfa.Dynamic = *b
+	}
+
+	if o, found := d.Find("Fields"); found {
+		fields, err := xRefTable.DereferenceArray(o)
+		if err != nil {
+			return nil, err
+		}
+		xfa.Fields = len(fields)
+	}
+
+	return xfa, nil
+}
+
+// ListXFA returns a description of the XFA part of xRefTable's form.
+func ListXFA(xRefTable *model.XRefTable) ([]string, error) {
+	xfa, err := ReadXFA(xRefTable)
+	if err != nil {
+		return nil, err
+	}
+
+	s := fmt.Sprintf("XFA form: %s", xfa.Kind())
+	if xfa.Hybrid() {
+		s += fmt.Sprintf(", hybrid (%d AcroForm fields)", xfa.Fields)
+	} else {
+		s += ", no AcroForm fallback"
+	}
+
+	ss := []string{s, "", "Packets:"}
+
+	for _, p := range xfa.Packets {
+		ss = append(ss, fmt.Sprintf("  %-12s %8d bytes", p.Name, len(p.Content)))
+	}
+
+	return ss, nil
+}
+
+// RemoveXFA removes the XFA part of xRefTable's form so viewers fall back to the AcroForm fields.
+func RemoveXFA(xRefTable *model.XRefTable) (bool, error) {
+	xfa, err := ReadXFA(xRefTable)
+	if err != nil {
+		if err == ErrNoXFA {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if xfa.Dynamic && !xfa.Hybrid() {
+		return false, errors.New("pdfcpu: dynamic XFA form without AcroForm fallback")
+	}
+
+	d, err := acroFormDict(xRefTable)
+	if err != nil {
+		return false, err
+	}
+	d.Delete("XFA")
+
+	rootDict, err := xRefTable.Catalog()
+	if err != nil {
+		return false, err
+	}
+	rootDict.Delete("NeedsRendering")
+
+	return true, nil
+}
+
+// writeXFA replaces the XFA part of xRefTable's form by xfa.
+func writeXFA(xRefTable *model.XRefTable, xfa *XFA) error {
+	d, err := acroFormDict(xRefTable)
+	if err != nil {
+		return err
+	}
+
+	newStream := func(bb []byte) (*types.IndirectRef, error) {
+		sd, err := xRefTable.NewStreamDictForBuf(bb)
+		if err != nil {
+			return nil, err
+		}
+		if err := sd.Encode(); err != nil {
+			return nil, err
+		}
+		return xRefTable.IndRefForNewObject(*sd)
+	}
+
+	if !xfa.array {
+		indRef, err := newStream(xfa.XDP())
+		if err != nil {
+			return err
+		}
+		d["XFA"] = *indRef
+		return nil
+	}
+
+	arr := types.Array{}
+	for _, p := range xfa.Packets {
+		indRef, err := newStream(p.Content)
+		if err != nil {
+			return err
+		}
+		arr = append(arr, types.StringLiteral(p.Name), *indRef)
+	}
+	d["XFA"] = arr
+
+	return nil
+}
+
+// xfaData returns the datasets packet for the XFA data read from rd.
+// rd may contain an xfa:datasets packet, an xfa:data element or the data root element.
+func xfaData(bb []byte) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+
+	var (
+		root xml.StartElement
+		off  int64
+	)
+
+	for {
+		off = dec.InputOffset()
+		t, err := dec.Token()
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidXFA, "missing data root element")
+		}
+		if se, ok := t.(xml.StartElement); ok {
+			root = se
+			break
+		}
+	}
+
+	// Verify well-formedness.
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(ErrInvalidXFA, err.Error())
+		}
+	}
+
+	s := strings.TrimSpace(string(bb[off:]))
+
+	switch root.Name.Local {
+	case "datasets":
+		return []byte(s), nil
+	case "data":
+		return []byte(`<xfa:datasets xmlns:xfa="` + xfaDataNamespace + `">` + s + `</xfa:datasets>`), nil
+	}
+
+	return []byte(`<xfa:datasets xmlns:xfa="` + xfaDataNamespace + `"><xfa:data>` + s + `</xfa:data></xfa:datasets>`), nil
+}
+
+func jsonToXML(dec *json.Decoder, name string, b *bytes.Buffer) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := t.(type) {
+
+	case json.Delim:
+		switch t {
+
+		case '{':
+			b.WriteString("<" + name + ">")
+			for dec.More() {
+				t, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := jsonToXML(dec, t.(string), b); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			b.WriteString("</" + name + ">")
+
+		case '[':
+			// Repeated elements
+			for dec.More() {
+				if err := jsonToXML(dec, name, b); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+		}
+
+	case nil:
+		b.WriteString("<" + name + "/>")
+
+	case bool:
+		v := "0"
+		if t {
+			v = "1"
+		}
+		b.WriteString("<" + name + ">" + v + "</" + name + ">")
+
+	default:
+		b.WriteString("<" + name + ">")
+		if err := xml.EscapeText(b, []byte(fmt.Sprintf("%v", t))); err != nil {
+			return err
+		}
+		b.WriteString("</" + name + ">")
+	}
+
+	return nil
+}
+
+// xfaDataForJSON converts a JSON object into XFA data.
+// Objects turn into subform elements, arrays into repeated elements.
+func xfaDataForJSON(bb []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bb))
+	dec.UseNumber()
+
+	t, err := dec.Token()
+	if err != nil || t != json.Delim('{') {
+		return nil, errors.Wrap(ErrInvalidXFA, "JSON data must be an object")
+	}
+
+	var b bytes.Buffer
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidXFA, err.Error())
+		}
+		if err := jsonToXML(dec, t.(string), &b); err != nil {
+			return nil, errors.Wrap(ErrInvalidXFA, err.Error())
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// xfaDataValues collects the values of all leaf elements of the datasets packet by path.
+func xfaDataValues(datasets []byte) (map[string][]string, error) {
+	m := map[string][]string{}
+
+	dec := xml.NewDecoder(bytes.NewReader(datasets))
+
+	var (
+		path []string
+		text strings.Builder
+		leaf bool
+	)
+
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidXFA, err.Error())
+		}
+
+		switch t := t.(type) {
+
+		case xml.StartElement:
+			path = append(path, t.Name.Local)
+			text.Reset()
+			leaf = true
+
+		case xml.CharData:
+			text.Write(t)
+
+		case xml.EndElement:
+			if leaf && len(path) > 2 && path[1] == "data" {
+				// Skip xfa:datasets and xfa:data.
+				k := strings.Join(path[2:], ".")
+				m[k] = append(m[k], text.String())
+			}
+			path = path[:len(path)-1]
+			leaf = false
+		}
+	}
+
+	return m, nil
+}
+
+// xfaFieldPath returns the data path of an AcroForm field generated for an XFA form,
+// eg. form1.name for form1[0].#subform[0].name[0]
+func xfaFieldPath(name string) string {
+	var ss []string
+	for _, s := range strings.Split(xfaIndexRegExp.ReplaceAllString(name, ""), ".") {
+		if s != "" && !strings.HasPrefix(s, "#") {
+			ss = append(ss, s)
+		}
+	}
+	return strings.Join(ss, ".")
+}
+
+func leafName(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// formForXFAData returns the AcroForm fields of a hybrid form with values taken from XFA data.
+// Fields are matched by data path or by unique leaf name.
+func formForXFAData(xRefTable *model.XRefTable, datasets []byte) (*Form, bool, error) {
+	values, err := xfaDataValues(datasets)
+	if err != nil {
+		return nil, false, err
+	}
+
+	leaves := map[string][]string{}
+	for k, vv := range values {
+		n := leafName(k)
+		if _, found := leaves[n]; found {
+			// Ambiguous
+			leaves[n] = nil
+			continue
+		}
+		leaves[n] = vv
+	}
+
+	formGroup, ok, err := ExportForm(xRefTable, "")
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var names []string
+	f0 := formGroup.Forms[0]
+	for _, f := range f0.TextFields {
+		names = append(names, f.Name)
+	}
+	for _, f := range f0.DateFields {
+		names = append(names, f.Name)
+	}
+	for _, f := range f0.CheckBoxes {
+		names = append(names, f.Name)
+	}
+	for _, f := range f0.RadioButtonGroups {
+		names = append(names, f.Name)
+	}
+	for _, f := range f0.ComboBoxes {
+		names = append(names, f.Name)
+	}
+	for _, f := range f0.ListBoxes {
+		names = append(names, f.Name)
+	}
+
+	m := map[string][]string{}
+	for _, name := range names {
+		path := xfaFieldPath(name)
+		if vv, found := values[path]; found {
+			m[name] = vv
+			continue
+		}
+		if vv := leaves[leafName(path)]; vv != nil {
+			m[name] = vv
+		}
+	}
+
+	if len(m) == 0 {
+		return nil, false, nil
+	}
+
+	f, ok, err := formForFieldValues(xRefTable, m)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	// XFA checkboxes use 1 and 0.
+	for i, cb := range f.CheckBoxes {
+		if vv := m[cb.Name]; vv[0] == "0" {
+			f.CheckBoxes[i].Value = false
+		}
+	}
+
+	return f, true, nil
+}
+
+// FillXFA replaces the datasets packet of xRefTable's XFA form by data read from rd in XML or JSON format.
+// For hybrid forms the AcroForm fields matching the data are returned for filling.
+func FillXFA(xRefTable *model.XRefTable, rd io.Reader, format DataFormat) (*Form, bool, error) {
+	xfa, err := ReadXFA(xRefTable)
+	if err != nil {
+		return nil, false, err
+	}
+
+	bb, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if format == JSON {
+		if bb, err = xfaDataForJSON(bb); err != nil {
+			return nil, false, err
+		}
+	}
+
+	datasets, err := xfaData(bb)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if p := xfa.Packet("datasets"); p != nil {
+		p.Content = datasets
+	} else {
+		// Insert before postamble.
+		i := len(xfa.Packets)
+		if i > 0 && xfa.Packets[i-1].Name == "postamble" {
+			i--
+		}
+		xfa.Packets = append(xfa.Packets[:i], append([]XFAPacket{{Name: "datasets", Content: datasets}}, xfa.Packets[i:]...)...)
+	}
+
+	if err := writeXFA(xRefTable, xfa); err != nil {
+		return nil, false, err
+	}
+
+	if !xfa.Hybrid() {
+		return nil, false, nil
+	}
+
+	return formForXFAData(xRefTable, datasets)
+}
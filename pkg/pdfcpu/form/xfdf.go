@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// xfdfFields returns the named fields of arr as XFDF field hierarchy.
+func xfdfFields(xRefTable *model.XRefTable, arr types.Array) ([]*model.XFDFField, error) {
+	var ff []*model.XFDFField
+
+	for _, o := range arr {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return nil, err
+		}
+		if len(d) == 0 {
+			continue
+		}
+
+		t, err := d.StringOrHexLiteralEntry("T")
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			// Widget annotation
+			continue
+		}
+
+		f := &model.XFDFField{Name: *t}
+
+		if o, found := d.Find("Kids"); found {
+			kids, err := xRefTable.DereferenceArray(o)
+			if err != nil {
+				return nil, err
+			}
+			if f.Fields, err = xfdfFields(xRefTable, kids); err != nil {
+				return nil, err
+			}
+		}
+
+		v, err := fieldValue(xRefTable, d)
+		if err != nil {
+			return nil, err
+		}
+		if f.Values, err = valueStrings(v); err != nil {
+			return nil, err
+		}
+
+		ff = append(ff, f)
+	}
+
+	return ff, nil
+}
+
+// ExportFormXFDF extracts form data originating from source from xRefTable and writes an XFDF representation to w.
+func ExportFormXFDF(xRefTable *model.XRefTable, source string, w io.Writer) (bool, error) {
+	fields, err := Fields(xRefTable)
+	if err != nil {
+		return false, err
+	}
+
+	ff, err := xfdfFields(xRefTable, fields)
+	if err != nil || len(ff) == 0 {
+		return false, err
+	}
+
+	x := model.NewXFDF(xRefTable, source)
+	x.Fields = &model.XFDFFields{Fields: ff}
+
+	return true, x.Write(w)
+}
+
+// xfdfFieldValueMap maps fully qualified field names to XFDF field values.
+func xfdfFieldValueMap(ff []*model.XFDFField, prefix string, m map[string][]string) {
+	for _, f := range ff {
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if f.Values != nil {
+			m[name] = f.Values
+		}
+		xfdfFieldValueMap(f.Fields, name, m)
+	}
+}
+
+// FormForXFDF returns the fields of xRefTable's form with values provided by x.
+// Fields not covered by x are not part of the result.
+func FormForXFDF(xRefTable *model.XRefTable, x *model.XFDF) (*Form, bool, error) {
+	if x.Fields == nil {
+		return nil, false, nil
+	}
+
+	m := map[string][]string{}
+	xfdfFieldValueMap(x.Fields.Fields, "", m)
+	if len(m) == 0 {
+		return nil, false, nil
+	}
+
+	return formForFieldValues(xRefTable, m)
+}
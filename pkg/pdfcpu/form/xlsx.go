@@ -0,0 +1,520 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/primitives"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidXLSX is returned for input that is not a readable xlsx workbook.
+var ErrInvalidXLSX = errors.New("pdfcpu: invalid xlsx input file")
+
+const (
+	xlsxMaxColumns  = 16384    // XFD
+	xlsxMaxPartSize = 64 << 20 // decompressed size limit of a single zip part
+)
+
+// XLSXCell represents a spreadsheet cell.
+type XLSXCell struct {
+	Value string     // text representation
+	Date  *time.Time // cells formatted as date
+}
+
+type xlsxWorkbook struct {
+	WorkbookPr struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt xlsxRichText) text() string {
+	if len(rt.R) == 0 {
+		return rt.T
+	}
+	var sb strings.Builder
+	for _, r := range rt.R {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	SI []xlsxRichText `xml:"si"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID         int    `xml:"numFmtId,attr"`
+		FormatCode string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string        `xml:"r,attr"`
+			S  int           `xml:"s,attr"`
+			T  string        `xml:"t,attr"`
+			V  string        `xml:"v"`
+			IS *xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxNumFmt int
+
+const (
+	xlsxNumber xlsxNumFmt = iota
+	xlsxDate
+	xlsxTime
+)
+
+type xlsxReader struct {
+	files    map[string]*zip.File
+	date1904 bool
+	strings  []string
+	numFmts  []xlsxNumFmt // by style index
+}
+
+func (r *xlsxReader) unmarshal(name string, v interface{}, required bool) error {
+	f, ok := r.files[name]
+	if !ok {
+		if required {
+			return errors.Wrapf(ErrInvalidXLSX, "missing %s", name)
+		}
+		return nil
+	}
+
+	if f.UncompressedSize64 > xlsxMaxPartSize {
+		return errors.Wrapf(ErrInvalidXLSX, "%s: exceeds %d bytes", name, xlsxMaxPartSize)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Don't trust the size recorded in the zip header.
+	lr := &io.LimitedReader{R: rc, N: xlsxMaxPartSize + 1}
+	if err := xml.NewDecoder(lr).Decode(v); err != nil {
+		if lr.N <= 0 {
+			return errors.Wrapf(ErrInvalidXLSX, "%s: exceeds %d bytes", name, xlsxMaxPartSize)
+		}
+		return errors.Wrapf(ErrInvalidXLSX, "%s: %v", name, err)
+	}
+
+	return nil
+}
+
+// builtInNumFmt returns the kind of the built-in number format id.
+func builtInNumFmt(id int) xlsxNumFmt {
+	switch {
+	case id >= 14 && id <= 17, id == 22, id >= 27 && id <= 31, id >= 34 && id <= 36, id >= 50 && id <= 58:
+		return xlsxDate
+	case id >= 18 && id <= 21, id == 32, id == 33, id >= 45 && id <= 47:
+		return xlsxTime
+	}
+	return xlsxNumber
+}
+
+// customNumFmt returns the kind of the custom number format code.
+func customNumFmt(code string) xlsxNumFmt {
+	var sb strings.Builder
+
+	// Strip literals, colors, conditions and escaped characters.
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '"':
+			if j := strings.IndexByte(code[i+1:], '"'); j >= 0 {
+				i += j + 1
+			}
+		case '[':
+			if j := strings.IndexByte(code[i+1:], ']'); j >= 0 {
+				s := strings.ToLower(code[i+1 : i+1+j])
+				if strings.Trim(s, "hms") == "" {
+					// Elapsed time
+					sb.WriteString(s)
+				}
+				i += j + 1
+			}
+		case '\\', '_', '*':
+			i++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	s := strings.ToLower(sb.String())
+	if strings.ContainsAny(s, "yd") || strings.Contains(s, "mmm") {
+		return xlsxDate
+	}
+	if strings.ContainsAny(s, "hs") {
+		return xlsxTime
+	}
+	return xlsxNumber
+}
+
+func (r *xlsxReader) readStyles() error {
+	var styles xlsxStyles
+	if err := r.unmarshal("xl/styles.xml", &styles, false); err != nil {
+		return err
+	}
+
+	custom := map[int]xlsxNumFmt{}
+	for _, nf := range styles.NumFmts {
+		custom[nf.ID] = customNumFmt(nf.FormatCode)
+	}
+
+	for _, xf := range styles.CellXfs {
+		nf, ok := custom[xf.NumFmtID]
+		if !ok {
+			nf = builtInNumFmt(xf.NumFmtID)
+		}
+		r.numFmts = append(r.numFmts, nf)
+	}
+
+	return nil
+}
+
+func (r *xlsxReader) readSharedStrings() error {
+	var ss xlsxSharedStrings
+	if err := r.unmarshal("xl/sharedStrings.xml", &ss, false); err != nil {
+		return err
+	}
+	for _, si := range ss.SI {
+		r.strings = append(r.strings, si.text())
+	}
+	return nil
+}
+
+// sheetPath returns the zip path of the worksheet identified by name or 1-based number.
+func (r *xlsxReader) sheetPath(wb *xlsxWorkbook, sheet string) (string, error) {
+	if len(wb.Sheets) == 0 {
+		return "", errors.Wrap(ErrInvalidXLSX, "no sheets")
+	}
+
+	i := -1
+	if sheet == "" {
+		i = 0
+	}
+	for j, s := range wb.Sheets {
+		if i < 0 && s.Name == sheet {
+			i = j
+		}
+	}
+	if i < 0 {
+		if j, err := strconv.Atoi(sheet); err == nil && j > 0 && j <= len(wb.Sheets) {
+			i = j - 1
+		}
+	}
+	if i < 0 {
+		return "", errors.Errorf("pdfcpu: xlsx: unknown sheet: %s", sheet)
+	}
+
+	var rels xlsxRelationships
+	if err := r.unmarshal("xl/_rels/workbook.xml.rels", &rels, true); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[i].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", errors.Wrapf(ErrInvalidXLSX, "missing worksheet for sheet: %s", wb.Sheets[i].Name)
+}
+
+// column returns the 0-based column index of the cell reference ref, eg. "C5".
+func column(ref string) (int, error) {
+	col := 0
+	for _, c := range strings.ToUpper(ref) {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		if col > xlsxMaxColumns {
+			return 0, errors.Wrapf(ErrInvalidXLSX, "invalid cell reference: %s", ref)
+		}
+	}
+	return col - 1, nil
+}
+
+// serialTime converts an Excel serial date into time.
+func (r *xlsxReader) serialTime(f float64) time.Time {
+	days := math.Floor(f)
+	secs := math.Round((f - days) * 86400)
+
+	var base time.Time
+	switch {
+	case r.date1904:
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case days < 61:
+		// Excel considers 1900 a leap year.
+		base = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	default:
+		base = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	}
+
+	return base.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second)
+}
+
+func formatTime(t time.Time) string {
+	if t.Second() == 0 {
+		return t.Format("15:04")
+	}
+	return t.Format("15:04:05")
+}
+
+func (r *xlsxReader) cell(t, v string, is *xlsxRichText, style int) (XLSXCell, error) {
+	switch t {
+
+	case "s":
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 || i >= len(r.strings) {
+			return XLSXCell{}, errors.Wrapf(ErrInvalidXLSX, "invalid shared string index: %s", v)
+		}
+		return XLSXCell{Value: r.strings[i]}, nil
+
+	case "inlineStr":
+		if is != nil {
+			return XLSXCell{Value: is.text()}, nil
+		}
+		return XLSXCell{}, nil
+
+	case "b":
+		return XLSXCell{Value: strconv.FormatBool(v == "1")}, nil
+
+	case "d":
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+			if tm, err := time.Parse(layout, v); err == nil {
+				return XLSXCell{Value: v, Date: &tm}, nil
+			}
+		}
+		return XLSXCell{Value: v}, nil
+
+	case "str", "e":
+		return XLSXCell{Value: v}, nil
+	}
+
+	// Number
+	if v == "" {
+		return XLSXCell{}, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return XLSXCell{}, errors.Wrapf(ErrInvalidXLSX, "invalid number: %s", v)
+	}
+
+	nf := xlsxNumber
+	if style >= 0 && style < len(r.numFmts) {
+		nf = r.numFmts[style]
+	}
+
+	switch nf {
+	case xlsxDate:
+		tm := r.serialTime(f)
+		return XLSXCell{Value: tm.Format("2006-01-02"), Date: &tm}, nil
+	case xlsxTime:
+		return XLSXCell{Value: formatTime(r.serialTime(f))}, nil
+	}
+
+	return XLSXCell{Value: strconv.FormatFloat(f, 'f', -1, 64)}, nil
+}
+
+// ReadXLSX returns the cells of a worksheet of the XLSX workbook ra.
+// sheet is a sheet name or a 1-based sheet number; the first sheet is used if sheet is empty.
+// Empty rows are skipped.
+func ReadXLSX(ra io.ReaderAt, size int64, sheet string) ([][]XLSXCell, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidXLSX, err.Error())
+	}
+
+	r := &xlsxReader{files: map[string]*zip.File{}}
+	for _, f := range zr.File {
+		r.files[f.Name] = f
+	}
+
+	var wb xlsxWorkbook
+	if err := r.unmarshal("xl/workbook.xml", &wb, true); err != nil {
+		return nil, err
+	}
+	r.date1904 = wb.WorkbookPr.Date1904 == "1" || wb.WorkbookPr.Date1904 == "true"
+
+	sheetPath, err := r.sheetPath(&wb, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.readSharedStrings(); err != nil {
+		return nil, err
+	}
+
+	if err := r.readStyles(); err != nil {
+		return nil, err
+	}
+
+	var ws xlsxWorksheet
+	if err := r.unmarshal(sheetPath, &ws, true); err != nil {
+		return nil, err
+	}
+
+	var rows [][]XLSXCell
+
+	for _, row := range ws.Rows {
+		var cc []XLSXCell
+		empty := true
+		for i, c := range row.Cells {
+			col := i
+			if c.R != "" {
+				if col, err = column(c.R); err != nil {
+					return nil, err
+				}
+			}
+			if col < len(cc) {
+				return nil, errors.Wrapf(ErrInvalidXLSX, "invalid cell reference: %s", c.R)
+			}
+			cell, err := r.cell(c.T, c.V, c.IS, c.S)
+			if err != nil {
+				return nil, err
+			}
+			for len(cc) < col {
+				cc = append(cc, XLSXCell{})
+			}
+			cc = append(cc, cell)
+			if strings.TrimSpace(cell.Value) != "" {
+				empty = false
+			}
+		}
+		if !empty {
+			rows = append(rows, cc)
+		}
+	}
+
+	return rows, nil
+}
+
+// DateFormats returns the date formats of all date fields of xRefTable's form by field id and name.
+func DateFormats(xRefTable *model.XRefTable) (map[string]*primitives.DateFormat, error) {
+	m := map[string]*primitives.DateFormat{}
+
+	formGroup, ok, err := ExportForm(xRefTable, "")
+	if err != nil || !ok {
+		return m, err
+	}
+
+	for _, df := range formGroup.Forms[0].DateFields {
+		dateFormat, err := primitives.DateFormatForFmtExt(df.Format)
+		if err != nil {
+			continue
+		}
+		m[df.ID] = dateFormat
+		if df.Name != "" {
+			m[df.Name] = dateFormat
+		}
+	}
+
+	return m, nil
+}
+
+// XLSXRecords returns the field names and form records of rows as consumed by FieldMap.
+// The first row identifies the fields via id or name.
+// Date cells are formatted using the date format of the corresponding date field, defaulting to yyyy-mm-dd.
+func XLSXRecords(rows [][]XLSXCell, dateFormats map[string]*primitives.DateFormat) ([]string, [][]string, error) {
+	if len(rows) < 2 {
+		return nil, nil, ErrInvalidXLSX
+	}
+
+	var fieldNames []string
+	for _, c := range rows[0] {
+		fieldNames = append(fieldNames, strings.TrimSpace(c.Value))
+	}
+	for len(fieldNames) > 0 && fieldNames[len(fieldNames)-1] == "" {
+		fieldNames = fieldNames[:len(fieldNames)-1]
+	}
+	if len(fieldNames) == 0 {
+		return nil, nil, ErrInvalidXLSX
+	}
+	for i, s := range fieldNames {
+		if s == "" {
+			return nil, nil, errors.Wrapf(ErrInvalidXLSX, "missing field name in column %d", i+1)
+		}
+	}
+
+	var records [][]string
+
+	for _, row := range rows[1:] {
+		rec := make([]string, len(fieldNames))
+		for i := range fieldNames {
+			if i >= len(row) {
+				break
+			}
+			c := row[i]
+			rec[i] = c.Value
+			if c.Date == nil {
+				continue
+			}
+			layout := "2006-01-02"
+			if df, ok := dateFormats[strings.TrimPrefix(fieldNames[i], "*")]; ok {
+				layout = df.Int
+			}
+			rec[i] = c.Date.Format(layout)
+		}
+		records = append(records, rec)
+	}
+
+	return fieldNames, records, nil
+}
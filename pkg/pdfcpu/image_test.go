@@ -18,19 +18,24 @@ package pdfcpu
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"image"
 	"image/color"
+	"image/gif"
+	"image/jpeg"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pdfcpu/pdfcpu/internal/ccitt"
 	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
+	"golang.org/x/image/bmp"
 )
 
 var inDir, outDir string
@@ -474,3 +479,202 @@ func TestReadWriteJPEG(t *testing.T) {
 	fmt.Printf("fileName: %s\n", fn)
 	// No comparison since JPG is lossy.
 }
+
+type tiffTestPage struct {
+	w, h, compression, photometric, bps, spp, t4Options int
+	dpiX, dpiY                                          int
+	data                                                []byte
+}
+
+// tiffTestFile returns a little endian TIFF file with one single strip image per page.
+func tiffTestFile(pages []tiffTestPage) []byte {
+	le := binary.LittleEndian
+	bb := []byte("II*\x00\x00\x00\x00\x00")
+	next := 4
+
+	for _, p := range pages {
+		stripOff := len(bb)
+		bb = append(bb, p.data...)
+		if len(bb)%2 == 1 {
+			bb = append(bb, 0)
+		}
+		resOff := len(bb)
+		bb = le.AppendUint32(bb, uint32(p.dpiX))
+		bb = le.AppendUint32(bb, 1)
+		bb = le.AppendUint32(bb, uint32(p.dpiY))
+		bb = le.AppendUint32(bb, 1)
+
+		le.PutUint32(bb[next:], uint32(len(bb)))
+
+		entries := [][3]int{ // tag, type, value
+			{256, 4, p.w},
+			{257, 4, p.h},
+			{258, 3, p.bps},
+			{259, 3, p.compression},
+			{262, 3, p.photometric},
+			{273, 4, stripOff},
+			{277, 3, p.spp},
+			{278, 4, p.h},
+			{279, 4, len(p.data)},
+			{282, 5, resOff},
+			{283, 5, resOff + 8},
+			{292, 4, p.t4Options},
+			{296, 3, 2},
+		}
+		bb = le.AppendUint16(bb, uint16(len(entries)))
+		for _, e := range entries {
+			bb = le.AppendUint16(bb, uint16(e[0]))
+			bb = le.AppendUint16(bb, uint16(e[1]))
+			bb = le.AppendUint32(bb, 1)
+			if e[1] == 3 {
+				bb = le.AppendUint16(bb, uint16(e[2]))
+				bb = le.AppendUint16(bb, 0)
+			} else {
+				bb = le.AppendUint32(bb, uint32(e[2]))
+			}
+		}
+		next = len(bb)
+		bb = le.AppendUint32(bb, 0)
+	}
+
+	return bb
+}
+
+func TestImportTIFFPassThrough(t *testing.T) {
+	w, h := 64, 40
+
+	// 1 bit gray, 0 = black
+	bilevel := make([]byte, 8*h)
+	for i := range bilevel {
+		bilevel[i] = byte(0xFF ^ i*37)
+	}
+
+	g4, err := ccitt.Encode(bilevel, ccitt.Params{K: -1, Columns: w, Rows: h})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g3, err := ccitt.Encode(bilevel, ccitt.Params{K: 4, Columns: w, Rows: h, EndOfLine: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jpg bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = byte(i)
+	}
+	if err := jpeg.Encode(&jpg, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tif := tiffTestFile([]tiffTestPage{
+		{w: w, h: h, compression: 4, photometric: 0, bps: 1, spp: 1, dpiX: 204, dpiY: 196, data: g4},
+		{w: w, h: h, compression: 3, photometric: 0, bps: 1, spp: 1, t4Options: 1, dpiX: 204, dpiY: 98, data: g3},
+		{w: w, h: h, compression: 7, photometric: 6, bps: 8, spp: 3, dpiX: 300, dpiY: 300, data: jpg.Bytes()},
+	})
+
+	irs, err := model.CreateImageResources(xRefTable, bytes.NewReader(tif), false, false)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if len(irs) != 3 {
+		t.Fatalf("want 3 pages, got %d", len(irs))
+	}
+
+	for i, want := range []struct {
+		filter     string
+		dpiX, dpiY float64
+		raw        []byte
+	}{
+		{filter.CCITTFax, 204, 196, g4},
+		{filter.CCITTFax, 204, 98, g3},
+		{filter.DCT, 300, 300, jpg.Bytes()},
+	} {
+		ir := irs[i]
+		if ir.Width != w || ir.Height != h || ir.DPIX != want.dpiX || ir.DPIY != want.dpiY {
+			t.Errorf("page %d: got %dx%d at %.0fx%.0f dpi", i+1, ir.Width, ir.Height, ir.DPIX, ir.DPIY)
+		}
+
+		sd, _, err := xRefTable.DereferenceStreamDict(*ir.Res.IndRef)
+		if err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+		if len(sd.FilterPipeline) != 1 || sd.FilterPipeline[0].Name != want.filter {
+			t.Fatalf("page %d: want filter %s, got %v", i+1, want.filter, sd.FilterPipeline)
+		}
+		if !bytes.Equal(sd.Raw, want.raw) {
+			t.Errorf("page %d: image data not passed through", i+1)
+		}
+		if want.filter != filter.CCITTFax {
+			continue
+		}
+
+		if err := sd.Decode(); err != nil {
+			t.Fatalf("page %d: err: %v\n", i+1, err)
+		}
+		if !bytes.Equal(sd.Content, bilevel) {
+			t.Errorf("page %d: decoded image differs", i+1)
+		}
+	}
+}
+
+func TestImportGIFAndBMP(t *testing.T) {
+	pal := color.Palette{color.Black, color.White, color.RGBA{R: 0xFF, A: 0xFF}}
+
+	frame := func(r image.Rectangle, c uint8) *image.Paletted {
+		p := image.NewPaletted(r, pal)
+		for i := range p.Pix {
+			p.Pix[i] = c
+		}
+		return p
+	}
+
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			frame(image.Rect(0, 0, 30, 20), 1),
+			frame(image.Rect(5, 5, 15, 10), 0),
+			frame(image.Rect(10, 10, 20, 20), 2),
+		},
+		Delay:    []int{10, 10, 10},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalNone, gif.DisposalNone},
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	irs, err := model.CreateImageResources(xRefTable, &buf, false, false)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if len(irs) != 3 {
+		t.Fatalf("want one page per frame, got %d", len(irs))
+	}
+	for _, ir := range irs {
+		if ir.Width != 30 || ir.Height != 20 {
+			t.Errorf("want frames rendered on the full canvas, got %dx%d", ir.Width, ir.Height)
+		}
+	}
+
+	// The second frame rendered onto the first one is black and white only.
+	sd, _, err := xRefTable.DereferenceStreamDict(*irs[1].Res.IndRef)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if sd.FilterPipeline[0].Name != filter.CCITTFax {
+		t.Errorf("want bilevel frame encoded with %s, got %s", filter.CCITTFax, sd.FilterPipeline[0].Name)
+	}
+
+	buf.Reset()
+	if err := bmp.Encode(&buf, frame(image.Rect(0, 0, 16, 8), 2)); err != nil {
+		t.Fatal(err)
+	}
+	irs, err = model.CreateImageResources(xRefTable, &buf, false, false)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if len(irs) != 1 || irs[0].Width != 16 || irs[0].Height != 8 {
+		t.Errorf("unexpected BMP import: %v", irs)
+	}
+}
@@ -353,7 +353,14 @@ func NewPagesForImage(xRefTable *model.XRefTable, r io.Reader, parentIndRef *typ
 			return nil, err
 		}
 
-		dim := &types.Dim{Width: float64(imgRes.Width), Height: float64(imgRes.Height)}
+		imgWidth, imgHeight := float64(imgRes.Width), float64(imgRes.Height)
+		if imp.DPI == 0 && imgRes.DPIX > 0 && imgRes.DPIY > 0 {
+			// Respect the resolution of the image which may differ horizontally and vertically (eg. fax).
+			imgWidth *= 72 / imgRes.DPIX
+			imgHeight *= 72 / imgRes.DPIY
+		}
+
+		dim := &types.Dim{Width: imgWidth, Height: imgHeight}
 		if imp.Pos != types.Full {
 			dim = imp.PageDim
 		}
@@ -361,7 +368,7 @@ func NewPagesForImage(xRefTable *model.XRefTable, r io.Reader, parentIndRef *typ
 		mediaBox := types.RectForDim(dim.Width, dim.Height)
 
 		var buf bytes.Buffer
-		importImagePDFBytes(&buf, dim, float64(imgRes.Width), float64(imgRes.Height), imp)
+		importImagePDFBytes(&buf, dim, imgWidth, imgHeight, imp)
 		sd, err := xRefTable.NewStreamDictForBuf(buf.Bytes())
 		if err != nil {
 			return nil, err
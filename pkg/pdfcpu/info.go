@@ -116,7 +116,8 @@ func ensureInfoDict(ctx *model.Context) error {
 	// ModDate		        modified by pdfcpu
 	// Trapped              -
 
-	now := types.DateString(time.Now())
+	t := time.Now()
+	now := types.DateString(t)
 
 	v := "pdfcpu " + model.VersionStr
 
@@ -134,6 +135,10 @@ func ensureInfoDict(ctx *model.Context) error {
 
 		ctx.Info = ir
 
+		if ctx.Conformance.PDFA() {
+			return updatePDFAMetadata(ctx, d, t)
+		}
+
 		return nil
 	}
 
@@ -150,6 +155,11 @@ func ensureInfoDict(ctx *model.Context) error {
 	d.Update("ModDate", types.StringLiteral(now))
 	d.Update("Producer", types.StringLiteral(v))
 
+	if ctx.Conformance.PDFA() {
+		// PDF/A requires the XMP metadata to be in sync with the info dict.
+		return updatePDFAMetadata(ctx, d, t)
+	}
+
 	return nil
 }
 
@@ -313,6 +323,7 @@ type PDFInfo struct {
 	FileName           string                          `json:"source,omitempty"`
 	Version            string                          `json:"version"`
 	PageCount          int                             `json:"pageCount"`
+	SelectedPages      types.IntSet                    `json:"-"`
 	PageBoundaries     []model.PageBoundaries          `json:"-"`
 	Boundaries         map[string]model.PageBoundaries `json:"pageBoundaries,omitempty"`
 	PageDimensions     map[types.Dim]bool              `json:"-"`
@@ -549,6 +560,7 @@ func Info(ctx *model.Context, fileName string, selectedPages types.IntSet, fonts
 	info.Version = (*v).String()
 
 	info.PageCount = ctx.PageCount
+	info.SelectedPages = selectedPages
 
 	// PageBoundaries for selected pages.
 	pbs, err := ctx.PageBoundaries(selectedPages)
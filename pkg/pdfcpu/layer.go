@@ -0,0 +1,1106 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Layers are optional content groups (OCGs).
+// Their default visibility is defined by the default optional content configuration dict (OCProperties/D).
+
+var errNoLayers = errors.New("pdfcpu: no layers available")
+
+// Layer represents an optional content group.
+type Layer struct {
+	ID      int               `json:"id"`   // object number of the OCG dict
+	Name    string            `json:"name"` // name as displayed in the user interface of a PDF processor
+	Visible bool              `json:"visible"`
+	Locked  bool              `json:"locked,omitempty"`
+	Intent  []string          `json:"intent,omitempty"`
+	Usage   map[string]string `json:"usage,omitempty"` // usage category eg. View, Print, Export => summary of the usage dict
+	indRef  types.IndirectRef
+}
+
+// layerAction describes what happens to content belonging to certain optional content.
+type layerAction int
+
+const (
+	layerKeep  layerAction = iota // leave untouched
+	layerStrip                    // keep content, remove optional content markup
+	layerDrop                     // remove content
+)
+
+func ocProperties(ctx *model.Context) (types.Dict, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := rootDict.Find("OCProperties")
+	if !found {
+		return nil, nil
+	}
+
+	return ctx.DereferenceDict(o)
+}
+
+func ensureOCProperties(ctx *model.Context) (types.Dict, error) {
+	d, err := ocProperties(ctx)
+	if err != nil || d != nil {
+		return d, err
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	d = types.Dict(
+		map[string]types.Object{
+			"OCGs": types.Array{},
+			"D": types.Dict(
+				map[string]types.Object{
+					"ON":    types.Array{},
+					"OFF":   types.Array{},
+					"Order": types.Array{},
+				},
+			),
+		},
+	)
+
+	rootDict.Update("OCProperties", d)
+
+	return d, nil
+}
+
+func defaultOCConfig(ctx *model.Context, ocp types.Dict) (types.Dict, error) {
+	o, found := ocp.Find("D")
+	if !found {
+		d := types.Dict{}
+		ocp["D"] = d
+		return d, nil
+	}
+	return ctx.DereferenceDict(o)
+}
+
+func ocgs(ctx *model.Context, ocp types.Dict) (types.Array, error) {
+	o, found := ocp.Find("OCGs")
+	if !found {
+		return nil, nil
+	}
+	return ctx.DereferenceArray(o)
+}
+
+func objNrSet(ctx *model.Context, d types.Dict, key string) (types.IntSet, error) {
+	m := types.IntSet{}
+
+	o, found := d.Find(key)
+	if !found {
+		return m, nil
+	}
+
+	a, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range a {
+		if ir, ok := o.(types.IndirectRef); ok {
+			m[ir.ObjectNumber.Value()] = true
+		}
+	}
+
+	return m, nil
+}
+
+func layerName(ctx *model.Context, d types.Dict) string {
+	o, err := ctx.Dereference(d["Name"])
+	if err != nil || o == nil {
+		return ""
+	}
+	s, err := model.Text(o)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+func layerIntent(d types.Dict) []string {
+	var ss []string
+	switch o := d["Intent"].(type) {
+	case types.Name:
+		ss = append(ss, o.Value())
+	case types.Array:
+		for _, o := range o {
+			if n, ok := o.(types.Name); ok {
+				ss = append(ss, n.Value())
+			}
+		}
+	}
+	return ss
+}
+
+func layerUsage(ctx *model.Context, d types.Dict) (map[string]string, error) {
+	o, found := d.Find("Usage")
+	if !found {
+		return nil, nil
+	}
+
+	d, err := ctx.DereferenceDict(o)
+	if err != nil || len(d) == 0 {
+		return nil, err
+	}
+
+	m := map[string]string{}
+
+	for cat, o := range d {
+		d1, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return nil, err
+		}
+		ss := []string{}
+		for k, v := range d1 {
+			v, err := ctx.Dereference(v)
+			if err != nil {
+				return nil, err
+			}
+			s := ""
+			switch v := v.(type) {
+			case types.Name:
+				s = v.Value()
+			case types.StringLiteral, types.HexLiteral:
+				s, _ = model.Text(v)
+			case nil:
+			default:
+				s = v.PDFString()
+			}
+			ss = append(ss, k+"="+s)
+		}
+		sort.Strings(ss)
+		m[cat] = strings.Join(ss, " ")
+	}
+
+	return m, nil
+}
+
+// Layers returns all layers of ctx along with their default visibility.
+func Layers(ctx *model.Context) ([]Layer, error) {
+	ocp, err := ocProperties(ctx)
+	if err != nil || ocp == nil {
+		return nil, err
+	}
+
+	a, err := ocgs(ctx, ocp)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, err := defaultOCConfig(ctx, ocp)
+	if err != nil {
+		return nil, err
+	}
+
+	on, err := objNrSet(ctx, dc, "ON")
+	if err != nil {
+		return nil, err
+	}
+
+	off, err := objNrSet(ctx, dc, "OFF")
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := objNrSet(ctx, dc, "Locked")
+	if err != nil {
+		return nil, err
+	}
+
+	baseStateOn := true
+	if bs := dc.NameEntry("BaseState"); bs != nil && *bs == "OFF" {
+		baseStateOn = false
+	}
+
+	ll := []Layer{}
+
+	for _, o := range a {
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			continue
+		}
+
+		objNr := ir.ObjectNumber.Value()
+
+		l := Layer{
+			indRef:  ir,
+			ID:      objNr,
+			Name:    layerName(ctx, d),
+			Visible: (baseStateOn || on[objNr]) && !off[objNr],
+			Locked:  locked[objNr],
+			Intent:  layerIntent(d),
+		}
+
+		if l.Usage, err = layerUsage(ctx, d); err != nil {
+			return nil, err
+		}
+
+		ll = append(ll, l)
+	}
+
+	return ll, nil
+}
+
+// LayerList returns a list of all layers of ctx.
+func LayerList(ctx *model.Context) ([]string, error) {
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ll) == 0 {
+		return []string{"no layers available"}, nil
+	}
+
+	maxLen := len("Name")
+	for _, l := range ll {
+		if len(l.Name) > maxLen {
+			maxLen = len(l.Name)
+		}
+	}
+
+	ss := []string{}
+	ss = append(ss, fmt.Sprintf("   id | %s | visible | locked | usage", fmt.Sprintf("%-*s", maxLen, "Name")))
+	ss = append(ss, strings.Repeat("=", 35+maxLen))
+
+	for _, l := range ll {
+		var usage []string
+		for k, v := range l.Usage {
+			usage = append(usage, fmt.Sprintf("%s(%s)", k, v))
+		}
+		sort.Strings(usage)
+		ss = append(ss, fmt.Sprintf("%5d | %-*s | %-7t | %-6t | %s", l.ID, maxLen, l.Name, l.Visible, l.Locked, strings.Join(usage, " ")))
+	}
+
+	return ss, nil
+}
+
+// findLayers returns all layers with given names.
+func findLayers(ctx *model.Context, names []string) ([]Layer, error) {
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ll) == 0 {
+		return nil, errNoLayers
+	}
+
+	if len(names) == 0 {
+		return ll, nil
+	}
+
+	m := types.IntSet{}
+
+	for _, name := range names {
+		found := false
+		for _, l := range ll {
+			if l.Name == name {
+				m[l.ID] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("pdfcpu: unknown layer: %s", name)
+		}
+	}
+
+	var ll1 []Layer
+	for _, l := range ll {
+		if m[l.ID] {
+			ll1 = append(ll1, l)
+		}
+	}
+
+	return ll1, nil
+}
+
+func layerObjNrs(ll []Layer) types.IntSet {
+	m := types.IntSet{}
+	for _, l := range ll {
+		m[l.ID] = true
+	}
+	return m
+}
+
+func newLayer(ctx *model.Context, name string) (*types.IndirectRef, error) {
+	s, err := types.EscapedUTF16String(name)
+	if err != nil {
+		return nil, err
+	}
+
+	d := types.Dict(
+		map[string]types.Object{
+			"Type": types.Name("OCG"),
+			"Name": types.StringLiteral(*s),
+		},
+	)
+
+	return ctx.IndRefForNewObject(d)
+}
+
+func appendToConfigArray(ctx *model.Context, d types.Dict, key string, ir types.IndirectRef) error {
+	o, found := d.Find(key)
+	if !found {
+		d[key] = types.Array{ir}
+		return nil
+	}
+
+	a, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	d[key] = append(a, ir)
+
+	return nil
+}
+
+func addLayer(ctx *model.Context, name string, visible bool) (*types.IndirectRef, error) {
+	ocp, err := ensureOCProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ir, err := newLayer(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := appendToConfigArray(ctx, ocp, "OCGs", *ir); err != nil {
+		return nil, err
+	}
+
+	dc, err := defaultOCConfig(ctx, ocp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := appendToConfigArray(ctx, dc, "Order", *ir); err != nil {
+		return nil, err
+	}
+
+	key := "ON"
+	if !visible {
+		key = "OFF"
+	}
+
+	if err := appendToConfigArray(ctx, dc, key, *ir); err != nil {
+		return nil, err
+	}
+
+	return ir, nil
+}
+
+// AddLayer adds a new empty layer to ctx.
+func AddLayer(ctx *model.Context, name string, visible bool) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("pdfcpu: missing layer name")
+	}
+
+	ll, err := Layers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range ll {
+		if l.Name == name {
+			return errors.Errorf("pdfcpu: layer already exists: %s", name)
+		}
+	}
+
+	_, err = addLayer(ctx, name, visible)
+
+	return err
+}
+
+// ensureLayer returns the layer with given name and creates it if necessary.
+// The returned flag is true if the layer has been created.
+func ensureLayer(ctx *model.Context, name string) (*types.IndirectRef, bool, error) {
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, l := range ll {
+		if l.Name == name {
+			ir := l.indRef
+			return &ir, false, nil
+		}
+	}
+
+	ir, err := addLayer(ctx, name, true)
+
+	return ir, true, err
+}
+
+func removeFromConfigArray(ctx *model.Context, d types.Dict, key string, m types.IntSet) error {
+	o, found := d.Find(key)
+	if !found {
+		return nil
+	}
+
+	a, err := ctx.DereferenceArray(o)
+	if err != nil || a == nil {
+		return err
+	}
+
+	d[key] = removeFromArray(ctx, a, m)
+
+	return nil
+}
+
+// removeFromArray removes all references to m from a including nested arrays like in Order or RBGroups.
+func removeFromArray(ctx *model.Context, a types.Array, m types.IntSet) types.Array {
+	a1 := types.Array{}
+	for _, o := range a {
+		if ir, ok := o.(types.IndirectRef); ok {
+			if m[ir.ObjectNumber.Value()] {
+				continue
+			}
+			if a2, err := ctx.DereferenceArray(ir); err == nil && a2 != nil {
+				o = removeFromArray(ctx, a2, m)
+			}
+		}
+		if a2, ok := o.(types.Array); ok {
+			o = removeFromArray(ctx, a2, m)
+		}
+		a1 = append(a1, o)
+	}
+	return a1
+}
+
+func removeFromOCConfig(ctx *model.Context, d types.Dict, m types.IntSet) error {
+	for _, k := range []string{"ON", "OFF", "Locked", "Order", "RBGroups"} {
+		if err := removeFromConfigArray(ctx, d, k, m); err != nil {
+			return err
+		}
+	}
+
+	o, found := d.Find("AS")
+	if !found {
+		return nil
+	}
+
+	a, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range a {
+		d1, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d1 != nil {
+			if err := removeFromConfigArray(ctx, d1, "OCGs", m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeFromOCProperties removes the layers m from the optional content properties of ctx.
+func removeFromOCProperties(ctx *model.Context, m types.IntSet) error {
+	ocp, err := ocProperties(ctx)
+	if err != nil || ocp == nil {
+		return err
+	}
+
+	if err := removeFromConfigArray(ctx, ocp, "OCGs", m); err != nil {
+		return err
+	}
+
+	a, err := ocgs(ctx, ocp)
+	if err != nil {
+		return err
+	}
+
+	if len(a) == 0 {
+		rootDict, err := ctx.Catalog()
+		if err != nil {
+			return err
+		}
+		rootDict.Delete("OCProperties")
+		return nil
+	}
+
+	dc, err := defaultOCConfig(ctx, ocp)
+	if err != nil {
+		return err
+	}
+
+	if err := removeFromOCConfig(ctx, dc, m); err != nil {
+		return err
+	}
+
+	o, found := ocp.Find("Configs")
+	if !found {
+		return nil
+	}
+
+	configs, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range configs {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			if err := removeFromOCConfig(ctx, d, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func setViewState(ctx *model.Context, d types.Dict, visible bool) error {
+	o, found := d.Find("Usage")
+	if !found {
+		return nil
+	}
+
+	usage, err := ctx.DereferenceDict(o)
+	if err != nil || usage == nil {
+		return err
+	}
+
+	o, found = usage.Find("View")
+	if !found {
+		return nil
+	}
+
+	view, err := ctx.DereferenceDict(o)
+	if err != nil || view == nil {
+		return err
+	}
+
+	state := "ON"
+	if !visible {
+		state = "OFF"
+	}
+	view["ViewState"] = types.Name(state)
+
+	return nil
+}
+
+// SetLayerVisibility sets the default visibility of layers with given names.
+func SetLayerVisibility(ctx *model.Context, names []string, visible bool) error {
+	ll, err := findLayers(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	m := layerObjNrs(ll)
+
+	ocp, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+
+	dc, err := defaultOCConfig(ctx, ocp)
+	if err != nil {
+		return err
+	}
+
+	if err := removeFromConfigArray(ctx, dc, "ON", m); err != nil {
+		return err
+	}
+
+	if err := removeFromConfigArray(ctx, dc, "OFF", m); err != nil {
+		return err
+	}
+
+	key := "ON"
+	if !visible {
+		key = "OFF"
+	}
+
+	sort.Slice(ll, func(i, j int) bool { return ll[i].ID < ll[j].ID })
+
+	for _, l := range ll {
+		ir := l.indRef
+		if err := appendToConfigArray(ctx, dc, key, ir); err != nil {
+			return err
+		}
+
+		// The usage view state gets applied when the document is opened, so keep it in sync.
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil {
+			return err
+		}
+		if err := setViewState(ctx, d, visible); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// layerProcessor removes or flattens layers.
+type layerProcessor struct {
+	ctx     *model.Context
+	actions map[int]layerAction // OCG object number => action
+	forms   types.IntSet        // processed form XObjects
+}
+
+// action returns the action for an optional content group or membership dict.
+func (lp *layerProcessor) action(o types.Object) (layerAction, error) {
+	ir, ok := o.(types.IndirectRef)
+	if ok {
+		if a, ok := lp.actions[ir.ObjectNumber.Value()]; ok {
+			return a, nil
+		}
+	}
+
+	d, err := lp.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return layerKeep, err
+	}
+
+	if t := d.Type(); t == nil || *t != "OCMD" {
+		return layerKeep, nil
+	}
+
+	// An optional content membership dict is affected if all of its OCGs are affected.
+	o, err = lp.ctx.Dereference(d["OCGs"])
+	if err != nil || o == nil {
+		return layerKeep, err
+	}
+
+	a, ok := o.(types.Array)
+	if !ok {
+		a = types.Array{o}
+	}
+
+	var visible []bool
+	for _, o := range a {
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			return layerKeep, nil
+		}
+		act, ok := lp.actions[ir.ObjectNumber.Value()]
+		if !ok {
+			return layerKeep, nil
+		}
+		visible = append(visible, act == layerStrip)
+	}
+
+	if len(visible) == 0 {
+		return layerKeep, nil
+	}
+
+	if membershipVisible(d.NameEntry("P"), visible) {
+		return layerStrip, nil
+	}
+
+	return layerDrop, nil
+}
+
+func membershipVisible(policy *string, visible []bool) bool {
+	on, off := 0, 0
+	for _, v := range visible {
+		if v {
+			on++
+		} else {
+			off++
+		}
+	}
+
+	p := "AnyOn"
+	if policy != nil {
+		p = *policy
+	}
+
+	switch p {
+	case "AllOn":
+		return off == 0
+	case "AnyOff":
+		return off > 0
+	case "AllOff":
+		return on == 0
+	}
+
+	return on > 0
+}
+
+func (lp *layerProcessor) resourceEntry(res types.Dict, resType, name string) (types.Object, error) {
+	if res == nil {
+		return nil, nil
+	}
+
+	o, found := res.Find(resType)
+	if !found {
+		return nil, nil
+	}
+
+	d, err := lp.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	o, _ = d.Find(strings.TrimPrefix(name, "/"))
+
+	return o, nil
+}
+
+func (lp *layerProcessor) markedContentAction(op model.ContentOp, res types.Dict) (layerAction, error) {
+	if op.Name != "BDC" || len(op.Operands) != 2 || op.Operands[0] != "/OC" || !strings.HasPrefix(op.Operands[1], "/") {
+		return layerKeep, nil
+	}
+
+	o, err := lp.resourceEntry(res, "Properties", op.Operands[1])
+	if err != nil || o == nil {
+		return layerKeep, err
+	}
+
+	return lp.action(o)
+}
+
+func (lp *layerProcessor) xObjectAction(op model.ContentOp, res types.Dict) (layerAction, error) {
+	if len(op.Operands) != 1 {
+		return layerKeep, nil
+	}
+
+	o, err := lp.resourceEntry(res, "XObject", op.Operands[0])
+	if err != nil || o == nil {
+		return layerKeep, err
+	}
+
+	sd, _, err := lp.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return layerKeep, err
+	}
+
+	a := layerKeep
+	if oc, found := sd.Find("OC"); found {
+		if a, err = lp.action(oc); err != nil {
+			return layerKeep, err
+		}
+		if a == layerDrop {
+			return a, nil
+		}
+	}
+
+	ir, ok := o.(types.IndirectRef)
+	if !ok {
+		return a, nil
+	}
+
+	objNr := ir.ObjectNumber.Value()
+	if lp.forms[objNr] {
+		return a, nil
+	}
+	lp.forms[objNr] = true
+
+	if a == layerStrip {
+		sd.Delete("OC")
+	}
+
+	if st := sd.Subtype(); st != nil && *st == "Form" {
+		if err := lp.processFormXObject(sd, res); err != nil {
+			return layerKeep, err
+		}
+	}
+
+	entry, found := lp.ctx.FindTableEntryForIndRef(&ir)
+	if found {
+		entry.Object = *sd
+	}
+
+	return a, nil
+}
+
+// processContent returns bb with the content of dropped layers removed and the markup of stripped layers removed.
+func (lp *layerProcessor) processContent(bb []byte, res types.Dict) ([]byte, bool, error) {
+	var (
+		buf      bytes.Buffer
+		stack    []layerAction
+		last     int
+		dropping = -1 // stack depth of the marked content sequence being removed
+		changed  bool
+	)
+
+	cut := func(op model.ContentOp) {
+		buf.Write(bb[last:op.Pos])
+		last = op.End
+		changed = true
+	}
+
+	err := model.ParseContentOps(bb, func(op model.ContentOp) error {
+		switch op.Name {
+
+		case "BMC", "BDC":
+			if dropping >= 0 {
+				stack = append(stack, layerKeep)
+				return nil
+			}
+			a, err := lp.markedContentAction(op, res)
+			if err != nil {
+				return err
+			}
+			switch a {
+			case layerDrop:
+				buf.Write(bb[last:op.Pos])
+				dropping = len(stack)
+				changed = true
+			case layerStrip:
+				cut(op)
+			}
+			stack = append(stack, a)
+
+		case "EMC":
+			if len(stack) == 0 {
+				return nil
+			}
+			a := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if dropping == len(stack) {
+				last = op.End
+				dropping = -1
+				return nil
+			}
+			if dropping < 0 && a == layerStrip {
+				cut(op)
+			}
+
+		case "Do":
+			if dropping >= 0 {
+				return nil
+			}
+			a, err := lp.xObjectAction(op, res)
+			if err != nil {
+				return err
+			}
+			if a == layerDrop {
+				cut(op)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if dropping >= 0 {
+		// Unbalanced marked content: drop everything up to the end of the stream.
+		return buf.Bytes(), true, nil
+	}
+
+	buf.Write(bb[last:])
+
+	return buf.Bytes(), changed, nil
+}
+
+func (lp *layerProcessor) processFormXObject(sd *types.StreamDict, res types.Dict) error {
+	if err := sd.Decode(); err != nil {
+		if err == filter.ErrUnsupportedFilter {
+			return nil
+		}
+		return err
+	}
+
+	if o, found := sd.Find("Resources"); found {
+		d, err := lp.ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			res = d
+		}
+	}
+
+	bb, changed, err := lp.processContent(sd.Content, res)
+	if err != nil || !changed {
+		return err
+	}
+
+	sd.Content = bb
+
+	return sd.Encode()
+}
+
+func (lp *layerProcessor) processAnnotations(d types.Dict) error {
+	o, found := d.Find("Annots")
+	if !found {
+		return nil
+	}
+
+	a, err := lp.ctx.DereferenceArray(o)
+	if err != nil || len(a) == 0 {
+		return err
+	}
+
+	a1 := types.Array{}
+
+	for _, o := range a {
+		d1, err := lp.ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d1 != nil {
+			if oc, found := d1.Find("OC"); found {
+				act, err := lp.action(oc)
+				if err != nil {
+					return err
+				}
+				if act == layerDrop {
+					continue
+				}
+				if act == layerStrip {
+					d1.Delete("OC")
+				}
+			}
+		}
+		a1 = append(a1, o)
+	}
+
+	if len(a1) == 0 {
+		d.Delete("Annots")
+		return nil
+	}
+
+	d["Annots"] = a1
+
+	return nil
+}
+
+func (lp *layerProcessor) processPage(pageNr int) error {
+	d, _, inhPAttrs, err := lp.ctx.PageDict(pageNr, true)
+	if err != nil {
+		return err
+	}
+
+	if err := lp.processAnnotations(d); err != nil {
+		return err
+	}
+
+	bb, err := lp.ctx.PageContent(d, pageNr)
+	if err == model.ErrNoContent {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	bb, changed, err := lp.processContent(bb, inhPAttrs.Resources)
+	if err != nil || !changed {
+		return err
+	}
+
+	sd, _ := lp.ctx.NewStreamDictForBuf(bb)
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	ir, err := lp.ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d["Contents"] = *ir
+
+	return nil
+}
+
+func processLayers(ctx *model.Context, actions map[int]layerAction) error {
+	lp := &layerProcessor{ctx: ctx, actions: actions, forms: types.IntSet{}}
+
+	for i := 1; i <= ctx.PageCount; i++ {
+		if err := lp.processPage(i); err != nil {
+			return err
+		}
+	}
+
+	m := types.IntSet{}
+	for objNr := range actions {
+		m[objNr] = true
+	}
+
+	return removeFromOCProperties(ctx, m)
+}
+
+// RemoveLayers removes the layers with given names including their content.
+func RemoveLayers(ctx *model.Context, names []string) error {
+	if len(names) == 0 {
+		return errors.New("pdfcpu: missing layer names")
+	}
+
+	ll, err := findLayers(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	actions := map[int]layerAction{}
+	for _, l := range ll {
+		actions[l.ID] = layerDrop
+	}
+
+	return processLayers(ctx, actions)
+}
+
+// FlattenLayers turns the content of the layers with given names (or all layers) into regular content.
+// The default visibility is preserved: content of hidden layers gets removed.
+func FlattenLayers(ctx *model.Context, names []string) error {
+	ll, err := findLayers(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	actions := map[int]layerAction{}
+	for _, l := range ll {
+		actions[l.ID] = layerDrop
+		if l.Visible {
+			actions[l.ID] = layerStrip
+		}
+	}
+
+	return processLayers(ctx, actions)
+}
@@ -932,6 +932,19 @@ func mergeDuplicateObjNumberIntSets(ctxSrc, ctxDest *model.Context) {
 // dividerPage ... insert blank page between merged files (not applicable for zipping)
 func MergeXRefTables(fName string, ctxSrc, ctxDest *model.Context, zip, dividerPage bool) (err error) {
 
+	// Page labels get resolved up front using the original object numbers.
+	vvSrc, err := pageLabelValues(ctxSrc)
+	if err != nil {
+		return err
+	}
+
+	vvDest, err := pageLabelValues(ctxDest)
+	if err != nil {
+		return err
+	}
+
+	destPageCount := ctxDest.PageCount
+
 	patchSourceObjectNumbers(ctxSrc, ctxDest)
 
 	appendSourceObjectsToDest(ctxSrc, ctxDest)
@@ -951,6 +964,10 @@ func MergeXRefTables(fName string, ctxSrc, ctxDest *model.Context, zip, dividerP
 		return nil
 	}
 
+	if err = mergePageLabels(ctxDest, vvDest, vvSrc, destPageCount, ctxSrc.PageCount, zip, dividerPage); err != nil {
+		return err
+	}
+
 	if err = mergeForms(ctxSrc, ctxDest); err != nil {
 		return err
 	}
@@ -18,8 +18,10 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/pdfcpu/pdfcpu/pkg/font"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
@@ -146,12 +148,8 @@ const (
 	BSUnderline
 )
 
-func borderStyleDict(width float64, style BorderStyle) types.Dict {
-	d := types.Dict(map[string]types.Object{
-		"Type": types.Name("Border"),
-		"W":    types.Float(width),
-	})
-
+// BorderStyleName returns the PDF name of a border style.
+func BorderStyleName(style BorderStyle) string {
 	var s string
 
 	switch style {
@@ -167,9 +165,15 @@ func borderStyleDict(width float64, style BorderStyle) types.Dict {
 		s = "U"
 	}
 
-	d["S"] = types.Name(s)
+	return s
+}
 
-	return d
+func borderStyleDict(width float64, style BorderStyle) types.Dict {
+	return types.Dict(map[string]types.Object{
+		"Type": types.Name("Border"),
+		"W":    types.Float(width),
+		"S":    types.Name(BorderStyleName(style)),
+	})
 }
 
 func borderEffectDict(cloudyBorder bool, intensity int) types.Dict {
@@ -257,6 +261,7 @@ type Annotation struct {
 	BorderRadX       float64            // Border radius X
 	BorderRadY       float64            // Border radius Y
 	BorderWidth      float64            // Border width
+	GenerateAP       bool               // Generate a normal appearance stream.
 	Hash             uint32
 	// StructParent int
 	// OC types.dict
@@ -403,6 +408,22 @@ func (ann Annotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.Indirec
 	return d, nil
 }
 
+// withAppearance adds a generated normal appearance stream to d if requested.
+func (ann Annotation) withAppearance(xRefTable *XRefTable, d types.Dict) (types.Dict, error) {
+	if !ann.GenerateAP {
+		return d, nil
+	}
+
+	ir, err := xRefTable.CreateAnnotationAppearance(d, true)
+	if err != nil {
+		return nil, err
+	}
+
+	d["AP"] = types.Dict(map[string]types.Object{"N": *ir})
+
+	return d, nil
+}
+
 // PopupAnnotation represents PDF Popup annotations.
 type PopupAnnotation struct {
 	Annotation
@@ -571,6 +592,8 @@ type MarkupAnnotation struct {
 	RC           string             // A rich text string that shall be displayed in the pop-up window when the annotation is opened.
 	CreationDate string             // The date and time when the annotation was created.
 	Subj         string             // Text representing a short description of the subject being addressed by the annotation.
+	IRT          *types.IndirectRef // (Since V1.5) A reference to the annotation that this annotation is "in reply to".
+	RT           string             // (Since V1.6) Specifies the relationship between this annotation and the one specified by IRT: R (reply) or Group.
 }
 
 // NewMarkupAnnotation returns a new markup annotation.
@@ -652,6 +675,13 @@ func (ann MarkupAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.I
 		d.InsertString("Subj", *s)
 	}
 
+	if ann.IRT != nil {
+		d["IRT"] = *ann.IRT
+		if ann.RT != "" {
+			d.InsertName("RT", ann.RT)
+		}
+	}
+
 	return d, nil
 }
 
@@ -703,7 +733,7 @@ func (ann TextAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.Ind
 		d.InsertName("Name", ann.Name)
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // FreeTextIntent represents the various free text annotation intents.
@@ -833,19 +863,21 @@ func (ann FreeTextAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types
 
 	da := ""
 
-	// TODO Implement Tf operator
-
-	// fontID, err := xRefTable.EnsureFont(ann.FontName) // in root page Resources?
-	// if err != nil {
-	// 	return nil, err
-	// }
-
-	// da := fmt.Sprintf("/%s %d Tf", fontID, ann.FontSize)
+	if ann.FontName != "" {
+		if ann.GenerateAP && !font.IsCoreFont(ann.FontName) {
+			return nil, errors.Errorf("pdfcpu: FreeTextAnnotation appearance supports core fonts only: %s", ann.FontName)
+		}
+		fontSize := ann.FontSize
+		if fontSize <= 0 {
+			fontSize = 12
+		}
+		da = fmt.Sprintf("/%s %d Tf", ann.FontName, fontSize)
+	}
 
 	if ann.FontCol != nil {
 		da += fmt.Sprintf(" %.2f %.2f %.2f rg", ann.FontCol.R, ann.FontCol.G, ann.FontCol.B)
 	}
-	d["DA"] = types.StringLiteral(da)
+	d["DA"] = types.StringLiteral(strings.TrimSpace(da))
 
 	d.InsertInt("Q", int(ann.HAlign))
 
@@ -887,7 +919,7 @@ func (ann FreeTextAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types
 		d["BE"] = borderEffectDict(ann.CloudyBorder, ann.CloudyBorderIntensity)
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // LineIntent represents the various line annotation intents.
@@ -1063,7 +1095,7 @@ func (ann LineAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.Ind
 		d["LE"] = ann.LineEndings
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // SquareAnnotation represents a square annotation.
@@ -1142,7 +1174,7 @@ func (ann SquareAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.I
 		d["BE"] = borderEffectDict(ann.CloudyBorder, ann.CloudyBorderIntensity)
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // CircleAnnotation represents a square annotation.
@@ -1221,7 +1253,7 @@ func (ann CircleAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.I
 		d["BE"] = borderEffectDict(ann.CloudyBorder, ann.CloudyBorderIntensity)
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // PolygonIntent represents the various polygon annotation intents.
@@ -1346,7 +1378,7 @@ func (ann PolygonAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.
 		d["BE"] = borderEffectDict(ann.CloudyBorder, ann.CloudyBorderIntensity)
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // PolyLineIntent represents the various polyline annotation intents.
@@ -1469,7 +1501,7 @@ func (ann PolyLineAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types
 		d["LE"] = ann.LineEndings
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 type TextMarkupAnnotation struct {
@@ -1513,7 +1545,7 @@ func (ann TextMarkupAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *typ
 		d.Insert("QuadPoints", ann.Quad.Array())
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 type HighlightAnnotation struct {
@@ -1667,7 +1699,7 @@ func (ann CaretAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.In
 		d["Sy"] = types.Name("P")
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
 
 // A series of alternating x and y coordinates in PDF user space, specifying points along the path.
@@ -1722,5 +1754,5 @@ func (ann InkAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.Indi
 		d["BS"] = borderStyleDict(ann.BorderWidth, ann.BorderStyle)
 	}
 
-	return d, nil
+	return ann.withAppearance(xRefTable, d)
 }
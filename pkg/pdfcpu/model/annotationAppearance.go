@@ -0,0 +1,1096 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Appearance streams generated for annotation dicts.
+// The form bounding box equals the annotation rectangle so all geometry may be expressed in default user space.
+
+// Bezier control point factor for approximating a quarter ellipse.
+const kappa = 0.5522847498
+
+const captionFontSize = 9
+
+var (
+	daFontRegExp   = regexp.MustCompile(`/([^\s/]+)\s+([\d.]+)\s+Tf`)
+	daColorRegExp  = regexp.MustCompile(`((?:[\d.]+\s+){1,4})(g|rg|k)\b`)
+	richTextRegExp = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Annotation subtypes supported by CreateAnnotationAppearance.
+var appearanceSubtypes = map[string]bool{
+	"Text":      true,
+	"FreeText":  true,
+	"Line":      true,
+	"Square":    true,
+	"Circle":    true,
+	"Polygon":   true,
+	"PolyLine":  true,
+	"Highlight": true,
+	"Underline": true,
+	"Squiggly":  true,
+	"StrikeOut": true,
+	"Caret":     true,
+	"Ink":       true,
+}
+
+// AnnotationAppearanceSupported returns true if CreateAnnotationAppearance is able to render annotations of subtype.
+func AnnotationAppearanceSupported(subtype string) bool {
+	return appearanceSubtypes[subtype]
+}
+
+type appearance struct {
+	xRefTable  *XRefTable
+	d          types.Dict
+	r          *types.Rectangle
+	buf        bytes.Buffer
+	c, ic      []float64 // Colour and interior colour.
+	stroke     string    // Stroke colour operator for C.
+	fill       string    // Fill colour operator for IC.
+	bw         float64   // Border width.
+	dash       string    // Dash pattern operator.
+	renderText bool
+	gs         types.Dict
+	fonts      types.Dict
+}
+
+func (ap *appearance) numbers(key string) ([]float64, error) {
+	return ap.numbersFor(ap.d, key)
+}
+
+func (ap *appearance) numbersFor(d types.Dict, key string) ([]float64, error) {
+	o, found := d.Find(key)
+	if !found {
+		return nil, nil
+	}
+
+	a, err := ap.xRefTable.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	ff := make([]float64, 0, len(a))
+	for _, o := range a {
+		f, err := ap.xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return nil, err
+		}
+		ff = append(ff, f)
+	}
+
+	return ff, nil
+}
+
+// annotationColorOp returns a color operator for a color array of 1, 3 or 4 components.
+// CMYK colors get converted to RGB.
+func annotationColorOp(ff []float64, stroke bool) string {
+	gray, rgb := "g", "rg"
+	if stroke {
+		gray, rgb = "G", "RG"
+	}
+
+	switch len(ff) {
+	case 1:
+		return fmt.Sprintf("%.3f %s", ff[0], gray)
+	case 3:
+		return fmt.Sprintf("%.3f %.3f %.3f %s", ff[0], ff[1], ff[2], rgb)
+	case 4:
+		k := 1 - ff[3]
+		return fmt.Sprintf("%.3f %.3f %.3f %s", (1-ff[0])*k, (1-ff[1])*k, (1-ff[2])*k, rgb)
+	}
+
+	return ""
+}
+
+func (ap *appearance) borderStyle() (types.Dict, error) {
+	o, found := ap.d.Find("BS")
+	if !found {
+		return nil, nil
+	}
+	return ap.xRefTable.DereferenceDict(o)
+}
+
+// border sets border width and dash pattern using BS or else Border.
+func (ap *appearance) border() error {
+	ap.bw = 1
+
+	bs, err := ap.borderStyle()
+	if err != nil {
+		return err
+	}
+
+	if bs != nil {
+		if o, found := bs.Find("W"); found {
+			if ap.bw, err = ap.xRefTable.DereferenceNumber(o); err != nil {
+				return err
+			}
+		}
+		if s := bs.NameEntry("S"); s != nil && *s == "D" {
+			dd, err := ap.numbersFor(bs, "D")
+			if err != nil {
+				return err
+			}
+			if len(dd) == 0 {
+				dd = []float64{3}
+			}
+			ap.dash = dashOp(dd)
+		}
+		return nil
+	}
+
+	o, found := ap.d.Find("Border")
+	if !found {
+		return nil
+	}
+
+	a, err := ap.xRefTable.DereferenceArray(o)
+	if err != nil || len(a) < 3 {
+		return err
+	}
+
+	if ap.bw, err = ap.xRefTable.DereferenceNumber(a[2]); err != nil {
+		return err
+	}
+
+	if len(a) > 3 {
+		d := types.Dict(map[string]types.Object{"D": a[3]})
+		dd, err := ap.numbersFor(d, "D")
+		if err != nil {
+			return err
+		}
+		if len(dd) > 0 {
+			ap.dash = dashOp(dd)
+		}
+	}
+
+	return nil
+}
+
+func dashOp(dd []float64) string {
+	ss := make([]string, len(dd))
+	for i, f := range dd {
+		ss[i] = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return "[" + strings.Join(ss, " ") + "] 0 d"
+}
+
+func (ap *appearance) opacity() error {
+	o, found := ap.d.Find("CA")
+	if !found {
+		return nil
+	}
+	ca, err := ap.xRefTable.DereferenceNumber(o)
+	if err != nil || ca >= 1 {
+		return err
+	}
+	ap.extGState()["CA"] = types.Float(ca)
+	ap.extGState()["ca"] = types.Float(ca)
+	return nil
+}
+
+func (ap *appearance) extGState() types.Dict {
+	if ap.gs == nil {
+		ap.gs = types.Dict(map[string]types.Object{"Type": types.Name("ExtGState")})
+	}
+	return ap.gs
+}
+
+// fontKey registers a core font resource for fontName.
+func (ap *appearance) fontKey(fontName string) string {
+	if ap.fonts == nil {
+		ap.fonts = types.Dict{}
+	}
+	for k, v := range ap.fonts {
+		if d, ok := v.(types.Dict); ok && d.NameEntry("BaseFont") != nil && *d.NameEntry("BaseFont") == fontName {
+			return k
+		}
+	}
+	d := types.Dict(map[string]types.Object{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name(fontName),
+	})
+	if fontName != "Symbol" && fontName != "ZapfDingbats" {
+		d["Encoding"] = types.Name("WinAnsiEncoding")
+	}
+	key := "F" + strconv.Itoa(len(ap.fonts))
+	ap.fonts[key] = d
+	return key
+}
+
+func (ap *appearance) paint(closed bool) string {
+	switch {
+	case closed && ap.fill != "" && ap.bw > 0:
+		return "B"
+	case closed && ap.fill != "":
+		return "f"
+	case ap.bw > 0:
+		return "S"
+	}
+	return "n"
+}
+
+func (ap *appearance) appendEllipse(r *types.Rectangle) {
+	cx, cy := r.LL.X+r.Width()/2, r.LL.Y+r.Height()/2
+	rx, ry := r.Width()/2, r.Height()/2
+	ox, oy := rx*kappa, ry*kappa
+
+	fmt.Fprintf(&ap.buf, "%.2f %.2f m ", cx+rx, cy)
+	fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f %.2f %.2f c ", cx+rx, cy+oy, cx+ox, cy+ry, cx, cy+ry)
+	fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f %.2f %.2f c ", cx-ox, cy+ry, cx-rx, cy+oy, cx-rx, cy)
+	fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f %.2f %.2f c ", cx-rx, cy-oy, cx-ox, cy-ry, cx, cy-ry)
+	fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx+ox, cy-ry, cx+rx, cy-oy, cx+rx, cy)
+}
+
+func (ap *appearance) appendPath(ff []float64, close bool) {
+	for i := 0; i+1 < len(ff); i += 2 {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&ap.buf, "%.2f %.2f %s ", ff[i], ff[i+1], op)
+	}
+	if close {
+		ap.buf.WriteString("h")
+	}
+	ap.buf.WriteString("\n")
+}
+
+// innerRect returns the annotation rectangle reduced by the rectangle differences RD (left, top, right, bottom).
+func (ap *appearance) innerRect() (*types.Rectangle, error) {
+	rd, err := ap.numbers("RD")
+	if err != nil {
+		return nil, err
+	}
+	r := ap.r
+	if len(rd) != 4 {
+		return types.NewRectangle(r.LL.X, r.LL.Y, r.UR.X, r.UR.Y), nil
+	}
+	return types.NewRectangle(r.LL.X+rd[0], r.LL.Y+rd[3], r.UR.X-rd[2], r.UR.Y-rd[1]), nil
+}
+
+// lineEnding renders a line ending style at x,y where ux,uy is the unit vector pointing away from the line.
+func (ap *appearance) lineEnding(style string, x, y, ux, uy float64) {
+	w := math.Max(ap.bw, 1)
+	h, l := 3*w, 5*w
+	nx, ny := -uy, ux
+
+	pt := func(du, dn float64) (float64, float64) {
+		return x + du*ux + dn*nx, y + du*uy + dn*ny
+	}
+
+	closed := func(pp ...float64) {
+		ap.appendPath(pp, true)
+		ap.buf.WriteString(ap.paint(true) + "\n")
+	}
+
+	open := func(pp ...float64) {
+		ap.appendPath(pp, false)
+		ap.buf.WriteString("S\n")
+	}
+
+	switch style {
+
+	case "Square":
+		x1, y1 := pt(-h, -h)
+		x2, y2 := pt(h, -h)
+		x3, y3 := pt(h, h)
+		x4, y4 := pt(-h, h)
+		closed(x1, y1, x2, y2, x3, y3, x4, y4)
+
+	case "Circle":
+		ap.appendEllipse(types.NewRectangle(x-h, y-h, x+h, y+h))
+		ap.buf.WriteString(ap.paint(true) + "\n")
+
+	case "Diamond":
+		x1, y1 := pt(h, 0)
+		x2, y2 := pt(0, h)
+		x3, y3 := pt(-h, 0)
+		x4, y4 := pt(0, -h)
+		closed(x1, y1, x2, y2, x3, y3, x4, y4)
+
+	case "OpenArrow", "ClosedArrow":
+		x1, y1 := pt(-l, h)
+		x3, y3 := pt(-l, -h)
+		if style == "OpenArrow" {
+			open(x1, y1, x, y, x3, y3)
+			break
+		}
+		closed(x1, y1, x, y, x3, y3)
+
+	case "ROpenArrow", "RClosedArrow":
+		x1, y1 := pt(l, h)
+		x3, y3 := pt(l, -h)
+		if style == "ROpenArrow" {
+			open(x1, y1, x, y, x3, y3)
+			break
+		}
+		closed(x1, y1, x, y, x3, y3)
+
+	case "Butt":
+		x1, y1 := pt(0, h)
+		x2, y2 := pt(0, -h)
+		open(x1, y1, x2, y2)
+
+	case "Slash":
+		// Rotated 30 degrees clockwise from the perpendicular.
+		du, dn := h*math.Sin(math.Pi/6), h*math.Cos(math.Pi/6)
+		x1, y1 := pt(du, dn)
+		x2, y2 := pt(-du, -dn)
+		open(x1, y1, x2, y2)
+	}
+}
+
+func (ap *appearance) lineEndings() ([]string, error) {
+	o, found := ap.d.Find("LE")
+	if !found {
+		return nil, nil
+	}
+
+	o, err := ap.xRefTable.Dereference(o)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o := o.(type) {
+	case types.Name:
+		// FreeText callout line ending
+		return []string{o.Value(), "None"}, nil
+	case types.Array:
+		ss := []string{}
+		for _, o1 := range o {
+			if n, ok := o1.(types.Name); ok {
+				ss = append(ss, n.Value())
+			}
+		}
+		if len(ss) == 2 {
+			return ss, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func unitVector(x1, y1, x2, y2 float64) (float64, float64) {
+	dx, dy := x2-x1, y2-y1
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return 1, 0
+	}
+	return dx / l, dy / l
+}
+
+// renderLineEndings renders the line endings for a polyline described by ff.
+func (ap *appearance) renderLineEndings(ff []float64) error {
+	if len(ff) < 4 {
+		return nil
+	}
+
+	les, err := ap.lineEndings()
+	if err != nil || les == nil {
+		return err
+	}
+
+	if ap.dash != "" {
+		ap.buf.WriteString("[] 0 d\n")
+	}
+
+	n := len(ff)
+
+	ux, uy := unitVector(ff[2], ff[3], ff[0], ff[1])
+	ap.lineEnding(les[0], ff[0], ff[1], ux, uy)
+
+	ux, uy = unitVector(ff[n-4], ff[n-3], ff[n-2], ff[n-1])
+	ap.lineEnding(les[1], ff[n-2], ff[n-1], ux, uy)
+
+	return nil
+}
+
+// plainText returns the text to be rendered for an annotation.
+func (ap *appearance) plainText() (string, error) {
+	for _, k := range []string{"Contents", "RC"} {
+		o, found := ap.d.Find(k)
+		if !found {
+			continue
+		}
+		s, err := types.StringOrHexLiteral(o)
+		if err != nil {
+			return "", err
+		}
+		if s == nil || *s == "" {
+			continue
+		}
+		if k == "RC" {
+			return strings.TrimSpace(richTextRegExp.ReplaceAllString(*s, "")), nil
+		}
+		return *s, nil
+	}
+	return "", nil
+}
+
+// showText writes a single line of text at x,y using a core font.
+func (ap *appearance) showText(s, fontName string, fontSize int, x, y float64) {
+	s1, _ := types.Escape(s)
+	fmt.Fprintf(&ap.buf, "BT /%s %d Tf %.2f %.2f Td (%s) Tj ET\n", ap.fontKey(fontName), fontSize, x, y, *s1)
+}
+
+func (ap *appearance) textAnnotation() {
+	fill := annotationColorOp(ap.c, false)
+	if fill == "" {
+		fill = "1 1 0 rg"
+	}
+	r := ap.r
+	x, y, w, h := r.LL.X, r.LL.Y, r.Width(), r.Height()
+	fmt.Fprintf(&ap.buf, "%s 0 G 0.5 w\n", fill)
+	fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f re B\n", x+0.5, y+0.5, w-1, h-1)
+	for i := 1; i <= 3; i++ {
+		yi := y + h*float64(i)/4
+		fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", x+w/5, yi, x+w*4/5, yi)
+	}
+}
+
+func (ap *appearance) square(circle bool) error {
+	r, err := ap.innerRect()
+	if err != nil {
+		return err
+	}
+
+	bw := ap.bw
+	r = types.NewRectangle(r.LL.X+bw/2, r.LL.Y+bw/2, r.UR.X-bw/2, r.UR.Y-bw/2)
+
+	if circle {
+		ap.appendEllipse(r)
+	} else {
+		fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f re\n", r.LL.X, r.LL.Y, r.Width(), r.Height())
+	}
+
+	ap.buf.WriteString(ap.paint(true) + "\n")
+
+	return nil
+}
+
+func (ap *appearance) line() error {
+	ff, err := ap.numbers("L")
+	if err != nil || len(ff) != 4 {
+		return err
+	}
+
+	x1, y1, x2, y2 := ff[0], ff[1], ff[2], ff[3]
+	ux, uy := unitVector(x1, y1, x2, y2)
+	nx, ny := -uy, ux
+
+	var ll, lle, llo float64
+	for k, v := range map[string]*float64{"LL": &ll, "LLE": &lle, "LLO": &llo} {
+		if o, found := ap.d.Find(k); found {
+			if *v, err = ap.xRefTable.DereferenceNumber(o); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ll != 0 {
+		sign := 1.
+		if ll < 0 {
+			sign = -1
+		}
+		for _, p := range [][2]float64{{x1, y1}, {x2, y2}} {
+			ax, ay := p[0]+nx*sign*llo, p[1]+ny*sign*llo
+			bx, by := p[0]+nx*(ll+sign*lle), p[1]+ny*(ll+sign*lle)
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", ax, ay, bx, by)
+		}
+		x1, y1, x2, y2 = x1+nx*ll, y1+ny*ll, x2+nx*ll, y2+ny*ll
+	}
+
+	captioned := false
+	if cap := ap.d.BooleanEntry("Cap"); cap != nil && *cap && ap.renderText {
+		captioned, err = ap.lineCaption(x1, y1, x2, y2)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !captioned {
+		fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+	}
+
+	return ap.renderLineEndings([]float64{x1, y1, x2, y2})
+}
+
+// lineCaption renders the caption of a line annotation and returns true if the line has been rendered too.
+func (ap *appearance) lineCaption(x1, y1, x2, y2 float64) (bool, error) {
+	s, err := ap.plainText()
+	if err != nil || s == "" {
+		return false, err
+	}
+	s = DecodeUTF8ToByte(s)
+
+	fontName := "Helvetica"
+	w := font.TextWidth(s, fontName, captionFontSize)
+
+	co, err := ap.numbers("CO")
+	if err != nil {
+		return false, err
+	}
+	var dx, dy float64
+	if len(co) == 2 {
+		dx, dy = co[0], co[1]
+	}
+
+	ux, uy := unitVector(x1, y1, x2, y2)
+	l := math.Hypot(x2-x1, y2-y1)
+
+	top := ap.d.NameEntry("CP") != nil && *ap.d.NameEntry("CP") == "Top"
+
+	var rendered bool
+	ty := 2 + ap.bw/2
+	if !top {
+		ty = -captionFontSize / 3.
+		gap := w + 4
+		if l > gap {
+			// Break the line around an inline caption.
+			a, b := (l-gap)/2+dx, (l+gap)/2+dx
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", x1, y1, x1+a*ux, y1+a*uy)
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", x1+b*ux, y1+b*uy, x2, y2)
+			rendered = true
+		}
+	}
+
+	mx, my := (x1+x2)/2, (y1+y2)/2
+
+	fill := annotationColorOp(ap.c, false)
+	if fill == "" {
+		fill = "0 g"
+	}
+
+	fmt.Fprintf(&ap.buf, "q %.5f %.5f %.5f %.5f %.2f %.2f cm %s\n", ux, uy, -uy, ux, mx, my, fill)
+	ap.showText(s, fontName, captionFontSize, -w/2+dx, ty+dy)
+	ap.buf.WriteString("Q\n")
+
+	return rendered, nil
+}
+
+func (ap *appearance) pathOps(closed bool) ([]float64, error) {
+	ff, err := ap.numbers("Vertices")
+	if err != nil {
+		return nil, err
+	}
+	if len(ff) >= 4 {
+		ap.appendPath(ff, closed)
+		return ff, nil
+	}
+
+	o, found := ap.d.Find("Path")
+	if !found {
+		return nil, nil
+	}
+
+	a, err := ap.xRefTable.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var pp []float64
+
+	for i, o := range a {
+		d := types.Dict(map[string]types.Object{"P": o})
+		ff, err := ap.numbersFor(d, "P")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case i == 0 && len(ff) == 2:
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m ", ff[0], ff[1])
+		case len(ff) == 2:
+			fmt.Fprintf(&ap.buf, "%.2f %.2f l ", ff[0], ff[1])
+		case len(ff) == 6:
+			fmt.Fprintf(&ap.buf, "%.2f %.2f %.2f %.2f %.2f %.2f c ", ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+		default:
+			continue
+		}
+		pp = append(pp, ff[len(ff)-2:]...)
+	}
+
+	if len(pp) > 0 && closed {
+		ap.buf.WriteString("h")
+	}
+	ap.buf.WriteString("\n")
+
+	return pp, nil
+}
+
+func (ap *appearance) polygon(closed bool) error {
+	pp, err := ap.pathOps(closed)
+	if err != nil || len(pp) < 4 {
+		return err
+	}
+
+	if closed {
+		ap.buf.WriteString(ap.paint(true) + "\n")
+		return nil
+	}
+
+	ap.buf.WriteString("S\n")
+
+	return ap.renderLineEndings(pp)
+}
+
+func (ap *appearance) ink() error {
+	o, found := ap.d.Find("InkList")
+	if !found {
+		return nil
+	}
+
+	a, err := ap.xRefTable.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	ap.buf.WriteString("1 J 1 j\n")
+
+	for _, o := range a {
+		d := types.Dict(map[string]types.Object{"P": o})
+		ff, err := ap.numbersFor(d, "P")
+		if err != nil {
+			return err
+		}
+		if len(ff) >= 2 {
+			ap.appendPath(ff, false)
+			ap.buf.WriteString("S\n")
+		}
+	}
+
+	return nil
+}
+
+// quads returns the quadrilaterals of a text markup annotation as (x1,y1 .. x4,y4) where 1,2 are the upper and 3,4 the lower points.
+func (ap *appearance) quads() ([][]float64, error) {
+	ff, err := ap.numbers("QuadPoints")
+	if err != nil {
+		return nil, err
+	}
+
+	var qq [][]float64
+	for i := 0; i+8 <= len(ff); i += 8 {
+		qq = append(qq, ff[i:i+8])
+	}
+
+	if len(qq) == 0 {
+		x1, y1, x2, y2 := ap.r.LL.X, ap.r.LL.Y, ap.r.UR.X, ap.r.UR.Y
+		qq = append(qq, []float64{x1, y2, x2, y2, x1, y1, x2, y1})
+	}
+
+	return qq, nil
+}
+
+func (ap *appearance) textMarkup(subtype string) error {
+	qq, err := ap.quads()
+	if err != nil {
+		return err
+	}
+
+	if subtype == "Highlight" {
+		fill := annotationColorOp(ap.c, false)
+		if fill == "" {
+			fill = "1 1 0 rg"
+		}
+		ap.extGState()["BM"] = types.Name("Multiply")
+		ap.buf.WriteString(fill + "\n")
+		for _, q := range qq {
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l %.2f %.2f l %.2f %.2f l h f\n", q[0], q[1], q[2], q[3], q[6], q[7], q[4], q[5])
+		}
+		return nil
+	}
+
+	stroke := ap.stroke
+	if stroke == "" {
+		stroke = "0 G"
+	}
+	ap.buf.WriteString(stroke + "\n")
+
+	for _, q := range qq {
+		x1, y1, x2, y2 := q[4], q[5], q[6], q[7]
+		h := q[1] - q[5]
+		w := h / 16
+		if w < 0.5 {
+			w = 0.5
+		}
+		fmt.Fprintf(&ap.buf, "%.2f w ", w)
+		switch subtype {
+		case "Underline":
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", x1, y1+w, x2, y2+w)
+		case "StrikeOut":
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m %.2f %.2f l S\n", x1, y1+h/2, x2, y2+h/2)
+		case "Squiggly":
+			step := h / 6
+			if step < 1 {
+				step = 1
+			}
+			fmt.Fprintf(&ap.buf, "%.2f %.2f m ", x1, y1+w)
+			up := true
+			for x := x1 + step; x < x2; x += step {
+				dy := w
+				if up {
+					dy = w + step
+				}
+				fmt.Fprintf(&ap.buf, "%.2f %.2f l ", x, y1+dy)
+				up = !up
+			}
+			ap.buf.WriteString("S\n")
+		}
+	}
+
+	return nil
+}
+
+func (ap *appearance) caret() error {
+	r, err := ap.innerRect()
+	if err != nil {
+		return err
+	}
+
+	fill := annotationColorOp(ap.c, false)
+	if fill == "" {
+		fill = "0 g"
+	}
+
+	x0, y0, x1, y1 := r.LL.X, r.LL.Y, r.UR.X, r.UR.Y
+	xm, ym := (x0+x1)/2, (y0+y1)/2
+
+	fmt.Fprintf(&ap.buf, "%s %.2f %.2f m %.2f %.2f %.2f %.2f %.2f %.2f c %.2f %.2f %.2f %.2f %.2f %.2f c h f\n",
+		fill, x0, y0, xm, ym, xm, ym, xm, y1, xm, ym, xm, ym, x1, y0)
+
+	return nil
+}
+
+// freeTextFont returns font name, font size and text colour operator of a FreeText annotation from its DA entry.
+func (ap *appearance) freeTextFont() (string, int, string) {
+	fontName, fontSize, col := "Helvetica", 12, "0 g"
+
+	s := ap.d.StringEntry("DA")
+	if s == nil {
+		return fontName, fontSize, col
+	}
+
+	if m := daFontRegExp.FindStringSubmatch(*s); m != nil {
+		if font.IsCoreFont(m[1]) {
+			fontName = m[1]
+		}
+		if f, err := strconv.ParseFloat(m[2], 64); err == nil && f > 0 {
+			fontSize = int(math.Round(f))
+		}
+	}
+
+	if m := daColorRegExp.FindStringSubmatch(*s); m != nil {
+		col = strings.TrimSpace(m[1]) + " " + m[2]
+	}
+
+	return fontName, fontSize, col
+}
+
+// ParseDefaultAppearance returns font name, font size and text colour of a default appearance string like "/Helv 12 Tf 0 0 1 rg".
+func ParseDefaultAppearance(da string) (string, int, *color.SimpleColor) {
+	var (
+		fontName string
+		fontSize int
+		col      *color.SimpleColor
+	)
+
+	if m := daFontRegExp.FindStringSubmatch(da); m != nil {
+		fontName = m[1]
+		if f, err := strconv.ParseFloat(m[2], 64); err == nil {
+			fontSize = int(math.Round(f))
+		}
+	}
+
+	if m := daColorRegExp.FindStringSubmatch(da); m != nil {
+		var ff []float32
+		for _, s := range strings.Fields(m[1]) {
+			f, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				return fontName, fontSize, nil
+			}
+			ff = append(ff, float32(f))
+		}
+		switch {
+		case m[2] == "g" && len(ff) == 1:
+			col = &color.SimpleColor{R: ff[0], G: ff[0], B: ff[0]}
+		case m[2] == "rg" && len(ff) == 3:
+			col = &color.SimpleColor{R: ff[0], G: ff[1], B: ff[2]}
+		case m[2] == "k" && len(ff) == 4:
+			k := 1 - ff[3]
+			col = &color.SimpleColor{R: (1 - ff[0]) * k, G: (1 - ff[1]) * k, B: (1 - ff[2]) * k}
+		}
+	}
+
+	return fontName, fontSize, col
+}
+
+// strokeColorOp returns the stroking counterpart of a non stroking colour operator.
+func strokeColorOp(op string) string {
+	i := strings.LastIndex(op, " ")
+	return op[:i+1] + strings.ToUpper(op[i+1:])
+}
+
+// wrapText breaks s into lines fitting into width w.
+func wrapText(s, fontName string, fontSize int, w float64) []string {
+	var lines []string
+
+	for _, par := range strings.Split(s, "\n") {
+		words := strings.Fields(par)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if font.TextWidth(line+" "+word, fontName, fontSize) > w {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func (ap *appearance) freeText() error {
+	r, err := ap.innerRect()
+	if err != nil {
+		return err
+	}
+
+	fontName, fontSize, col := ap.freeTextFont()
+
+	bs, err := ap.borderStyle()
+	if err != nil {
+		return err
+	}
+	if bs == nil {
+		// No border unless specified.
+		ap.bw = 0
+	}
+
+	// The callout line.
+	if ff, err := ap.numbers("CL"); err != nil {
+		return err
+	} else if len(ff) >= 4 {
+		ap.buf.WriteString("q " + strokeColorOp(col) + " 1 w\n")
+		ap.appendPath(ff, false)
+		ap.buf.WriteString("S\n")
+		if err := ap.renderLineEndings(ff); err != nil {
+			return err
+		}
+		ap.buf.WriteString("Q\n")
+	}
+
+	bw := ap.bw
+	box := types.NewRectangle(r.LL.X+bw/2, r.LL.Y+bw/2, r.UR.X-bw/2, r.UR.Y-bw/2)
+
+	// Background using C and border using the text colour.
+	if bg := annotationColorOp(ap.c, false); bg != "" {
+		fmt.Fprintf(&ap.buf, "%s %.2f %.2f %.2f %.2f re f\n", bg, box.LL.X, box.LL.Y, box.Width(), box.Height())
+	}
+	if bw > 0 {
+		fmt.Fprintf(&ap.buf, "%s %.2f %.2f %.2f %.2f re S\n", strokeColorOp(col), box.LL.X, box.LL.Y, box.Width(), box.Height())
+	}
+
+	if !ap.renderText {
+		return nil
+	}
+
+	s, err := ap.plainText()
+	if err != nil || s == "" {
+		return err
+	}
+	s = DecodeUTF8ToByte(s)
+
+	pad := bw + 2
+	w := box.Width() - 2*pad
+
+	q := 0
+	if i := ap.d.IntEntry("Q"); i != nil {
+		q = *i
+	}
+
+	fmt.Fprintf(&ap.buf, "q %.2f %.2f %.2f %.2f re W n %s\n", box.LL.X, box.LL.Y, box.Width(), box.Height(), col)
+
+	lh := font.LineHeight(fontName, fontSize)
+	y := box.UR.Y - pad - font.Ascent(fontName, fontSize)
+
+	for _, line := range wrapText(s, fontName, fontSize, w) {
+		x := box.LL.X + pad
+		switch q {
+		case 1:
+			x += (w - font.TextWidth(line, fontName, fontSize)) / 2
+		case 2:
+			x += w - font.TextWidth(line, fontName, fontSize)
+		}
+		ap.showText(line, fontName, fontSize, x, y)
+		y -= lh
+	}
+
+	ap.buf.WriteString("Q\n")
+
+	return nil
+}
+
+func (ap *appearance) content(subtype string) error {
+	c, err := ap.numbers("C")
+	if err != nil {
+		return err
+	}
+	ic, err := ap.numbers("IC")
+	if err != nil {
+		return err
+	}
+
+	ap.c, ap.ic = c, ic
+	ap.stroke, ap.fill = annotationColorOp(c, true), annotationColorOp(ic, false)
+
+	if err := ap.border(); err != nil {
+		return err
+	}
+
+	if err := ap.opacity(); err != nil {
+		return err
+	}
+
+	if subtype != "Text" && subtype != "FreeText" && subtype != "Caret" {
+		if ap.stroke != "" {
+			ap.buf.WriteString(ap.stroke + " ")
+		}
+		if ap.fill != "" {
+			ap.buf.WriteString(ap.fill + " ")
+		}
+		fmt.Fprintf(&ap.buf, "%.2f w", ap.bw)
+		if ap.dash != "" {
+			ap.buf.WriteString(" " + ap.dash)
+		}
+		ap.buf.WriteString("\n")
+	}
+
+	switch subtype {
+
+	case "Text":
+		ap.textAnnotation()
+
+	case "Square", "Circle":
+		return ap.square(subtype == "Circle")
+
+	case "Line":
+		return ap.line()
+
+	case "Polygon", "PolyLine":
+		return ap.polygon(subtype == "Polygon")
+
+	case "Ink":
+		return ap.ink()
+
+	case "Highlight", "Underline", "StrikeOut", "Squiggly":
+		ap.buf.Reset()
+		return ap.textMarkup(subtype)
+
+	case "Caret":
+		return ap.caret()
+
+	case "FreeText":
+		if ap.dash != "" {
+			ap.buf.WriteString(ap.dash + "\n")
+		}
+		return ap.freeText()
+
+	default:
+		// Render nothing but provide a valid appearance.
+		ap.buf.Reset()
+	}
+
+	return nil
+}
+
+// CreateAnnotationAppearance creates a normal appearance stream for the annotation dict d
+// reflecting colour, interior colour, border style, line endings and opacity.
+// Text of FreeText annotations and line captions gets rendered using core fonts if renderText is true.
+func (xRefTable *XRefTable) CreateAnnotationAppearance(d types.Dict, renderText bool) (*types.IndirectRef, error) {
+	subtype := d.Subtype()
+	if subtype == nil {
+		return nil, errors.New("pdfcpu: annotation appearance: missing subtype")
+	}
+
+	a := d.ArrayEntry("Rect")
+	if len(a) != 4 {
+		return nil, errors.New("pdfcpu: annotation appearance: missing rect")
+	}
+
+	r, err := xRefTable.RectForArray(a)
+	if err != nil {
+		return nil, err
+	}
+
+	ap := &appearance{xRefTable: xRefTable, d: d, r: r, renderText: renderText}
+
+	if err := ap.content(*subtype); err != nil {
+		return nil, err
+	}
+
+	bb := ap.buf.Bytes()
+	if ap.gs != nil {
+		bb = append([]byte("/GS0 gs\n"), bb...)
+	}
+
+	sd, err := xRefTable.NewStreamDictForBuf(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	sd.InsertName("Type", "XObject")
+	sd.InsertName("Subtype", "Form")
+	sd.Insert("BBox", r.Array())
+	sd.Insert("Matrix", types.NewNumberArray(1, 0, 0, 1, 0, 0))
+
+	res := types.Dict{}
+	if ap.gs != nil {
+		res["ExtGState"] = types.Dict(map[string]types.Object{"GS0": ap.gs})
+	}
+	if ap.fonts != nil {
+		res["Font"] = ap.fonts
+	}
+	if len(res) > 0 {
+		sd.Insert("Resources", res)
+	}
+
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+
+	return xRefTable.IndRefForNewObject(*sd)
+}
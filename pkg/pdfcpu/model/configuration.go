@@ -161,6 +161,28 @@ const (
 	INSPECTCERTIFICATES
 	IMPORTCERTIFICATES
 	VALIDATESIGNATURES
+	CONVERTPDFA
+	EXPORTSTRUCTURE
+	IMPORTSTRUCTURE
+	LISTLAYERS
+	ADDLAYER
+	REMOVELAYERS
+	SHOWLAYERS
+	HIDELAYERS
+	FLATTENLAYERS
+	LISTPAGELABELS
+	ADDPAGELABELS
+	REMOVEPAGELABELS
+	FLATTENFORMFIELDS
+	FLATTENANNOTATIONS
+	EXPORTANNOTATIONS
+	IMPORTANNOTATIONS
+	LISTXFA
+	EXTRACTXFA
+	REMOVEXFA
+	FILLXFA
+	EMBEDFONTS
+	REPLACEFONT
 )
 
 // Configuration of a Context.
@@ -184,6 +206,9 @@ type Configuration struct {
 	// Validate against ISO-32000: strict or relaxed.
 	ValidationMode int
 
+	// Additionally check conformance against eg. ISO 19005 (PDF/A).
+	Conformance Conformance
+
 	// Enable validation right before writing.
 	PostProcessValidate bool
 
@@ -277,6 +302,9 @@ type Configuration struct {
 	// Limit form field content for display purposes when using pdfcpu form list.
 	// If > 0 affects the columns AltName, Default and Value.
 	FormFieldListMaxColWidth int
+
+	// User fonts rendering characters missing in the selected font of text boxes and stamps in order of preference.
+	FallbackFonts []string
 }
 
 // ConfigPath defines the location of pdfcpu's configuration directory.
@@ -298,6 +326,9 @@ var configFileBytes []byte
 //go:embed resources/Roboto-Regular.ttf
 var robotoFontFileBytes []byte
 
+//go:embed resources/sRGB-IEC61966-2.1.icc
+var sRGBProfileBytes []byte
+
 //go:embed resources/certs/*.p7c
 var certFilesEU embed.FS
 
@@ -554,10 +585,14 @@ func (c *Configuration) EolString() string {
 
 // ValidationModeString returns a string rep for the validation mode in effect.
 func (c *Configuration) ValidationModeString() string {
+	s := "relaxed"
 	if c.ValidationMode == ValidationStrict {
-		return "strict"
+		s = "strict"
 	}
-	return "relaxed"
+	if c.Conformance != ConformanceNone {
+		s += ", " + c.Conformance.String()
+	}
+	return s
 }
 
 // PreferredCertRevocationCheckerString returns a string rep for the preferred certificate revocation checker in effect.
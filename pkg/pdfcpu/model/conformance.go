@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Conformance represents a conformance level checked on top of ISO 32000 validation.
+type Conformance int
+
+// The supported conformance levels.
+const (
+	ConformanceNone Conformance = iota
+	PDFA1B                      // ISO 19005-1 level B
+	PDFA2B                      // ISO 19005-2 level B
+	PDFA2U                      // ISO 19005-2 level U
+	PDFA3B                      // ISO 19005-3 level B
+	PDFA3U                      // ISO 19005-3 level U
+	PDFUA1                      // ISO 14289-1
+)
+
+// ConformanceFor returns the conformance level for s, eg. "pdfa-2b", "2b" or "ua".
+func ConformanceFor(s string) *Conformance {
+	s = strings.ToLower(s)
+
+	var c Conformance
+	switch s {
+	case "ua", "ua1", "ua-1", "pdfua", "pdfua1", "pdfua-1":
+		c = PDFUA1
+		return &c
+	}
+
+	s = strings.TrimPrefix(s, "pdfa")
+	s = strings.TrimPrefix(s, "-")
+
+	switch s {
+	case "1b":
+		c = PDFA1B
+	case "2b":
+		c = PDFA2B
+	case "2u":
+		c = PDFA2U
+	case "3b":
+		c = PDFA3B
+	case "3u":
+		c = PDFA3U
+	default:
+		return nil
+	}
+	return &c
+}
+
+func (c Conformance) String() string {
+	switch c {
+	case PDFA1B:
+		return "PDF/A-1b"
+	case PDFA2B:
+		return "PDF/A-2b"
+	case PDFA2U:
+		return "PDF/A-2u"
+	case PDFA3B:
+		return "PDF/A-3b"
+	case PDFA3U:
+		return "PDF/A-3u"
+	case PDFUA1:
+		return "PDF/UA-1"
+	}
+	return ""
+}
+
+// PDFA returns true if c is a PDF/A conformance level.
+func (c Conformance) PDFA() bool {
+	return c >= PDFA1B && c <= PDFA3U
+}
+
+// UA returns true if c is a PDF/UA conformance level.
+func (c Conformance) UA() bool {
+	return c == PDFUA1
+}
+
+// Part returns the ISO 19005 or ISO 14289 part number of c.
+func (c Conformance) Part() int {
+	switch c {
+	case PDFA1B:
+		return 1
+	case PDFA2B, PDFA2U:
+		return 2
+	case PDFA3B, PDFA3U:
+		return 3
+	case PDFUA1:
+		return 1
+	}
+	return 0
+}
+
+// Level returns the ISO 19005 conformance level of c as used by pdfaid:conformance.
+func (c Conformance) Level() string {
+	switch c {
+	case PDFA1B, PDFA2B, PDFA3B:
+		return "B"
+	case PDFA2U, PDFA3U:
+		return "U"
+	}
+	return ""
+}
+
+// PDFAForbiddenActions are the action types not allowed in PDF/A, see ISO 19005-2 6.6.1
+var PDFAForbiddenActions = []string{
+	"Launch", "Sound", "Movie", "ResetForm", "ImportData", "Hide",
+	"SetOCGState", "Rendition", "Trans", "GoTo3DView", "JavaScript", "RichMediaExecute",
+}
+
+// PDFAPermittedNamedActions are the named actions allowed in PDF/A, see ISO 19005-2 6.6.1
+var PDFAPermittedNamedActions = []string{"NextPage", "PrevPage", "FirstPage", "LastPage"}
+
+// PDFAForbiddenAnnotations are the annotation types not allowed in PDF/A, see ISO 19005-2 6.3.1
+var PDFAForbiddenAnnotations = []string{"Sound", "Movie", "Screen", "3D", "RichMedia", "TrapNet"}
+
+// AnnotationForbidden returns true if annotations of type subtype are not allowed for PDF/A conformance c.
+// File attachment annotations are allowed as of PDF/A-2.
+func (c Conformance) AnnotationForbidden(subtype string) bool {
+	if subtype == "FileAttachment" {
+		return c.Part() == 1
+	}
+	return types.MemberOf(subtype, PDFAForbiddenAnnotations)
+}
+
+// ConformanceViolation represents a single finding of a conformance check.
+type ConformanceViolation struct {
+	Topic  string // eg. Fonts, Metadata, Actions, StructureTree
+	PageNr int    // 0 for document level findings
+	ObjNr  int    // 0 for direct objects
+	Msg    string
+}
+
+func (v ConformanceViolation) String() string {
+	var ss []string
+	if v.PageNr > 0 {
+		ss = append(ss, fmt.Sprintf("page %d", v.PageNr))
+	}
+	if v.ObjNr > 0 {
+		ss = append(ss, fmt.Sprintf("obj#%d", v.ObjNr))
+	}
+	loc := ""
+	if len(ss) > 0 {
+		loc = " (" + strings.Join(ss, ", ") + ")"
+	}
+	return fmt.Sprintf("%s: %s%s", v.Topic, v.Msg, loc)
+}
+
+// ConformanceReport holds the findings of a conformance check.
+type ConformanceReport struct {
+	Conformance Conformance
+	Violations  []ConformanceViolation
+}
+
+// Add records a violation.
+func (r *ConformanceReport) Add(topic string, pageNr, objNr int, format string, args ...interface{}) {
+	v := ConformanceViolation{Topic: topic, PageNr: pageNr, ObjNr: objNr, Msg: fmt.Sprintf(format, args...)}
+	for _, v1 := range r.Violations {
+		if v1 == v {
+			return
+		}
+	}
+	r.Violations = append(r.Violations, v)
+}
+
+// Conforming returns true if no violations have been recorded.
+func (r ConformanceReport) Conforming() bool {
+	return len(r.Violations) == 0
+}
+
+// ListViolations returns a sorted list of all violations grouped by topic.
+func (r ConformanceReport) ListViolations() []string {
+	vv := make([]ConformanceViolation, len(r.Violations))
+	copy(vv, r.Violations)
+	sort.SliceStable(vv, func(i, j int) bool {
+		if vv[i].Topic != vv[j].Topic {
+			return vv[i].Topic < vv[j].Topic
+		}
+		if vv[i].PageNr != vv[j].PageNr {
+			return vv[i].PageNr < vv[j].PageNr
+		}
+		if vv[i].ObjNr != vv[j].ObjNr {
+			return vv[i].ObjNr < vv[j].ObjNr
+		}
+		return vv[i].Msg < vv[j].Msg
+	})
+	ss := make([]string, len(vv))
+	for i, v := range vv {
+		ss[i] = v.String()
+	}
+	return ss
+}
+
+func (r ConformanceReport) String() string {
+	if r.Conforming() {
+		return fmt.Sprintf("%s: conforming", r.Conformance)
+	}
+	ss := []string{fmt.Sprintf("%s: %d violation(s)", r.Conformance, len(r.Violations))}
+	for _, s := range r.ListViolations() {
+		ss = append(ss, "  "+s)
+	}
+	return strings.Join(ss, "\n")
+}
+
+// SRGBICCProfile returns the bundled sRGB IEC61966-2.1 ICC profile (version 2)
+// suitable as DestOutputProfile of a PDF/A output intent.
+func SRGBICCProfile() []byte {
+	return sRGBProfileBytes
+}
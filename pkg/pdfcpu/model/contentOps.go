@@ -0,0 +1,241 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// ContentOp represents a content stream operator along with its operands.
+// For inline images (BI) the operands hold the image dict tokens.
+// Pos and End delimit the operation including its operands within the content stream.
+type ContentOp struct {
+	Name     string
+	Operands []string
+	Pos, End int
+}
+
+func contentWhitespace(c byte) bool {
+	return c == 0x00 || c == 0x09 || c == 0x0A || c == 0x0C || c == 0x0D || c == 0x20
+}
+
+func contentDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func skipContentStringLiteral(bb []byte, i int) (int, error) {
+	// bb[i] == '('
+	depth := 0
+	for ; i < len(bb); i++ {
+		switch bb[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, errors.New("pdfcpu: content: unterminated string literal")
+}
+
+func skipContentDict(bb []byte, i int) (int, error) {
+	// bb[i:i+2] == "<<"
+	depth := 0
+	for i < len(bb) {
+		switch {
+		case bb[i] == '(':
+			j, err := skipContentStringLiteral(bb, i)
+			if err != nil {
+				return 0, err
+			}
+			i = j
+			continue
+		case bytes.HasPrefix(bb[i:], []byte("<<")):
+			depth++
+			i += 2
+			continue
+		case bytes.HasPrefix(bb[i:], []byte(">>")):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i, nil
+			}
+			continue
+		}
+		i++
+	}
+	return 0, errors.New("pdfcpu: content: unterminated dict")
+}
+
+func skipInlineImageData(bb []byte, i int) (int, error) {
+	// Skip the single whitespace following ID.
+	i++
+	for j := i; j+2 <= len(bb); j++ {
+		if bb[j] != 'E' || bb[j+1] != 'I' {
+			continue
+		}
+		if j > 0 && !contentWhitespace(bb[j-1]) {
+			continue
+		}
+		if j+2 < len(bb) && !contentWhitespace(bb[j+2]) {
+			continue
+		}
+		return j + 2, nil
+	}
+	return 0, errors.New("pdfcpu: content: inline image without EI")
+}
+
+func scanContentToken(bb []byte, i int) (string, int, error) {
+	for i < len(bb) {
+		if contentWhitespace(bb[i]) {
+			i++
+			continue
+		}
+		if bb[i] == '%' {
+			for i < len(bb) && bb[i] != 0x0A && bb[i] != 0x0D {
+				i++
+			}
+			continue
+		}
+		break
+	}
+
+	if i >= len(bb) {
+		return "", i, nil
+	}
+
+	start := i
+
+	switch c := bb[i]; {
+
+	case c == '(':
+		j, err := skipContentStringLiteral(bb, i)
+		if err != nil {
+			return "", 0, err
+		}
+		return string(bb[start:j]), j, nil
+
+	case c == '<':
+		if i+1 < len(bb) && bb[i+1] == '<' {
+			j, err := skipContentDict(bb, i)
+			if err != nil {
+				return "", 0, err
+			}
+			return string(bb[start:j]), j, nil
+		}
+		j := bytes.IndexByte(bb[i:], '>')
+		if j < 0 {
+			return "", 0, errors.New("pdfcpu: content: unterminated hex string")
+		}
+		return string(bb[start : i+j+1]), i + j + 1, nil
+
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		return string(c), i + 1, nil
+
+	case c == '/':
+		i++
+	}
+
+	for i < len(bb) && !contentWhitespace(bb[i]) && !contentDelimiter(bb[i]) {
+		i++
+	}
+
+	if i == start {
+		// Stray delimiter like ')' or '>'.
+		i++
+	}
+
+	return string(bb[start:i]), i, nil
+}
+
+func contentOperand(s string) bool {
+	if s == "" {
+		return false
+	}
+	switch s[0] {
+	case '(', '<', '[', ']', '/', '{', '}', '+', '-', '.', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	return s == "true" || s == "false" || s == "null"
+}
+
+// ParseContentOps tokenizes a content stream and calls fn for every operator encountered.
+func ParseContentOps(bb []byte, fn func(op ContentOp) error) error {
+	var operands []string
+	pos := -1
+
+	for i := 0; i < len(bb); {
+
+		t, j, err := scanContentToken(bb, i)
+		if err != nil {
+			return err
+		}
+		if t == "" {
+			break
+		}
+		i = j
+
+		if pos < 0 {
+			pos = j - len(t)
+		}
+
+		if contentOperand(t) {
+			operands = append(operands, t)
+			continue
+		}
+
+		if t == "BI" {
+			// Collect the inline image dict up to ID.
+			var dict []string
+			for {
+				t, j, err = scanContentToken(bb, i)
+				if err != nil {
+					return err
+				}
+				if t == "" {
+					return errors.New("pdfcpu: content: inline image without ID")
+				}
+				i = j
+				if t == "ID" {
+					break
+				}
+				dict = append(dict, t)
+			}
+			if i, err = skipInlineImageData(bb, i); err != nil {
+				return err
+			}
+			if err := fn(ContentOp{Name: "BI", Operands: dict, Pos: pos, End: i}); err != nil {
+				return err
+			}
+			operands, pos = nil, -1
+			continue
+		}
+
+		if err := fn(ContentOp{Name: t, Operands: operands, Pos: pos, End: i}); err != nil {
+			return err
+		}
+		operands, pos = nil, -1
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// AnnotationVisible returns true if the annotation d is displayed, ie. neither Hidden nor NoView is set.
+// A missing F entry is equivalent to F 0.
+func AnnotationVisible(d types.Dict) bool {
+	return annotationFlags(d)&(AnnHidden|AnnNoView) == 0
+}
+
+// AnnotationPrintable returns true if the annotation d is visible and has its Print flag set.
+// Since flattened appearances become page content which is displayed and printed alike
+// only printable annotations get flattened.
+func AnnotationPrintable(d types.Dict) bool {
+	return AnnotationVisible(d) && annotationFlags(d)&AnnPrint > 0
+}
+
+func annotationFlags(d types.Dict) AnnotationFlags {
+	var flags AnnotationFlags
+	if f := d.IntEntry("F"); f != nil {
+		flags = AnnotationFlags(*f)
+	}
+	return flags
+}
+
+// NormalAppearance returns the normal appearance stream of the annotation d honouring its appearance state.
+func (xRefTable *XRefTable) NormalAppearance(d types.Dict) (*types.IndirectRef, error) {
+	o, found := d.Find("AP")
+	if !found {
+		return nil, nil
+	}
+
+	ap, err := xRefTable.DereferenceDict(o)
+	if err != nil || ap == nil {
+		return nil, err
+	}
+
+	o, found = ap.Find("N")
+	if !found {
+		return nil, nil
+	}
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		o1, err := xRefTable.Dereference(ir)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := o1.(types.StreamDict); ok {
+			return &ir, nil
+		}
+	}
+
+	// Appearance subdictionary
+	n, err := xRefTable.DereferenceDict(o)
+	if err != nil || n == nil {
+		return nil, err
+	}
+
+	as := d.NameEntry("AS")
+	if as == nil {
+		if len(n) != 1 {
+			return nil, nil
+		}
+		for k := range n {
+			as = &k
+		}
+	}
+
+	return n.IndirectRefEntry(*as), nil
+}
+
+func (xRefTable *XRefTable) appearanceTransform(sd *types.StreamDict, r *types.Rectangle) ([6]float64, error) {
+	var m [6]float64
+
+	bbox := types.NewRectangle(0, 0, r.Width(), r.Height())
+	if a := sd.ArrayEntry("BBox"); len(a) == 4 {
+		rect, err := xRefTable.RectForArray(a)
+		if err != nil {
+			return m, err
+		}
+		bbox = rect
+	}
+
+	am := [6]float64{1, 0, 0, 1, 0, 0}
+	if a := sd.ArrayEntry("Matrix"); len(a) == 6 {
+		for i, o := range a {
+			f, err := xRefTable.DereferenceNumber(o)
+			if err != nil {
+				return m, err
+			}
+			am[i] = f
+		}
+	}
+
+	// Transform the appearance bounding box by its matrix.
+	minX, minY := math.MaxFloat64, math.MaxFloat64
+	maxX, maxY := -math.MaxFloat64, -math.MaxFloat64
+	for _, p := range []types.Point{
+		{X: bbox.LL.X, Y: bbox.LL.Y}, {X: bbox.UR.X, Y: bbox.LL.Y},
+		{X: bbox.UR.X, Y: bbox.UR.Y}, {X: bbox.LL.X, Y: bbox.UR.Y}} {
+		x := am[0]*p.X + am[2]*p.Y + am[4]
+		y := am[1]*p.X + am[3]*p.Y + am[5]
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+
+	// Map the transformed box onto the annotation rectangle.
+	sx, sy := 1., 1.
+	if maxX-minX > 0 {
+		sx = r.Width() / (maxX - minX)
+	}
+	if maxY-minY > 0 {
+		sy = r.Height() / (maxY - minY)
+	}
+
+	return [6]float64{sx, 0, 0, sy, r.LL.X - minX*sx, r.LL.Y - minY*sy}, nil
+}
+
+func (xRefTable *XRefTable) addXObjectResource(resDict types.Dict, ir types.IndirectRef, used map[string]bool) (string, error) {
+	var d types.Dict
+
+	if o, found := resDict.Find("XObject"); found {
+		d1, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return "", err
+		}
+		d = d1
+		if _, ok := o.(types.IndirectRef); ok && d1 != nil {
+			// The XObject dict may be shared with other pages.
+			d = d1.Clone().(types.Dict)
+		}
+	}
+
+	if d == nil {
+		d = types.Dict{}
+	}
+	resDict["XObject"] = d
+
+	for i := 0; ; i++ {
+		id := "Fx" + strconv.Itoa(i)
+		if _, found := d.Find(id); found || used[id] {
+			continue
+		}
+		d[id] = ir
+		used[id] = true
+		return id, nil
+	}
+}
+
+func (xRefTable *XRefTable) appendPageContentIsolated(pageDict types.Dict, bb []byte) error {
+	o, found := pageDict.Find("Contents")
+	if !found {
+		return xRefTable.insertContent(pageDict, bb)
+	}
+
+	// Protect the new content from any graphics state left over by the existing content.
+	irq, err := xRefTable.StreamDictIndRef([]byte("q"))
+	if err != nil {
+		return err
+	}
+
+	ir, err := xRefTable.StreamDictIndRef(append([]byte("Q "), bb...))
+	if err != nil {
+		return err
+	}
+
+	arr := types.Array{*irq}
+
+	o1, err := xRefTable.Dereference(o)
+	if err != nil {
+		return err
+	}
+
+	if a, ok := o1.(types.Array); ok {
+		arr = append(arr, a...)
+	} else {
+		arr = append(arr, o)
+	}
+
+	pageDict["Contents"] = append(arr, *ir)
+
+	return nil
+}
+
+// FlattenAppearances merges the normal appearances of annots into the content of page pageNr.
+// Annotations which are not printable are skipped, see AnnotationPrintable.
+// Returns the number of flattened annotations.
+func (xRefTable *XRefTable) FlattenAppearances(pageNr int, annots []types.Dict) (int, error) {
+	pageDict, _, inhPAttrs, err := xRefTable.PageDict(pageNr, false)
+	if err != nil {
+		return 0, err
+	}
+
+	// Resources may be shared with other pages or inherited from the page tree.
+	resDict := types.Dict{}
+	if inhPAttrs.Resources != nil {
+		resDict = inhPAttrs.Resources.Clone().(types.Dict)
+	}
+
+	var (
+		buf  bytes.Buffer
+		used = map[string]bool{}
+		c    int
+	)
+
+	for _, d := range annots {
+
+		if !AnnotationPrintable(d) {
+			continue
+		}
+
+		ir, err := xRefTable.NormalAppearance(d)
+		if err != nil {
+			return 0, err
+		}
+		if ir == nil {
+			continue
+		}
+
+		sd, _, err := xRefTable.DereferenceStreamDict(*ir)
+		if err != nil {
+			return 0, err
+		}
+		if sd == nil {
+			continue
+		}
+
+		a := d.ArrayEntry("Rect")
+		if len(a) != 4 {
+			continue
+		}
+		r, err := xRefTable.RectForArray(a)
+		if err != nil {
+			return 0, err
+		}
+
+		// Ensure a proper form XObject.
+		sd.InsertName("Type", "XObject")
+		sd.InsertName("Subtype", "Form")
+		if _, found := sd.Find("BBox"); !found {
+			sd.Insert("BBox", types.NewRectangle(0, 0, r.Width(), r.Height()).Array())
+		}
+
+		m, err := xRefTable.appearanceTransform(sd, r)
+		if err != nil {
+			return 0, err
+		}
+
+		id, err := xRefTable.addXObjectResource(resDict, *ir, used)
+		if err != nil {
+			return 0, err
+		}
+
+		fmt.Fprintf(&buf, "q %.5f %.5f %.5f %.5f %.5f %.5f cm /%s Do Q ", m[0], m[1], m[2], m[3], m[4], m[5], id)
+		c++
+	}
+
+	if c == 0 {
+		return 0, nil
+	}
+
+	pageDict["Resources"] = resDict
+
+	return c, xRefTable.appendPageContentIsolated(pageDict, buf.Bytes())
+}
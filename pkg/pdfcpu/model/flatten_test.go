@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestAnnotationVisible(t *testing.T) {
+	for _, tt := range []struct {
+		f    types.Object
+		want bool
+	}{
+		{nil, true}, // missing F equals F 0
+		{types.Integer(0), true},
+		{types.Integer(AnnPrint), true},
+		{types.Integer(AnnPrint | AnnLocked), true},
+		{types.Integer(AnnHidden), false},
+		{types.Integer(AnnNoView), false},
+		{types.Integer(AnnPrint | AnnHidden), false},
+		{types.Integer(AnnPrint | AnnNoView), false},
+	} {
+		d := types.Dict{"Subtype": types.Name("Square")}
+		if tt.f != nil {
+			d["F"] = tt.f
+		}
+		if got := AnnotationVisible(d); got != tt.want {
+			t.Errorf("F %v: got %t want %t", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestAnnotationPrintable(t *testing.T) {
+	for _, tt := range []struct {
+		f    types.Object
+		want bool
+	}{
+		{nil, false}, // missing F equals F 0
+		{types.Integer(0), false},
+		{types.Integer(AnnPrint), true},
+		{types.Integer(AnnPrint | AnnLocked), true},
+		{types.Integer(AnnHidden), false},
+		{types.Integer(AnnPrint | AnnHidden), false},
+		{types.Integer(AnnPrint | AnnNoView), false},
+	} {
+		d := types.Dict{"Subtype": types.Name("Square")}
+		if tt.f != nil {
+			d["F"] = tt.f
+		}
+		if got := AnnotationPrintable(d); got != tt.want {
+			t.Errorf("F %v: got %t want %t", tt.f, got, tt.want)
+		}
+	}
+}
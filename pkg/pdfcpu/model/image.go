@@ -18,11 +18,10 @@ package model
 
 import (
 	"bytes"
-	"encoding/binary"
-	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
+	"image/gif"
 	"image/jpeg"
 	_ "image/png"
 
@@ -32,11 +31,11 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/hhrutter/tiff"
-
+	_ "github.com/pdfcpu/pdfcpu/internal/jpx"
 	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
+	_ "golang.org/x/image/bmp"
 	_ "golang.org/x/image/webp"
 )
 
@@ -65,7 +64,7 @@ type Image struct {
 // ImageFileName returns true for supported image file types.
 func ImageFileName(fileName string) bool {
 	ext := strings.ToLower(filepath.Ext(fileName))
-	return types.MemberOf(ext, []string{".png", ".webp", ".tif", ".tiff", ".jpg", ".jpeg"})
+	return types.MemberOf(ext, []string{".png", ".webp", ".tif", ".tiff", ".jpg", ".jpeg", ".jp2", ".j2k", ".jpx", ".bmp", ".gif"})
 }
 
 // ImageFileNames returns a slice of image file names contained in dir constrained by maxFileSize.
@@ -159,6 +158,47 @@ func CreateFlateImageStreamDict(xRefTable *XRefTable, buf, sm []byte, w, h, bpc
 	return sd, nil
 }
 
+func ccittImageStreamDict(w, h int, parms types.Dict) *types.StreamDict {
+	sd := &types.StreamDict{
+		Dict: types.Dict(
+			map[string]types.Object{
+				"Type":             types.Name("XObject"),
+				"Subtype":          types.Name("Image"),
+				"Width":            types.Integer(w),
+				"Height":           types.Integer(h),
+				"BitsPerComponent": types.Integer(1),
+				"ColorSpace":       types.Name(DeviceGrayCS),
+				"DecodeParms":      parms,
+			},
+		),
+		FilterPipeline: []types.PDFFilter{{Name: filter.CCITTFax, DecodeParms: parms}},
+	}
+
+	sd.InsertName("Filter", filter.CCITTFax)
+
+	return sd
+}
+
+// CreateCCITTImageStreamDict returns a CCITT Group 4 encoded stream dict for a bilevel image given as rows of packed 1-bit gray values.
+func CreateCCITTImageStreamDict(xRefTable *XRefTable, buf []byte, w, h int) (*types.StreamDict, error) {
+	parms := types.Dict(
+		map[string]types.Object{
+			"K":       types.Integer(-1),
+			"Columns": types.Integer(w),
+			"Rows":    types.Integer(h),
+		},
+	)
+
+	sd := ccittImageStreamDict(w, h, parms)
+	sd.Content = buf
+
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
 // CreateDCTImageStreamDict returns a DCT encoded stream dict.
 func CreateDCTImageStreamDict(xRefTable *XRefTable, buf []byte, w, h, bpc int, cs string) (*types.StreamDict, error) {
 	sd := &types.StreamDict{
@@ -401,6 +441,72 @@ func writeGrayImageBuf(img image.Image) []byte {
 	return buf
 }
 
+// bilevelKind returns 0 for opaque black, 1 for opaque white and 2 for any other color.
+func bilevelKind(c color.Color) byte {
+	switch r, g, b, a := c.RGBA(); {
+	case a != 0xFFFF:
+	case r == 0 && g == 0 && b == 0:
+		return 0
+	case r == 0xFFFF && g == 0xFFFF && b == 0xFFFF:
+		return 1
+	}
+	return 2
+}
+
+// writeBilevelImageBuf returns the rows of packed 1-bit gray values of img if img consists of opaque black and white pixels only.
+func writeBilevelImageBuf(img image.Image) ([]byte, bool) {
+	var white func(x, y int) (bool, bool)
+
+	switch im := img.(type) {
+	case *image.Gray:
+		white = func(x, y int) (bool, bool) {
+			v := im.GrayAt(x, y).Y
+			return v == 0xFF, v == 0 || v == 0xFF
+		}
+
+	case *image.Paletted:
+		kinds := make([]byte, len(im.Palette))
+		for i, c := range im.Palette {
+			kinds[i] = bilevelKind(c)
+		}
+		white = func(x, y int) (bool, bool) {
+			i := int(im.ColorIndexAt(x, y))
+			if i >= len(kinds) {
+				return false, false
+			}
+			return kinds[i] == 1, kinds[i] < 2
+		}
+
+	case *image.RGBA:
+		white = func(x, y int) (bool, bool) {
+			k := bilevelKind(im.RGBAAt(x, y))
+			return k == 1, k < 2
+		}
+
+	default:
+		return nil, false
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	stride := (w + 7) / 8
+	buf := make([]byte, stride*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v, ok := white(b.Min.X+x, b.Min.Y+y)
+			if !ok {
+				return nil, false
+			}
+			if v {
+				buf[y*stride+x>>3] |= 0x80 >> uint(x&7)
+			}
+		}
+	}
+
+	return buf, true
+}
+
 func writeGray16ImageBuf(img image.Image) []byte {
 	w := img.Bounds().Dx()
 	h := img.Bounds().Dy()
@@ -546,9 +652,11 @@ func createImageStreamDict(xRefTable *XRefTable, buf, softMask []byte, w, h, bpc
 		sd  *types.StreamDict
 		err error
 	)
-	switch format {
-	case "jpeg":
+	switch {
+	case format == "jpeg":
 		sd, err = CreateDCTImageStreamDict(xRefTable, buf, w, h, bpc, cs)
+	case bpc == 1:
+		sd, err = CreateCCITTImageStreamDict(xRefTable, buf, w, h)
 	default:
 		sd, err = CreateFlateImageStreamDict(xRefTable, buf, softMask, w, h, bpc, cs)
 	}
@@ -667,6 +775,13 @@ func createImageBuf(xRefTable *XRefTable, img image.Image, imgA image.Image, for
 		return bb, nil, 8, cs, err
 	}
 
+	if imgA == nil {
+		// Black and white images compress best using CCITT Group 4.
+		if buf, ok := writeBilevelImageBuf(img); ok {
+			return buf, nil, 1, DeviceGrayCS, nil
+		}
+	}
+
 	switch im := img.(type) {
 	case *image.RGBA, *image.RGBA64, *image.NRGBA, *image.NRGBA64,
 		*image.YCbCr, *image.NYCbCrA, *image.Paletted:
@@ -720,12 +835,8 @@ func createImageResourcesForJPEG(xRefTable *XRefTable, c image.Config, bb bytes.
 	return []ImageResource{ir}, err
 }
 
-func decodeImage(xRefTable *XRefTable, buf *bytes.Reader, currentOffset int64, gray, sepia bool, byteOrder binary.ByteOrder, imgResources *[]ImageResource) (int64, error) {
-	img, err := tiff.DecodeAt(buf, currentOffset)
-	if err != nil {
-		return 0, err
-	}
-
+// createImageResource creates a new XObject for img and applies optional filters.
+func createImageResource(xRefTable *XRefTable, img image.Image, format string, gray, sepia bool) (ImageResource, error) {
 	if gray {
 		switch img.(type) {
 		case *image.Gray, *image.Gray16:
@@ -742,136 +853,94 @@ func decodeImage(xRefTable *XRefTable, buf *bytes.Reader, currentOffset int64, g
 		}
 	}
 
-	imgBuf, softMask, bpc, cs, err := createImageBuf(xRefTable, img, nil, "tiff")
+	imgBuf, softMask, bpc, cs, err := createImageBuf(xRefTable, img, nil, format)
 	if err != nil {
-		return 0, err
+		return ImageResource{}, err
 	}
 
 	w, h := img.Bounds().Dx(), img.Bounds().Dy()
 
-	sd, err := createImageStreamDict(xRefTable, imgBuf, softMask, w, h, bpc, "tiff", cs)
+	sd, err := createImageStreamDict(xRefTable, imgBuf, softMask, w, h, bpc, format, cs)
 	if err != nil {
-		return 0, err
+		return ImageResource{}, err
 	}
 
 	indRef, err := xRefTable.IndRefForNewObject(*sd)
 	if err != nil {
-		return 0, err
+		return ImageResource{}, err
 	}
 
 	res := Resource{ID: "Im0", IndRef: indRef}
-	ir := ImageResource{Res: res, Width: w, Height: h}
-	*imgResources = append(*imgResources, ir)
-
-	if _, err := buf.Seek(currentOffset, io.SeekStart); err != nil {
-		return 0, err
-	}
+	return ImageResource{Res: res, Width: w, Height: h}, nil
+}
 
-	var numEntries uint16
-	if err := binary.Read(buf, byteOrder, &numEntries); err != nil {
-		return 0, err
+func createImageResources(xRefTable *XRefTable, c image.Config, bb bytes.Buffer, gray, sepia bool) ([]ImageResource, error) {
+	img, format, err := image.Decode(&bb)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, err := buf.Seek(int64(numEntries)*12, io.SeekCurrent); err != nil {
-		return 0, err
+	if w, h := img.Bounds().Dx(), img.Bounds().Dy(); w != c.Width || h != c.Height {
+		return nil, errors.New("pdfcpu: unexpected width or height")
 	}
 
-	var nextIFDOffset uint32
-	if err := binary.Read(buf, byteOrder, &nextIFDOffset); err != nil {
-		return 0, err
+	ir, err := createImageResource(xRefTable, img, format, gray, sepia)
+	if err != nil {
+		return nil, err
 	}
 
-	// if nextIFDOffset >= uint32(bb.Len()) {
-	// 	fmt.Println("Invalid next IFD offset, stopping.")
-	// 	break
-	// }
-
-	return int64(nextIFDOffset), nil
+	return []ImageResource{ir}, nil
 }
 
-func createImageResourcesForTIFF(xRefTable *XRefTable, bb bytes.Buffer, gray, sepia bool) ([]ImageResource, error) {
-	imgResources := []ImageResource{}
-
-	buf := bytes.NewReader(bb.Bytes())
-
-	var header [8]byte
-	if _, err := io.ReadFull(buf, header[:]); err != nil {
+// createImageResourcesForGIF creates a new XObject for each frame of an animated GIF.
+func createImageResourcesForGIF(xRefTable *XRefTable, bb bytes.Buffer, gray, sepia bool) ([]ImageResource, error) {
+	g, err := gif.DecodeAll(&bb)
+	if err != nil {
 		return nil, err
 	}
 
-	var byteOrder binary.ByteOrder
-	if string(header[:2]) == "II" {
-		byteOrder = binary.LittleEndian
-	} else if string(header[:2]) == "MM" {
-		byteOrder = binary.BigEndian
-	} else {
-		return nil, fmt.Errorf("invalid TIFF byte order")
-	}
-
-	firstIFDOffset := byteOrder.Uint32(header[4:])
-	if firstIFDOffset < 8 || firstIFDOffset >= uint32(bb.Len()) {
-		return nil, fmt.Errorf("invalid TIFF file: no valid IFD")
-	}
-
-	var err error
-
-	off := int64(firstIFDOffset)
-
-	for off != 0 && off < int64(bb.Len()) {
-		off, err = decodeImage(xRefTable, buf, off, gray, sepia, byteOrder, &imgResources)
+	r := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if len(g.Image) == 1 && g.Image[0].Bounds() == r {
+		ir, err := createImageResource(xRefTable, g.Image[0], "gif", gray, sepia)
 		if err != nil {
 			return nil, err
 		}
+		return []ImageResource{ir}, nil
 	}
 
-	return imgResources, nil
-}
+	// Frames may cover parts of the canvas only and are rendered on top of their predecessors according to their disposal method.
+	canvas := image.NewRGBA(r)
 
-func createImageResources(xRefTable *XRefTable, c image.Config, bb bytes.Buffer, gray, sepia bool) ([]ImageResource, error) {
-	img, format, err := image.Decode(&bb)
-	if err != nil {
-		return nil, err
-	}
+	imgResources := []ImageResource{}
 
-	if gray {
-		switch img.(type) {
-		case *image.Gray, *image.Gray16:
-		default:
-			img = convertToGray(img)
+	for i, frame := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
 		}
-	}
 
-	if sepia {
-		switch img.(type) {
-		case *image.Gray, *image.Gray16:
-		default:
-			img = convertToSepia(img)
+		var prev []byte
+		if disposal == gif.DisposalPrevious {
+			prev = append(prev, canvas.Pix...)
 		}
-	}
-
-	imgBuf, softMask, bpc, cs, err := createImageBuf(xRefTable, img, nil, format)
-	if err != nil {
-		return nil, err
-	}
 
-	w, h := img.Bounds().Dx(), img.Bounds().Dy()
-	if w != c.Width || h != c.Height {
-		return nil, errors.New("pdfcpu: unexpected width or height")
-	}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
 
-	sd, err := createImageStreamDict(xRefTable, imgBuf, softMask, w, h, bpc, format, cs)
-	if err != nil {
-		return nil, err
-	}
+		ir, err := createImageResource(xRefTable, canvas, "gif", gray, sepia)
+		if err != nil {
+			return nil, err
+		}
+		imgResources = append(imgResources, ir)
 
-	indRef, err := xRefTable.IndRefForNewObject(*sd)
-	if err != nil {
-		return nil, err
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, prev)
+		}
 	}
 
-	res := Resource{ID: "Im0", IndRef: indRef}
-	ir := ImageResource{Res: res, Width: w, Height: h}
-	return []ImageResource{ir}, err
+	return imgResources, nil
 }
 
 // CreateImageResources creates a new XObject for given image data represented by r and applies optional filters.
@@ -894,6 +963,10 @@ func CreateImageResources(xRefTable *XRefTable, r io.Reader, gray, sepia bool) (
 		return createImageResourcesForTIFF(xRefTable, bb, gray, sepia)
 	}
 
+	if format == "gif" {
+		return createImageResourcesForGIF(xRefTable, bb, gray, sepia)
+	}
+
 	if format == "jpeg" && !gray && !sepia {
 		return createImageResourcesForJPEG(xRefTable, c, bb)
 	}
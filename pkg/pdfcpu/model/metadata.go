@@ -17,7 +17,10 @@ limitations under the License.
 package model
 
 import (
+	"bytes"
 	"encoding/xml"
+	"io"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -100,6 +103,124 @@ type XMPMeta struct {
 	RDF     RDF
 }
 
+// XMP namespaces of the PDF/A and PDF/UA identification schemas.
+const (
+	NamespacePDFAID  = "http://www.aiim.org/pdfa/ns/id/"
+	NamespacePDFUAID = "http://www.aiim.org/pdfua/ns/id/"
+)
+
+// PDFAID represents the PDF/A identification schema (pdfaid) of XMP metadata.
+type PDFAID struct {
+	Part        int
+	Conformance string
+}
+
+// parseXMPProperties returns all properties of namespace ns found in XMP metadata bb.
+// Properties may be encoded as attributes or elements of rdf:Description.
+func parseXMPProperties(bb []byte, ns string) (map[string]string, error) {
+	var (
+		m     map[string]string
+		local string
+	)
+
+	set := func(k, v string) {
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[k] = strings.TrimSpace(v)
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				if attr.Name.Space == ns {
+					set(attr.Name.Local, attr.Value)
+				}
+			}
+			local = ""
+			if t.Name.Space == ns {
+				local = t.Name.Local
+			}
+		case xml.CharData:
+			if local != "" {
+				set(local, string(t))
+			}
+		case xml.EndElement:
+			local = ""
+		}
+	}
+
+	return m, nil
+}
+
+// ParsePDFAID returns the PDF/A identification found in XMP metadata bb or nil.
+func ParsePDFAID(bb []byte) (*PDFAID, error) {
+	m, err := parseXMPProperties(bb, NamespacePDFAID)
+	if err != nil || m == nil {
+		return nil, err
+	}
+
+	id := PDFAID{Conformance: strings.ToUpper(m["conformance"])}
+	if i, err := strconv.Atoi(m["part"]); err == nil {
+		id.Part = i
+	}
+
+	return &id, nil
+}
+
+// ParsePDFUAID returns the PDF/UA part found in XMP metadata bb or 0.
+func ParsePDFUAID(bb []byte) (int, error) {
+	m, err := parseXMPProperties(bb, NamespacePDFUAID)
+	if err != nil || m == nil {
+		return 0, err
+	}
+
+	i, _ := strconv.Atoi(m["part"])
+
+	return i, nil
+}
+
+// HasXMPTitle returns true if XMP metadata bb contains a non empty dc:title.
+func HasXMPTitle(bb []byte) (bool, error) {
+	const nsDC = "http://purl.org/dc/elements/1.1/"
+
+	depth := 0
+
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			if depth > 0 || t.Name.Space == nsDC && t.Name.Local == "title" {
+				depth++
+			}
+		case xml.EndElement:
+			if depth > 0 {
+				depth--
+			}
+		case xml.CharData:
+			if depth > 0 && len(bytes.TrimSpace(t)) > 0 {
+				return true, nil
+			}
+		}
+	}
+}
+
 func removeTag(s, kw string) string {
 	kwLen := len(kw)
 	i := strings.Index(s, kw)
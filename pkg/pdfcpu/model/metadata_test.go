@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import "testing"
+
+func TestParsePDFAID(t *testing.T) {
+	for _, tt := range []struct {
+		xmp         string
+		part        int
+		conformance string
+	}{
+		{`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/" pdfaid:part="2" pdfaid:conformance="b"/>
+</rdf:RDF></x:xmpmeta>`, 2, "B"},
+		{`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+<pdfaid:part>3</pdfaid:part><pdfaid:conformance>U</pdfaid:conformance>
+</rdf:Description></rdf:RDF></x:xmpmeta>`, 3, "U"},
+	} {
+		id, err := ParsePDFAID([]byte(tt.xmp))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id == nil {
+			t.Fatal("missing pdfaid")
+		}
+		if id.Part != tt.part || id.Conformance != tt.conformance {
+			t.Fatalf("want: %d%s, got: %d%s", tt.part, tt.conformance, id.Part, id.Conformance)
+		}
+	}
+
+	id, err := ParsePDFAID([]byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != nil {
+		t.Fatal("unexpected pdfaid")
+	}
+}
+
+func TestParsePDFUAID(t *testing.T) {
+	xmp := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfuaid="http://www.aiim.org/pdfua/ns/id/" pdfuaid:part="1"/>
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">Title</rdf:li></rdf:Alt></dc:title>
+</rdf:Description></rdf:RDF></x:xmpmeta>`
+
+	part, err := ParsePDFUAID([]byte(xmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part != 1 {
+		t.Fatalf("want: 1, got: %d", part)
+	}
+
+	ok, err := HasXMPTitle([]byte(xmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("missing dc:title")
+	}
+
+	ok, err = HasXMPTitle([]byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("unexpected dc:title")
+	}
+}
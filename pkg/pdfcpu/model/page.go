@@ -18,6 +18,7 @@ package model
 
 import (
 	"bytes"
+	"fmt"
 	"strconv"
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
@@ -58,9 +59,10 @@ func (fm FontMap) EnsureKey(fontName string) string {
 
 // ImageResource represents an existing PDF image resource.
 type ImageResource struct {
-	Res    Resource
-	Width  int
-	Height int
+	Res        Resource
+	Width      int
+	Height     int
+	DPIX, DPIY float64 // resolution, 0 if unknown
 }
 
 // ImageMap maps image filenames to image resources.
@@ -76,15 +78,104 @@ type FieldAnnotation struct {
 
 // Page represents rendered page content.
 type Page struct {
-	MediaBox   *types.Rectangle
-	CropBox    *types.Rectangle
-	Fm         FontMap
-	Im         ImageMap
-	Annots     []FieldAnnotation
-	AnnotTabs  map[int]FieldAnnotation
-	LinkAnnots []LinkAnnotation
-	Buf        *bytes.Buffer
-	Fields     types.Array
+	MediaBox    *types.Rectangle
+	CropBox     *types.Rectangle
+	Fm          FontMap
+	Im          ImageMap
+	Annots      []FieldAnnotation
+	AnnotTabs   map[int]FieldAnnotation
+	LinkAnnots  []LinkAnnotation
+	Buf         *bytes.Buffer
+	Fields      types.Array
+	Tagged      bool          // emit marked content and structure elements
+	StructElems []*StructElem // top level structure elements of this page
+	tags        []*StructElem // open structure elements
+	artifacts   int           // open artifact nesting level
+	mcid        int           // next marked content id
+}
+
+// StructElem represents a structure element of tagged page content.
+type StructElem struct {
+	Type  string              // standard structure type eg. P, H1, Figure, Table, TR, TD, Form
+	Alt   string              // alternate description
+	MCIDs []int               // marked content ids of this page
+	Objs  []types.IndirectRef // referenced annotations
+	Kids  []*StructElem
+}
+
+func (p *Page) inArtifact() bool {
+	return !p.Tagged || p.artifacts > 0
+}
+
+// BeginStructElem opens a structure element of type s.
+// Subsequent structure elements become kids until EndStructElem gets called.
+func (p *Page) BeginStructElem(s, alt string) *StructElem {
+	if p.inArtifact() {
+		return nil
+	}
+
+	se := &StructElem{Type: s, Alt: alt}
+
+	if len(p.tags) == 0 {
+		p.StructElems = append(p.StructElems, se)
+	} else {
+		parent := p.tags[len(p.tags)-1]
+		parent.Kids = append(parent.Kids, se)
+	}
+
+	p.tags = append(p.tags, se)
+
+	return se
+}
+
+// EndStructElem closes the current structure element.
+func (p *Page) EndStructElem() {
+	if p.inArtifact() || len(p.tags) == 0 {
+		return
+	}
+	p.tags = p.tags[:len(p.tags)-1]
+}
+
+// BeginMarkedContent starts a marked content sequence belonging to the current structure element.
+func (p *Page) BeginMarkedContent() {
+	if p.inArtifact() || len(p.tags) == 0 {
+		return
+	}
+
+	se := p.tags[len(p.tags)-1]
+	se.MCIDs = append(se.MCIDs, p.mcid)
+	fmt.Fprintf(p.Buf, "/%s <</MCID %d>> BDC ", se.Type, p.mcid)
+	p.mcid++
+}
+
+// EndMarkedContent ends the current marked content sequence.
+func (p *Page) EndMarkedContent() {
+	if p.inArtifact() || len(p.tags) == 0 {
+		return
+	}
+	p.Buf.WriteString("EMC ")
+}
+
+// BeginArtifact starts a marked content sequence for content not being part of the logical structure.
+func (p *Page) BeginArtifact() {
+	if !p.Tagged {
+		return
+	}
+	if p.artifacts == 0 {
+		p.Buf.WriteString("/Artifact BMC ")
+	}
+	p.artifacts++
+}
+
+// EndArtifact ends the current artifact.
+func (p *Page) EndArtifact() {
+	if !p.Tagged || p.artifacts == 0 {
+		return
+	}
+	p.artifacts--
+	if p.artifacts == 0 {
+		p.Buf.WriteString("EMC ")
+	}
 }
 
 // NewPage creates a page for given mediaBox and cropBox.
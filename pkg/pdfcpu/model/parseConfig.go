@@ -30,34 +30,35 @@ import (
 )
 
 type configuration struct {
-	CreationDate                    string `yaml:"created"`
-	Version                         string `yaml:"version"`
-	CheckFileNameExt                bool   `yaml:"checkFileNameExt"`
-	Reader15                        bool   `yaml:"reader15"`
-	DecodeAllStreams                bool   `yaml:"decodeAllStreams"`
-	ValidationMode                  string `yaml:"validationMode"`
-	PostProcessValidate             bool   `yaml:"postProcessValidate"`
-	Eol                             string `yaml:"eol"`
-	WriteObjectStream               bool   `yaml:"writeObjectStream"`
-	WriteXRefStream                 bool   `yaml:"writeXRefStream"`
-	EncryptUsingAES                 bool   `yaml:"encryptUsingAES"`
-	EncryptKeyLength                int    `yaml:"encryptKeyLength"`
-	Permissions                     int    `yaml:"permissions"`
-	Unit                            string `yaml:"unit"`
-	TimestampFormat                 string `yaml:"timestampFormat"`
-	DateFormat                      string `yaml:"dateFormat"`
-	Optimize                        bool   `yaml:"optimize"`
-	OptimizeBeforeWriting           bool   `yaml:"optimizeBeforeWriting"`
-	OptimizeResourceDicts           bool   `yaml:"optimizeResourceDicts"`
-	OptimizeDuplicateContentStreams bool   `yaml:"optimizeDuplicateContentStreams"`
-	CreateBookmarks                 bool   `yaml:"createBookmarks"`
-	NeedAppearances                 bool   `yaml:"needAppearances"`
-	Offline                         bool   `yaml:"offline"`
-	Timeout                         int    `yaml:"timeout"`
-	TimeoutCRL                      int    `yaml:"timeoutCRL"`
-	TimeoutOCSP                     int    `yaml:"timeoutOCSP"`
-	PreferredCertRevocationChecker  string `yaml:"preferredCertRevocationChecker"`
-	FormFieldListMaxColWidth        int    `yaml:"formFieldListMaxColWidth"`
+	CreationDate                    string   `yaml:"created"`
+	Version                         string   `yaml:"version"`
+	CheckFileNameExt                bool     `yaml:"checkFileNameExt"`
+	Reader15                        bool     `yaml:"reader15"`
+	DecodeAllStreams                bool     `yaml:"decodeAllStreams"`
+	ValidationMode                  string   `yaml:"validationMode"`
+	PostProcessValidate             bool     `yaml:"postProcessValidate"`
+	Eol                             string   `yaml:"eol"`
+	WriteObjectStream               bool     `yaml:"writeObjectStream"`
+	WriteXRefStream                 bool     `yaml:"writeXRefStream"`
+	EncryptUsingAES                 bool     `yaml:"encryptUsingAES"`
+	EncryptKeyLength                int      `yaml:"encryptKeyLength"`
+	Permissions                     int      `yaml:"permissions"`
+	Unit                            string   `yaml:"unit"`
+	TimestampFormat                 string   `yaml:"timestampFormat"`
+	DateFormat                      string   `yaml:"dateFormat"`
+	Optimize                        bool     `yaml:"optimize"`
+	OptimizeBeforeWriting           bool     `yaml:"optimizeBeforeWriting"`
+	OptimizeResourceDicts           bool     `yaml:"optimizeResourceDicts"`
+	OptimizeDuplicateContentStreams bool     `yaml:"optimizeDuplicateContentStreams"`
+	CreateBookmarks                 bool     `yaml:"createBookmarks"`
+	NeedAppearances                 bool     `yaml:"needAppearances"`
+	Offline                         bool     `yaml:"offline"`
+	Timeout                         int      `yaml:"timeout"`
+	TimeoutCRL                      int      `yaml:"timeoutCRL"`
+	TimeoutOCSP                     int      `yaml:"timeoutOCSP"`
+	PreferredCertRevocationChecker  string   `yaml:"preferredCertRevocationChecker"`
+	FormFieldListMaxColWidth        int      `yaml:"formFieldListMaxColWidth"`
+	FallbackFonts                   []string `yaml:"fallbackFonts"`
 }
 
 func loadedConfig(c configuration, configPath string) *Configuration {
@@ -120,6 +121,7 @@ func loadedConfig(c configuration, configPath string) *Configuration {
 	conf.TimeoutCRL = c.TimeoutCRL
 	conf.TimeoutOCSP = c.TimeoutOCSP
 	conf.FormFieldListMaxColWidth = c.FormFieldListMaxColWidth
+	conf.FallbackFonts = c.FallbackFonts
 
 	switch strings.ToLower(c.PreferredCertRevocationChecker) {
 	case "crl":
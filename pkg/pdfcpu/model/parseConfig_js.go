@@ -150,6 +150,18 @@ func handleFormFieldListMaxColWidth(v string, c *Configuration) error {
 	return nil
 }
 
+func handleFallbackFonts(v string, c *Configuration) error {
+	v = strings.TrimSpace(v)
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+	c.FallbackFonts = nil
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			c.FallbackFonts = append(c.FallbackFonts, s)
+		}
+	}
+	return nil
+}
+
 func handleTimeout(v string, c *Configuration) error {
 	i, err := strconv.Atoi(v)
 	if err != nil || i <= 0 {
@@ -306,6 +318,9 @@ func parseKeysPart2(k, v string, c *Configuration) (bool, error) {
 	case "formFieldListMaxColWidth":
 		return true, handleFormFieldListMaxColWidth(v, c)
 
+	case "fallbackFonts":
+		return true, handleFallbackFonts(v, c)
+
 	case "preferredCertRevocationChecker":
 		return true, handlePreferredCertRevocationChecker(v, c)
 	}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -35,9 +37,12 @@ import (
 type TextDescriptor struct {
 	Text           string              // A multi line string using \n for line breaks.
 	FontName       string              // Name of the core or user font to be used.
-	RTL            bool                // Right to left user font.
+	RTL            bool                // Right to left paragraph direction for user fonts.
 	Embed          bool                // Embed font.
 	FontKey        string              // Resource id registered for FontName.
+	Fallback       []string            // User fonts rendering characters missing in FontName, in order of preference.
+	FallbackKeys   map[string]string   // Resource ids registered for Fallback.
+	Kerning        bool                // Apply kerning and standard ligatures.
 	FontSize       int                 // Fontsize in points.
 	X, Y           float64             // Position of first char's baseline.
 	Dx, Dy         float64             // Horizontal and vertical offsets for X,Y.
@@ -78,45 +83,11 @@ func deltaAlignBottom(fontName string, fontSize, lines int, mBot float64) float6
 	return -font.Ascent(fontName, fontSize) + float64(lines)*font.LineHeight(fontName, fontSize) + mBot
 }
 
-var unicodeToCP1252 = map[rune]byte{
-	0x20AC: 128, // € Euro Sign Note: Width in metrics file is not correct!
-	0x201A: 130, // ‚ Single Low-9 Quotation Mark
-	0x0192: 131, // ƒ Latin Small Letter F with Hook
-	0x201E: 132, // „ Double Low-9 Quotation Mark
-	0x2026: 133, // … Horizontal Ellipsis
-	0x2020: 134, // † Dagger
-	0x2021: 135, // ‡ Double Dagger
-	0x02C6: 136, // ˆ Modifier Letter Circumflex Accent
-	0x2030: 137, // ‰ Per Mille Sign
-	0x0160: 138, // Š Latin Capital Letter S with Caron
-	0x2039: 139, // ‹ Single Left-Pointing Angle Quotation Mark
-	0x0152: 140, // Œ Latin Capital Ligature Oe
-	0x017D: 142, // Ž Latin Capital Letter Z with Caron
-	0x2018: 145, // ‘ Left Single Quotation Mark
-	0x2019: 146, // ’ Right Single Quotation Mark
-	0x201C: 147, // “ Left Double Quotation Mark
-	0x201D: 148, // ” Right Double Quotation Mark
-	0x2022: 149, // • Bullet
-	0x2013: 150, // – En Dash
-	0x2014: 151, // — Em Dash
-	0x02DC: 152, // ˜ Small Tilde
-	0x2122: 153, // ™ Trade Mark Sign Emoji
-	0x0161: 154, // š Latin Small Letter S with Caron
-	0x203A: 155, // › Single Right-Pointing Angle Quotation Mark
-	0x0153: 156, // œ Latin Small Ligature Oe
-	0x017E: 158, // ž Latin Small Letter Z with Caron
-	0x0178: 159, // Ÿ Latin Capital Letter Y with Diaeresis
-}
-
 func DecodeUTF8ToByte(s string) string {
 	var sb strings.Builder
 	for _, r := range s {
 		// Unicode => char code
-		if r <= 0xFF {
-			sb.WriteByte(byte(r))
-			continue
-		}
-		if b, ok := unicodeToCP1252[r]; ok {
+		if b, ok := font.CoreFontCharCode(r); ok {
 			sb.WriteByte(b)
 			continue
 		}
@@ -154,7 +125,7 @@ func CalcBoundingBoxForRects(r1, r2 *types.Rectangle) *types.Rectangle {
 	return calcBoundingBoxForRectAndPoint(bbox, r2.UR)
 }
 
-func calcBoundingBoxForLines(lines []string, x, y float64, fontName string, fontSize int) (*types.Rectangle, string) {
+func calcBoundingBoxForLines(lines []string, x, y float64, fontSize int, td TextDescriptor) (*types.Rectangle, string) {
 	var (
 		box      *types.Rectangle
 		maxLine  string
@@ -162,7 +133,7 @@ func calcBoundingBoxForLines(lines []string, x, y float64, fontName string, font
 	)
 	// TODO Return error if lines == nil or empty.
 	for _, s := range lines {
-		bbox := CalcBoundingBox(s, x, y, fontName, fontSize)
+		bbox := td.boundingBox(s, x, y, fontSize)
 		if bbox.Width() > maxWidth {
 			maxWidth = bbox.Width()
 			maxLine = s
@@ -173,18 +144,56 @@ func calcBoundingBoxForLines(lines []string, x, y float64, fontName string, font
 	return box, maxLine
 }
 
+// useGlyph registers a glyph of user font fontName used for rendering.
+func (xRefTable *XRefTable) useGlyph(fontName string, ttf font.TTFLight, g font.Glyph) {
+	usedGIDs, ok := xRefTable.UsedGIDs[fontName]
+	if !ok {
+		usedGIDs = map[uint16]bool{}
+		xRefTable.UsedGIDs[fontName] = usedGIDs
+	}
+	usedGIDs[g.GID] = true
+
+	if _, ok := ttf.ToUnicode[g.GID]; ok || len(g.Runes) == 0 {
+		return
+	}
+	if xRefTable.GlyphRunes == nil {
+		xRefTable.GlyphRunes = map[string]map[uint16][]rune{}
+	}
+	glyphRunes, ok := xRefTable.GlyphRunes[fontName]
+	if !ok {
+		glyphRunes = map[uint16][]rune{}
+		xRefTable.GlyphRunes[fontName] = glyphRunes
+	}
+	if _, ok := glyphRunes[g.GID]; !ok {
+		glyphRunes[g.GID] = g.Runes
+	}
+}
+
+func userFontMetrics(fontName string) font.TTFLight {
+	font.UserFontMetricsLock.RLock()
+	defer font.UserFontMetricsLock.RUnlock()
+	return font.UserFontMetrics[fontName]
+}
+
 func PrepBytes(xRefTable *XRefTable, s, fontName string, embed, rtl, fillFont bool) string {
 	if font.IsUserFont(fontName) && (!fillFont || !embed) {
-		if rtl {
-			s = types.Reverse(s)
-		}
+		shape := rtl || font.NeedsShaping(s)
 		bb := []byte{}
 		if !embed {
+			if shape {
+				s = font.VisualOrder(s, rtl)
+			}
 			for _, r := range s {
 				b := make([]byte, 2)
 				binary.BigEndian.PutUint16(b, uint16(r))
 				bb = append(bb, b...)
 			}
+		} else if shape {
+			ttf := userFontMetrics(fontName)
+			for _, g := range font.Shape(fontName, s, rtl) {
+				bb = binary.BigEndian.AppendUint16(bb, g.GID)
+				xRefTable.useGlyph(fontName, ttf, g)
+			}
 		} else {
 			usedGIDs, ok := xRefTable.UsedGIDs[fontName]
 			if !ok {
@@ -192,9 +201,7 @@ func PrepBytes(xRefTable *XRefTable, s, fontName string, embed, rtl, fillFont bo
 				usedGIDs = xRefTable.UsedGIDs[fontName]
 			}
 
-			font.UserFontMetricsLock.RLock()
-			ttf := font.UserFontMetrics[fontName]
-			font.UserFontMetricsLock.RUnlock()
+			ttf := userFontMetrics(fontName)
 
 			for _, r := range s {
 				gid, ok := ttf.Chars[uint32(r)]
@@ -212,9 +219,178 @@ func PrepBytes(xRefTable *XRefTable, s, fontName string, embed, rtl, fillFont bo
 	return *s1
 }
 
-func writeStringToBuf(xRefTable *XRefTable, w io.Writer, s string, x, y float64, td TextDescriptor) {
-	s = PrepBytes(xRefTable, s, td.FontName, td.Embed, td.RTL, false)
-	fmt.Fprintf(w, "BT 0 Tw %.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td %d Tr (%s) Tj ET ",
+// PrepText returns the text showing operators for s rendered using fontName and fontSize.
+// Embedded user font text in need of shaping is rendered glyph by glyph applying the glyph positioning of the font.
+func PrepText(xRefTable *XRefTable, s, fontName string, fontSize int, embed, rtl, fillFont bool) string {
+	return prepText(xRefTable, s, fontName, fontSize, embed, rtl, fillFont, false, 0)
+}
+
+// kernedCoreFontText returns the text showing operators for s rendered using core font fontName
+// applying its kerning pairs and following each space by an additional adjustment of spaceAdj glyph space units.
+func kernedCoreFontText(xRefTable *XRefTable, s, fontName string, rtl bool, spaceAdj int) string {
+	if rtl {
+		ss := strings.Split(s, " ")
+		slices.Reverse(ss)
+		s = strings.Join(ss, " ")
+	}
+	var (
+		arr   []string // TJ array
+		start int      // start of pending chars
+	)
+	for i := 0; i < len(s)-1; i++ {
+		adj := font.CoreFontKerning(fontName, s[i], s[i+1])
+		if s[i] == ' ' {
+			adj += spaceAdj
+		}
+		if adj != 0 {
+			arr = append(arr, fmt.Sprintf("(%s) %d", PrepBytes(xRefTable, s[start:i+1], fontName, false, false, false), -adj))
+			start = i + 1
+		}
+	}
+	if len(arr) == 0 {
+		return fmt.Sprintf("(%s) Tj", PrepBytes(xRefTable, s, fontName, false, false, false))
+	}
+	if start < len(s) {
+		arr = append(arr, fmt.Sprintf("(%s)", PrepBytes(xRefTable, s[start:], fontName, false, false, false)))
+	}
+	return "[" + strings.Join(arr, " ") + "] TJ"
+}
+
+// prepText follows each space glyph by an additional adjustment of spaceAdj glyph space units.
+// For kern core font text is kerned and embedded user font text is shaped applying kerning and standard ligatures.
+func prepText(xRefTable *XRefTable, s, fontName string, fontSize int, embed, rtl, fillFont, kern bool, spaceAdj int) string {
+	if kern && font.IsCoreFont(fontName) {
+		return kernedCoreFontText(xRefTable, s, fontName, rtl, spaceAdj)
+	}
+	if !embed || fillFont || !font.IsUserFont(fontName) || !(kern || rtl || font.NeedsShaping(s)) {
+		if spaceAdj == 0 || !strings.Contains(s, " ") {
+			return fmt.Sprintf("(%s) Tj", PrepBytes(xRefTable, s, fontName, embed, rtl, fillFont))
+		}
+		blank := PrepBytes(xRefTable, " ", fontName, embed, rtl, fillFont)
+		ss := strings.Split(s, " ")
+		if rtl {
+			slices.Reverse(ss)
+		}
+		arr := make([]string, 0, 2*len(ss))
+		for i, s1 := range ss {
+			if i > 0 {
+				arr = append(arr, fmt.Sprintf("(%s) %d", blank, -spaceAdj))
+			}
+			if s1 != "" {
+				arr = append(arr, fmt.Sprintf("(%s)", PrepBytes(xRefTable, s1, fontName, embed, rtl, fillFont)))
+			}
+		}
+		return "[" + strings.Join(arr, " ") + "] TJ"
+	}
+
+	ttf := userFontMetrics(fontName)
+
+	var (
+		sb   strings.Builder
+		arr  []string // TJ array
+		bb   []byte   // pending glyphs
+		adj  int      // pending position adjustment in glyph space units
+		rise int      // current text rise in glyph space units
+	)
+
+	flushGlyphs := func() {
+		if len(bb) > 0 {
+			s1, _ := types.Escape(string(bb))
+			arr = append(arr, "("+*s1+")")
+			bb = nil
+		}
+	}
+
+	flushAdj := func() {
+		if adj != 0 {
+			flushGlyphs()
+			arr = append(arr, strconv.Itoa(-adj))
+			adj = 0
+		}
+	}
+
+	flush := func() {
+		flushGlyphs()
+		flushAdj()
+		if len(arr) == 1 && arr[0][0] == '(' {
+			sb.WriteString(arr[0] + " Tj ")
+		} else if len(arr) > 0 {
+			sb.WriteString("[" + strings.Join(arr, " ") + "] TJ ")
+		}
+		arr = nil
+	}
+
+	shape := font.Shape
+	if kern {
+		shape = font.ShapeKerned
+	}
+
+	for _, g := range shape(fontName, s, rtl) {
+		xRefTable.useGlyph(fontName, ttf, g)
+		if g.DY != rise {
+			flush()
+			rise = g.DY
+			fmt.Fprintf(&sb, "%.2f Ts ", float64(rise*fontSize)/1000)
+		}
+		w := 0
+		if int(g.GID) < len(ttf.GlyphWidths) {
+			w = ttf.GlyphWidths[g.GID]
+		}
+		adj += g.DX
+		flushAdj()
+		bb = binary.BigEndian.AppendUint16(bb, g.GID)
+		adj = g.Width - w - g.DX
+		if spaceAdj != 0 && string(g.Runes) == " " {
+			adj += spaceAdj
+		}
+	}
+	flush()
+	if rise != 0 {
+		sb.WriteString("0.00 Ts ")
+	}
+
+	if sb.Len() == 0 {
+		return "() Tj"
+	}
+	return strings.TrimSuffix(sb.String(), " ")
+}
+
+func (td TextDescriptor) fontKey(fontName string) string {
+	if k, ok := td.FallbackKeys[fontName]; ok {
+		return k
+	}
+	return td.FontKey
+}
+
+// showText returns the text showing operators for s rendered using the font of td
+// switching to its fallback fonts for characters missing in td.FontName.
+func showText(xRefTable *XRefTable, s string, fontSize int, td TextDescriptor, spaceAdj int) string {
+	if len(td.Fallback) == 0 {
+		return prepText(xRefTable, s, td.FontName, fontSize, td.Embed, td.RTL, false, td.Kerning, spaceAdj)
+	}
+	runs := font.FontRuns(s, td.FontName, td.RTL, td.Fallback...)
+	ss := make([]string, 0, 2*len(runs)+1)
+	fontName := td.FontName
+	for _, run := range runs {
+		if run.FontName != fontName {
+			ss = append(ss, fmt.Sprintf("/%s %d Tf", td.fontKey(run.FontName), fontSize))
+			fontName = run.FontName
+		}
+		s1 := run.Text
+		if font.IsCoreFont(run.FontName) {
+			s1 = DecodeUTF8ToByte(s1)
+		}
+		ss = append(ss, prepText(xRefTable, s1, run.FontName, fontSize, td.Embed, run.RTL, false, td.Kerning, spaceAdj))
+	}
+	if fontName != td.FontName {
+		ss = append(ss, fmt.Sprintf("/%s %d Tf", td.FontKey, fontSize))
+	}
+	return strings.Join(ss, " ")
+}
+
+func writeStringToBuf(xRefTable *XRefTable, w io.Writer, s string, x, y float64, fontSize int, td TextDescriptor) {
+	s = showText(xRefTable, s, fontSize, td, 0)
+	fmt.Fprintf(w, "BT 0 Tw %.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td %d Tr %s ET ",
 		td.StrokeCol.R, td.StrokeCol.G, td.StrokeCol.B, td.FillCol.R, td.FillCol.G, td.FillCol.B, x, y, td.RMode, s)
 }
 
@@ -222,13 +398,40 @@ func setFont(w io.Writer, fontID string, fontSize float32) {
 	fmt.Fprintf(w, "BT /%s %.2f Tf ET ", fontID, fontSize)
 }
 
-func CalcBoundingBox(s string, x, y float64, fontName string, fontSize int) *types.Rectangle {
-	w := font.TextWidth(s, fontName, fontSize)
+func CalcBoundingBox(s string, x, y float64, fontName string, fontSize int, fallbacks ...string) *types.Rectangle {
+	w := font.TextWidth(s, fontName, fontSize, fallbacks...)
 	h := font.LineHeight(fontName, fontSize)
 	y -= math.Ceil(font.Descent(fontName, fontSize))
 	return types.NewRectangle(x, y, x+w, y+h)
 }
 
+// textWidth returns the width of s rendered using the fonts of td in user space units.
+func (td TextDescriptor) textWidth(s string, fontSize int) float64 {
+	if td.Kerning {
+		return font.KernedTextWidth(s, td.FontName, fontSize, td.Fallback...)
+	}
+	return font.TextWidth(s, td.FontName, fontSize, td.Fallback...)
+}
+
+// fontSizeForWidth returns the font size needed for rendering s using the fonts of td with width.
+func (td TextDescriptor) fontSizeForWidth(s string, width float64) int {
+	if td.Kerning {
+		return font.KernedSize(s, td.FontName, width, td.Fallback...)
+	}
+	return font.Size(s, td.FontName, width, td.Fallback...)
+}
+
+// boundingBox returns the bounding box for s rendered using the fonts of td at x/y.
+func (td TextDescriptor) boundingBox(s string, x, y float64, fontSize int) *types.Rectangle {
+	if !td.Kerning {
+		return CalcBoundingBox(s, x, y, td.FontName, fontSize, td.Fallback...)
+	}
+	w := td.textWidth(s, fontSize)
+	h := font.LineHeight(td.FontName, fontSize)
+	y -= math.Ceil(font.Descent(td.FontName, fontSize))
+	return types.NewRectangle(x, y, x+w, y+h)
+}
+
 func horAdjustBoundingBoxForLines(r, box *types.Rectangle, dx, dy float64, x, y *float64) {
 	if r.UR.X-box.LL.X < box.Width() {
 		dx -= box.Width() - (r.UR.X - box.LL.X)
@@ -250,12 +453,22 @@ func horAdjustBoundingBoxForLines(r, box *types.Rectangle, dx, dy float64, x, y
 	}
 }
 
-func prepJustifiedLine(xRefTable *XRefTable, lines *[]string, strbuf []string, strWidth, w float64, fontSize int, fontName string, embed, rtl bool) {
+func prepJustifiedLine(xRefTable *XRefTable, lines *[]string, strbuf []string, strWidth, w float64, fontSize int, td TextDescriptor) {
+	fontName, embed, rtl := td.FontName, td.Embed, td.RTL
+	wc := len(strbuf)
+	if s := strings.Join(strbuf, " "); td.Kerning || len(td.Fallback) > 0 || embed && font.IsUserFont(fontName) && (rtl || font.NeedsShaping(s)) {
+		// Shaped or kerned lines and lines using fallback fonts are reordered as a whole, spaces are stretched in place.
+		if td.Kerning {
+			strWidth = td.textWidth(s, fontSize)
+		}
+		dx := font.GlyphSpaceUnits(float64((w-strWidth))/float64(wc-1), fontSize)
+		*lines = append(*lines, showText(xRefTable, s, fontSize, td, int(dx)))
+		return
+	}
+	dx := font.GlyphSpaceUnits(float64((w-strWidth))/float64(wc-1), fontSize)
 	blank := PrepBytes(xRefTable, " ", fontName, embed, true, false)
 	var sb strings.Builder
 	sb.WriteString("[")
-	wc := len(strbuf)
-	dx := font.GlyphSpaceUnits(float64((w-strWidth))/float64(wc-1), fontSize)
 	for i := 0; i < wc; i++ {
 		j := i
 		if rtl {
@@ -273,8 +486,8 @@ func prepJustifiedLine(xRefTable *XRefTable, lines *[]string, strbuf []string, s
 
 func newPrepJustifiedString(
 	xRefTable *XRefTable,
-	fontName string,
-	fontSize int) func(lines *[]string, s string, w float64, fontName string, fontSize *int, lastline, parIndent, cjk, rtl bool) int {
+	td TextDescriptor,
+	fontSize int) func(lines *[]string, s string, w float64, fontSize *int, lastline, parIndent bool) int {
 
 	// Not yet rendered content.
 	strbuf := []string{}
@@ -288,18 +501,18 @@ func newPrepJustifiedString(
 	// Indentation string for first line of paragraphs.
 	identPrefix := "    "
 
-	blankWidth := font.TextWidth(" ", fontName, fontSize)
+	blankWidth := td.textWidth(" ", fontSize)
 
-	return func(lines *[]string, s string, w float64, fontName string, fontSize *int, lastline, parIndent, embed, rtl bool) int {
+	return func(lines *[]string, s string, w float64, fontSize *int, lastline, parIndent bool) int {
 
 		if len(s) == 0 {
 			if len(strbuf) > 0 {
-				s1 := PrepBytes(xRefTable, strings.Join(strbuf, " "), fontName, embed, rtl, false)
-				if rtl {
+				s1 := showText(xRefTable, strings.Join(strbuf, " "), *fontSize, td, 0)
+				if td.RTL {
 					dx := font.GlyphSpaceUnits(w-strWidth, *fontSize)
-					s = fmt.Sprintf("[ %d (%s) ] TJ ", -int(dx), s1)
+					s = fmt.Sprintf("[ %d ] TJ %s ", -int(dx), s1)
 				} else {
-					s = fmt.Sprintf("(%s) Tj", s1)
+					s = s1
 				}
 				*lines = append(*lines, s)
 				strbuf = []string{}
@@ -322,7 +535,7 @@ func newPrepJustifiedString(
 		}
 
 		for _, s1 := range ss {
-			s1Width := font.TextWidth(s1, fontName, *fontSize)
+			s1Width := td.textWidth(s1, *fontSize)
 			bw := 0.
 			if len(strbuf) > 0 {
 				bw = blankWidth
@@ -333,15 +546,15 @@ func newPrepJustifiedString(
 				continue
 			}
 			// Ensure s1 fits into w.
-			fs := font.Size(s1, fontName, w)
+			fs := td.fontSizeForWidth(s1, w)
 			if fs < *fontSize {
 				*fontSize = fs
 			}
 			if len(strbuf) == 0 {
-				prepJustifiedLine(xRefTable, lines, []string{s1}, s1Width, w, *fontSize, fontName, embed, rtl)
+				prepJustifiedLine(xRefTable, lines, []string{s1}, s1Width, w, *fontSize, td)
 			} else {
 				// Note: Previous lines have whitespace based on bigger font size.
-				prepJustifiedLine(xRefTable, lines, strbuf, strWidth, w, *fontSize, fontName, embed, rtl)
+				prepJustifiedLine(xRefTable, lines, strbuf, strWidth, w, *fontSize, td)
 				strbuf = []string{s1}
 				strWidth = s1Width
 			}
@@ -369,21 +582,21 @@ func preRenderJustifiedText(
 		if width > 0 {
 			ww = width * td.Scale
 		} else {
-			box, _ := calcBoundingBoxForLines(*lines, x, y, td.FontName, *fontSize)
+			box, _ := calcBoundingBoxForLines(*lines, x, y, *fontSize, td)
 			ww = box.Width() * td.Scale
 		}
 	}
 	ww -= mLeft + mRight + 2*borderWidth
-	prepJustifiedString := newPrepJustifiedString(xRefTable, td.FontName, *fontSize)
+	prepJustifiedString := newPrepJustifiedString(xRefTable, td, *fontSize)
 	l := []string{}
 	for i, s := range *lines {
-		linefeeds := prepJustifiedString(&l, s, ww, td.FontName, fontSize, false, td.ParIndent, td.Embed, td.RTL)
+		linefeeds := prepJustifiedString(&l, s, ww, fontSize, false, td.ParIndent)
 		for j := 0; j < linefeeds; j++ {
 			l = append(l, "")
 		}
 		isLastLine := i == len(*lines)-1
 		if isLastLine {
-			prepJustifiedString(&l, "", ww, td.FontName, fontSize, true, td.ParIndent, td.Embed, td.RTL)
+			prepJustifiedString(&l, "", ww, fontSize, true, td.ParIndent)
 		}
 	}
 	*lines = l
@@ -392,13 +605,13 @@ func preRenderJustifiedText(
 
 func scaleFontSize(r *types.Rectangle, lines []string, scaleAbs bool,
 	scale, width, x, y, mLeft, mRight, borderWidth float64,
-	fontName string, fontSize *int) {
+	td TextDescriptor, fontSize *int) {
 	if scaleAbs {
 		*fontSize = int(float64(*fontSize) * scale)
 	} else {
 		www := width
 		if width == 0 {
-			box, _ := calcBoundingBoxForLines(lines, x, y, fontName, *fontSize)
+			box, _ := calcBoundingBoxForLines(lines, x, y, *fontSize, td)
 			www = box.Width() + mLeft + mRight + 2*borderWidth
 		}
 		*fontSize = int(r.Width() * scale * float64(*fontSize) / www)
@@ -407,7 +620,7 @@ func scaleFontSize(r *types.Rectangle, lines []string, scaleAbs bool,
 
 func horizontalWrapUp(box *types.Rectangle, maxLine string, hAlign types.HAlignment,
 	x *float64, width, ww, mLeft, mRight, borderWidth float64,
-	fontName string, fontSize *int) {
+	td TextDescriptor, fontSize *int) {
 	switch hAlign {
 	case types.AlignLeft:
 		box.Translate(mLeft+borderWidth, 0)
@@ -428,7 +641,7 @@ func horizontalWrapUp(box *types.Rectangle, maxLine string, hAlign types.HAlignm
 	} else if width > 0 {
 		netWidth := width - 2*borderWidth - mLeft - mRight
 		if box.Width() > netWidth {
-			*fontSize = font.Size(maxLine, fontName, netWidth)
+			*fontSize = td.fontSizeForWidth(maxLine, netWidth)
 		}
 		switch hAlign {
 		case types.AlignLeft:
@@ -461,7 +674,7 @@ func createBoundingBoxForColumn(xRefTable *XRefTable, r *types.Rectangle, x, y *
 	}
 
 	if td.HAlign != types.AlignJustify {
-		scaleFontSize(r, *lines, td.ScaleAbs, td.Scale, width, *x, *y, mLeft, mRight, borderWidth, td.FontName, fontSize)
+		scaleFontSize(r, *lines, td.ScaleAbs, td.Scale, width, *x, *y, mLeft, mRight, borderWidth, td, fontSize)
 	}
 
 	// Apply vertical alignment.
@@ -476,9 +689,9 @@ func createBoundingBoxForColumn(xRefTable *XRefTable, r *types.Rectangle, x, y *
 	}
 	*y += math.Ceil(dy1)
 
-	box, maxLine := calcBoundingBoxForLines(*lines, *x, *y, td.FontName, *fontSize)
+	box, maxLine := calcBoundingBoxForLines(*lines, *x, *y, *fontSize, td)
 	// maxLine for hAlign != AlignJustify only!
-	horizontalWrapUp(box, maxLine, td.HAlign, x, width, ww, mLeft, mRight, borderWidth, td.FontName, fontSize)
+	horizontalWrapUp(box, maxLine, td.HAlign, x, width, ww, mLeft, mRight, borderWidth, td, fontSize)
 
 	box.LL.Y -= mBot + borderWidth
 	box.UR.Y += mTop + borderWidth
@@ -551,7 +764,7 @@ func renderText(xRefTable *XRefTable, w io.Writer, lines []string, td TextDescri
 	lh := font.LineHeight(td.FontName, fontSize)
 	for _, s := range lines {
 		if td.HAlign != types.AlignJustify {
-			lineBB := CalcBoundingBox(s, x, y, td.FontName, fontSize)
+			lineBB := td.boundingBox(s, x, y, fontSize)
 			// Apply horizontal alignment.
 			var dx float64
 			switch td.HAlign {
@@ -566,7 +779,7 @@ func renderText(xRefTable *XRefTable, w io.Writer, lines []string, td TextDescri
 				draw.SetStrokeColor(w, color.Black)
 				draw.DrawRectSimple(w, lineBB)
 			}
-			writeStringToBuf(xRefTable, w, s, x-dx, y, td)
+			writeStringToBuf(xRefTable, w, s, x-dx, y, fontSize, td)
 			y -= lh
 			continue
 		}
@@ -673,7 +886,8 @@ func WriteColumn(xRefTable *XRefTable, w io.Writer, mediaBox, region *types.Rect
 	// Cache haircross coordinates.
 	x0, y0 := x, y
 
-	if font.IsCoreFont(td.FontName) && utf8.ValidString(s) {
+	// Core font text using fallback fonts is encoded per font run.
+	if font.IsCoreFont(td.FontName) && len(td.Fallback) == 0 && utf8.ValidString(s) {
 		s = DecodeUTF8ToByte(s)
 	}
 
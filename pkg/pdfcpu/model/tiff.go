@@ -0,0 +1,398 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+
+	"github.com/hhrutter/tiff"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// TIFF tags used for page sizing and for passing through compressed image data, see TIFF 6.0
+const (
+	tiffImageWidth      = 256
+	tiffImageLength     = 257
+	tiffBitsPerSample   = 258
+	tiffCompression     = 259
+	tiffPhotometric     = 262
+	tiffFillOrder       = 266
+	tiffStripOffsets    = 273
+	tiffSamplesPerPixel = 277
+	tiffStripByteCounts = 279
+	tiffXResolution     = 282
+	tiffYResolution     = 283
+	tiffT4Options       = 292
+	tiffT6Options       = 293
+	tiffResolutionUnit  = 296
+	tiffJPEGTables      = 347
+)
+
+// TIFF compression schemes passed through.
+const (
+	tiffCompressionG3   = 3
+	tiffCompressionG4   = 4
+	tiffCompressionJPEG = 7
+)
+
+// TIFF photometric interpretations passed through.
+const (
+	tiffWhiteIsZero = 0
+	tiffBlackIsZero = 1
+	tiffRGB         = 2
+	tiffYCbCr       = 6
+)
+
+// tiffTypeSizes are the sizes of the TIFF field types in bytes.
+var tiffTypeSizes = [...]int{0, 1, 1, 2, 4, 8, 1, 1, 2, 4, 8, 4, 8}
+
+type tiffField struct {
+	typ  uint16
+	data []byte
+}
+
+// tiffIFD represents the image file directory of a TIFF page.
+type tiffIFD struct {
+	bb        []byte
+	byteOrder binary.ByteOrder
+	fields    map[uint16]tiffField
+}
+
+// parseTIFFIFD parses the image file directory at off and returns it along with the offset of the next one.
+func parseTIFFIFD(bb []byte, off int64, byteOrder binary.ByteOrder) (*tiffIFD, int64, error) {
+	if off < 8 || off+2 > int64(len(bb)) {
+		return nil, 0, errors.New("pdfcpu: invalid TIFF IFD offset")
+	}
+
+	n := int64(byteOrder.Uint16(bb[off:]))
+	end := off + 2 + n*12
+	if end+4 > int64(len(bb)) {
+		return nil, 0, errors.New("pdfcpu: invalid TIFF IFD")
+	}
+
+	ifd := &tiffIFD{bb: bb, byteOrder: byteOrder, fields: map[uint16]tiffField{}}
+
+	for e := off + 2; e < end; e += 12 {
+		tag, typ, count := byteOrder.Uint16(bb[e:]), byteOrder.Uint16(bb[e+2:]), int64(byteOrder.Uint32(bb[e+4:]))
+		if int(typ) >= len(tiffTypeSizes) || typ == 0 {
+			continue
+		}
+		size := count * int64(tiffTypeSizes[typ])
+		data := bb[e+8 : e+12]
+		if size > 4 {
+			o := int64(byteOrder.Uint32(data))
+			if o+size > int64(len(bb)) {
+				continue
+			}
+			data = bb[o : o+size]
+		}
+		ifd.fields[tag] = tiffField{typ: typ, data: data[:size]}
+	}
+
+	return ifd, int64(byteOrder.Uint32(bb[end:])), nil
+}
+
+// ints returns the values of an integer field.
+func (ifd *tiffIFD) ints(tag uint16) []int {
+	f, ok := ifd.fields[tag]
+	if !ok {
+		return nil
+	}
+	var ii []int
+	switch f.typ {
+	case 1, 7:
+		for _, b := range f.data {
+			ii = append(ii, int(b))
+		}
+	case 3:
+		for i := 0; i+2 <= len(f.data); i += 2 {
+			ii = append(ii, int(ifd.byteOrder.Uint16(f.data[i:])))
+		}
+	case 4:
+		for i := 0; i+4 <= len(f.data); i += 4 {
+			ii = append(ii, int(ifd.byteOrder.Uint32(f.data[i:])))
+		}
+	}
+	return ii
+}
+
+// int returns the first value of an integer field or def.
+func (ifd *tiffIFD) int(tag uint16, def int) int {
+	if ii := ifd.ints(tag); len(ii) > 0 {
+		return ii[0]
+	}
+	return def
+}
+
+// rational returns the value of a rational field or 0.
+func (ifd *tiffIFD) rational(tag uint16) float64 {
+	f, ok := ifd.fields[tag]
+	if !ok || f.typ != 5 || len(f.data) < 8 {
+		return 0
+	}
+	num, den := ifd.byteOrder.Uint32(f.data), ifd.byteOrder.Uint32(f.data[4:])
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
+
+// resolution returns the horizontal and vertical resolution in dots per inch or 0 if unknown.
+func (ifd *tiffIFD) resolution() (float64, float64) {
+	x, y := ifd.rational(tiffXResolution), ifd.rational(tiffYResolution)
+	if x <= 0 || y <= 0 {
+		return 0, 0
+	}
+	switch ifd.int(tiffResolutionUnit, 2) {
+	case 2: // inch
+		return x, y
+	case 3: // centimeter
+		return x * 2.54, y * 2.54
+	}
+	return 0, 0
+}
+
+// strip returns the image data if it is stored in a single strip.
+func (ifd *tiffIFD) strip() ([]byte, bool) {
+	offs, counts := ifd.ints(tiffStripOffsets), ifd.ints(tiffStripByteCounts)
+	if len(offs) != 1 || len(counts) != 1 || offs[0]+counts[0] > len(ifd.bb) {
+		return nil, false
+	}
+	return ifd.bb[offs[0] : offs[0]+counts[0]], true
+}
+
+// ccittStreamDict returns a stream dict for CCITT compressed image data stored in a single strip.
+func (ifd *tiffIFD) ccittStreamDict(w, h int) (*types.StreamDict, bool) {
+	if ifd.int(tiffBitsPerSample, 1) != 1 || ifd.int(tiffSamplesPerPixel, 1) != 1 {
+		return nil, false
+	}
+
+	photometric := ifd.int(tiffPhotometric, tiffWhiteIsZero)
+	if photometric != tiffWhiteIsZero && photometric != tiffBlackIsZero {
+		return nil, false
+	}
+
+	parms := types.Dict(
+		map[string]types.Object{
+			"Columns": types.Integer(w),
+			"Rows":    types.Integer(h),
+		},
+	)
+
+	if ifd.int(tiffCompression, 1) == tiffCompressionG3 {
+		opts := ifd.int(tiffT4Options, 0)
+		if opts&2 != 0 {
+			// Uncompressed mode
+			return nil, false
+		}
+		k := 0
+		if opts&1 != 0 {
+			// Any K > 0 signals mixed one- and two-dimensional coding.
+			k = 2
+		}
+		parms["K"] = types.Integer(k)
+		parms["EndOfLine"] = types.Boolean(true)
+	} else {
+		if ifd.int(tiffT6Options, 0)&2 != 0 {
+			return nil, false
+		}
+		parms["K"] = types.Integer(-1)
+	}
+
+	data, ok := ifd.strip()
+	if !ok {
+		return nil, false
+	}
+
+	if ifd.int(tiffFillOrder, 1) == 2 {
+		// Lower order bits first.
+		data = append([]byte(nil), data...)
+		for i, b := range data {
+			b = b>>4 | b<<4
+			b = b>>2&0x33 | b<<2&0xCC
+			data[i] = b>>1&0x55 | b<<1&0xAA
+		}
+	}
+
+	sd := ccittImageStreamDict(w, h, parms)
+
+	if photometric == tiffBlackIsZero {
+		// Runs coded white are displayed black.
+		sd.Insert("Decode", types.NewIntegerArray(1, 0))
+	}
+
+	// Calling Encode without FilterPipeline ensures an encoded stream in sd.Raw.
+	fpl := sd.FilterPipeline
+	sd.FilterPipeline = nil
+	sd.Content = data
+	if err := sd.Encode(); err != nil {
+		return nil, false
+	}
+	sd.Content = nil
+	sd.FilterPipeline = fpl
+
+	return sd, true
+}
+
+// dctStreamDict returns a stream dict for JPEG compressed image data stored in a single strip.
+func (ifd *tiffIFD) dctStreamDict(xRefTable *XRefTable, w, h int) (*types.StreamDict, bool) {
+	if ifd.int(tiffBitsPerSample, 8) != 8 {
+		return nil, false
+	}
+
+	var cs string
+	switch ifd.int(tiffPhotometric, -1) {
+	case tiffBlackIsZero:
+		cs = DeviceGrayCS
+	case tiffRGB, tiffYCbCr:
+		cs = DeviceRGBCS
+	default:
+		return nil, false
+	}
+
+	data, ok := ifd.strip()
+	if !ok || len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, false
+	}
+
+	// Merge the tables shared by all strips into the abbreviated JPEG stream of the strip.
+	if tables := ifd.fields[tiffJPEGTables].data; len(tables) > 4 {
+		n := len(tables)
+		if tables[n-2] != 0xFF || tables[n-1] != 0xD9 {
+			return nil, false
+		}
+		data = append(append([]byte(nil), tables[:n-2]...), data[2:]...)
+	}
+
+	c, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil || c.Width != w || c.Height != h {
+		return nil, false
+	}
+
+	sd, err := CreateDCTImageStreamDict(xRefTable, data, w, h, 8, cs)
+	if err != nil {
+		return nil, false
+	}
+
+	if ifd.int(tiffPhotometric, -1) == tiffRGB {
+		// The JPEG data is not YCbCr encoded.
+		parms := types.Dict(map[string]types.Object{"ColorTransform": types.Integer(0)})
+		sd.Insert("DecodeParms", parms)
+		sd.FilterPipeline[0].DecodeParms = parms
+	}
+
+	return sd, true
+}
+
+// passThroughStreamDict returns a stream dict for compressed image data that PDF supports without re-encoding.
+func (ifd *tiffIFD) passThroughStreamDict(xRefTable *XRefTable, gray, sepia bool) (*types.StreamDict, int, int, bool) {
+	w, h := ifd.int(tiffImageWidth, 0), ifd.int(tiffImageLength, 0)
+	if w <= 0 || h <= 0 {
+		return nil, 0, 0, false
+	}
+
+	var (
+		sd *types.StreamDict
+		ok bool
+	)
+
+	switch ifd.int(tiffCompression, 1) {
+	case tiffCompressionG3, tiffCompressionG4:
+		sd, ok = ifd.ccittStreamDict(w, h)
+	case tiffCompressionJPEG:
+		if (gray || sepia) && ifd.int(tiffPhotometric, -1) != tiffBlackIsZero {
+			return nil, 0, 0, false
+		}
+		sd, ok = ifd.dctStreamDict(xRefTable, w, h)
+	}
+
+	return sd, w, h, ok
+}
+
+// createImageResourceForTIFFPage creates a new XObject for the TIFF page at off and applies optional filters.
+// CCITT and JPEG compressed image data is passed through if possible.
+func createImageResourceForTIFFPage(xRefTable *XRefTable, bb []byte, off int64, ifd *tiffIFD, gray, sepia bool) (ImageResource, error) {
+	var ir ImageResource
+
+	if sd, w, h, ok := ifd.passThroughStreamDict(xRefTable, gray, sepia); ok {
+		indRef, err := xRefTable.IndRefForNewObject(*sd)
+		if err != nil {
+			return ir, err
+		}
+		ir = ImageResource{Res: Resource{ID: "Im0", IndRef: indRef}, Width: w, Height: h}
+	} else {
+		img, err := tiff.DecodeAt(bytes.NewReader(bb), off)
+		if err != nil {
+			return ir, err
+		}
+		if ir, err = createImageResource(xRefTable, img, "tiff", gray, sepia); err != nil {
+			return ir, err
+		}
+	}
+
+	ir.DPIX, ir.DPIY = ifd.resolution()
+
+	return ir, nil
+}
+
+// createImageResourcesForTIFF creates a new XObject for each page of a TIFF file.
+func createImageResourcesForTIFF(xRefTable *XRefTable, bb bytes.Buffer, gray, sepia bool) ([]ImageResource, error) {
+	buf := bb.Bytes()
+	if len(buf) < 8 {
+		return nil, errors.New("pdfcpu: invalid TIFF file")
+	}
+
+	var byteOrder binary.ByteOrder
+	switch string(buf[:2]) {
+	case "II":
+		byteOrder = binary.LittleEndian
+	case "MM":
+		byteOrder = binary.BigEndian
+	default:
+		return nil, errors.New("pdfcpu: invalid TIFF byte order")
+	}
+
+	off := int64(byteOrder.Uint32(buf[4:]))
+	if off < 8 || off >= int64(len(buf)) {
+		return nil, errors.New("pdfcpu: invalid TIFF file: no valid IFD")
+	}
+
+	imgResources := []ImageResource{}
+	visited := map[int64]bool{}
+
+	for off != 0 && off < int64(len(buf)) && !visited[off] {
+		visited[off] = true
+
+		ifd, next, err := parseTIFFIFD(buf, off, byteOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		ir, err := createImageResourceForTIFFPage(xRefTable, buf, off, ifd, gray, sepia)
+		if err != nil {
+			return nil, err
+		}
+		imgResources = append(imgResources, ir)
+
+		off = next
+	}
+
+	return imgResources, nil
+}
@@ -64,6 +64,7 @@ type Watermark struct {
 	TextString                string              // raw display text.
 	TextLines                 []string            // display multiple lines of text.
 	URL                       string              // overlay link annotation for stamps.
+	Layer                     string              // name of the layer (optional content group) to place the watermark on.
 	InpUnit                   types.DisplayUnit   // input display unit.
 	Pos                       types.Anchor        // position anchor, one of tl,tc,tr,l,c,r,bl,bc,br.
 	Dx, Dy                    float64             // anchor offset.
@@ -73,6 +74,7 @@ type Watermark struct {
 	ScaledFontSize            int                 // font scaling factor for a specific page
 	ScriptName                string              // ISO 15924: Hans, Hant, Hira, Kana, Jpan, Hang, Kore: if set, font will not be embedded.
 	RTL                       bool                // if true, render text from right to left
+	Kerning                   bool                // if true, apply kerning and standard ligatures
 	Color                     color.SimpleColor   // text fill color(=non stroking color) for backwards compatibility.
 	FillColor                 color.SimpleColor   // text fill color(=non stroking color).
 	StrokeColor               color.SimpleColor   // text stroking color
@@ -94,6 +96,10 @@ type Watermark struct {
 	Ocg, ExtGState, Font, Img *types.IndirectRef  // resources
 	Width, Height             int                 // image or page dimensions
 
+	// Text stamp fallback fonts
+	Fallback      []string             // configured fallback fonts needed for rendering TextString.
+	FallbackFonts []*types.IndirectRef // font resources for Fallback.
+
 	// PDF stamp
 	bbPDF                   *types.Rectangle     // bounding box
 	PdfRes                  map[int]PdfResources // content & corresponding resources
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// XFDFNamespace is the XML namespace of XFDF documents.
+const XFDFNamespace = "http://ns.adobe.com/xfdf/"
+
+var ErrInvalidXFDF = errors.New("pdfcpu: invalid XFDF")
+
+// XFDF represents an XML Forms Data Format document (ISO 19444-1).
+type XFDF struct {
+	XMLName xml.Name    `xml:"xfdf"`
+	XMLNS   string      `xml:"xmlns,attr,omitempty"`
+	Space   string      `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	F       *XFDFFile   `xml:"f"`
+	IDs     *XFDFIDs    `xml:"ids"`
+	Fields  *XFDFFields `xml:"fields"`
+	Annots  *XFDFAnnots `xml:"annots"`
+}
+
+// XFDFFile refers to the PDF file the XFDF data belongs to.
+type XFDFFile struct {
+	Href string `xml:"href,attr"`
+}
+
+// XFDFIDs holds the file identifiers of the PDF file the XFDF data belongs to.
+type XFDFIDs struct {
+	Original string `xml:"original,attr"`
+	Modified string `xml:"modified,attr"`
+}
+
+// XFDFFields is the container for form field values.
+type XFDFFields struct {
+	Fields []*XFDFField `xml:"field"`
+}
+
+// XFDFField represents a form field identified by its partial name.
+type XFDFField struct {
+	Name   string       `xml:"name,attr"`
+	Values []string     `xml:"value"`
+	Fields []*XFDFField `xml:"field"`
+}
+
+// XFDFAnnots is the container for annotations.
+type XFDFAnnots struct {
+	Annots []*XFDFAnnot `xml:",any"`
+}
+
+// XFDFAnnot represents an annotation element like <text>, <line> or <ink>.
+// The element name is the lower case annotation type.
+type XFDFAnnot struct {
+	XMLName xml.Name
+
+	// Attributes common to all annotations.
+	Page         int    `xml:"page,attr"`
+	Rect         string `xml:"rect,attr,omitempty"`
+	Name         string `xml:"name,attr,omitempty"`
+	Title        string `xml:"title,attr,omitempty"`
+	Subject      string `xml:"subject,attr,omitempty"`
+	Date         string `xml:"date,attr,omitempty"`
+	CreationDate string `xml:"creationdate,attr,omitempty"`
+	Flags        string `xml:"flags,attr,omitempty"`
+	Color        string `xml:"color,attr,omitempty"`
+	Opacity      string `xml:"opacity,attr,omitempty"`
+	InReplyTo    string `xml:"inreplyto,attr,omitempty"`
+	ReplyType    string `xml:"replyType,attr,omitempty"`
+
+	// Border attributes.
+	Width     string `xml:"width,attr,omitempty"`
+	Style     string `xml:"style,attr,omitempty"`
+	Dashes    string `xml:"dashes,attr,omitempty"`
+	Intensity string `xml:"intensity,attr,omitempty"`
+
+	// Type specific attributes.
+	Icon           string `xml:"icon,attr,omitempty"`
+	Coords         string `xml:"coords,attr,omitempty"`
+	Start          string `xml:"start,attr,omitempty"`
+	End            string `xml:"end,attr,omitempty"`
+	Head           string `xml:"head,attr,omitempty"`
+	Tail           string `xml:"tail,attr,omitempty"`
+	InteriorColor  string `xml:"interior-color,attr,omitempty"`
+	LeaderLength   string `xml:"leaderLength,attr,omitempty"`
+	LeaderExtend   string `xml:"leaderExtend,attr,omitempty"`
+	LeaderOffset   string `xml:"leaderOffset,attr,omitempty"`
+	Caption        string `xml:"caption,attr,omitempty"`
+	CaptionStyle   string `xml:"caption-style,attr,omitempty"`
+	CaptionOffsetH string `xml:"caption-offset-h,attr,omitempty"`
+	CaptionOffsetV string `xml:"caption-offset-v,attr,omitempty"`
+	Fringe         string `xml:"fringe,attr,omitempty"`
+	Symbol         string `xml:"symbol,attr,omitempty"`
+	Justification  string `xml:"justification,attr,omitempty"`
+	Intent         string `xml:"intent,attr,omitempty"`
+	Callout        string `xml:"callout,attr,omitempty"`
+
+	Contents          string        `xml:"contents,omitempty"`
+	RichText          *XFDFRichText `xml:"contents-richtext"`
+	DefaultAppearance string        `xml:"defaultappearance,omitempty"`
+	DefaultStyle      string        `xml:"defaultstyle,omitempty"`
+	Vertices          string        `xml:"vertices,omitempty"`
+	InkList           *XFDFInkList  `xml:"inklist"`
+	Popup             *XFDFPopup    `xml:"popup"`
+}
+
+// Type returns the annotation type represented by a.
+func (a XFDFAnnot) Type() string {
+	return a.XMLName.Local
+}
+
+// XFDFRichText holds the XHTML body of a rich text string.
+type XFDFRichText struct {
+	InnerXML string `xml:",innerxml"`
+}
+
+// XFDFInkList holds the paths of an ink annotation.
+type XFDFInkList struct {
+	Gestures []string `xml:"gesture"`
+}
+
+// XFDFPopup represents the popup annotation of a markup annotation.
+type XFDFPopup struct {
+	Page  int    `xml:"page,attr"`
+	Rect  string `xml:"rect,attr,omitempty"`
+	Flags string `xml:"flags,attr,omitempty"`
+	Open  string `xml:"open,attr,omitempty"`
+}
+
+func fileIDHex(o types.Object) string {
+	var (
+		bb  []byte
+		err error
+	)
+	switch s := o.(type) {
+	case types.HexLiteral:
+		bb, err = s.Bytes()
+	case types.StringLiteral:
+		bb, err = types.Unescape(s.Value())
+	}
+	if err != nil {
+		return ""
+	}
+	return strings.ToUpper(hex.EncodeToString(bb))
+}
+
+// NewXFDF returns a new XFDF document for xRefTable originating from source.
+func NewXFDF(xRefTable *XRefTable, source string) *XFDF {
+	x := &XFDF{XMLNS: XFDFNamespace, Space: "preserve"}
+
+	if source != "" {
+		x.F = &XFDFFile{Href: filepath.Base(source)}
+	}
+
+	if len(xRefTable.ID) == 2 {
+		x.IDs = &XFDFIDs{Original: fileIDHex(xRefTable.ID[0]), Modified: fileIDHex(xRefTable.ID[1])}
+	}
+
+	return x
+}
+
+// ParseXFDF parses an XFDF document from rd.
+func ParseXFDF(rd io.Reader) (*XFDF, error) {
+	x := &XFDF{}
+	if err := xml.NewDecoder(rd).Decode(x); err != nil {
+		return nil, errors.Wrap(ErrInvalidXFDF, err.Error())
+	}
+	return x, nil
+}
+
+// Write writes an XML representation of x to w.
+func (x *XFDF) Write(w io.Writer) error {
+	bb, err := xml.MarshalIndent(x, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	if _, err = w.Write(bb); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -183,8 +183,9 @@ type XRefTable struct {
 	AppendOnly     bool
 
 	// Fonts
-	UsedGIDs  map[string]map[uint16]bool
-	FillFonts map[string]types.IndirectRef
+	UsedGIDs   map[string]map[uint16]bool
+	GlyphRunes map[string]map[uint16][]rune // Characters represented by shaped glyphs without a cmap entry.
+	FillFonts  map[string]types.IndirectRef
 }
 
 // NewXRefTable creates a new XRefTable.
@@ -205,6 +206,7 @@ func newXRefTable(conf *Configuration) (xRefTable *XRefTable) {
 		ValidateLinks:     conf.ValidateLinks,
 		URIs:              map[int]map[string]string{},
 		UsedGIDs:          map[string]map[uint16]bool{},
+		GlyphRunes:        map[string]map[uint16][]rune{},
 		FillFonts:         map[string]types.IndirectRef{},
 		Conf:              conf,
 	}
@@ -809,9 +809,11 @@ func optimizeXObjectResourcesDict(ctx *model.Context, rDict types.Dict, pageNr,
 		}
 
 		if *sd.Subtype() == "Form" {
-			// Get rid of PieceInfo dict from form XObjects.
-			if err := ctx.DeleteDictEntry(sd.Dict, "PieceInfo"); err != nil {
-				return err
+			// Get rid of PieceInfo dict from form XObjects but keep the marker of pdfcpu watermarks.
+			if !stampPieceInfo(sd.Dict) {
+				if err := ctx.DeleteDictEntry(sd.Dict, "PieceInfo"); err != nil {
+					return err
+				}
 			}
 			if err := optimizeForm(ctx, sd, rNamePrefix, rName, rDict, objNr, pageNr, pageObjNumber, vis); err != nil {
 				return err
@@ -243,6 +243,10 @@ func AddPages(ctxSrc, ctxDest *model.Context, pageNrs []int, usePgCache bool) er
 
 	migrated := map[int]int{}
 
+	if err := addPageLabels(ctxSrc, ctxDest, pageNrs); err != nil {
+		return err
+	}
+
 	if err := addPages(ctxSrc, ctxDest, pageNrs, usePgCache, *pagesIndRef, pagesDict, &fieldsSrc, &fieldsDest, migrated); err != nil {
 		return err
 	}
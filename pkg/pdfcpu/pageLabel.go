@@ -0,0 +1,666 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// PageLabel represents a page label range starting at page PageFrom and extending to the next range.
+type PageLabel struct {
+	PageFrom int    `json:"page"`             // First page of this range.
+	Style    string `json:"style,omitempty"`  // D, R, r, A, a or none for prefix only labels.
+	Prefix   string `json:"prefix,omitempty"` // Label prefix.
+	Start    int    `json:"start,omitempty"`  // Numeric value for the first page of this range.
+}
+
+// pageLabelValue represents the label of a single page.
+type pageLabelValue struct {
+	style  string
+	prefix string
+	nr     int
+}
+
+func validPageLabelStyle(s string) bool {
+	return types.MemberOf(s, []string{"", "D", "R", "r", "A", "a"})
+}
+
+func romanNumeral(i int) string {
+	vals := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	syms := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var sb strings.Builder
+	for j, v := range vals {
+		for i >= v {
+			sb.WriteString(syms[j])
+			i -= v
+		}
+	}
+	return sb.String()
+}
+
+func letterNumeral(i int) string {
+	// A to Z, followed by AA to ZZ, AAA to ZZZ ...
+	c := string(rune('A' + (i-1)%26))
+	return strings.Repeat(c, (i-1)/26+1)
+}
+
+func (v pageLabelValue) String() string {
+	s := v.prefix
+	switch v.style {
+	case "D":
+		s += strconv.Itoa(v.nr)
+	case "R":
+		s += romanNumeral(v.nr)
+	case "r":
+		s += strings.ToLower(romanNumeral(v.nr))
+	case "A":
+		s += letterNumeral(v.nr)
+	case "a":
+		s += strings.ToLower(letterNumeral(v.nr))
+	}
+	return s
+}
+
+// String returns a sample representation for the first page label of this range.
+func (pl PageLabel) String() string {
+	start := pl.Start
+	if start < 1 {
+		start = 1
+	}
+	return pageLabelValue{style: pl.Style, prefix: pl.Prefix, nr: start}.String()
+}
+
+func pageLabelFromDict(xRefTable *model.XRefTable, o types.Object, pageFrom int) (*PageLabel, error) {
+	d, err := xRefTable.DereferenceDict(o)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.Errorf("pdfcpu: corrupt page label for page %d", pageFrom)
+	}
+
+	pl := PageLabel{PageFrom: pageFrom, Start: 1}
+
+	if o, found := d.Find("S"); found {
+		n, err := xRefTable.DereferenceName(o, model.V13, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !validPageLabelStyle(n.Value()) {
+			return nil, errors.Errorf("pdfcpu: invalid page label style: %s", n.Value())
+		}
+		pl.Style = n.Value()
+	}
+
+	if o, found := d.Find("P"); found {
+		s, err := xRefTable.DereferenceText(o)
+		if err != nil {
+			return nil, err
+		}
+		pl.Prefix = s
+	}
+
+	if o, found := d.Find("St"); found {
+		i, err := xRefTable.DereferenceInteger(o)
+		if err != nil {
+			return nil, err
+		}
+		if i != nil && i.Value() > 0 {
+			pl.Start = i.Value()
+		}
+	}
+
+	return &pl, nil
+}
+
+func pageLabelsFromNumberTree(xRefTable *model.XRefTable, o types.Object, visited types.IntSet, m map[int]PageLabel) error {
+	if ir, ok := o.(types.IndirectRef); ok {
+		if visited[ir.ObjectNumber.Value()] {
+			return nil
+		}
+		visited[ir.ObjectNumber.Value()] = true
+	}
+
+	d, err := xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return err
+	}
+
+	if o, found := d.Find("Kids"); found {
+		kids, err := xRefTable.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+		for _, kid := range kids {
+			if err := pageLabelsFromNumberTree(xRefTable, kid, visited, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	o, found := d.Find("Nums")
+	if !found {
+		return nil
+	}
+
+	nums, err := xRefTable.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(nums); i += 2 {
+		k, err := xRefTable.DereferenceInteger(nums[i])
+		if err != nil || k == nil {
+			return errors.New("pdfcpu: corrupt page label number tree")
+		}
+		pl, err := pageLabelFromDict(xRefTable, nums[i+1], k.Value()+1)
+		if err != nil {
+			return err
+		}
+		m[pl.PageFrom] = *pl
+	}
+
+	return nil
+}
+
+// PageLabels returns the page label ranges of ctx sorted by page number.
+func PageLabels(ctx *model.Context) ([]PageLabel, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := rootDict.Find("PageLabels")
+	if !found {
+		return nil, nil
+	}
+
+	m := map[int]PageLabel{}
+	if err := pageLabelsFromNumberTree(ctx.XRefTable, o, types.IntSet{}, m); err != nil {
+		return nil, err
+	}
+
+	var pls []PageLabel
+	for _, pl := range m {
+		if pl.PageFrom <= ctx.PageCount {
+			pls = append(pls, pl)
+		}
+	}
+
+	sort.Slice(pls, func(i, j int) bool { return pls[i].PageFrom < pls[j].PageFrom })
+
+	return pls, nil
+}
+
+// pageLabelValues returns the label value of each page of ctx in page order.
+// Returns nil if ctx does not use page labels.
+func pageLabelValues(ctx *model.Context) ([]pageLabelValue, error) {
+	pls, err := PageLabels(ctx)
+	if err != nil || len(pls) == 0 {
+		return nil, err
+	}
+
+	vv := make([]pageLabelValue, ctx.PageCount)
+
+	for i := range vv {
+		// Pages preceding the first range get decimal labels.
+		vv[i] = pageLabelValue{style: "D", nr: i + 1}
+	}
+
+	for j, pl := range pls {
+		pageThru := ctx.PageCount
+		if j+1 < len(pls) {
+			pageThru = pls[j+1].PageFrom - 1
+		}
+		for p := pl.PageFrom; p <= pageThru; p++ {
+			vv[p-1] = pageLabelValue{style: pl.Style, prefix: pl.Prefix, nr: pl.Start + p - pl.PageFrom}
+		}
+	}
+
+	return vv, nil
+}
+
+// PageLabelStrings returns the label of each page of ctx in page order.
+// Pages of documents without page labels are labelled by their page number.
+func PageLabelStrings(ctx *model.Context) ([]string, error) {
+	vv, err := pageLabelValues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]string, ctx.PageCount)
+	for i := range ss {
+		if vv == nil {
+			ss[i] = strconv.Itoa(i + 1)
+			continue
+		}
+		ss[i] = vv[i].String()
+	}
+
+	return ss, nil
+}
+
+// PageNrForLabel returns the number of the first page of ctx labelled s.
+func PageNrForLabel(ctx *model.Context, s string) (int, error) {
+	ss, err := PageLabelStrings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, label := range ss {
+		if label == s {
+			return i + 1, nil
+		}
+	}
+
+	return 0, errors.Errorf("pdfcpu: unknown page label: %s", s)
+}
+
+// pageLabelRanges compacts page label values into page label ranges.
+func pageLabelRanges(vv []pageLabelValue) []PageLabel {
+	var pls []PageLabel
+
+	for i, v := range vv {
+		if i > 0 {
+			prev := vv[i-1]
+			if v.style == prev.style && v.prefix == prev.prefix && (v.style == "" || v.nr == prev.nr+1) {
+				continue
+			}
+		}
+		pls = append(pls, PageLabel{PageFrom: i + 1, Style: v.style, Prefix: v.prefix, Start: v.nr})
+	}
+
+	return pls
+}
+
+func validatePageLabels(ctx *model.Context, pls []PageLabel) error {
+	for i, pl := range pls {
+		if pl.PageFrom < 1 || pl.PageFrom > ctx.PageCount {
+			return errors.Errorf("pdfcpu: invalid page label page: %d", pl.PageFrom)
+		}
+		if !validPageLabelStyle(pl.Style) {
+			return errors.Errorf("pdfcpu: invalid page label style: %s (use one of D, R, r, A, a)", pl.Style)
+		}
+		if pl.Start < 0 {
+			return errors.Errorf("pdfcpu: invalid page label start: %d", pl.Start)
+		}
+		if i > 0 && pls[i-1].PageFrom == pl.PageFrom {
+			return errors.Errorf("pdfcpu: duplicate page label for page: %d", pl.PageFrom)
+		}
+	}
+	return nil
+}
+
+func pageLabelDict(pl PageLabel) (types.Dict, error) {
+	d := types.Dict(map[string]types.Object{"Type": types.Name("PageLabel")})
+
+	if pl.Style != "" {
+		d["S"] = types.Name(pl.Style)
+	}
+
+	if pl.Prefix != "" {
+		s, err := types.EscapedUTF16String(pl.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		d["P"] = types.StringLiteral(*s)
+	}
+
+	if pl.Start > 1 {
+		d["St"] = types.Integer(pl.Start)
+	}
+
+	return d, nil
+}
+
+func writePageLabels(ctx *model.Context, pls []PageLabel) error {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	if len(pls) == 0 {
+		if o, found := rootDict.Find("PageLabels"); found {
+			if ir, ok := o.(types.IndirectRef); ok {
+				if err := ctx.DeleteObjectGraph(ir); err != nil {
+					return err
+				}
+			}
+			rootDict.Delete("PageLabels")
+		}
+		return nil
+	}
+
+	nums := types.Array{}
+	for _, pl := range pls {
+		d, err := pageLabelDict(pl)
+		if err != nil {
+			return err
+		}
+		nums = append(nums, types.Integer(pl.PageFrom-1), d)
+	}
+
+	d := types.Dict(map[string]types.Object{"Nums": nums})
+
+	if o, found := rootDict.Find("PageLabels"); found {
+		if ir, ok := o.(types.IndirectRef); ok {
+			if entry, found := ctx.FindTableEntryForIndRef(&ir); found {
+				entry.Object = d
+				return nil
+			}
+		}
+	}
+
+	ir, err := ctx.IndRefForNewObject(d)
+	if err != nil {
+		return err
+	}
+
+	rootDict["PageLabels"] = *ir
+
+	return nil
+}
+
+func sortedPageLabels(pls []PageLabel) []PageLabel {
+	pls1 := make([]PageLabel, len(pls))
+	copy(pls1, pls)
+	sort.SliceStable(pls1, func(i, j int) bool { return pls1[i].PageFrom < pls1[j].PageFrom })
+	return pls1
+}
+
+// SetPageLabels replaces the page labels of ctx by pls.
+// If pls does not cover the first page, a decimal range gets inserted for page 1.
+func SetPageLabels(ctx *model.Context, pls []PageLabel) error {
+	pls = sortedPageLabels(pls)
+
+	if err := validatePageLabels(ctx, pls); err != nil {
+		return err
+	}
+
+	if len(pls) > 0 && pls[0].PageFrom > 1 {
+		pls = append([]PageLabel{{PageFrom: 1, Style: "D"}}, pls...)
+	}
+
+	return writePageLabels(ctx, pls)
+}
+
+// AddPageLabels inserts pls into the page labels of ctx replacing any ranges starting on the same page.
+func AddPageLabels(ctx *model.Context, pls []PageLabel) error {
+	pls = sortedPageLabels(pls)
+
+	if err := validatePageLabels(ctx, pls); err != nil {
+		return err
+	}
+
+	pls0, err := PageLabels(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := map[int]PageLabel{}
+	for _, pl := range pls0 {
+		m[pl.PageFrom] = pl
+	}
+	for _, pl := range pls {
+		m[pl.PageFrom] = pl
+	}
+
+	pls = nil
+	for _, pl := range m {
+		pls = append(pls, pl)
+	}
+
+	return SetPageLabels(ctx, pls)
+}
+
+// RemovePageLabels removes the page label ranges starting at pageNrs.
+// An empty pageNrs removes all page labels.
+// Returns true if at least one range was removed.
+func RemovePageLabels(ctx *model.Context, pageNrs []int) (bool, error) {
+	pls, err := PageLabels(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if len(pls) == 0 {
+		return false, nil
+	}
+
+	if len(pageNrs) == 0 {
+		return true, writePageLabels(ctx, nil)
+	}
+
+	var (
+		pls1    []PageLabel
+		removed bool
+	)
+
+	for _, pl := range pls {
+		if types.IntMemberOf(pl.PageFrom, pageNrs) {
+			removed = true
+			continue
+		}
+		pls1 = append(pls1, pl)
+	}
+
+	if !removed {
+		return false, nil
+	}
+
+	return true, SetPageLabels(ctx, pls1)
+}
+
+// PageLabelList returns a list of the page label ranges of ctx.
+func PageLabelList(ctx *model.Context) ([]string, error) {
+	pls, err := PageLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pls) == 0 {
+		return []string{"no page labels available"}, nil
+	}
+
+	ss := []string{"pages     style prefix     first label"}
+	ss = append(ss, strings.Repeat("=", 40))
+
+	for i, pl := range pls {
+		pageThru := ctx.PageCount
+		if i+1 < len(pls) {
+			pageThru = pls[i+1].PageFrom - 1
+		}
+		pages := strconv.Itoa(pl.PageFrom)
+		if pageThru > pl.PageFrom {
+			pages += "-" + strconv.Itoa(pageThru)
+		}
+		style := pl.Style
+		if style == "" {
+			style = "-"
+		}
+		ss = append(ss, fmt.Sprintf("%-9s %-5s %-10s %s", pages, style, pl.Prefix, pl))
+	}
+
+	return ss, nil
+}
+
+func parsePageLabelPage(s string, pl *PageLabel) error {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 1 {
+		return errors.Errorf("pdfcpu: invalid page label page: %s", s)
+	}
+	pl.PageFrom = i
+	return nil
+}
+
+func parsePageLabelStyle(s string, pl *PageLabel) error {
+	if s == "none" {
+		s = ""
+	}
+	if !validPageLabelStyle(s) {
+		return errors.Errorf("pdfcpu: invalid page label style: %s (use one of D, R, r, A, a, none)", s)
+	}
+	pl.Style = s
+	return nil
+}
+
+func parsePageLabelPrefix(s string, pl *PageLabel) error {
+	pl.Prefix = s
+	return nil
+}
+
+func parsePageLabelStart(s string, pl *PageLabel) error {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 1 {
+		return errors.Errorf("pdfcpu: invalid page label start: %s", s)
+	}
+	pl.Start = i
+	return nil
+}
+
+var pageLabelParamMap = map[string]func(string, *PageLabel) error{
+	"page":   parsePageLabelPage,
+	"style":  parsePageLabelStyle,
+	"prefix": parsePageLabelPrefix,
+	"start":  parsePageLabelStart,
+}
+
+// ParsePageLabelDetails parses a page label range description eg. "page:1, style:r" or "page:5, prefix:A-".
+func ParsePageLabelDetails(s string) (*PageLabel, error) {
+	if s == "" {
+		return nil, errors.New("pdfcpu: missing page label description")
+	}
+
+	pl := &PageLabel{Style: "D"}
+
+	for _, s := range strings.Split(s, ",") {
+
+		ss := strings.SplitN(s, ":", 2)
+		if len(ss) != 2 {
+			return nil, errors.New("pdfcpu: invalid page label description. Please consult pdfcpu help pagelabels")
+		}
+
+		paramPrefix := strings.ToLower(strings.TrimSpace(ss[0]))
+		paramValueStr := strings.TrimSpace(ss[1])
+
+		var param string
+		for k := range pageLabelParamMap {
+			if !strings.HasPrefix(k, paramPrefix) {
+				continue
+			}
+			if len(param) > 0 {
+				return nil, errors.Errorf("pdfcpu: ambiguous parameter prefix \"%s\"", paramPrefix)
+			}
+			param = k
+		}
+
+		if param == "" {
+			return nil, errors.Errorf("pdfcpu: unknown parameter prefix \"%s\"", paramPrefix)
+		}
+
+		if err := pageLabelParamMap[param](paramValueStr, pl); err != nil {
+			return nil, err
+		}
+	}
+
+	if pl.PageFrom == 0 {
+		return nil, errors.New("pdfcpu: page label description: missing page")
+	}
+
+	return pl, nil
+}
+
+// pageLabelAppender collects page label values for a sequence of pages.
+// Pages originating from documents without page labels get labelled by their resulting page number.
+type pageLabelAppender []pageLabelValue
+
+func (a *pageLabelAppender) append(vv []pageLabelValue, pageNr int) {
+	if pageNr == 0 {
+		// eg. divider page
+		*a = append(*a, pageLabelValue{})
+		return
+	}
+	if vv == nil {
+		*a = append(*a, pageLabelValue{style: "D", nr: len(*a) + 1})
+		return
+	}
+	*a = append(*a, vv[pageNr-1])
+}
+
+// addPageLabels carries over the page labels of ctxSrc for pageNrs appended to ctxDest.
+func addPageLabels(ctxSrc, ctxDest *model.Context, pageNrs []int) error {
+	vvSrc, err := pageLabelValues(ctxSrc)
+	if err != nil || vvSrc == nil {
+		return err
+	}
+
+	vvDest, err := pageLabelValues(ctxDest)
+	if err != nil {
+		return err
+	}
+
+	a := pageLabelAppender{}
+	for i := 1; i <= ctxDest.PageCount; i++ {
+		a.append(vvDest, i)
+	}
+	for _, p := range pageNrs {
+		a.append(vvSrc, p)
+	}
+
+	return writePageLabels(ctxDest, pageLabelRanges(a))
+}
+
+// mergePageLabels sets the page labels of ctxDest resulting from merging in srcPageCount pages labelled vvSrc.
+// vvDest are the page labels of the original destPageCount pages of ctxDest.
+func mergePageLabels(ctxDest *model.Context, vvDest, vvSrc []pageLabelValue, destPageCount, srcPageCount int, zip, dividerPage bool) error {
+	if vvDest == nil && vvSrc == nil {
+		return nil
+	}
+
+	a := pageLabelAppender{}
+
+	if zip {
+		for i := 1; i <= destPageCount || i <= srcPageCount; i++ {
+			if i <= destPageCount {
+				a.append(vvDest, i)
+			}
+			if i <= srcPageCount {
+				a.append(vvSrc, i)
+			}
+		}
+		return writePageLabels(ctxDest, pageLabelRanges(a))
+	}
+
+	for i := 1; i <= destPageCount; i++ {
+		a.append(vvDest, i)
+	}
+	if dividerPage {
+		a.append(nil, 0)
+	}
+	for i := 1; i <= srcPageCount; i++ {
+		a.append(vvSrc, i)
+	}
+
+	return writePageLabels(ctxDest, pageLabelRanges(a))
+}
@@ -0,0 +1,775 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+const sRGBOutputCondition = "sRGB IEC61966-2.1"
+
+// ConvertToPDFA applies all changes to ctx that are necessary for conformance with ctx.Conformance
+// and can be done automatically. Supported conformance levels are PDF/A-2b and PDF/A-3b.
+//
+// The XMP metadata gets generated from the document info dict when writing ctx.
+// Remaining violations like missing font programs need to be checked by running the PDF/A validation on the result.
+func ConvertToPDFA(ctx *model.Context) error {
+	c := ctx.Conformance
+	if c != model.PDFA2B && c != model.PDFA3B {
+		return errors.Errorf("pdfcpu: PDF/A conversion: unsupported conformance level %s", c)
+	}
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("converting to %s\n", c)
+	}
+
+	// Remove encryption.
+	ctx.Encrypt = nil
+	ctx.EncKey = nil
+
+	// PDF/A-2 and PDF/A-3 are based on PDF 1.7.
+	if ctx.XRefTable.Version() > model.V17 {
+		v := model.V17
+		ctx.HeaderVersion = &v
+		ctx.RootVersion = nil
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	if err := fixCatalogForPDFA(ctx, rootDict); err != nil {
+		return err
+	}
+
+	if err := fixPagesForPDFA(ctx); err != nil {
+		return err
+	}
+
+	if err := fixObjectsForPDFA(ctx, rootDict); err != nil {
+		return err
+	}
+
+	return ensurePDFAOutputIntent(ctx, rootDict)
+}
+
+func forbiddenAction(ctx *model.Context, o types.Object) (bool, error) {
+	o, err := ctx.Dereference(o)
+	if err != nil {
+		return false, err
+	}
+
+	// Keys like A also occur as resource names.
+	d, ok := o.(types.Dict)
+	if !ok {
+		return false, nil
+	}
+
+	if t := d.Type(); t != nil && *t != "Action" {
+		return false, nil
+	}
+
+	s := d.NameEntry("S")
+	if s == nil {
+		return false, nil
+	}
+
+	if types.MemberOf(*s, model.PDFAForbiddenActions) {
+		return true, nil
+	}
+
+	if *s == "Named" {
+		n := d.NameEntry("N")
+		return n == nil || !types.MemberOf(*n, model.PDFAPermittedNamedActions), nil
+	}
+
+	return false, nil
+}
+
+func removeForbiddenActions(ctx *model.Context, d types.Dict) error {
+	// Additional actions are not allowed for any object.
+	d.Delete("AA")
+
+	for _, k := range []string{"A", "OpenAction", "Next"} {
+		o, found := d.Find(k)
+		if !found {
+			continue
+		}
+
+		if a, ok := o.(types.Array); ok && k == "Next" {
+			// Next may also be an array of actions.
+			var a1 types.Array
+			for _, o1 := range a {
+				forbidden, err := forbiddenAction(ctx, o1)
+				if err != nil {
+					return err
+				}
+				if !forbidden {
+					a1 = append(a1, o1)
+				}
+			}
+			if len(a1) == 0 {
+				d.Delete(k)
+				continue
+			}
+			d[k] = a1
+			continue
+		}
+
+		forbidden, err := forbiddenAction(ctx, o)
+		if err != nil {
+			return err
+		}
+		if forbidden {
+			d.Delete(k)
+		}
+	}
+
+	return nil
+}
+
+func fixOCPropertiesForPDFA(ctx *model.Context, rootDict types.Dict) error {
+	o, found := rootDict.Find("OCProperties")
+	if !found {
+		return nil
+	}
+
+	d, err := ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return err
+	}
+
+	var configs types.Array
+	if o, found := d.Find("D"); found {
+		configs = append(configs, o)
+	}
+	if o, found := d.Find("Configs"); found {
+		a, err := ctx.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+		configs = append(configs, a...)
+	}
+
+	for i, o := range configs {
+		d1, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d1 == nil {
+			continue
+		}
+		d1.Delete("AS")
+		d1.Insert("Name", types.StringLiteral(fmt.Sprintf("Config%d", i)))
+	}
+
+	return nil
+}
+
+func fixCatalogForPDFA(ctx *model.Context, rootDict types.Dict) error {
+	if err := removeForbiddenActions(ctx, rootDict); err != nil {
+		return err
+	}
+
+	if o, found := rootDict.Find("Names"); found {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			d.Delete("JavaScript")
+		}
+	}
+
+	if o, found := rootDict.Find("AcroForm"); found {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			d.Delete("XFA")
+			d.Delete("NeedAppearances")
+		}
+	}
+
+	return fixOCPropertiesForPDFA(ctx, rootDict)
+}
+
+func forbiddenAnnotation(ctx *model.Context, d types.Dict) bool {
+	st := d.Subtype()
+	if st == nil {
+		return false
+	}
+	return ctx.Conformance.AnnotationForbidden(*st)
+}
+
+func fixPagesForPDFA(ctx *model.Context) error {
+	for i := 1; i <= ctx.PageCount; i++ {
+		d, _, _, err := ctx.PageDict(i, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		d.Delete("AA")
+		d.Delete("PresSteps")
+
+		o, found := d.Find("Annots")
+		if !found {
+			continue
+		}
+
+		annots, err := ctx.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+
+		var a types.Array
+		for _, o := range annots {
+			d1, err := ctx.DereferenceDict(o)
+			if err != nil {
+				return err
+			}
+			if d1 != nil && forbiddenAnnotation(ctx, d1) {
+				continue
+			}
+			a = append(a, o)
+		}
+
+		if len(a) == len(annots) {
+			continue
+		}
+
+		if len(a) == 0 {
+			d.Delete("Annots")
+			continue
+		}
+
+		if ir, ok := o.(types.IndirectRef); ok {
+			entry, _ := ctx.FindTableEntryForIndRef(&ir)
+			entry.Object = a
+			continue
+		}
+
+		d["Annots"] = a
+	}
+
+	return nil
+}
+
+func annotation(d types.Dict) bool {
+	if t := d.Type(); t != nil {
+		return *t == "Annot"
+	}
+	_, hasRect := d.Find("Rect")
+	return hasRect && d.Subtype() != nil
+}
+
+func fixAnnotationFlags(d types.Dict) {
+	if st := d.Subtype(); st != nil && *st == "Popup" {
+		return
+	}
+
+	var f model.AnnotationFlags
+	if i := d.IntEntry("F"); i != nil {
+		f = model.AnnotationFlags(*i)
+	}
+
+	f |= model.AnnPrint
+	f &^= model.AnnInvisible | model.AnnHidden | model.AnnNoView | model.AnnToggleNoView
+
+	d["F"] = types.Integer(f)
+}
+
+func annotationRect(ctx *model.Context, d types.Dict) (*types.Rectangle, error) {
+	o, found := d.Find("Rect")
+	if !found {
+		return nil, nil
+	}
+	a, err := ctx.DereferenceArray(o)
+	if err != nil || len(a) != 4 {
+		return nil, err
+	}
+	return ctx.RectForArray(a)
+}
+
+func fixAnnotationAppearance(ctx *model.Context, d types.Dict) error {
+	st := d.Subtype()
+	if st == nil || *st == "Popup" || *st == "Link" {
+		return nil
+	}
+
+	o, found := d.Find("AP")
+	if found {
+		ap, err := ctx.DereferenceDict(o)
+		if err != nil || ap == nil {
+			return err
+		}
+		if _, ok := ap.Find("N"); ok {
+			// Only the normal appearance is allowed.
+			ap.Delete("R")
+			ap.Delete("D")
+			return nil
+		}
+	}
+
+	r, err := annotationRect(ctx, d)
+	if err != nil || r == nil {
+		return err
+	}
+
+	if r.Width() == 0 && r.Height() == 0 {
+		// Annotations with zero size are exempt.
+		return nil
+	}
+
+	// PDF/A requires embedded fonts, so text is not rendered.
+	ir, err := ctx.CreateAnnotationAppearance(d, false)
+	if err != nil {
+		return err
+	}
+
+	d["AP"] = types.Dict(map[string]types.Object{"N": *ir})
+
+	return nil
+}
+
+func fixAnnotationForPDFA(ctx *model.Context, d types.Dict) error {
+	fixAnnotationFlags(d)
+	return fixAnnotationAppearance(ctx, d)
+}
+
+func fixExtGStateForPDFA(d types.Dict) {
+	if _, found := d.Find("TR"); found {
+		d.Delete("TR")
+	}
+	if o, found := d.Find("TR2"); found {
+		if n, ok := o.(types.Name); !ok || n.Value() != "Default" {
+			d["TR2"] = types.Name("Default")
+		}
+	}
+	d.Delete("HTO")
+}
+
+func fixFileSpecForPDFA(ctx *model.Context, d types.Dict, indRef *types.IndirectRef, rootDict types.Dict) error {
+	o, found := d.Find("EF")
+	if !found || ctx.Conformance.Part() < 3 {
+		return nil
+	}
+
+	d.Insert("AFRelationship", types.Name("Unspecified"))
+
+	if o, found := d.Find("F"); found {
+		d.Insert("UF", o)
+	}
+
+	ef, err := ctx.DereferenceDict(o)
+	if err != nil || ef == nil {
+		return err
+	}
+
+	for _, k := range []string{"F", "UF"} {
+		o, found := ef.Find(k)
+		if !found {
+			continue
+		}
+		sd, _, err := ctx.DereferenceStreamDict(o)
+		if err != nil || sd == nil {
+			return err
+		}
+		// Modify the dict in place.
+		sd.Insert("Subtype", types.Name("application/octet-stream"))
+		sd.Insert("Params", types.NewDict())
+	}
+
+	if indRef == nil {
+		return nil
+	}
+
+	// Associate the embedded file with the document.
+	a, err := ctx.DereferenceArray(rootDict["AF"])
+	if err != nil {
+		return err
+	}
+	for _, o := range a {
+		if ir, ok := o.(types.IndirectRef); ok && ir.ObjectNumber == indRef.ObjectNumber {
+			return nil
+		}
+	}
+	rootDict["AF"] = append(a, *indRef)
+
+	return nil
+}
+
+func reencodeLZWStream(sd *types.StreamDict) error {
+	lzw := false
+	for _, f := range sd.FilterPipeline {
+		switch f.Name {
+		case filter.LZW:
+			lzw = true
+		case filter.Flate, filter.ASCII85, filter.ASCIIHex, filter.RunLength:
+		default:
+			// We can only replace lossless filters that can be decoded.
+			return nil
+		}
+	}
+
+	if !lzw {
+		return nil
+	}
+
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+
+	sd.FilterPipeline = []types.PDFFilter{{Name: filter.Flate, DecodeParms: nil}}
+	sd.Update("Filter", types.Name(filter.Flate))
+	sd.Delete("DecodeParms")
+
+	return sd.Encode()
+}
+
+func fixStreamDictForPDFA(ctx *model.Context, sd *types.StreamDict) error {
+	if err := reencodeLZWStream(sd); err != nil {
+		return err
+	}
+
+	st := sd.Subtype()
+	if st == nil {
+		return nil
+	}
+
+	switch *st {
+	case "Image":
+		sd.Delete("Alternates")
+		sd.Delete("OPI")
+		if b := sd.BooleanEntry("Interpolate"); b != nil && *b {
+			sd.Delete("Interpolate")
+		}
+	case "Form":
+		sd.Delete("OPI")
+		sd.Delete("PS")
+		sd.Delete("Ref")
+	}
+
+	return nil
+}
+
+func fixDictForPDFA(ctx *model.Context, d types.Dict, indRef *types.IndirectRef, rootDict types.Dict) error {
+	if err := removeForbiddenActions(ctx, d); err != nil {
+		return err
+	}
+
+	if annotation(d) {
+		if err := fixAnnotationForPDFA(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	if t := d.Type(); t != nil && *t == "ExtGState" {
+		fixExtGStateForPDFA(d)
+	} else if _, found := d.Find("TR"); found {
+		// The Type entry of graphics state parameter dicts is optional.
+		fixExtGStateForPDFA(d)
+	}
+
+	if t := d.Type(); t != nil && *t == "Filespec" || t == nil && d["EF"] != nil {
+		if err := fixFileSpecForPDFA(ctx, d, indRef, rootDict); err != nil {
+			return err
+		}
+	}
+
+	// Process direct objects.
+	for _, o := range d {
+		if err := fixDirectObjectForPDFA(ctx, o, rootDict); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fixDirectObjectForPDFA(ctx *model.Context, o types.Object, rootDict types.Dict) error {
+	switch o := o.(type) {
+	case types.Dict:
+		return fixDictForPDFA(ctx, o, nil, rootDict)
+	case types.Array:
+		for _, o1 := range o {
+			if err := fixDirectObjectForPDFA(ctx, o1, rootDict); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func fixObjectsForPDFA(ctx *model.Context, rootDict types.Dict) error {
+	// Take a snapshot of the object numbers since new objects may get created along the way.
+	objNrs := make([]int, 0, len(ctx.Table))
+	for objNr := range ctx.Table {
+		objNrs = append(objNrs, objNr)
+	}
+
+	for _, objNr := range objNrs {
+		entry := ctx.Table[objNr]
+		if entry == nil || entry.Free || entry.Object == nil {
+			continue
+		}
+
+		indRef := types.NewIndirectRef(objNr, *entry.Generation)
+
+		switch o := entry.Object.(type) {
+
+		case types.Dict:
+			if err := fixDictForPDFA(ctx, o, indRef, rootDict); err != nil {
+				return err
+			}
+
+		case types.StreamDict:
+			if err := fixStreamDictForPDFA(ctx, &o); err != nil {
+				return err
+			}
+			entry.Object = o
+			if err := fixDictForPDFA(ctx, o.Dict, indRef, rootDict); err != nil {
+				return err
+			}
+
+		case types.Array:
+			if err := fixDirectObjectForPDFA(ctx, o, rootDict); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func pdfaOutputIntent(ctx *model.Context, a types.Array) (bool, error) {
+	for _, o := range a {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return false, err
+		}
+		if d == nil {
+			continue
+		}
+		if s := d.NameEntry("S"); s != nil && *s == "GTS_PDFA1" {
+			if _, found := d.Find("DestOutputProfile"); found {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func ensurePDFAOutputIntent(ctx *model.Context, rootDict types.Dict) error {
+	var a types.Array
+
+	o, found := rootDict.Find("OutputIntents")
+	if found {
+		var err error
+		if a, err = ctx.DereferenceArray(o); err != nil {
+			return err
+		}
+		ok, err := pdfaOutputIntent(ctx, a)
+		if err != nil || ok {
+			return err
+		}
+	}
+
+	sd, err := ctx.NewStreamDictForBuf(model.SRGBICCProfile())
+	if err != nil {
+		return err
+	}
+	sd.InsertInt("N", 3)
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	ir, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d := types.Dict(map[string]types.Object{
+		"Type":                      types.Name("OutputIntent"),
+		"S":                         types.Name("GTS_PDFA1"),
+		"OutputConditionIdentifier": types.StringLiteral(sRGBOutputCondition),
+		"RegistryName":              types.StringLiteral("http://www.color.org"),
+		"Info":                      types.StringLiteral(sRGBOutputCondition),
+		"DestOutputProfile":         *ir,
+	})
+
+	a = append(a, d)
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		entry, _ := ctx.FindTableEntryForIndRef(&ir)
+		entry.Object = a
+		return nil
+	}
+
+	rootDict["OutputIntents"] = a
+
+	return nil
+}
+
+func xmpText(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func pdfaXMPPacket(ctx *model.Context, infoDict types.Dict, t time.Time) ([]byte, error) {
+	text := func(key string) (string, error) {
+		o, found := infoDict.Find(key)
+		if !found {
+			return "", nil
+		}
+		s, err := ctx.DereferenceText(o)
+		if err != nil {
+			return "", err
+		}
+		return xmpText(s), nil
+	}
+
+	vals := map[string]string{}
+	for _, k := range []string{"Title", "Author", "Subject", "Keywords", "Creator", "Producer"} {
+		s, err := text(k)
+		if err != nil {
+			return nil, err
+		}
+		vals[k] = s
+	}
+
+	// The XMP dates need to match the info dict, t applies to missing dates only.
+	date := func(key string) (string, error) {
+		o, found := infoDict.Find(key)
+		if !found {
+			return t.Format(time.RFC3339), nil
+		}
+		s, err := ctx.DereferenceText(o)
+		if err != nil {
+			return "", err
+		}
+		d, ok := types.DateTime(s, ctx.XRefTable.ValidationMode == model.ValidationRelaxed)
+		if !ok {
+			return t.Format(time.RFC3339), nil
+		}
+		return d.Format(time.RFC3339), nil
+	}
+
+	created, err := date("CreationDate")
+	if err != nil {
+		return nil, err
+	}
+
+	modified, err := date("ModDate")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.WriteString("  <rdf:Description rdf:about=\"\"\n")
+	b.WriteString("    xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n")
+	b.WriteString("    xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n")
+	b.WriteString("    xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"\n")
+	b.WriteString("    xmlns:pdfaid=\"" + model.NamespacePDFAID + "\">\n")
+	fmt.Fprintf(&b, "   <pdfaid:part>%d</pdfaid:part>\n", ctx.Conformance.Part())
+	fmt.Fprintf(&b, "   <pdfaid:conformance>%s</pdfaid:conformance>\n", ctx.Conformance.Level())
+	b.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	if s := vals["Title"]; s != "" {
+		fmt.Fprintf(&b, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", s)
+	}
+	if s := vals["Author"]; s != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", s)
+	}
+	if s := vals["Subject"]; s != "" {
+		fmt.Fprintf(&b, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", s)
+	}
+	if s := vals["Creator"]; s != "" {
+		fmt.Fprintf(&b, "   <xmp:CreatorTool>%s</xmp:CreatorTool>\n", s)
+	}
+	fmt.Fprintf(&b, "   <xmp:CreateDate>%s</xmp:CreateDate>\n", created)
+	fmt.Fprintf(&b, "   <xmp:ModifyDate>%s</xmp:ModifyDate>\n", modified)
+	fmt.Fprintf(&b, "   <xmp:MetadataDate>%s</xmp:MetadataDate>\n", t.Format(time.RFC3339))
+	if s := vals["Producer"]; s != "" {
+		fmt.Fprintf(&b, "   <pdf:Producer>%s</pdf:Producer>\n", s)
+	}
+	if s := vals["Keywords"]; s != "" {
+		fmt.Fprintf(&b, "   <pdf:Keywords>%s</pdf:Keywords>\n", s)
+	}
+	b.WriteString("  </rdf:Description>\n")
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString("<?xpacket end=\"w\"?>")
+
+	return []byte(b.String()), nil
+}
+
+// updatePDFAMetadata synchronizes the catalog's XMP metadata with infoDict.
+func updatePDFAMetadata(ctx *model.Context, infoDict types.Dict, t time.Time) error {
+	bb, err := pdfaXMPPacket(ctx, infoDict, t)
+	if err != nil {
+		return err
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	// PDF/A-1 does not allow filtered metadata streams.
+	sd := types.StreamDict{Dict: types.NewDict(), Content: bb}
+	sd.InsertName("Type", "Metadata")
+	sd.InsertName("Subtype", "XML")
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	if ir := rootDict.IndirectRefEntry("Metadata"); ir != nil {
+		if entry, found := ctx.FindTableEntryForIndRef(ir); found && entry.Object != nil {
+			entry.Object = sd
+			return nil
+		}
+	}
+
+	ir, err := ctx.IndRefForNewObject(sd)
+	if err != nil {
+		return err
+	}
+
+	rootDict["Metadata"] = *ir
+
+	return nil
+}
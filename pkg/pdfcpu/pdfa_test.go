@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestPDFAXMPPacketDates(t *testing.T) {
+	conf := model.NewDefaultConfiguration()
+	conf.Conformance = model.PDFA2B
+
+	ctx, err := ReadFile(filepath.Join("..", "testdata", "test.pdf"), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tt := range []struct {
+		infoDict          types.Dict
+		created, modified string
+	}{
+		{
+			types.Dict{
+				"CreationDate": types.StringLiteral("D:20200102030405+01'00'"),
+				"ModDate":      types.StringLiteral("D:20210102030405Z"),
+			},
+			"2020-01-02T03:04:05+01:00", "2021-01-02T03:04:05Z",
+		},
+		// Missing dates fall back to the time of writing.
+		{types.Dict{}, "2026-01-02T03:04:05Z", "2026-01-02T03:04:05Z"},
+	} {
+		bb, err := pdfaXMPPacket(ctx, tt.infoDict, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := string(bb)
+		for _, want := range []string{
+			"<xmp:CreateDate>" + tt.created + "</xmp:CreateDate>",
+			"<xmp:ModifyDate>" + tt.modified + "</xmp:ModifyDate>",
+			"<xmp:MetadataDate>2026-01-02T03:04:05Z</xmp:MetadataDate>",
+		} {
+			if !strings.Contains(s, want) {
+				t.Errorf("missing %s in:\n%s", want, s)
+			}
+		}
+	}
+}
@@ -141,6 +141,11 @@ func (hb *HorizontalBand) renderAnchoredTextBox(
 		Scale:    1.,
 		ScaleAbs: true,
 		RTL:      hb.RTL, // for user fonts only!
+		Kerning:  font.Kerning,
+	}
+
+	if err := pdf.setFallbackFonts(&td, font, p.Fm, fonts, pageNr); err != nil {
+		return err
 	}
 
 	if col != nil {
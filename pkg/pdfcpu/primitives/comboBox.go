@@ -365,7 +365,7 @@ func (cb *ComboBox) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 		v = model.DecodeUTF8ToByte(v)
 	}
 	lineBB := model.CalcBoundingBox(v, 0, 0, f.Name, f.Size)
-	s := model.PrepBytes(xRefTable, v, f.Name, true, cb.RTL, f.FillFont)
+	s := model.PrepText(xRefTable, v, f.Name, f.Size, true, cb.RTL, f.FillFont)
 	x := 2 * boWidth
 	if x == 0 {
 		x = 2
@@ -380,7 +380,7 @@ func (cb *ComboBox) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 	y := (cb.BoundingBox.Height()-font.LineHeight(f.Name, f.Size))/2 + font.Descent(f.Name, f.Size)
 
 	fmt.Fprintf(buf, "BT /%s %d Tf ", cb.fontID, f.Size)
-	fmt.Fprintf(buf, "%.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td (%s) Tj ET ",
+	fmt.Fprintf(buf, "%.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td %s ET ",
 		f.col.R, f.col.G, f.col.B,
 		f.col.R, f.col.G, f.col.B, x, y, s)
 
@@ -1038,7 +1038,7 @@ func (c *Content) renderBars(p *model.Page) error {
 		if b.Hide {
 			continue
 		}
-		if err := b.render(p); err != nil {
+		if err := renderArtifact(p, func() error { return b.render(p) }); err != nil {
 			return err
 		}
 	}
@@ -1059,7 +1059,7 @@ func (c *Content) renderSimpleBoxes(p *model.Page) error {
 			}
 			sb.mergeIn(sb0)
 		}
-		if err := sb.render(p); err != nil {
+		if err := renderArtifact(p, func() error { return sb.render(p) }); err != nil {
 			return err
 		}
 	}
@@ -1080,7 +1080,11 @@ func (c *Content) renderTextBoxes(p *model.Page, pageNr int, fonts model.FontMap
 			}
 			tb.mergeIn(tb0)
 		}
-		if err := tb.render(p, pageNr, fonts); err != nil {
+		role := tb.Role
+		if role == "" {
+			role = "P"
+		}
+		if err := renderTagged(p, role, "", func() error { return tb.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1101,7 +1105,7 @@ func (c *Content) renderImageBoxes(p *model.Page, pageNr int, images model.Image
 			}
 			ib.mergeIn(ib0)
 		}
-		if err := ib.render(p, pageNr, images); err != nil {
+		if err := renderTagged(p, "Figure", ib.Alt, func() error { return ib.render(p, pageNr, images) }); err != nil {
 			return err
 		}
 	}
@@ -1122,7 +1126,7 @@ func (c *Content) renderTables(p *model.Page, pageNr int, fonts model.FontMap) e
 			}
 			t.mergeIn(t0)
 		}
-		if err := t.render(p, pageNr, fonts); err != nil {
+		if err := renderStructElem(p, "Table", func() error { return t.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1134,7 +1138,7 @@ func (c *Content) renderTextFields(p *model.Page, pageNr int, fonts model.FontMa
 		if tf.Hide {
 			continue
 		}
-		if err := tf.render(p, pageNr, fonts); err != nil {
+		if err := c.page.pdf.renderFormField(p, func() error { return tf.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1146,7 +1150,7 @@ func (c *Content) renderDateFields(p *model.Page, pageNr int, fonts model.FontMa
 		if df.Hide {
 			continue
 		}
-		if err := df.render(p, pageNr, fonts); err != nil {
+		if err := c.page.pdf.renderFormField(p, func() error { return df.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1158,7 +1162,7 @@ func (c *Content) renderCheckBoxes(p *model.Page, pageNr int, fonts model.FontMa
 		if cb.Hide {
 			continue
 		}
-		if err := cb.render(p, pageNr, fonts); err != nil {
+		if err := c.page.pdf.renderFormField(p, func() error { return cb.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1170,7 +1174,7 @@ func (c *Content) renderRadioButtonGroups(p *model.Page, pageNr int, fonts model
 		if rbg.Hide {
 			continue
 		}
-		if err := rbg.render(p, pageNr, fonts); err != nil {
+		if err := c.page.pdf.renderFormField(p, func() error { return rbg.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1182,7 +1186,7 @@ func (c *Content) renderComboBoxes(p *model.Page, pageNr int, fonts model.FontMa
 		if cb.Hide {
 			continue
 		}
-		if err := cb.render(p, pageNr, fonts); err != nil {
+		if err := c.page.pdf.renderFormField(p, func() error { return cb.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1194,7 +1198,7 @@ func (c *Content) renderListBoxes(p *model.Page, pageNr int, fonts model.FontMap
 		if lb.Hide {
 			continue
 		}
-		if err := lb.render(p, pageNr, fonts); err != nil {
+		if err := c.page.pdf.renderFormField(p, func() error { return lb.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1215,7 +1219,7 @@ func (c *Content) renderFieldGroups(p *model.Page, pageNr int, fonts model.FontM
 			}
 			fg.mergeIn(fg0)
 		}
-		if err := fg.render(p, pageNr, fonts); err != nil {
+		if err := renderStructElem(p, "Div", func() error { return fg.render(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -1225,6 +1229,9 @@ func (c *Content) renderFieldGroups(p *model.Page, pageNr int, fonts model.FontM
 func (c *Content) renderBoxesAndGuides(p *model.Page) {
 	pdf := c.page.pdf
 
+	p.BeginArtifact()
+	defer p.EndArtifact()
+
 	// Render mediaBox & contentBox
 	if pdf.ContentBox {
 		draw.DrawRect(p.Buf, c.mediaBox, 0, &color.Green, nil)
@@ -1295,6 +1302,8 @@ func (c *Content) render(p *model.Page, pageNr int, fonts model.FontMap, images
 		return c.Regions.render(p, pageNr, fonts, images)
 	}
 
+	p.BeginArtifact()
+
 	// Render background
 	if c.bgCol != nil {
 		draw.FillRectNoBorder(p.Buf, c.BorderRect(), *c.bgCol)
@@ -1306,6 +1315,8 @@ func (c *Content) render(p *model.Page, pageNr int, fonts model.FontMap, images
 		draw.DrawRect(p.Buf, c.BorderRect(), float64(b.Width), b.col, &b.style)
 	}
 
+	p.EndArtifact()
+
 	if err := c.renderPrimitives(p, pageNr, fonts, images); err != nil {
 		return err
 	}
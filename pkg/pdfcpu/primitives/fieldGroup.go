@@ -295,7 +295,7 @@ func (fg *FieldGroup) renderTextFields(p *model.Page, fonts model.FontMap) error
 		if tf.Hide {
 			continue
 		}
-		if err := tf.doRender(p, fonts); err != nil {
+		if err := fg.pdf.renderFormField(p, func() error { return tf.doRender(p, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -307,7 +307,7 @@ func (fg *FieldGroup) renderDateFields(p *model.Page, fonts model.FontMap) error
 		if df.Hide {
 			continue
 		}
-		if err := df.doRender(p, fonts); err != nil {
+		if err := fg.pdf.renderFormField(p, func() error { return df.doRender(p, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -319,7 +319,7 @@ func (fg *FieldGroup) renderCheckBoxes(p *model.Page, fonts model.FontMap) error
 		if cb.Hide {
 			continue
 		}
-		if err := cb.doRender(p, fonts); err != nil {
+		if err := fg.pdf.renderFormField(p, func() error { return cb.doRender(p, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -331,7 +331,7 @@ func (fg *FieldGroup) renderRadioButtonGroups(p *model.Page, pageNr int, fonts m
 		if rbg.Hide {
 			continue
 		}
-		if err := rbg.doRender(p, pageNr, fonts); err != nil {
+		if err := fg.pdf.renderFormField(p, func() error { return rbg.doRender(p, pageNr, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -343,7 +343,7 @@ func (fg *FieldGroup) renderComboBoxes(p *model.Page, fonts model.FontMap) error
 		if cb.Hide {
 			continue
 		}
-		if err := cb.doRender(p, fonts); err != nil {
+		if err := fg.pdf.renderFormField(p, func() error { return cb.doRender(p, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -355,7 +355,7 @@ func (fg *FieldGroup) renderListBoxes(p *model.Page, fonts model.FontMap) error
 		if cb.Hide {
 			continue
 		}
-		if err := cb.doRender(p, fonts); err != nil {
+		if err := fg.pdf.renderFormField(p, func() error { return cb.doRender(p, fonts) }); err != nil {
 			return err
 		}
 	}
@@ -388,7 +388,7 @@ func (fg *FieldGroup) render(p *model.Page, pageNr int, fonts model.FontMap) err
 	}
 
 	// Render simpleBox containing all fields of this group.
-	if err := fg.renderBBox(bbox, p); err != nil {
+	if err := renderArtifact(p, func() error { return fg.renderBBox(bbox, p) }); err != nil {
 		return err
 	}
 
@@ -0,0 +1,738 @@
+/*
+	Copyright 2026 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package primitives
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Field formats corresponding to the Acrobat built-in JavaScript functions
+// AFNumber_Format, AFPercent_Format, AFDate_FormatEx and AFSpecial_Format.
+const (
+	FieldFormatNumber  = "number"
+	FieldFormatPercent = "percent"
+	FieldFormatDate    = "date"
+	FieldFormatZip     = "zip"
+	FieldFormatZip4    = "zip4"
+	FieldFormatPhone   = "phone"
+	FieldFormatSSN     = "ssn"
+)
+
+// AFSpecial_Format psf values.
+var specialFieldFormats = []string{FieldFormatZip, FieldFormatZip4, FieldFormatPhone, FieldFormatSSN}
+
+// Calculation operations supported by AFSimple_Calculate.
+var calcOps = []string{"SUM", "PRD", "AVG", "MIN", "MAX"}
+
+var afCallRegExp = regexp.MustCompile(`(AF\w+)\s*\(`)
+
+// FieldFormat represents the display format of a text field.
+type FieldFormat struct {
+	Type           string // number, percent, date, zip, zip4, phone, ssn
+	Decimals       int    `json:"dec"`
+	SepStyle       int    `json:"sep"` // 0: 1,234.56 1: 1234.56 2: 1.234,56 3: 1234,56 4: 1'234.56
+	NegStyle       int    `json:"neg"` // 0: -1234.56 1: red 2: (1234.56) 3: red (1234.56)
+	Currency       string // eg. "$"
+	AppendCurrency bool   `json:"appendCurrency"`
+	Date           string // eg. "yyyy-mm-dd"
+	dateFormat     *DateFormat
+}
+
+// FieldRange represents a range validation for numeric text fields.
+type FieldRange struct {
+	Min *float64
+	Max *float64
+}
+
+// FieldCalc represents a calculated text field.
+type FieldCalc struct {
+	Op     string   // sum, prd, avg, min, max
+	Fields []string // fully qualified names of the fields taking part in the calculation
+}
+
+// FieldScripts represents the built-in JavaScript actions of a text field.
+type FieldScripts struct {
+	Format *FieldFormat
+	Range  *FieldRange
+	Calc   *FieldCalc
+}
+
+func (ff *FieldFormat) validate() error {
+	ff.Type = strings.ToLower(ff.Type)
+
+	switch ff.Type {
+
+	case FieldFormatNumber, FieldFormatPercent:
+		if ff.Decimals < 0 || ff.Decimals > 10 {
+			return errors.Errorf("format: dec out of range: %d", ff.Decimals)
+		}
+		if ff.SepStyle < 0 || ff.SepStyle > 4 {
+			return errors.Errorf("format: sep out of range: %d", ff.SepStyle)
+		}
+		if ff.NegStyle < 0 || ff.NegStyle > 3 {
+			return errors.Errorf("format: neg out of range: %d", ff.NegStyle)
+		}
+
+	case FieldFormatDate:
+		df, err := DateFormatForFmtExt(ff.Date)
+		if err != nil {
+			return errors.Errorf("format: unsupported date format: %s", ff.Date)
+		}
+		ff.dateFormat = df
+
+	default:
+		if ff.specialFormat() < 0 {
+			return errors.Errorf("format: unsupported type: %s", ff.Type)
+		}
+	}
+
+	return nil
+}
+
+func (ff *FieldFormat) specialFormat() int {
+	for i, s := range specialFieldFormats {
+		if s == ff.Type {
+			return i
+		}
+	}
+	return -1
+}
+
+func (fr *FieldRange) validate() error {
+	if fr.Min == nil && fr.Max == nil {
+		return errors.New("range: missing min or max")
+	}
+	if fr.Min != nil && fr.Max != nil && *fr.Min > *fr.Max {
+		return errors.New("range: min > max")
+	}
+	return nil
+}
+
+func (fc *FieldCalc) validate(id string) error {
+	fc.Op = strings.ToUpper(fc.Op)
+	if !types.MemberOf(fc.Op, calcOps) {
+		return errors.Errorf("calc: unsupported op: %s", fc.Op)
+	}
+	if len(fc.Fields) == 0 {
+		return errors.New("calc: missing fields")
+	}
+	for _, s := range fc.Fields {
+		if s == id {
+			return errors.New("calc: field refers to itself")
+		}
+	}
+	return nil
+}
+
+func jsString(s string) string {
+	return strconv.Quote(s)
+}
+
+func jsNumber(f *float64) string {
+	if f == nil {
+		return "0"
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func (ff *FieldFormat) scripts() (string, string) {
+	switch ff.Type {
+
+	case FieldFormatNumber:
+		args := fmt.Sprintf("%d, %d, %d, 0, %s, %t", ff.Decimals, ff.SepStyle, ff.NegStyle, jsString(ff.Currency), !ff.AppendCurrency)
+		return "AFNumber_Format(" + args + ");", "AFNumber_Keystroke(" + args + ");"
+
+	case FieldFormatPercent:
+		args := fmt.Sprintf("%d, %d", ff.Decimals, ff.SepStyle)
+		return "AFPercent_Format(" + args + ");", "AFPercent_Keystroke(" + args + ");"
+
+	case FieldFormatDate:
+		args := jsString(ff.dateFormat.Ext)
+		return "AFDate_FormatEx(" + args + ");", "AFDate_KeystrokeEx(" + args + ");"
+	}
+
+	args := strconv.Itoa(ff.specialFormat())
+	return "AFSpecial_Format(" + args + ");", "AFSpecial_Keystroke(" + args + ");"
+}
+
+func (fr *FieldRange) script() string {
+	return fmt.Sprintf("AFRange_Validate(%t, %s, %t, %s);", fr.Min != nil, jsNumber(fr.Min), fr.Max != nil, jsNumber(fr.Max))
+}
+
+func (fc *FieldCalc) script() string {
+	ss := make([]string, len(fc.Fields))
+	for i, s := range fc.Fields {
+		ss[i] = jsString(s)
+	}
+	return fmt.Sprintf("AFSimple_Calculate(%s, new Array(%s));", jsString(fc.Op), strings.Join(ss, ", "))
+}
+
+func javaScriptAction(js string) (types.Dict, error) {
+	var s *string
+	var err error
+
+	for _, r := range js {
+		if r > unicode.MaxASCII {
+			s, err = types.EscapedUTF16String(js)
+			break
+		}
+	}
+	if s == nil && err == nil {
+		s, err = types.Escape(js)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return types.Dict(
+		map[string]types.Object{
+			"JS": types.StringLiteral(*s),
+			"S":  types.Name("JavaScript"),
+		},
+	), nil
+}
+
+// additionalActions returns the additional-actions dict for fs.
+func (fs FieldScripts) additionalActions() (types.Dict, error) {
+	m := map[string]string{}
+
+	if fs.Format != nil {
+		m["F"], m["K"] = fs.Format.scripts()
+	}
+	if fs.Range != nil {
+		m["V"] = fs.Range.script()
+	}
+	if fs.Calc != nil {
+		m["C"] = fs.Calc.script()
+	}
+
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	d := types.Dict{}
+	for k, js := range m {
+		a, err := javaScriptAction(js)
+		if err != nil {
+			return nil, err
+		}
+		d[k] = a
+	}
+
+	return d, nil
+}
+
+// jsArgs parses the argument list of a JavaScript function call starting right after the opening parenthesis.
+// Supported are strings, numbers, booleans and arrays created via "new Array(...)".
+func jsArgs(s string) ([]interface{}, error) {
+	var args []interface{}
+
+	for {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		if s == "" {
+			return nil, errors.New("pdfcpu: unterminated function call")
+		}
+
+		switch c := s[0]; {
+
+		case c == ')':
+			return args, nil
+
+		case c == '"' || c == '\'':
+			i := 1
+			for ; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(s) {
+				return nil, errors.New("pdfcpu: unterminated string")
+			}
+			v := s[1:i]
+			if c == '\'' {
+				v = strings.ReplaceAll(v, `"`, `\"`)
+			}
+			u, err := strconv.Unquote(`"` + v + `"`)
+			if err != nil {
+				u = v
+			}
+			args = append(args, u)
+			s = s[i+1:]
+
+		case strings.HasPrefix(s, "new Array("):
+			i := strings.Index(s, ")")
+			if i < 0 {
+				return nil, errors.New("pdfcpu: unterminated array")
+			}
+			aa, err := jsArgs(s[len("new Array(") : i+1])
+			if err != nil {
+				return nil, err
+			}
+			ss := make([]string, len(aa))
+			for j, a := range aa {
+				ss[j] = fmt.Sprint(a)
+			}
+			args = append(args, ss)
+			s = s[i+1:]
+
+		default:
+			i := strings.IndexAny(s, ",)")
+			if i < 0 {
+				return nil, errors.New("pdfcpu: unterminated function call")
+			}
+			tok := strings.TrimSpace(s[:i])
+			if tok == "true" || tok == "false" {
+				args = append(args, tok == "true")
+			} else if f, err := strconv.ParseFloat(tok, 64); err == nil {
+				args = append(args, f)
+			} else {
+				args = append(args, tok)
+			}
+			s = s[i:]
+		}
+	}
+}
+
+// afCall returns the name and arguments of the first Acrobat built-in function called by js.
+func afCall(js string) (string, []interface{}, error) {
+	m := afCallRegExp.FindStringSubmatchIndex(js)
+	if m == nil {
+		return "", nil, nil
+	}
+	args, err := jsArgs(js[m[1]:])
+	if err != nil {
+		return "", nil, err
+	}
+	return js[m[2]:m[3]], args, nil
+}
+
+func intArg(args []interface{}, i int) int {
+	if i < len(args) {
+		if f, ok := args[i].(float64); ok {
+			return int(f)
+		}
+	}
+	return 0
+}
+
+func floatArg(args []interface{}, i int) *float64 {
+	if i < len(args) {
+		if f, ok := args[i].(float64); ok {
+			return &f
+		}
+	}
+	return nil
+}
+
+func boolArg(args []interface{}, i int) bool {
+	if i < len(args) {
+		if b, ok := args[i].(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
+func stringArg(args []interface{}, i int) string {
+	if i < len(args) {
+		if s, ok := args[i].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// numberFormat returns ff with decimals clamped to the supported range or nil for unsupported separator or negative styles.
+func numberFormat(ff *FieldFormat) *FieldFormat {
+	ff.Decimals = min(max(ff.Decimals, 0), 10)
+	if ff.validate() != nil {
+		// Format not supported.
+		return nil
+	}
+	return ff
+}
+
+func parseFormatScript(js string) (*FieldFormat, error) {
+	name, args, err := afCall(js)
+	if err != nil || name == "" {
+		return nil, err
+	}
+
+	switch name {
+
+	case "AFNumber_Format":
+		return numberFormat(&FieldFormat{
+			Type:           FieldFormatNumber,
+			Decimals:       intArg(args, 0),
+			SepStyle:       intArg(args, 1),
+			NegStyle:       intArg(args, 2),
+			Currency:       stringArg(args, 4),
+			AppendCurrency: len(args) > 5 && !boolArg(args, 5),
+		}), nil
+
+	case "AFPercent_Format":
+		return numberFormat(&FieldFormat{Type: FieldFormatPercent, Decimals: intArg(args, 0), SepStyle: intArg(args, 1)}), nil
+
+	case "AFDate_FormatEx":
+		ff := &FieldFormat{Type: FieldFormatDate, Date: stringArg(args, 0)}
+		if ff.dateFormat, err = DateFormatForFmtExt(ff.Date); err != nil {
+			// Date format not supported.
+			return nil, nil
+		}
+		return ff, nil
+
+	case "AFSpecial_Format":
+		if i := intArg(args, 0); i >= 0 && i < len(specialFieldFormats) {
+			return &FieldFormat{Type: specialFieldFormats[i]}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func parseRangeScript(js string) (*FieldRange, error) {
+	name, args, err := afCall(js)
+	if err != nil || name != "AFRange_Validate" {
+		return nil, err
+	}
+
+	fr := &FieldRange{}
+	if boolArg(args, 0) {
+		fr.Min = floatArg(args, 1)
+	}
+	if boolArg(args, 2) {
+		fr.Max = floatArg(args, 3)
+	}
+	if fr.Min == nil && fr.Max == nil {
+		return nil, nil
+	}
+
+	return fr, nil
+}
+
+func parseCalcScript(js string) (*FieldCalc, error) {
+	name, args, err := afCall(js)
+	if err != nil || name != "AFSimple_Calculate" {
+		return nil, err
+	}
+
+	fc := &FieldCalc{Op: strings.ToUpper(stringArg(args, 0))}
+	if !types.MemberOf(fc.Op, calcOps) || len(args) < 2 {
+		return nil, nil
+	}
+
+	switch a := args[1].(type) {
+	case []string:
+		fc.Fields = a
+	case string:
+		// Comma separated list of field names.
+		for _, s := range strings.Split(a, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				fc.Fields = append(fc.Fields, s)
+			}
+		}
+	}
+
+	if len(fc.Fields) == 0 {
+		return nil, nil
+	}
+
+	return fc, nil
+}
+
+func javaScript(xRefTable *model.XRefTable, d types.Dict, key string) (string, error) {
+	o, found := d.Find(key)
+	if !found {
+		return "", nil
+	}
+
+	d1, err := xRefTable.DereferenceDict(o)
+	if err != nil || d1 == nil {
+		return "", err
+	}
+
+	if s := d1.NameEntry("S"); s == nil || *s != "JavaScript" {
+		return "", nil
+	}
+
+	o, err = xRefTable.Dereference(d1["JS"])
+	if err != nil || o == nil {
+		return "", err
+	}
+
+	if sd, ok := o.(types.StreamDict); ok {
+		if err := sd.Decode(); err != nil {
+			return "", err
+		}
+		return string(sd.Content), nil
+	}
+
+	return model.Text(o)
+}
+
+// ParseFieldScripts returns the built-in format, validation and calculation scripts of the form field d.
+func ParseFieldScripts(xRefTable *model.XRefTable, d types.Dict) (*FieldScripts, error) {
+	o, found := d.Find("AA")
+	if !found {
+		return nil, nil
+	}
+
+	aa, err := xRefTable.DereferenceDict(o)
+	if err != nil || aa == nil {
+		return nil, err
+	}
+
+	fs := &FieldScripts{}
+
+	js, err := javaScript(xRefTable, aa, "F")
+	if err != nil {
+		return nil, err
+	}
+	if fs.Format, err = parseFormatScript(js); err != nil {
+		return nil, err
+	}
+
+	if js, err = javaScript(xRefTable, aa, "V"); err != nil {
+		return nil, err
+	}
+	if fs.Range, err = parseRangeScript(js); err != nil {
+		return nil, err
+	}
+
+	if js, err = javaScript(xRefTable, aa, "C"); err != nil {
+		return nil, err
+	}
+	if fs.Calc, err = parseCalcScript(js); err != nil {
+		return nil, err
+	}
+
+	if fs.Format == nil && fs.Range == nil && fs.Calc == nil {
+		return nil, nil
+	}
+
+	return fs, nil
+}
+
+// ParseNumber parses a numeric field value.
+// Errors returned refer to s and are meant to be qualified by the caller.
+func ParseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return f, nil
+	}
+
+	// Strip grouping and accept a decimal comma.
+	s = strings.NewReplacer(" ", "", "'", "").Replace(s)
+	if i, j := strings.LastIndex(s, ","), strings.LastIndex(s, "."); i > j {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	f, err = strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid number: %s", s)
+	}
+
+	return f, nil
+}
+
+// FormatNumber renders f like the Acrobat JavaScript calculation result.
+func FormatNumber(f float64) string {
+	f = math.Round(f*1e10) / 1e10
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func groupDigits(s, sep string) string {
+	if sep == "" || len(s) <= 3 {
+		return s
+	}
+	var sb strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+func (ff *FieldFormat) number(f float64) string {
+	s := strconv.FormatFloat(math.Abs(f), 'f', ff.Decimals, 64)
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	groupSep := []string{",", "", ".", "", "'"}[ff.SepStyle]
+	decSep := "."
+	if ff.SepStyle == 2 || ff.SepStyle == 3 {
+		decSep = ","
+	}
+
+	s = groupDigits(intPart, groupSep)
+	if fracPart != "" {
+		s += decSep + fracPart
+	}
+
+	return s
+}
+
+func (ff *FieldFormat) formatNumber(v string) (string, error) {
+	f, err := ParseNumber(v)
+	if err != nil {
+		return "", err
+	}
+
+	if ff.Type == FieldFormatPercent {
+		return ff.number(f*100) + "%", nil
+	}
+
+	s := ff.number(f)
+	if ff.Currency != "" {
+		if ff.AppendCurrency {
+			s += ff.Currency
+		} else {
+			s = ff.Currency + s
+		}
+	}
+
+	if f < 0 && strings.ContainsAny(s, "123456789") {
+		// Colors of negStyle 1 and 3 are not applied.
+		if ff.NegStyle >= 2 {
+			return "(" + s + ")", nil
+		}
+		return "-" + s, nil
+	}
+
+	return s, nil
+}
+
+func (ff *FieldFormat) formatDate(v string) (string, error) {
+	df := ff.dateFormat
+	if _, err := time.Parse(df.Int, v); err == nil {
+		return v, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.Format(df.Int), nil
+		}
+	}
+	return "", errors.Errorf("invalid date: %s (expected %s)", v, df.Ext)
+}
+
+func (ff *FieldFormat) formatSpecial(v string) (string, error) {
+	var sb strings.Builder
+	for _, r := range v {
+		if unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	d := sb.String()
+
+	switch {
+	case ff.Type == FieldFormatZip && len(d) == 5:
+		return d, nil
+	case ff.Type == FieldFormatZip4 && len(d) == 9:
+		return d[:5] + "-" + d[5:], nil
+	case ff.Type == FieldFormatPhone && len(d) == 10:
+		return "(" + d[:3] + ") " + d[3:6] + "-" + d[6:], nil
+	case ff.Type == FieldFormatPhone && len(d) == 7:
+		return d[:3] + "-" + d[3:], nil
+	case ff.Type == FieldFormatSSN && len(d) == 9:
+		return d[:3] + "-" + d[3:5] + "-" + d[5:], nil
+	}
+
+	return "", errors.Errorf("invalid %s: %s", ff.Type, v)
+}
+
+// Apply returns v formatted for display.
+// Errors returned refer to v and are meant to be qualified by the caller.
+func (ff *FieldFormat) Apply(v string) (string, error) {
+	if strings.TrimSpace(v) == "" {
+		return v, nil
+	}
+
+	switch ff.Type {
+	case FieldFormatNumber, FieldFormatPercent:
+		return ff.formatNumber(v)
+	case FieldFormatDate:
+		return ff.formatDate(v)
+	}
+
+	return ff.formatSpecial(v)
+}
+
+// Validate checks if the numeric value v is within range.
+// Errors returned refer to v and are meant to be qualified by the caller.
+func (fr *FieldRange) Validate(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+
+	f, err := ParseNumber(v)
+	if err != nil {
+		return err
+	}
+
+	if (fr.Min != nil && f < *fr.Min) || (fr.Max != nil && f > *fr.Max) {
+		return errors.Errorf("value %s out of range [%s, %s]", v, jsNumber(fr.Min), jsNumber(fr.Max))
+	}
+
+	return nil
+}
+
+// Calculate applies the calculation operation to ff.
+func (fc *FieldCalc) Calculate(ff []float64) float64 {
+	if len(ff) == 0 {
+		return 0
+	}
+
+	res := ff[0]
+	for _, f := range ff[1:] {
+		switch fc.Op {
+		case "SUM", "AVG":
+			res += f
+		case "PRD":
+			res *= f
+		case "MIN":
+			res = math.Min(res, f)
+		case "MAX":
+			res = math.Max(res, f)
+		}
+	}
+
+	if fc.Op == "AVG" {
+		res /= float64(len(ff))
+	}
+
+	return res
+}
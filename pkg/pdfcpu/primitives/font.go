@@ -37,6 +37,8 @@ type FormFont struct {
 	Color    string `json:"col"`
 	col      *color.SimpleColor
 	FillFont bool
+	Fallback []string // User fonts rendering characters missing in this font.
+	Kerning  bool     // Apply kerning and standard ligatures.
 }
 
 // ISO-639 country codes
@@ -101,6 +103,12 @@ func (f *FormFont) validate() error {
 		}
 	}
 
+	for _, fn := range f.Fallback {
+		if !font.IsUserFont(fn) {
+			return errors.Errorf("pdfcpu: fallback font %s is not an installed user font, please refer to \"pdfcpu fonts list\".\n", fn)
+		}
+	}
+
 	if f.Color != "" {
 		sc, err := f.pdf.parseColor(f.Color)
 		if err != nil {
@@ -128,6 +136,12 @@ func (f *FormFont) mergeIn(f0 *FormFont) {
 	if f.Script == "" {
 		f.Script = f0.Script
 	}
+	if f.Fallback == nil {
+		f.Fallback = f0.Fallback
+	}
+	if !f.Kerning {
+		f.Kerning = f0.Kerning
+	}
 }
 
 func (f *FormFont) SetCol(c color.SimpleColor) {
@@ -106,6 +106,8 @@ type PDF struct {
 	FileNames       map[string]string          `json:"files"`
 	TimestampFormat string                     `json:"timestamp"`
 	DateFormat      string                     `json:"dateFormat"`
+	Tagged          bool                       // generate a tagged PDF (marked content, structure tree)
+	Lang            string                     // natural language of the document eg. "en-US"
 	Conf            *model.Configuration       `json:"-"`
 	XRefTable       *model.XRefTable           `json:"-"`
 	Optimize        *model.OptimizationContext `json:"-"`
@@ -450,6 +452,10 @@ func (pdf *PDF) Validate() error {
 		pdf.DateFormat = pdf.Conf.DateFormat
 	}
 
+	if pdf.Tagged && pdf.Update() {
+		return errors.New("pdfcpu: tagging is supported for new PDF files only")
+	}
+
 	if len(pdf.Pages) == 0 {
 		return errors.New("pdfcpu: Please supply \"pages\"")
 	}
@@ -524,6 +530,30 @@ func (pdf *PDF) calcFont(f *FormFont) error {
 	if f.Script == "" {
 		f.Script = f0.Script
 	}
+	if f.Fallback == nil {
+		f.Fallback = f0.Fallback
+	}
+	if !f.Kerning {
+		f.Kerning = f0.Kerning
+	}
+	return nil
+}
+
+// setFallbackFonts registers the fallback fonts of f and the configured fallback fonts needed for rendering td.Text.
+func (pdf *PDF) setFallbackFonts(td *model.TextDescriptor, f *FormFont, pageFonts, globalFonts model.FontMap, pageNr int) error {
+	ff := append(append([]string{}, f.Fallback...), pdf.Conf.FallbackFonts...)
+	td.Fallback = font.UsedFallbacks(td.Text, td.FontName, ff...)
+	if len(td.Fallback) == 0 {
+		return nil
+	}
+	td.FallbackKeys = map[string]string{}
+	for _, fontName := range td.Fallback {
+		id, err := pdf.idForFontName(fontName, "", pageFonts, globalFonts, pageNr)
+		if err != nil {
+			return err
+		}
+		td.FallbackKeys[fontName] = id
+	}
 	return nil
 }
 
@@ -1048,7 +1078,10 @@ func (pdf *PDF) newModelPageforPDFPage(page *PDFPage) model.Page {
 		cropBox = page.cropBox
 	}
 
-	return model.NewPage(mediaBox, cropBox)
+	p := model.NewPage(mediaBox, cropBox)
+	p.Tagged = pdf.Tagged
+
+	return p
 }
 
 // RenderPages renders page content into model.Pages
@@ -1072,6 +1105,8 @@ func (pdf *PDF) RenderPages() ([]*model.Page, model.FontMap, error) {
 				continue
 			}
 
+			p.BeginArtifact()
+
 			// Create blank page with optional background color.
 			if pdf.bgCol != nil {
 				draw.FillRectNoBorder(p.Buf, p.CropBox, *pdf.bgCol)
@@ -1091,11 +1126,15 @@ func (pdf *PDF) RenderPages() ([]*model.Page, model.FontMap, error) {
 				}
 			}
 
+			p.EndArtifact()
+
 			pp = append(pp, &p)
 
 			continue
 		}
 
+		p.BeginArtifact()
+
 		pdf.renderPageBackground(page, p.Buf)
 
 		var headerHeight, headerDy float64
@@ -1119,6 +1158,8 @@ func (pdf *PDF) RenderPages() ([]*model.Page, model.FontMap, error) {
 			footerDy = float64(pdf.Footer.Dy)
 		}
 
+		p.EndArtifact()
+
 		// Render page content.
 		r := page.cropBox.CroppedCopy(0)
 		r.LL.Y += footerHeight + footerDy
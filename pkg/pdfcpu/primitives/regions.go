@@ -158,5 +158,5 @@ func (r *Regions) render(p *model.Page, pageNr int, fonts model.FontMap, images
 
 	}
 
-	return r.Divider.render(p)
+	return renderArtifact(p, func() error { return r.Divider.render(p) })
 }
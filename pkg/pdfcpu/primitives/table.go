@@ -509,6 +509,12 @@ func (t *Table) calcFont() error {
 		if f.Script == "" {
 			f.Script = f0.Script
 		}
+		if f.Fallback == nil {
+			f.Fallback = f0.Fallback
+		}
+		if !f.Kerning {
+			f.Kerning = f0.Kerning
+		}
 	}
 	if f.col == nil {
 		f.col = &color.Black
@@ -757,6 +763,7 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 	td.FontKey = id
 	td.FontSize = f.Size
 	td.RTL = t.RTL
+	td.Kerning = f.Kerning
 	td.StrokeCol = *f.col
 	td.FillCol = *f.col
 
@@ -767,6 +774,8 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 			break
 		}
 
+		p.BeginStructElem("TR", "")
+
 		for j := 0; j < t.Cols; j++ {
 
 			if len(t.Values[i]) < j+1 {
@@ -775,6 +784,8 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 
 			s := t.Values[i][j]
 			if len(strings.TrimSpace(s)) == 0 {
+				p.BeginStructElem("TD", "")
+				p.EndStructElem()
 				continue
 			}
 
@@ -787,6 +798,10 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 
 			colTd.Text, _ = format.Text(s, pdf.TimestampFormat, pageNr, pdf.pageCount())
 
+			if err := pdf.setFallbackFonts(&colTd, f, p.Fm, fonts, pageNr); err != nil {
+				return err
+			}
+
 			row := i
 			if t.Header != nil {
 				row++
@@ -795,7 +810,11 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 			x, y := ll(row, j)
 			r := types.RectForWidthAndHeight(x, y, colWidths[j], float64(t.LineHeight))
 
+			p.BeginStructElem("TD", "")
+			p.BeginMarkedContent()
 			bb := model.WriteMultiLineAnchored(pdf.XRefTable, p.Buf, r, nil, colTd, t.colAnchors[j])
+			p.EndMarkedContent()
+			p.EndStructElem()
 
 			if bb.Width() > colWidths[j] {
 				return errors.Errorf("pdfcpu: table cell width overflow - reduce padding or text: %s", colTd.Text)
@@ -805,6 +824,8 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 				return errors.Errorf("pdfcpu: table cell height overflow - reduce padding or text: %s", colTd.Text)
 			}
 		}
+
+		p.EndStructElem()
 	}
 	return nil
 }
@@ -832,6 +853,12 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 		if f1.col == nil {
 			f1.col = f0.col
 		}
+		if f1.Fallback == nil {
+			f1.Fallback = f0.Fallback
+		}
+		if !f1.Kerning {
+			f1.Kerning = f0.Kerning
+		}
 	}
 	if f1.col == nil {
 		f1.col = &color.Black
@@ -847,13 +874,19 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 	td.FontKey = id
 	td.FontSize = f1.Size
 	td.RTL = th.RTL
+	td.Kerning = f1.Kerning
 	td.StrokeCol = *f1.col
 	td.FillCol = *f1.col
 
+	p.BeginStructElem("TR", "")
+	defer p.EndStructElem()
+
 	// Render header values.
 	for i, s := range th.Values {
 
 		if len(strings.TrimSpace(s)) == 0 {
+			p.BeginStructElem("TH", "")
+			p.EndStructElem()
 			continue
 		}
 
@@ -861,6 +894,10 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 		th.calcColumnPadding(&colTd, i)
 		colTd.Text, _ = format.Text(s, pdf.TimestampFormat, pageNr, pdf.pageCount())
 
+		if err := pdf.setFallbackFonts(&colTd, &f1, p.Fm, fonts, pageNr); err != nil {
+			return err
+		}
+
 		x, y := ll(0, i)
 		r := types.RectForWidthAndHeight(x, y, colWidths[i], float64(t.LineHeight))
 
@@ -869,7 +906,11 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 			a = th.colAnchors[i]
 		}
 
+		p.BeginStructElem("TH", "")
+		p.BeginMarkedContent()
 		bb := model.WriteMultiLineAnchored(pdf.XRefTable, p.Buf, r, nil, colTd, a)
+		p.EndMarkedContent()
+		p.EndStructElem()
 
 		if bb.Width() > colWidths[i] {
 			return errors.Errorf("pdfcpu: table header cell width overflow - reduce padding or text: %s", colTd.Text)
@@ -903,6 +944,8 @@ func (t *Table) render(p *model.Page, pageNr int, fonts model.FontMap) error {
 
 	fmt.Fprintf(p.Buf, "q %.5f %.5f %.5f %.5f %.5f %.5f cm ", m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1])
 
+	p.BeginArtifact()
+
 	if t.bgCol != nil {
 		x, w := r.LL.X+bWidth/2, t.Width-2*bWidth
 		if bWidth == 0 {
@@ -927,6 +970,8 @@ func (t *Table) render(p *model.Page, pageNr int, fonts model.FontMap) error {
 		t.renderGrid(p, colWidths, bWidth, bCol, r)
 	}
 
+	p.EndArtifact()
+
 	td, err := t.prepareTextDescriptor()
 	if err != nil {
 		return err
@@ -941,20 +986,29 @@ func (t *Table) render(p *model.Page, pageNr int, fonts model.FontMap) error {
 		return r.LL.X + bWidth/2 + x, y
 	}
 
+	// Tagged content is in reading order, so the header row goes first.
+	if t.Header != nil && p.Tagged {
+		if err := t.renderHeader(p, pageNr, fonts, colWidths, td, ll); err != nil {
+			return err
+		}
+	}
+
 	if len(t.Values) > 0 {
 		if err := t.renderValues(p, pageNr, fonts, colWidths, td, ll); err != nil {
 			return err
 		}
 	}
 
-	if t.Header != nil {
+	if t.Header != nil && !p.Tagged {
 		if err := t.renderHeader(p, pageNr, fonts, colWidths, td, ll); err != nil {
 			return err
 		}
 	}
 
 	if t.pdf.Debug {
+		p.BeginArtifact()
 		draw.DrawCircle(p.Buf, r.LL.X, r.LL.Y, 5, color.Black, &color.Red)
+		p.EndArtifact()
 	}
 
 	fmt.Fprint(p.Buf, "Q ")
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package primitives
+
+import (
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Tagged PDF support: Content gets wrapped into marked content sequences
+// belonging to structure elements or into artifacts.
+// For untagged pages all of this is a no-op.
+
+// renderTagged calls f rendering content for a new structure element of type s.
+func renderTagged(p *model.Page, s, alt string, f func() error) error {
+	p.BeginStructElem(s, alt)
+	p.BeginMarkedContent()
+	err := f()
+	p.EndMarkedContent()
+	p.EndStructElem()
+	return err
+}
+
+// renderStructElem calls f rendering the kids of a new structure element of type s.
+func renderStructElem(p *model.Page, s string, f func() error) error {
+	p.BeginStructElem(s, "")
+	err := f()
+	p.EndStructElem()
+	return err
+}
+
+// renderArtifact calls f rendering content not being part of the logical structure eg. backgrounds, borders, headers and footers.
+func renderArtifact(p *model.Page, f func() error) error {
+	p.BeginArtifact()
+	err := f()
+	p.EndArtifact()
+	return err
+}
+
+func (pdf *PDF) ensureAnnotationIndRef(an *model.FieldAnnotation) ([]types.IndirectRef, error) {
+	if an.Kids != nil {
+		ii := make([]types.IndirectRef, 0, len(an.Kids))
+		for _, o := range an.Kids {
+			if ir, ok := o.(types.IndirectRef); ok {
+				ii = append(ii, ir)
+			}
+		}
+		return ii, nil
+	}
+
+	if an.IndRef == nil {
+		ir, err := pdf.XRefTable.IndRefForNewObject(an.Dict)
+		if err != nil {
+			return nil, err
+		}
+		an.IndRef = ir
+	}
+
+	return []types.IndirectRef{*an.IndRef}, nil
+}
+
+// renderFormField calls f rendering a form field as Form structure element referencing the widget annotations created.
+func (pdf *PDF) renderFormField(p *model.Page, f func() error) error {
+	if !p.Tagged {
+		return f()
+	}
+
+	i := len(p.Annots)
+	tabs := map[int]bool{}
+	for k := range p.AnnotTabs {
+		tabs[k] = true
+	}
+
+	se := p.BeginStructElem("Form", "")
+	p.BeginMarkedContent()
+	err := f()
+	p.EndMarkedContent()
+	p.EndStructElem()
+
+	if err != nil || se == nil {
+		return err
+	}
+
+	for ; i < len(p.Annots); i++ {
+		ii, err := pdf.ensureAnnotationIndRef(&p.Annots[i])
+		if err != nil {
+			return err
+		}
+		se.Objs = append(se.Objs, ii...)
+	}
+
+	var kk []int
+	for k := range p.AnnotTabs {
+		if !tabs[k] {
+			kk = append(kk, k)
+		}
+	}
+	sort.Ints(kk)
+
+	for _, k := range kk {
+		an := p.AnnotTabs[k]
+		ii, err := pdf.ensureAnnotationIndRef(&an)
+		if err != nil {
+			return err
+		}
+		p.AnnotTabs[k] = an
+		se.Objs = append(se.Objs, ii...)
+	}
+
+	return nil
+}
@@ -51,6 +51,7 @@ type TextBox struct {
 	horAlign        types.HAlignment
 	RTL             bool
 	Rotation        float64 `json:"rot"`
+	Role            string  // structure type for tagged PDF: P (default), H1-H6
 	Hide            bool
 }
 
@@ -128,6 +129,13 @@ func (tb *TextBox) validateHorAlign() error {
 	return nil
 }
 
+func (tb *TextBox) validateRole() error {
+	if tb.Role != "" && !types.MemberOf(tb.Role, []string{"P", "H1", "H2", "H3", "H4", "H5", "H6"}) {
+		return errors.Errorf("pdfcpu: invalid text role: %s, use one of P, H1-H6", tb.Role)
+	}
+	return nil
+}
+
 func (tb *TextBox) validate() error {
 
 	tb.x = tb.Position[0]
@@ -137,6 +145,10 @@ func (tb *TextBox) validate() error {
 		return errors.New("pdfcpu: invalid text reference $")
 	}
 
+	if err := tb.validateRole(); err != nil {
+		return err
+	}
+
 	if err := tb.validateAnchor(); err != nil {
 		return err
 	}
@@ -237,6 +249,10 @@ func (tb *TextBox) mergeIn(tb0 *TextBox) {
 		tb.Rotation = tb0.Rotation
 	}
 
+	if tb.Role == "" {
+		tb.Role = tb0.Role
+	}
+
 	if !tb.Hide {
 		tb.Hide = tb0.Hide
 	}
@@ -264,6 +280,12 @@ func (tb *TextBox) calcFont() error {
 		if f.Script == "" {
 			f.Script = f0.Script
 		}
+		if f.Fallback == nil {
+			f.Fallback = f0.Fallback
+		}
+		if !f.Kerning {
+			f.Kerning = f0.Kerning
+		}
 	}
 	if f.col == nil {
 		f.col = &color.Black
@@ -318,6 +340,11 @@ func (tb *TextBox) prepareTextDescriptor(p *model.Page, pageNr int, fonts model.
 		ScaleAbs: true,
 		Rotation: tb.Rotation,
 		RTL:      tb.RTL, // for user fonts only!
+		Kerning:  f.Kerning,
+	}
+
+	if err := pdf.setFallbackFonts(&td, f, p.Fm, fonts, pageNr); err != nil {
+		return nil, err
 	}
 
 	if col != nil {
@@ -57,6 +57,9 @@ type TextField struct {
 	HorAlign        types.HAlignment   `json:"-"`
 	MaxLen          int                `json:"maxlen"`
 	Comb            bool               `json:"comb"`
+	Format          *FieldFormat       // number, percent, date or special format
+	Range           *FieldRange        // numeric range validation
+	Calc            *FieldCalc         // calculated value
 	RTL             bool
 	Tab             int
 	Locked          bool
@@ -182,6 +185,33 @@ func (tf *TextField) validateTab() error {
 	return nil
 }
 
+func (tf *TextField) validateScripts() error {
+	if tf.Format != nil {
+		if err := tf.Format.validate(); err != nil {
+			return errors.Errorf("pdfcpu: field: %s %v", tf.ID, err)
+		}
+		if tf.Format.Type == FieldFormatDate {
+			// Date values are stored formatted.
+			v, err := tf.Format.Apply(tf.Value)
+			if err != nil {
+				return errors.Errorf("pdfcpu: field: %s %v", tf.ID, err)
+			}
+			tf.Value = v
+		}
+	}
+	if tf.Range != nil {
+		if err := tf.Range.validate(); err != nil {
+			return errors.Errorf("pdfcpu: field: %s %v", tf.ID, err)
+		}
+	}
+	if tf.Calc != nil {
+		if err := tf.Calc.validate(tf.ID); err != nil {
+			return errors.Errorf("pdfcpu: field: %s %v", tf.ID, err)
+		}
+	}
+	return nil
+}
+
 func (tf *TextField) validate() error {
 	if err := tf.validateID(); err != nil {
 		return err
@@ -223,6 +253,10 @@ func (tf *TextField) validate() error {
 		return err
 	}
 
+	if err := tf.validateScripts(); err != nil {
+		return err
+	}
+
 	return tf.validateTab()
 }
 
@@ -374,7 +408,6 @@ func (tf *TextField) renderLines(xRefTable *model.XRefTable, boWidth, lh, w, y f
 	for i := 0; i < len(lines); i++ {
 		s := lines[i]
 		lineBB := model.CalcBoundingBox(s, 0, 0, f.Name, f.Size)
-		s = model.PrepBytes(xRefTable, s, f.Name, !cjk, f.RTL(), f.FillFont)
 		x := 2 * boWidth
 		if x == 0 {
 			x = 2
@@ -394,6 +427,7 @@ func (tf *TextField) renderLines(xRefTable *model.XRefTable, boWidth, lh, w, y f
 		}
 
 		if tf.Comb && tf.MaxLen > 0 && tf.HorAlign == types.AlignLeft {
+			s = model.PrepBytes(xRefTable, s, f.Name, !cjk, f.RTL(), f.FillFont)
 			x = 0.5
 			dx := w / float64(tf.MaxLen)
 			y0 := y
@@ -404,7 +438,8 @@ func (tf *TextField) renderLines(xRefTable *model.XRefTable, boWidth, lh, w, y f
 			}
 			fmt.Fprint(buf, "ET ")
 		} else {
-			fmt.Fprintf(buf, "%.2f %.2f Td (%s) Tj ET ", x, y, s)
+			s = model.PrepText(xRefTable, s, f.Name, f.Size, !cjk, f.RTL(), f.FillFont)
+			fmt.Fprintf(buf, "%.2f %.2f Td %s ET ", x, y, s)
 		}
 
 		y -= lh
@@ -430,6 +465,14 @@ func (tf *TextField) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 		s = tf.Default
 	}
 
+	if tf.Format != nil {
+		s1, err := tf.Format.Apply(s)
+		if err != nil {
+			return nil, errors.Errorf("pdfcpu: field: %s %v", tf.ID, err)
+		}
+		s = s1
+	}
+
 	if font.IsCoreFont(f.Name) && utf8.ValidString(s) {
 		s = model.DecodeUTF8ToByte(s)
 	}
@@ -629,8 +672,22 @@ func (tf *TextField) prepareDict(fonts model.FontMap) (types.Dict, error) {
 		d["MaxLen"] = types.Integer(tf.MaxLen)
 	}
 
+	aa, err := FieldScripts{Format: tf.Format, Range: tf.Range, Calc: tf.Calc}.additionalActions()
+	if err != nil {
+		return nil, err
+	}
+	if aa != nil {
+		d["AA"] = aa
+	}
+
 	tf.handleBorderAndMK(d)
 
+	if tf.Range != nil {
+		if err := tf.Range.Validate(tf.Value); err != nil {
+			return nil, errors.Errorf("pdfcpu: field: %s %v", tf.ID, err)
+		}
+	}
+
 	if tf.Value != "" {
 		if tf.MaxLen > 0 && len(tf.Value) > tf.MaxLen {
 			return nil, errors.Errorf("pdfcpu: field overflow at %s, maxLen = %d", tf.ID, tf.MaxLen)
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf16"
 
 	"github.com/pdfcpu/pdfcpu/pkg/filter"
@@ -40,7 +41,12 @@ import (
 	"github.com/pkg/errors"
 )
 
-const stampWithBBox = false
+const (
+	stampWithBBox = false
+
+	// stampPieceInfoKey identifies the page-piece data pdfcpu attaches to the forms of its watermarks and stamps.
+	stampPieceInfoKey = "pdfcpu"
+)
 
 var (
 	errNoWatermark        = errors.New("pdfcpu: no watermarks found")
@@ -102,6 +108,8 @@ var wmParamMap = watermarkParamMap{
 	"diagonal":        parseDiagonal,
 	"fillcolor":       parseFillColor,
 	"fontname":        parseFontName,
+	"kerning":         parseKerning,
+	"layer":           parseLayer,
 	"scriptname":      parseScriptName,
 	"margins":         parseMargins,
 	"mode":            parseRenderMode,
@@ -212,6 +220,15 @@ func parseURL(s string, wm *model.Watermark) error {
 	return nil
 }
 
+func parseLayer(s string, wm *model.Watermark) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return errors.New("pdfcpu: missing layer name")
+	}
+	wm.Layer = s
+	return nil
+}
+
 func parseFontSize(s string, wm *model.Watermark) error {
 	fs, err := strconv.Atoi(s)
 	if err != nil {
@@ -277,6 +294,19 @@ func parseRightToLeft(s string, wm *model.Watermark) error {
 	return nil
 }
 
+func parseKerning(s string, wm *model.Watermark) error {
+	switch strings.ToLower(s) {
+	case "on", "true", "t":
+		wm.Kerning = true
+	case "off", "false", "f":
+		wm.Kerning = false
+	default:
+		return errors.New("pdfcpu: kerning, please provide one of: on/off true/false t/f")
+	}
+
+	return nil
+}
+
 func parseStrokeColor(s string, wm *model.Watermark) error {
 	c, err := color.ParseColor(s)
 	if err != nil {
@@ -556,7 +586,7 @@ func setImageWatermark(s string, wm *model.Watermark) error {
 		return nil
 	}
 	if !model.ImageFileName(s) {
-		return errors.New("imageFileName has to have one of these extensions: .jpg, .jpeg, .png, .tif, .tiff, .webp")
+		return errors.New("imageFileName has to have one of these extensions: .jpg, .jpeg, .jp2, .j2k, .jpx, .png, .tif, .tiff, .webp, .bmp, .gif")
 	}
 	wm.FileName = s
 	f, err := os.Open(wm.FileName)
@@ -750,14 +780,49 @@ func createImageResForWM(ctx *model.Context, wm *model.Watermark) (err error) {
 	return err
 }
 
-func createFontResForWM(ctx *model.Context, wm *model.Watermark) (err error) {
-	// TODO Reuse font dict.
-	if font.IsUserFont(wm.FontName) {
+// setFallbackFontsForWM determines the configured fallback fonts needed for rendering wm.
+func setFallbackFontsForWM(ctx *model.Context, wm *model.Watermark) {
+	wm.Fallback = font.UsedFallbacks(wm.TextString, wm.FontName, ctx.Configuration.FallbackFonts...)
+	wm.FallbackFonts = make([]*types.IndirectRef, len(wm.Fallback))
+}
+
+// prerenderWM registers the glyphs of user fonts used by wm.
+func prerenderWM(ctx *model.Context, wm *model.Watermark) {
+	if font.IsUserFont(wm.FontName) || len(wm.Fallback) > 0 {
 		td, _ := setupTextDescriptor(*wm, "", 123456789, 0)
 		model.WriteMultiLine(ctx.XRefTable, new(bytes.Buffer), types.RectForFormat("A4"), nil, td)
 	}
+}
+
+// setFontResForWM assigns the font dict ir of fontName to text watermark wm.
+func setFontResForWM(wm *model.Watermark, fontName string, ir *types.IndirectRef) {
+	if !wm.IsText() {
+		return
+	}
+	if wm.FontName == fontName {
+		wm.Font = ir
+	}
+	for i, fn := range wm.Fallback {
+		if fn == fontName {
+			wm.FallbackFonts[i] = ir
+		}
+	}
+}
+
+func createFontResForWM(ctx *model.Context, wm *model.Watermark) (err error) {
+	// TODO Reuse font dict.
+	setFallbackFontsForWM(ctx, wm)
+	prerenderWM(ctx, wm)
 	wm.Font, err = pdffont.EnsureFontDict(ctx.XRefTable, wm.FontName, "", wm.ScriptName, false, nil)
-	return err
+	if err != nil {
+		return err
+	}
+	for i, fontName := range wm.Fallback {
+		if wm.FallbackFonts[i], err = pdffont.EnsureFontDict(ctx.XRefTable, fontName, "", "", false, nil); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func createResourcesForWM(ctx *model.Context, wm *model.Watermark) error {
@@ -796,7 +861,38 @@ func ensureOCG(ctx *model.Context, onTop bool) (*types.IndirectRef, error) {
 	return ctx.IndRefForNewObject(d)
 }
 
-func prepareOCPropertiesInRoot(ctx *model.Context, onTop bool) (*types.IndirectRef, error) {
+// ensureWatermarkLayer returns the named layer for watermarks and creates it if necessary.
+// Only a layer created here gets marked as containing watermarks, existing user layers stay untouched.
+func ensureWatermarkLayer(ctx *model.Context, name string, onTop bool) (*types.IndirectRef, error) {
+	ir, created, err := ensureLayer(ctx, name)
+	if err != nil || !created {
+		return ir, err
+	}
+
+	d, err := ctx.DereferenceDict(*ir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark the layer as containing pagination artifacts.
+	subt := "BG"
+	if onTop {
+		subt = "FG"
+	}
+	d["Usage"] = types.Dict(
+		map[string]types.Object{
+			"PageElement": types.Dict(map[string]types.Object{"Subtype": types.Name(subt)}),
+		},
+	)
+
+	return ir, nil
+}
+
+func prepareOCPropertiesInRoot(ctx *model.Context, onTop bool, layer string) (*types.IndirectRef, error) {
+	if layer != "" {
+		return ensureWatermarkLayer(ctx, layer, onTop)
+	}
+
 	rootDict, err := ctx.Catalog()
 	if err != nil {
 		return nil, err
@@ -888,9 +984,16 @@ func createFormResDict(ctx *model.Context, pageNr int, wm *model.Watermark) (*ty
 		return ctx.IndRefForNewObject(d)
 	}
 
+	fontResDict := types.Dict(map[string]types.Object{"F1": *wm.Font})
+	for i, ir := range wm.FallbackFonts {
+		if ir != nil {
+			fontResDict[fallbackFontID(i)] = *ir
+		}
+	}
+
 	d := types.Dict(
 		map[string]types.Object{
-			"Font":    types.Dict(map[string]types.Object{"F1": *wm.Font}),
+			"Font":    fontResDict,
 			"ProcSet": types.NewNameArray("PDF", "Text", "ImageB", "ImageC", "ImageI"),
 		},
 	)
@@ -943,6 +1046,11 @@ func formContent(w io.Writer, pageNr int, wm model.Watermark) error {
 	return nil
 }
 
+// fallbackFontID returns the resource id of the i-th fallback font of a text watermark.
+func fallbackFontID(i int) string {
+	return "F" + strconv.Itoa(i+2)
+}
+
 func setupTextDescriptor(wm model.Watermark, timestampFormat string, pageNr, pageCount int) (model.TextDescriptor, bool) {
 	// Set horizontal alignment.
 	var hAlign types.HAlignment
@@ -959,8 +1067,18 @@ func setupTextDescriptor(wm model.Watermark, timestampFormat string, pageNr, pag
 	td, unique := textDescriptor(wm, timestampFormat, pageNr, pageCount)
 	td.X, td.Y, td.HAlign, td.VAlign, td.FontKey = x, y, hAlign, vAlign, "F1"
 
-	// Set right to left rendering.
+	// Set right to left rendering and kerning.
 	td.RTL = wm.RTL
+	td.Kerning = wm.Kerning
+
+	// Set fallback fonts.
+	if len(wm.Fallback) > 0 {
+		td.Fallback = wm.Fallback
+		td.FallbackKeys = map[string]string{}
+		for i, fontName := range wm.Fallback {
+			td.FallbackKeys[fontName] = fallbackFontID(i)
+		}
+	}
 
 	td.Embed = wm.ScriptName == ""
 
@@ -1057,6 +1175,16 @@ func createForm(ctx *model.Context, pageNr, pageCount int, wm *model.Watermark,
 				"BBox":    bbox.Array(),
 				"Matrix":  types.NewNumberArray(1, 0, 0, 1, 0, 0),
 				"OC":      *wm.Ocg,
+				"PieceInfo": types.Dict(
+					map[string]types.Object{
+						stampPieceInfoKey: types.Dict(
+							map[string]types.Object{
+								"LastModified": types.StringLiteral(types.DateString(time.Now())),
+								"Private":      types.Name("Watermark"),
+							},
+						),
+					},
+				),
 			},
 		),
 		Content:        b.Bytes(),
@@ -1459,16 +1587,16 @@ func createResourcesForPageNr(
 
 	// Text watermark
 
-	if font.IsUserFont(wm.FontName) {
-		td, _ := setupTextDescriptor(*wm, "", 123456789, 0)
-		model.WriteMultiLine(ctx.XRefTable, new(bytes.Buffer), types.RectForFormat("A4"), nil, td)
-	}
+	setFallbackFontsForWM(ctx, wm)
+	prerenderWM(ctx, wm)
 
-	pageSet, found := fm[wm.FontName]
-	if !found {
-		fm[wm.FontName] = types.IntSet{pageNr: true}
-	} else {
-		pageSet[pageNr] = true
+	for _, fontName := range append([]string{wm.FontName}, wm.Fallback...) {
+		pageSet, found := fm[fontName]
+		if !found {
+			fm[fontName] = types.IntSet{pageNr: true}
+		} else {
+			pageSet[pageNr] = true
+		}
 	}
 
 	return nil
@@ -1515,14 +1643,16 @@ func AddWatermarksMap(ctx *model.Context, m map[int]*model.Watermark) error {
 	var (
 		onTop   bool
 		opacity float64
+		layer   string
 	)
 	for _, wm := range m {
 		onTop = wm.OnTop
 		opacity = wm.Opacity
+		layer = wm.Layer
 		break
 	}
 
-	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, onTop)
+	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, onTop, layer)
 	if err != nil {
 		return err
 	}
@@ -1547,10 +1677,7 @@ func AddWatermarksMap(ctx *model.Context, m map[int]*model.Watermark) error {
 			if !v {
 				continue
 			}
-			wm := m[pageNr]
-			if wm.IsText() && wm.FontName == fontName {
-				m[pageNr].Font = ir
-			}
+			setFontResForWM(m[pageNr], fontName, ir)
 		}
 	}
 
@@ -1576,9 +1703,7 @@ func resolveFonts(fm map[string]types.IntSet, xRefTable *model.XRefTable, m1 map
 				continue
 			}
 			for _, wm := range m1[pageNr] {
-				if wm.IsText() && wm.FontName == fontName {
-					wm.Font = ir
-				}
+				setFontResForWM(wm, fontName, ir)
 			}
 		}
 	}
@@ -1590,6 +1715,7 @@ func AddWatermarksSliceMap(ctx *model.Context, m map[int][]*model.Watermark) err
 	var (
 		onTop   bool
 		opacity float64
+		layer   string
 	)
 	m1 := map[int][]*model.Watermark{}
 	gotParms := false
@@ -1601,6 +1727,7 @@ func AddWatermarksSliceMap(ctx *model.Context, m map[int][]*model.Watermark) err
 		if !gotParms {
 			onTop = wms[0].OnTop
 			opacity = wms[0].Opacity
+			layer = wms[0].Layer
 			gotParms = true
 		}
 	}
@@ -1609,7 +1736,7 @@ func AddWatermarksSliceMap(ctx *model.Context, m map[int][]*model.Watermark) err
 		errors.Errorf("pdfcpu: no watermarks available")
 	}
 
-	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, onTop)
+	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, onTop, layer)
 	if err != nil {
 		return err
 	}
@@ -1646,7 +1773,7 @@ func AddWatermarks(ctx *model.Context, selectedPages types.IntSet, wm *model.Wat
 		log.Debug.Printf("AddWatermarks wm:\n%s\n", wm)
 	}
 	var err error
-	if wm.Ocg, err = prepareOCPropertiesInRoot(ctx, wm.OnTop); err != nil {
+	if wm.Ocg, err = prepareOCPropertiesInRoot(ctx, wm.OnTop, wm.Layer); err != nil {
 		return err
 	}
 
@@ -1707,7 +1834,7 @@ func removeForms(ctx *model.Context, d types.Dict, ids []string, i int) error {
 	return removeResDictEntry(ctx, d, "XObject", ids, i)
 }
 
-func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string, forms []string, err error) {
+func removeArtifacts(sd *types.StreamDict, i int, stamped func(form string) (bool, error)) (ok bool, extGStates []string, forms []string, err error) {
 	err = sd.Decode()
 	if err == filter.ErrUnsupportedFilter {
 		if log.InfoEnabled() {
@@ -1723,12 +1850,13 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 
 	// Watermarks may begin or end the content stream.
 
-	for {
+	for off := 0; ; {
 		s := string(sd.Content)
-		beg := strings.Index(s, "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC")
+		beg := strings.Index(s[off:], "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC")
 		if beg < 0 {
 			break
 		}
+		beg += off
 
 		end := strings.Index(s[beg:], "EMC")
 		if end < 0 {
@@ -1738,12 +1866,14 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 		// Check for usage of resources.
 		t := s[beg : beg+end]
 
+		var extGState, form string
+
 		i := strings.Index(t, "/GS")
 		if i > 0 {
 			j := i + 3
 			k := strings.Index(t[j:], " gs")
 			if k > 0 {
-				extGStates = append(extGStates, "GS"+t[j:j+k])
+				extGState = "GS" + t[j:j+k]
 			}
 		}
 
@@ -1752,10 +1882,29 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 			j := i + 3
 			k := strings.Index(t[j:], " Do")
 			if k > 0 {
-				forms = append(forms, "Fm"+t[j:j+k])
+				form = "Fm" + t[j:j+k]
 			}
 		}
 
+		// Leave alone any pagination artifacts not created by pdfcpu.
+		if form == "" {
+			off = beg + end + 3
+			continue
+		}
+		ok, err := stamped(form)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		if !ok {
+			off = beg + end + 3
+			continue
+		}
+
+		if extGState != "" {
+			extGStates = append(extGStates, extGState)
+		}
+		forms = append(forms, form)
+
 		// TODO Remove whitespace until 0x0a
 		sd.Content = append(sd.Content[:beg], sd.Content[beg+end+3:]...)
 		patched = true
@@ -1768,10 +1917,63 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 	return patched, extGStates, forms, err
 }
 
+// stampPieceInfo returns true if the form XObject dict d carries the page-piece data pdfcpu writes for watermarks and stamps.
+func stampPieceInfo(d types.Dict) bool {
+	pi := d.DictEntry("PieceInfo")
+	if pi == nil || pi.Len() != 1 {
+		return false
+	}
+	_, found := pi.Find(stampPieceInfoKey)
+	return found
+}
+
+// stampForm returns true if the form XObject id of resDict renders a watermark or stamp created by pdfcpu.
+func stampForm(ctx *model.Context, resDict types.Dict, id string) (bool, error) {
+	o, found := resDict.Find("XObject")
+	if !found {
+		return false, nil
+	}
+
+	d, err := ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return false, err
+	}
+
+	o, found = d.Find(id)
+	if !found {
+		return false, nil
+	}
+
+	sd, _, err := ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return false, err
+	}
+
+	if stampPieceInfo(sd.Dict) {
+		return true, nil
+	}
+
+	// Forms created by earlier versions lack the marker but use the default watermark layers.
+	o, found = sd.Find("OC")
+	if !found {
+		return false, nil
+	}
+
+	ocg, err := ctx.DereferenceDict(o)
+	if err != nil || ocg == nil {
+		return false, err
+	}
+
+	return stampOCG(ocg), nil
+}
+
 func removeArtifactsFromPage(ctx *model.Context, sd *types.StreamDict, resDict types.Dict, i int) (bool, error) {
 	// Remove watermark artifacts and locate id's
 	// of used extGStates and forms.
-	ok, extGStates, forms, err := removeArtifacts(sd, i)
+	stamped := func(form string) (bool, error) {
+		return stampForm(ctx, resDict, form)
+	}
+	ok, extGStates, forms, err := removeArtifacts(sd, i, stamped)
 	if err != nil {
 		return false, err
 	}
@@ -1945,35 +2147,14 @@ func locateOCGs(ctx *model.Context) (types.Array, error) {
 	return ctx.DereferenceArray(o)
 }
 
-func detectStampOCG(ctx *model.Context, arr types.Array) error {
-	for _, o := range arr {
-
-		d, err := ctx.DereferenceDict(o)
-		if err != nil {
-			return err
-		}
-
-		if o == nil {
-			continue
-		}
-
-		if *d.Type() != "OCG" {
-			continue
-		}
-
-		n := d.StringEntry("Name")
-		if n == nil {
-			continue
-		}
-
-		if *n != "Background" && *n != "Watermark" {
-			continue
-		}
-
-		return nil
+// stampOCG returns true if d is one of the default OCGs pdfcpu creates for watermarks or stamps.
+func stampOCG(d types.Dict) bool {
+	if t := d.Type(); t == nil || *t != "OCG" {
+		return false
 	}
 
-	return errNoWatermark
+	n := d.StringEntry("Name")
+	return n != nil && (*n == "Background" || *n == "Watermark")
 }
 
 func removePageWatermarks(ctx *model.Context, selectedPages types.IntSet) error {
@@ -2008,15 +2189,12 @@ func RemoveWatermarks(ctx *model.Context, selectedPages types.IntSet) error {
 		log.Debug.Printf("RemoveWatermarks\n")
 	}
 
-	arr, err := locateOCGs(ctx)
-	if err != nil {
-		return err
-	}
-
-	if err := detectStampOCG(ctx, arr); err != nil {
+	// Watermarks always live on some layer.
+	if _, err := locateOCGs(ctx); err != nil {
 		return err
 	}
 
+	// Only content marked as created by pdfcpu gets removed, see stampForm.
 	return removePageWatermarks(ctx, selectedPages)
 }
 
@@ -2180,16 +2358,7 @@ func DetectWatermarks(ctx *model.Context) error {
 			continue
 		}
 
-		if *d.Type() != "OCG" {
-			continue
-		}
-
-		n := d.StringEntry("Name")
-		if n == nil {
-			continue
-		}
-
-		if *n != "Background" && *n != "Watermark" {
+		if !stampOCG(d) {
 			continue
 		}
 
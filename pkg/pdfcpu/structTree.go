@@ -0,0 +1,528 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// StructureTree represents the logical structure of a tagged PDF.
+type StructureTree struct {
+	Header  Header            `json:"header"`
+	RoleMap map[string]string `json:"roleMap,omitempty"`
+	Kids    []*StructNode     `json:"kids,omitempty"`
+}
+
+// StructAttributes represents an attribute object of a structure element.
+type StructAttributes struct {
+	Owner   string            `json:"owner,omitempty"`
+	Entries map[string]string `json:"entries,omitempty"`
+}
+
+// StructNode represents a structure element.
+// ID is the object number of the structure element dict and is used to identify the element on import.
+type StructNode struct {
+	ID         int                `json:"id"`
+	Role       string             `json:"role"`
+	StdRole    string             `json:"stdRole,omitempty"` // role after applying the role map
+	Title      string             `json:"title,omitempty"`
+	Alt        string             `json:"alt,omitempty"`
+	ActualText string             `json:"actualText,omitempty"`
+	Lang       string             `json:"lang,omitempty"`
+	Attributes []StructAttributes `json:"attributes,omitempty"`
+	Classes    []string           `json:"classes,omitempty"`
+	PageNr     int                `json:"page,omitempty"`
+	MCIDs      []int              `json:"mcids,omitempty"`
+	ObjRefs    []int              `json:"objRefs,omitempty"` // object numbers of referenced annotations or XObjects
+	Kids       []*StructNode      `json:"kids,omitempty"`
+}
+
+type structTreeWalker struct {
+	ctx     *model.Context
+	roleMap types.Dict
+	visited map[int]bool
+	elems   map[int]types.IndirectRef // structure elements reachable from the structure tree root
+}
+
+func structRoot(ctx *model.Context) (types.Dict, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := rootDict.Find("StructTreeRoot")
+	if !found {
+		return nil, nil
+	}
+
+	return ctx.DereferenceDict(o)
+}
+
+func (w *structTreeWalker) text(d types.Dict, key string) (string, error) {
+	o, found := d.Find(key)
+	if !found {
+		return "", nil
+	}
+
+	o, err := w.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return "", err
+	}
+
+	s, err := model.Text(o)
+	if err != nil {
+		if w.ctx.XRefTable.ValidationMode == model.ValidationStrict {
+			return "", err
+		}
+		return "", nil
+	}
+
+	return s, nil
+}
+
+func (w *structTreeWalker) stdRole(s string) string {
+	for i := 0; w.roleMap != nil && i < 10; i++ {
+		n := w.roleMap.NameEntry(s)
+		if n == nil || *n == s {
+			break
+		}
+		s = *n
+	}
+	return s
+}
+
+func (w *structTreeWalker) pageNr(o types.Object) (int, error) {
+	ir, ok := o.(types.IndirectRef)
+	if !ok {
+		return 0, nil
+	}
+	return w.ctx.PageNumber(ir.ObjectNumber.Value())
+}
+
+func (w *structTreeWalker) objectString(o types.Object) (string, error) {
+	o, err := w.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return "", err
+	}
+	switch o := o.(type) {
+	case types.StringLiteral, types.HexLiteral:
+		if s, err := model.Text(o); err == nil {
+			return s, nil
+		}
+	case types.Name:
+		return o.Value(), nil
+	}
+	return o.PDFString(), nil
+}
+
+func (w *structTreeWalker) attributes(o types.Object) ([]StructAttributes, error) {
+	o, err := w.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return nil, err
+	}
+
+	var aa []StructAttributes
+
+	addDict := func(o types.Object) error {
+		d, err := w.ctx.DereferenceDict(o)
+		if err != nil || d == nil {
+			return err
+		}
+		sa := StructAttributes{Entries: map[string]string{}}
+		for k, v := range d {
+			if k == "O" {
+				if n, ok := v.(types.Name); ok {
+					sa.Owner = n.Value()
+				}
+				continue
+			}
+			s, err := w.objectString(v)
+			if err != nil {
+				return err
+			}
+			sa.Entries[k] = s
+		}
+		aa = append(aa, sa)
+		return nil
+	}
+
+	a, ok := o.(types.Array)
+	if !ok {
+		return aa, addDict(o)
+	}
+
+	// Attribute objects may be followed by revision numbers.
+	for _, o := range a {
+		if _, ok := o.(types.Integer); ok {
+			continue
+		}
+		if err := addDict(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return aa, nil
+}
+
+func (w *structTreeWalker) classes(o types.Object) ([]string, error) {
+	o, err := w.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return nil, err
+	}
+
+	var ss []string
+
+	switch o := o.(type) {
+	case types.Name:
+		ss = append(ss, o.Value())
+	case types.Array:
+		for _, o := range o {
+			if n, ok := o.(types.Name); ok {
+				ss = append(ss, n.Value())
+			}
+		}
+	}
+
+	return ss, nil
+}
+
+func (w *structTreeWalker) processKid(sn *StructNode, o types.Object) error {
+	if i, ok := o.(types.Integer); ok {
+		sn.MCIDs = append(sn.MCIDs, i.Value())
+		return nil
+	}
+
+	ir, isIndRef := o.(types.IndirectRef)
+
+	d, err := w.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return err
+	}
+
+	if t := d.Type(); (t == nil || *t == "StructElem") && d.NameEntry("S") != nil {
+		objNr := 0
+		if isIndRef {
+			objNr = ir.ObjectNumber.Value()
+			w.elems[objNr] = ir
+		}
+		kid, err := w.structNode(d, objNr)
+		if err != nil || kid == nil {
+			return err
+		}
+		sn.Kids = append(sn.Kids, kid)
+		return nil
+	}
+
+	if mcid := d.IntEntry("MCID"); mcid != nil {
+		// Marked-content reference
+		sn.MCIDs = append(sn.MCIDs, *mcid)
+		if sn.PageNr == 0 {
+			if sn.PageNr, err = w.pageNr(d["Pg"]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if ir := d.IndirectRefEntry("Obj"); ir != nil {
+		// Object reference
+		sn.ObjRefs = append(sn.ObjRefs, ir.ObjectNumber.Value())
+	}
+
+	return nil
+}
+
+func (w *structTreeWalker) processKids(sn *StructNode, o types.Object) error {
+	if o == nil {
+		return nil
+	}
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		o1, err := w.ctx.Dereference(ir)
+		if err != nil {
+			return err
+		}
+		if a, ok := o1.(types.Array); ok {
+			o = a
+		}
+	}
+
+	a, ok := o.(types.Array)
+	if !ok {
+		return w.processKid(sn, o)
+	}
+
+	for _, o := range a {
+		if err := w.processKid(sn, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *structTreeWalker) structNode(d types.Dict, objNr int) (*StructNode, error) {
+	if objNr > 0 {
+		if w.visited[objNr] {
+			return nil, nil
+		}
+		w.visited[objNr] = true
+	}
+
+	s := d.NameEntry("S")
+	if s == nil {
+		return nil, errors.Errorf("pdfcpu: structure element %d: missing entry \"S\"", objNr)
+	}
+
+	sn := &StructNode{ID: objNr, Role: *s}
+	if std := w.stdRole(*s); std != *s {
+		sn.StdRole = std
+	}
+
+	var err error
+
+	for k, p := range map[string]*string{"T": &sn.Title, "Alt": &sn.Alt, "ActualText": &sn.ActualText, "Lang": &sn.Lang} {
+		if *p, err = w.text(d, k); err != nil {
+			return nil, err
+		}
+	}
+
+	if sn.Attributes, err = w.attributes(d["A"]); err != nil {
+		return nil, err
+	}
+
+	if sn.Classes, err = w.classes(d["C"]); err != nil {
+		return nil, err
+	}
+
+	if sn.PageNr, err = w.pageNr(d["Pg"]); err != nil {
+		return nil, err
+	}
+
+	if err := w.processKids(sn, d["K"]); err != nil {
+		return nil, err
+	}
+
+	return sn, nil
+}
+
+func readStructureTree(ctx *model.Context, rootDict types.Dict, source string) (*StructureTree, *structTreeWalker, error) {
+	w := &structTreeWalker{ctx: ctx, visited: map[int]bool{}, elems: map[int]types.IndirectRef{}}
+
+	st := &StructureTree{Header: header(ctx.XRefTable, source)}
+
+	if o, found := rootDict.Find("RoleMap"); found {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(d) > 0 {
+			w.roleMap = d
+			st.RoleMap = map[string]string{}
+			for k, v := range d {
+				if n, ok := v.(types.Name); ok {
+					st.RoleMap[k] = n.Value()
+				}
+			}
+		}
+	}
+
+	root := &StructNode{}
+	if err := w.processKids(root, rootDict["K"]); err != nil {
+		return nil, nil, err
+	}
+	st.Kids = root.Kids
+
+	return st, w, nil
+}
+
+// ReadStructureTree returns the structure tree of ctx or nil if ctx is not tagged.
+func ReadStructureTree(ctx *model.Context, source string) (*StructureTree, error) {
+	rootDict, err := structRoot(ctx)
+	if err != nil || rootDict == nil {
+		return nil, err
+	}
+
+	st, _, err := readStructureTree(ctx, rootDict, source)
+
+	return st, err
+}
+
+// ExportStructureTreeJSON writes the structure tree of ctx as JSON to w.
+func ExportStructureTreeJSON(ctx *model.Context, source string, w io.Writer) (bool, error) {
+	st, err := ReadStructureTree(ctx, source)
+	if err != nil || st == nil {
+		return false, err
+	}
+
+	bb, err := json.MarshalIndent(st, "", "\t")
+	if err != nil {
+		return false, err
+	}
+
+	_, err = w.Write(bb)
+
+	return true, err
+}
+
+func parseStructureTreeFromJSON(bb []byte) (*StructureTree, error) {
+
+	if !json.Valid(bb) {
+		return nil, errors.Errorf("pdfcpu: invalid JSON encoding detected.")
+	}
+
+	st := &StructureTree{}
+
+	if err := json.Unmarshal(bb, st); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+func updateStructElemText(d types.Dict, key, s string) error {
+	if s == "" {
+		d.Delete(key)
+		return nil
+	}
+
+	if key == "Lang" {
+		s1, err := types.Escape(s)
+		if err != nil {
+			return err
+		}
+		d[key] = types.StringLiteral(*s1)
+		return nil
+	}
+
+	s1, err := types.EscapedUTF16String(s)
+	if err != nil {
+		return err
+	}
+	d[key] = types.StringLiteral(*s1)
+
+	return nil
+}
+
+// updateStructElem updates the structure element identified by sn.ID.
+// elems holds all structure elements reachable from the structure tree root.
+func updateStructElem(ctx *model.Context, elems map[int]types.IndirectRef, sn *StructNode) error {
+	if sn.ID > 0 {
+		ir, ok := elems[sn.ID]
+		if !ok {
+			return errors.Errorf("pdfcpu: invalid structure element id: %d", sn.ID)
+		}
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil {
+			return err
+		}
+		if d == nil || d.NameEntry("S") == nil {
+			return errors.Errorf("pdfcpu: invalid structure element id: %d", sn.ID)
+		}
+		// Type is optional for structure elements.
+		if t := d.Type(); t != nil && *t != "StructElem" {
+			return errors.Errorf("pdfcpu: invalid structure element id: %d", sn.ID)
+		}
+
+		if sn.Role == "" {
+			return errors.Errorf("pdfcpu: structure element %d: missing role", sn.ID)
+		}
+		d["S"] = types.Name(sn.Role)
+
+		for k, v := range map[string]string{"T": sn.Title, "Alt": sn.Alt, "ActualText": sn.ActualText, "Lang": sn.Lang} {
+			if err := updateStructElemText(d, k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, kid := range sn.Kids {
+		if err := updateStructElem(ctx, elems, kid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func updateRoleMap(rootDict types.Dict, m map[string]string) {
+	if m == nil {
+		// Leave the role map untouched.
+		return
+	}
+
+	if len(m) == 0 {
+		rootDict.Delete("RoleMap")
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	d := types.Dict{}
+	for _, k := range keys {
+		d[k] = types.Name(m[k])
+	}
+
+	rootDict["RoleMap"] = d
+}
+
+// ImportStructureTree applies the structure tree provided by rd to ctx.
+// Structure elements are identified by their id.
+// Roles, titles, alternate descriptions, replacement texts, languages and the role map (if present) get updated.
+// Attributes, classes, page references and content items are read only.
+func ImportStructureTree(ctx *model.Context, rd io.Reader) (bool, error) {
+	rootDict, err := structRoot(ctx)
+	if err != nil || rootDict == nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rd); err != nil {
+		return false, err
+	}
+
+	st, err := parseStructureTreeFromJSON(buf.Bytes())
+	if err != nil {
+		return false, err
+	}
+
+	_, w, err := readStructureTree(ctx, rootDict, "")
+	if err != nil {
+		return false, err
+	}
+
+	for _, sn := range st.Kids {
+		if err := updateStructElem(ctx, w.elems, sn); err != nil {
+			return true, err
+		}
+	}
+
+	updateRoleMap(rootDict, st.RoleMap)
+
+	return true, nil
+}
@@ -332,7 +332,8 @@ func (sd *StreamDict) decodeLength(maxLen int64) ([]byte, error) {
 	// Apply each filter in the pipeline to result of preceding filter.
 	for idx, f := range sd.FilterPipeline {
 
-		if f.Name == filter.JPX {
+		if f.Name == filter.JPX || f.Name == filter.JBIG2 {
+			// Image decoding happens during image processing.
 			break
 		}
 
@@ -408,8 +409,8 @@ func (sd *StreamDict) DecodeLength(maxLen int64) ([]byte, error) {
 
 	fpl := sd.FilterPipeline
 
-	// No filter or sole filter DTC && !CMYK or JPX - nothing to decode.
-	if fpl == nil || len(fpl) == 1 && ((fpl[0].Name == filter.DCT && sd.CSComponents != 4) || fpl[0].Name == filter.JPX) {
+	// No filter or sole filter DTC && !CMYK or JPX or JBIG2 - nothing to decode.
+	if fpl == nil || len(fpl) == 1 && ((fpl[0].Name == filter.DCT && sd.CSComponents != 4) || fpl[0].Name == filter.JPX || fpl[0].Name == filter.JBIG2) {
 		sd.Content = sd.Raw
 		//fmt.Printf("decodedStream returning %d(#%02x)bytes: \n%s\n", len(sd.Content), len(sd.Content), hex.Dump(sd.Content))
 		if maxLen < 0 {
@@ -0,0 +1,1180 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Topics used for PDF/A conformance reports.
+const (
+	topicActions        = "Actions"
+	topicAnnotations    = "Annotations"
+	topicColor          = "OutputIntents"
+	topicEmbeddedFiles  = "EmbeddedFiles"
+	topicEncryption     = "Encryption"
+	topicExternal       = "ExternalContent"
+	topicFileStructure  = "FileStructure"
+	topicFilters        = "Filters"
+	topicFonts          = "Fonts"
+	topicForms          = "Forms"
+	topicGraphicsState  = "GraphicsState"
+	topicImages         = "Images"
+	topicMetadata       = "Metadata"
+	topicOptContent     = "OptionalContent"
+	topicTransparency   = "Transparency"
+	topicContentStreams = "ContentStreams"
+)
+
+var actionTypes = []string{
+	"GoTo", "GoToR", "GoToE", "GoToDp", "Launch", "Thread", "URI", "Sound", "Movie", "Hide", "Named",
+	"SubmitForm", "ResetForm", "ImportData", "JavaScript", "SetOCGState", "Rendition", "Trans",
+	"GoTo3DView", "RichMediaExecute",
+}
+
+var annotationTypes = []string{
+	"Text", "Link", "FreeText", "Line", "Square", "Circle", "Polygon", "PolyLine", "Highlight",
+	"Underline", "Squiggly", "StrikeOut", "Stamp", "Caret", "Ink", "Popup", "FileAttachment",
+	"Sound", "Movie", "Widget", "Screen", "PrinterMark", "TrapNet", "Watermark", "3D", "Redact",
+	"RichMedia", "Projection",
+}
+
+type pdfaChecker struct {
+	ctx          *model.Context
+	xRefTable    *model.XRefTable
+	conf         model.Conformance
+	r            *model.ConformanceReport
+	visited      types.IntSet
+	pageNr       int
+	objNr        int
+	deviceRGB    bool
+	deviceCMYK   bool
+	deviceGray   bool
+	transparency bool
+}
+
+func (c *pdfaChecker) add(topic, format string, args ...interface{}) {
+	c.r.Add(topic, c.pageNr, c.objNr, format, args...)
+}
+
+func (c *pdfaChecker) part() int {
+	return c.conf.Part()
+}
+
+// PDFA checks ctx for conformance with ctx.Conformance (ISO 19005 part 1, 2 or 3, level B or U)
+// and returns a report of all violations found.
+// ctx is expected to be validated against ISO 32000.
+func PDFA(ctx *model.Context) (*model.ConformanceReport, error) {
+	if !ctx.Conformance.PDFA() {
+		return nil, errors.New("pdfcpu: PDFA: missing PDF/A conformance level")
+	}
+
+	if log.ValidateEnabled() {
+		log.Validate.Printf("*** PDFA %s begin ***\n", ctx.Conformance)
+	}
+
+	c := &pdfaChecker{
+		ctx:       ctx,
+		xRefTable: ctx.XRefTable,
+		conf:      ctx.Conformance,
+		r:         &model.ConformanceReport{Conformance: ctx.Conformance},
+		visited:   types.IntSet{},
+	}
+
+	rootDict, err := c.xRefTable.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	c.checkFileStructure()
+
+	if err := c.checkMetadata(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkPages(); err != nil {
+		return nil, err
+	}
+
+	c.pageNr, c.objNr = 0, 0
+	if err := c.checkCatalog(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.walk(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkOutputIntents(rootDict); err != nil {
+		return nil, err
+	}
+
+	if log.ValidateEnabled() {
+		log.Validate.Printf("*** PDFA %s end ***\n", ctx.Conformance)
+	}
+
+	return c.r, nil
+}
+
+func (c *pdfaChecker) checkFileStructure() {
+	xRefTable := c.xRefTable
+
+	if xRefTable.Encrypt != nil {
+		c.add(topicEncryption, "encryption is forbidden")
+	}
+
+	if len(xRefTable.ID) == 0 {
+		c.add(topicFileStructure, "missing file identifier (ID) in trailer")
+	}
+
+	if c.part() == 1 {
+		if c.ctx.Read.UsingObjectStreams {
+			c.add(topicFileStructure, "object streams are forbidden")
+		}
+		if c.ctx.Read.UsingXRefStreams {
+			c.add(topicFileStructure, "xref streams are forbidden")
+		}
+	}
+
+	if c.part() > 1 && xRefTable.Version() > model.V17 {
+		c.add(topicFileStructure, "PDF version %s exceeds 1.7", xRefTable.VersionString())
+	}
+}
+
+func (c *pdfaChecker) infoText(d types.Dict, key string) (string, bool, error) {
+	o, found := d.Find(key)
+	if !found || o == nil {
+		return "", false, nil
+	}
+	s, err := c.xRefTable.DereferenceText(o)
+	if err != nil {
+		return "", false, err
+	}
+	return s, true, nil
+}
+
+func (c *pdfaChecker) checkInfoDictSync(d types.Dict, xmp *model.XMPMeta) error {
+	desc := xmp.RDF.Description
+
+	for _, e := range []struct {
+		key  string
+		vals []string
+		prop string
+	}{
+		{"Title", desc.Title.Alt.Entries, "dc:title"},
+		{"Author", desc.Author.Seq.Entries, "dc:creator"},
+		{"Subject", desc.Subject.Alt.Entries, "dc:description"},
+		{"Creator", []string{desc.Creator}, "xmp:CreatorTool"},
+		{"Producer", []string{desc.Producer}, "pdf:Producer"},
+		{"Keywords", []string{desc.Keywords}, "pdf:Keywords"},
+	} {
+		s, found, err := c.infoText(d, e.key)
+		if err != nil {
+			return err
+		}
+		if !found || s == "" {
+			continue
+		}
+		if !types.MemberOf(s, e.vals) && strings.Join(e.vals, ", ") != s {
+			c.add(topicMetadata, "info dict entry %s not in sync with XMP %s", e.key, e.prop)
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkMetadata(rootDict types.Dict) error {
+	o, found := rootDict.Find("Metadata")
+	if !found {
+		c.add(topicMetadata, "missing XMP metadata stream in catalog")
+		return nil
+	}
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		c.objNr = ir.ObjectNumber.Value()
+	}
+	defer func() { c.objNr = 0 }()
+
+	sd, _, err := c.xRefTable.DereferenceStreamDict(o)
+	if err != nil {
+		return err
+	}
+	if sd == nil {
+		c.add(topicMetadata, "missing XMP metadata stream in catalog")
+		return nil
+	}
+
+	if _, found := sd.Find("Filter"); found && c.part() == 1 {
+		c.add(topicMetadata, "metadata stream shall not be filtered")
+	}
+
+	if err := sd.Decode(); err != nil {
+		if err == filter.ErrUnsupportedFilter {
+			c.add(topicMetadata, "metadata stream uses an unsupported filter")
+			return nil
+		}
+		return err
+	}
+
+	id, err := model.ParsePDFAID(sd.Content)
+	if err != nil {
+		c.add(topicMetadata, "malformed XMP metadata: %v", err)
+		return nil
+	}
+
+	if id == nil {
+		c.add(topicMetadata, "missing PDF/A identification (pdfaid) in XMP metadata")
+	} else {
+		if id.Part != c.part() {
+			c.add(topicMetadata, "pdfaid:part %d does not match PDF/A-%d", id.Part, c.part())
+		}
+		if id.Conformance != c.conf.Level() {
+			// Level A also satisfies levels B and U, level U also satisfies level B.
+			ok := id.Conformance == "A" || (id.Conformance == "U" && c.conf.Level() == "B")
+			if !ok {
+				c.add(topicMetadata, "pdfaid:conformance %s does not match level %s", id.Conformance, c.conf.Level())
+			}
+		}
+	}
+
+	if c.xRefTable.Info == nil || c.xRefTable.CatalogXMPMeta == nil {
+		return nil
+	}
+
+	d, err := c.xRefTable.DereferenceDict(*c.xRefTable.Info)
+	if err != nil || d == nil {
+		return err
+	}
+
+	return c.checkInfoDictSync(d, c.xRefTable.CatalogXMPMeta)
+}
+
+func (c *pdfaChecker) checkPages() error {
+	for i := 1; i <= c.xRefTable.PageCount; i++ {
+		d, indRef, _, err := c.xRefTable.PageDict(i, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		c.pageNr, c.objNr = i, 0
+		if indRef != nil {
+			c.objNr = indRef.ObjectNumber.Value()
+			c.visited[c.objNr] = true
+		}
+
+		if err := c.checkPage(d); err != nil {
+			return err
+		}
+
+		if err := c.walk(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *pdfaChecker) checkPage(d types.Dict) error {
+	if _, found := d.Find("AA"); found {
+		c.add(topicActions, "page dict shall not contain AA")
+	}
+
+	if _, found := d.Find("PresSteps"); found {
+		c.add(topicActions, "page dict shall not contain PresSteps")
+	}
+
+	bb, err := c.xRefTable.PageContent(d, c.pageNr)
+	if err != nil {
+		if err == model.ErrNoContent {
+			return nil
+		}
+		if err == filter.ErrUnsupportedFilter {
+			c.add(topicFilters, "page content uses an unsupported filter")
+			return nil
+		}
+		return err
+	}
+
+	c.checkContent(bb)
+
+	return nil
+}
+
+func (c *pdfaChecker) checkContent(bb []byte) {
+	err := model.ParseContentOps(bb, func(op model.ContentOp) error {
+		switch op.Name {
+		case "rg", "RG":
+			c.deviceRGB = true
+		case "k", "K":
+			c.deviceCMYK = true
+		case "g", "G":
+			c.deviceGray = true
+		case "cs", "CS":
+			if len(op.Operands) > 0 {
+				c.recordColorSpaceName(strings.TrimPrefix(op.Operands[len(op.Operands)-1], "/"))
+			}
+		case "PS":
+			c.add(topicContentStreams, "PostScript operator PS is forbidden")
+		case "BI":
+			c.checkInlineImage(op.Operands)
+		}
+		return nil
+	})
+	if err != nil {
+		c.add(topicContentStreams, "malformed content stream: %v", err)
+	}
+}
+
+func (c *pdfaChecker) recordColorSpaceName(s string) {
+	switch s {
+	case "DeviceRGB", "RGB":
+		c.deviceRGB = true
+	case "DeviceCMYK", "CMYK":
+		c.deviceCMYK = true
+	case "DeviceGray", "G":
+		c.deviceGray = true
+	}
+}
+
+// inlineImageValue returns the tokens making up the inline image dict value at dict[i] along with the index of the next key.
+// Array values are returned without brackets.
+func inlineImageValue(dict []string, i int) ([]string, int) {
+	if i >= len(dict) {
+		return nil, i
+	}
+	if dict[i] != "[" {
+		return dict[i : i+1], i + 1
+	}
+	depth := 0
+	for j := i; j < len(dict); j++ {
+		switch dict[j] {
+		case "[":
+			depth++
+		case "]":
+			depth--
+			if depth == 0 {
+				return dict[i+1 : j], j + 1
+			}
+		}
+	}
+	return dict[i+1:], len(dict)
+}
+
+func (c *pdfaChecker) checkInlineImage(dict []string) {
+	for i := 0; i < len(dict); {
+		k := dict[i]
+		var vv []string
+		vv, i = inlineImageValue(dict, i+1)
+		switch k {
+		case "/CS", "/ColorSpace":
+			for _, v := range vv {
+				c.recordColorSpaceName(strings.TrimPrefix(v, "/"))
+			}
+		case "/F", "/Filter":
+			for _, v := range vv {
+				if v == "/LZW" || v == "/LZWDecode" {
+					c.add(topicFilters, "inline image: LZWDecode is forbidden")
+					break
+				}
+			}
+		case "/I", "/Interpolate":
+			if len(vv) == 1 && vv[0] == "true" {
+				c.add(topicImages, "inline image: Interpolate shall be false")
+			}
+		}
+	}
+}
+
+func (c *pdfaChecker) recordColorSpace(o types.Object) error {
+	o, err := c.xRefTable.Dereference(o)
+	if err != nil {
+		return err
+	}
+	switch o := o.(type) {
+	case types.Name:
+		c.recordColorSpaceName(o.Value())
+	case types.Array:
+		// Check the base or alternate color space eg. [/Indexed /DeviceRGB ...], [/Separation /Spot /DeviceCMYK ...]
+		for _, o1 := range o {
+			if _, ok := o1.(types.Name); ok {
+				if err := c.recordColorSpace(o1); err != nil {
+					return err
+				}
+			}
+		}
+	case types.Dict:
+		// Color space resource dict
+		for k, v := range o {
+			if types.MemberOf(k, []string{"DefaultRGB", "DefaultCMYK", "DefaultGray"}) {
+				continue
+			}
+			if err := c.recordColorSpace(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walk traverses the object graph starting at o and checks every dict and stream dict reached.
+func (c *pdfaChecker) walk(o types.Object) error {
+	objNr := c.objNr
+	defer func() { c.objNr = objNr }()
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		nr := ir.ObjectNumber.Value()
+		if c.visited[nr] {
+			return nil
+		}
+		o1, err := c.xRefTable.Dereference(ir)
+		if err != nil {
+			return err
+		}
+		if d, ok := o1.(types.Dict); ok && d.Type() != nil && *d.Type() == "Page" {
+			// Pages reached via destinations or page tree nodes get walked on their own.
+			return nil
+		}
+		c.visited[nr] = true
+		c.objNr = nr
+		o = o1
+	}
+
+	switch o := o.(type) {
+
+	case types.Dict:
+		if err := c.checkDict(o); err != nil {
+			return err
+		}
+		return c.walkDict(o)
+
+	case types.StreamDict:
+		if err := c.checkStreamDict(&o); err != nil {
+			return err
+		}
+		return c.walkDict(o.Dict)
+
+	case types.Array:
+		for _, o1 := range o {
+			if err := c.walk(o1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) walkDict(d types.Dict) error {
+	for k, v := range d {
+		// Don't leave the current page via back pointers.
+		if k == "Parent" || k == "P" || k == "Pg" {
+			continue
+		}
+		if k == "ColorSpace" || k == "CS" {
+			if err := c.recordColorSpace(v); err != nil {
+				return err
+			}
+		}
+		if err := c.walk(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *pdfaChecker) checkDict(d types.Dict) error {
+	t := d.Type()
+	st := d.Subtype()
+
+	if t != nil {
+		switch *t {
+		case "Action":
+			c.checkAction(d)
+			return nil
+		case "Annot":
+			return c.checkAnnotation(d)
+		case "Font":
+			return c.checkFont(d)
+		case "ExtGState":
+			c.checkExtGState(d)
+			return nil
+		case "Filespec":
+			return c.checkFileSpec(d)
+		}
+	}
+
+	if s := d.NameEntry("S"); s != nil && t == nil && types.MemberOf(*s, actionTypes) {
+		c.checkAction(d)
+		return nil
+	}
+
+	if st != nil && t == nil && types.MemberOf(*st, annotationTypes) {
+		if _, ok := d.Find("Rect"); ok {
+			return c.checkAnnotation(d)
+		}
+	}
+
+	if _, ok := d.Find("EF"); ok && t == nil {
+		return c.checkFileSpec(d)
+	}
+
+	if _, ok := d.Find("FT"); ok {
+		// Form field
+		if _, found := d.Find("AA"); found {
+			c.add(topicForms, "form field shall not contain AA")
+		}
+	}
+
+	if s := d.NameEntry("S"); s != nil && *s == "Transparency" {
+		c.transparency = true
+		if c.part() == 1 {
+			c.add(topicTransparency, "transparency groups are forbidden")
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkAction(d types.Dict) {
+	s := d.NameEntry("S")
+	if s == nil {
+		return
+	}
+
+	if types.MemberOf(*s, model.PDFAForbiddenActions) {
+		c.add(topicActions, "%s action is forbidden", *s)
+		return
+	}
+
+	if *s == "Named" {
+		n := d.NameEntry("N")
+		if n == nil || !types.MemberOf(*n, model.PDFAPermittedNamedActions) {
+			name := ""
+			if n != nil {
+				name = *n
+			}
+			c.add(topicActions, "named action %s is forbidden", name)
+		}
+	}
+}
+
+func (c *pdfaChecker) checkAnnotationFlags(d types.Dict, subtype string) {
+	if subtype == "Popup" {
+		return
+	}
+
+	var f model.AnnotationFlags
+	if i := d.IntEntry("F"); i != nil {
+		f = model.AnnotationFlags(*i)
+	}
+
+	if f&model.AnnPrint == 0 {
+		c.add(topicAnnotations, "%s annotation: print flag not set", subtype)
+	}
+
+	if f&(model.AnnInvisible|model.AnnHidden|model.AnnNoView|model.AnnToggleNoView) > 0 {
+		c.add(topicAnnotations, "%s annotation: Invisible, Hidden, NoView and ToggleNoView flags shall not be set", subtype)
+	}
+}
+
+func (c *pdfaChecker) annotationWithZeroSize(d types.Dict) bool {
+	a, err := c.xRefTable.DereferenceArray(d["Rect"])
+	if err != nil || len(a) != 4 {
+		return false
+	}
+	r, err := c.xRefTable.RectForArray(a)
+	if err != nil || r == nil {
+		return false
+	}
+	return r.Width() == 0 && r.Height() == 0
+}
+
+func (c *pdfaChecker) checkAnnotationAppearance(d types.Dict, subtype string) error {
+	if subtype == "Popup" || subtype == "Link" || c.annotationWithZeroSize(d) {
+		return nil
+	}
+
+	o, found := d.Find("AP")
+	if !found {
+		if c.part() > 1 {
+			c.add(topicAnnotations, "%s annotation: missing appearance dict", subtype)
+		}
+		return nil
+	}
+
+	ap, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || ap == nil {
+		return err
+	}
+
+	if _, ok := ap.Find("N"); !ok {
+		c.add(topicAnnotations, "%s annotation: missing normal appearance", subtype)
+	}
+
+	for k := range ap {
+		if k != "N" {
+			c.add(topicAnnotations, "%s annotation: appearance dict shall only contain N", subtype)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkAnnotation(d types.Dict) error {
+	subtype := ""
+	if st := d.Subtype(); st != nil {
+		subtype = *st
+	}
+
+	if c.conf.AnnotationForbidden(subtype) {
+		c.add(topicAnnotations, "%s annotation is forbidden", subtype)
+		return nil
+	}
+
+	c.checkAnnotationFlags(d, subtype)
+
+	if _, found := d.Find("AA"); found {
+		c.add(topicAnnotations, "%s annotation: AA is forbidden", subtype)
+	}
+
+	if c.part() == 1 {
+		if f, found := d.Find("CA"); found {
+			if ca, err := c.xRefTable.DereferenceNumber(f); err == nil && ca != 1 {
+				c.add(topicTransparency, "%s annotation: CA shall be 1.0", subtype)
+			}
+		}
+	}
+
+	return c.checkAnnotationAppearance(d, subtype)
+}
+
+// findingFunc records a conformance violation.
+type findingFunc func(topic, format string, args ...interface{})
+
+func fontName(d types.Dict) string {
+	if s := d.NameEntry("BaseFont"); s != nil {
+		return *s
+	}
+	return "unknown"
+}
+
+// checkFontEmbedding reports the font d of object objNr unless its font program is embedded
+// and returns its font descriptor.
+// Font checks are shared by PDF/A and PDF/UA.
+func checkFontEmbedding(xRefTable *model.XRefTable, d types.Dict, objNr int, add findingFunc) (types.Dict, error) {
+	fd, err := font.FontDescriptor(xRefTable, d, objNr)
+	if err != nil {
+		add(topicFonts, "%s: corrupt font dict: %v", fontName(d), err)
+		return nil, nil
+	}
+
+	if fd == nil {
+		add(topicFonts, "%s: font not embedded (no font descriptor)", fontName(d))
+		return nil, nil
+	}
+
+	embedded, err := font.Embedded(xRefTable, d, objNr)
+	if err != nil {
+		return nil, err
+	}
+	if !embedded {
+		add(topicFonts, "%s: font not embedded", fontName(d))
+	}
+
+	return fd, nil
+}
+
+// checkFontUnicode reports the font d with font descriptor fd unless its glyphs map to Unicode.
+func checkFontUnicode(d, fd types.Dict, add findingFunc) {
+	if _, found := d.Find("ToUnicode"); found {
+		return
+	}
+
+	if st := d.Subtype(); st != nil && *st != "Type0" && simpleFontWithUnicodeEncoding(d, fd) {
+		return
+	}
+
+	add(topicFonts, "%s: missing Unicode mapping (ToUnicode)", fontName(d))
+}
+
+func (c *pdfaChecker) checkFontCIDSet(d, fd types.Dict) {
+	// ISO 19005-1 6.3.5: Font subsets of CIDFonts shall contain a CIDSet.
+	if c.part() > 1 || fd == nil {
+		return
+	}
+	fn := fontName(d)
+	if len(fn) > 7 && fn[6] == '+' {
+		if _, found := fd.Find("CIDSet"); !found {
+			c.add(topicFonts, "%s: font subset without CIDSet", fn)
+		}
+	}
+}
+
+// simpleFontWithUnicodeEncoding returns true if the glyphs of a simple font map to Unicode via a standard encoding.
+func simpleFontWithUnicodeEncoding(d, fd types.Dict) bool {
+	// Symbolic fonts require a ToUnicode CMap.
+	if fd != nil {
+		if f := fd.IntEntry("Flags"); f != nil && *f&0x04 > 0 {
+			return false
+		}
+	}
+	enc := d.NameEntry("Encoding")
+	if enc != nil {
+		return types.MemberOf(*enc, []string{"WinAnsiEncoding", "MacRomanEncoding", "MacExpertEncoding", "StandardEncoding"})
+	}
+	_, found := d.Find("Encoding")
+	return found
+}
+
+func (c *pdfaChecker) checkFont(d types.Dict) error {
+	st := d.Subtype()
+	if st == nil {
+		return nil
+	}
+	subtype := *st
+
+	if subtype == "CIDFontType0" || subtype == "CIDFontType2" {
+		// Checked via the parent Type0 font.
+		return nil
+	}
+
+	if subtype == "Type3" {
+		return nil
+	}
+
+	fd, err := checkFontEmbedding(c.xRefTable, d, c.objNr, c.add)
+	if err != nil {
+		return err
+	}
+
+	if subtype == "Type0" {
+		c.checkFontCIDSet(d, fd)
+	}
+
+	if c.conf.Level() == "U" {
+		checkFontUnicode(d, fd, c.add)
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkExtGState(d types.Dict) {
+	if _, found := d.Find("TR"); found {
+		c.add(topicGraphicsState, "extGState: TR is forbidden")
+	}
+
+	if o, found := d.Find("TR2"); found {
+		if n, ok := o.(types.Name); !ok || n.Value() != "Default" {
+			c.add(topicGraphicsState, "extGState: TR2 other than Default is forbidden")
+		}
+	}
+
+	if _, found := d.Find("HTO"); found && c.part() > 1 {
+		c.add(topicGraphicsState, "extGState: HTO is forbidden")
+	}
+
+	transparent := false
+
+	if o, found := d.Find("SMask"); found {
+		if n, ok := o.(types.Name); !ok || n.Value() != "None" {
+			transparent = true
+		}
+	}
+
+	for _, k := range []string{"CA", "ca"} {
+		if o, found := d.Find(k); found {
+			if f, err := c.xRefTable.DereferenceNumber(o); err == nil && f != 1 {
+				transparent = true
+			}
+		}
+	}
+
+	if bm := d.NameEntry("BM"); bm != nil && *bm != "Normal" && *bm != "Compatible" {
+		transparent = true
+	}
+
+	if transparent {
+		c.transparency = true
+		if c.part() == 1 {
+			c.add(topicTransparency, "extGState: transparency (SMask, CA, ca, BM) is forbidden")
+		}
+	}
+}
+
+func (c *pdfaChecker) checkEmbeddedFile(d types.Dict) error {
+	o, found := d.Find("EF")
+	if !found {
+		return nil
+	}
+
+	ef, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || ef == nil {
+		return err
+	}
+
+	for _, k := range []string{"F", "UF"} {
+		o, found := ef.Find(k)
+		if !found {
+			continue
+		}
+		sd, _, err := c.xRefTable.DereferenceStreamDict(o)
+		if err != nil || sd == nil {
+			return err
+		}
+
+		if c.part() == 3 {
+			if sd.Subtype() == nil {
+				c.add(topicEmbeddedFiles, "embedded file stream: missing Subtype (MIME type)")
+			}
+			if _, found := sd.Find("Params"); !found {
+				c.add(topicEmbeddedFiles, "embedded file stream: missing Params")
+			}
+			continue
+		}
+
+		// PDF/A-2: Embedded files shall be PDF/A files themselves.
+		if err := sd.Decode(); err != nil {
+			if err == filter.ErrUnsupportedFilter {
+				c.add(topicEmbeddedFiles, "embedded file stream uses an unsupported filter")
+				continue
+			}
+			return err
+		}
+		if !strings.HasPrefix(string(sd.Content), "%PDF-") {
+			c.add(topicEmbeddedFiles, "embedded file is not a PDF file")
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkFileSpec(d types.Dict) error {
+	if _, found := d.Find("EF"); !found {
+		return nil
+	}
+
+	if c.part() == 1 {
+		c.add(topicEmbeddedFiles, "embedded files are forbidden")
+		return nil
+	}
+
+	if c.part() == 3 {
+		if _, found := d.Find("F"); !found {
+			c.add(topicEmbeddedFiles, "file specification: missing F")
+		}
+		if _, found := d.Find("UF"); !found {
+			c.add(topicEmbeddedFiles, "file specification: missing UF")
+		}
+		if _, found := d.Find("AFRelationship"); !found {
+			c.add(topicEmbeddedFiles, "file specification: missing AFRelationship")
+		}
+	}
+
+	return c.checkEmbeddedFile(d)
+}
+
+func (c *pdfaChecker) checkStreamFilters(sd *types.StreamDict) {
+	for _, f := range sd.FilterPipeline {
+		switch f.Name {
+		case filter.LZW:
+			c.add(topicFilters, "LZWDecode is forbidden")
+		case filter.JPX:
+			if c.part() == 1 {
+				c.add(topicFilters, "JPXDecode is forbidden")
+			}
+		case "Crypt":
+			if n := f.DecodeParms.NameEntry("Name"); n == nil || *n != "Identity" {
+				c.add(topicFilters, "Crypt filters other than Identity are forbidden")
+			}
+		}
+	}
+
+	for _, k := range []string{"F", "FFilter", "FDecodeParms"} {
+		if _, found := sd.Find(k); found {
+			c.add(topicExternal, "stream dict shall not contain %s", k)
+		}
+	}
+}
+
+func (c *pdfaChecker) checkImage(sd *types.StreamDict) {
+	if _, found := sd.Find("Alternates"); found {
+		c.add(topicImages, "image: Alternates is forbidden")
+	}
+
+	if _, found := sd.Find("OPI"); found {
+		c.add(topicImages, "image: OPI is forbidden")
+	}
+
+	if b := sd.BooleanEntry("Interpolate"); b != nil && *b {
+		c.add(topicImages, "image: Interpolate shall be false")
+	}
+
+	if _, found := sd.Find("SMask"); found {
+		c.transparency = true
+		if c.part() == 1 {
+			c.add(topicTransparency, "image: SMask is forbidden")
+		}
+	}
+}
+
+func (c *pdfaChecker) checkFormXObject(sd *types.StreamDict) error {
+	for _, k := range []string{"OPI", "Ref", "PS"} {
+		if _, found := sd.Find(k); found {
+			c.add(topicExternal, "form XObject: %s is forbidden", k)
+		}
+	}
+
+	if st := sd.NameEntry("Subtype2"); st != nil && *st == "PS" {
+		c.add(topicExternal, "form XObject: Subtype2 PS is forbidden")
+	}
+
+	if err := sd.Decode(); err != nil {
+		if err == filter.ErrUnsupportedFilter {
+			return nil
+		}
+		return err
+	}
+
+	c.checkContent(sd.Content)
+
+	return nil
+}
+
+func (c *pdfaChecker) checkStreamDict(sd *types.StreamDict) error {
+	c.checkStreamFilters(sd)
+
+	if t := sd.Type(); t != nil && *t == "XObject" || sd.Subtype() != nil {
+		st := sd.Subtype()
+		if st == nil {
+			return nil
+		}
+		switch *st {
+		case "Image":
+			c.checkImage(sd)
+		case "Form":
+			if err := c.checkFormXObject(sd); err != nil {
+				return err
+			}
+		case "PS":
+			c.add(topicExternal, "PostScript XObjects are forbidden")
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkOCProperties(rootDict types.Dict) error {
+	o, found := rootDict.Find("OCProperties")
+	if !found {
+		return nil
+	}
+
+	if c.part() == 1 {
+		c.add(topicOptContent, "optional content is forbidden")
+		return nil
+	}
+
+	d, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return err
+	}
+
+	var configs types.Array
+	if o, found := d.Find("D"); found {
+		configs = append(configs, o)
+	}
+	if o, found := d.Find("Configs"); found {
+		a, err := c.xRefTable.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+		configs = append(configs, a...)
+	}
+
+	for _, o := range configs {
+		d1, err := c.xRefTable.DereferenceDict(o)
+		if err != nil || d1 == nil {
+			return err
+		}
+		if _, found := d1.Find("Name"); !found {
+			c.add(topicOptContent, "optional content configuration: missing Name")
+		}
+		if _, found := d1.Find("AS"); found {
+			c.add(topicOptContent, "optional content configuration: AS is forbidden")
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkAcroForm(rootDict types.Dict) error {
+	o, found := rootDict.Find("AcroForm")
+	if !found {
+		return nil
+	}
+
+	d, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return err
+	}
+
+	if b := d.BooleanEntry("NeedAppearances"); b != nil && *b {
+		c.add(topicForms, "NeedAppearances shall be false")
+	}
+
+	if _, found := d.Find("XFA"); found {
+		c.add(topicForms, "XFA forms are forbidden")
+	}
+
+	return nil
+}
+
+func (c *pdfaChecker) checkCatalog(rootDict types.Dict) error {
+	if _, found := rootDict.Find("AA"); found {
+		c.add(topicActions, "catalog shall not contain AA")
+	}
+
+	if o, found := rootDict.Find("Names"); found {
+		d, err := c.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if _, found := d.Find("JavaScript"); found {
+			c.add(topicActions, "JavaScript name tree is forbidden")
+		}
+		if _, found := d.Find("EmbeddedFiles"); found && c.part() == 1 {
+			c.add(topicEmbeddedFiles, "embedded files are forbidden")
+		}
+	}
+
+	if err := c.checkOCProperties(rootDict); err != nil {
+		return err
+	}
+
+	return c.checkAcroForm(rootDict)
+}
+
+func iccProfileHeader(bb []byte) (major int, colorSpace string, ok bool) {
+	if len(bb) < 128 || string(bb[36:40]) != "acsp" {
+		return 0, "", false
+	}
+	return int(bb[8]), string(bb[16:20]), true
+}
+
+func (c *pdfaChecker) checkDestOutputProfile(o types.Object) (string, error) {
+	sd, _, err := c.xRefTable.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return "", err
+	}
+
+	if err := sd.Decode(); err != nil {
+		if err == filter.ErrUnsupportedFilter {
+			c.add(topicColor, "DestOutputProfile uses an unsupported filter")
+			return "", nil
+		}
+		return "", err
+	}
+
+	major, cs, ok := iccProfileHeader(sd.Content)
+	if !ok {
+		c.add(topicColor, "DestOutputProfile is not a valid ICC profile")
+		return "", nil
+	}
+
+	if c.part() == 1 && major > 2 || major > 4 {
+		c.add(topicColor, "DestOutputProfile: unsupported ICC profile version %d", major)
+	}
+
+	if cs != "RGB " && cs != "CMYK" && cs != "GRAY" {
+		c.add(topicColor, "DestOutputProfile: unsupported ICC color space %s", strings.TrimSpace(cs))
+	}
+
+	return cs, nil
+}
+
+func (c *pdfaChecker) checkOutputIntents(rootDict types.Dict) error {
+	var (
+		profileCS string
+		profile   *types.IndirectRef
+		found     bool
+	)
+
+	if o, ok := rootDict.Find("OutputIntents"); ok {
+		a, err := c.xRefTable.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+		for _, o := range a {
+			d, err := c.xRefTable.DereferenceDict(o)
+			if err != nil {
+				return err
+			}
+			if d == nil {
+				continue
+			}
+			s := d.NameEntry("S")
+			if s == nil || *s != "GTS_PDFA1" {
+				continue
+			}
+			o1, ok := d.Find("DestOutputProfile")
+			if !ok {
+				c.add(topicColor, "PDF/A output intent without DestOutputProfile")
+				continue
+			}
+			if ir, ok := o1.(types.IndirectRef); ok {
+				if profile != nil && profile.ObjectNumber != ir.ObjectNumber {
+					c.add(topicColor, "output intents with different DestOutputProfiles")
+				}
+				profile = &ir
+			}
+			if found {
+				continue
+			}
+			found = true
+			if profileCS, err = c.checkDestOutputProfile(o1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !found {
+		if c.deviceRGB || c.deviceCMYK || c.deviceGray || c.transparency {
+			c.add(topicColor, "device color spaces or transparency used without PDF/A output intent")
+		}
+		return nil
+	}
+
+	if c.deviceRGB && profileCS != "RGB " {
+		c.add(topicColor, "DeviceRGB used but output intent is not RGB")
+	}
+
+	if c.deviceCMYK && profileCS != "CMYK" {
+		c.add(topicColor, "DeviceCMYK used but output intent is not CMYK")
+	}
+
+	return nil
+}
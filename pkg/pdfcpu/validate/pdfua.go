@@ -0,0 +1,844 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Additional topics used for PDF/UA conformance reports.
+const (
+	topicCatalog    = "Catalog"
+	topicStructure  = "StructureTree"
+	topicTagging    = "TaggedContent"
+	topicAltText    = "AlternateDescriptions"
+	topicHeadings   = "Headings"
+	topicTables     = "Tables"
+	topicNavigation = "Navigation"
+)
+
+// Standard structure types, see ISO 32000-1 14.8.4
+var standardStructureTypes = []string{
+	"Document", "Part", "Art", "Sect", "Div", "BlockQuote", "Caption", "TOC", "TOCI", "Index", "NonStruct", "Private",
+	"P", "H", "H1", "H2", "H3", "H4", "H5", "H6", "L", "LI", "Lbl", "LBody",
+	"Table", "TR", "TH", "TD", "THead", "TBody", "TFoot",
+	"Span", "Quote", "Note", "Reference", "BibEntry", "Code", "Link", "Annot", "Ruby", "RB", "RT", "RP", "Warichu", "WT", "WP",
+	"Figure", "Formula", "Form",
+}
+
+// Content stream operators painting marks onto the page.
+var paintingOperators = []string{
+	"Tj", "TJ", "'", "\"", "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "sh", "BI",
+}
+
+type pdfuaChecker struct {
+	ctx        *model.Context
+	xRefTable  *model.XRefTable
+	r          *model.ConformanceReport
+	pageNr     int
+	objNr      int
+	roleMap    map[string]string
+	objRefs    types.IntSet // objects referenced from the structure tree via OBJR
+	elems      types.IntSet // visited structure elements
+	forms      types.IntSet // visited form XObjects
+	heading    int          // level of the most recent numbered heading
+	hUsed      bool         // unnumbered heading H used
+	hnUsed     bool         // numbered heading H1-H6 used
+	untagged   int          // untagged painting operations on current page
+	structTree bool
+}
+
+func (c *pdfuaChecker) add(topic, format string, args ...interface{}) {
+	c.r.Add(topic, c.pageNr, c.objNr, format, args...)
+}
+
+// PDFUA checks ctx for conformance with ISO 14289-1 (PDF/UA-1) and returns a report of all violations found.
+// Only machine checkable requirements are covered.
+// ctx is expected to be validated against ISO 32000.
+func PDFUA(ctx *model.Context) (*model.ConformanceReport, error) {
+	if !ctx.Conformance.UA() {
+		return nil, errors.New("pdfcpu: PDFUA: missing PDF/UA conformance level")
+	}
+
+	if log.ValidateEnabled() {
+		log.Validate.Printf("*** PDFUA %s begin ***\n", ctx.Conformance)
+	}
+
+	c := &pdfuaChecker{
+		ctx:       ctx,
+		xRefTable: ctx.XRefTable,
+		r:         &model.ConformanceReport{Conformance: ctx.Conformance},
+		roleMap:   map[string]string{},
+		objRefs:   types.IntSet{},
+		elems:     types.IntSet{},
+		forms:     types.IntSet{},
+	}
+
+	rootDict, err := c.xRefTable.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkCatalog(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkMetadata(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkStructTree(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkPages(); err != nil {
+		return nil, err
+	}
+
+	c.pageNr, c.objNr = 0, 0
+	if err := c.checkFonts(); err != nil {
+		return nil, err
+	}
+
+	if log.ValidateEnabled() {
+		log.Validate.Printf("*** PDFUA %s end ***\n", ctx.Conformance)
+	}
+
+	return c.r, nil
+}
+
+// CheckConformance checks ctx for conformance with ctx.Conformance and returns a report of all violations found.
+func CheckConformance(ctx *model.Context) (*model.ConformanceReport, error) {
+	switch {
+	case ctx.Conformance.PDFA():
+		return PDFA(ctx)
+	case ctx.Conformance.UA():
+		return PDFUA(ctx)
+	}
+	return nil, errors.New("pdfcpu: CheckConformance: missing conformance level")
+}
+
+func (c *pdfuaChecker) checkCatalog(rootDict types.Dict) error {
+	marked := false
+	if o, found := rootDict.Find("MarkInfo"); found {
+		d, err := c.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			if b := d.BooleanEntry("Marked"); b != nil && *b {
+				marked = true
+			}
+			if b := d.BooleanEntry("Suspects"); b != nil && *b {
+				c.add(topicCatalog, "MarkInfo: Suspects shall not be true")
+			}
+		}
+	}
+	if !marked {
+		c.add(topicCatalog, "document is not marked as tagged (MarkInfo Marked)")
+	}
+
+	lang := ""
+	if o, found := rootDict.Find("Lang"); found {
+		s, err := c.xRefTable.DereferenceText(o)
+		if err != nil {
+			return err
+		}
+		lang = strings.TrimSpace(s)
+	}
+	if lang == "" {
+		c.add(topicCatalog, "missing natural language (Lang) in catalog")
+	}
+
+	displayDocTitle := false
+	if o, found := rootDict.Find("ViewerPreferences"); found {
+		d, err := c.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			if b := d.BooleanEntry("DisplayDocTitle"); b != nil && *b {
+				displayDocTitle = true
+			}
+		}
+	}
+	if !displayDocTitle {
+		c.add(topicCatalog, "ViewerPreferences: DisplayDocTitle shall be true")
+	}
+
+	return nil
+}
+
+func (c *pdfuaChecker) checkMetadata(rootDict types.Dict) error {
+	o, found := rootDict.Find("Metadata")
+	if !found {
+		c.add(topicMetadata, "missing XMP metadata stream in catalog")
+		return nil
+	}
+
+	sd, _, err := c.xRefTable.DereferenceStreamDict(o)
+	if err != nil {
+		return err
+	}
+	if sd == nil {
+		c.add(topicMetadata, "missing XMP metadata stream in catalog")
+		return nil
+	}
+
+	if err := sd.Decode(); err != nil {
+		if err == filter.ErrUnsupportedFilter {
+			c.add(topicMetadata, "metadata stream uses an unsupported filter")
+			return nil
+		}
+		return err
+	}
+
+	part, err := model.ParsePDFUAID(sd.Content)
+	if err != nil {
+		c.add(topicMetadata, "malformed XMP metadata: %v", err)
+		return nil
+	}
+
+	if part == 0 {
+		c.add(topicMetadata, "missing PDF/UA identification (pdfuaid:part) in XMP metadata")
+	} else if part != 1 {
+		c.add(topicMetadata, "pdfuaid:part %d does not match PDF/UA-1", part)
+	}
+
+	ok, err := model.HasXMPTitle(sd.Content)
+	if err != nil {
+		c.add(topicMetadata, "malformed XMP metadata: %v", err)
+		return nil
+	}
+	if !ok {
+		c.add(topicMetadata, "missing document title (dc:title) in XMP metadata")
+	}
+
+	return nil
+}
+
+func (c *pdfuaChecker) parseRoleMap(d types.Dict) error {
+	o, found := d.Find("RoleMap")
+	if !found {
+		return nil
+	}
+
+	rm, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || rm == nil {
+		return err
+	}
+
+	for k, v := range rm {
+		o, err := c.xRefTable.Dereference(v)
+		if err != nil {
+			return err
+		}
+		if n, ok := o.(types.Name); ok {
+			c.roleMap[k] = n.Value()
+		}
+	}
+
+	for k := range c.roleMap {
+		if types.MemberOf(k, standardStructureTypes) {
+			c.add(topicStructure, "RoleMap: standard structure type %s shall not be remapped", k)
+		}
+	}
+
+	return nil
+}
+
+// standardRole returns the standard structure type s is mapped to or "".
+func (c *pdfuaChecker) standardRole(s string) string {
+	for i := 0; i < 10; i++ {
+		if types.MemberOf(s, standardStructureTypes) {
+			return s
+		}
+		s1, found := c.roleMap[s]
+		if !found {
+			return ""
+		}
+		s = s1
+	}
+	// Circular mapping
+	return ""
+}
+
+func (c *pdfuaChecker) checkStructTree(rootDict types.Dict) error {
+	o, found := rootDict.Find("StructTreeRoot")
+	if !found {
+		c.add(topicStructure, "missing structure tree (StructTreeRoot)")
+		return nil
+	}
+
+	d, err := c.xRefTable.DereferenceDict(o)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		c.add(topicStructure, "missing structure tree (StructTreeRoot)")
+		return nil
+	}
+
+	c.structTree = true
+
+	if err := c.parseRoleMap(d); err != nil {
+		return err
+	}
+
+	o, found = d.Find("K")
+	if !found {
+		c.add(topicStructure, "empty structure tree")
+		return nil
+	}
+
+	if err := c.walkStructElems(o, ""); err != nil {
+		return err
+	}
+
+	if c.hUsed && c.hnUsed {
+		c.add(topicHeadings, "numbered (H1-H6) and unnumbered (H) headings shall not be mixed")
+	}
+
+	return nil
+}
+
+// walkStructElems traverses the structure elements at o whose parent is of standard type parent.
+func (c *pdfuaChecker) walkStructElems(o types.Object, parent string) error {
+	objNr := c.objNr
+	defer func() { c.objNr = objNr }()
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		nr := ir.ObjectNumber.Value()
+		if c.elems[nr] {
+			return nil
+		}
+		c.elems[nr] = true
+		c.objNr = nr
+	}
+
+	o, err := c.xRefTable.Dereference(o)
+	if err != nil {
+		return err
+	}
+
+	switch o := o.(type) {
+
+	case types.Array:
+		for _, o1 := range o {
+			if err := c.walkStructElems(o1, parent); err != nil {
+				return err
+			}
+		}
+
+	case types.Dict:
+		return c.checkStructElem(o, parent)
+	}
+
+	return nil
+}
+
+func (c *pdfuaChecker) structElemPageNr(d types.Dict) int {
+	ir := d.IndirectRefEntry("Pg")
+	if ir == nil {
+		return 0
+	}
+	i, err := c.xRefTable.PageNumber(ir.ObjectNumber.Value())
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+func (c *pdfuaChecker) hasAlternateDescription(d types.Dict) bool {
+	for _, k := range []string{"Alt", "ActualText"} {
+		if o, found := d.Find(k); found {
+			if s, err := c.xRefTable.DereferenceText(o); err == nil && strings.TrimSpace(s) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *pdfuaChecker) checkHeading(s string) {
+	if s == "H" {
+		c.hUsed = true
+		return
+	}
+
+	c.hnUsed = true
+	level := int(s[1] - '0')
+	if level > c.heading+1 {
+		c.add(topicHeadings, "heading level skipped: %s follows H%d", s, c.heading)
+	}
+	c.heading = level
+}
+
+// containsStructType returns true if the structure element subtree at o contains an element of standard type s.
+func (c *pdfuaChecker) containsStructType(o types.Object, s string, visited types.IntSet) bool {
+	if ir, ok := o.(types.IndirectRef); ok {
+		nr := ir.ObjectNumber.Value()
+		if visited[nr] {
+			return false
+		}
+		visited[nr] = true
+	}
+
+	o, err := c.xRefTable.Dereference(o)
+	if err != nil {
+		return false
+	}
+
+	switch o := o.(type) {
+
+	case types.Array:
+		for _, o1 := range o {
+			if c.containsStructType(o1, s, visited) {
+				return true
+			}
+		}
+
+	case types.Dict:
+		if n := o.NameEntry("S"); n != nil && c.standardRole(*n) == s {
+			return true
+		}
+		if t := o.Type(); t != nil && (*t == "MCR" || *t == "OBJR") {
+			return false
+		}
+		if k, found := o.Find("K"); found {
+			return c.containsStructType(k, s, visited)
+		}
+	}
+
+	return false
+}
+
+func (c *pdfuaChecker) checkTableStructure(s, parent string) {
+	switch s {
+	case "TR":
+		if !types.MemberOf(parent, []string{"Table", "THead", "TBody", "TFoot"}) {
+			c.add(topicTables, "TR shall be a child of Table, THead, TBody or TFoot, not %s", parent)
+		}
+	case "TH", "TD":
+		if parent != "TR" {
+			c.add(topicTables, "%s shall be a child of TR, not %s", s, parent)
+		}
+	case "THead", "TBody", "TFoot":
+		if parent != "Table" {
+			c.add(topicTables, "%s shall be a child of Table, not %s", s, parent)
+		}
+	}
+
+	if parent == "TR" && s != "TH" && s != "TD" {
+		c.add(topicTables, "TR shall only contain TH or TD, not %s", s)
+	}
+}
+
+func (c *pdfuaChecker) checkStructElem(d types.Dict, parent string) error {
+	if t := d.Type(); t != nil {
+		switch *t {
+		case "MCR":
+			return nil
+		case "OBJR":
+			if ir := d.IndirectRefEntry("Obj"); ir != nil {
+				c.objRefs[ir.ObjectNumber.Value()] = true
+			}
+			return nil
+		}
+	}
+
+	pageNr := c.pageNr
+	defer func() { c.pageNr = pageNr }()
+	if i := c.structElemPageNr(d); i > 0 {
+		c.pageNr = i
+	}
+
+	n := d.NameEntry("S")
+	if n == nil {
+		c.add(topicStructure, "structure element without structure type (S)")
+		return nil
+	}
+
+	s := c.standardRole(*n)
+	if s == "" {
+		c.add(topicStructure, "structure type %s is not mapped to a standard structure type", *n)
+	}
+
+	switch s {
+	case "Figure", "Formula":
+		if !c.hasAlternateDescription(d) {
+			c.add(topicAltText, "%s without alternate description (Alt or ActualText)", s)
+		}
+	case "H", "H1", "H2", "H3", "H4", "H5", "H6":
+		c.checkHeading(s)
+	case "Table":
+		if k, found := d.Find("K"); !found || !c.containsStructType(k, "TH", types.IntSet{}) {
+			c.add(topicTables, "table without header cells (TH)")
+		}
+	}
+
+	if s != "" && parent != "" {
+		c.checkTableStructure(s, parent)
+	}
+
+	o, found := d.Find("K")
+	if !found {
+		return nil
+	}
+
+	return c.walkStructElems(o, s)
+}
+
+func (c *pdfuaChecker) checkPages() error {
+	for i := 1; i <= c.xRefTable.PageCount; i++ {
+		d, indRef, inhPAttrs, err := c.xRefTable.PageDict(i, true)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		c.pageNr, c.objNr = i, 0
+		if indRef != nil {
+			c.objNr = indRef.ObjectNumber.Value()
+		}
+
+		var res types.Dict
+		if inhPAttrs != nil {
+			res = inhPAttrs.Resources
+		}
+
+		if err := c.checkPageContent(d, res); err != nil {
+			return err
+		}
+
+		if err := c.checkAnnotations(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markedContent represents an open marked content sequence.
+type markedContent struct {
+	tagged   bool // marked content with MCID
+	artifact bool
+}
+
+func (c *pdfuaChecker) propertyList(res types.Dict, name string) types.Dict {
+	if res == nil {
+		return nil
+	}
+	o, found := res.Find("Properties")
+	if !found {
+		return nil
+	}
+	d, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return nil
+	}
+	o, found = d.Find(name)
+	if !found {
+		return nil
+	}
+	d, err = c.xRefTable.DereferenceDict(o)
+	if err != nil {
+		return nil
+	}
+	return d
+}
+
+func (c *pdfuaChecker) markedContent(op model.ContentOp, res types.Dict) markedContent {
+	if len(op.Operands) == 0 {
+		return markedContent{}
+	}
+
+	mc := markedContent{artifact: op.Operands[0] == "/Artifact"}
+	if op.Name != "BDC" || len(op.Operands) < 2 {
+		return mc
+	}
+
+	props := op.Operands[len(op.Operands)-1]
+	if strings.HasPrefix(props, "<<") {
+		mc.tagged = strings.Contains(props, "/MCID")
+		return mc
+	}
+
+	if d := c.propertyList(res, strings.TrimPrefix(props, "/")); d != nil {
+		_, mc.tagged = d.Find("MCID")
+	}
+
+	return mc
+}
+
+func (c *pdfuaChecker) xObject(res types.Dict, name string) (*types.StreamDict, int) {
+	if res == nil {
+		return nil, 0
+	}
+	o, found := res.Find("XObject")
+	if !found {
+		return nil, 0
+	}
+	d, err := c.xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return nil, 0
+	}
+	o, found = d.Find(name)
+	if !found {
+		return nil, 0
+	}
+	objNr := 0
+	if ir, ok := o.(types.IndirectRef); ok {
+		objNr = ir.ObjectNumber.Value()
+	}
+	sd, _, err := c.xRefTable.DereferenceStreamDict(o)
+	if err != nil {
+		return nil, 0
+	}
+	return sd, objNr
+}
+
+func (c *pdfuaChecker) checkContent(bb []byte, res types.Dict) error {
+	var stack []markedContent
+
+	inside := func() bool {
+		for _, mc := range stack {
+			if mc.tagged || mc.artifact {
+				return true
+			}
+		}
+		return false
+	}
+
+	err := model.ParseContentOps(bb, func(op model.ContentOp) error {
+		switch op.Name {
+
+		case "BMC", "BDC":
+			stack = append(stack, c.markedContent(op, res))
+
+		case "EMC":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+
+		case "Do":
+			if inside() || len(op.Operands) == 0 {
+				return nil
+			}
+			sd, objNr := c.xObject(res, strings.TrimPrefix(op.Operands[0], "/"))
+			if sd == nil {
+				return nil
+			}
+			if st := sd.Subtype(); st == nil || *st != "Form" {
+				c.untagged++
+				return nil
+			}
+			if objNr > 0 {
+				if c.forms[objNr] {
+					return nil
+				}
+				c.forms[objNr] = true
+			}
+			return c.checkFormXObject(sd, res)
+
+		default:
+			if !inside() && types.MemberOf(op.Name, paintingOperators) {
+				c.untagged++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		c.add(topicContentStreams, "malformed content stream: %v", err)
+	}
+
+	return nil
+}
+
+func (c *pdfuaChecker) checkFormXObject(sd *types.StreamDict, res types.Dict) error {
+	if err := sd.Decode(); err != nil {
+		if err == filter.ErrUnsupportedFilter {
+			return nil
+		}
+		return err
+	}
+
+	if o, found := sd.Find("Resources"); found {
+		d, err := c.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			res = d
+		}
+	}
+
+	return c.checkContent(sd.Content, res)
+}
+
+func (c *pdfuaChecker) checkPageContent(d, res types.Dict) error {
+	bb, err := c.xRefTable.PageContent(d, c.pageNr)
+	if err != nil {
+		if err == model.ErrNoContent {
+			return nil
+		}
+		if err == filter.ErrUnsupportedFilter {
+			c.add(topicFilters, "page content uses an unsupported filter")
+			return nil
+		}
+		return err
+	}
+
+	c.untagged = 0
+	c.forms = types.IntSet{}
+
+	if err := c.checkContent(bb, res); err != nil {
+		return err
+	}
+
+	if c.untagged > 0 {
+		c.add(topicTagging, "%d painting operation(s) neither tagged nor marked as artifact", c.untagged)
+	}
+
+	return nil
+}
+
+func (c *pdfuaChecker) checkAnnotations(d types.Dict) error {
+	o, found := d.Find("Annots")
+	if !found {
+		return nil
+	}
+
+	a, err := c.xRefTable.DereferenceArray(o)
+	if err != nil || len(a) == 0 {
+		return err
+	}
+
+	if tabs := d.NameEntry("Tabs"); tabs == nil || *tabs != "S" {
+		c.add(topicNavigation, "page with annotations: Tabs shall be S (structure order)")
+	}
+
+	for _, o := range a {
+		objNr := 0
+		if ir, ok := o.(types.IndirectRef); ok {
+			objNr = ir.ObjectNumber.Value()
+		}
+
+		d1, err := c.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d1 == nil {
+			continue
+		}
+
+		subtype := ""
+		if st := d1.Subtype(); st != nil {
+			subtype = *st
+		}
+
+		if subtype == "Popup" {
+			continue
+		}
+
+		if f := d1.IntEntry("F"); f != nil && model.AnnotationFlags(*f)&model.AnnHidden > 0 {
+			continue
+		}
+
+		if subtype == "TrapNet" || subtype == "PrinterMark" {
+			c.add(topicAnnotations, "%s annotation is forbidden", subtype)
+			continue
+		}
+
+		if c.structTree && (objNr == 0 || !c.objRefs[objNr]) {
+			c.add(topicAnnotations, "%s annotation not referenced by the structure tree (OBJR)", subtype)
+		}
+
+		if subtype != "Widget" && !c.hasAlternateDescription(d1) {
+			if o, found := d1.Find("Contents"); !found || o == nil {
+				c.add(topicAnnotations, "%s annotation without alternate description (Contents)", subtype)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfuaChecker) checkFont(d types.Dict) error {
+	st := d.Subtype()
+	if st == nil {
+		return nil
+	}
+	subtype := *st
+
+	if subtype == "CIDFontType0" || subtype == "CIDFontType2" {
+		// Checked via the parent Type0 font.
+		return nil
+	}
+
+	var fd types.Dict
+
+	if subtype != "Type3" {
+		var err error
+		if fd, err = checkFontEmbedding(c.xRefTable, d, c.objNr, c.add); err != nil {
+			return err
+		}
+	}
+
+	checkFontUnicode(d, fd, c.add)
+
+	return nil
+}
+
+func (c *pdfuaChecker) checkFonts() error {
+	objNrs := make([]int, 0, len(c.xRefTable.Table))
+	for objNr := range c.xRefTable.Table {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		entry := c.xRefTable.Table[objNr]
+		if entry == nil || entry.Free || entry.Object == nil {
+			continue
+		}
+		d, ok := entry.Object.(types.Dict)
+		if !ok {
+			continue
+		}
+		if t := d.Type(); t == nil || *t != "Font" {
+			continue
+		}
+		c.objNr = objNr
+		if err := c.checkFont(d); err != nil {
+			return err
+		}
+	}
+
+	c.objNr = 0
+
+	return nil
+}
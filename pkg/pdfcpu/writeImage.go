@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	"github.com/hhrutter/tiff"
+	"github.com/pdfcpu/pdfcpu/internal/jpx"
 	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
@@ -89,7 +90,11 @@ func pdfImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, objN
 		return nil, err
 	}
 
-	bpc := *sd.IntEntry("BitsPerComponent")
+	// BitsPerComponent is optional for JPX images.
+	bpc := 8
+	if i := sd.IntEntry("BitsPerComponent"); i != nil {
+		bpc = *i
+	}
 
 	obj, ok := sd.Find("Width")
 	if !ok {
@@ -841,6 +846,10 @@ func renderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, o
 		return nil, "", err
 	}
 
+	return renderImageForColorSpace(xRefTable, pdfImage, o, objNr)
+}
+
+func renderImageForColorSpace(xRefTable *model.XRefTable, pdfImage *PDFImage, o types.Object, objNr int) (io.Reader, string, error) {
 	switch cs := o.(type) {
 
 	case types.Name:
@@ -941,6 +950,139 @@ func renderDCTToPNG(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool
 	return renderCMYKToPng(im)
 }
 
+func isIndexedColorSpace(o types.Object) bool {
+	cs, ok := o.(types.Array)
+	return ok && len(cs) == 4 && cs[0] == types.Name(model.IndexedCS)
+}
+
+func renderJPXToPNG(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, objNr int) (io.Reader, string, error) {
+	bb := sd.Content
+	if bb == nil {
+		bb = sd.Raw
+	}
+
+	img, err := jpx.Decode(bytes.NewReader(bb))
+	if err != nil {
+		return nil, "", err
+	}
+
+	im, err := pdfImage(xRefTable, sd, thumb, objNr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	o, err := xRefTable.DereferenceDictEntry(sd.Dict, "ColorSpace")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Decode is ignored for JPX images and all samples are rendered with 8 bits.
+	im.w, im.h, im.bpc, im.comp, im.decode = img.Width, img.Height, 8, len(img.Components), nil
+
+	if i := sd.IntEntry("SMaskInData"); i != nil && *i > 0 && img.Alpha != nil {
+		im.softMask = img.Alpha.Bytes()
+	}
+	if len(im.softMask) != im.w*im.h {
+		im.softMask = nil
+	}
+
+	content := img.Bytes()
+	if isIndexedColorSpace(o) && len(img.Components) == 1 && img.Components[0].Precision <= 8 {
+		// Use palette indices as is.
+		for i, v := range img.Components[0].Samples {
+			content[i] = byte(v)
+		}
+	}
+
+	sd1 := *sd
+	sd1.Content = content
+	im.sd = &sd1
+
+	if o != nil {
+		// The ColorSpace of the image dict overrides any color space specification of the JPX data.
+		n, err := ColorSpaceComponents(xRefTable, sd)
+		if err != nil {
+			return nil, "", err
+		}
+		if n == im.comp || isIndexedColorSpace(o) && im.comp == 1 {
+			return renderImageForColorSpace(xRefTable, im, o, objNr)
+		}
+	}
+
+	switch {
+	case img.ColorSpace == jpx.ColorSpaceGray && im.comp == 1:
+		return renderDeviceGrayToPNG(im)
+	case img.ColorSpace == jpx.ColorSpaceRGB && im.comp == 3:
+		return renderDeviceRGBToPNG(im)
+	case img.ColorSpace == jpx.ColorSpaceCMYK && im.comp == 4:
+		return renderDeviceCMYKToTIFF(im)
+	}
+
+	return nil, "", errors.Errorf("pdfcpu: renderJPXToPNG: objNr=%d unsupported JPX color space with %d components", objNr, im.comp)
+}
+
+// decodeJBIG2 decodes the JBIG2 data of sd using the global segments of the stream referenced by JBIG2Globals.
+func decodeJBIG2(xRefTable *model.XRefTable, sd *types.StreamDict) ([]byte, error) {
+	if sd.Content == nil {
+		if err := sd.Decode(); err != nil {
+			return nil, err
+		}
+	}
+
+	var globals []byte
+	if fpl := sd.FilterPipeline; len(fpl) > 0 && fpl[len(fpl)-1].DecodeParms != nil {
+		if o, found := fpl[len(fpl)-1].DecodeParms.Find("JBIG2Globals"); found {
+			gsd, _, err := xRefTable.DereferenceStreamDict(o)
+			if err != nil {
+				return nil, err
+			}
+			if gsd != nil {
+				if err := gsd.Decode(); err != nil {
+					return nil, err
+				}
+				globals = gsd.Content
+			}
+		}
+	}
+
+	r, err := filter.NewJBIG2Filter(globals).Decode(bytes.NewReader(sd.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+func renderJBIG2ToPNG(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, objNr int) (io.Reader, string, error) {
+	bb, err := decodeJBIG2(xRefTable, sd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	im, err := pdfImage(xRefTable, sd, thumb, objNr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// JBIG2 images are always bilevel.
+	im.bpc = 1
+
+	sd1 := *sd
+	sd1.Content = bb
+	im.sd = &sd1
+
+	o, err := xRefTable.DereferenceDictEntry(sd.Dict, "ColorSpace")
+	if err != nil {
+		return nil, "", err
+	}
+	if o == nil {
+		// Image mask
+		return renderDeviceGrayToPNG(im)
+	}
+
+	return renderImageForColorSpace(xRefTable, im, o, objNr)
+}
+
 // RenderImage returns a reader for a decoded image stream.
 func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, resourceName string, objNr int) (io.Reader, string, error) {
 	// Image compression is the last filter in the pipeline.
@@ -963,7 +1105,10 @@ func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, r
 		return bytes.NewReader(sd.Content), "jpg", nil
 
 	case filter.JPX:
-		return bytes.NewReader(sd.Content), "jpx", nil
+		return renderJPXToPNG(xRefTable, sd, thumb, objNr)
+
+	case filter.JBIG2:
+		return renderJBIG2ToPNG(xRefTable, sd, thumb, objNr)
 	}
 
 	return nil, "", nil
@@ -0,0 +1,1288 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// XFDF element names for supported markup annotation types.
+var xfdfAnnotElements = map[string]string{
+	"Text":      "text",
+	"FreeText":  "freetext",
+	"Line":      "line",
+	"Square":    "square",
+	"Circle":    "circle",
+	"Polygon":   "polygon",
+	"PolyLine":  "polyline",
+	"Highlight": "highlight",
+	"Underline": "underline",
+	"Squiggly":  "squiggly",
+	"StrikeOut": "strikeout",
+	"Caret":     "caret",
+	"Ink":       "ink",
+}
+
+// XFDF flag names in annotation flag bit order.
+var xfdfFlagNames = []string{
+	"invisible", "hidden", "print", "nozoom", "norotate", "noview", "readonly", "locked", "togglenoview", "lockedcontents",
+}
+
+// XFDF border styles.
+var xfdfBorderStyles = map[string]model.BorderStyle{
+	"solid":     model.BSSolid,
+	"dash":      model.BSDashed,
+	"bevelled":  model.BSBeveled,
+	"inset":     model.BSInset,
+	"underline": model.BSUnderline,
+}
+
+var xfdfJustifications = []string{"left", "centered", "right"}
+
+func xfdfNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
+
+// xfdfNumbers formats ff as a comma separated list of numbers.
+// If pairs is set coordinate pairs get separated by semicolons.
+func xfdfNumbers(ff []float64, pairs bool) string {
+	var sb strings.Builder
+	for i, f := range ff {
+		if i > 0 {
+			if pairs && i%2 == 0 {
+				sb.WriteByte(';')
+			} else {
+				sb.WriteByte(',')
+			}
+		}
+		sb.WriteString(xfdfNumber(f))
+	}
+	return sb.String()
+}
+
+func parseXFDFNumbers(s string) ([]float64, error) {
+	ss := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	ff := make([]float64, len(ss))
+	for i, s := range ss {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, errors.Errorf("pdfcpu: invalid XFDF number: %s", s)
+		}
+		ff[i] = f
+	}
+	return ff, nil
+}
+
+func xfdfFlags(f model.AnnotationFlags) string {
+	var ss []string
+	for i, s := range xfdfFlagNames {
+		if f&(1<<i) > 0 {
+			ss = append(ss, s)
+		}
+	}
+	return strings.Join(ss, ",")
+}
+
+func parseXFDFFlags(s string) model.AnnotationFlags {
+	var f model.AnnotationFlags
+	for _, s := range strings.Split(s, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		for i, name := range xfdfFlagNames {
+			if s == name {
+				f |= 1 << i
+			}
+		}
+	}
+	return f
+}
+
+func xfdfColor(ff []float64) string {
+	switch len(ff) {
+	case 1:
+		ff = []float64{ff[0], ff[0], ff[0]}
+	case 3:
+	case 4:
+		k := 1 - ff[3]
+		ff = []float64{(1 - ff[0]) * k, (1 - ff[1]) * k, (1 - ff[2]) * k}
+	default:
+		return ""
+	}
+	c := func(f float64) int {
+		return int(math.Round(math.Max(0, math.Min(1, f)) * 255))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", c(ff[0]), c(ff[1]), c(ff[2]))
+}
+
+func parseXFDFColor(s string) (*color.SimpleColor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	c, err := color.NewSimpleColorForHexCode(s)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func parseXFDFRect(s string) (*types.Rectangle, error) {
+	ff, err := parseXFDFNumbers(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(ff) != 4 {
+		return nil, errors.Errorf("pdfcpu: invalid XFDF rect: %s", s)
+	}
+	return types.NewRectangle(math.Min(ff[0], ff[2]), math.Min(ff[1], ff[3]), math.Max(ff[0], ff[2]), math.Max(ff[1], ff[3])), nil
+}
+
+func lineEndingStyleForName(s string) *model.LineEndingStyle {
+	for les := model.LESquare; les <= model.LESlash; les++ {
+		if model.LineEndingStyleName(les) == s {
+			return &les
+		}
+	}
+	return nil
+}
+
+// wellFormedXML returns true if s is a well-formed XML fragment.
+func wellFormedXML(s string) bool {
+	dec := xml.NewDecoder(strings.NewReader(s))
+	var hasElement bool
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return hasElement
+		}
+		if err != nil {
+			return false
+		}
+		if _, ok := t.(xml.StartElement); ok {
+			hasElement = true
+		}
+	}
+}
+
+// xmlText returns the character data of the XML fragment s.
+func xmlText(s string) string {
+	var sb strings.Builder
+	dec := xml.NewDecoder(strings.NewReader(s))
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if cd, ok := t.(xml.CharData); ok {
+			sb.Write(cd)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// Export
+
+type xfdfExporter struct {
+	xRefTable *model.XRefTable
+	d         types.Dict
+	names     *xfdfNames
+}
+
+func (e xfdfExporter) numbers(d types.Dict, key string) ([]float64, error) {
+	o, found := d.Find(key)
+	if !found {
+		return nil, nil
+	}
+
+	a, err := e.xRefTable.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	ff := make([]float64, 0, len(a))
+	for _, o := range a {
+		f, err := e.xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return nil, err
+		}
+		ff = append(ff, f)
+	}
+
+	return ff, nil
+}
+
+func (e xfdfExporter) number(d types.Dict, key string) (string, error) {
+	o, found := d.Find(key)
+	if !found {
+		return "", nil
+	}
+	f, err := e.xRefTable.DereferenceNumber(o)
+	if err != nil {
+		return "", err
+	}
+	return xfdfNumber(f), nil
+}
+
+func (e xfdfExporter) text(d types.Dict, key string) (string, error) {
+	o, found := d.Find(key)
+	if !found {
+		return "", nil
+	}
+	return e.xRefTable.DereferenceText(o)
+}
+
+func (e xfdfExporter) name(d types.Dict, key string) (string, error) {
+	o, found := d.Find(key)
+	if !found {
+		return "", nil
+	}
+	n, err := e.xRefTable.DereferenceName(o, model.V10, nil)
+	return n.Value(), err
+}
+
+func (e xfdfExporter) rect(d types.Dict) (string, error) {
+	ff, err := e.numbers(d, "Rect")
+	if err != nil {
+		return "", err
+	}
+	return xfdfNumbers(ff, false), nil
+}
+
+func (e xfdfExporter) flags(d types.Dict) string {
+	if f := d.IntEntry("F"); f != nil {
+		return xfdfFlags(model.AnnotationFlags(*f))
+	}
+	return ""
+}
+
+func (e xfdfExporter) color(key string) (string, error) {
+	ff, err := e.numbers(e.d, key)
+	if err != nil {
+		return "", err
+	}
+	return xfdfColor(ff), nil
+}
+
+// xfdfNames hands out annotation names unique within a document.
+type xfdfNames struct {
+	used     map[string]bool
+	assigned map[int]string // by objNr
+}
+
+func annotNM(xRefTable *model.XRefTable, d types.Dict) (string, error) {
+	o, found := d.Find("NM")
+	if !found {
+		return "", nil
+	}
+	return xRefTable.DereferenceText(o)
+}
+
+// newXFDFNames collects the names of all page annotations of ctx.
+func newXFDFNames(ctx *model.Context) (*xfdfNames, error) {
+	names := &xfdfNames{used: map[string]bool{}, assigned: map[int]string{}}
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		d, _, _, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return nil, err
+		}
+		arr, err := ctx.DereferenceArray(d["Annots"])
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range arr {
+			d, err := ctx.DereferenceDict(o)
+			if err != nil {
+				return nil, err
+			}
+			s, err := annotNM(ctx.XRefTable, d)
+			if err != nil {
+				return nil, err
+			}
+			if s != "" {
+				names.used[s] = true
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// annotName returns the name of the annotation d with object number objNr.
+// Annotations without name get one assigned for reference by replies.
+func (names *xfdfNames) annotName(xRefTable *model.XRefTable, d types.Dict, objNr int) (string, error) {
+	s, err := annotNM(xRefTable, d)
+	if err != nil || s != "" {
+		return s, err
+	}
+
+	if s, ok := names.assigned[objNr]; ok {
+		return s, nil
+	}
+
+	s = fmt.Sprintf("pdfcpu-%d", objNr)
+	for i := 1; names.used[s]; i++ {
+		s = fmt.Sprintf("pdfcpu-%d-%d", objNr, i)
+	}
+	names.used[s] = true
+	names.assigned[objNr] = s
+
+	return s, nil
+}
+
+func (e xfdfExporter) markup(a *model.XFDFAnnot) error {
+	var err error
+
+	if a.Title, err = e.text(e.d, "T"); err != nil {
+		return err
+	}
+
+	if a.Subject, err = e.text(e.d, "Subj"); err != nil {
+		return err
+	}
+
+	if a.CreationDate, err = e.text(e.d, "CreationDate"); err != nil {
+		return err
+	}
+
+	if a.Opacity, err = e.number(e.d, "CA"); err != nil {
+		return err
+	}
+
+	rc, err := e.text(e.d, "RC")
+	if err != nil {
+		return err
+	}
+	if rc = strings.TrimSpace(rc); strings.HasPrefix(rc, "<?xml") {
+		if i := strings.Index(rc, "?>"); i > 0 {
+			rc = strings.TrimSpace(rc[i+2:])
+		}
+	}
+	if wellFormedXML(rc) {
+		a.RichText = &model.XFDFRichText{InnerXML: rc}
+	}
+
+	if indRef := e.d.IndirectRefEntry("IRT"); indRef != nil {
+		d, err := e.xRefTable.DereferenceDict(*indRef)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			if a.InReplyTo, err = e.names.annotName(e.xRefTable, d, indRef.ObjectNumber.Value()); err != nil {
+				return err
+			}
+			a.ReplyType = "reply"
+			if rt, _ := e.name(e.d, "RT"); rt == "Group" {
+				a.ReplyType = "group"
+			}
+		}
+	}
+
+	if indRef := e.d.IndirectRefEntry("Popup"); indRef != nil {
+		d, err := e.xRefTable.DereferenceDict(*indRef)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			p := &model.XFDFPopup{Page: a.Page, Flags: e.flags(d), Open: "no"}
+			if p.Rect, err = e.rect(d); err != nil {
+				return err
+			}
+			if b := d.BooleanEntry("Open"); b != nil && *b {
+				p.Open = "yes"
+			}
+			a.Popup = p
+		}
+	}
+
+	return nil
+}
+
+func (e xfdfExporter) border(a *model.XFDFAnnot) error {
+	if o, found := e.d.Find("BS"); found {
+		bs, err := e.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if a.Width, err = e.number(bs, "W"); err != nil {
+			return err
+		}
+		s, err := e.name(bs, "S")
+		if err != nil {
+			return err
+		}
+		for k, v := range xfdfBorderStyles {
+			if s == model.BorderStyleName(v) {
+				a.Style = k
+			}
+		}
+		dd, err := e.numbers(bs, "D")
+		if err != nil {
+			return err
+		}
+		a.Dashes = xfdfNumbers(dd, false)
+	} else {
+		ff, err := e.numbers(e.d, "Border")
+		if err != nil {
+			return err
+		}
+		if len(ff) >= 3 {
+			a.Width = xfdfNumber(ff[2])
+		}
+	}
+
+	if o, found := e.d.Find("BE"); found {
+		be, err := e.xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if s, _ := e.name(be, "S"); s == "C" {
+			a.Style = "cloudy"
+			if a.Intensity, err = e.number(be, "I"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e xfdfExporter) lineEndings(a *model.XFDFAnnot) error {
+	o, found := e.d.Find("LE")
+	if !found {
+		return nil
+	}
+
+	o, err := e.xRefTable.Dereference(o)
+	if err != nil {
+		return err
+	}
+
+	switch le := o.(type) {
+	case types.Name:
+		a.Head = le.Value()
+	case types.Array:
+		if len(le) == 2 {
+			n1, _ := le[0].(types.Name)
+			n2, _ := le[1].(types.Name)
+			a.Head, a.Tail = n1.Value(), n2.Value()
+		}
+	}
+
+	return nil
+}
+
+func (e xfdfExporter) fringe(a *model.XFDFAnnot) error {
+	ff, err := e.numbers(e.d, "RD")
+	if err != nil {
+		return err
+	}
+	a.Fringe = xfdfNumbers(ff, false)
+	return nil
+}
+
+func (e xfdfExporter) line(a *model.XFDFAnnot) error {
+	ff, err := e.numbers(e.d, "L")
+	if err != nil {
+		return err
+	}
+	if len(ff) != 4 {
+		return errors.New("pdfcpu: corrupt line annotation: missing \"L\"")
+	}
+	a.Start, a.End = xfdfNumbers(ff[:2], false), xfdfNumbers(ff[2:], false)
+
+	if a.LeaderLength, err = e.number(e.d, "LL"); err != nil {
+		return err
+	}
+	if a.LeaderExtend, err = e.number(e.d, "LLE"); err != nil {
+		return err
+	}
+	if a.LeaderOffset, err = e.number(e.d, "LLO"); err != nil {
+		return err
+	}
+
+	if b := e.d.BooleanEntry("Cap"); b != nil && *b {
+		a.Caption = "yes"
+		if a.CaptionStyle, err = e.name(e.d, "CP"); err != nil {
+			return err
+		}
+		co, err := e.numbers(e.d, "CO")
+		if err != nil {
+			return err
+		}
+		if len(co) == 2 {
+			a.CaptionOffsetH, a.CaptionOffsetV = xfdfNumber(co[0]), xfdfNumber(co[1])
+		}
+	}
+
+	if a.Intent, err = e.name(e.d, "IT"); err != nil {
+		return err
+	}
+
+	return e.lineEndings(a)
+}
+
+func (e xfdfExporter) poly(a *model.XFDFAnnot) error {
+	ff, err := e.numbers(e.d, "Vertices")
+	if err != nil {
+		return err
+	}
+	a.Vertices = xfdfNumbers(ff, true)
+
+	if a.Intent, err = e.name(e.d, "IT"); err != nil {
+		return err
+	}
+
+	return e.lineEndings(a)
+}
+
+func (e xfdfExporter) ink(a *model.XFDFAnnot) error {
+	o, found := e.d.Find("InkList")
+	if !found {
+		return nil
+	}
+
+	arr, err := e.xRefTable.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	a.InkList = &model.XFDFInkList{}
+	for _, o := range arr {
+		ff, err := e.numbers(types.Dict(map[string]types.Object{"P": o}), "P")
+		if err != nil {
+			return err
+		}
+		a.InkList.Gestures = append(a.InkList.Gestures, xfdfNumbers(ff, true))
+	}
+
+	return nil
+}
+
+func (e xfdfExporter) freeText(a *model.XFDFAnnot) error {
+	var err error
+
+	if a.DefaultAppearance, err = e.text(e.d, "DA"); err != nil {
+		return err
+	}
+
+	if a.DefaultStyle, err = e.text(e.d, "DS"); err != nil {
+		return err
+	}
+
+	if q := e.d.IntEntry("Q"); q != nil && *q >= 0 && *q < len(xfdfJustifications) {
+		a.Justification = xfdfJustifications[*q]
+	}
+
+	if a.Intent, err = e.name(e.d, "IT"); err != nil {
+		return err
+	}
+
+	cl, err := e.numbers(e.d, "CL")
+	if err != nil {
+		return err
+	}
+	a.Callout = xfdfNumbers(cl, false)
+
+	if err := e.fringe(a); err != nil {
+		return err
+	}
+
+	return e.lineEndings(a)
+}
+
+func (e xfdfExporter) typeSpecific(subtype string, a *model.XFDFAnnot) error {
+	var err error
+
+	switch subtype {
+
+	case "Text":
+		a.Icon, err = e.name(e.d, "Name")
+
+	case "FreeText":
+		err = e.freeText(a)
+
+	case "Line":
+		err = e.line(a)
+
+	case "Square", "Circle":
+		err = e.fringe(a)
+
+	case "Polygon", "PolyLine":
+		err = e.poly(a)
+
+	case "Highlight", "Underline", "Squiggly", "StrikeOut":
+		var ff []float64
+		if ff, err = e.numbers(e.d, "QuadPoints"); err == nil {
+			a.Coords = xfdfNumbers(ff, false)
+		}
+
+	case "Caret":
+		a.Symbol = "None"
+		if sy, _ := e.name(e.d, "Sy"); sy == "P" {
+			a.Symbol = "paragraph"
+		}
+		err = e.fringe(a)
+
+	case "Ink":
+		err = e.ink(a)
+	}
+
+	return err
+}
+
+func xfdfAnnot(xRefTable *model.XRefTable, names *xfdfNames, d types.Dict, pageNr, objNr int) (*model.XFDFAnnot, error) {
+	subtype := d.NameEntry("Subtype")
+	if subtype == nil {
+		return nil, nil
+	}
+
+	elem, ok := xfdfAnnotElements[*subtype]
+	if !ok {
+		return nil, nil
+	}
+
+	e := xfdfExporter{xRefTable: xRefTable, d: d, names: names}
+
+	a := &model.XFDFAnnot{XMLName: xml.Name{Local: elem}, Page: pageNr - 1, Flags: e.flags(d)}
+
+	var err error
+
+	if a.Rect, err = e.rect(d); err != nil {
+		return nil, err
+	}
+
+	if a.Name, err = names.annotName(xRefTable, d, objNr); err != nil {
+		return nil, err
+	}
+
+	if a.Date, err = e.text(d, "M"); err != nil {
+		return nil, err
+	}
+
+	if a.Contents, err = e.text(d, "Contents"); err != nil {
+		return nil, err
+	}
+
+	if a.Color, err = e.color("C"); err != nil {
+		return nil, err
+	}
+
+	if a.InteriorColor, err = e.color("IC"); err != nil {
+		return nil, err
+	}
+
+	if err := e.markup(a); err != nil {
+		return nil, err
+	}
+
+	if err := e.border(a); err != nil {
+		return nil, err
+	}
+
+	if err := e.typeSpecific(*subtype, a); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// ExportAnnotationsXFDF returns an XFDF document containing the markup annotations of selected pages.
+func ExportAnnotationsXFDF(ctx *model.Context, selectedPages types.IntSet, source string) (*model.XFDF, bool, error) {
+	names, err := newXFDFNames(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	x := model.NewXFDF(ctx.XRefTable, source)
+	x.Annots = &model.XFDFAnnots{}
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		if selectedPages != nil && !selectedPages[pageNr] {
+			continue
+		}
+
+		d, _, _, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return nil, false, err
+		}
+
+		o, found := d.Find("Annots")
+		if !found {
+			continue
+		}
+
+		annots, err := ctx.DereferenceArray(o)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, o := range annots {
+			indRef, ok := o.(types.IndirectRef)
+			if !ok {
+				continue
+			}
+			d, err := ctx.DereferenceDict(indRef)
+			if err != nil {
+				return nil, false, err
+			}
+			if d == nil {
+				continue
+			}
+			a, err := xfdfAnnot(ctx.XRefTable, names, d, pageNr, indRef.ObjectNumber.Value())
+			if err != nil {
+				return nil, false, err
+			}
+			if a != nil {
+				x.Annots.Annots = append(x.Annots.Annots, a)
+			}
+		}
+	}
+
+	return x, len(x.Annots.Annots) > 0, nil
+}
+
+// Import
+
+type xfdfImport struct {
+	*model.XFDFAnnot
+	rect      types.Rectangle
+	modDate   string
+	f         model.AnnotationFlags
+	col, ic   *color.SimpleColor
+	ca        *float64
+	rc        string
+	bw        float64
+	bs        model.BorderStyle
+	cloudy    bool
+	intensity int
+	irt       *types.IndirectRef
+}
+
+func validXFDFDate(s string, relaxed bool) string {
+	if _, ok := types.DateTime(s, relaxed); ok {
+		return s
+	}
+	return ""
+}
+
+func newXFDFImport(a *model.XFDFAnnot, relaxed bool) (*xfdfImport, error) {
+	ai := &xfdfImport{XFDFAnnot: a, f: parseXFDFFlags(a.Flags), modDate: validXFDFDate(a.Date, relaxed)}
+
+	r, err := parseXFDFRect(a.Rect)
+	if err != nil {
+		return nil, err
+	}
+	ai.rect = *r
+
+	if ai.col, err = parseXFDFColor(a.Color); err != nil {
+		return nil, err
+	}
+
+	if ai.ic, err = parseXFDFColor(a.InteriorColor); err != nil {
+		return nil, err
+	}
+
+	if a.Opacity != "" {
+		f, err := strconv.ParseFloat(a.Opacity, 64)
+		if err != nil {
+			return nil, errors.Errorf("pdfcpu: invalid XFDF opacity: %s", a.Opacity)
+		}
+		ai.ca = &f
+	}
+
+	if a.RichText != nil {
+		ai.rc = strings.TrimSpace(a.RichText.InnerXML)
+	}
+
+	if a.Width != "" {
+		if ai.bw, err = strconv.ParseFloat(a.Width, 64); err != nil {
+			return nil, errors.Errorf("pdfcpu: invalid XFDF width: %s", a.Width)
+		}
+	}
+
+	ai.bs = xfdfBorderStyles[a.Style]
+	if a.Style == "cloudy" {
+		ai.cloudy = true
+		ai.intensity = 1
+		if i, err := strconv.Atoi(a.Intensity); err == nil {
+			ai.intensity = i
+		}
+	}
+
+	return ai, nil
+}
+
+// finish sets the markup annotation attributes not covered by constructors.
+func (ai *xfdfImport) finish(ma *model.MarkupAnnotation, relaxed bool) {
+	if s := validXFDFDate(ai.CreationDate, relaxed); s != "" {
+		ma.CreationDate = s
+	}
+	if ai.irt != nil {
+		ma.IRT = ai.irt
+		if ai.ReplyType == "group" {
+			ma.RT = "Group"
+		}
+	}
+	ma.GenerateAP = true
+}
+
+func (ai *xfdfImport) lineEndings() (*model.LineEndingStyle, *model.LineEndingStyle) {
+	if ai.Head == "" && ai.Tail == "" {
+		return nil, nil
+	}
+	none := model.LENone
+	head, tail := lineEndingStyleForName(ai.Head), lineEndingStyleForName(ai.Tail)
+	if head == nil {
+		head = &none
+	}
+	if tail == nil {
+		tail = &none
+	}
+	return head, tail
+}
+
+func (ai *xfdfImport) fringe() ([]float64, error) {
+	ff, err := parseXFDFNumbers(ai.Fringe)
+	if err != nil {
+		return nil, err
+	}
+	if len(ff) != 4 {
+		return []float64{0, 0, 0, 0}, nil
+	}
+	return ff, nil
+}
+
+func (ai *xfdfImport) text(relaxed bool) model.AnnotationRenderer {
+	open := ai.Popup != nil && ai.Popup.Open == "yes"
+	ann := model.NewTextAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject, 0, 0, 0, open, ai.Icon)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+	return ann
+}
+
+func (ai *xfdfImport) freeText(relaxed bool) (model.AnnotationRenderer, error) {
+	fontName, fontSize, fontCol := model.ParseDefaultAppearance(ai.DefaultAppearance)
+
+	hAlign := types.AlignLeft
+	for i, s := range xfdfJustifications {
+		if ai.Justification == s {
+			hAlign = types.HAlignment(i)
+		}
+	}
+
+	var intent *model.FreeTextIntent
+	for _, fti := range []model.FreeTextIntent{model.IntentFreeText, model.IntentFreeTextCallout, model.IntentFreeTextTypeWriter} {
+		if model.FreeTextIntentName(fti) == ai.Intent {
+			intent = &fti
+		}
+	}
+
+	cl, err := parseXFDFNumbers(ai.Callout)
+	if err != nil {
+		return nil, err
+	}
+	var callOutLine types.Array
+	if len(cl) > 0 {
+		callOutLine = types.NewNumberArray(cl...)
+	}
+
+	rd, err := ai.fringe()
+	if err != nil {
+		return nil, err
+	}
+
+	text := ai.Contents
+	if text == "" {
+		text = xmlText(ai.rc)
+	}
+
+	ann := model.NewFreeTextAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+		text, hAlign, fontName, fontSize, fontCol, ai.DefaultStyle, intent, callOutLine, lineEndingStyleForName(ai.Head),
+		rd[0], rd[1], rd[2], rd[3], ai.bw, ai.bs, ai.cloudy, ai.intensity)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+
+	// Appearances for free text annotations are restricted to core fonts.
+	ann.GenerateAP = fontName == "" || font.IsCoreFont(fontName)
+
+	return ann, nil
+}
+
+func (ai *xfdfImport) line(relaxed bool) (model.AnnotationRenderer, error) {
+	p1, err := parseXFDFNumbers(ai.Start)
+	if err != nil {
+		return nil, err
+	}
+	p2, err := parseXFDFNumbers(ai.End)
+	if err != nil {
+		return nil, err
+	}
+	if len(p1) != 2 || len(p2) != 2 {
+		return nil, errors.New("pdfcpu: invalid XFDF line: missing start or end")
+	}
+
+	var ll, lle, llo, cox, coy float64
+	for _, v := range []struct {
+		s string
+		f *float64
+	}{
+		{ai.LeaderLength, &ll}, {ai.LeaderExtend, &lle}, {ai.LeaderOffset, &llo},
+		{ai.CaptionOffsetH, &cox}, {ai.CaptionOffsetV, &coy},
+	} {
+		if v.s == "" {
+			continue
+		}
+		if *v.f, err = strconv.ParseFloat(v.s, 64); err != nil {
+			return nil, errors.Errorf("pdfcpu: invalid XFDF line attribute: %s", v.s)
+		}
+	}
+
+	var intent *model.LineIntent
+	for _, li := range []model.LineIntent{model.IntentLineArrow, model.IntentLineDimension} {
+		if model.LineIntentName(li) == ai.Intent {
+			intent = &li
+		}
+	}
+
+	head, tail := ai.lineEndings()
+
+	ann := model.NewLineAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+		types.NewPoint(p1[0], p1[1]), types.NewPoint(p2[0], p2[1]), head, tail, ll, llo, lle, intent, nil,
+		ai.Caption == "yes", ai.CaptionStyle == "Top", cox, coy, ai.ic, ai.bw, ai.bs)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+
+	return ann, nil
+}
+
+func (ai *xfdfImport) squareOrCircle(circle, relaxed bool) (model.AnnotationRenderer, error) {
+	rd, err := ai.fringe()
+	if err != nil {
+		return nil, err
+	}
+
+	if circle {
+		ann := model.NewCircleAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+			ai.ic, rd[0], rd[1], rd[2], rd[3], ai.bw, ai.bs, ai.cloudy, ai.intensity)
+		ai.finish(&ann.MarkupAnnotation, relaxed)
+		return ann, nil
+	}
+
+	ann := model.NewSquareAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+		ai.ic, rd[0], rd[1], rd[2], rd[3], ai.bw, ai.bs, ai.cloudy, ai.intensity)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+	return ann, nil
+}
+
+func (ai *xfdfImport) poly(polyLine, relaxed bool) (model.AnnotationRenderer, error) {
+	ff, err := parseXFDFNumbers(ai.Vertices)
+	if err != nil {
+		return nil, err
+	}
+	if len(ff) < 4 || len(ff)%2 > 0 {
+		return nil, errors.Errorf("pdfcpu: invalid XFDF vertices: %s", ai.Vertices)
+	}
+	vertices := types.NewNumberArray(ff...)
+
+	if polyLine {
+		var intent *model.PolyLineIntent
+		if ai.Intent == model.PolyLineIntentName(model.IntentPolyLineDimension) {
+			pi := model.IntentPolyLineDimension
+			intent = &pi
+		}
+		head, tail := ai.lineEndings()
+		ann := model.NewPolyLineAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+			vertices, nil, intent, nil, ai.ic, ai.bw, ai.bs, head, tail)
+		ai.finish(&ann.MarkupAnnotation, relaxed)
+		return ann, nil
+	}
+
+	var intent *model.PolygonIntent
+	for _, pi := range []model.PolygonIntent{model.IntentPolygonCloud, model.IntentPolygonDimension} {
+		if model.PolygonIntentName(pi) == ai.Intent {
+			intent = &pi
+		}
+	}
+	ann := model.NewPolygonAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+		vertices, nil, intent, nil, ai.ic, ai.bw, ai.bs, ai.cloudy, ai.intensity)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+	return ann, nil
+}
+
+func (ai *xfdfImport) textMarkup(typ string, relaxed bool) (model.AnnotationRenderer, error) {
+	ff, err := parseXFDFNumbers(ai.Coords)
+	if err != nil {
+		return nil, err
+	}
+	if len(ff) == 0 || len(ff)%8 > 0 {
+		return nil, errors.Errorf("pdfcpu: invalid XFDF coords: %s", ai.Coords)
+	}
+
+	var qp types.QuadPoints
+	for i := 0; i < len(ff); i += 8 {
+		qp.AddQuadLiteral(types.QuadLiteral{
+			P1: types.NewPoint(ff[i], ff[i+1]),
+			P2: types.NewPoint(ff[i+2], ff[i+3]),
+			P3: types.NewPoint(ff[i+4], ff[i+5]),
+			P4: types.NewPoint(ff[i+6], ff[i+7]),
+		})
+	}
+
+	ann := model.NewTextMarkupAnnotation(model.AnnotTypes[typ], ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, 0, 0, ai.bw, ai.Title, nil, ai.ca, ai.rc, ai.Subject, qp)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+
+	switch typ {
+	case "Highlight":
+		return model.HighlightAnnotation{TextMarkupAnnotation: ann}, nil
+	case "Underline":
+		return model.UnderlineAnnotation{TextMarkupAnnotation: ann}, nil
+	case "Squiggly":
+		return model.SquigglyAnnotation{TextMarkupAnnotation: ann}, nil
+	}
+	return model.StrikeOutAnnotation{TextMarkupAnnotation: ann}, nil
+}
+
+func (ai *xfdfImport) caret(relaxed bool) (model.AnnotationRenderer, error) {
+	var rd *types.Rectangle
+	if ai.Fringe != "" {
+		ff, err := ai.fringe()
+		if err != nil {
+			return nil, err
+		}
+		rd = types.NewRectangle(ff[0], ff[1], ff[2], ff[3])
+	}
+
+	ann := model.NewCaretAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, 0, 0, 0, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+		rd, ai.Symbol == "paragraph")
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+	return ann, nil
+}
+
+func (ai *xfdfImport) ink(relaxed bool) (model.AnnotationRenderer, error) {
+	var paths []model.InkPath
+	if ai.InkList != nil {
+		for _, g := range ai.InkList.Gestures {
+			ff, err := parseXFDFNumbers(g)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, ff)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("pdfcpu: invalid XFDF ink: missing inklist")
+	}
+
+	ann := model.NewInkAnnotation(ai.rect, 0, ai.Contents, ai.Name, ai.modDate, ai.f, ai.col, ai.Title, nil, ai.ca, ai.rc, ai.Subject,
+		paths, ai.bw, ai.bs)
+	ai.finish(&ann.MarkupAnnotation, relaxed)
+	return ann, nil
+}
+
+func (ai *xfdfImport) renderer(relaxed bool) (model.AnnotationRenderer, error) {
+	switch typ := ai.Type(); typ {
+
+	case "text":
+		return ai.text(relaxed), nil
+
+	case "freetext":
+		return ai.freeText(relaxed)
+
+	case "line":
+		return ai.line(relaxed)
+
+	case "square", "circle":
+		return ai.squareOrCircle(typ == "circle", relaxed)
+
+	case "polygon", "polyline":
+		return ai.poly(typ == "polyline", relaxed)
+
+	case "highlight", "underline", "squiggly", "strikeout":
+		for k, v := range xfdfAnnotElements {
+			if v == typ {
+				return ai.textMarkup(k, relaxed)
+			}
+		}
+
+	case "caret":
+		return ai.caret(relaxed)
+
+	case "ink":
+		return ai.ink(relaxed)
+	}
+
+	return nil, nil
+}
+
+// pageAnnotation returns true if page pageNr has an annotation named nm
+// along with its indirect reference if there is one.
+func pageAnnotation(ctx *model.Context, pageNr int, nm string) (bool, *types.IndirectRef, error) {
+	d, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return false, nil, err
+	}
+
+	o, found := d.Find("Annots")
+	if !found {
+		return false, nil, nil
+	}
+
+	annots, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return false, nil, err
+	}
+
+	i, err := findAnnotByID(ctx, nm, annots)
+	if err != nil || i < 0 {
+		return false, nil, err
+	}
+
+	if indRef, ok := annots[i].(types.IndirectRef); ok {
+		return true, &indRef, nil
+	}
+
+	return true, nil, nil
+}
+
+func addXFDFPopup(ctx *model.Context, p *model.XFDFPopup, pageNr int, parent types.IndirectRef, d types.Dict, incr bool) error {
+	r, err := parseXFDFRect(p.Rect)
+	if err != nil {
+		return err
+	}
+
+	popup := model.NewPopupAnnotation(*r, 0, "", "", "", parseXFDFFlags(p.Flags), nil, 0, 0, 0, &parent, p.Open == "yes")
+
+	indRef, _, err := AddAnnotationToPage(ctx, pageNr, popup, incr)
+	if err != nil {
+		return err
+	}
+
+	d["Popup"] = *indRef
+
+	return nil
+}
+
+// ImportAnnotationsXFDF adds the markup annotations of x including replies and popups to ctx.
+// Annotations whose name is already taken on their page are skipped.
+func ImportAnnotationsXFDF(ctx *model.Context, x *model.XFDF, incr bool) (bool, error) {
+	if x.Annots == nil {
+		return false, nil
+	}
+
+	relaxed := ctx.XRefTable.ValidationMode == model.ValidationRelaxed
+
+	// Annotation names and their indirect references for resolving replies.
+	names := map[string]types.IndirectRef{}
+
+	type reply struct {
+		d       types.Dict
+		irt, rt string
+	}
+	var replies []reply
+
+	var ok bool
+
+	for _, a := range x.Annots.Annots {
+		pageNr := a.Page + 1
+		if pageNr < 1 || pageNr > ctx.PageCount {
+			return false, errors.Errorf("pdfcpu: XFDF annotation \"%s\": invalid page: %d", a.Name, a.Page)
+		}
+
+		if a.Name != "" {
+			found, indRef, err := pageAnnotation(ctx, pageNr, a.Name)
+			if err != nil {
+				return false, err
+			}
+			if found {
+				// Replies may still refer to the existing annotation.
+				if indRef != nil {
+					names[a.Name] = *indRef
+				}
+				model.ShowSkipped(fmt.Sprintf("XFDF annotation \"%s\" already present", a.Name))
+				continue
+			}
+		}
+
+		ai, err := newXFDFImport(a, relaxed)
+		if err != nil {
+			return false, err
+		}
+
+		if a.InReplyTo != "" {
+			if indRef, found := names[a.InReplyTo]; found {
+				ai.irt = &indRef
+			}
+		}
+
+		ar, err := ai.renderer(relaxed)
+		if err != nil {
+			return false, err
+		}
+		if ar == nil {
+			model.ShowSkipped(fmt.Sprintf("unsupported XFDF annotation: %s", a.Type()))
+			continue
+		}
+
+		indRef, d, err := AddAnnotationToPage(ctx, pageNr, ar, incr)
+		if err != nil {
+			return false, err
+		}
+		ok = true
+
+		if ai.modDate != "" {
+			d["M"] = types.StringLiteral(ai.modDate)
+		}
+
+		if a.Name != "" {
+			names[a.Name] = *indRef
+		}
+
+		if a.InReplyTo != "" && ai.irt == nil {
+			replies = append(replies, reply{d: d, irt: a.InReplyTo, rt: a.ReplyType})
+		}
+
+		if a.Popup != nil {
+			if err := addXFDFPopup(ctx, a.Popup, pageNr, *indRef, d, incr); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// Resolve replies to annotations following later on.
+	for _, r := range replies {
+		indRef, found := names[r.irt]
+		if !found {
+			continue
+		}
+		r.d["IRT"] = indRef
+		if r.rt == "group" {
+			r.d["RT"] = types.Name("Group")
+		}
+	}
+
+	return ok, nil
+}